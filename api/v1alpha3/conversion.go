@@ -26,13 +26,39 @@ import (
 func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha4.Cluster)
 
-	return Convert_v1alpha3_Cluster_To_v1alpha4_Cluster(src, dst, nil)
+	if err := Convert_v1alpha3_Cluster_To_v1alpha4_Cluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1alpha4.Cluster{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	for id, failureDomain := range dst.Status.FailureDomains {
+		if restoredFailureDomain, ok := restored.Status.FailureDomains[id]; ok {
+			failureDomain.Weight = restoredFailureDomain.Weight
+			dst.Status.FailureDomains[id] = failureDomain
+		}
+	}
+
+	return nil
 }
 
 func (dst *Cluster) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1alpha4.Cluster)
 
-	return Convert_v1alpha4_Cluster_To_v1alpha3_Cluster(src, dst, nil)
+	if err := Convert_v1alpha4_Cluster_To_v1alpha3_Cluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *ClusterList) ConvertTo(dstRaw conversion.Hub) error {
@@ -50,13 +76,40 @@ func (dst *ClusterList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha4.Machine)
 
-	return Convert_v1alpha3_Machine_To_v1alpha4_Machine(src, dst, nil)
+	if err := Convert_v1alpha3_Machine_To_v1alpha4_Machine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1alpha4.Machine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.NodeVolumeDetachTimeout = restored.Spec.NodeVolumeDetachTimeout
+	dst.Spec.InfrastructureReadyTimeout = restored.Spec.InfrastructureReadyTimeout
+	dst.Spec.InfrastructureReadyTimeoutPolicy = restored.Spec.InfrastructureReadyTimeoutPolicy
+	dst.Spec.ReadinessGates = restored.Spec.ReadinessGates
+	dst.Status.NodeDrainStartTime = restored.Status.NodeDrainStartTime
+	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
+	dst.Status.PhaseTransitionTimes = restored.Status.PhaseTransitionTimes
+
+	return nil
 }
 
 func (dst *Machine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1alpha4.Machine)
 
-	return Convert_v1alpha4_Machine_To_v1alpha3_Machine(src, dst, nil)
+	if err := Convert_v1alpha4_Machine_To_v1alpha3_Machine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *MachineList) ConvertTo(dstRaw conversion.Hub) error {
@@ -74,13 +127,37 @@ func (dst *MachineList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *MachineSet) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha4.MachineSet)
 
-	return Convert_v1alpha3_MachineSet_To_v1alpha4_MachineSet(src, dst, nil)
+	if err := Convert_v1alpha3_MachineSet_To_v1alpha4_MachineSet(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1alpha4.MachineSet{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.InfrastructureReadyTimeout = restored.Spec.Template.Spec.InfrastructureReadyTimeout
+	dst.Spec.Template.Spec.InfrastructureReadyTimeoutPolicy = restored.Spec.Template.Spec.InfrastructureReadyTimeoutPolicy
+	dst.Spec.Template.Spec.ReadinessGates = restored.Spec.Template.Spec.ReadinessGates
+
+	return nil
 }
 
 func (dst *MachineSet) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1alpha4.MachineSet)
 
-	return Convert_v1alpha4_MachineSet_To_v1alpha3_MachineSet(src, dst, nil)
+	if err := Convert_v1alpha4_MachineSet_To_v1alpha3_MachineSet(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *MachineSetList) ConvertTo(dstRaw conversion.Hub) error {
@@ -118,6 +195,12 @@ func (src *MachineDeployment) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.Strategy.RollingUpdate.DeletePolicy = restored.Spec.Strategy.RollingUpdate.DeletePolicy
 
 	}
+	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.InfrastructureReadyTimeout = restored.Spec.Template.Spec.InfrastructureReadyTimeout
+	dst.Spec.Template.Spec.InfrastructureReadyTimeoutPolicy = restored.Spec.Template.Spec.InfrastructureReadyTimeoutPolicy
+	dst.Spec.Template.Spec.ReadinessGates = restored.Spec.Template.Spec.ReadinessGates
+	dst.Status.LastProgressTime = restored.Status.LastProgressTime
+	dst.Status.Conditions = restored.Status.Conditions
 
 	return nil
 }
@@ -165,6 +248,10 @@ func (src *MachineHealthCheck) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.UnhealthyRange != nil {
 		dst.Spec.UnhealthyRange = restored.Spec.UnhealthyRange
 	}
+	dst.Spec.RemediationBackoff = restored.Spec.RemediationBackoff
+	dst.Spec.RemediationMaxRetries = restored.Spec.RemediationMaxRetries
+	dst.Spec.Selectors = restored.Spec.Selectors
+	dst.Status.RemediationAttempts = restored.Status.RemediationAttempts
 
 	return nil
 }
@@ -208,3 +295,32 @@ func Convert_v1alpha4_MachineRollingUpdateDeployment_To_v1alpha3_MachineRollingU
 func Convert_v1alpha4_MachineHealthCheckSpec_To_v1alpha3_MachineHealthCheckSpec(in *v1alpha4.MachineHealthCheckSpec, out *MachineHealthCheckSpec, s apiconversion.Scope) error {
 	return autoConvert_v1alpha4_MachineHealthCheckSpec_To_v1alpha3_MachineHealthCheckSpec(in, out, s)
 }
+
+func Convert_v1alpha4_MachineHealthCheckStatus_To_v1alpha3_MachineHealthCheckStatus(in *v1alpha4.MachineHealthCheckStatus, out *MachineHealthCheckStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_MachineHealthCheckStatus_To_v1alpha3_MachineHealthCheckStatus(in, out, s)
+}
+
+// Convert_v1alpha4_MachineDeploymentStatus_To_v1alpha3_MachineDeploymentStatus converts from the Hub version (v1alpha4) of the MachineDeploymentStatus to this version.
+// MachineDeploymentStatus.LastProgressTime and MachineDeploymentStatus.Conditions do not exist in v1alpha3.
+func Convert_v1alpha4_MachineDeploymentStatus_To_v1alpha3_MachineDeploymentStatus(in *v1alpha4.MachineDeploymentStatus, out *MachineDeploymentStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_MachineDeploymentStatus_To_v1alpha3_MachineDeploymentStatus(in, out, s)
+}
+
+// Convert_v1alpha4_MachineStatus_To_v1alpha3_MachineStatus converts from the Hub version (v1alpha4) of the MachineStatus to this version.
+// MachineStatus.NodeDrainStartTime, MachineStatus.CertificatesExpiryDate and MachineStatus.PhaseTransitionTimes do not exist in v1alpha3.
+func Convert_v1alpha4_MachineStatus_To_v1alpha3_MachineStatus(in *v1alpha4.MachineStatus, out *MachineStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_MachineStatus_To_v1alpha3_MachineStatus(in, out, s)
+}
+
+// Convert_v1alpha4_MachineSpec_To_v1alpha3_MachineSpec converts from the Hub version (v1alpha4) of the MachineSpec to this version.
+// MachineSpec.NodeVolumeDetachTimeout, MachineSpec.InfrastructureReadyTimeout, MachineSpec.InfrastructureReadyTimeoutPolicy
+// and MachineSpec.ReadinessGates do not exist in v1alpha3.
+func Convert_v1alpha4_MachineSpec_To_v1alpha3_MachineSpec(in *v1alpha4.MachineSpec, out *MachineSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_MachineSpec_To_v1alpha3_MachineSpec(in, out, s)
+}
+
+// Convert_v1alpha4_FailureDomainSpec_To_v1alpha3_FailureDomainSpec converts from the Hub version (v1alpha4) of the FailureDomainSpec to this version.
+// FailureDomainSpec.Weight does not exist in v1alpha3.
+func Convert_v1alpha4_FailureDomainSpec_To_v1alpha3_FailureDomainSpec(in *v1alpha4.FailureDomainSpec, out *FailureDomainSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_FailureDomainSpec_To_v1alpha3_FailureDomainSpec(in, out, s)
+}
@@ -266,4 +266,11 @@ type FailureDomainSpec struct {
 	// Attributes is a free form map of attributes an infrastructure provider might use or require.
 	// +optional
 	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Weight is the relative share of the machines spread across the failure domains that should be placed in
+	// this failure domain, e.g. to place more machines in a larger availability zone. Failure domains with the
+	// same weight are spread evenly. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
 }
@@ -74,11 +74,27 @@ const (
 	// MachineSkipRemediationAnnotation is the annotation used to mark the machines that should not be considered for remediation by MachineHealthCheck reconciler.
 	MachineSkipRemediationAnnotation = "cluster.x-k8s.io/skip-remediation"
 
+	// DisableMachineCreate is an annotation that can be used to signal a MachineSet to stop creating new machines.
+	// It is utilized in the OnDelete MachineDeploymentStrategy to allow the MachineDeployment controller to scale down
+	// older MachineSets when Machines are deleted and add the new replicas to the latest MachineSet.
+	DisableMachineCreate = "machineset.cluster.x-k8s.io/disable-machine-create"
+
 	// ClusterSecretType defines the type of secret created by core components
 	ClusterSecretType corev1.SecretType = "cluster.x-k8s.io/secret" //nolint:gosec
 
 	// InterruptibleLabel is the label used to mark the nodes that run on interruptible instances
 	InterruptibleLabel = "cluster.x-k8s.io/interruptible"
+
+	// NodeLabelPrefix is the default prefix of the Machine labels which are synced to the Machine's Node.
+	NodeLabelPrefix = "node.cluster.x-k8s.io/"
+
+	// LabelsFromMachineAnnotation is the annotation set on nodes to track the labels synced from the Machine,
+	// so labels are removed from the Node when they are removed from the Machine.
+	LabelsFromMachineAnnotation = "cluster.x-k8s.io/labels-from-machine"
+
+	// StartupTaintAnnotation is the Machine annotation defining a taint, in the key[=value]:effect format, which is applied
+	// to the Machine's Node when it first joins the cluster and removed once the Machine is running and the Node is ready.
+	StartupTaintAnnotation = "cluster.x-k8s.io/startup-taint"
 )
 
 // MachineAddressType describes a valid MachineAddress type.
@@ -72,6 +72,19 @@ const (
 	// NOTE: Having the control plane machine available is a pre-condition for joining additional control planes
 	// or workers nodes.
 	WaitingForControlPlaneAvailableReason = "WaitingForControlPlaneAvailable"
+
+	// InfrastructureFailedReason (Severity=Error) documents a cluster whose infrastructure object reports
+	// a failure in its status.failureReason or status.failureMessage fields.
+	InfrastructureFailedReason = "InfrastructureFailed"
+
+	// InfrastructureProvisionFailedReason (Severity=Error) documents a machine whose infrastructure machine did not
+	// report being ready within the machine's InfrastructureReadyTimeout; it is also used by the MachineHealthCheck
+	// to report such a machine as unhealthy if its InfrastructureReadyTimeoutPolicy is Remediate.
+	InfrastructureProvisionFailedReason = "InfrastructureProvisionFailed"
+
+	// ControlPlaneFailedReason (Severity=Error) documents a cluster whose control plane object reports
+	// a failure in its status.failureReason or status.failureMessage fields.
+	ControlPlaneFailedReason = "ControlPlaneFailed"
 )
 
 // Conditions and condition Reasons for the Machine object
@@ -88,6 +101,10 @@ const (
 	// NOTE: This reason is used only as a fallback when the bootstrap object is not reporting its own ready condition.
 	WaitingForDataSecretFallbackReason = "WaitingForDataSecret"
 
+	// ReadinessGatesNotMetReason (Severity=Info) documents a machine not Ready because some of the conditions
+	// listed in its readiness gates are not yet reported by the external controllers setting them.
+	ReadinessGatesNotMetReason = "ReadinessGatesNotMet"
+
 	// DrainingSucceededCondition provide evidence of the status of the node drain operation which happens during the machine
 	// deletion process.
 	DrainingSucceededCondition ConditionType = "DrainingSucceeded"
@@ -95,9 +112,23 @@ const (
 	// DrainingReason (Severity=Info) documents a machine node being drained.
 	DrainingReason = "Draining"
 
-	// DrainingFailedReason (Severity=Warning) documents a machine node drain operation failed.
+	// DrainingFailedReason (Severity=Warning) documents a machine node drain operation failed,
+	// or did not complete within the machine's NodeDrainTimeout.
 	DrainingFailedReason = "DrainingFailed"
 
+	// VolumeDetachSucceededCondition reports a machine waiting for the volumes attached to its node to be detached
+	// before the infrastructure machine is deleted; while waiting, the condition is false with the
+	// WaitingForVolumeDetachReason.
+	VolumeDetachSucceededCondition ConditionType = "VolumeDetachSucceeded"
+
+	// WaitingForVolumeDetachReason (Severity=Info) documents a machine waiting for the volumes attached to its node
+	// to be detached.
+	WaitingForVolumeDetachReason = "WaitingForVolumeDetach"
+
+	// VolumeDetachTimeoutReason (Severity=Warning) documents the volumes attached to a machine node not being detached
+	// within the machine's NodeVolumeDetachTimeout, the infrastructure machine is deleted regardless.
+	VolumeDetachTimeoutReason = "VolumeDetachTimeout"
+
 	// PreDrainDeleteHookSucceededCondition reports a machine waiting for a PreDrainDeleteHook before being delete.
 	PreDrainDeleteHookSucceededCondition ConditionType = "PreDrainDeleteHookSucceeded"
 
@@ -134,6 +165,14 @@ const (
 	// RemediationFailedReason is the reason used when a remediation owner fails to remediate an unhealthy machine.
 	RemediationFailedReason = "RemediationFailed"
 
+	// ExternalRemediationFailedReason is the reason used when the External Remediation Request created for an unhealthy
+	// machine reports a failure, and the machine is handed over to its owner for remediation.
+	ExternalRemediationFailedReason = "ExternalRemediationFailed"
+
+	// ExternalRemediationRetriesExhaustedReason is the reason used when the External Remediation Request created for an
+	// unhealthy machine is retried more than allowed without completing, and the machine is handed over to its owner for remediation.
+	ExternalRemediationRetriesExhaustedReason = "ExternalRemediationRetriesExhausted"
+
 	// RemediationInProgressReason is the reason used when an unhealthy machine is being remediated by the remediation owner.
 	RemediationInProgressReason = "RemediationInProgress"
 
@@ -171,6 +210,23 @@ const (
 
 	// NodeConditionsFailedReason (Severity=Warning) documents a node is not in a healthy state due to the failed state of at least 1 Kubelet condition.
 	NodeConditionsFailedReason = "NodeConditionsFailed"
+
+	// NodeUninitializedReason (Severity=Info) documents a machine's node has been provisioned but is not yet initialized
+	// by the out-of-tree cloud provider, i.e. the node still has the node.cloudprovider.kubernetes.io/uninitialized taint.
+	NodeUninitializedReason = "NodeUninitialized"
+)
+
+// Conditions and condition Reasons for the MachineDeployment object
+
+const (
+	// MachineDeploymentProgressingCondition documents a MachineDeployment whose rollout is either completed or
+	// making progress within the ProgressDeadlineSeconds.
+	MachineDeploymentProgressingCondition ConditionType = "Progressing"
+
+	// ProgressDeadlineExceededReason (Severity=Warning) documents a MachineDeployment whose rollout did not increase
+	// the number of updated or available replicas within the ProgressDeadlineSeconds; this usually means that the
+	// new machines keep failing to be provisioned.
+	ProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
 )
 
 // Conditions and condition Reasons for the MachineHealthCheck object
@@ -183,4 +239,13 @@ const (
 	// TooManyUnhealthy is the reason used when too many Machines are unhealthy and the MachineHealthCheck is blocked
 	// from making any further remediations.
 	TooManyUnhealthyReason = "TooManyUnhealthy"
+
+	// RemediationBackoffCondition is set on MachineHealthChecks using a remediation backoff, and it documents
+	// that no remediation is being delayed by the backoff.
+	RemediationBackoffCondition ConditionType = "RemediationBackoff"
+
+	// RemediationDelayedReason (Severity=Warning) is the reason used when the remediation of at least one unhealthy
+	// Machine is delayed because machines with the same owner were recently remediated; this usually
+	// means that the replacement machines keep failing.
+	RemediationDelayedReason = "RemediationDelayed"
 )
@@ -32,6 +32,9 @@ const (
 	// ExcludeNodeDrainingAnnotation annotation explicitly skips node draining if set
 	ExcludeNodeDrainingAnnotation = "machine.cluster.x-k8s.io/exclude-node-draining"
 
+	// ExcludeWaitForNodeVolumeDetachAnnotation annotation explicitly skips waiting for the node volumes to be detached if set
+	ExcludeWaitForNodeVolumeDetachAnnotation = "machine.cluster.x-k8s.io/exclude-wait-for-node-volume-detach"
+
 	// MachineSetLabelName is the label set on machines if they're controlled by MachineSet
 	MachineSetLabelName = "cluster.x-k8s.io/set-name"
 
@@ -95,8 +98,56 @@ type MachineSpec struct {
 	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
 	// +optional
 	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// NodeVolumeDetachTimeout is the total amount of time that the controller will spend on waiting for all volumes
+	// to be detached from the node before deleting the infrastructure machine.
+	// The default value is 0, meaning that the volumes can be detached without any time limitations.
+	// +optional
+	NodeVolumeDetachTimeout *metav1.Duration `json:"nodeVolumeDetachTimeout,omitempty"`
+
+	// InfrastructureReadyTimeout is the total amount of time the infrastructure machine has to report being ready,
+	// measured from its creation. Once exceeded, the InfrastructureReady condition of the machine reports the
+	// provisioning as failed, and the machine is handled according to the InfrastructureReadyTimeoutPolicy.
+	// The default value is 0, meaning that the infrastructure machine can be provisioned without any time limitations.
+	// +optional
+	InfrastructureReadyTimeout *metav1.Duration `json:"infrastructureReadyTimeout,omitempty"`
+
+	// InfrastructureReadyTimeoutPolicy defines how the machine is handled once the InfrastructureReadyTimeout is exceeded.
+	// Defaults to Report.
+	// +kubebuilder:validation:Enum=Report;Remediate
+	// +optional
+	InfrastructureReadyTimeoutPolicy InfrastructureReadyTimeoutPolicy `json:"infrastructureReadyTimeoutPolicy,omitempty"`
+
+	// ReadinessGates specifies additional conditions, set on the machine by external controllers,
+	// which must be True for the machine to be considered Ready, e.g. a condition reporting
+	// that a security agent running on the machine is registered. The conditions set by the Cluster API
+	// controllers, e.g. Ready or NodeHealthy, cannot be used as readiness gates.
+	// +optional
+	// +listType=map
+	// +listMapKey=conditionType
+	ReadinessGates []MachineReadinessGate `json:"readinessGates,omitempty"`
+}
+
+// MachineReadinessGate is a condition the machine must satisfy to be considered Ready.
+type MachineReadinessGate struct {
+	// ConditionType is the type of a condition in the machine's condition list, which must be True
+	// for the machine to be considered Ready.
+	// +kubebuilder:validation:MinLength=1
+	ConditionType ConditionType `json:"conditionType"`
 }
 
+// InfrastructureReadyTimeoutPolicy defines how a machine is handled once its InfrastructureReadyTimeout is exceeded.
+type InfrastructureReadyTimeoutPolicy string
+
+const (
+	// InfrastructureReadyTimeoutReportPolicy only reports the failure in the InfrastructureReady condition of the machine.
+	InfrastructureReadyTimeoutReportPolicy InfrastructureReadyTimeoutPolicy = "Report"
+
+	// InfrastructureReadyTimeoutRemediatePolicy also has the MachineHealthChecks targeting the machine consider it
+	// unhealthy, so it is remediated by its owner.
+	InfrastructureReadyTimeoutRemediatePolicy InfrastructureReadyTimeoutPolicy = "Remediate"
+)
+
 // ANCHOR_END: MachineSpec
 
 // ANCHOR: MachineStatus
@@ -111,6 +162,10 @@ type MachineStatus struct {
 	// +optional
 	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
 
+	// PhaseTransitionTimes records when the Machine first entered the Provisioning, Provisioned and Running phases.
+	// +optional
+	PhaseTransitionTimes *MachinePhaseTransitionTimes `json:"phaseTransitionTimes,omitempty"`
+
 	// Version specifies the current version of Kubernetes running
 	// on the corresponding Node. This is meant to be a means of bubbling
 	// up status from the Node to the Machine.
@@ -178,6 +233,16 @@ type MachineStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// NodeDrainStartTime is the time when the controller started draining the node of a machine being deleted.
+	// It is used to measure the NodeDrainTimeout.
+	// +optional
+	NodeDrainStartTime *metav1.Time `json:"nodeDrainStartTime,omitempty"`
+
+	// CertificatesExpiryDate is the expiry date of the machine certificates.
+	// This value is only set for control plane machines.
+	// +optional
+	CertificatesExpiryDate *metav1.Time `json:"certificatesExpiryDate,omitempty"`
+
 	// Conditions defines current service state of the Machine.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
@@ -185,6 +250,22 @@ type MachineStatus struct {
 
 // ANCHOR_END: MachineStatus
 
+// MachinePhaseTransitionTimes records when a Machine first entered the phases of its provisioning.
+// A phase which the Machine skipped, e.g. because it was provisioned within a single reconciliation, is not recorded.
+type MachinePhaseTransitionTimes struct {
+	// Provisioning is when the Machine entered the Provisioning phase.
+	// +optional
+	Provisioning *metav1.Time `json:"provisioning,omitempty"`
+
+	// Provisioned is when the Machine entered the Provisioned phase.
+	// +optional
+	Provisioned *metav1.Time `json:"provisioned,omitempty"`
+
+	// Running is when the Machine entered the Running phase.
+	// +optional
+	Running *metav1.Time `json:"running,omitempty"`
+}
+
 // SetTypedPhase sets the Phase field to the string representation of MachinePhase.
 func (m *MachineStatus) SetTypedPhase(p MachinePhase) {
 	m.Phase = string(p)
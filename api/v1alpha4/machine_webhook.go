@@ -127,8 +127,40 @@ func (m *Machine) validate(old *Machine) error {
 		}
 	}
 
+	allErrs = append(allErrs, validateReadinessGates(m.Spec.ReadinessGates, field.NewPath("spec", "readinessGates"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("Machine").GroupKind(), m.Name, allErrs)
 }
+
+// machineControllerConditionTypes are the types of the conditions set on Machines by the Cluster API controllers,
+// which cannot be used as readiness gates.
+var machineControllerConditionTypes = []ConditionType{
+	ReadyCondition,
+	InfrastructureReadyCondition,
+	BootstrapReadyCondition,
+	DrainingSucceededCondition,
+	VolumeDetachSucceededCondition,
+	PreDrainDeleteHookSucceededCondition,
+	PreTerminateDeleteHookSucceededCondition,
+	MachineHealthCheckSuccededCondition,
+	MachineOwnerRemediatedCondition,
+	MachineNodeHealthyCondition,
+}
+
+// validateReadinessGates checks that the readiness gates do not use the conditions set by the Cluster API controllers,
+// which are already part of the Ready condition of the machine or depend on it.
+func validateReadinessGates(gates []MachineReadinessGate, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, gate := range gates {
+		for _, conditionType := range machineControllerConditionTypes {
+			if gate.ConditionType == conditionType {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("conditionType"), gate.ConditionType, "must not be a condition set by the Cluster API controllers"))
+				break
+			}
+		}
+	}
+	return allErrs
+}
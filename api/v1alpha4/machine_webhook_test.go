@@ -246,3 +246,66 @@ func TestMachineVersionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineReadinessGatesValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		gates     []MachineReadinessGate
+		expectErr bool
+	}{
+		{
+			name:      "should succeed without readiness gates",
+			expectErr: false,
+		},
+		{
+			name:      "should succeed with a condition set by an external controller",
+			gates:     []MachineReadinessGate{{ConditionType: "SecurityAgentRegistered"}},
+			expectErr: false,
+		},
+		{
+			name:      "should return error when given the Ready condition",
+			gates:     []MachineReadinessGate{{ConditionType: ReadyCondition}},
+			expectErr: true,
+		},
+		{
+			name:      "should return error when given a condition set by the Cluster API controllers",
+			gates:     []MachineReadinessGate{{ConditionType: "SecurityAgentRegistered"}, {ConditionType: MachineNodeHealthyCondition}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{
+				Spec: MachineSpec{
+					Bootstrap:      Bootstrap{ConfigRef: nil, DataSecretName: pointer.StringPtr("test")},
+					ReadinessGates: tt.gates,
+				},
+			}
+			ms := &MachineSet{
+				Spec: MachineSetSpec{
+					Template: MachineTemplateSpec{Spec: m.Spec},
+				},
+			}
+			md := &MachineDeployment{
+				Spec: MachineDeploymentSpec{
+					Template: MachineTemplateSpec{Spec: m.Spec},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+				g.Expect(ms.ValidateCreate()).NotTo(Succeed())
+				g.Expect(md.ValidateCreate()).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+				g.Expect(ms.ValidateCreate()).To(Succeed())
+				g.Expect(md.ValidateCreate()).To(Succeed())
+			}
+		})
+	}
+}
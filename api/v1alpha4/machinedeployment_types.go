@@ -28,10 +28,17 @@ const (
 	// i.e. gradually scale down the old MachineSet and scale up the new one.
 	RollingUpdateMachineDeploymentStrategyType MachineDeploymentStrategyType = "RollingUpdate"
 
+	// Replace the old MachineSet only when the user deletes its machines
+	// i.e. scale up the new MachineSet as machines of the old MachineSet are deleted.
+	OnDeleteMachineDeploymentStrategyType MachineDeploymentStrategyType = "OnDelete"
+
 	// RevisionAnnotation is the revision annotation of a machine deployment's machine sets which records its rollout sequence
 	RevisionAnnotation = "machinedeployment.clusters.x-k8s.io/revision"
 	// RevisionHistoryAnnotation maintains the history of all old revisions that a machine set has served for a machine deployment.
 	RevisionHistoryAnnotation = "machinedeployment.clusters.x-k8s.io/revision-history"
+	// RollbackToRevisionAnnotation can be set on a machine deployment to roll it back to the machine template of the machine set
+	// with the given revision; the annotation is removed by the controller once the rollback has been processed.
+	RollbackToRevisionAnnotation = "cluster.x-k8s.io/rollback-to-revision"
 	// DesiredReplicasAnnotation is the desired replicas for a machine deployment recorded as an annotation
 	// in its machine sets. Helps in separating scaling events from the rollout process and for
 	// determining if the new machine set for a deployment is really saturated.
@@ -62,6 +69,8 @@ type MachineDeploymentSpec struct {
 	Selector metav1.LabelSelector `json:"selector"`
 
 	// Template describes the machines that will be created.
+	// Changes to the labels and annotations of the template are propagated in place to the existing
+	// MachineSet and Machines; any other change to the template triggers a rollout.
 	Template MachineTemplateSpec `json:"template"`
 
 	// The deployment strategy to use to replace existing machines with
@@ -77,6 +86,8 @@ type MachineDeploymentSpec struct {
 	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
 
 	// The number of old MachineSets to retain to allow rollback.
+	// Once a rollout completes, the oldest MachineSets scaled down to zero replicas beyond this
+	// limit are deleted; the MachineSet currently in use is never deleted.
 	// This is a pointer to distinguish between explicit zero and not specified.
 	// Defaults to 1.
 	// +optional
@@ -91,6 +102,7 @@ type MachineDeploymentSpec struct {
 	// process failed deployments and a condition with a ProgressDeadlineExceeded
 	// reason will be surfaced in the deployment status. Note that progress will
 	// not be estimated during the time a deployment is paused. Defaults to 600s.
+	// +optional
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
 }
 
@@ -101,14 +113,15 @@ type MachineDeploymentSpec struct {
 // MachineDeploymentStrategy describes how to replace existing machines
 // with new ones.
 type MachineDeploymentStrategy struct {
-	// Type of deployment. Currently the only supported strategy is
-	// "RollingUpdate".
+	// Type of deployment. Allowed values are "RollingUpdate" and "OnDelete".
 	// Default is RollingUpdate.
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
 	// +optional
 	Type MachineDeploymentStrategyType `json:"type,omitempty"`
 
 	// Rolling update config params. Present only if
 	// MachineDeploymentStrategyType = RollingUpdate.
+	// With the OnDelete strategy only DeletePolicy may be set.
 	// +optional
 	RollingUpdate *MachineRollingUpdateDeployment `json:"rollingUpdate,omitempty"`
 }
@@ -204,6 +217,16 @@ type MachineDeploymentStatus struct {
 	// Phase represents the current phase of a MachineDeployment (ScalingUp, ScalingDown, Running, Failed, or Unknown).
 	// +optional
 	Phase string `json:"phase,omitempty"`
+
+	// LastProgressTime is the last time the rollout of the deployment made progress, i.e. the number
+	// of updated or available replicas increased. It is not set when no rollout is in progress
+	// or the deployment is paused.
+	// +optional
+	LastProgressTime *metav1.Time `json:"lastProgressTime,omitempty"`
+
+	// Conditions defines current service state of the MachineDeployment.
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // ANCHOR_END: MachineDeploymentStatus
@@ -268,6 +291,14 @@ type MachineDeployment struct {
 	Status MachineDeploymentStatus `json:"status,omitempty"`
 }
 
+func (m *MachineDeployment) GetConditions() Conditions {
+	return m.Status.Conditions
+}
+
+func (m *MachineDeployment) SetConditions(conditions Conditions) {
+	m.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // MachineDeploymentList contains a list of MachineDeployment
@@ -18,6 +18,7 @@ package v1alpha4
 
 import (
 	"fmt"
+	"strconv"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -85,6 +86,30 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 		)
 	}
 
+	if m.Spec.Strategy != nil && m.Spec.Strategy.Type == OnDeleteMachineDeploymentStrategyType && m.Spec.Strategy.RollingUpdate != nil {
+		if m.Spec.Strategy.RollingUpdate.MaxSurge != nil {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(field.NewPath("spec", "strategy", "rollingUpdate", "maxSurge"), "must not be set when strategy type is OnDelete"),
+			)
+		}
+		if m.Spec.Strategy.RollingUpdate.MaxUnavailable != nil {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(field.NewPath("spec", "strategy", "rollingUpdate", "maxUnavailable"), "must not be set when strategy type is OnDelete"),
+			)
+		}
+	}
+
+	if value, ok := m.Annotations[RollbackToRevisionAnnotation]; ok {
+		if revision, err := strconv.ParseInt(value, 10, 64); err != nil || revision <= 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(field.NewPath("metadata", "annotations", RollbackToRevisionAnnotation), value, "must be a positive integer"),
+			)
+		}
+	}
+
 	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
@@ -92,6 +117,8 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 		)
 	}
 
+	allErrs = append(allErrs, validateReadinessGates(m.Spec.Template.Spec.ReadinessGates, field.NewPath("spec", "template", "spec", "readinessGates"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
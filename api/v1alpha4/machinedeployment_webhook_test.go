@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 )
 
@@ -113,6 +114,116 @@ func TestMachineDeploymentValidation(t *testing.T) {
 	}
 }
 
+func TestMachineDeploymentOnDeleteStrategyValidation(t *testing.T) {
+	intOrStr := func(i int) *intstr.IntOrString {
+		x := intstr.FromInt(i)
+		return &x
+	}
+
+	tests := []struct {
+		name          string
+		rollingUpdate *MachineRollingUpdateDeployment
+		expectErr     bool
+	}{
+		{
+			name:          "should not return error without rolling update params",
+			rollingUpdate: nil,
+			expectErr:     false,
+		},
+		{
+			name: "should not return error with a delete policy",
+			rollingUpdate: &MachineRollingUpdateDeployment{
+				DeletePolicy: pointer.StringPtr("Oldest"),
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error with maxSurge",
+			rollingUpdate: &MachineRollingUpdateDeployment{
+				MaxSurge: intOrStr(1),
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error with maxUnavailable",
+			rollingUpdate: &MachineRollingUpdateDeployment{
+				MaxUnavailable: intOrStr(0),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			md := &MachineDeployment{
+				Spec: MachineDeploymentSpec{
+					Strategy: &MachineDeploymentStrategy{
+						Type:          OnDeleteMachineDeploymentStrategyType,
+						RollingUpdate: tt.rollingUpdate,
+					},
+				},
+			}
+			md.Default()
+			g.Expect(md.Spec.Strategy.RollingUpdate).To(Equal(tt.rollingUpdate))
+			if tt.expectErr {
+				g.Expect(md.ValidateCreate()).NotTo(Succeed())
+				g.Expect(md.ValidateUpdate(md)).NotTo(Succeed())
+			} else {
+				g.Expect(md.ValidateCreate()).To(Succeed())
+				g.Expect(md.ValidateUpdate(md)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineDeploymentRollbackToRevisionValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		revision  string
+		expectErr bool
+	}{
+		{
+			name:      "should not return error for a positive revision",
+			revision:  "2",
+			expectErr: false,
+		},
+		{
+			name:      "should return error for a zero revision",
+			revision:  "0",
+			expectErr: true,
+		},
+		{
+			name:      "should return error for a negative revision",
+			revision:  "-1",
+			expectErr: true,
+		},
+		{
+			name:      "should return error for a revision which is not an integer",
+			revision:  "latest",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			md := &MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{RollbackToRevisionAnnotation: tt.revision},
+				},
+			}
+			if tt.expectErr {
+				g.Expect(md.ValidateCreate()).NotTo(Succeed())
+				g.Expect(md.ValidateUpdate(md)).NotTo(Succeed())
+			} else {
+				g.Expect(md.ValidateCreate()).To(Succeed())
+				g.Expect(md.ValidateUpdate(md)).To(Succeed())
+			}
+		})
+	}
+}
+
 func TestMachineDeploymentWithSpec(t *testing.T) {
 	g := NewWithT(t)
 	md := MachineDeployment{
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MachineDrainRuleDrainBehavior defines the drain behavior of the Pods matching a MachineDrainRule.
+// +kubebuilder:validation:Enum=Drain;Skip;WaitCompleted
+type MachineDrainRuleDrainBehavior string
+
+const (
+	// MachineDrainRuleDrainBehaviorDrain means the Pods are evicted, even if they would be skipped
+	// by default, e.g. because they are managed by a DaemonSet. Mirror Pods are always skipped.
+	MachineDrainRuleDrainBehaviorDrain MachineDrainRuleDrainBehavior = "Drain"
+
+	// MachineDrainRuleDrainBehaviorSkip means the Pods are not evicted.
+	MachineDrainRuleDrainBehaviorSkip MachineDrainRuleDrainBehavior = "Skip"
+
+	// MachineDrainRuleDrainBehaviorWaitCompleted means the Pods are not evicted, and the drain
+	// waits for them to complete (i.e. to be in the Succeeded or Failed phase, or to be gone).
+	MachineDrainRuleDrainBehaviorWaitCompleted MachineDrainRuleDrainBehavior = "WaitCompleted"
+)
+
+// ANCHOR: MachineDrainRuleSpec
+
+// MachineDrainRuleSpec defines the desired state of MachineDrainRule
+type MachineDrainRuleSpec struct {
+	// Behavior defines the drain behavior of the Pods matching the rule.
+	Behavior MachineDrainRuleDrainBehavior `json:"behavior"`
+
+	// Priority defines the order in which the rules are evaluated; the rule with the highest
+	// priority matching a Pod defines its drain behavior, and rules with the same priority are
+	// evaluated in alphabetical order of their names.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// MachineSelector selects the Machines in the namespace of the rule whose Node drain is
+	// affected by the rule. An empty or nil selector selects all the Machines.
+	// +optional
+	MachineSelector *metav1.LabelSelector `json:"machineSelector,omitempty"`
+
+	// Pods defines the Pods matching the rule; a Pod matches if it is matched by any of the
+	// selectors. An empty list matches all the Pods.
+	// +optional
+	Pods []MachineDrainRulePodSelector `json:"pods,omitempty"`
+}
+
+// ANCHOR_END: MachineDrainRuleSpec
+
+// MachineDrainRulePodSelector selects Pods in the workload cluster.
+type MachineDrainRulePodSelector struct {
+	// Selector is a label selector matching the labels of the Pods.
+	// An empty or nil selector matches all the Pods.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// NamespaceSelector is a label selector matching the labels of the namespaces of the Pods.
+	// An empty or nil selector matches all the namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=machinedrainrules,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Behavior",type="string",JSONPath=".spec.behavior",description="Drain behavior of the matching Pods"
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority",description="Priority of the rule"
+
+// MachineDrainRule is the Schema for the machinedrainrules API
+type MachineDrainRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MachineDrainRuleSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MachineDrainRuleList contains a list of MachineDrainRule
+type MachineDrainRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MachineDrainRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MachineDrainRule{}, &MachineDrainRuleList{})
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -30,9 +32,16 @@ type MachineHealthCheckSpec struct {
 	// +kubebuilder:validation:MinLength=1
 	ClusterName string `json:"clusterName"`
 
-	// Label selector to match machines whose health will be exercised
+	// Label selector to match machines whose health will be exercised.
+	// Optional if Selectors is set, otherwise must not be empty.
+	// +optional
 	Selector metav1.LabelSelector `json:"selector"`
 
+	// Selectors are additional label selectors to match machines whose health will be exercised, for machines
+	// with disjoint label sets. A machine is matched if any of Selector and Selectors matches its labels.
+	// +optional
+	Selectors []metav1.LabelSelector `json:"selectors,omitempty"`
+
 	// UnhealthyConditions contains a list of the conditions that determine
 	// whether a node is considered unhealthy.  The conditions are combined in a
 	// logical OR, i.e. if any of the conditions is met, the node is unhealthy.
@@ -41,11 +50,11 @@ type MachineHealthCheckSpec struct {
 	UnhealthyConditions []UnhealthyCondition `json:"unhealthyConditions"`
 
 	// Any further remediation is only allowed if at most "MaxUnhealthy" machines selected by
-	// "selector" are not healthy.
+	// "selector" and "selectors" are not healthy. A percentage is rounded up, an absolute value of 0 disables remediation.
 	// +optional
 	MaxUnhealthy *intstr.IntOrString `json:"maxUnhealthy,omitempty"`
 
-	// Any further remediation is only allowed if the number of machines selected by "selector" as not healthy
+	// Any further remediation is only allowed if the number of machines selected by "selector" and "selectors" as not healthy
 	// is within the range of "UnhealthyRange". Takes precedence over MaxUnhealthy.
 	// Eg. "[3-5]" - This means that remediation will be allowed only when:
 	// (a) there are at least 3 unhealthy machines (and)
@@ -56,6 +65,8 @@ type MachineHealthCheckSpec struct {
 
 	// Machines older than this duration without a node will be considered to have
 	// failed and will be remediated.
+	// Machines with a node are never remediated because of this timeout, they are only
+	// checked against UnhealthyConditions.
 	// +optional
 	NodeStartupTimeout *metav1.Duration `json:"nodeStartupTimeout,omitempty"`
 
@@ -65,12 +76,49 @@ type MachineHealthCheckSpec struct {
 	// This field is completely optional, when filled, the MachineHealthCheck controller
 	// creates a new object from the template referenced and hands off remediation of the machine to
 	// a controller that lives outside of Cluster API.
+	// The remediation object reports its progress through status.completed, set to true once the machine is
+	// remediated, and status.retryCount, the number of times the remediation was retried.
+	// If the remediation object reports a failure, by setting status.failureReason or status.failureMessage,
+	// or exhausts RemediationMaxRetries, the machine is marked for remediation by its owner, which deletes it.
 	// +optional
 	RemediationTemplate *corev1.ObjectReference `json:"remediationTemplate,omitempty"`
+
+	// RemediationMaxRetries is the number of retries of the remediation object created from RemediationTemplate
+	// after which the machine is marked for remediation by its owner, if the remediation is not yet completed.
+	// When not set, the remediation object is retried until it completes or reports a failure.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RemediationMaxRetries *int32 `json:"remediationMaxRetries,omitempty"`
+
+	// RemediationBackoff spaces out consecutive remediations of machines with the same owner, e.g. a MachineSet,
+	// so a replacement which keeps failing does not lead to a tight delete/recreate loop.
+	// When not set, machines are remediated as soon as they are unhealthy.
+	// +optional
+	RemediationBackoff *RemediationBackoff `json:"remediationBackoff,omitempty"`
 }
 
 // ANCHOR_END: MachineHealthCHeckSpec
 
+// DefaultRemediationBackoffInitialDelay is the default delay between the first and the second consecutive remediation
+// of machines with the same owner, when RemediationBackoff.InitialDelay is not set.
+const DefaultRemediationBackoffInitialDelay = time.Minute
+
+// ANCHOR: RemediationBackoff
+
+// RemediationBackoff defines an exponential backoff between consecutive remediations of machines with the same owner.
+type RemediationBackoff struct {
+	// InitialDelay is the delay enforced between the first and the second consecutive remediation of machines
+	// with the same owner; the delay doubles at each further consecutive remediation.
+	// Defaults to 1 minute.
+	// +optional
+	InitialDelay *metav1.Duration `json:"initialDelay,omitempty"`
+
+	// MaxDelay is the maximum delay enforced between consecutive remediations of machines with the same owner.
+	MaxDelay metav1.Duration `json:"maxDelay"`
+}
+
+// ANCHOR_END: RemediationBackoff
+
 // ANCHOR: UnhealthyCondition
 
 // UnhealthyCondition represents a Node condition type and value with a timeout
@@ -118,10 +166,29 @@ type MachineHealthCheckStatus struct {
 	// Conditions defines current service state of the MachineHealthCheck.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
+
+	// RemediationAttempts tracks the consecutive remediations of machines with the same owner,
+	// which are used to compute the remediation backoff.
+	// +optional
+	RemediationAttempts []RemediationAttempt `json:"remediationAttempts,omitempty"`
 }
 
 // ANCHOR_END: MachineHealthCheckStatus
 
+// RemediationAttempt reports the consecutive remediations of machines with the same owner.
+type RemediationAttempt struct {
+	// Owner identifies the owner of the remediated machines, in the Kind/Name format; machines
+	// without a controller owner are tracked as Machine/Name.
+	Owner string `json:"owner"`
+
+	// Count is the number of consecutive remediations of machines with this owner.
+	// The count is reset when all the machines of the owner are healthy.
+	Count int32 `json:"count"`
+
+	// LastAttemptTime is the time of the last remediation of a machine with this owner.
+	LastAttemptTime metav1.Time `json:"lastAttemptTime"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=machinehealthchecks,shortName=mhc;mhcs,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
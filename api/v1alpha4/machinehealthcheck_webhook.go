@@ -76,6 +76,10 @@ func (m *MachineHealthCheck) Default() {
 	if m.Spec.NodeStartupTimeout == nil {
 		m.Spec.NodeStartupTimeout = &defaultNodeStartupTimeout
 	}
+
+	if m.Spec.RemediationBackoff != nil && m.Spec.RemediationBackoff.InitialDelay == nil {
+		m.Spec.RemediationBackoff.InitialDelay = &metav1.Duration{Duration: DefaultRemediationBackoffInitialDelay}
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
@@ -97,30 +101,42 @@ func (m *MachineHealthCheck) ValidateDelete() error {
 	return nil
 }
 
-func (m *MachineHealthCheck) validate(old *MachineHealthCheck) error {
+func (m *MachineHealthCheck) validateSelector(labelSelector metav1.LabelSelector, allowEmpty bool, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	// Validate selector parses as Selector
-	selector, err := metav1.LabelSelectorAsSelector(&m.Spec.Selector)
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
 	if err != nil {
 		allErrs = append(
 			allErrs,
-			field.Invalid(field.NewPath("spec", "selector"), m.Spec.Selector, err.Error()),
+			field.Invalid(fldPath, labelSelector, err.Error()),
 		)
 	}
 
 	// Validate that the selector isn't empty.
-	if selector != nil && selector.Empty() {
+	if selector != nil && selector.Empty() && !allowEmpty {
 		allErrs = append(
 			allErrs,
-			field.Invalid(field.NewPath("spec", "selector"), m.Spec.Selector, "selector must not be empty"),
+			field.Invalid(fldPath, labelSelector, "selector must not be empty"),
 		)
 	}
 
-	if clusterName, ok := m.Spec.Selector.MatchLabels[ClusterLabelName]; ok && clusterName != m.Spec.ClusterName {
+	if clusterName, ok := labelSelector.MatchLabels[ClusterLabelName]; ok && clusterName != m.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
-			field.Invalid(field.NewPath("spec", "selector"), m.Spec.Selector, "cannot specify a cluster selector other than the one specified by ClusterName"))
+			field.Invalid(fldPath, labelSelector, "cannot specify a cluster selector other than the one specified by ClusterName"))
+	}
+
+	return allErrs
+}
+
+func (m *MachineHealthCheck) validate(old *MachineHealthCheck) error {
+	var allErrs field.ErrorList
+
+	// The selector can be empty only if selectors are set.
+	allErrs = append(allErrs, m.validateSelector(m.Spec.Selector, len(m.Spec.Selectors) > 0, field.NewPath("spec", "selector"))...)
+	for i := range m.Spec.Selectors {
+		allErrs = append(allErrs, m.validateSelector(m.Spec.Selectors[i], false, field.NewPath("spec", "selectors").Index(i))...)
 	}
 
 	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
@@ -153,6 +169,32 @@ func (m *MachineHealthCheck) validate(old *MachineHealthCheck) error {
 		}
 	}
 
+	if m.Spec.RemediationMaxRetries != nil && m.Spec.RemediationTemplate == nil {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(field.NewPath("spec", "remediationMaxRetries"), "can only be set together with remediationTemplate"),
+		)
+	}
+
+	if m.Spec.RemediationBackoff != nil {
+		initialDelay := metav1.Duration{Duration: DefaultRemediationBackoffInitialDelay}
+		if m.Spec.RemediationBackoff.InitialDelay != nil {
+			initialDelay = *m.Spec.RemediationBackoff.InitialDelay
+		}
+		if initialDelay.Duration <= 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(field.NewPath("spec", "remediationBackoff", "initialDelay"), initialDelay.Duration.String(), "must be greater than 0"),
+			)
+		}
+		if m.Spec.RemediationBackoff.MaxDelay.Duration < initialDelay.Duration {
+			allErrs = append(
+				allErrs,
+				field.Invalid(field.NewPath("spec", "remediationBackoff", "maxDelay"), m.Spec.RemediationBackoff.MaxDelay.Duration.String(), "must be greater than or equal to initialDelay"),
+			)
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
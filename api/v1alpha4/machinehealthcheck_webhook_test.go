@@ -22,8 +22,10 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
 )
 
 func TestMachineHealthCheckDefault(t *testing.T) {
@@ -36,6 +38,13 @@ func TestMachineHealthCheckDefault(t *testing.T) {
 	g.Expect(mhc.Spec.MaxUnhealthy.String()).To(Equal("100%"))
 	g.Expect(mhc.Spec.NodeStartupTimeout).ToNot(BeNil())
 	g.Expect(*mhc.Spec.NodeStartupTimeout).To(Equal(metav1.Duration{Duration: 10 * time.Minute}))
+	g.Expect(mhc.Spec.RemediationBackoff).To(BeNil())
+
+	mhc.Spec.RemediationBackoff = &RemediationBackoff{MaxDelay: metav1.Duration{Duration: time.Hour}}
+	mhc.Default()
+
+	g.Expect(mhc.Spec.RemediationBackoff.InitialDelay).ToNot(BeNil())
+	g.Expect(*mhc.Spec.RemediationBackoff.InitialDelay).To(Equal(metav1.Duration{Duration: time.Minute}))
 }
 
 func TestMachineHealthCheckLabelSelectorAsSelectorValidation(t *testing.T) {
@@ -253,6 +262,119 @@ func TestMachineHealthCheckMaxUnhealthy(t *testing.T) {
 	}
 }
 
+func TestMachineHealthCheckRemediationBackoff(t *testing.T) {
+	tests := []struct {
+		name         string
+		initialDelay *metav1.Duration
+		maxDelay     metav1.Duration
+		expectErr    bool
+	}{
+		{
+			name:         "when maxDelay is greater than initialDelay",
+			initialDelay: &metav1.Duration{Duration: time.Minute},
+			maxDelay:     metav1.Duration{Duration: time.Hour},
+			expectErr:    false,
+		},
+		{
+			name:      "when initialDelay is not set and maxDelay is greater than the default",
+			maxDelay:  metav1.Duration{Duration: time.Hour},
+			expectErr: false,
+		},
+		{
+			name:         "when initialDelay is zero",
+			initialDelay: &metav1.Duration{},
+			maxDelay:     metav1.Duration{Duration: time.Hour},
+			expectErr:    true,
+		},
+		{
+			name:         "when maxDelay is lower than initialDelay",
+			initialDelay: &metav1.Duration{Duration: time.Hour},
+			maxDelay:     metav1.Duration{Duration: time.Minute},
+			expectErr:    true,
+		},
+		{
+			name:      "when maxDelay is not set",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mhc := &MachineHealthCheck{
+				Spec: MachineHealthCheckSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					RemediationBackoff: &RemediationBackoff{
+						InitialDelay: tt.initialDelay,
+						MaxDelay:     tt.maxDelay,
+					},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(mhc.ValidateCreate()).NotTo(Succeed())
+				g.Expect(mhc.ValidateUpdate(mhc)).NotTo(Succeed())
+			} else {
+				g.Expect(mhc.ValidateCreate()).To(Succeed())
+				g.Expect(mhc.ValidateUpdate(mhc)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineHealthCheckRemediationMaxRetries(t *testing.T) {
+	tests := []struct {
+		name                string
+		remediationTemplate *corev1.ObjectReference
+		expectErr           bool
+	}{
+		{
+			name: "when remediationTemplate is set",
+			remediationTemplate: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+				Kind:       "InfrastructureRemediationTemplate",
+				Name:       "remediation-template",
+			},
+			expectErr: false,
+		},
+		{
+			name:      "when remediationTemplate is not set",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mhc := &MachineHealthCheck{
+				Spec: MachineHealthCheckSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					RemediationTemplate:   tt.remediationTemplate,
+					RemediationMaxRetries: pointer.Int32Ptr(3),
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(mhc.ValidateCreate()).NotTo(Succeed())
+				g.Expect(mhc.ValidateUpdate(mhc)).NotTo(Succeed())
+			} else {
+				g.Expect(mhc.ValidateCreate()).To(Succeed())
+				g.Expect(mhc.ValidateUpdate(mhc)).To(Succeed())
+			}
+		})
+	}
+}
+
 func TestMachineHealthCheckSelectorValidation(t *testing.T) {
 	g := NewWithT(t)
 	mhc := &MachineHealthCheck{}
@@ -261,6 +383,60 @@ func TestMachineHealthCheckSelectorValidation(t *testing.T) {
 	g.Expect(err.Error()).To(ContainSubstring("selector must not be empty"))
 }
 
+func TestMachineHealthCheckSelectorsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		selector  metav1.LabelSelector
+		selectors []metav1.LabelSelector
+		expectErr string
+	}{
+		{
+			name:      "should not return error for selectors without selector",
+			selectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"pool": "a"}}, {MatchLabels: map[string]string{"pool": "b"}}},
+		},
+		{
+			name:      "should not return error for selectors with selector",
+			selector:  metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+			selectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"pool": "b"}}},
+		},
+		{
+			name:      "should return error for an empty selector in selectors",
+			selectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"pool": "a"}}, {}},
+			expectErr: "spec.selectors[1]: Invalid value",
+		},
+		{
+			name:      "should return error for an invalid selector in selectors",
+			selectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"-123-pool": "a"}}},
+			expectErr: "spec.selectors[0]: Invalid value",
+		},
+		{
+			name:      "should return error for a selector in selectors with a cluster other than clusterName",
+			selectors: []metav1.LabelSelector{{MatchLabels: map[string]string{ClusterLabelName: "bar"}}},
+			expectErr: "cannot specify a cluster selector other than the one specified by ClusterName",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mhc := &MachineHealthCheck{
+				Spec: MachineHealthCheckSpec{
+					ClusterName: "foo",
+					Selector:    tt.selector,
+					Selectors:   tt.selectors,
+				},
+			}
+			err := mhc.validate(nil)
+			if tt.expectErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestMachineHealthCheckClusterNameSelectorValidation(t *testing.T) {
 	g := NewWithT(t)
 	mhc := &MachineHealthCheck{
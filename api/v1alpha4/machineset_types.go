@@ -83,24 +83,26 @@ type MachineTemplateSpec struct {
 
 // MachineSetDeletePolicy defines how priority is assigned to nodes to delete when
 // downscaling a MachineSet. Defaults to "Random".
+//
+// Regardless of the policy, Machines that have the annotation "cluster.x-k8s.io/delete-machine"
+// are deleted first, followed by Machines that are unhealthy (they have no NodeRef,
+// Status.FailureReason or Status.FailureMessage are set to a non-empty value, or the
+// HealthCheckSucceeded condition is false). The policy only orders the remaining Machines.
 type MachineSetDeletePolicy string
 
 const (
 	// RandomMachineSetDeletePolicy prioritizes both Machines that have the annotation
-	// "cluster.x-k8s.io/delete-machine=yes" and Machines that are unhealthy
-	// (Status.FailureReason or Status.FailureMessage are set to a non-empty value).
+	// "cluster.x-k8s.io/delete-machine=yes" and Machines that are unhealthy.
 	// Finally, it picks Machines at random to delete.
 	RandomMachineSetDeletePolicy MachineSetDeletePolicy = "Random"
 
 	// NewestMachineSetDeletePolicy prioritizes both Machines that have the annotation
-	// "cluster.x-k8s.io/delete-machine=yes" and Machines that are unhealthy
-	// (Status.FailureReason or Status.FailureMessage are set to a non-empty value).
+	// "cluster.x-k8s.io/delete-machine=yes" and Machines that are unhealthy.
 	// It then prioritizes the newest Machines for deletion based on the Machine's CreationTimestamp.
 	NewestMachineSetDeletePolicy MachineSetDeletePolicy = "Newest"
 
 	// OldestMachineSetDeletePolicy prioritizes both Machines that have the annotation
-	// "cluster.x-k8s.io/delete-machine=yes" and Machines that are unhealthy
-	// (Status.FailureReason or Status.FailureMessage are set to a non-empty value).
+	// "cluster.x-k8s.io/delete-machine=yes" and Machines that are unhealthy.
 	// It then prioritizes the oldest Machines for deletion based on the Machine's CreationTimestamp.
 	OldestMachineSetDeletePolicy MachineSetDeletePolicy = "Oldest"
 )
@@ -111,6 +111,8 @@ func (m *MachineSet) validate(old *MachineSet) error {
 		)
 	}
 
+	allErrs = append(allErrs, validateReadinessGates(m.Spec.Template.Spec.ReadinessGates, field.NewPath("spec", "template", "spec", "readinessGates"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
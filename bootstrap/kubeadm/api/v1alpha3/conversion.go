@@ -19,19 +19,47 @@ package v1alpha3
 import (
 	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	kubeadmbootstrapv1alpha4 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
 // ConvertTo converts this KubeadmConfig to the Hub version (v1alpha4).
 func (src *KubeadmConfig) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*kubeadmbootstrapv1alpha4.KubeadmConfig)
-	return Convert_v1alpha3_KubeadmConfig_To_v1alpha4_KubeadmConfig(src, dst, nil)
+
+	if err := Convert_v1alpha3_KubeadmConfig_To_v1alpha4_KubeadmConfig(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &kubeadmbootstrapv1alpha4.KubeadmConfig{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.TokenTTL = restored.Spec.TokenTTL
+	dst.Spec.TokenExtraGroups = restored.Spec.TokenExtraGroups
+	dst.Spec.TokenUsages = restored.Spec.TokenUsages
+	dst.Spec.ImageRepository = restored.Spec.ImageRepository
+	dst.Spec.AdditionalDataSecretKeys = restored.Spec.AdditionalDataSecretKeys
+	dst.Spec.GzipDataSecret = restored.Spec.GzipDataSecret
+	dst.Spec.Patches = restored.Spec.Patches
+	dst.Spec.SkipPhases = restored.Spec.SkipPhases
+	dst.Status.BootstrapDataPreview = restored.Status.BootstrapDataPreview
+
+	return nil
 }
 
 // ConvertFrom converts from the KubeadmConfig Hub version (v1alpha4) to this version.
 func (dst *KubeadmConfig) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*kubeadmbootstrapv1alpha4.KubeadmConfig)
-	return Convert_v1alpha4_KubeadmConfig_To_v1alpha3_KubeadmConfig(src, dst, nil)
+
+	if err := Convert_v1alpha4_KubeadmConfig_To_v1alpha3_KubeadmConfig(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 // ConvertTo converts this KubeadmConfigList to the Hub version (v1alpha4).
@@ -49,13 +77,39 @@ func (dst *KubeadmConfigList) ConvertFrom(srcRaw conversion.Hub) error {
 // ConvertTo converts this KubeadmConfigTemplate to the Hub version (v1alpha4).
 func (src *KubeadmConfigTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*kubeadmbootstrapv1alpha4.KubeadmConfigTemplate)
-	return Convert_v1alpha3_KubeadmConfigTemplate_To_v1alpha4_KubeadmConfigTemplate(src, dst, nil)
+
+	if err := Convert_v1alpha3_KubeadmConfigTemplate_To_v1alpha4_KubeadmConfigTemplate(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &kubeadmbootstrapv1alpha4.KubeadmConfigTemplate{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Template.Spec.TokenTTL = restored.Spec.Template.Spec.TokenTTL
+	dst.Spec.Template.Spec.TokenExtraGroups = restored.Spec.Template.Spec.TokenExtraGroups
+	dst.Spec.Template.Spec.TokenUsages = restored.Spec.Template.Spec.TokenUsages
+	dst.Spec.Template.Spec.ImageRepository = restored.Spec.Template.Spec.ImageRepository
+	dst.Spec.Template.Spec.AdditionalDataSecretKeys = restored.Spec.Template.Spec.AdditionalDataSecretKeys
+	dst.Spec.Template.Spec.GzipDataSecret = restored.Spec.Template.Spec.GzipDataSecret
+	dst.Spec.Template.Spec.Patches = restored.Spec.Template.Spec.Patches
+	dst.Spec.Template.Spec.SkipPhases = restored.Spec.Template.Spec.SkipPhases
+
+	return nil
 }
 
 // ConvertFrom converts from the KubeadmConfigTemplate Hub version (v1alpha4) to this version.
 func (dst *KubeadmConfigTemplate) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*kubeadmbootstrapv1alpha4.KubeadmConfigTemplate)
-	return Convert_v1alpha4_KubeadmConfigTemplate_To_v1alpha3_KubeadmConfigTemplate(src, dst, nil)
+
+	if err := Convert_v1alpha4_KubeadmConfigTemplate_To_v1alpha3_KubeadmConfigTemplate(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 // ConvertTo converts this KubeadmConfigTemplateList to the Hub version (v1alpha3).
@@ -74,3 +128,16 @@ func (dst *KubeadmConfigTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 func Convert_v1alpha3_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in *KubeadmConfigStatus, out *kubeadmbootstrapv1alpha4.KubeadmConfigStatus, s apiconversion.Scope) error { //nolint
 	return autoConvert_v1alpha3_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in, out, s)
 }
+
+// Convert_v1alpha4_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec is an autogenerated conversion function.
+func Convert_v1alpha4_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in *kubeadmbootstrapv1alpha4.KubeadmConfigSpec, out *KubeadmConfigSpec, s apiconversion.Scope) error {
+	// KubeadmConfigSpec.TokenTTL, KubeadmConfigSpec.TokenExtraGroups, KubeadmConfigSpec.TokenUsages, KubeadmConfigSpec.ImageRepository,
+	// KubeadmConfigSpec.AdditionalDataSecretKeys and KubeadmConfigSpec.GzipDataSecret do not exist in v1alpha3.
+	return autoConvert_v1alpha4_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in, out, s)
+}
+
+// Convert_v1alpha4_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus is an autogenerated conversion function.
+func Convert_v1alpha4_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus(in *kubeadmbootstrapv1alpha4.KubeadmConfigStatus, out *KubeadmConfigStatus, s apiconversion.Scope) error {
+	// KubeadmConfigStatus.BootstrapDataPreview does not exist in v1alpha3.
+	return autoConvert_v1alpha4_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus(in, out, s)
+}
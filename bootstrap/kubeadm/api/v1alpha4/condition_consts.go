@@ -49,6 +49,26 @@ const (
 	// an error while generating a data secret; those kind of errors are usually due to misconfigurations
 	// and user intervention is required to get them fixed.
 	DataSecretGenerationFailedReason = "DataSecretGenerationFailed"
+
+	// ClusterCertificatesUnavailableReason (Severity=Warning) documents a KubeadmConfig controller unable to
+	// generate a data secret because the cluster certificates, e.g. the cluster CA, cannot be found or generated;
+	// see the CertificatesAvailableCondition for details.
+	ClusterCertificatesUnavailableReason = "ClusterCertificatesUnavailable"
+
+	// DataSecretRenderFailedReason (Severity=Warning) documents a KubeadmConfig controller unable to
+	// generate a data secret because the kubeadm configuration or the cloud-init/Ignition data cannot be rendered;
+	// those kind of errors are usually due to misconfigurations and user intervention is required to get them fixed.
+	DataSecretRenderFailedReason = "DataSecretRenderFailed"
+
+	// FileContentUnavailableReason (Severity=Warning) documents a KubeadmConfig controller unable to
+	// generate a data secret because a Secret referenced by the files of the KubeadmConfig does not exist
+	// or does not contain the referenced key.
+	FileContentUnavailableReason = "FileContentUnavailable"
+
+	// ImageRepositoryMismatchReason (Severity=Warning) documents a KubeadmConfig controller unable to
+	// generate a data secret because the imageRepository of the KubeadmConfig does not match the imageRepository
+	// of the ClusterConfiguration the control plane was initialized with.
+	ImageRepositoryMismatchReason = "ImageRepositoryMismatch"
 )
 
 const (
@@ -69,3 +89,33 @@ const (
 	// an error while while retrieving certificates for a joining node.
 	CertificatesCorruptedReason = "CertificatesCorrupted"
 )
+
+const (
+	// TokenCapacityAvailableCondition documents whether a new bootstrap token can be created in the workload cluster
+	// without exceeding the maximum number of active tokens per cluster the controller is configured with.
+	//
+	// NOTE: This condition is set only if the controller is configured with a maximum number of active tokens.
+	TokenCapacityAvailableCondition clusterv1.ConditionType = "TokenCapacityAvailable"
+
+	// TokenLimitReachedReason (Severity=Warning) documents a KubeadmConfig controller waiting for the number
+	// of active bootstrap tokens in the workload cluster to drop below the configured maximum before creating a new one.
+	TokenLimitReachedReason = "TokenLimitReached"
+)
+
+const (
+	// TokenValidCondition documents whether the bootstrap token used by the KubeadmConfig to join a node
+	// exists in the workload cluster and is not expired; the message reports when the token expires.
+	//
+	// NOTE: This condition is set only for bootstrap tokens which are refreshed or rotated by the KubeadmConfig controller.
+	TokenValidCondition clusterv1.ConditionType = "TokenValid"
+
+	// TokenExpiredReason (Severity=Warning) documents a bootstrap token which is past its expiration.
+	TokenExpiredReason = "TokenExpired"
+
+	// TokenNotFoundReason (Severity=Warning) documents a bootstrap token whose Secret does not exist in the workload cluster.
+	TokenNotFoundReason = "TokenNotFound"
+
+	// TokenInvalidReason (Severity=Warning) documents a bootstrap token whose Secret exists in the workload cluster
+	// but cannot be used, e.g. because its expiration cannot be parsed.
+	TokenInvalidReason = "TokenInvalid"
+)
@@ -17,18 +17,34 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 )
 
+const (
+	// KubeadmConfigFinalizer allows the KubeadmConfig controller to clean up the bootstrap token
+	// it created in the workload cluster before the KubeadmConfig is removed from the API server.
+	KubeadmConfigFinalizer = "kubeadmconfig.bootstrap.cluster.x-k8s.io"
+
+	// PreviewBootstrapDataAnnotation can be set on a KubeadmConfig to have the controller store a preview of the
+	// bootstrap data rendered from its spec in the status, with the certificates, tokens, passwords and the content
+	// of the files sourced from secrets masked. The preview is removed when the annotation is removed.
+	PreviewBootstrapDataAnnotation = "bootstrap.cluster.x-k8s.io/preview-bootstrap-data"
+)
+
 // Format specifies the output format of the bootstrap data
-// +kubebuilder:validation:Enum=cloud-config
+// +kubebuilder:validation:Enum=cloud-config;ignition
 type Format string
 
 const (
 	// CloudConfig make the bootstrap data to be of cloud-config format
 	CloudConfig Format = "cloud-config"
+
+	// Ignition make the bootstrap data to be of Ignition format
+	Ignition Format = "ignition"
 )
 
 // KubeadmConfigSpec defines the desired state of KubeadmConfig.
@@ -74,7 +90,8 @@ type KubeadmConfigSpec struct {
 	// +optional
 	NTP *NTP `json:"ntp,omitempty"`
 
-	// Format specifies the output format of the bootstrap data
+	// Format specifies the output format of the bootstrap data; it defaults to cloud-config.
+	// DiskSetup and Mounts are not supported with the ignition format.
 	// +optional
 	Format Format `json:"format,omitempty"`
 
@@ -95,8 +112,120 @@ type KubeadmConfigSpec struct {
 	// For more information, refer to https://github.com/kubernetes-sigs/cluster-api/pull/2763#discussion_r397306055.
 	// +optional
 	UseExperimentalRetryJoin bool `json:"useExperimentalRetryJoin,omitempty"`
+
+	// TokenTTL is the amount of time a bootstrap token generated for this config will be valid.
+	// If not set, the controller-wide default is used (15 minutes unless overridden via --bootstrap-token-ttl).
+	// Must be at least 1 minute.
+	// +optional
+	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
+
+	// TokenExtraGroups are the extra groups the bootstrap token generated for this config will authenticate as.
+	// If not set, the token authenticates as system:bootstrappers:kubeadm:default-node-token, which is the group
+	// kubeadm grants the permissions required to join; if set, the list replaces it and should usually include it.
+	// Each group must start with system:bootstrappers:.
+	// +optional
+	TokenExtraGroups []string `json:"tokenExtraGroups,omitempty"`
+
+	// TokenUsages are the ways the bootstrap token generated for this config can be used; allowed values
+	// are "signing", for signing the cluster-info ConfigMap, and "authentication", for authenticating to the API server
+	// as required to join nodes.
+	// If not set, both usages are enabled.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	TokenUsages []string `json:"tokenUsages,omitempty"`
+
+	// ImageRepository is the container registry joining nodes pull the pause image from, e.g. a mirror
+	// in air-gapped environments; kube-proxy and the other control plane images are pulled from the
+	// imageRepository of the ClusterConfiguration the control plane was initialized with, which must match.
+	// If not set, the kubelet default pause image is used.
+	// +optional
+	ImageRepository string `json:"imageRepository,omitempty"`
+
+	// AdditionalDataSecretKeys are keys of the bootstrap data secret the bootstrap data is stored under
+	// in addition to the value key, for infrastructure providers reading the bootstrap data from a different key.
+	// +optional
+	AdditionalDataSecretKeys []string `json:"additionalDataSecretKeys,omitempty"`
+
+	// GzipDataSecret enables storing a gzip-compressed copy of the bootstrap data under the value.gz key
+	// of the bootstrap data secret, for infrastructure providers with a size limit on the user data, e.g. AWS.
+	// +optional
+	GzipDataSecret bool `json:"gzipDataSecret,omitempty"`
+
+	// Patches are the kubeadm patches applied to the static pod manifests generated by kubeadm,
+	// passed to kubeadm init and join with the --experimental-patches flag for Kubernetes v1.19 to v1.21,
+	// and the --patches flag as of v1.22. Patches require Kubernetes v1.19 or later.
+	// +optional
+	Patches *Patches `json:"patches,omitempty"`
+
+	// SkipPhases are the phases of kubeadm init to skip, passed to kubeadm init with the --skip-phases flag,
+	// e.g. addon/kube-proxy to not install kube-proxy. They are ignored by kubeadm join.
+	// +optional
+	SkipPhases []string `json:"skipPhases,omitempty"`
+}
+
+// KubeProxyAddonPhase is the phase of kubeadm init installing kube-proxy.
+const KubeProxyAddonPhase = "addon/kube-proxy"
+
+// SkipsPhase returns true if the phase of kubeadm init, or its parent phase, is skipped.
+func (c *KubeadmConfigSpec) SkipsPhase(phase string) bool {
+	for _, skipped := range c.SkipPhases {
+		if skipped == phase || strings.HasPrefix(phase, skipped+"/") {
+			return true
+		}
+	}
+	return false
 }
 
+// DefaultPatchesDirectory is the directory the kubeadm patch files are written to when Patches.Directory is not set.
+const DefaultPatchesDirectory = "/etc/kubernetes/patches"
+
+// Patches defines the kubeadm patches applied to the static pod manifests generated by kubeadm.
+type Patches struct {
+	// Directory is the absolute path of the directory containing the patch files on the machine,
+	// which kubeadm reads the patches from. Additional patch files can be written to it with Files.
+	// Defaults to /etc/kubernetes/patches.
+	// +optional
+	Directory string `json:"directory,omitempty"`
+
+	// Files are the patch files written to Directory.
+	// +optional
+	Files []PatchFile `json:"files,omitempty"`
+}
+
+// PatchFile defines a kubeadm patch file.
+type PatchFile struct {
+	// Name is the name of the patch file, following the kubeadm naming convention target[suffix][+patchtype].extension,
+	// e.g. kube-apiserver0+merge.yaml. Target is one of kube-apiserver, kube-controller-manager, kube-scheduler and etcd,
+	// patchtype one of strategic (default), merge and json, and extension one of json and yaml.
+	Name string `json:"name"`
+
+	// Content is the content of the patch file.
+	Content string `json:"content"`
+}
+
+const (
+	// DataSecretValueKey is the key of the bootstrap data secret the bootstrap data is stored under.
+	DataSecretValueKey = "value"
+
+	// DataSecretFormatKey is the key of the bootstrap data secret the format of the bootstrap data is stored under.
+	DataSecretFormatKey = "format"
+
+	// DataSecretGzipValueKey is the key of the bootstrap data secret the gzip-compressed bootstrap data is stored under.
+	DataSecretGzipValueKey = "value.gz"
+
+	// DataSecretFormatLabel is the label set on the bootstrap data secret to the format of the bootstrap data, so
+	// infrastructure providers can select the secrets of a given format without reading them.
+	DataSecretFormatLabel = "bootstrap.cluster.x-k8s.io/format"
+)
+
+const (
+	// SigningTokenUsage allows a bootstrap token to be used for signing the cluster-info ConfigMap.
+	SigningTokenUsage = "signing"
+
+	// AuthenticationTokenUsage allows a bootstrap token to be used for authenticating to the API server.
+	AuthenticationTokenUsage = "authentication"
+)
+
 // KubeadmConfigStatus defines the observed state of KubeadmConfig
 type KubeadmConfigStatus struct {
 	// Ready indicates the BootstrapData field is ready to be consumed
@@ -121,6 +250,11 @@ type KubeadmConfigStatus struct {
 	// Conditions defines current service state of the KubeadmConfig.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// BootstrapDataPreview is the bootstrap data rendered from the spec of the KubeadmConfig with the sensitive
+	// values masked. It is only set while the KubeadmConfig has the preview-bootstrap-data annotation.
+	// +optional
+	BootstrapDataPreview string `json:"bootstrapDataPreview,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -18,10 +18,12 @@ package v1alpha4
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 )
 
 // These tests are written in BDD-style using Ginkgo framework. Refer to
@@ -145,6 +147,325 @@ func TestClusterValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"valid tokenTTL": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					TokenTTL: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+		},
+		"invalid tokenTTL shorter than a minute": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					TokenTTL: &metav1.Duration{Duration: 30 * time.Second},
+				},
+			},
+			expectErr: true,
+		},
+		"valid tokenExtraGroups": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					TokenExtraGroups: []string{"system:bootstrappers:kubeadm:default-node-token", "system:bootstrappers:custom"},
+				},
+			},
+		},
+		"invalid tokenExtraGroups without the system:bootstrappers: prefix": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					TokenExtraGroups: []string{"system:bootstrappers:kubeadm:default-node-token", "system:masters"},
+				},
+			},
+			expectErr: true,
+		},
+		"valid ignition format": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+				},
+			},
+		},
+		"invalid ignition format with diskSetup": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format:    Ignition,
+					DiskSetup: &DiskSetup{},
+				},
+			},
+			expectErr: true,
+		},
+		"valid tokenUsages": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					TokenUsages: []string{"authentication"},
+				},
+			},
+		},
+		"invalid empty tokenUsages": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					TokenUsages: []string{},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid unknown tokenUsages": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					TokenUsages: []string{"authentication", "encryption"},
+				},
+			},
+			expectErr: true,
+		},
+		"valid imageRepository matching clusterConfiguration": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					ImageRepository: "registry.example.com/k8s",
+					ClusterConfiguration: &kubeadmv1beta1.ClusterConfiguration{
+						ImageRepository: "registry.example.com/k8s",
+					},
+				},
+			},
+		},
+		"invalid imageRepository not matching clusterConfiguration": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					ImageRepository: "registry.example.com/k8s",
+					ClusterConfiguration: &kubeadmv1beta1.ClusterConfiguration{
+						ImageRepository: "k8s.gcr.io",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"valid additionalDataSecretKeys": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					AdditionalDataSecretKeys: []string{"userData", "custom-data"},
+				},
+			},
+		},
+		"invalid additionalDataSecretKeys with a reserved key": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					AdditionalDataSecretKeys: []string{"userData", "value.gz"},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid additionalDataSecretKeys with an invalid key": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					AdditionalDataSecretKeys: []string{"user data"},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid additionalDataSecretKeys with a duplicate key": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					AdditionalDataSecretKeys: []string{"userData", "custom-data", "userData"},
+				},
+			},
+			expectErr: true,
+		},
+		"valid patches": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Patches: &Patches{
+						Directory: "/etc/kubeadm/patches",
+						Files: []PatchFile{
+							{Name: "kube-apiserver0+merge.yaml", Content: "foo: bar"},
+							{Name: "kube-apiserver1.json", Content: "{}"},
+							{Name: "etcd+json.json", Content: "[]"},
+						},
+					},
+				},
+			},
+		},
+		"invalid patches with a relative directory": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Patches: &Patches{
+						Directory: "patches",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid patches with an unknown target": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Patches: &Patches{
+						Files: []PatchFile{{Name: "kube-proxy.yaml", Content: "foo: bar"}},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid patches with an unknown patch type": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Patches: &Patches{
+						Files: []PatchFile{{Name: "kube-apiserver+replace.yaml", Content: "foo: bar"}},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid patches with an unknown extension": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Patches: &Patches{
+						Files: []PatchFile{{Name: "kube-apiserver.yml", Content: "foo: bar"}},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid patches with conflicting names": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Patches: &Patches{
+						Files: []PatchFile{
+							{Name: "kube-scheduler.yaml", Content: "foo: bar"},
+							{Name: "kube-scheduler.yaml", Content: "bar: baz"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"valid skip phases": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					SkipPhases: []string{KubeProxyAddonPhase, "mark-control-plane"},
+				},
+			},
+		},
+		"invalid skip phases with an unknown phase": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					SkipPhases: []string{"addons/kube-proxy"},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid skip phases with an invalid sub phase": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					SkipPhases: []string{"addon/kube-proxy,addon/coredns"},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid skip phases with conflicting phases": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					SkipPhases: []string{KubeProxyAddonPhase, KubeProxyAddonPhase},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for name, tt := range cases {
@@ -160,3 +481,39 @@ func TestClusterValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestKubeadmConfigSpecSkipsPhase(t *testing.T) {
+	tests := []struct {
+		name       string
+		skipPhases []string
+		want       bool
+	}{
+		{
+			name: "no skipped phases",
+			want: false,
+		},
+		{
+			name:       "phase skipped",
+			skipPhases: []string{KubeProxyAddonPhase},
+			want:       true,
+		},
+		{
+			name:       "parent phase skipped",
+			skipPhases: []string{"addon"},
+			want:       true,
+		},
+		{
+			name:       "other phases skipped",
+			skipPhases: []string{"addon/coredns", "addon/kube"},
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			spec := &KubeadmConfigSpec{SkipPhases: tt.skipPhases}
+			g.Expect(spec.SkipsPhase(KubeProxyAddonPhase)).To(Equal(tt.want))
+		})
+	}
+}
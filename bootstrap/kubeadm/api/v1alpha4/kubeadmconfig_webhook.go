@@ -18,22 +18,53 @@ package v1alpha4
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var (
-	ConflictingFileSourceMsg = "only one of content of contentFrom may be specified for a single file"
-	MissingFileSourceMsg     = "source for file content must be specified if contenFrom is non-nil"
-	MissingSecretNameMsg     = "secret file source must specify non-empty secret name"
-	MissingSecretKeyMsg      = "secret file source must specify non-empty secret key"
-	PathConflictMsg          = "path property must be unique among all files"
+	ConflictingFileSourceMsg   = "only one of content of contentFrom may be specified for a single file"
+	MissingFileSourceMsg       = "source for file content must be specified if contenFrom is non-nil"
+	MissingSecretNameMsg       = "secret file source must specify non-empty secret name"
+	MissingSecretKeyMsg        = "secret file source must specify non-empty secret key"
+	PathConflictMsg            = "path property must be unique among all files"
+	TokenTTLTooShortMsg        = fmt.Sprintf("tokenTTL must be at least %s", MinimumTokenTTL)
+	InvalidTokenExtraGroupMsg  = fmt.Sprintf("token extra groups must start with %q", BootstrapTokenGroupPrefix)
+	InvalidTokenUsageMsg       = fmt.Sprintf("token usages must be one of %q or %q", SigningTokenUsage, AuthenticationTokenUsage)
+	EmptyTokenUsagesMsg        = "token usages must not be empty if specified"
+	UnsupportedIgnitionMsg     = "not supported when format is ignition"
+	ImageRepositoryMismatchMsg = "imageRepository must match clusterConfiguration.imageRepository if both are specified"
+	ReservedDataSecretKeyMsg   = fmt.Sprintf("additional data secret keys must not be %q, %q or %q", DataSecretValueKey, DataSecretFormatKey, DataSecretGzipValueKey)
+	DataSecretKeyConflictMsg   = "additional data secret keys must be unique"
+	RelativePatchesDirMsg      = "patches directory must be an absolute path"
+	InvalidPatchFileNameMsg    = "patch file name must be target[suffix][+patchtype].extension, with target one of kube-apiserver, kube-controller-manager, kube-scheduler or etcd, patchtype one of strategic, merge or json, and extension one of json or yaml"
+	PatchFileNameConflictMsg   = "name property must be unique among all patch files"
+	InvalidSkipPhaseMsg        = "skip phase must be a phase of kubeadm init, optionally followed by a sub phase, e.g. addon/kube-proxy"
+	SkipPhaseConflictMsg       = "skip phases must be unique"
 )
 
+// patchFileNameRegex matches the names of the patch files kubeadm applies: target[suffix][+patchtype].extension.
+var patchFileNameRegex = regexp.MustCompile(`^(kube-apiserver|kube-controller-manager|kube-scheduler|etcd)[^+.]*(\+(strategic|merge|json))?\.(json|yaml)$`)
+
+// skipPhaseRegex matches the phases of kubeadm init, optionally followed by a sub phase: phase[/subphase].
+var skipPhaseRegex = regexp.MustCompile(`^(preflight|certs|kubeconfig|kubelet-start|control-plane|etcd|upload-config|upload-certs|mark-control-plane|bootstrap-token|kubelet-finalize|addon|show-join-command)(/[a-z0-9]([-a-z0-9]*[a-z0-9])?)?$`)
+
+// BootstrapTokenGroupPrefix is the prefix required for the extra groups of a bootstrap token.
+const BootstrapTokenGroupPrefix = "system:bootstrappers:"
+
+// MinimumTokenTTL is the shortest TokenTTL accepted for a KubeadmConfig.
+const MinimumTokenTTL = 1 * time.Minute
+
 func (c *KubeadmConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
@@ -115,8 +146,146 @@ func (c *KubeadmConfigSpec) validate(name string) error {
 		knownPaths[file.Path] = struct{}{}
 	}
 
+	allErrs = append(allErrs, ValidateTokenTTL(c.TokenTTL, field.NewPath("spec", "tokenTTL"))...)
+
+	allErrs = append(allErrs, ValidateTokenExtraGroups(c.TokenExtraGroups, field.NewPath("spec", "tokenExtraGroups"))...)
+
+	allErrs = append(allErrs, ValidateIgnitionFormat(c, field.NewPath("spec"))...)
+
+	allErrs = append(allErrs, ValidateTokenUsages(c.TokenUsages, field.NewPath("spec", "tokenUsages"))...)
+
+	allErrs = append(allErrs, ValidateImageRepository(c, field.NewPath("spec"))...)
+
+	allErrs = append(allErrs, ValidateAdditionalDataSecretKeys(c.AdditionalDataSecretKeys, field.NewPath("spec", "additionalDataSecretKeys"))...)
+	allErrs = append(allErrs, ValidatePatches(c.Patches, field.NewPath("spec", "patches"))...)
+	allErrs = append(allErrs, ValidateSkipPhases(c.SkipPhases, field.NewPath("spec", "skipPhases"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("KubeadmConfig").GroupKind(), name, allErrs)
 }
+
+// ValidateTokenTTL validates the TTL of the bootstrap tokens is not shorter than MinimumTokenTTL.
+func ValidateTokenTTL(ttl *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	if ttl == nil || ttl.Duration >= MinimumTokenTTL {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath, ttl.Duration.String(), TokenTTLTooShortMsg)}
+}
+
+// ValidateTokenExtraGroups validates the extra groups of the bootstrap tokens are bootstrap token groups.
+func ValidateTokenExtraGroups(groups []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, group := range groups {
+		if !strings.HasPrefix(group, BootstrapTokenGroupPrefix) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), group, InvalidTokenExtraGroupMsg))
+		}
+	}
+	return allErrs
+}
+
+// ValidateTokenUsages validates the usages of the bootstrap tokens are not empty, if set, and are known usages.
+func ValidateTokenUsages(usages []string, fldPath *field.Path) field.ErrorList {
+	if usages != nil && len(usages) == 0 {
+		return field.ErrorList{field.Invalid(fldPath, usages, EmptyTokenUsagesMsg)}
+	}
+	var allErrs field.ErrorList
+	for i, usage := range usages {
+		if usage != SigningTokenUsage && usage != AuthenticationTokenUsage {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), usage, InvalidTokenUsageMsg))
+		}
+	}
+	return allErrs
+}
+
+// ValidateIgnitionFormat validates the spec, at fldPath, does not use fields not supported by the Ignition format,
+// if the bootstrap data is rendered as Ignition.
+func ValidateIgnitionFormat(spec *KubeadmConfigSpec, fldPath *field.Path) field.ErrorList {
+	if spec.Format != Ignition {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if spec.DiskSetup != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("diskSetup"), UnsupportedIgnitionMsg))
+	}
+	if len(spec.Mounts) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("mounts"), UnsupportedIgnitionMsg))
+	}
+	return allErrs
+}
+
+// ValidateImageRepository validates the image repository of the spec, at fldPath, matches the image repository
+// of its cluster configuration, if both are set.
+func ValidateImageRepository(spec *KubeadmConfigSpec, fldPath *field.Path) field.ErrorList {
+	if spec.ImageRepository == "" || spec.ClusterConfiguration == nil || spec.ClusterConfiguration.ImageRepository == "" ||
+		spec.ImageRepository == spec.ClusterConfiguration.ImageRepository {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath.Child("imageRepository"), spec.ImageRepository, ImageRepositoryMismatchMsg)}
+}
+
+// ValidateAdditionalDataSecretKeys validates the additional keys of the bootstrap data secret are unique valid secret
+// keys that are not used by the bootstrap data secret already.
+func ValidateAdditionalDataSecretKeys(keys []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	knownKeys := map[string]struct{}{}
+	for i, key := range keys {
+		if _, conflict := knownKeys[key]; conflict {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, DataSecretKeyConflictMsg))
+			continue
+		}
+		knownKeys[key] = struct{}{}
+
+		switch key {
+		case DataSecretValueKey, DataSecretFormatKey, DataSecretGzipValueKey:
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, ReservedDataSecretKeyMsg))
+			continue
+		}
+		for _, msg := range validation.IsConfigMapKey(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, msg))
+		}
+	}
+	return allErrs
+}
+
+// ValidatePatches validates the patches directory is an absolute path, and the patch files are named
+// following the kubeadm naming convention.
+func ValidatePatches(patches *Patches, fldPath *field.Path) field.ErrorList {
+	if patches == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if patches.Directory != "" && !path.IsAbs(patches.Directory) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("directory"), patches.Directory, RelativePatchesDirMsg))
+	}
+
+	knownNames := map[string]struct{}{}
+	for i, file := range patches.Files {
+		if !patchFileNameRegex.MatchString(file.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("files").Index(i).Child("name"), file.Name, InvalidPatchFileNameMsg))
+		}
+		if _, conflict := knownNames[file.Name]; conflict {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("files").Index(i).Child("name"), file.Name, PatchFileNameConflictMsg))
+		}
+		knownNames[file.Name] = struct{}{}
+	}
+	return allErrs
+}
+
+// ValidateSkipPhases validates the skip phases are unique phases of kubeadm init.
+func ValidateSkipPhases(phases []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	knownPhases := map[string]struct{}{}
+	for i, phase := range phases {
+		if !skipPhaseRegex.MatchString(phase) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), phase, InvalidSkipPhaseMsg))
+		}
+		if _, conflict := knownPhases[phase]; conflict {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), phase, SkipPhaseConflictMsg))
+		}
+		knownPhases[phase] = struct{}{}
+	}
+	return allErrs
+}
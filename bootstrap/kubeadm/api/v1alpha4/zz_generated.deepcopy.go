@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
@@ -253,6 +254,36 @@ func (in *KubeadmConfigSpec) DeepCopyInto(out *KubeadmConfigSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.TokenTTL != nil {
+		in, out := &in.TokenTTL, &out.TokenTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TokenExtraGroups != nil {
+		in, out := &in.TokenExtraGroups, &out.TokenExtraGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TokenUsages != nil {
+		in, out := &in.TokenUsages, &out.TokenUsages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalDataSecretKeys != nil {
+		in, out := &in.AdditionalDataSecretKeys, &out.AdditionalDataSecretKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = new(Patches)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SkipPhases != nil {
+		in, out := &in.SkipPhases, &out.SkipPhases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmConfigSpec.
@@ -451,6 +482,41 @@ func (in *Partition) DeepCopy() *Partition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchFile) DeepCopyInto(out *PatchFile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchFile.
+func (in *PatchFile) DeepCopy() *PatchFile {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Patches) DeepCopyInto(out *Patches) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]PatchFile, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Patches.
+func (in *Patches) DeepCopy() *Patches {
+	if in == nil {
+		return nil
+	}
+	out := new(Patches)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretFileSource) DeepCopyInto(out *SecretFileSource) {
 	*out = *in
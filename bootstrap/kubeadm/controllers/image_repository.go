@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/version"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// kubeadmConfigMapName is the name of the ConfigMap kubeadm uploads the ClusterConfiguration to.
+	kubeadmConfigMapName = "kubeadm-config"
+
+	// clusterConfigurationKey is the key of the ClusterConfiguration in the kubeadm-config ConfigMap.
+	clusterConfigurationKey = "ClusterConfiguration"
+
+	// podInfraContainerImageArg is the kubelet flag defining the pause image.
+	podInfraContainerImageArg = "pod-infra-container-image"
+)
+
+var (
+	minVerPause341 = semver.MustParse("1.21.0")
+	minVerPause35  = semver.MustParse("1.22.0")
+)
+
+// reconcileImageRepository ensures a node joining with the config pulls the pause image from the imageRepository
+// of the config, after checking it matches the imageRepository the control plane was initialized with.
+func (r *KubeadmConfigReconciler) reconcileImageRepository(ctx context.Context, scope *Scope) error {
+	imageRepository := scope.Config.Spec.ImageRepository
+	if imageRepository == "" {
+		return nil
+	}
+
+	controlPlaneImageRepository, err := r.controlPlaneImageRepository(ctx, scope.Cluster)
+	if err != nil {
+		return err
+	}
+	if controlPlaneImageRepository != "" && controlPlaneImageRepository != imageRepository {
+		err := errors.Errorf("imageRepository %q does not match the imageRepository %q of the control plane ClusterConfiguration", imageRepository, controlPlaneImageRepository)
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.ImageRepositoryMismatchReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	nodeRegistration := &scope.Config.Spec.JoinConfiguration.NodeRegistration
+	if _, ok := nodeRegistration.KubeletExtraArgs[podInfraContainerImageArg]; ok {
+		// Respect the pause image explicitly set by the user.
+		return nil
+	}
+	image, err := pauseImage(imageRepository, scope.ConfigOwner.KubernetesVersion())
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+	if nodeRegistration.KubeletExtraArgs == nil {
+		nodeRegistration.KubeletExtraArgs = map[string]string{}
+	}
+	nodeRegistration.KubeletExtraArgs[podInfraContainerImageArg] = image
+	ctrl.LoggerFrom(ctx).Info("Altering JoinConfiguration.NodeRegistration.KubeletExtraArgs", podInfraContainerImageArg, image)
+	return nil
+}
+
+// controlPlaneImageRepository returns the imageRepository of the ClusterConfiguration uploaded by kubeadm to the
+// workload cluster, or an empty string if the ClusterConfiguration does not exist.
+func (r *KubeadmConfigReconciler) controlPlaneImageRepository(ctx context.Context, cluster *clusterv1.Cluster) (string, error) {
+	remoteClient, err := r.remoteClientGetter(ctx, KubeadmConfigControllerName, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		return "", err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: kubeadmConfigMapName}
+	if err := remoteClient.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get the %s ConfigMap", kubeadmConfigMapName)
+	}
+
+	data, ok := configMap.Data[clusterConfigurationKey]
+	if !ok {
+		return "", nil
+	}
+	clusterConfiguration := &kubeadmv1beta1.ClusterConfiguration{}
+	if err := yaml.Unmarshal([]byte(data), clusterConfiguration); err != nil {
+		return "", errors.Wrapf(err, "failed to decode the %s of the %s ConfigMap", clusterConfigurationKey, kubeadmConfigMapName)
+	}
+	return clusterConfiguration.ImageRepository, nil
+}
+
+// pauseImage returns the pause image kubeadm uses for the Kubernetes version, pulled from the image repository.
+func pauseImage(imageRepository, kubernetesVersion string) (string, error) {
+	v, err := version.ParseMajorMinorPatchTolerant(kubernetesVersion)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse Kubernetes version %q", kubernetesVersion)
+	}
+
+	tag := "3.2"
+	switch {
+	case v.GTE(minVerPause35):
+		tag = "3.5"
+	case v.GTE(minVerPause341):
+		tag = "3.4.1"
+	}
+	return fmt.Sprintf("%s/pause:%s", imageRepository, tag), nil
+}
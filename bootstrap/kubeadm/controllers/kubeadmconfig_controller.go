@@ -17,11 +17,16 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/blang/semver"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -30,10 +35,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/locking"
 	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
@@ -46,9 +53,11 @@ import (
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/cluster-api/util/version"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -74,6 +83,18 @@ type KubeadmConfigReconciler struct {
 	Client          client.Client
 	KubeadmInitLock InitLocker
 
+	// TokenManager manages the bootstrap tokens used by nodes to join; it defaults to storing
+	// them as Secrets in the workload cluster.
+	TokenManager TokenManager
+
+	// TokenSecretNamespace is the namespace of the workload cluster where the default TokenManager stores the
+	// bootstrap token Secrets; it defaults to kube-system.
+	TokenSecretNamespace string
+
+	// MaxActiveTokensPerCluster is the maximum number of active bootstrap tokens created in a workload
+	// cluster; new tokens are not created while it is reached. 0 means no limit.
+	MaxActiveTokensPerCluster int
+
 	remoteClientGetter remote.ClusterClientGetter
 }
 
@@ -92,6 +113,9 @@ func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 	if r.remoteClientGetter == nil {
 		r.remoteClientGetter = remote.NewClusterClient
 	}
+	if r.TokenManager == nil {
+		r.TokenManager = NewSecretTokenManagerForNamespace(r.TokenSecretNamespace)
+	}
 
 	b := ctrl.NewControllerManagedBy(mgr).
 		For(&bootstrapv1.KubeadmConfig{}).
@@ -140,39 +164,58 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	// Handle deletion reconciliation loop.
+	// This happens before looking up the owner, given that the owner is usually gone by the time the config is deleted.
+	if !config.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, config)
+	}
+
 	// Look up the owner of this kubeadm config if there is one
-	configOwner, err := bsutil.GetConfigOwner(ctx, r.Client, config)
-	if apierrors.IsNotFound(err) {
-		// Could not find the owner yet, this is not an error and will rereconcile when the owner gets set.
+	configOwner, ownerErr := bsutil.GetConfigOwner(ctx, r.Client, config)
+	if ownerErr != nil && !apierrors.IsNotFound(ownerErr) {
+		log.Error(ownerErr, "Failed to get owner")
+		return ctrl.Result{}, ownerErr
+	}
+
+	// Lookup the cluster the config owner is associated with, if any
+	var cluster *clusterv1.Cluster
+	var clusterErr error
+	if configOwner != nil {
+		log = log.WithValues("kind", configOwner.GetKind(), "version", configOwner.GetResourceVersion(), "name", configOwner.GetName())
+
+		cluster, clusterErr = util.GetClusterByName(ctx, r.Client, configOwner.GetNamespace(), configOwner.ClusterName())
+		if clusterErr != nil && errors.Cause(clusterErr) != util.ErrNoCluster && !apierrors.IsNotFound(clusterErr) {
+			log.Error(clusterErr, "Could not get cluster with metadata")
+			return ctrl.Result{}, clusterErr
+		}
+	}
+
+	if cluster != nil && annotations.IsPaused(cluster, config) {
+		log.Info("Reconciliation is paused for this object")
 		return ctrl.Result{}, nil
 	}
-	if err != nil {
-		log.Error(err, "Failed to get owner")
+
+	// Reconcile the bootstrap data preview before waiting for the owner and independently of the state of the
+	// bootstrap data, so the preview is available for configs without an owner and follows the changes of the spec.
+	if err := r.reconcileBootstrapDataPreview(ctx, config, configOwner); err != nil {
+		log.Error(err, "Failed to reconcile bootstrap data preview")
 		return ctrl.Result{}, err
 	}
+
+	if apierrors.IsNotFound(ownerErr) {
+		// Could not find the owner yet, this is not an error and will rereconcile when the owner gets set.
+		return ctrl.Result{}, nil
+	}
 	if configOwner == nil {
 		return ctrl.Result{}, nil
 	}
-	log = log.WithValues("kind", configOwner.GetKind(), "version", configOwner.GetResourceVersion(), "name", configOwner.GetName())
 
-	// Lookup the cluster the config owner is associated with
-	cluster, err := util.GetClusterByName(ctx, r.Client, configOwner.GetNamespace(), configOwner.ClusterName())
-	if err != nil {
-		if errors.Cause(err) == util.ErrNoCluster {
+	if clusterErr != nil {
+		if errors.Cause(clusterErr) == util.ErrNoCluster {
 			log.Info(fmt.Sprintf("%s does not belong to a cluster yet, waiting until it's part of a cluster", configOwner.GetKind()))
 			return ctrl.Result{}, nil
 		}
-
-		if apierrors.IsNotFound(err) {
-			log.Info("Cluster does not exist yet, waiting until it is created")
-			return ctrl.Result{}, nil
-		}
-		log.Error(err, "Could not get cluster with metadata")
-		return ctrl.Result{}, err
-	}
-
-	if annotations.IsPaused(cluster, config) {
-		log.Info("Reconciliation is paused for this object")
+		log.Info("Cluster does not exist yet, waiting until it is created")
 		return ctrl.Result{}, nil
 	}
 
@@ -189,6 +232,9 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	// Add finalizer first if not exist, so the bootstrap token can be cleaned up on deletion.
+	controllerutil.AddFinalizer(config, bootstrapv1.KubeadmConfigFinalizer)
+
 	// Attempt to Patch the KubeadmConfig object and status after each reconciliation if no error occurs.
 	defer func() {
 		// always update the readyCondition; the summary is represented using the "1 of x completed" notation.
@@ -230,7 +276,7 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			if !configOwner.IsInfrastructureReady() {
 				// If the BootstrapToken has been generated for a join and the infrastructure is not ready.
 				// This indicates the token in the join config has not been consumed and it may need a refresh.
-				return r.refreshBootstrapToken(ctx, config, cluster)
+				return r.refreshBootstrapToken(ctx, config, cluster, scope)
 			}
 			if configOwner.IsMachinePool() {
 				// If the BootstrapToken has been generated and infrastructure is ready but the configOwner is a MachinePool,
@@ -267,7 +313,154 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return r.joinWorker(ctx, scope)
 }
 
-func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+// reconcileDelete removes the bootstrap token generated for a KubeadmConfig from the workload cluster,
+// and then removes the finalizer.
+func (r *KubeadmConfigReconciler) reconcileDelete(ctx context.Context, config *bootstrapv1.KubeadmConfig) (_ ctrl.Result, rerr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !controllerutil.ContainsFinalizer(config, bootstrapv1.KubeadmConfigFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	cluster, err := r.clusterForDelete(ctx, config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if annotations.HasPausedAnnotation(config) || (cluster != nil && cluster.Spec.Paused) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(config, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, config); err != nil {
+			log.Error(err, "Failed to patch config")
+			if rerr == nil {
+				rerr = err
+			}
+		}
+	}()
+
+	r.deleteBootstrapToken(ctx, config, cluster)
+
+	controllerutil.RemoveFinalizer(config, bootstrapv1.KubeadmConfigFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// clusterForDelete returns the cluster of the config being deleted, or nil if the config does not have
+// a cluster label or the cluster is gone.
+func (r *KubeadmConfigReconciler) clusterForDelete(ctx context.Context, config *bootstrapv1.KubeadmConfig) (*clusterv1.Cluster, error) {
+	clusterName, ok := config.Labels[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil, nil
+	}
+	cluster, err := util.GetClusterByName(ctx, r.Client, config.Namespace, clusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// deleteBootstrapToken deletes the bootstrap token referenced by the JoinConfiguration, if any.
+// The cleanup is skipped if the cluster is gone or is being deleted, given that the token is going to be deleted with it.
+// The cleanup is best effort: if the workload cluster cannot be reached the failure is logged and the deletion goes on,
+// given that the token expires anyway and the config must not be blocked from being deleted.
+func (r *KubeadmConfigReconciler) deleteBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if config.Spec.JoinConfiguration == nil || config.Spec.JoinConfiguration.Discovery.BootstrapToken == nil ||
+		config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token == "" {
+		return
+	}
+
+	if cluster == nil {
+		log.Info("KubeadmConfig does not belong to an existing cluster, skipping bootstrap token cleanup")
+		return
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return
+	}
+
+	remoteClient, err := r.remoteClientGetter(ctx, KubeadmConfigControllerName, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		log.Error(err, "Failed to create remote cluster client, skipping bootstrap token cleanup")
+		return
+	}
+
+	log.Info("Deleting bootstrap token")
+	if err := r.tokenManager().Delete(ctx, remoteClient, config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token); err != nil {
+		log.Error(err, "Failed to delete bootstrap token, skipping bootstrap token cleanup")
+	}
+}
+
+// hasTokenCapacity returns true if a new bootstrap token can be created in the cluster the given client points to
+// without exceeding MaxActiveTokensPerCluster, and reflects this in the TokenCapacityAvailableCondition of the config.
+func (r *KubeadmConfigReconciler) hasTokenCapacity(ctx context.Context, remoteClient client.Client, config *bootstrapv1.KubeadmConfig) (bool, error) {
+	if r.MaxActiveTokensPerCluster <= 0 {
+		return true, nil
+	}
+
+	active, err := r.tokenManager().CountActive(ctx, remoteClient)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to count active bootstrap tokens")
+	}
+	if active >= r.MaxActiveTokensPerCluster {
+		conditions.MarkFalse(config, bootstrapv1.TokenCapacityAvailableCondition, bootstrapv1.TokenLimitReachedReason, clusterv1.ConditionSeverityWarning,
+			"%d of %d bootstrap tokens are active in the cluster", active, r.MaxActiveTokensPerCluster)
+		return false, nil
+	}
+	condition := conditions.TrueCondition(bootstrapv1.TokenCapacityAvailableCondition)
+	condition.Message = fmt.Sprintf("%d of %d bootstrap tokens are active in the cluster", active, r.MaxActiveTokensPerCluster)
+	conditions.Set(config, condition)
+	return true, nil
+}
+
+// setTokenValidCondition sets the TokenValidCondition of the config according to the state of the given token
+// in the cluster the given client points to.
+func (r *KubeadmConfigReconciler) setTokenValidCondition(ctx context.Context, remoteClient client.Client, config *bootstrapv1.KubeadmConfig, token string) {
+	secret, err := r.tokenManager().Get(ctx, remoteClient, token)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			conditions.MarkFalse(config, bootstrapv1.TokenValidCondition, bootstrapv1.TokenNotFoundReason, clusterv1.ConditionSeverityWarning,
+				"bootstrap token Secret not found")
+			return
+		}
+		conditions.MarkFalse(config, bootstrapv1.TokenValidCondition, bootstrapv1.TokenInvalidReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return
+	}
+
+	expiration, err := time.Parse(time.RFC3339, string(secret.Data[bootstrapapi.BootstrapTokenExpirationKey]))
+	if err != nil {
+		conditions.MarkFalse(config, bootstrapv1.TokenValidCondition, bootstrapv1.TokenInvalidReason, clusterv1.ConditionSeverityWarning,
+			"failed to parse bootstrap token expiration: %v", err)
+		return
+	}
+	if !expiration.After(time.Now().UTC()) {
+		conditions.MarkFalse(config, bootstrapv1.TokenValidCondition, bootstrapv1.TokenExpiredReason, clusterv1.ConditionSeverityWarning,
+			"bootstrap token expired at %s", expiration.Format(time.RFC3339))
+		return
+	}
+	condition := conditions.TrueCondition(bootstrapv1.TokenValidCondition)
+	condition.Message = fmt.Sprintf("bootstrap token expires at %s", expiration.Format(time.RFC3339))
+	conditions.Set(config, condition)
+}
+
+// tokenManager returns the TokenManager of the reconciler, falling back to the default one
+// if the reconciler has not been set up with a manager.
+func (r *KubeadmConfigReconciler) tokenManager() TokenManager {
+	if r.TokenManager == nil {
+		return NewSecretTokenManagerForNamespace(r.TokenSecretNamespace)
+	}
+	return r.TokenManager
+}
+
+func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster, scope *Scope) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	token := config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
 
@@ -278,11 +471,40 @@ func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, con
 	}
 
 	log.Info("Refreshing token until the infrastructure has a chance to consume it")
-	if err := refreshToken(ctx, remoteClient, token); err != nil {
+	refreshed := token
+	err = r.tokenManager().Refresh(ctx, remoteClient, token, tokenTTL(config))
+	if apierrors.IsNotFound(err) {
+		// The token has not been consumed yet, so it is recreated if its Secret has been deleted,
+		// as long as the cluster has capacity for a new token.
+		if ok, err := r.hasTokenCapacity(ctx, remoteClient, config); err != nil {
+			return ctrl.Result{}, err
+		} else if !ok {
+			log.Info("Maximum number of active bootstrap tokens reached for the cluster, waiting to recreate the token")
+			r.setTokenValidCondition(ctx, remoteClient, config, token)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		tokenID, _, _ := parseToken(token)
+		log.Info("Bootstrap token secret not found, creating a new token", "tokenID", tokenID)
+		refreshed, err = r.tokenManager().Create(ctx, remoteClient, config)
+	}
+	if err != nil {
+		r.setTokenValidCondition(ctx, remoteClient, config, token)
 		return ctrl.Result{}, errors.Wrapf(err, "failed to refresh bootstrap token")
 	}
+	if refreshed != token {
+		config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token = refreshed
+		log.Info("Altering JoinConfiguration.Discovery.BootstrapToken")
+		r.setTokenValidCondition(ctx, remoteClient, config, refreshed)
+
+		// update the bootstrap data
+		if scope.ConfigOwner.IsControlPlaneMachine() {
+			return r.joinControlplane(ctx, scope)
+		}
+		return r.joinWorker(ctx, scope)
+	}
+	r.setTokenValidCondition(ctx, remoteClient, config, token)
 	return ctrl.Result{
-		RequeueAfter: DefaultTokenTTL / 2,
+		RequeueAfter: tokenTTL(config) / 2,
 	}, nil
 }
 
@@ -295,33 +517,43 @@ func (r *KubeadmConfigReconciler) rotateMachinePoolBootstrapToken(ctx context.Co
 	}
 
 	token := config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
-	shouldRotate, err := shouldRotate(ctx, remoteClient, token)
+	shouldRotate, err := r.tokenManager().ShouldRotate(ctx, remoteClient, token, tokenTTL(config))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if shouldRotate {
+		if ok, err := r.hasTokenCapacity(ctx, remoteClient, config); err != nil {
+			return ctrl.Result{}, err
+		} else if !ok {
+			log.Info("Maximum number of active bootstrap tokens reached for the cluster, waiting to rotate the token")
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+
 		log.V(2).Info("Creating new bootstrap token")
-		token, err := createToken(ctx, remoteClient)
+		token, err := r.tokenManager().Create(ctx, remoteClient, config)
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to create new bootstrap token")
 		}
+		tokenRotatedTotal.Inc()
 
 		config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token = token
-		log.Info("Altering JoinConfiguration.Discovery.BootstrapToken", "Token", token)
+		log.Info("Altering JoinConfiguration.Discovery.BootstrapToken")
+		r.setTokenValidCondition(ctx, remoteClient, config, token)
 
 		// update the bootstrap data
 		return r.joinWorker(ctx, scope)
 	}
+	r.setTokenValidCondition(ctx, remoteClient, config, token)
 	return ctrl.Result{
-		RequeueAfter: DefaultTokenTTL / 3,
+		RequeueAfter: tokenTTL(config) / 3,
 	}, nil
 }
 
 func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Context, scope *Scope) (_ ctrl.Result, reterr error) {
 	// initialize the DataSecretAvailableCondition if missing.
 	// this is required in order to avoid the condition's LastTransitionTime to flicker in case of errors surfacing
-	// using the DataSecretGeneratedFailedReason
-	if conditions.GetReason(scope.Config, bootstrapv1.DataSecretAvailableCondition) != bootstrapv1.DataSecretGenerationFailedReason {
+	// using one of the reasons documenting a failure in generating the data secret.
+	if !dataSecretGenerationFailed(scope.Config) {
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, clusterv1.WaitingForControlPlaneAvailableReason, clusterv1.ConditionSeverityInfo, "")
 	}
 
@@ -376,6 +608,7 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 	initdata, err := kubeadmv1beta1.ConfigurationToYAMLForVersion(scope.Config.Spec.InitConfiguration, scope.ConfigOwner.KubernetesVersion())
 	if err != nil {
 		scope.Error(err, "Failed to marshal init configuration")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
@@ -394,6 +627,7 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 	clusterdata, err := kubeadmv1beta1.ConfigurationToYAMLForVersion(scope.Config.Spec.ClusterConfiguration, scope.ConfigOwner.KubernetesVersion())
 	if err != nil {
 		scope.Error(err, "Failed to marshal cluster configuration")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
@@ -406,22 +640,26 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 	)
 	if err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.ClusterCertificatesUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 	conditions.MarkTrue(scope.Config, bootstrapv1.CertificatesAvailableCondition)
 
-	verbosityFlag := ""
-	if scope.Config.Spec.Verbosity != nil {
-		verbosityFlag = fmt.Sprintf("--v %s", strconv.Itoa(int(*scope.Config.Spec.Verbosity)))
+	flags := kubeadmInitFlags(&scope.Config.Spec)
+	patchesFlag, err := kubeadmPatchesFlag(scope.Config.Spec.Patches, scope.ConfigOwner.KubernetesVersion())
+	if err != nil {
+		scope.Error(err, "Failed to generate the kubeadm patches flag")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
 	}
 
 	files, err := r.resolveFiles(ctx, scope.Config)
 	if err != nil {
-		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.FileContentUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
-	cloudInitData, err := cloudinit.NewInitControlPlane(&cloudinit.ControlPlaneInput{
+	controlPlaneInput := &cloudinit.ControlPlaneInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:     files,
 			NTP:                 scope.Config.Spec.NTP,
@@ -430,19 +668,29 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 			Users:               scope.Config.Spec.Users,
 			Mounts:              scope.Config.Spec.Mounts,
 			DiskSetup:           scope.Config.Spec.DiskSetup,
-			KubeadmVerbosity:    verbosityFlag,
+			KubeadmVerbosity:    flags,
+			KubeadmPatches:      patchesFlag,
 		},
 		InitConfiguration:    initdata,
 		ClusterConfiguration: clusterdata,
 		Certificates:         certificates,
-	})
+	}
+
+	var cloudInitData []byte
+	if scope.Config.Spec.Format == bootstrapv1.Ignition {
+		cloudInitData, err = ignition.NewInitControlPlane(controlPlaneInput)
+	} else {
+		cloudInitData, err = cloudinit.NewInitControlPlane(controlPlaneInput)
+	}
 	if err != nil {
 		scope.Error(err, "Failed to generate cloud init for bootstrap control plane")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
 	if err := r.storeBootstrapData(ctx, scope, cloudInitData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
@@ -458,10 +706,12 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 	)
 	if err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesCorruptedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.ClusterCertificatesUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 	if err := certificates.EnsureAllExist(); err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesCorruptedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.ClusterCertificatesUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 	conditions.MarkTrue(scope.Config, bootstrapv1.CertificatesAvailableCondition)
@@ -473,30 +723,41 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		return res, nil
 	}
 
+	// Ensure that the node pulls the pause image from the image repository of the config.
+	if err := r.reconcileImageRepository(ctx, scope); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	joinData, err := kubeadmv1beta1.ConfigurationToYAMLForVersion(scope.Config.Spec.JoinConfiguration, scope.ConfigOwner.KubernetesVersion())
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
 	if scope.Config.Spec.JoinConfiguration.ControlPlane != nil {
-		return ctrl.Result{}, errors.New("Machine is a Worker, but JoinConfiguration.ControlPlane is set in the KubeadmConfig object")
+		err := errors.New("Machine is a Worker, but JoinConfiguration.ControlPlane is set in the KubeadmConfig object")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
 	}
 
 	scope.Info("Creating BootstrapData for the worker node")
 
-	verbosityFlag := ""
-	if scope.Config.Spec.Verbosity != nil {
-		verbosityFlag = fmt.Sprintf("--v %s", strconv.Itoa(int(*scope.Config.Spec.Verbosity)))
+	flags := kubeadmFlags(&scope.Config.Spec)
+	patchesFlag, err := kubeadmPatchesFlag(scope.Config.Spec.Patches, scope.ConfigOwner.KubernetesVersion())
+	if err != nil {
+		scope.Error(err, "Failed to generate the kubeadm patches flag")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
 	}
 
 	files, err := r.resolveFiles(ctx, scope.Config)
 	if err != nil {
-		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.FileContentUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
-	cloudJoinData, err := cloudinit.NewNode(&cloudinit.NodeInput{
+	nodeInput := &cloudinit.NodeInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:      files,
 			NTP:                  scope.Config.Spec.NTP,
@@ -505,18 +766,28 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 			Users:                scope.Config.Spec.Users,
 			Mounts:               scope.Config.Spec.Mounts,
 			DiskSetup:            scope.Config.Spec.DiskSetup,
-			KubeadmVerbosity:     verbosityFlag,
+			KubeadmVerbosity:     flags,
+			KubeadmPatches:       patchesFlag,
 			UseExperimentalRetry: scope.Config.Spec.UseExperimentalRetryJoin,
 		},
 		JoinConfiguration: joinData,
-	})
+	}
+
+	var cloudJoinData []byte
+	if scope.Config.Spec.Format == bootstrapv1.Ignition {
+		cloudJoinData, err = ignition.NewNode(nodeInput)
+	} else {
+		cloudJoinData, err = cloudinit.NewNode(nodeInput)
+	}
 	if err != nil {
 		scope.Error(err, "Failed to create a worker join configuration")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
 	if err := r.storeBootstrapData(ctx, scope, cloudJoinData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 	return ctrl.Result{}, nil
@@ -539,10 +810,12 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 	)
 	if err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesCorruptedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.ClusterCertificatesUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 	if err := certificates.EnsureAllExist(); err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesCorruptedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.ClusterCertificatesUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 	conditions.MarkTrue(scope.Config, bootstrapv1.CertificatesAvailableCondition)
@@ -554,26 +827,35 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return res, nil
 	}
 
+	// Ensure that the node pulls the pause image from the image repository of the config.
+	if err := r.reconcileImageRepository(ctx, scope); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	joinData, err := kubeadmv1beta1.ConfigurationToYAMLForVersion(scope.Config.Spec.JoinConfiguration, scope.ConfigOwner.KubernetesVersion())
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
 	scope.Info("Creating BootstrapData for the join control plane")
 
-	verbosityFlag := ""
-	if scope.Config.Spec.Verbosity != nil {
-		verbosityFlag = fmt.Sprintf("--v %s", strconv.Itoa(int(*scope.Config.Spec.Verbosity)))
+	flags := kubeadmFlags(&scope.Config.Spec)
+	patchesFlag, err := kubeadmPatchesFlag(scope.Config.Spec.Patches, scope.ConfigOwner.KubernetesVersion())
+	if err != nil {
+		scope.Error(err, "Failed to generate the kubeadm patches flag")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
 	}
 
 	files, err := r.resolveFiles(ctx, scope.Config)
 	if err != nil {
-		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.FileContentUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
-	cloudJoinData, err := cloudinit.NewJoinControlPlane(&cloudinit.ControlPlaneJoinInput{
+	controlPlaneJoinInput := &cloudinit.ControlPlaneJoinInput{
 		JoinConfiguration: joinData,
 		Certificates:      certificates,
 		BaseUserData: cloudinit.BaseUserData{
@@ -584,17 +866,27 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 			Users:                scope.Config.Spec.Users,
 			Mounts:               scope.Config.Spec.Mounts,
 			DiskSetup:            scope.Config.Spec.DiskSetup,
-			KubeadmVerbosity:     verbosityFlag,
+			KubeadmVerbosity:     flags,
+			KubeadmPatches:       patchesFlag,
 			UseExperimentalRetry: scope.Config.Spec.UseExperimentalRetryJoin,
 		},
-	})
+	}
+
+	var cloudJoinData []byte
+	if scope.Config.Spec.Format == bootstrapv1.Ignition {
+		cloudJoinData, err = ignition.NewJoinControlPlane(controlPlaneJoinInput)
+	} else {
+		cloudJoinData, err = cloudinit.NewJoinControlPlane(controlPlaneJoinInput)
+	}
 	if err != nil {
 		scope.Error(err, "Failed to create a control plane join configuration")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
 	if err := r.storeBootstrapData(ctx, scope, cloudJoinData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
@@ -602,7 +894,7 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 }
 
 // resolveFiles maps .Spec.Files into cloudinit.Files, resolving any object references
-// along the way.
+// along the way, and adds the kubeadm patch files of .Spec.Patches.
 func (r *KubeadmConfigReconciler) resolveFiles(ctx context.Context, cfg *bootstrapv1.KubeadmConfig) ([]bootstrapv1.File, error) {
 	collected := make([]bootstrapv1.File, 0, len(cfg.Spec.Files))
 
@@ -619,7 +911,72 @@ func (r *KubeadmConfigReconciler) resolveFiles(ctx context.Context, cfg *bootstr
 		collected = append(collected, in)
 	}
 
-	return collected, nil
+	return append(collected, patchFiles(cfg.Spec.Patches)...), nil
+}
+
+// patchFiles returns the files writing the kubeadm patches to the patches directory.
+func patchFiles(patches *bootstrapv1.Patches) []bootstrapv1.File {
+	if patches == nil {
+		return nil
+	}
+
+	files := make([]bootstrapv1.File, 0, len(patches.Files))
+	for _, patchFile := range patches.Files {
+		files = append(files, bootstrapv1.File{
+			Path:        path.Join(patchesDirectory(patches), patchFile.Name),
+			Owner:       "root:root",
+			Permissions: "0640",
+			Content:     patchFile.Content,
+		})
+	}
+	return files
+}
+
+// kubeadmFlags returns the additional flags of the kubeadm init and join commands.
+func kubeadmFlags(spec *bootstrapv1.KubeadmConfigSpec) string {
+	if spec.Verbosity != nil {
+		return fmt.Sprintf("--v %s", strconv.Itoa(int(*spec.Verbosity)))
+	}
+	return ""
+}
+
+// kubeadmPatchesFlag returns the flag passing the patches directory to the kubeadm commands and phases accepting it:
+// --experimental-patches for Kubernetes v1.19 to v1.21, and --patches as of v1.22. Patches are not supported with
+// older versions.
+func kubeadmPatchesFlag(patches *bootstrapv1.Patches, kubernetesVersion string) (string, error) {
+	if patches == nil {
+		return "", nil
+	}
+
+	v, err := version.ParseMajorMinorPatchTolerant(kubernetesVersion)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the Kubernetes version %q to pass the kubeadm patches", kubernetesVersion)
+	}
+	switch {
+	case v.LT(semver.MustParse("1.19.0")):
+		return "", errors.Errorf("kubeadm patches are not supported with Kubernetes %s, they require v1.19.0 or later", kubernetesVersion)
+	case v.LT(semver.MustParse("1.22.0")):
+		return fmt.Sprintf("--experimental-patches %s", patchesDirectory(patches)), nil
+	default:
+		return fmt.Sprintf("--patches %s", patchesDirectory(patches)), nil
+	}
+}
+
+// kubeadmInitFlags returns the additional flags of the kubeadm init command.
+func kubeadmInitFlags(spec *bootstrapv1.KubeadmConfigSpec) string {
+	flags := kubeadmFlags(spec)
+	if len(spec.SkipPhases) > 0 {
+		flags = strings.TrimSpace(fmt.Sprintf("%s --skip-phases %s", flags, strings.Join(spec.SkipPhases, ",")))
+	}
+	return flags
+}
+
+// patchesDirectory returns the directory kubeadm reads the patches from.
+func patchesDirectory(patches *bootstrapv1.Patches) string {
+	if patches.Directory != "" {
+		return patches.Directory
+	}
+	return bootstrapv1.DefaultPatchesDirectory
 }
 
 // resolveSecretFileContent returns file content fetched from a referenced secret object.
@@ -767,7 +1124,14 @@ func (r *KubeadmConfigReconciler) reconcileDiscovery(ctx context.Context, cluste
 			return ctrl.Result{}, err
 		}
 
-		token, err := createToken(ctx, remoteClient)
+		if ok, err := r.hasTokenCapacity(ctx, remoteClient, config); err != nil {
+			return ctrl.Result{}, err
+		} else if !ok {
+			log.Info("Maximum number of active bootstrap tokens reached for the cluster, waiting to create a token")
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+
+		token, err := r.tokenManager().Create(ctx, remoteClient, config)
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to create new bootstrap token")
 		}
@@ -831,17 +1195,69 @@ func (r *KubeadmConfigReconciler) reconcileTopLevelObjectSettings(ctx context.Co
 	}
 }
 
+// bootstrapDataFormat returns the format of the bootstrap data generated for the given config.
+func bootstrapDataFormat(config *bootstrapv1.KubeadmConfig) bootstrapv1.Format {
+	if config.Spec.Format == "" {
+		return bootstrapv1.CloudConfig
+	}
+	return config.Spec.Format
+}
+
+// dataSecretGenerationFailed returns true if the DataSecretAvailableCondition of the config documents
+// a failure in generating the data secret.
+func dataSecretGenerationFailed(config *bootstrapv1.KubeadmConfig) bool {
+	switch conditions.GetReason(config, bootstrapv1.DataSecretAvailableCondition) {
+	case bootstrapv1.DataSecretGenerationFailedReason,
+		bootstrapv1.ClusterCertificatesUnavailableReason,
+		bootstrapv1.DataSecretRenderFailedReason,
+		bootstrapv1.FileContentUnavailableReason,
+		bootstrapv1.ImageRepositoryMismatchReason:
+		return true
+	}
+	return false
+}
+
+// bootstrapDataSecretData returns the content of the bootstrap data secret for the given config: the
+// bootstrap data and its format, plus the bootstrap data under the additional keys and gzip-compressed, if required.
+func bootstrapDataSecretData(config *bootstrapv1.KubeadmConfig, data []byte) (map[string][]byte, error) {
+	secretData := map[string][]byte{
+		bootstrapv1.DataSecretValueKey:  data,
+		bootstrapv1.DataSecretFormatKey: []byte(bootstrapDataFormat(config)),
+	}
+	for _, key := range config.Spec.AdditionalDataSecretKeys {
+		secretData[key] = data
+	}
+	if config.Spec.GzipDataSecret {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, errors.Wrap(err, "failed to gzip bootstrap data")
+		}
+		if err := w.Close(); err != nil {
+			return nil, errors.Wrap(err, "failed to gzip bootstrap data")
+		}
+		secretData[bootstrapv1.DataSecretGzipValueKey] = buf.Bytes()
+	}
+	return secretData, nil
+}
+
 // storeBootstrapData creates a new secret with the data passed in as input,
 // sets the reference in the configuration status and ready to true.
 func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope *Scope, data []byte) error {
 	log := ctrl.LoggerFrom(ctx)
 
+	secretData, err := bootstrapDataSecretData(scope.Config, data)
+	if err != nil {
+		return err
+	}
+
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      scope.Config.Name,
 			Namespace: scope.Config.Namespace,
 			Labels: map[string]string{
-				clusterv1.ClusterLabelName: scope.Cluster.Name,
+				clusterv1.ClusterLabelName:        scope.Cluster.Name,
+				bootstrapv1.DataSecretFormatLabel: string(bootstrapDataFormat(scope.Config)),
 			},
 			OwnerReferences: []metav1.OwnerReference{
 				{
@@ -853,9 +1269,7 @@ func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope
 				},
 			},
 		},
-		Data: map[string][]byte{
-			"value": data,
-		},
+		Data: secretData,
 		Type: clusterv1.ClusterSecretType,
 	}
 
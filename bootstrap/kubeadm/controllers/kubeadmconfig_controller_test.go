@@ -18,19 +18,25 @@ package controllers
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"reflect"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"strings"
 	"testing"
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
@@ -417,6 +423,461 @@ func TestKubeadmConfigReconciler_Reconcile_GenerateCloudConfigData(t *testing.T)
 	g.Expect(err).NotTo(HaveOccurred())
 }
 
+func TestKubeadmConfigReconciler_Reconcile_Patches(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	controlPlaneInitConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine, "control-plane-init-cfg")
+	controlPlaneInitConfig.Spec.Patches = &bootstrapv1.Patches{
+		Files: []bootstrapv1.PatchFile{
+			{Name: "kube-apiserver0+merge.yaml", Content: "spec:\n  containers:\n  - name: kube-apiserver\n"},
+		},
+	}
+
+	objects := []client.Object{
+		cluster,
+		controlPlaneInitMachine,
+		controlPlaneInitConfig,
+	}
+	objects = append(objects, createSecrets(t, cluster, controlPlaneInitConfig)...)
+
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+
+	k := &KubeadmConfigReconciler{
+		Client:          myclient,
+		KubeadmInitLock: &myInitLocker{},
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "control-plane-init-cfg",
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "control-plane-init-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: "default", Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(string(s.Data[bootstrapv1.DataSecretValueKey])).To(ContainSubstring("path: /etc/kubernetes/patches/kube-apiserver0+merge.yaml"))
+	g.Expect(string(s.Data[bootstrapv1.DataSecretValueKey])).To(ContainSubstring("- name: kube-apiserver"))
+	// kubeadm v1.19 only accepts the experimental patches flag.
+	g.Expect(string(s.Data[bootstrapv1.DataSecretValueKey])).To(ContainSubstring("kubeadm init --config /run/kubeadm/kubeadm.yaml --experimental-patches /etc/kubernetes/patches"))
+}
+
+func TestKubeadmPatchesFlag(t *testing.T) {
+	tests := []struct {
+		name              string
+		patches           *bootstrapv1.Patches
+		kubernetesVersion string
+		want              string
+		wantErr           bool
+	}{
+		{
+			name:              "no patches",
+			kubernetesVersion: "v1.17.1",
+			want:              "",
+		},
+		{
+			name:              "experimental patches flag before v1.22",
+			patches:           &bootstrapv1.Patches{},
+			kubernetesVersion: "v1.21.2",
+			want:              "--experimental-patches /etc/kubernetes/patches",
+		},
+		{
+			name:              "patches flag as of v1.22",
+			patches:           &bootstrapv1.Patches{Directory: "/tmp/patches"},
+			kubernetesVersion: "v1.22.0",
+			want:              "--patches /tmp/patches",
+		},
+		{
+			name:              "patches not supported before v1.19",
+			patches:           &bootstrapv1.Patches{},
+			kubernetesVersion: "v1.18.8",
+			wantErr:           true,
+		},
+		{
+			name:              "invalid version",
+			patches:           &bootstrapv1.Patches{},
+			kubernetesVersion: "",
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := kubeadmPatchesFlag(tt.patches, tt.kubernetesVersion)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestKubeadmConfigReconciler_Reconcile_SkipPhases(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	controlPlaneInitConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine, "control-plane-init-cfg")
+	controlPlaneInitConfig.Spec.SkipPhases = []string{bootstrapv1.KubeProxyAddonPhase, "addon/coredns"}
+
+	objects := []client.Object{
+		cluster,
+		controlPlaneInitMachine,
+		controlPlaneInitConfig,
+	}
+	objects = append(objects, createSecrets(t, cluster, controlPlaneInitConfig)...)
+
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+
+	k := &KubeadmConfigReconciler{
+		Client:          myclient,
+		KubeadmInitLock: &myInitLocker{},
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "control-plane-init-cfg",
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "control-plane-init-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: "default", Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(string(s.Data[bootstrapv1.DataSecretValueKey])).To(ContainSubstring("kubeadm init --config /run/kubeadm/kubeadm.yaml --skip-phases addon/kube-proxy,addon/coredns"))
+}
+
+func TestKubeadmInitFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *bootstrapv1.KubeadmConfigSpec
+		want string
+	}{
+		{
+			name: "no flags",
+			spec: &bootstrapv1.KubeadmConfigSpec{},
+			want: "",
+		},
+		{
+			name: "skip phases",
+			spec: &bootstrapv1.KubeadmConfigSpec{SkipPhases: []string{bootstrapv1.KubeProxyAddonPhase}},
+			want: "--skip-phases addon/kube-proxy",
+		},
+		{
+			name: "skip phases with verbosity",
+			spec: &bootstrapv1.KubeadmConfigSpec{
+				Verbosity:  pointer.Int32Ptr(5),
+				SkipPhases: []string{bootstrapv1.KubeProxyAddonPhase, "addon/coredns"},
+			},
+			want: "--v 5 --skip-phases addon/kube-proxy,addon/coredns",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(kubeadmInitFlags(tt.spec)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestKubeadmConfigReconciler_Reconcile_BootstrapDataPreview(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	controlPlaneInitConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine, "control-plane-init-cfg")
+	controlPlaneInitConfig.Annotations = map[string]string{bootstrapv1.PreviewBootstrapDataAnnotation: ""}
+	controlPlaneInitConfig.Spec.Users = []bootstrapv1.User{
+		{Name: "foo", Passwd: pointer.StringPtr("$6$hashedpassword")},
+	}
+
+	objects := []client.Object{
+		cluster,
+		controlPlaneInitMachine,
+		controlPlaneInitConfig,
+	}
+	objects = append(objects, createSecrets(t, cluster, controlPlaneInitConfig)...)
+
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+
+	k := &KubeadmConfigReconciler{
+		Client:          myclient,
+		KubeadmInitLock: &myInitLocker{},
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "control-plane-init-cfg",
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "control-plane-init-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+	caSecret := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: "default", Name: secret.Name(cluster.Name, secret.ClusterCA)}, caSecret)).To(Succeed())
+	// The PEM blocks are indented in the cloud-config, compare their first line of data.
+	caCert := strings.Split(string(caSecret.Data[secret.TLSCrtDataName]), "\n")[1]
+	caKey := strings.Split(string(caSecret.Data[secret.TLSKeyDataName]), "\n")[1]
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: "default", Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(string(s.Data[bootstrapv1.DataSecretValueKey])).To(ContainSubstring(caKey))
+
+	// The preview masks the certificates and the user passwords, but keeps the rest of the bootstrap data.
+	g.Expect(cfg.Status.BootstrapDataPreview).To(ContainSubstring("kubeadm init --config /run/kubeadm/kubeadm.yaml"))
+	g.Expect(cfg.Status.BootstrapDataPreview).To(ContainSubstring("path: /etc/kubernetes/pki/ca.key"))
+	g.Expect(cfg.Status.BootstrapDataPreview).To(ContainSubstring(redactedValue))
+	g.Expect(cfg.Status.BootstrapDataPreview).NotTo(ContainSubstring(caCert))
+	g.Expect(cfg.Status.BootstrapDataPreview).NotTo(ContainSubstring(caKey))
+	g.Expect(cfg.Status.BootstrapDataPreview).NotTo(ContainSubstring("$6$hashedpassword"))
+}
+
+func TestKubeadmConfigReconciler_Reconcile_BootstrapDataPreviewJoin(t *testing.T) {
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantPreview bool
+	}{
+		{
+			name:        "config with the preview annotation",
+			annotations: map[string]string{bootstrapv1.PreviewBootstrapDataAnnotation: ""},
+			wantPreview: true,
+		},
+		{
+			name:        "config without the preview annotation",
+			wantPreview: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := newWorkerMachine(cluster)
+			config := newWorkerJoinKubeadmConfig(machine)
+			config.Annotations = tt.annotations
+			config.Spec.Files = []bootstrapv1.File{
+				{
+					Path: "/etc/registry-credentials",
+					ContentFrom: &bootstrapv1.FileSource{
+						Secret: bootstrapv1.SecretFileSource{
+							Name: "registry-credentials",
+							Key:  "config.json",
+						},
+					},
+				},
+				{
+					Path:    "/etc/motd",
+					Content: "welcome",
+				},
+			}
+
+			objects := []client.Object{
+				cluster,
+				machine,
+				config,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "registry-credentials",
+						Namespace: config.Namespace,
+					},
+					Data: map[string][]byte{
+						"config.json": []byte(`{"auths":{"registry":{"auth":"c2VjcmV0"}}}`),
+					},
+				},
+			}
+			objects = append(objects, createSecrets(t, cluster, config)...)
+			myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+			k := &KubeadmConfigReconciler{
+				Client:             myclient,
+				KubeadmInitLock:    &myInitLocker{},
+				remoteClientGetter: fakeremote.NewClusterClient,
+			}
+
+			request := ctrl.Request{
+				NamespacedName: client.ObjectKey{
+					Namespace: config.GetNamespace(),
+					Name:      config.GetName(),
+				},
+			}
+			_, err := k.Reconcile(ctx, request)
+			g.Expect(err).NotTo(HaveOccurred())
+			// The preview is rendered again from the spec, which has the generated bootstrap token now.
+			_, err = k.Reconcile(ctx, request)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cfg, err := getKubeadmConfig(myclient, config.GetName())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cfg.Status.Ready).To(BeTrue())
+
+			if !tt.wantPreview {
+				g.Expect(cfg.Status.BootstrapDataPreview).To(BeEmpty())
+				return
+			}
+
+			token := cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+			g.Expect(token).NotTo(BeEmpty())
+
+			s := &corev1.Secret{}
+			g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+			g.Expect(string(s.Data[bootstrapv1.DataSecretValueKey])).To(ContainSubstring(token))
+
+			// The preview masks the bootstrap token and the content of the files sourced from secrets.
+			g.Expect(cfg.Status.BootstrapDataPreview).To(ContainSubstring("path: /etc/registry-credentials"))
+			g.Expect(cfg.Status.BootstrapDataPreview).To(ContainSubstring("welcome"))
+			g.Expect(cfg.Status.BootstrapDataPreview).To(ContainSubstring(redactedValue))
+			g.Expect(cfg.Status.BootstrapDataPreview).NotTo(ContainSubstring(token))
+			g.Expect(cfg.Status.BootstrapDataPreview).NotTo(ContainSubstring("c2VjcmV0"))
+		})
+	}
+}
+
+func TestKubeadmConfigReconciler_Reconcile_BootstrapDataPreviewLifecycle(t *testing.T) {
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	pausedCluster := newCluster("paused-cluster")
+	pausedCluster.Spec.Paused = true
+
+	tests := []struct {
+		name        string
+		machine     *clusterv1.Machine
+		config      func(machine *clusterv1.Machine) *bootstrapv1.KubeadmConfig
+		wantPreview []string
+	}{
+		{
+			name: "config without an owner",
+			config: func(_ *clusterv1.Machine) *bootstrapv1.KubeadmConfig {
+				c := newKubeadmConfig(nil, "no-owner-cfg")
+				c.Annotations = map[string]string{bootstrapv1.PreviewBootstrapDataAnnotation: ""}
+				c.Spec.JoinConfiguration = &kubeadmv1beta1.JoinConfiguration{}
+				c.Spec.Files = []bootstrapv1.File{
+					{
+						Path: "/etc/registry-credentials",
+						ContentFrom: &bootstrapv1.FileSource{
+							Secret: bootstrapv1.SecretFileSource{
+								Name: "missing-secret",
+								Key:  "config.json",
+							},
+						},
+					},
+				}
+				return c
+			},
+			wantPreview: []string{"kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml", "path: /etc/registry-credentials", redactedValue},
+		},
+		{
+			name:    "config with the bootstrap data already generated",
+			machine: newControlPlaneMachine(cluster, "control-plane-machine"),
+			config: func(machine *clusterv1.Machine) *bootstrapv1.KubeadmConfig {
+				machine.Spec.Bootstrap.DataSecretName = pointer.StringPtr("control-plane-init-cfg")
+				c := newControlPlaneInitKubeadmConfig(machine, "control-plane-init-cfg")
+				c.Annotations = map[string]string{bootstrapv1.PreviewBootstrapDataAnnotation: ""}
+				c.Status.Ready = true
+				c.Status.DataSecretName = pointer.StringPtr("control-plane-init-cfg")
+				return c
+			},
+			wantPreview: []string{"kubeadm init --config /run/kubeadm/kubeadm.yaml", "apiVersion: kubeadm.k8s.io/v1beta2"},
+		},
+		{
+			name: "config from which the preview annotation was removed",
+			config: func(_ *clusterv1.Machine) *bootstrapv1.KubeadmConfig {
+				c := newKubeadmConfig(nil, "removed-annotation-cfg")
+				c.Status.BootstrapDataPreview = "stale preview"
+				return c
+			},
+		},
+		{
+			name: "paused config",
+			config: func(_ *clusterv1.Machine) *bootstrapv1.KubeadmConfig {
+				c := newKubeadmConfig(nil, "paused-cfg")
+				c.Annotations = map[string]string{
+					bootstrapv1.PreviewBootstrapDataAnnotation: "",
+					clusterv1.PausedAnnotation:                 "",
+				}
+				c.Spec.JoinConfiguration = &kubeadmv1beta1.JoinConfiguration{}
+				return c
+			},
+		},
+		{
+			name:    "config of a paused cluster",
+			machine: newWorkerMachine(pausedCluster),
+			config: func(machine *clusterv1.Machine) *bootstrapv1.KubeadmConfig {
+				c := newWorkerJoinKubeadmConfig(machine)
+				c.Annotations = map[string]string{bootstrapv1.PreviewBootstrapDataAnnotation: ""}
+				return c
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := tt.config(tt.machine)
+			objects := []client.Object{cluster, pausedCluster, config}
+			if tt.machine != nil {
+				objects = append(objects, tt.machine)
+			}
+			myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+			k := &KubeadmConfigReconciler{
+				Client:          myclient,
+				KubeadmInitLock: &myInitLocker{},
+			}
+
+			request := ctrl.Request{
+				NamespacedName: client.ObjectKey{
+					Namespace: config.GetNamespace(),
+					Name:      config.GetName(),
+				},
+			}
+			_, err := k.Reconcile(ctx, request)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cfg, err := getKubeadmConfig(myclient, config.GetName())
+			g.Expect(err).NotTo(HaveOccurred())
+			if len(tt.wantPreview) == 0 {
+				g.Expect(cfg.Status.BootstrapDataPreview).To(BeEmpty())
+				return
+			}
+			for _, want := range tt.wantPreview {
+				g.Expect(cfg.Status.BootstrapDataPreview).To(ContainSubstring(want))
+			}
+		})
+	}
+}
+
 // If a control plane has no JoinConfiguration, then we will create a default and no error will occur
 func TestKubeadmConfigReconciler_Reconcile_ErrorIfJoiningControlPlaneHasInvalidConfiguration(t *testing.T) {
 	g := NewWithT(t)
@@ -582,8 +1043,112 @@ func TestReconcileIfJoinNodesAndControlPlaneIsReady(t *testing.T) {
 	}
 }
 
-func TestReconcileIfJoinNodePoolsAndControlPlaneIsReady(t *testing.T) {
-	_ = feature.MutableGates.Set("MachinePool=true")
+func TestReconcileIfJoinNodesWithIgnitionFormat(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	machine := newWorkerMachine(cluster)
+	config := newWorkerJoinKubeadmConfig(machine)
+	config.Spec.Format = bootstrapv1.Ignition
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: config.GetNamespace(),
+			Name:      config.GetName(),
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, config.GetName())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(s.Data["format"]).To(Equal([]byte(bootstrapv1.Ignition)))
+	g.Expect(s.Labels).To(HaveKeyWithValue(bootstrapv1.DataSecretFormatLabel, string(bootstrapv1.Ignition)))
+	ignitionConfig := map[string]interface{}{}
+	g.Expect(json.Unmarshal(s.Data["value"], &ignitionConfig)).To(Succeed())
+	g.Expect(ignitionConfig).To(HaveKey("ignition"))
+}
+
+func TestReconcileIfJoinNodesWithAdditionalDataSecretKeysAndGzip(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	machine := newWorkerMachine(cluster)
+	config := newWorkerJoinKubeadmConfig(machine)
+	config.Spec.AdditionalDataSecretKeys = []string{"userData", "custom-data"}
+	config.Spec.GzipDataSecret = true
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: config.GetNamespace(),
+			Name:      config.GetName(),
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, config.GetName())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(s.Data).To(HaveLen(5))
+	g.Expect(s.Data[bootstrapv1.DataSecretValueKey]).NotTo(BeEmpty())
+	g.Expect(s.Data[bootstrapv1.DataSecretFormatKey]).To(Equal([]byte(bootstrapv1.CloudConfig)))
+	g.Expect(s.Labels).To(HaveKeyWithValue(bootstrapv1.DataSecretFormatLabel, string(bootstrapv1.CloudConfig)))
+	g.Expect(s.Data["userData"]).To(Equal(s.Data[bootstrapv1.DataSecretValueKey]))
+	g.Expect(s.Data["custom-data"]).To(Equal(s.Data[bootstrapv1.DataSecretValueKey]))
+
+	r, err := gzip.NewReader(bytes.NewReader(s.Data[bootstrapv1.DataSecretGzipValueKey]))
+	g.Expect(err).NotTo(HaveOccurred())
+	gunzipped, err := ioutil.ReadAll(r)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gunzipped).To(Equal(s.Data[bootstrapv1.DataSecretValueKey]))
+}
+
+func TestReconcileIfJoinNodePoolsAndControlPlaneIsReady(t *testing.T) {
+	_ = feature.MutableGates.Set("MachinePool=true")
 
 	cluster := newCluster("cluster")
 	cluster.Status.InfrastructureReady = true
@@ -952,75 +1517,528 @@ func TestBootstrapTokenRotationMachinePool(t *testing.T) {
 		g.Expect(result.RequeueAfter).NotTo(BeNumerically(">=", DefaultTokenTTL))
 	}
 
-	l = &corev1.SecretList{}
-	err = myclient.List(ctx, l, client.ListOption(client.InNamespace(metav1.NamespaceSystem)))
+	l = &corev1.SecretList{}
+	err = myclient.List(ctx, l, client.ListOption(client.InNamespace(metav1.NamespaceSystem)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(l.Items)).To(Equal(1))
+
+	for i, item := range l.Items {
+		g.Expect(bytes.Equal(tokenExpires[i], item.Data[bootstrapapi.BootstrapTokenExpirationKey])).To(BeFalse())
+		tokenExpires[i] = item.Data[bootstrapapi.BootstrapTokenExpirationKey]
+	}
+
+	// ...until the infrastructure is marked "ready"
+	patchHelper, err := patch.NewHelper(workerMachinePool, myclient)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	workerMachinePool.Status.InfrastructureReady = true
+	g.Expect(patchHelper.Patch(ctx, workerMachinePool, patch.WithStatusObservedGeneration{})).To(Succeed())
+
+	<-time.After(1 * time.Second)
+
+	request = ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "workerpool-join-cfg",
+		},
+	}
+	result, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(DefaultTokenTTL / 3))
+
+	l = &corev1.SecretList{}
+	err = myclient.List(ctx, l, client.ListOption(client.InNamespace(metav1.NamespaceSystem)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(l.Items)).To(Equal(1))
+
+	for i, item := range l.Items {
+		g.Expect(bytes.Equal(tokenExpires[i], item.Data[bootstrapapi.BootstrapTokenExpirationKey])).To(BeTrue())
+	}
+
+	// before token expires, it should rotate it
+	tokenExpires[0] = []byte(time.Now().UTC().Add(DefaultTokenTTL / 5).Format(time.RFC3339))
+	l.Items[0].Data[bootstrapapi.BootstrapTokenExpirationKey] = tokenExpires[0]
+	err = myclient.Update(ctx, &l.Items[0])
+	g.Expect(err).NotTo(HaveOccurred())
+
+	request = ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "workerpool-join-cfg",
+		},
+	}
+	result, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+
+	l = &corev1.SecretList{}
+	err = myclient.List(ctx, l, client.ListOption(client.InNamespace(metav1.NamespaceSystem)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(l.Items)).To(Equal(2))
+	foundOld := false
+	foundNew := true
+	for _, item := range l.Items {
+		if bytes.Equal(item.Data[bootstrapapi.BootstrapTokenExpirationKey], tokenExpires[0]) {
+			foundOld = true
+		} else {
+			g.Expect(string(item.Data[bootstrapapi.BootstrapTokenExpirationKey])).To(Equal(time.Now().UTC().Add(DefaultTokenTTL).Format(time.RFC3339)))
+			foundNew = true
+		}
+	}
+	g.Expect(foundOld).To(BeTrue())
+	g.Expect(foundNew).To(BeTrue())
+}
+
+func TestBootstrapTokenTTLFromConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	initConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine, "control-plane-init-config")
+	workerMachine := newWorkerMachine(cluster)
+	workerJoinConfig := newWorkerJoinKubeadmConfig(workerMachine)
+	ttl := 2 * time.Hour
+	workerJoinConfig.Spec.TokenTTL = &metav1.Duration{Duration: ttl}
+	objects := []client.Object{
+		cluster,
+		workerMachine,
+		workerJoinConfig,
+	}
+
+	objects = append(objects, createSecrets(t, cluster, initConfig)...)
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "worker-join-cfg",
+		},
+	}
+	result, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Requeue).To(BeFalse())
+	g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+
+	l := &corev1.SecretList{}
+	err = myclient.List(ctx, l, client.ListOption(client.InNamespace(metav1.NamespaceSystem)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(l.Items)).To(Equal(1))
+
+	expiration, err := time.Parse(time.RFC3339, string(l.Items[0].Data[bootstrapapi.BootstrapTokenExpirationKey]))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(expiration).To(BeTemporally("~", time.Now().Add(ttl), time.Minute))
+
+	// the token should be refreshed using the TTL from the config, not the default.
+	result, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(ttl / 2))
+
+	cfg, err := getKubeadmConfig(myclient, "worker-join-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.TokenValidCondition)).To(BeTrue())
+}
+
+func TestBootstrapTokenRecreatedIfSecretDeleted(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	initConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine, "control-plane-init-config")
+	workerMachine := newWorkerMachine(cluster)
+	workerJoinConfig := newWorkerJoinKubeadmConfig(workerMachine)
+	objects := []client.Object{
+		cluster,
+		workerMachine,
+		workerJoinConfig,
+	}
+
+	objects = append(objects, createSecrets(t, cluster, initConfig)...)
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "worker-join-cfg",
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "worker-join-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	oldToken := cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+	g.Expect(oldToken).NotTo(BeEmpty())
+
+	// the token Secret is deleted before the infrastructure had a chance to consume the token...
+	l := &corev1.SecretList{}
+	g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+	g.Expect(l.Items).To(HaveLen(1))
+	g.Expect(myclient.Delete(ctx, &l.Items[0])).To(Succeed())
+
+	// ...so a new token is created, and the bootstrap data is updated to use it.
+	_, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err = getKubeadmConfig(myclient, "worker-join-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	newToken := cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+	g.Expect(newToken).NotTo(Equal(oldToken))
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.TokenValidCondition)).To(BeTrue())
+
+	l = &corev1.SecretList{}
+	g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+	g.Expect(l.Items).To(HaveLen(1))
+	newTokenID, _, err := parseToken(newToken)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(l.Items[0].Name).To(Equal(bootstraputil.BootstrapTokenSecretName(newTokenID)))
+
+	dataSecret := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: "default", Name: *cfg.Status.DataSecretName}, dataSecret)).To(Succeed())
+	g.Expect(string(dataSecret.Data["value"])).To(ContainSubstring(newToken))
+	g.Expect(string(dataSecret.Data["value"])).NotTo(ContainSubstring(oldToken))
+}
+
+func TestKubeadmConfigReconciler_SetTokenValidCondition(t *testing.T) {
+	newSecret := func(expiration string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bootstraputil.BootstrapTokenSecretName("abcdef"),
+				Namespace: metav1.NamespaceSystem,
+			},
+			Type: bootstrapapi.SecretTypeBootstrapToken,
+			Data: map[string][]byte{
+				bootstrapapi.BootstrapTokenIDKey:         []byte("abcdef"),
+				bootstrapapi.BootstrapTokenSecretKey:     []byte("0123456789abcdef"),
+				bootstrapapi.BootstrapTokenExpirationKey: []byte(expiration),
+			},
+		}
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name            string
+		objects         []client.Object
+		expectedStatus  corev1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "token is valid",
+			objects:         []client.Object{newSecret(future)},
+			expectedStatus:  corev1.ConditionTrue,
+			expectedMessage: "bootstrap token expires at " + future,
+		},
+		{
+			name:            "token is expired",
+			objects:         []client.Object{newSecret(past)},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  bootstrapv1.TokenExpiredReason,
+			expectedMessage: "bootstrap token expired at " + past,
+		},
+		{
+			name:            "token does not exist",
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  bootstrapv1.TokenNotFoundReason,
+			expectedMessage: "bootstrap token Secret not found",
+		},
+		{
+			name:           "token has an invalid expiration",
+			objects:        []client.Object{newSecret("tomorrow")},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: bootstrapv1.TokenInvalidReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			k := &KubeadmConfigReconciler{}
+			config := &bootstrapv1.KubeadmConfig{}
+			k.setTokenValidCondition(ctx, helpers.NewFakeClientWithScheme(setupScheme(), tt.objects...), config, "abcdef.0123456789abcdef")
+
+			c := conditions.Get(config, bootstrapv1.TokenValidCondition)
+			g.Expect(c).NotTo(BeNil())
+			g.Expect(c.Status).To(Equal(tt.expectedStatus))
+			g.Expect(c.Reason).To(Equal(tt.expectedReason))
+			if tt.expectedMessage != "" {
+				g.Expect(c.Message).To(Equal(tt.expectedMessage))
+			}
+		})
+	}
+}
+
+func TestKubeadmConfigReconciler_Reconcile_MaxActiveTokensPerCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	machine := newWorkerMachine(cluster)
+	config := newWorkerJoinKubeadmConfig(machine)
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+	_, err := createToken(ctx, myclient, metav1.NamespaceSystem, &bootstrapv1.KubeadmConfig{ObjectMeta: metav1.ObjectMeta{UID: "other-config"}})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	k := &KubeadmConfigReconciler{
+		Client:                    myclient,
+		KubeadmInitLock:           &myInitLocker{},
+		MaxActiveTokensPerCluster: 1,
+		remoteClientGetter:        fakeremote.NewClusterClient,
+	}
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: config.GetNamespace(),
+			Name:      config.GetName(),
+		},
+	}
+	result, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+	g.Expect(countTokenSecrets(g, myclient)).To(Equal(1))
+
+	cfg, err := getKubeadmConfig(myclient, config.GetName())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeFalse())
+	g.Expect(cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token).To(BeEmpty())
+	c := conditions.Get(cfg, bootstrapv1.TokenCapacityAvailableCondition)
+	g.Expect(c).NotTo(BeNil())
+	g.Expect(c.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(c.Reason).To(Equal(bootstrapv1.TokenLimitReachedReason))
+	g.Expect(c.Message).To(Equal("1 of 1 bootstrap tokens are active in the cluster"))
+
+	// Raising the limit allows the token to be created.
+	k.MaxActiveTokensPerCluster = 2
+	result, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+	g.Expect(countTokenSecrets(g, myclient)).To(Equal(2))
+
+	cfg, err = getKubeadmConfig(myclient, config.GetName())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.TokenCapacityAvailableCondition)).To(BeTrue())
+	g.Expect(conditions.Get(cfg, bootstrapv1.TokenCapacityAvailableCondition).Message).To(Equal("1 of 2 bootstrap tokens are active in the cluster"))
+
+	// A token whose Secret has been deleted before being consumed is only recreated if there is capacity for it.
+	token := cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+	g.Expect(deleteToken(ctx, myclient, metav1.NamespaceSystem, token)).To(Succeed())
+	k.MaxActiveTokensPerCluster = 1
+	result, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+	g.Expect(countTokenSecrets(g, myclient)).To(Equal(1))
+	cfg, err = getKubeadmConfig(myclient, config.GetName())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token).To(Equal(token))
+	g.Expect(conditions.IsFalse(cfg, bootstrapv1.TokenCapacityAvailableCondition)).To(BeTrue())
+
+	k.MaxActiveTokensPerCluster = 2
+	_, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(countTokenSecrets(g, myclient)).To(Equal(2))
+	cfg, err = getKubeadmConfig(myclient, config.GetName())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token).NotTo(Equal(token))
+}
+
+func TestKubeadmConfigReconciler_Reconcile_DeletesBootstrapToken(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), cluster)
+	token, err := createToken(ctx, myclient, metav1.NamespaceSystem, &bootstrapv1.KubeadmConfig{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	workerJoinConfig := newWorkerJoinKubeadmConfig(nil)
+	workerJoinConfig.Labels = map[string]string{clusterv1.ClusterLabelName: cluster.Name}
+	workerJoinConfig.Finalizers = []string{bootstrapv1.KubeadmConfigFinalizer}
+	workerJoinConfig.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	workerJoinConfig.Spec.JoinConfiguration.Discovery.BootstrapToken = &kubeadmv1beta1.BootstrapTokenDiscovery{
+		Token: token,
+	}
+	g.Expect(myclient.Create(ctx, workerJoinConfig)).To(Succeed())
+
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "worker-join-cfg",
+		},
+	}
+	result, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Requeue).To(BeFalse())
+	g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+
+	l := &corev1.SecretList{}
+	g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+	g.Expect(l.Items).To(BeEmpty())
+
+	cfg, err := getKubeadmConfig(myclient, "worker-join-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Finalizers).NotTo(ContainElement(bootstrapv1.KubeadmConfigFinalizer))
+}
+
+func TestKubeadmConfigReconciler_Reconcile_DeleteWithUnreachableCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	workerJoinConfig := newWorkerJoinKubeadmConfig(nil)
+	workerJoinConfig.Labels = map[string]string{clusterv1.ClusterLabelName: cluster.Name}
+	workerJoinConfig.Finalizers = []string{bootstrapv1.KubeadmConfigFinalizer}
+	workerJoinConfig.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	workerJoinConfig.Spec.JoinConfiguration.Discovery.BootstrapToken = &kubeadmv1beta1.BootstrapTokenDiscovery{
+		Token: "abcdef.0123456789abcdef",
+	}
+
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), cluster, workerJoinConfig)
+	k := &KubeadmConfigReconciler{
+		Client:          myclient,
+		KubeadmInitLock: &myInitLocker{},
+		remoteClientGetter: func(_ context.Context, _ string, _ client.Client, _ client.ObjectKey) (client.Client, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	request := ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "worker-join-cfg"}}
+
+	// The bootstrap token cleanup is best effort, the deletion must not be blocked by an unreachable cluster.
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "worker-join-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Finalizers).NotTo(ContainElement(bootstrapv1.KubeadmConfigFinalizer))
+}
+
+func TestKubeadmConfigReconciler_Reconcile_DeletePaused(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Spec.Paused = true
+	workerJoinConfig := newWorkerJoinKubeadmConfig(nil)
+	workerJoinConfig.Labels = map[string]string{clusterv1.ClusterLabelName: cluster.Name}
+	workerJoinConfig.Finalizers = []string{bootstrapv1.KubeadmConfigFinalizer}
+	workerJoinConfig.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	workerJoinConfig.Spec.JoinConfiguration.Discovery.BootstrapToken = &kubeadmv1beta1.BootstrapTokenDiscovery{
+		Token: "abcdef.0123456789abcdef",
+	}
+
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), cluster, workerJoinConfig)
+	tokenManager := &fakeTokenManager{}
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		TokenManager:       tokenManager,
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+	request := ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "worker-join-cfg"}}
+
+	// Nothing is cleaned up while the cluster is paused.
+	_, err := k.Reconcile(ctx, request)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(len(l.Items)).To(Equal(1))
+	g.Expect(tokenManager.deleted).To(BeEmpty())
 
-	for i, item := range l.Items {
-		g.Expect(bytes.Equal(tokenExpires[i], item.Data[bootstrapapi.BootstrapTokenExpirationKey])).To(BeFalse())
-		tokenExpires[i] = item.Data[bootstrapapi.BootstrapTokenExpirationKey]
-	}
+	cfg, err := getKubeadmConfig(myclient, "worker-join-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Finalizers).To(ContainElement(bootstrapv1.KubeadmConfigFinalizer))
+}
 
-	// ...until the infrastructure is marked "ready"
-	patchHelper, err := patch.NewHelper(workerMachinePool, myclient)
-	g.Expect(err).ShouldNot(HaveOccurred())
-	workerMachinePool.Status.InfrastructureReady = true
-	g.Expect(patchHelper.Patch(ctx, workerMachinePool, patch.WithStatusObservedGeneration{})).To(Succeed())
+func TestKubeadmConfigReconciler_Reconcile_UsesTokenManager(t *testing.T) {
+	g := NewWithT(t)
 
-	<-time.After(1 * time.Second)
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
 
-	request = ctrl.Request{
+	workerJoinConfig := newWorkerJoinKubeadmConfig(nil)
+	workerJoinConfig.Labels = map[string]string{clusterv1.ClusterLabelName: cluster.Name}
+	workerJoinConfig.Finalizers = []string{bootstrapv1.KubeadmConfigFinalizer}
+	workerJoinConfig.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	workerJoinConfig.Spec.JoinConfiguration.Discovery.BootstrapToken = &kubeadmv1beta1.BootstrapTokenDiscovery{
+		Token: "abcdef.0123456789abcdef",
+	}
+
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), cluster, workerJoinConfig)
+	tokenManager := &fakeTokenManager{}
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		TokenManager:       tokenManager,
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+	request := ctrl.Request{
 		NamespacedName: client.ObjectKey{
 			Namespace: "default",
-			Name:      "workerpool-join-cfg",
+			Name:      "worker-join-cfg",
 		},
 	}
-	result, err = k.Reconcile(ctx, request)
+	_, err := k.Reconcile(ctx, request)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(result.RequeueAfter).To(Equal(DefaultTokenTTL / 3))
+	g.Expect(tokenManager.deleted).To(ConsistOf("abcdef.0123456789abcdef"))
+}
 
-	l = &corev1.SecretList{}
-	err = myclient.List(ctx, l, client.ListOption(client.InNamespace(metav1.NamespaceSystem)))
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(len(l.Items)).To(Equal(1))
+// fakeTokenManager is a TokenManager recording the tokens it is asked to delete.
+type fakeTokenManager struct {
+	deleted []string
+}
 
-	for i, item := range l.Items {
-		g.Expect(bytes.Equal(tokenExpires[i], item.Data[bootstrapapi.BootstrapTokenExpirationKey])).To(BeTrue())
-	}
+func (m *fakeTokenManager) Create(_ context.Context, _ client.Client, _ *bootstrapv1.KubeadmConfig) (string, error) {
+	return "abcdef.0123456789abcdef", nil
+}
 
-	// before token expires, it should rotate it
-	tokenExpires[0] = []byte(time.Now().UTC().Add(DefaultTokenTTL / 5).Format(time.RFC3339))
-	l.Items[0].Data[bootstrapapi.BootstrapTokenExpirationKey] = tokenExpires[0]
-	err = myclient.Update(ctx, &l.Items[0])
-	g.Expect(err).NotTo(HaveOccurred())
+func (m *fakeTokenManager) Get(_ context.Context, _ client.Client, _ string) (*corev1.Secret, error) {
+	return &corev1.Secret{}, nil
+}
 
-	request = ctrl.Request{
-		NamespacedName: client.ObjectKey{
-			Namespace: "default",
-			Name:      "workerpool-join-cfg",
-		},
-	}
-	result, err = k.Reconcile(ctx, request)
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+func (m *fakeTokenManager) Refresh(_ context.Context, _ client.Client, _ string, _ time.Duration) error {
+	return nil
+}
 
-	l = &corev1.SecretList{}
-	err = myclient.List(ctx, l, client.ListOption(client.InNamespace(metav1.NamespaceSystem)))
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(len(l.Items)).To(Equal(2))
-	foundOld := false
-	foundNew := true
-	for _, item := range l.Items {
-		if bytes.Equal(item.Data[bootstrapapi.BootstrapTokenExpirationKey], tokenExpires[0]) {
-			foundOld = true
-		} else {
-			g.Expect(string(item.Data[bootstrapapi.BootstrapTokenExpirationKey])).To(Equal(time.Now().UTC().Add(DefaultTokenTTL).Format(time.RFC3339)))
-			foundNew = true
-		}
-	}
-	g.Expect(foundOld).To(BeTrue())
-	g.Expect(foundNew).To(BeTrue())
+func (m *fakeTokenManager) ShouldRotate(_ context.Context, _ client.Client, _ string, _ time.Duration) (bool, error) {
+	return false, nil
+}
+
+func (m *fakeTokenManager) Delete(_ context.Context, _ client.Client, token string) error {
+	m.deleted = append(m.deleted, token)
+	return nil
+}
+
+func (m *fakeTokenManager) CountActive(_ context.Context, _ client.Client) (int, error) {
+	return 0, nil
 }
 
 // Ensure the discovery portion of the JoinConfiguration gets generated correctly.
@@ -1559,6 +2577,293 @@ func TestKubeadmConfigReconciler_Reconcile_PatchWhenErrorOccurred(t *testing.T)
 	g.Expect(cfg.Status.ObservedGeneration).NotTo(BeNil())
 }
 
+func TestKubeadmConfigReconciler_Reconcile_MissingFileSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	machine := newWorkerMachine(cluster)
+	config := newWorkerJoinKubeadmConfig(machine)
+	config.Spec.Files = []bootstrapv1.File{
+		{
+			Path: "/etc/registry-credentials",
+			ContentFrom: &bootstrapv1.FileSource{
+				Secret: bootstrapv1.SecretFileSource{
+					Name: "registry-credentials",
+					Key:  "config.json",
+				},
+			},
+		},
+	}
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+		// A Secret with the same name in another namespace must not be used.
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "registry-credentials",
+				Namespace: "other",
+			},
+			Data: map[string][]byte{
+				"config.json": []byte("{}"),
+			},
+		},
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: config.GetNamespace(),
+			Name:      config.GetName(),
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).To(HaveOccurred())
+	assertHasFalseCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition, clusterv1.ConditionSeverityWarning, bootstrapv1.FileContentUnavailableReason)
+
+	cfg, err := getKubeadmConfig(myclient, config.GetName())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeFalse())
+	g.Expect(cfg.Status.DataSecretName).To(BeNil())
+}
+
+func TestKubeadmConfigReconciler_Reconcile_DataSecretAvailableReasons(t *testing.T) {
+	tests := []struct {
+		name           string
+		withoutCA      bool
+		mutateMachine  func(*clusterv1.Machine)
+		mutateConfig   func(*bootstrapv1.KubeadmConfig)
+		expectedReason string
+	}{
+		{
+			name:           "the cluster CA is missing",
+			withoutCA:      true,
+			expectedReason: bootstrapv1.ClusterCertificatesUnavailableReason,
+		},
+		{
+			name: "a Secret referenced by a file is missing",
+			mutateConfig: func(c *bootstrapv1.KubeadmConfig) {
+				c.Spec.Files = []bootstrapv1.File{
+					{
+						Path: "/etc/registry-credentials",
+						ContentFrom: &bootstrapv1.FileSource{
+							Secret: bootstrapv1.SecretFileSource{Name: "registry-credentials", Key: "config.json"},
+						},
+					},
+				}
+			},
+			expectedReason: bootstrapv1.FileContentUnavailableReason,
+		},
+		{
+			name: "the join configuration cannot be rendered",
+			mutateMachine: func(m *clusterv1.Machine) {
+				m.Spec.Version = pointer.StringPtr("not-a-version")
+			},
+			expectedReason: bootstrapv1.DataSecretRenderFailedReason,
+		},
+		{
+			name: "a worker has a control plane join configuration",
+			mutateConfig: func(c *bootstrapv1.KubeadmConfig) {
+				c.Spec.JoinConfiguration.ControlPlane = &kubeadmv1beta1.JoinControlPlane{}
+			},
+			expectedReason: bootstrapv1.DataSecretGenerationFailedReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := newCluster("cluster")
+			cluster.Status.InfrastructureReady = true
+			cluster.Status.ControlPlaneInitialized = true
+			cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+			machine := newWorkerMachine(cluster)
+			if tt.mutateMachine != nil {
+				tt.mutateMachine(machine)
+			}
+			config := newWorkerJoinKubeadmConfig(machine)
+			if tt.mutateConfig != nil {
+				tt.mutateConfig(config)
+			}
+
+			objects := []client.Object{
+				cluster,
+				machine,
+				config,
+			}
+			if !tt.withoutCA {
+				objects = append(objects, createSecrets(t, cluster, config)...)
+			}
+			myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+			k := &KubeadmConfigReconciler{
+				Client:             myclient,
+				KubeadmInitLock:    &myInitLocker{},
+				remoteClientGetter: fakeremote.NewClusterClient,
+			}
+
+			request := ctrl.Request{
+				NamespacedName: client.ObjectKey{
+					Namespace: config.GetNamespace(),
+					Name:      config.GetName(),
+				},
+			}
+			_, err := k.Reconcile(ctx, request)
+			g.Expect(err).To(HaveOccurred())
+			assertHasFalseCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition, clusterv1.ConditionSeverityWarning, tt.expectedReason)
+
+			cfg, err := getKubeadmConfig(myclient, config.GetName())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cfg.Status.Ready).To(BeFalse())
+			g.Expect(cfg.Status.DataSecretName).To(BeNil())
+		})
+	}
+}
+
+func TestKubeadmConfigReconciler_Reconcile_ImageRepository(t *testing.T) {
+	tests := []struct {
+		name                        string
+		controlPlane                bool
+		controlPlaneImageRepository string
+		kubeletExtraArgs            map[string]string
+		expectedPauseImage          string
+		expectedReason              string
+	}{
+		{
+			name:               "a worker pulls the pause image from the image repository",
+			expectedPauseImage: "registry.example.com/k8s/pause:3.2",
+		},
+		{
+			name:               "a control plane machine pulls the pause image from the image repository",
+			controlPlane:       true,
+			expectedPauseImage: "registry.example.com/k8s/pause:3.2",
+		},
+		{
+			name:                        "the image repository matches the one of the control plane",
+			controlPlaneImageRepository: "registry.example.com/k8s",
+			expectedPauseImage:          "registry.example.com/k8s/pause:3.2",
+		},
+		{
+			name:               "the pause image set by the user is respected",
+			kubeletExtraArgs:   map[string]string{"pod-infra-container-image": "registry.example.com/custom/pause:3.1"},
+			expectedPauseImage: "registry.example.com/custom/pause:3.1",
+		},
+		{
+			name:                        "the image repository does not match the one of the control plane",
+			controlPlaneImageRepository: "k8s.gcr.io",
+			expectedReason:              bootstrapv1.ImageRepositoryMismatchReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := newCluster("cluster")
+			cluster.Status.InfrastructureReady = true
+			cluster.Status.ControlPlaneInitialized = true
+			cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+			var config *bootstrapv1.KubeadmConfig
+			var machine *clusterv1.Machine
+			if tt.controlPlane {
+				machine = newControlPlaneMachine(cluster, "control-plane-join-machine")
+				config = newControlPlaneJoinKubeadmConfig(machine, "control-plane-join-cfg")
+			} else {
+				machine = newWorkerMachine(cluster)
+				config = newWorkerJoinKubeadmConfig(machine)
+			}
+			config.Spec.ImageRepository = "registry.example.com/k8s"
+			config.Spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs = tt.kubeletExtraArgs
+
+			objects := []client.Object{
+				cluster,
+				machine,
+				config,
+			}
+			if tt.controlPlaneImageRepository != "" {
+				objects = append(objects, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kubeadm-config",
+						Namespace: metav1.NamespaceSystem,
+					},
+					Data: map[string]string{
+						"ClusterConfiguration": fmt.Sprintf("apiVersion: kubeadm.k8s.io/v1beta2\nkind: ClusterConfiguration\nimageRepository: %s\n", tt.controlPlaneImageRepository),
+					},
+				})
+			}
+			objects = append(objects, createSecrets(t, cluster, config)...)
+			myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+			k := &KubeadmConfigReconciler{
+				Client:             myclient,
+				KubeadmInitLock:    &myInitLocker{},
+				remoteClientGetter: fakeremote.NewClusterClient,
+			}
+
+			request := ctrl.Request{
+				NamespacedName: client.ObjectKey{
+					Namespace: config.GetNamespace(),
+					Name:      config.GetName(),
+				},
+			}
+			_, err := k.Reconcile(ctx, request)
+			if tt.expectedReason != "" {
+				g.Expect(err).To(HaveOccurred())
+				assertHasFalseCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition, clusterv1.ConditionSeverityWarning, tt.expectedReason)
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cfg, err := getKubeadmConfig(myclient, config.GetName())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+			dataSecret := &corev1.Secret{}
+			g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: *cfg.Status.DataSecretName}, dataSecret)).To(Succeed())
+			g.Expect(string(dataSecret.Data["value"])).To(ContainSubstring("pod-infra-container-image: " + tt.expectedPauseImage))
+		})
+	}
+}
+
+func TestPauseImage(t *testing.T) {
+	tests := []struct {
+		kubernetesVersion string
+		expectedImage     string
+		expectErr         bool
+	}{
+		{kubernetesVersion: "v1.19.1", expectedImage: "registry.example.com/pause:3.2"},
+		{kubernetesVersion: "v1.21.0", expectedImage: "registry.example.com/pause:3.4.1"},
+		{kubernetesVersion: "v1.22.2", expectedImage: "registry.example.com/pause:3.5"},
+		{kubernetesVersion: "not-a-version", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kubernetesVersion, func(t *testing.T) {
+			g := NewWithT(t)
+
+			image, err := pauseImage("registry.example.com", tt.kubernetesVersion)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image).To(Equal(tt.expectedImage))
+		})
+	}
+}
+
 func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 	testSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1596,6 +2901,43 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 				},
 			},
 		},
+		"patch files should be written to the patches directory": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							Content:     "foo",
+							Path:        "/path",
+							Owner:       "root:root",
+							Permissions: "0600",
+						},
+					},
+					Patches: &bootstrapv1.Patches{
+						Directory: "/etc/kubeadm/patches",
+						Files: []bootstrapv1.PatchFile{
+							{
+								Name:    "etcd+json.json",
+								Content: "[]",
+							},
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.File{
+				{
+					Content:     "foo",
+					Path:        "/path",
+					Owner:       "root:root",
+					Permissions: "0600",
+				},
+				{
+					Content:     "[]",
+					Path:        "/etc/kubeadm/patches/etcd+json.json",
+					Owner:       "root:root",
+					Permissions: "0640",
+				},
+			},
+		},
 		"contentFrom should convert correctly": {
 			cfg: &bootstrapv1.KubeadmConfig{
 				Spec: bootstrapv1.KubeadmConfigSpec{
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// tokenCreatedTotal is a prometheus metric which counts the bootstrap tokens created.
+	tokenCreatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capi_bootstrap_token_created_total",
+		Help: "Total number of bootstrap tokens created",
+	})
+
+	// tokenCreationFailedTotal is a prometheus metric which counts the failed attempts to create a bootstrap token.
+	tokenCreationFailedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capi_bootstrap_token_creation_failed_total",
+		Help: "Total number of failed attempts to create a bootstrap token",
+	})
+
+	// tokenRefreshedTotal is a prometheus metric which counts the bootstrap tokens whose TTL has been extended.
+	tokenRefreshedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capi_bootstrap_token_refreshed_total",
+		Help: "Total number of bootstrap tokens refreshed",
+	})
+
+	// tokenRotatedTotal is a prometheus metric which counts the bootstrap tokens replaced by a new one.
+	tokenRotatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capi_bootstrap_token_rotated_total",
+		Help: "Total number of bootstrap tokens rotated",
+	})
+
+	// tokenActive is a prometheus metric which tracks the bootstrap token Secrets created by this controller
+	// which have not expired yet, per workload cluster. It is set each time the tokens of the cluster are
+	// swept, whether or not the BootstrapTokenGarbageCollection feature gate is enabled.
+	tokenActive = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "capi_bootstrap_token_active",
+		Help: "Number of bootstrap token Secrets created by the controller which have not expired yet in the workload cluster",
+	}, []string{"cluster"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		tokenCreatedTotal,
+		tokenCreationFailedTotal,
+		tokenRefreshedTotal,
+		tokenRotatedTotal,
+		tokenActive,
+	)
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition"
+	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
+	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+// redactedValue replaces the sensitive values in the bootstrap data preview.
+const redactedValue = "REDACTED"
+
+// reconcileBootstrapDataPreview stores the bootstrap data preview in the status of the config if it has the
+// preview annotation, and removes it otherwise. The preview is rendered from the spec of the config only, without
+// generating or reading the cluster certificates and the bootstrap tokens, so it does not depend on the owner of the
+// config, nor on the bootstrap data being generated yet.
+func (r *KubeadmConfigReconciler) reconcileBootstrapDataPreview(ctx context.Context, config *bootstrapv1.KubeadmConfig, configOwner *bsutil.ConfigOwner) error {
+	_, requested := config.Annotations[bootstrapv1.PreviewBootstrapDataAnnotation]
+	if !requested && config.Status.BootstrapDataPreview == "" {
+		return nil
+	}
+	if annotations.HasPausedAnnotation(config) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(config, r.Client)
+	if err != nil {
+		return err
+	}
+
+	config.Status.BootstrapDataPreview = ""
+	if requested {
+		kubernetesVersion := ""
+		if config.Spec.ClusterConfiguration != nil {
+			kubernetesVersion = config.Spec.ClusterConfiguration.KubernetesVersion
+		}
+		if configOwner != nil && configOwner.KubernetesVersion() != "" {
+			kubernetesVersion = configOwner.KubernetesVersion()
+		}
+		isControlPlane := configOwner != nil && configOwner.IsControlPlaneMachine()
+
+		data, err := renderBootstrapDataPreview(config, kubernetesVersion, isControlPlane)
+		if err != nil {
+			return errors.Wrap(err, "failed to render bootstrap data preview")
+		}
+		config.Status.BootstrapDataPreview = string(data)
+	}
+
+	return patchHelper.Patch(ctx, config)
+}
+
+// renderBootstrapDataPreview renders the bootstrap data of the config with the sensitive values masked: the
+// data to join the cluster if the config has a JoinConfiguration, and the data to initialize the control plane
+// otherwise. If the Kubernetes version is not known, the kubeadm configurations are rendered with the
+// kubeadm v1beta1 API and the patches are passed with the --patches flag.
+func renderBootstrapDataPreview(config *bootstrapv1.KubeadmConfig, kubernetesVersion string, isControlPlane bool) ([]byte, error) {
+	spec := config.Spec.DeepCopy()
+	tokens := bootstrapTokens(config)
+
+	patchesFlag := ""
+	if spec.Patches != nil {
+		patchesFlag = fmt.Sprintf("--patches %s", patchesDirectory(spec.Patches))
+		if kubernetesVersion != "" {
+			flag, err := kubeadmPatchesFlag(spec.Patches, kubernetesVersion)
+			if err != nil {
+				return nil, err
+			}
+			patchesFlag = flag
+		}
+	}
+
+	baseUserData := redactedBaseUserData(cloudinit.BaseUserData{
+		AdditionalFiles:      previewFiles(spec),
+		NTP:                  spec.NTP,
+		PreKubeadmCommands:   spec.PreKubeadmCommands,
+		PostKubeadmCommands:  spec.PostKubeadmCommands,
+		Users:                spec.Users,
+		Mounts:               spec.Mounts,
+		DiskSetup:            spec.DiskSetup,
+		KubeadmVerbosity:     kubeadmFlags(spec),
+		KubeadmPatches:       patchesFlag,
+		UseExperimentalRetry: spec.UseExperimentalRetryJoin,
+	})
+
+	if spec.JoinConfiguration == nil {
+		if spec.InitConfiguration == nil {
+			spec.InitConfiguration = &kubeadmv1beta1.InitConfiguration{}
+		}
+		initData, err := previewConfigurationToYAML(spec.InitConfiguration, kubernetesVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal init configuration")
+		}
+		if spec.ClusterConfiguration == nil {
+			spec.ClusterConfiguration = &kubeadmv1beta1.ClusterConfiguration{}
+		}
+		clusterData, err := previewConfigurationToYAML(spec.ClusterConfiguration, kubernetesVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal cluster configuration")
+		}
+
+		baseUserData.KubeadmVerbosity = kubeadmInitFlags(spec)
+		input := &cloudinit.ControlPlaneInput{
+			BaseUserData:         baseUserData,
+			InitConfiguration:    redactValues(initData, tokens),
+			ClusterConfiguration: redactValues(clusterData, tokens),
+			Certificates:         redactedCertificates(secret.NewCertificatesForInitialControlPlane(spec.ClusterConfiguration)),
+		}
+		if spec.Format == bootstrapv1.Ignition {
+			return ignition.NewInitControlPlane(input)
+		}
+		return cloudinit.NewInitControlPlane(input)
+	}
+
+	if isControlPlane && spec.JoinConfiguration.ControlPlane == nil {
+		spec.JoinConfiguration.ControlPlane = &kubeadmv1beta1.JoinControlPlane{}
+	}
+	joinData, err := previewConfigurationToYAML(spec.JoinConfiguration, kubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal join configuration")
+	}
+
+	if spec.JoinConfiguration.ControlPlane == nil {
+		input := &cloudinit.NodeInput{
+			BaseUserData:      baseUserData,
+			JoinConfiguration: redactValues(joinData, tokens),
+		}
+		if spec.Format == bootstrapv1.Ignition {
+			return ignition.NewNode(input)
+		}
+		return cloudinit.NewNode(input)
+	}
+
+	input := &cloudinit.ControlPlaneJoinInput{
+		BaseUserData:      baseUserData,
+		JoinConfiguration: redactValues(joinData, tokens),
+		Certificates:      redactedCertificates(secret.NewControlPlaneJoinCerts(spec.ClusterConfiguration)),
+	}
+	if spec.Format == bootstrapv1.Ignition {
+		return ignition.NewJoinControlPlane(input)
+	}
+	return cloudinit.NewJoinControlPlane(input)
+}
+
+// previewConfigurationToYAML marshals the kubeadm configuration for the Kubernetes version, or with the kubeadm
+// v1beta1 API if the version is not known.
+func previewConfigurationToYAML(obj runtime.Object, kubernetesVersion string) (string, error) {
+	if kubernetesVersion == "" {
+		data, err := kubeadmv1beta1.MarshalToYamlForCodecs(obj, kubeadmv1beta1.GroupVersion, kubeadmv1beta1.GetCodecs())
+		return string(data), err
+	}
+	return kubeadmv1beta1.ConfigurationToYAMLForVersion(obj, kubernetesVersion)
+}
+
+// previewFiles returns the files of the spec and the kubeadm patch files, masking the content of the files
+// sourced from secrets instead of reading them.
+func previewFiles(spec *bootstrapv1.KubeadmConfigSpec) []bootstrapv1.File {
+	files := make([]bootstrapv1.File, 0, len(spec.Files))
+	for _, file := range spec.Files {
+		if file.ContentFrom != nil {
+			file.ContentFrom = nil
+			file.Content = redactedValue
+		}
+		files = append(files, file)
+	}
+	return append(files, patchFiles(spec.Patches)...)
+}
+
+// redactedBaseUserData returns a copy of the input masking the user passwords.
+func redactedBaseUserData(input cloudinit.BaseUserData) cloudinit.BaseUserData {
+	users := make([]bootstrapv1.User, len(input.Users))
+	copy(users, input.Users)
+	for i := range users {
+		if users[i].Passwd != nil {
+			users[i].Passwd = pointer.StringPtr(redactedValue)
+		}
+	}
+	input.Users = users
+
+	return input
+}
+
+// redactedCertificates sets masked key pairs on the certificates, which are neither generated nor read from
+// the cluster for the preview.
+func redactedCertificates(certificates secret.Certificates) secret.Certificates {
+	for _, certificate := range certificates {
+		certificate.KeyPair = &certs.KeyPair{
+			Cert: []byte(redactedValue),
+			Key:  []byte(redactedValue),
+		}
+	}
+	return certificates
+}
+
+// bootstrapTokens returns the bootstrap tokens set in the kubeadm configurations of the config.
+func bootstrapTokens(config *bootstrapv1.KubeadmConfig) []string {
+	var tokens []string
+	if config.Spec.InitConfiguration != nil {
+		for _, bootstrapToken := range config.Spec.InitConfiguration.BootstrapTokens {
+			if bootstrapToken.Token != nil {
+				tokens = append(tokens, bootstrapToken.Token.String())
+			}
+		}
+	}
+	if config.Spec.JoinConfiguration != nil {
+		discovery := config.Spec.JoinConfiguration.Discovery
+		if discovery.BootstrapToken != nil {
+			tokens = append(tokens, discovery.BootstrapToken.Token)
+		}
+		tokens = append(tokens, discovery.TLSBootstrapToken)
+	}
+	return tokens
+}
+
+// redactValues masks the occurrences of the values in s.
+func redactValues(s string, values []string) string {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		s = strings.ReplaceAll(s, value, redactedValue)
+	}
+	return s
+}
@@ -18,93 +18,403 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
 	bootstraputil "k8s.io/cluster-bootstrap/token/util"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
 	// DefaultTokenTTL is the amount of time a bootstrap token (and therefore a KubeadmConfig) will be valid
 	DefaultTokenTTL = 15 * time.Minute
+
+	// TokenRotationJitter is the maximum fraction by which the rotation threshold of a token (half of its TTL)
+	// is moved back or forth, so that tokens created at the same time are not all rotated at the same time.
+	TokenRotationJitter = 0.1
+)
+
+const (
+	// tokenOwnerLabel is set on bootstrap token Secrets to the UID of the KubeadmConfig that created them.
+	tokenOwnerLabel = "bootstrap.cluster.x-k8s.io/owner-uid"
+
+	// defaultTokenExtraGroup is the group kubeadm grants the permissions required for nodes to join.
+	defaultTokenExtraGroup = "system:bootstrappers:kubeadm:default-node-token"
+
+	// tokenDescriptionPrefix is the prefix of the description of all the tokens generated by this controller.
+	tokenDescriptionPrefix = "token generated by cluster-api-bootstrap-provider-kubeadm"
+)
+
+var (
+	// generateBootstrapToken generates a new random bootstrap token; it is a variable so tests can replace it.
+	generateBootstrapToken = bootstraputil.GenerateBootstrapToken
+
+	// tokenCreationBackoff is the backoff used when retrying the creation of a token whose ID collides with
+	// an existing Secret.
+	tokenCreationBackoff = wait.Backoff{
+		Steps:    5,
+		Duration: 10 * time.Millisecond,
+		Factor:   2.0,
+		Jitter:   0.1,
+	}
 )
 
-// createToken attempts to create a token with the given ID.
-func createToken(ctx context.Context, c client.Client) (string, error) {
-	token, err := bootstraputil.GenerateBootstrapToken()
+// TokenManager manages the bootstrap tokens used by nodes to join a workload cluster.
+// All the methods are passed a client for the workload cluster the token belongs to.
+type TokenManager interface {
+	// Create returns a bootstrap token for the given config, reusing a token previously created for it
+	// if more than half of its TTL remains.
+	Create(ctx context.Context, c client.Client, config *bootstrapv1.KubeadmConfig) (string, error)
+
+	// Get returns the Secret backing the given token, or an error if it is invalid.
+	Get(ctx context.Context, c client.Client, token string) (*v1.Secret, error)
+
+	// Refresh extends the expiration of the given token by ttl.
+	Refresh(ctx context.Context, c client.Client, token string, ttl time.Duration) error
+
+	// ShouldRotate returns true if the given token should be replaced by a new one.
+	ShouldRotate(ctx context.Context, c client.Client, token string, ttl time.Duration) (bool, error)
+
+	// Delete deletes the given token; deleting a token that does not exist is not an error.
+	Delete(ctx context.Context, c client.Client, token string) error
+
+	// CountActive returns the number of tokens created by the manager that have not expired yet.
+	CountActive(ctx context.Context, c client.Client) (int, error)
+}
+
+// secretTokenManager is the default TokenManager, storing bootstrap tokens as Secrets in a namespace
+// of the workload cluster, kube-system by default.
+type secretTokenManager struct {
+	namespace string
+}
+
+var _ TokenManager = &secretTokenManager{}
+
+// NewSecretTokenManager returns a TokenManager storing bootstrap tokens as Secrets in the kube-system
+// namespace of the workload cluster.
+func NewSecretTokenManager() TokenManager {
+	return NewSecretTokenManagerForNamespace(metav1.NamespaceSystem)
+}
+
+// NewSecretTokenManagerForNamespace returns a TokenManager storing bootstrap tokens as Secrets in the given
+// namespace of the workload cluster; an empty namespace means kube-system.
+// Note that the API server only authenticates the bootstrap tokens stored in kube-system, so a different
+// namespace is only useful for setups where the tokens are handled by a custom authenticator.
+func NewSecretTokenManagerForNamespace(namespace string) TokenManager {
+	return &secretTokenManager{namespace: tokenSecretNamespace(namespace)}
+}
+
+func (m *secretTokenManager) Create(ctx context.Context, c client.Client, config *bootstrapv1.KubeadmConfig) (string, error) {
+	return getOrCreateToken(ctx, c, m.namespace, config)
+}
+
+func (m *secretTokenManager) Get(ctx context.Context, c client.Client, token string) (*v1.Secret, error) {
+	return getToken(ctx, c, m.namespace, token)
+}
+
+func (m *secretTokenManager) Refresh(ctx context.Context, c client.Client, token string, ttl time.Duration) error {
+	return refreshToken(ctx, c, m.namespace, token, ttl)
+}
+
+func (m *secretTokenManager) ShouldRotate(ctx context.Context, c client.Client, token string, ttl time.Duration) (bool, error) {
+	return shouldRotate(ctx, c, m.namespace, token, ttl)
+}
+
+func (m *secretTokenManager) Delete(ctx context.Context, c client.Client, token string) error {
+	return deleteToken(ctx, c, m.namespace, token)
+}
+
+func (m *secretTokenManager) CountActive(ctx context.Context, c client.Client) (int, error) {
+	return countActiveTokens(ctx, c, m.namespace)
+}
+
+// getOrCreateToken returns an existing token created for the given config if more than half of its TTL
+// remains, otherwise it creates a new one.
+func getOrCreateToken(ctx context.Context, c client.Client, namespace string, config *bootstrapv1.KubeadmConfig) (string, error) {
+	ttl := tokenTTL(config)
+	if config.UID != "" {
+		secrets := &v1.SecretList{}
+		if err := c.List(ctx, secrets, client.InNamespace(namespace), client.MatchingLabels{tokenOwnerLabel: string(config.UID)}); err != nil {
+			return "", errors.Wrap(err, "failed to list bootstrap token secrets")
+		}
+		for i := range secrets.Items {
+			secret := &secrets.Items[i]
+			if secret.Type != bootstrapapi.SecretTypeBootstrapToken || !secret.DeletionTimestamp.IsZero() {
+				continue
+			}
+			expiration, err := time.Parse(time.RFC3339, string(secret.Data[bootstrapapi.BootstrapTokenExpirationKey]))
+			if err != nil {
+				continue
+			}
+			if expiration.After(time.Now().UTC().Add(ttl / 2)) {
+				return bootstraputil.TokenFromIDAndSecret(
+					string(secret.Data[bootstrapapi.BootstrapTokenIDKey]),
+					string(secret.Data[bootstrapapi.BootstrapTokenSecretKey]),
+				), nil
+			}
+		}
+	}
+	return createToken(ctx, c, namespace, config)
+}
+
+// createToken attempts to create a token for the given config, generating a new one if the token ID
+// of the previous attempt collides with an existing Secret.
+func createToken(ctx context.Context, c client.Client, namespace string, config *bootstrapv1.KubeadmConfig) (string, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	var token string
+	err := retry.OnError(tokenCreationBackoff, apierrors.IsAlreadyExists, func() error {
+		var secretToken *v1.Secret
+		var err error
+		token, secretToken, err = newTokenSecret(namespace, config)
+		if err != nil {
+			return err
+		}
+		log = ctrl.LoggerFrom(ctx).WithValues("tokenID", string(secretToken.Data[bootstrapapi.BootstrapTokenIDKey]), "secretName", secretToken.Name)
+		if err := c.Create(ctx, secretToken); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				log.V(4).Info("Bootstrap token ID collides with an existing Secret")
+			}
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		return "", errors.Wrap(err, "unable to generate bootstrap token")
+		tokenCreationFailedTotal.Inc()
+		return "", err
 	}
+	log.V(4).Info("Created bootstrap token")
+	tokenCreatedTotal.Inc()
+	return token, nil
+}
 
-	substrs := bootstraputil.BootstrapTokenRegexp.FindStringSubmatch(token)
-	if len(substrs) != 3 {
-		return "", errors.Errorf("the bootstrap token %q was not of the form %q", token, bootstrapapi.BootstrapTokenPattern)
+// newTokenSecret generates a new bootstrap token for the given config and returns it along with its Secret.
+func newTokenSecret(namespace string, config *bootstrapv1.KubeadmConfig) (string, *v1.Secret, error) {
+	token, err := generateBootstrapToken()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "unable to generate bootstrap token")
+	}
+
+	tokenID, tokenSecret, err := parseToken(token)
+	if err != nil {
+		return "", nil, err
 	}
-	tokenID := substrs[1]
-	tokenSecret := substrs[2]
 
 	secretName := bootstraputil.BootstrapTokenSecretName(tokenID)
 	secretToken := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
-			Namespace: metav1.NamespaceSystem,
+			Namespace: namespace,
 		},
 		Type: bootstrapapi.SecretTypeBootstrapToken,
 		Data: map[string][]byte{
-			bootstrapapi.BootstrapTokenIDKey:               []byte(tokenID),
-			bootstrapapi.BootstrapTokenSecretKey:           []byte(tokenSecret),
-			bootstrapapi.BootstrapTokenExpirationKey:       []byte(time.Now().UTC().Add(DefaultTokenTTL).Format(time.RFC3339)),
-			bootstrapapi.BootstrapTokenUsageSigningKey:     []byte("true"),
-			bootstrapapi.BootstrapTokenUsageAuthentication: []byte("true"),
-			bootstrapapi.BootstrapTokenExtraGroupsKey:      []byte("system:bootstrappers:kubeadm:default-node-token"),
-			bootstrapapi.BootstrapTokenDescriptionKey:      []byte("token generated by cluster-api-bootstrap-provider-kubeadm"),
+			bootstrapapi.BootstrapTokenIDKey:          []byte(tokenID),
+			bootstrapapi.BootstrapTokenSecretKey:      []byte(tokenSecret),
+			bootstrapapi.BootstrapTokenExpirationKey:  []byte(time.Now().UTC().Add(tokenTTL(config)).Format(time.RFC3339)),
+			bootstrapapi.BootstrapTokenExtraGroupsKey: []byte(strings.Join(tokenExtraGroups(config), ",")),
+			bootstrapapi.BootstrapTokenDescriptionKey: []byte(tokenDescription(config)),
 		},
 	}
 
-	if err = c.Create(ctx, secretToken); err != nil {
-		return "", err
+	for _, usage := range tokenUsages(config) {
+		switch usage {
+		case bootstrapv1.SigningTokenUsage:
+			secretToken.Data[bootstrapapi.BootstrapTokenUsageSigningKey] = []byte("true")
+		case bootstrapv1.AuthenticationTokenUsage:
+			secretToken.Data[bootstrapapi.BootstrapTokenUsageAuthentication] = []byte("true")
+		}
 	}
-	return token, nil
+
+	if config.UID != "" {
+		secretToken.Labels = map[string]string{
+			tokenOwnerLabel: string(config.UID),
+		}
+	}
+
+	return token, secretToken, nil
 }
 
 // getToken fetches the token Secret and returns an error if it is invalid.
-func getToken(ctx context.Context, c client.Client, token string) (*v1.Secret, error) {
-	substrs := bootstraputil.BootstrapTokenRegexp.FindStringSubmatch(token)
-	if len(substrs) != 3 {
-		return nil, errors.Errorf("the bootstrap token %q was not of the form %q", token, bootstrapapi.BootstrapTokenPattern)
+func getToken(ctx context.Context, c client.Client, namespace, token string) (*v1.Secret, error) {
+	tokenID, _, err := parseToken(token)
+	if err != nil {
+		return nil, err
 	}
-	tokenID := substrs[1]
 
 	secretName := bootstraputil.BootstrapTokenSecretName(tokenID)
 	secret := &v1.Secret{}
-	if err := c.Get(ctx, client.ObjectKey{Name: secretName, Namespace: metav1.NamespaceSystem}, secret); err != nil {
+	if err := c.Get(ctx, client.ObjectKey{Name: secretName, Namespace: namespace}, secret); err != nil {
 		return secret, err
 	}
 
+	if secret.Type != bootstrapapi.SecretTypeBootstrapToken {
+		return nil, errors.Errorf("Invalid bootstrap secret %q, expected type %q but got %q, remove the token from the kubadm config to re-create", secretName, bootstrapapi.SecretTypeBootstrapToken, secret.Type)
+	}
 	if secret.Data == nil {
 		return nil, errors.Errorf("Invalid bootstrap secret %q, remove the token from the kubadm config to re-create", secretName)
 	}
+	for _, key := range []string{bootstrapapi.BootstrapTokenIDKey, bootstrapapi.BootstrapTokenSecretKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, errors.Errorf("Invalid bootstrap secret %q, missing %q, remove the token from the kubadm config to re-create", secretName, key)
+		}
+	}
 	return secret, nil
 }
 
+// countActiveTokens returns the number of bootstrap token Secrets created by this controller that have not expired yet.
+func countActiveTokens(ctx context.Context, c client.Client, namespace string) (int, error) {
+	secrets := &v1.SecretList{}
+	if err := c.List(ctx, secrets, client.InNamespace(namespace), client.HasLabels{tokenOwnerLabel}); err != nil {
+		return 0, errors.Wrap(err, "failed to list bootstrap token secrets")
+	}
+	now := time.Now().UTC()
+	active := 0
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type != bootstrapapi.SecretTypeBootstrapToken || !secret.DeletionTimestamp.IsZero() {
+			continue
+		}
+		expiration, err := time.Parse(time.RFC3339, string(secret.Data[bootstrapapi.BootstrapTokenExpirationKey]))
+		if err != nil {
+			continue
+		}
+		if expiration.After(now) {
+			active++
+		}
+	}
+	return active, nil
+}
+
+// deleteExpiredTokens deletes the bootstrap token Secrets that expired more than gracePeriod ago,
+// and returns the number of Secrets deleted.
+func deleteExpiredTokens(ctx context.Context, c client.Client, namespace string, gracePeriod time.Duration) (int, error) {
+	secrets := &v1.SecretList{}
+	if err := c.List(ctx, secrets, client.InNamespace(namespace)); err != nil {
+		return 0, errors.Wrap(err, "failed to list bootstrap token secrets")
+	}
+	cutoff := time.Now().UTC().Add(-gracePeriod)
+	deleted := 0
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type != bootstrapapi.SecretTypeBootstrapToken || !secret.DeletionTimestamp.IsZero() {
+			continue
+		}
+		expiration, err := time.Parse(time.RFC3339, string(secret.Data[bootstrapapi.BootstrapTokenExpirationKey]))
+		if err != nil || !expiration.Before(cutoff) {
+			continue
+		}
+		if err := c.Delete(ctx, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return deleted, errors.Wrapf(err, "failed to delete expired bootstrap token secret %q", secret.Name)
+		}
+		ctrl.LoggerFrom(ctx).V(4).Info("Deleted expired bootstrap token", "tokenID", string(secret.Data[bootstrapapi.BootstrapTokenIDKey]), "secretName", secret.Name,
+			"expiration", expiration.Format(time.RFC3339))
+		deleted++
+	}
+	return deleted, nil
+}
+
+// deleteOrphanedTokens deletes the bootstrap token Secrets created by this controller for KubeadmConfigs which are not
+// in the given list anymore, and returns the number of Secrets deleted. Tokens still referenced by one of the configs,
+// e.g. because the config has been moved to another management cluster and has got a new UID, are preserved.
+func deleteOrphanedTokens(ctx context.Context, c client.Client, namespace string, configs []bootstrapv1.KubeadmConfig) (int, error) {
+	owners := make(map[string]bool, len(configs))
+	referenced := map[string]bool{}
+	for i := range configs {
+		config := &configs[i]
+		owners[string(config.UID)] = true
+		if config.Spec.JoinConfiguration == nil || config.Spec.JoinConfiguration.Discovery.BootstrapToken == nil {
+			continue
+		}
+		if tokenID, _, err := parseToken(config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token); err == nil {
+			referenced[tokenID] = true
+		}
+	}
+
+	secrets := &v1.SecretList{}
+	if err := c.List(ctx, secrets, client.InNamespace(namespace), client.HasLabels{tokenOwnerLabel}); err != nil {
+		return 0, errors.Wrap(err, "failed to list bootstrap token secrets")
+	}
+	deleted := 0
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type != bootstrapapi.SecretTypeBootstrapToken || !secret.DeletionTimestamp.IsZero() {
+			continue
+		}
+		tokenID := string(secret.Data[bootstrapapi.BootstrapTokenIDKey])
+		if owners[secret.Labels[tokenOwnerLabel]] || referenced[tokenID] {
+			continue
+		}
+		if err := c.Delete(ctx, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return deleted, errors.Wrapf(err, "failed to delete orphaned bootstrap token secret %q", secret.Name)
+		}
+		ctrl.LoggerFrom(ctx).V(4).Info("Deleted orphaned bootstrap token", "tokenID", tokenID, "secretName", secret.Name,
+			"ownerUID", secret.Labels[tokenOwnerLabel])
+		deleted++
+	}
+	return deleted, nil
+}
+
+// deleteToken removes the Secret backing the given token, if it still exists.
+func deleteToken(ctx context.Context, c client.Client, namespace, token string) error {
+	tokenID, _, err := parseToken(token)
+	if err != nil {
+		return err
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstraputil.BootstrapTokenSecretName(tokenID),
+			Namespace: namespace,
+		},
+	}
+	if err := c.Delete(ctx, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete bootstrap token secret %q", secret.Name)
+	}
+	ctrl.LoggerFrom(ctx).V(4).Info("Deleted bootstrap token", "tokenID", tokenID, "secretName", secret.Name)
+	return nil
+}
+
 // refreshToken extends the TTL for an existing token.
-func refreshToken(ctx context.Context, c client.Client, token string) error {
-	secret, err := getToken(ctx, c, token)
+func refreshToken(ctx context.Context, c client.Client, namespace, token string, ttl time.Duration) error {
+	secret, err := getToken(ctx, c, namespace, token)
 	if err != nil {
 		return err
 	}
-	secret.Data[bootstrapapi.BootstrapTokenExpirationKey] = []byte(time.Now().UTC().Add(DefaultTokenTTL).Format(time.RFC3339))
+	secret.Data[bootstrapapi.BootstrapTokenExpirationKey] = []byte(time.Now().UTC().Add(ttl).Format(time.RFC3339))
 
-	return c.Update(ctx, secret)
+	if err := c.Update(ctx, secret); err != nil {
+		return err
+	}
+	ctrl.LoggerFrom(ctx).V(4).Info("Refreshed bootstrap token", "tokenID", string(secret.Data[bootstrapapi.BootstrapTokenIDKey]), "secretName", secret.Name,
+		"expiration", string(secret.Data[bootstrapapi.BootstrapTokenExpirationKey]))
+	tokenRefreshedTotal.Inc()
+	return nil
 }
 
-// shouldRotate returns true if an existing token is past half of its TTL and should to be rotated.
-func shouldRotate(ctx context.Context, c client.Client, token string) (bool, error) {
-	secret, err := getToken(ctx, c, token)
+// shouldRotate returns true if an existing token is past (about) half of its TTL and should to be rotated.
+func shouldRotate(ctx context.Context, c client.Client, namespace, token string, ttl time.Duration) (bool, error) {
+	secret, err := getToken(ctx, c, namespace, token)
 	if err != nil {
 		return false, err
 	}
@@ -113,5 +423,84 @@ func shouldRotate(ctx context.Context, c client.Client, token string) (bool, err
 	if err != nil {
 		return false, err
 	}
-	return expiration.Before(time.Now().UTC().Add(DefaultTokenTTL / 2)), nil
+	tokenID := string(secret.Data[bootstrapapi.BootstrapTokenIDKey])
+	threshold := rotationThreshold(tokenID, ttl)
+	if expiration.Before(time.Now().UTC().Add(threshold)) {
+		ctrl.LoggerFrom(ctx).V(4).Info("Bootstrap token should be rotated", "tokenID", tokenID, "secretName", secret.Name,
+			"expiration", expiration.Format(time.RFC3339))
+		return true, nil
+	}
+	return false, nil
+}
+
+// tokenSecretNamespace returns the namespace of the bootstrap token Secrets, defaulting to kube-system.
+func tokenSecretNamespace(namespace string) string {
+	if namespace == "" {
+		return metav1.NamespaceSystem
+	}
+	return namespace
+}
+
+// parseToken splits the given token into its ID and secret; the token is never included in the
+// returned error, so it does not end up in logs.
+func parseToken(token string) (string, string, error) {
+	substrs := bootstraputil.BootstrapTokenRegexp.FindStringSubmatch(token)
+	if len(substrs) != 3 {
+		return "", "", errors.Errorf("the bootstrap token was not of the form %q", bootstrapapi.BootstrapTokenPattern)
+	}
+	return substrs[1], substrs[2], nil
+}
+
+// rotationThreshold returns the remaining lifetime below which a token should be rotated.
+// This is half of the TTL, moved by up to TokenRotationJitter in both directions; the jitter is
+// derived from the token ID, so it is stable across calls for the same token.
+func rotationThreshold(tokenID string, ttl time.Duration) time.Duration {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(tokenID))
+	// Map the hash to [-1, 1].
+	f := float64(h.Sum64())/float64(math.MaxUint64)*2 - 1
+	return time.Duration(float64(ttl/2) * (1 + TokenRotationJitter*f))
+}
+
+// tokenTTL returns the TTL for bootstrap tokens generated for the given config,
+// falling back to DefaultTokenTTL if the config does not specify one.
+func tokenTTL(config *bootstrapv1.KubeadmConfig) time.Duration {
+	if config.Spec.TokenTTL != nil {
+		return config.Spec.TokenTTL.Duration
+	}
+	return DefaultTokenTTL
+}
+
+// tokenDescription returns the description for bootstrap tokens generated for the given config,
+// which refers to the Machine or MachinePool owning the config, if any, or to the config itself.
+func tokenDescription(config *bootstrapv1.KubeadmConfig) string {
+	if config.Name == "" {
+		return tokenDescriptionPrefix
+	}
+	kind, name := "KubeadmConfig", config.Name
+	for _, ref := range config.OwnerReferences {
+		if ref.Kind == "Machine" || ref.Kind == "MachinePool" {
+			kind, name = ref.Kind, ref.Name
+			break
+		}
+	}
+	return fmt.Sprintf("%s for %s %s/%s", tokenDescriptionPrefix, kind, config.Namespace, name)
+}
+
+// tokenUsages returns the usages for bootstrap tokens generated for the given config,
+// falling back to both signing and authentication if the config does not specify any.
+func tokenUsages(config *bootstrapv1.KubeadmConfig) []string {
+	if len(config.Spec.TokenUsages) > 0 {
+		return config.Spec.TokenUsages
+	}
+	return []string{bootstrapv1.SigningTokenUsage, bootstrapv1.AuthenticationTokenUsage}
+}
+
+// tokenExtraGroups returns the extra groups for bootstrap tokens generated for the given config,
+// falling back to the kubeadm default node token group if the config does not specify any.
+func tokenExtraGroups(config *bootstrapv1.KubeadmConfig) []string {
+	if len(config.Spec.TokenExtraGroups) > 0 {
+		return config.Spec.TokenExtraGroups
+	}
+	return []string{defaultTokenExtraGroup}
 }
@@ -0,0 +1,528 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/test/helpers"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGetOrCreateToken(t *testing.T) {
+	newOwner := func(uid types.UID) *bootstrapv1.KubeadmConfig {
+		return &bootstrapv1.KubeadmConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cfg",
+				Namespace: "default",
+				UID:       uid,
+			},
+		}
+	}
+
+	t.Run("reuses a token with more than half of its TTL remaining", func(t *testing.T) {
+		g := NewWithT(t)
+		c := helpers.NewFakeClientWithScheme(setupScheme())
+		owner := newOwner("owner-1")
+
+		token, err := createToken(ctx, c, metav1.NamespaceSystem, owner)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		reused, err := getOrCreateToken(ctx, c, metav1.NamespaceSystem, owner)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(reused).To(Equal(token))
+		g.Expect(countTokenSecrets(g, c)).To(Equal(1))
+	})
+
+	t.Run("creates a new token when the existing one is past half of its TTL", func(t *testing.T) {
+		g := NewWithT(t)
+		c := helpers.NewFakeClientWithScheme(setupScheme())
+		owner := newOwner("owner-1")
+
+		shortLived := owner.DeepCopy()
+		shortLived.Spec.TokenTTL = &metav1.Duration{Duration: DefaultTokenTTL / 3}
+		token, err := createToken(ctx, c, metav1.NamespaceSystem, shortLived)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		fresh, err := getOrCreateToken(ctx, c, metav1.NamespaceSystem, owner)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(fresh).NotTo(Equal(token))
+		g.Expect(countTokenSecrets(g, c)).To(Equal(2))
+	})
+
+	t.Run("does not reuse a token created for a different owner", func(t *testing.T) {
+		g := NewWithT(t)
+		c := helpers.NewFakeClientWithScheme(setupScheme())
+
+		token, err := createToken(ctx, c, metav1.NamespaceSystem, newOwner("owner-1"))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		other, err := getOrCreateToken(ctx, c, metav1.NamespaceSystem, newOwner("owner-2"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(other).NotTo(Equal(token))
+		g.Expect(countTokenSecrets(g, c)).To(Equal(2))
+	})
+
+	t.Run("always creates a new token for an owner without UID", func(t *testing.T) {
+		g := NewWithT(t)
+		c := helpers.NewFakeClientWithScheme(setupScheme())
+		owner := newOwner("")
+
+		token, err := getOrCreateToken(ctx, c, metav1.NamespaceSystem, owner)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		other, err := getOrCreateToken(ctx, c, metav1.NamespaceSystem, owner)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(other).NotTo(Equal(token))
+		g.Expect(countTokenSecrets(g, c)).To(Equal(2))
+	})
+}
+
+func TestCreateTokenRetriesOnCollision(t *testing.T) {
+	g := NewWithT(t)
+
+	colliding := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstraputil.BootstrapTokenSecretName("abcdef"),
+			Namespace: metav1.NamespaceSystem,
+		},
+		Type: bootstrapapi.SecretTypeBootstrapToken,
+	}
+	c := helpers.NewFakeClientWithScheme(setupScheme(), colliding)
+
+	tokens := []string{"abcdef.0123456789abcdef", "abcdef.fedcba9876543210", "ghijkl.0123456789abcdef"}
+	generateBootstrapToken = func() (string, error) {
+		token := tokens[0]
+		tokens = tokens[1:]
+		return token, nil
+	}
+	defer func() { generateBootstrapToken = bootstraputil.GenerateBootstrapToken }()
+
+	token, err := createToken(ctx, c, metav1.NamespaceSystem, &bootstrapv1.KubeadmConfig{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(token).To(Equal("ghijkl.0123456789abcdef"))
+	g.Expect(tokens).To(BeEmpty())
+	g.Expect(countTokenSecrets(g, c)).To(Equal(2))
+
+	// Collisions are only retried a bounded number of times.
+	generateBootstrapToken = func() (string, error) {
+		return "abcdef.0123456789abcdef", nil
+	}
+	_, err = createToken(ctx, c, metav1.NamespaceSystem, &bootstrapv1.KubeadmConfig{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+}
+
+func TestCreateTokenExtraGroups(t *testing.T) {
+	g := NewWithT(t)
+	c := helpers.NewFakeClientWithScheme(setupScheme())
+
+	token, err := createToken(ctx, c, metav1.NamespaceSystem, &bootstrapv1.KubeadmConfig{})
+	g.Expect(err).NotTo(HaveOccurred())
+	secret, err := getToken(ctx, c, metav1.NamespaceSystem, token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(secret.Data[bootstrapapi.BootstrapTokenExtraGroupsKey])).To(Equal("system:bootstrappers:kubeadm:default-node-token"))
+
+	config := &bootstrapv1.KubeadmConfig{
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			TokenExtraGroups: []string{"system:bootstrappers:kubeadm:default-node-token", "system:bootstrappers:custom"},
+		},
+	}
+	token, err = createToken(ctx, c, metav1.NamespaceSystem, config)
+	g.Expect(err).NotTo(HaveOccurred())
+	secret, err = getToken(ctx, c, metav1.NamespaceSystem, token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(secret.Data[bootstrapapi.BootstrapTokenExtraGroupsKey])).To(Equal("system:bootstrappers:kubeadm:default-node-token,system:bootstrappers:custom"))
+}
+
+func TestCreateTokenUsages(t *testing.T) {
+	g := NewWithT(t)
+	c := helpers.NewFakeClientWithScheme(setupScheme())
+
+	token, err := createToken(ctx, c, metav1.NamespaceSystem, &bootstrapv1.KubeadmConfig{})
+	g.Expect(err).NotTo(HaveOccurred())
+	secret, err := getToken(ctx, c, metav1.NamespaceSystem, token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret.Data).To(HaveKeyWithValue(bootstrapapi.BootstrapTokenUsageSigningKey, []byte("true")))
+	g.Expect(secret.Data).To(HaveKeyWithValue(bootstrapapi.BootstrapTokenUsageAuthentication, []byte("true")))
+
+	config := &bootstrapv1.KubeadmConfig{
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			TokenUsages: []string{bootstrapv1.AuthenticationTokenUsage},
+		},
+	}
+	token, err = createToken(ctx, c, metav1.NamespaceSystem, config)
+	g.Expect(err).NotTo(HaveOccurred())
+	secret, err = getToken(ctx, c, metav1.NamespaceSystem, token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret.Data).NotTo(HaveKey(bootstrapapi.BootstrapTokenUsageSigningKey))
+	g.Expect(secret.Data).To(HaveKeyWithValue(bootstrapapi.BootstrapTokenUsageAuthentication, []byte("true")))
+}
+
+func TestCreateTokenDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *bootstrapv1.KubeadmConfig
+		expected string
+	}{
+		{
+			name:     "config without name",
+			config:   &bootstrapv1.KubeadmConfig{},
+			expected: "token generated by cluster-api-bootstrap-provider-kubeadm",
+		},
+		{
+			name: "config without owner",
+			config: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cfg"},
+			},
+			expected: "token generated by cluster-api-bootstrap-provider-kubeadm for KubeadmConfig default/cfg",
+		},
+		{
+			name: "config owned by a Machine",
+			config: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "cfg",
+					OwnerReferences: []metav1.OwnerReference{
+						{Kind: "Machine", Name: "machine"},
+					},
+				},
+			},
+			expected: "token generated by cluster-api-bootstrap-provider-kubeadm for Machine default/machine",
+		},
+		{
+			name: "config owned by a MachinePool",
+			config: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "cfg",
+					OwnerReferences: []metav1.OwnerReference{
+						{Kind: "MachinePool", Name: "pool"},
+					},
+				},
+			},
+			expected: "token generated by cluster-api-bootstrap-provider-kubeadm for MachinePool default/pool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := helpers.NewFakeClientWithScheme(setupScheme())
+
+			token, err := createToken(ctx, c, metav1.NamespaceSystem, tt.config)
+			g.Expect(err).NotTo(HaveOccurred())
+			secret, err := getToken(ctx, c, metav1.NamespaceSystem, token)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(secret.Data[bootstrapapi.BootstrapTokenDescriptionKey])).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestGetToken(t *testing.T) {
+	const token = "abcdef.0123456789abcdef"
+	newSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bootstraputil.BootstrapTokenSecretName("abcdef"),
+				Namespace: metav1.NamespaceSystem,
+			},
+			Type: bootstrapapi.SecretTypeBootstrapToken,
+			Data: map[string][]byte{
+				bootstrapapi.BootstrapTokenIDKey:     []byte("abcdef"),
+				bootstrapapi.BootstrapTokenSecretKey: []byte("0123456789abcdef"),
+			},
+		}
+	}
+
+	wrongType := newSecret()
+	wrongType.Type = corev1.SecretTypeOpaque
+
+	noData := newSecret()
+	noData.Data = nil
+
+	missingID := newSecret()
+	delete(missingID.Data, bootstrapapi.BootstrapTokenIDKey)
+
+	missingSecret := newSecret()
+	missingSecret.Data[bootstrapapi.BootstrapTokenSecretKey] = []byte{}
+
+	tests := []struct {
+		name      string
+		secret    *corev1.Secret
+		expectErr bool
+	}{
+		{
+			name:   "valid token secret",
+			secret: newSecret(),
+		},
+		{
+			name:      "secret with the wrong type",
+			secret:    wrongType,
+			expectErr: true,
+		},
+		{
+			name:      "secret without data",
+			secret:    noData,
+			expectErr: true,
+		},
+		{
+			name:      "secret without token ID",
+			secret:    missingID,
+			expectErr: true,
+		},
+		{
+			name:      "secret with an empty token secret",
+			secret:    missingSecret,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := helpers.NewFakeClientWithScheme(setupScheme(), tt.secret)
+
+			_, err := getToken(ctx, c, metav1.NamespaceSystem, token)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func countTokenSecrets(g *WithT, c client.Client) int {
+	l := &corev1.SecretList{}
+	g.Expect(c.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+	n := 0
+	for _, s := range l.Items {
+		if s.Type == bootstrapapi.SecretTypeBootstrapToken {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCountActiveTokens(t *testing.T) {
+	g := NewWithT(t)
+
+	newSecret := func(name string, labels map[string]string, expiration time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bootstraputil.BootstrapTokenSecretName(name),
+				Namespace: metav1.NamespaceSystem,
+				Labels:    labels,
+			},
+			Type: bootstrapapi.SecretTypeBootstrapToken,
+			Data: map[string][]byte{
+				bootstrapapi.BootstrapTokenExpirationKey: []byte(expiration.UTC().Format(time.RFC3339)),
+			},
+		}
+	}
+	owned := map[string]string{tokenOwnerLabel: "uid"}
+	c := helpers.NewFakeClientWithScheme(setupScheme(),
+		newSecret("aaaaaa", owned, time.Now().Add(time.Hour)),
+		newSecret("bbbbbb", owned, time.Now().Add(time.Hour)),
+		newSecret("cccccc", owned, time.Now().Add(-time.Hour)),
+		newSecret("dddddd", nil, time.Now().Add(time.Hour)),
+	)
+
+	active, err := countActiveTokens(ctx, c, metav1.NamespaceSystem)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(active).To(Equal(2))
+}
+
+func TestSecretTokenManagerNamespace(t *testing.T) {
+	g := NewWithT(t)
+	c := helpers.NewFakeClientWithScheme(setupScheme())
+
+	m := NewSecretTokenManagerForNamespace("bootstrap-tokens")
+	config := &bootstrapv1.KubeadmConfig{ObjectMeta: metav1.ObjectMeta{UID: "owner"}}
+
+	token, err := m.Create(ctx, c, config)
+	g.Expect(err).NotTo(HaveOccurred())
+	tokenID, _, err := parseToken(token)
+	g.Expect(err).NotTo(HaveOccurred())
+	secretName := bootstraputil.BootstrapTokenSecretName(tokenID)
+
+	// Writes go to the configured namespace only.
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "bootstrap-tokens", Name: secretName}, &corev1.Secret{})).To(Succeed())
+	err = c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: secretName}, &corev1.Secret{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// Reads use the same namespace as writes.
+	secret, err := m.Get(ctx, c, token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret.Namespace).To(Equal("bootstrap-tokens"))
+	reused, err := m.Create(ctx, c, config)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reused).To(Equal(token))
+	g.Expect(m.Refresh(ctx, c, token, time.Minute)).To(Succeed())
+	rotate, err := m.ShouldRotate(ctx, c, token, time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotate).To(BeTrue())
+	active, err := m.CountActive(ctx, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(active).To(Equal(1))
+
+	// A manager using the default namespace does not see the token.
+	_, err = NewSecretTokenManager().Get(ctx, c, token)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	g.Expect(m.Delete(ctx, c, token)).To(Succeed())
+	err = c.Get(ctx, client.ObjectKey{Namespace: "bootstrap-tokens", Name: secretName}, &corev1.Secret{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	g.Expect(NewSecretTokenManagerForNamespace("")).To(Equal(NewSecretTokenManager()))
+}
+
+func TestTokenOperationsDoNotLogSecret(t *testing.T) {
+	g := NewWithT(t)
+	c := helpers.NewFakeClientWithScheme(setupScheme())
+
+	sink := &recordingLogSink{}
+	logCtx := ctrl.LoggerInto(ctx, &recordingLogger{sink: sink})
+
+	token, err := createToken(logCtx, c, metav1.NamespaceSystem, &bootstrapv1.KubeadmConfig{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(refreshToken(logCtx, c, metav1.NamespaceSystem, token, time.Minute)).To(Succeed())
+	rotate, err := shouldRotate(logCtx, c, metav1.NamespaceSystem, token, time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotate).To(BeTrue())
+	g.Expect(deleteToken(logCtx, c, metav1.NamespaceSystem, token)).To(Succeed())
+	g.Expect(deleteToken(logCtx, c, metav1.NamespaceSystem, "not-a-token")).NotTo(Succeed())
+
+	tokenID, tokenSecret, err := parseToken(token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sink.lines).To(HaveLen(4))
+	for _, line := range sink.lines {
+		g.Expect(line).To(ContainSubstring(tokenID))
+		g.Expect(line).To(ContainSubstring(bootstraputil.BootstrapTokenSecretName(tokenID)))
+		g.Expect(line).NotTo(ContainSubstring(tokenSecret))
+	}
+}
+
+// recordingLogSink collects the lines logged by a recordingLogger and its children.
+type recordingLogSink struct {
+	lines []string
+}
+
+// recordingLogger is a logr.Logger enabled at all verbosity levels, recording each message along with its key-value pairs.
+type recordingLogger struct {
+	sink   *recordingLogSink
+	values []interface{}
+}
+
+func (l *recordingLogger) Enabled() bool { return true }
+
+func (l *recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.sink.lines = append(l.sink.lines, fmt.Sprint(msg, l.values, keysAndValues))
+}
+
+func (l *recordingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.sink.lines = append(l.sink.lines, fmt.Sprint(err, msg, l.values, keysAndValues))
+}
+
+func (l *recordingLogger) V(_ int) logr.Logger { return l }
+
+func (l *recordingLogger) WithName(_ string) logr.Logger { return l }
+
+func (l *recordingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &recordingLogger{sink: l.sink, values: append(append([]interface{}{}, l.values...), keysAndValues...)}
+}
+
+func TestShouldRotate(t *testing.T) {
+	g := NewWithT(t)
+	ttl := 10 * time.Minute
+
+	first := rotationThreshold("abcdef", ttl)
+	second := rotationThreshold("ghijkl", ttl)
+
+	// the threshold is deterministic for a given token ID...
+	g.Expect(rotationThreshold("abcdef", ttl)).To(Equal(first))
+	// ...it differs across token IDs...
+	g.Expect(first).NotTo(Equal(second))
+	// ...and it stays within the jitter of half the TTL.
+	for _, threshold := range []time.Duration{first, second} {
+		g.Expect(threshold).To(BeNumerically(">=", time.Duration(float64(ttl/2)*(1-TokenRotationJitter))))
+		g.Expect(threshold).To(BeNumerically("<=", time.Duration(float64(ttl/2)*(1+TokenRotationJitter))))
+	}
+
+	// a token expiring between the two thresholds is rotated for one token ID but not for the other.
+	low, high := first, second
+	lowID, highID := "abcdef", "ghijkl"
+	if low > high {
+		low, high = high, low
+		lowID, highID = highID, lowID
+	}
+	expiration := time.Now().UTC().Add(low + (high-low)/2).Format(time.RFC3339)
+
+	c := helpers.NewFakeClientWithScheme(setupScheme())
+	for _, id := range []string{lowID, highID} {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bootstraputil.BootstrapTokenSecretName(id),
+				Namespace: metav1.NamespaceSystem,
+			},
+			Type: bootstrapapi.SecretTypeBootstrapToken,
+			Data: map[string][]byte{
+				bootstrapapi.BootstrapTokenIDKey:         []byte(id),
+				bootstrapapi.BootstrapTokenSecretKey:     []byte("0123456789abcdef"),
+				bootstrapapi.BootstrapTokenExpirationKey: []byte(expiration),
+			},
+		}
+		g.Expect(c.Create(ctx, secret)).To(Succeed())
+	}
+
+	rotate, err := shouldRotate(ctx, c, metav1.NamespaceSystem, bootstraputil.TokenFromIDAndSecret(lowID, "0123456789abcdef"), ttl)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotate).To(BeFalse())
+
+	rotate, err = shouldRotate(ctx, c, metav1.NamespaceSystem, bootstraputil.TokenFromIDAndSecret(highID, "0123456789abcdef"), ttl)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotate).To(BeTrue())
+}
+
+func TestTokenMetrics(t *testing.T) {
+	g := NewWithT(t)
+	c := helpers.NewFakeClientWithScheme(setupScheme())
+
+	created := testutil.ToFloat64(tokenCreatedTotal)
+	refreshed := testutil.ToFloat64(tokenRefreshedTotal)
+
+	token, err := createToken(ctx, c, metav1.NamespaceSystem, &bootstrapv1.KubeadmConfig{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(testutil.ToFloat64(tokenCreatedTotal)).To(Equal(created + 1))
+
+	g.Expect(refreshToken(ctx, c, metav1.NamespaceSystem, token, DefaultTokenTTL)).To(Succeed())
+	g.Expect(testutil.ToFloat64(tokenRefreshedTotal)).To(Equal(refreshed + 1))
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	// TokenGarbageCollectorControllerName defines the controller used when creating clients.
+	TokenGarbageCollectorControllerName = "bootstrap-token-gc-controller"
+
+	// DefaultTokenGarbageCollectionInterval is the default interval between two sweeps of the same cluster.
+	DefaultTokenGarbageCollectionInterval = 10 * time.Minute
+)
+
+// TokenGarbageCollectorReconciler periodically sweeps the bootstrap token Secrets of workload clusters, deleting
+// the tokens whose KubeadmConfig has been deleted without the token being cleaned up and keeping track of the
+// active tokens. When enabled, it also deletes the expired tokens, for clusters where the kubeadm token cleaner
+// is not running.
+type TokenGarbageCollectorReconciler struct {
+	Client client.Client
+
+	// DeleteExpiredTokens enables the deletion of the expired bootstrap token Secrets; orphaned bootstrap token
+	// Secrets are deleted regardless.
+	DeleteExpiredTokens bool
+
+	// GracePeriod is how long after their expiration bootstrap token Secrets are deleted.
+	GracePeriod time.Duration
+
+	// TokenSecretNamespace is the namespace of the workload cluster where the bootstrap token Secrets are stored;
+	// it defaults to kube-system.
+	TokenSecretNamespace string
+
+	// Interval is the time between two sweeps of the same cluster; it defaults to DefaultTokenGarbageCollectionInterval.
+	Interval time.Duration
+
+	remoteClientGetter remote.ClusterClientGetter
+}
+
+// SetupWithManager sets up the reconciler with the Manager.
+func (r *TokenGarbageCollectorReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	if r.remoteClientGetter == nil {
+		r.remoteClientGetter = remote.NewClusterClient
+	}
+	if r.Interval == 0 {
+		r.Interval = DefaultTokenGarbageCollectionInterval
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("bootstraptokengc").
+		For(&clusterv1.Cluster{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(ctx))).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+// Reconcile deletes the orphaned bootstrap tokens of a workload cluster, as well as the expired ones if enabled,
+// and counts the active ones.
+func (r *TokenGarbageCollectorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	cluster := &clusterv1.Cluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			tokenActive.DeleteLabelValues(req.String())
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if annotations.IsPaused(cluster, cluster) {
+		log.V(4).Info("Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	// Tokens can only be swept once the control plane is up, and there is no point in doing so
+	// while the cluster is being deleted.
+	if !cluster.DeletionTimestamp.IsZero() || !cluster.Status.ControlPlaneInitialized {
+		tokenActive.DeleteLabelValues(req.String())
+		return ctrl.Result{}, nil
+	}
+
+	remoteClient, err := r.remoteClientGetter(ctx, TokenGarbageCollectorControllerName, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error creating remote cluster client")
+	}
+
+	if r.DeleteExpiredTokens {
+		deleted, err := deleteExpiredTokens(ctx, remoteClient, tokenSecretNamespace(r.TokenSecretNamespace), r.GracePeriod)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if deleted > 0 {
+			log.Info("Deleted expired bootstrap tokens", "count", deleted)
+		}
+	}
+
+	// The token Secrets live in the workload cluster, while their owners live in the management cluster, so
+	// instead of owner references the Secrets are labeled with the UID of their owner.
+	configs := &bootstrapv1.KubeadmConfigList{}
+	if err := r.Client.List(ctx, configs, client.InNamespace(cluster.Namespace)); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list KubeadmConfigs")
+	}
+	orphaned, err := deleteOrphanedTokens(ctx, remoteClient, tokenSecretNamespace(r.TokenSecretNamespace), configs.Items)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if orphaned > 0 {
+		log.Info("Deleted orphaned bootstrap tokens", "count", orphaned)
+	}
+
+	active, err := countActiveTokens(ctx, remoteClient, tokenSecretNamespace(r.TokenSecretNamespace))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	tokenActive.WithLabelValues(req.String()).Set(float64(active))
+	return ctrl.Result{RequeueAfter: r.Interval}, nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
+	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
+	fakeremote "sigs.k8s.io/cluster-api/controllers/remote/fake"
+	"sigs.k8s.io/cluster-api/test/helpers"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTokenGarbageCollectorReconciler_Reconcile(t *testing.T) {
+	newTokenSecret := func(tokenID string, expiration time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bootstraputil.BootstrapTokenSecretName(tokenID),
+				Namespace: metav1.NamespaceSystem,
+			},
+			Type: bootstrapapi.SecretTypeBootstrapToken,
+			Data: map[string][]byte{
+				bootstrapapi.BootstrapTokenIDKey:         []byte(tokenID),
+				bootstrapapi.BootstrapTokenExpirationKey: []byte(expiration.UTC().Format(time.RFC3339)),
+			},
+		}
+	}
+	newObjects := func() []client.Object {
+		return []client.Object{
+			newTokenSecret("aaaaaa", time.Now().Add(-2*time.Hour)),
+			newTokenSecret("bbbbbb", time.Now().Add(-10*time.Minute)),
+			newTokenSecret("cccccc", time.Now().Add(10*time.Minute)),
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other",
+					Namespace: metav1.NamespaceSystem,
+				},
+			},
+		}
+	}
+	remainingSecrets := func(g *WithT, c client.Client) []string {
+		l := &corev1.SecretList{}
+		g.Expect(c.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+		names := []string{}
+		for _, s := range l.Items {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	t.Run("deletes the tokens expired for longer than the grace period", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster("cluster")
+		cluster.Status.ControlPlaneInitialized = true
+		myclient := helpers.NewFakeClientWithScheme(setupScheme(), append(newObjects(), cluster)...)
+
+		r := &TokenGarbageCollectorReconciler{
+			Client:              myclient,
+			DeleteExpiredTokens: true,
+			GracePeriod:         time.Hour,
+			Interval:            DefaultTokenGarbageCollectionInterval,
+			remoteClientGetter:  fakeremote.NewClusterClient,
+		}
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(DefaultTokenGarbageCollectionInterval))
+		g.Expect(remainingSecrets(g, myclient)).To(ConsistOf(
+			bootstraputil.BootstrapTokenSecretName("bbbbbb"),
+			bootstraputil.BootstrapTokenSecretName("cccccc"),
+			"other",
+		))
+	})
+
+	t.Run("deletes the tokens whose KubeadmConfig has been deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		newOwnedTokenSecret := func(tokenID string, ownerUID types.UID) *corev1.Secret {
+			secret := newTokenSecret(tokenID, time.Now().Add(time.Hour))
+			secret.Labels = map[string]string{tokenOwnerLabel: string(ownerUID)}
+			return secret
+		}
+		live := newKubeadmConfig(nil, "live")
+		live.UID = "live-uid"
+		// The moved config references its token, created by the same config before it got a new UID.
+		moved := newKubeadmConfig(nil, "moved")
+		moved.UID = "moved-uid"
+		moved.Spec.JoinConfiguration = &kubeadmv1beta1.JoinConfiguration{
+			Discovery: kubeadmv1beta1.Discovery{
+				BootstrapToken: &kubeadmv1beta1.BootstrapTokenDiscovery{Token: "eeeeee.abcdefghijklmnop"},
+			},
+		}
+
+		cluster := newCluster("cluster")
+		cluster.Status.ControlPlaneInitialized = true
+		myclient := helpers.NewFakeClientWithScheme(setupScheme(),
+			cluster,
+			live,
+			moved,
+			newOwnedTokenSecret("dddddd", live.UID),
+			newOwnedTokenSecret("eeeeee", "moved-old-uid"),
+			newOwnedTokenSecret("ffffff", "deleted-uid"),
+			newTokenSecret("gggggg", time.Now().Add(time.Hour)),
+		)
+
+		// Orphaned tokens are deleted even when deletion of expired tokens is disabled, as it is by default.
+		r := &TokenGarbageCollectorReconciler{
+			Client:             myclient,
+			GracePeriod:        time.Hour,
+			Interval:           DefaultTokenGarbageCollectionInterval,
+			remoteClientGetter: fakeremote.NewClusterClient,
+		}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(remainingSecrets(g, myclient)).To(ConsistOf(
+			bootstraputil.BootstrapTokenSecretName("dddddd"),
+			bootstraputil.BootstrapTokenSecretName("eeeeee"),
+			bootstraputil.BootstrapTokenSecretName("gggggg"),
+		))
+		// The active tokens gauge counts the unexpired tokens created by the controller which are left.
+		g.Expect(testutil.ToFloat64(tokenActive.WithLabelValues(util.ObjectKey(cluster).String()))).To(Equal(float64(2)))
+
+		// Once the owning config is gone, its token is deleted at the next sweep.
+		g.Expect(myclient.Delete(ctx, live)).To(Succeed())
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(remainingSecrets(g, myclient)).To(ConsistOf(
+			bootstraputil.BootstrapTokenSecretName("eeeeee"),
+			bootstraputil.BootstrapTokenSecretName("gggggg"),
+		))
+		g.Expect(testutil.ToFloat64(tokenActive.WithLabelValues(util.ObjectKey(cluster).String()))).To(Equal(float64(1)))
+	})
+
+	t.Run("keeps the expired tokens when deletion of expired tokens is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := newKubeadmConfig(nil, "config")
+		config.UID = "config-uid"
+		cluster := newCluster("cluster")
+		cluster.Status.ControlPlaneInitialized = true
+		objects := newObjects()
+		for _, o := range objects {
+			if o.GetName() != "other" {
+				o.SetLabels(map[string]string{tokenOwnerLabel: string(config.UID)})
+			}
+		}
+		myclient := helpers.NewFakeClientWithScheme(setupScheme(), append(objects, cluster, config)...)
+
+		r := &TokenGarbageCollectorReconciler{
+			Client:             myclient,
+			GracePeriod:        time.Hour,
+			Interval:           DefaultTokenGarbageCollectionInterval,
+			remoteClientGetter: fakeremote.NewClusterClient,
+		}
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(DefaultTokenGarbageCollectionInterval))
+		g.Expect(remainingSecrets(g, myclient)).To(HaveLen(4))
+		g.Expect(testutil.ToFloat64(tokenActive.WithLabelValues(util.ObjectKey(cluster).String()))).To(Equal(float64(1)))
+	})
+
+	t.Run("does nothing until the control plane is initialized", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster("cluster")
+		myclient := helpers.NewFakeClientWithScheme(setupScheme(), append(newObjects(), cluster)...)
+
+		r := &TokenGarbageCollectorReconciler{
+			Client:              myclient,
+			DeleteExpiredTokens: true,
+			GracePeriod:         time.Hour,
+			remoteClientGetter:  fakeremote.NewClusterClient,
+		}
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(remainingSecrets(g, myclient)).To(HaveLen(4))
+	})
+}
@@ -19,6 +19,7 @@ package cloudinit
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/pkg/errors"
@@ -53,13 +54,20 @@ type BaseUserData struct {
 	UseExperimentalRetry bool
 	KubeadmCommand       string
 	KubeadmVerbosity     string
+	KubeadmPatches       string
 	SentinelFileCommand  string
 }
 
+// KubeadmCommandFlags returns the flags of the kubeadm init and join commands, i.e. the verbosity
+// and the patches flags.
+func (input *BaseUserData) KubeadmCommandFlags() string {
+	return strings.TrimSpace(input.KubeadmVerbosity + " " + input.KubeadmPatches)
+}
+
 func (input *BaseUserData) prepare() error {
 	input.Header = cloudConfigHeader
 	input.WriteFiles = append(input.WriteFiles, input.AdditionalFiles...)
-	input.KubeadmCommand = fmt.Sprintf(standardJoinCommand, input.KubeadmVerbosity)
+	input.KubeadmCommand = fmt.Sprintf(standardJoinCommand, input.KubeadmCommandFlags())
 	if input.UseExperimentalRetry {
 		input.KubeadmCommand = retriableJoinScriptName
 		joinScriptFile, err := generateBootstrapScript(input)
@@ -115,6 +123,12 @@ func generate(kind string, tpl string, data interface{}) ([]byte, error) {
 	return out.Bytes(), nil
 }
 
+// NewBootstrapScript returns the script running kubeadm join with retries used when UseExperimentalRetry is set,
+// so it can be embedded in bootstrap data formats other than cloud-config.
+func NewBootstrapScript(input *BaseUserData) (*bootstrapv1.File, error) {
+	return generateBootstrapScript(input)
+}
+
 func generateBootstrapScript(input interface{}) (*bootstrapv1.File, error) {
 	scriptBytes, err := bootstrapKubeadmInternalCloudinitKubeadmBootstrapScriptShBytes()
 	if err != nil {
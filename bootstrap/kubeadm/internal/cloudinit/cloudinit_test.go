@@ -179,3 +179,31 @@ func TestNewInitControlPlaneDiskMounts(t *testing.T) {
 	g.Expect(out).To(ContainSubstring(expectedFSSetup))
 	g.Expect(out).To(ContainSubstring(expectedMounts))
 }
+
+func TestNewJoinControlPlanePatches(t *testing.T) {
+	g := NewWithT(t)
+
+	cpinput := &ControlPlaneJoinInput{
+		BaseUserData: BaseUserData{
+			Header:           "test",
+			KubeadmVerbosity: "--v 5",
+			KubeadmPatches:   "--patches /etc/kubernetes/patches",
+		},
+		Certificates:      secret.Certificates{},
+		BootstrapToken:    "my-bootstrap-token",
+		JoinConfiguration: "my-join-config",
+	}
+
+	out, err := NewJoinControlPlane(cpinput)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out).To(ContainSubstring("kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml --v 5 --patches /etc/kubernetes/patches"))
+
+	// With retries, the patches are only passed to the join phases generating the static pod manifests.
+	cpinput.UseExperimentalRetry = true
+	out, err = NewJoinControlPlane(cpinput)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out).To(ContainSubstring("retry-command kubeadm join phase control-plane-prepare control-plane --patches /etc/kubernetes/patches\n"))
+	g.Expect(out).To(ContainSubstring("try-or-die-command kubeadm join phase control-plane-join etcd --patches /etc/kubernetes/patches\n"))
+	g.Expect(out).To(ContainSubstring("retry-command kubeadm join phase kubelet-start\n"))
+	g.Expect(out).To(ContainSubstring("--config=/run/kubeadm/kubeadm-join-config.yaml --v 5\n"))
+}
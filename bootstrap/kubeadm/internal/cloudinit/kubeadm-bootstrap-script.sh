@@ -125,11 +125,13 @@ retry-command kubeadm join phase preflight --ignore-preflight-errors=DirAvailabl
 retry-command kubeadm join phase control-plane-prepare download-certs
 retry-command kubeadm join phase control-plane-prepare certs
 retry-command kubeadm join phase control-plane-prepare kubeconfig
-retry-command kubeadm join phase control-plane-prepare control-plane
+# shellcheck disable=SC1083
+retry-command kubeadm join phase control-plane-prepare control-plane{{with .KubeadmPatches}} {{.}}{{end}}
 # {{ end }}
 retry-command kubeadm join phase kubelet-start
 # {{ if .ControlPlane }}
-try-or-die-command kubeadm join phase control-plane-join etcd
+# shellcheck disable=SC1083
+try-or-die-command kubeadm join phase control-plane-join etcd{{with .KubeadmPatches}} {{.}}{{end}}
 retry-command kubeadm join phase control-plane-join update-status
 retry-command kubeadm join phase control-plane-join mark-control-plane
 # {{ end }}
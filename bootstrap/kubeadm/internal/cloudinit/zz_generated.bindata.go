@@ -93,7 +93,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _bootstrapKubeadmInternalCloudinitKubeadmBootstrapScriptSh = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x57\x61\x6f\xdb\xb0\x11\xfd\xae\x5f\xf1\x62\x1b\x6b\xd2\x44\xb6\xe3\xa2\x43\x91\xc0\xdb\xbc\xb4\xc5\x8c\x76\x49\x11\xa7\x2d\x8a\xa2\x08\x68\xe9\x24\x71\xa6\x48\x95\xa4\xe2\x18\x6e\xfe\xfb\x40\x4a\x76\xec\xd8\x4e\xda\x6c\xf9\x12\x81\x3c\xbe\x7b\x77\xf7\xee\x48\x37\xf7\x3a\x63\x2e\x3b\x63\x66\xb2\xa0\x89\x33\x55\xcc\x34\x4f\x33\x8b\x5e\xb7\xd7\xc5\x55\x46\xf8\x50\x8e\x49\x4b\xb2\x64\x30\x28\x6d\xa6\xb4\x69\x07\xcd\xa0\x89\x8f\x3c\x22\x69\x28\x46\x29\x63\xd2\xb0\x19\x61\x50\xb0\x28\xa3\xc5\xce\x11\xbe\x90\x36\x5c\x49\xf4\xda\x5d\xec\x3b\x83\x46\xbd\xd5\x38\x38\x0d\x9a\x98\xa9\x12\x39\x9b\x41\x2a\x8b\xd2\x10\x6c\xc6\x0d\x12\x2e\x08\x74\x1b\x51\x61\xc1\x25\x22\x95\x17\x82\x33\x19\x11\xa6\xdc\x66\xde\x4d\x0d\xd2\x0e\x9a\xf8\x56\x43\xa8\xb1\x65\x5c\x82\x21\x52\xc5\x0c\x2a\x59\xb5\x03\xb3\x9e\xb0\xfb\xcb\xac\x2d\x4e\x3a\x9d\xe9\x74\xda\x66\x9e\x6c\x5b\xe9\xb4\x23\x2a\x43\xd3\xf9\x38\x3c\x7b\x77\x3e\x7a\x17\xf6\xda\x5d\x7f\xe4\xb3\x14\x64\x0c\x34\xfd\x2c\xb9\xa6\x18\xe3\x19\x58\x51\x08\x1e\xb1\xb1\x20\x08\x36\x85\xd2\x60\xa9\x26\x8a\x61\x95\xe3\x3b\xd5\xdc\x72\x99\x1e\xc1\xa8\xc4\x4e\x99\xa6\xa0\x89\x98\x1b\xab\xf9\xb8\xb4\x6b\xc9\x5a\xb0\xe3\x66\xcd\x40\x49\x30\x89\xc6\x60\x84\xe1\xa8\x81\x7f\x0e\x46\xc3\xd1\x51\xd0\xc4\xd7\xe1\xd5\xbf\x2e\x3e\x5f\xe1\xeb\xe0\xf2\x72\x70\x7e\x35\x7c\x37\xc2\xc5\x25\xce\x2e\xce\xdf\x0e\xaf\x86\x17\xe7\x23\x5c\xbc\xc7\xe0\xfc\x1b\x3e\x0c\xcf\xdf\x1e\x81\xb8\xcd\x48\x83\x6e\x0b\xed\xf8\x2b\x0d\xee\xd2\x48\xb1\xcb\xd9\x88\x68\x8d\x40\xa2\x2a\x42\xa6\xa0\x88\x27\x3c\x82\x60\x32\x2d\x59\x4a\x48\xd5\x0d\x69\xc9\x65\x8a\x82\x74\xce\x8d\x2b\xa6\x01\x93\x71\xd0\x84\xe0\x39\xb7\xcc\xfa\x95\x8d\xa0\xda\x81\x13\x88\x4a\x5d\x28\xa4\xb5\x4b\x92\x8c\x41\xb7\xdc\x3a\x02\x03\x9d\x9a\x13\x5f\x90\xd6\x31\xfe\x4d\xc6\x38\x5f\x56\x41\xa8\xf4\xbe\xc8\xfe\x58\x65\xd4\xf3\x3a\xac\x70\x22\x15\x7b\x5b\x4d\xb6\xd4\x32\x10\x2a\x3d\x39\xf1\x3b\xd7\x0e\x7d\xff\x00\xf3\x00\x10\x2a\x62\x02\x79\x85\xdc\x6f\xb4\xe6\xc7\x77\x8d\xe5\xb2\x43\x70\x6b\xbd\xbb\x46\xe0\x17\x17\x08\x68\xb4\xe6\xf5\x19\x6f\xde\xc4\x7c\x0e\x9e\xa0\x7d\xa6\xa4\xd5\x4a\x7c\x12\x4c\x12\xee\xee\x16\x87\xb8\x4c\x14\x1a\x97\x94\xab\x1b\x97\xa2\x9c\xf2\x31\x69\x24\x5a\xe5\x88\x44\x69\x2c\x69\x18\xcb\x6c\x69\x1c\xd8\xa4\x1c\x13\x8b\x73\x68\x32\x64\x11\x26\x28\x8b\x98\x59\x0a\x6b\xcb\xb0\xb2\xc4\xaf\x5f\xb0\xba\xa4\x1d\x2e\xc8\x46\x71\xed\x67\x2b\xa6\x76\x86\x14\x3a\xb3\xb0\xa6\x73\x0f\xe8\xc3\x21\x19\x6f\x89\xc0\x90\x75\xa2\x5d\x00\x6e\xc5\x7e\xc0\xac\xce\x58\x4d\xbf\x7d\x1b\x4e\xde\x98\x36\x57\xcb\x73\x63\xa5\xac\xb1\x9a\x15\x30\x91\xe6\x85\x45\xab\xeb\xeb\xef\xdc\xf8\x1a\xd7\x01\xb7\xe6\xae\x1e\x3e\xdf\x6e\xdb\xd5\xa0\x5e\xb8\x0b\xaa\xea\x9a\x32\x8a\xc8\x98\xf5\xfa\x2e\xc9\xff\x11\x81\x84\x4b\x6e\x32\x8a\x97\xde\xba\xce\xcb\x03\xa5\x8e\x4b\x8b\x09\x51\x81\x54\x71\x99\xb6\x57\x24\xf6\xb8\xba\x2c\xcf\xc9\x58\x96\x17\xfd\xd6\xbe\x2b\x2d\xc2\x90\x1b\x15\xbe\xf9\x6b\xf7\xb8\x6f\x28\x52\x32\x36\x07\xce\x6f\x94\x29\x34\xf6\xf6\xf6\xf0\xbd\x35\x5f\x9e\xb9\xfb\x01\x8f\x83\xbf\xfd\xa5\x17\x00\x26\xe3\x89\x0d\xe0\x5b\xb3\x76\x74\x8a\x58\x05\x6e\x84\x55\x00\xee\x6b\x45\xae\xf5\xb9\x58\x49\xaa\x42\xfa\xa4\xb9\xb4\x60\x8b\x34\x0b\x2e\xa9\x0d\xbc\x57\x3a\x67\xd6\x56\xd3\xca\x64\x6a\x8a\xb2\x80\x9f\x9b\xc6\x6a\x62\xb9\x9b\x9c\xaa\xb4\x45\x69\xeb\xb8\x5d\x92\xeb\xb0\xff\x28\xbe\xc3\xc3\xc3\xad\xf1\x3d\x27\xb6\x95\xb8\xa2\x8c\xa2\xc9\x75\x5d\xe2\xeb\x48\xe5\x39\x93\xf1\x5a\x59\xea\xb5\xc7\x9a\x1e\x88\x98\xa1\x85\xf2\xc0\x65\x00\x34\xba\x8d\x03\xcf\x60\x45\x5a\xf7\x2d\x50\x28\xed\x72\x56\x2b\x31\x29\x05\xe8\x96\xa2\xd2\x0d\x3f\x1f\x86\x83\xf2\x6e\x3d\x3a\x70\x7a\xea\x20\x8f\x57\x21\xeb\x7e\xd9\xc0\x4c\x18\x17\x14\x83\x45\x0e\x6c\xdf\x1c\x3c\x82\xd7\xfb\x1d\xbc\x42\x53\x22\xfc\x05\xee\x73\x55\x6b\x3a\x2e\xb5\x6b\xbc\xed\xb8\xaf\x36\x70\xaf\xab\x56\xdc\x00\xbf\x61\x82\xc7\x7e\xe6\xd7\xb8\x0f\xc8\xae\x74\xef\x12\xfe\xe5\x6f\x90\x2e\xe5\x44\xaa\xe9\x02\x74\x51\x98\x9d\x99\x20\xc3\x22\xa7\x86\xa4\x94\x3e\x6d\xee\x32\xd0\xb3\x70\x5d\x0e\xb2\xdf\x5d\x56\x7f\x21\x98\xfa\xd2\x00\x4a\x69\xb9\xc0\x77\xb4\x24\xc2\x94\xf0\x1a\x3f\x96\x12\x5c\x11\x80\x2e\xa5\xbf\xfc\x5e\xb4\x5e\xbe\xa8\xdc\x37\x61\x32\x12\xa2\x4a\x6d\xcc\x8d\x7b\x06\xf4\x47\x67\xc7\xdd\x37\xaf\xfc\x7e\xa3\xf5\x8f\x06\xc2\x30\x52\x32\xe1\x69\xbf\xa3\x4b\xd9\xa9\x7d\x2f\xfe\x87\xff\x51\x5c\xd6\x06\xed\x19\xcb\x05\xe6\xf3\xf6\x87\x6a\xef\x0b\xe9\xb1\x32\xdc\xce\xfc\x84\xc6\x03\xda\xfd\xd6\xdf\xfd\xea\xd6\x1e\x40\xc3\x93\x74\x05\x58\x3f\x55\xe7\x8d\x27\x2e\xda\x87\x7b\x08\xe9\x27\xba\x2e\x78\x9b\x91\xf4\x86\xc0\x58\x13\x9b\xf8\xef\x84\xd7\x41\x7f\x25\x30\x21\xd4\x74\x45\x5d\xbe\x54\xc6\x8d\x91\x82\x19\xf3\x94\x8f\xde\x53\x3e\x64\xbf\xb5\xbf\x2f\x71\x88\xe3\x83\x4a\x2f\x46\xb8\x11\x7c\xfc\x7a\xd1\xfc\xbb\xe1\x25\x3d\x08\x61\x43\xc7\x56\x29\xe4\x4c\xce\x6a\xd2\x47\x8b\x8b\x68\x57\xba\x12\x5e\xcd\xd0\x1d\xd7\xff\x52\x76\x4e\x74\x4a\x87\x31\xa7\x70\xdb\x28\xda\x50\xdd\x23\xd2\x7a\x5c\x58\xff\x4f\x59\x6d\x13\xd5\x33\x24\xf5\xfc\x6a\x24\xcc\x32\x51\x95\xe2\xf7\x2a\xb1\xfa\x70\x09\xd6\x5a\x7d\x79\xd9\xbb\xe8\x51\x64\x6e\xac\xdf\x4b\x34\x0c\x79\x2a\x95\xa6\x70\xb9\x14\x56\x02\xe8\xbf\xe5\x7a\x70\xc3\xb8\x70\x59\x0e\xdd\x73\x29\x9c\x2c\x7f\xe4\x84\x39\x93\x3c\x21\x63\xcd\x6e\x05\x3c\x49\x22\xaa\x0e\x84\x85\x3b\xe1\xfc\x17\x4c\x13\x62\x35\x95\x42\xb1\x38\x8c\x48\x5b\xf3\x5c\x94\xff\xe9\xb0\x33\xac\x54\xf2\x6c\xf7\xab\xab\x6b\xb5\x79\x12\xd0\x2d\x09\xb2\xee\xad\xab\xed\xee\xec\x6e\xb6\xd5\xd3\xec\xfc\x86\x7b\xf7\xfe\x69\x58\x7e\xa3\x7e\x8b\x57\x6f\xa5\x67\x21\xe4\x4c\x4f\xc2\xdd\xa9\xd9\x7c\xc9\x06\xff\x0d\x00\x00\xff\xff\xe6\x1b\xc5\xf3\x78\x0f\x00\x00")
+var _bootstrapKubeadmInternalCloudinitKubeadmBootstrapScriptSh = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xac\x57\x7f\x6f\xdb\x38\x12\xfd\x5f\x9f\xe2\x55\x36\x6e\x93\x6d\x64\x3b\x5e\xec\xa1\x68\xe0\xbb\xf3\xa5\x5d\x9c\xd1\xbd\xa4\x88\xb3\x5b\x2c\x16\x8b\x80\x96\x46\x12\xcf\x14\xa9\x25\xa9\x38\x86\xeb\xef\x7e\x20\x25\x39\x76\xec\xfc\x68\xee\xfa\x4f\x83\xe1\xcc\x9b\x79\x33\x6f\x28\xba\xf3\xa6\x3f\xe3\xb2\x3f\x63\x26\x0f\x3a\x38\x57\xe5\x52\xf3\x2c\xb7\x18\x0e\x86\x03\x5c\xe7\x84\x4f\xd5\x8c\xb4\x24\x4b\x06\xe3\xca\xe6\x4a\x9b\x5e\xd0\x09\x3a\xf8\x99\xc7\x24\x0d\x25\xa8\x64\x42\x1a\x36\x27\x8c\x4b\x16\xe7\xd4\x9e\x9c\xe0\x57\xd2\x86\x2b\x89\x61\x6f\x80\x23\xe7\x10\x36\x47\xe1\xf1\x59\xd0\xc1\x52\x55\x28\xd8\x12\x52\x59\x54\x86\x60\x73\x6e\x90\x72\x41\xa0\xbb\x98\x4a\x0b\x2e\x11\xab\xa2\x14\x9c\xc9\x98\xb0\xe0\x36\xf7\x69\x1a\x90\x5e\xd0\xc1\x6f\x0d\x84\x9a\x59\xc6\x25\x18\x62\x55\x2e\xa1\xd2\x6d\x3f\x30\xeb\x0b\x76\xff\x72\x6b\xcb\xf7\xfd\xfe\x62\xb1\xe8\x31\x5f\x6c\x4f\xe9\xac\x2f\x6a\x47\xd3\xff\x79\x72\xfe\xf1\x62\xfa\x31\x1a\xf6\x06\x3e\xe4\x17\x29\xc8\x18\x68\xfa\xb3\xe2\x9a\x12\xcc\x96\x60\x65\x29\x78\xcc\x66\x82\x20\xd8\x02\x4a\x83\x65\x9a\x28\x81\x55\xae\xde\x85\xe6\x96\xcb\xec\x04\x46\xa5\x76\xc1\x34\x05\x1d\x24\xdc\x58\xcd\x67\x95\xdd\x69\x56\x5b\x1d\x37\x3b\x0e\x4a\x82\x49\x84\xe3\x29\x26\xd3\x10\xff\x1c\x4f\x27\xd3\x93\xa0\x83\x2f\x93\xeb\x7f\x5d\xfe\x72\x8d\x2f\xe3\xab\xab\xf1\xc5\xf5\xe4\xe3\x14\x97\x57\x38\xbf\xbc\xf8\x30\xb9\x9e\x5c\x5e\x4c\x71\xf9\x13\xc6\x17\xbf\xe1\xd3\xe4\xe2\xc3\x09\x88\xdb\x9c\x34\xe8\xae\xd4\xae\x7e\xa5\xc1\x5d\x1b\x29\x71\x3d\x9b\x12\xed\x14\x90\xaa\xba\x20\x53\x52\xcc\x53\x1e\x43\x30\x99\x55\x2c\x23\x64\xea\x96\xb4\xe4\x32\x43\x49\xba\xe0\xc6\x0d\xd3\x80\xc9\x24\xe8\x40\xf0\x82\x5b\x66\xbd\x65\x8f\x54\x2f\x70\x02\x51\x99\xa3\x42\x5a\xbb\x26\xc9\x04\x74\xc7\xad\x2b\x60\xac\x33\xf3\xde\x0f\xa4\x7b\x8a\x7f\x93\x31\x2e\x97\x55\x10\x2a\xbb\x1f\xb2\x0f\xab\x9d\x86\xb8\x6e\x0d\x88\x55\xe2\x7d\x35\xd9\x4a\xcb\x40\xa8\xec\xfd\x7b\x7f\x72\xe3\xd0\x8f\x8e\xb1\x0a\x00\xa1\x62\x26\x50\xd4\xc8\xa3\xb0\xbb\x3a\x5d\x87\x1b\xb3\x43\x70\xb6\xe1\x3a\x0c\xbc\xb1\x45\x40\xd8\x5d\x35\x31\xde\xbd\x83\xd5\x0a\x3c\x45\xef\x5c\x49\xab\x95\xf8\x2c\x98\x24\xac\xd7\x6d\x10\x97\xa9\x42\x78\x45\x85\xba\x75\x2d\x2a\xa8\x98\x91\x46\xaa\x55\x81\x58\x54\xc6\x92\x86\xb1\xcc\x56\xc6\x81\xcd\xab\x19\xb1\xa4\x80\x26\x43\x16\x51\x8a\xaa\x4c\x98\xa5\xa8\xf1\x8c\x6a\x4f\x7c\xfd\x0a\xab\x2b\x7a\x24\x05\xd9\x38\x69\xf2\x1c\xc4\xd4\xce\x91\x22\xe7\x16\x35\xe5\xdc\x03\x7a\x3a\x24\x93\x03\x0c\x0c\x59\x27\xda\x16\xf0\x20\xf6\x83\xca\x9a\x8e\x35\xe5\xf7\xee\xa2\xf9\x3b\xd3\xe3\x6a\x13\x37\x53\xca\x1a\xab\x59\x09\x13\x6b\x5e\x5a\x74\x07\x7e\xfe\x5c\x36\x33\x6e\x08\x77\x57\x6e\x1e\xbe\xdf\xee\x18\xe1\xc6\xb0\x0e\xea\xe9\x9a\x2a\x8e\xc9\x98\xdd\xf9\x6e\x8a\xff\xa6\x02\x52\x2e\xb9\xc9\x29\xd9\x64\x1b\x04\xeb\x3d\xa5\xce\x2a\x8b\x39\x51\x89\x4c\x71\x99\xf5\xb6\x24\xf6\xb4\xba\x2c\x2f\xc8\x58\x56\x94\xa3\xee\x91\x1b\x2d\xa2\x88\x1b\x15\xbd\xfb\xeb\xe0\x74\x64\x28\x56\x32\x31\xc7\x2e\x6f\x9c\x2b\x84\x6f\xde\xbc\xc1\xef\xdd\xd5\x26\x66\xfd\x07\x3c\x0e\xfe\xf6\x97\x61\x00\x98\x9c\xa7\x36\x80\x5f\xcd\x26\xd1\x19\x12\x15\x00\x2d\x80\xfb\x6b\x4b\xae\x4d\x5c\xa2\x24\xd5\x94\x3e\x6b\x2e\x2d\x58\xdb\x66\xc1\x25\xf5\x80\x9f\x94\x2e\x98\xb5\xf5\x6d\x65\x72\xb5\x40\x55\xc2\xdf\x9b\xc6\x6a\x62\x05\x54\x0a\x55\xd9\xb2\xb2\xbd\x60\xd3\xe4\x86\xf6\x37\xf1\x7b\xfb\xf6\xed\x41\x7e\xaf\xe1\xb6\xc5\x2b\xce\x29\x9e\xdf\x34\x23\xbe\x89\x55\x51\x30\x99\xec\x8c\xa5\xb1\x3d\xb5\xf4\x40\xcc\x0c\xb5\xca\x03\x97\x01\x10\x0e\xc2\x63\x5f\xc1\x96\xb4\xee\x57\xa0\x54\xda\xf5\xac\x51\x62\x5a\x09\xd0\x1d\xc5\x95\xbb\xfc\x3c\x8d\xee\xaa\x49\xeb\xd1\x81\xb3\x33\x07\x79\xba\x0d\xd9\xec\xcb\x1e\x66\xca\xb8\xa0\x04\x2c\x76\x60\x47\xe6\xf8\x09\xbc\xe1\x4b\xf0\x4a\x4d\xa9\xf0\x1f\x70\xdf\xab\x46\xd3\x49\xa5\xdd\xe2\x1d\xc6\xfd\x61\x0f\xf7\xa6\x5e\xc5\x3d\xf0\x5b\x26\x78\xe2\xef\xfc\x06\xf7\x41\xb1\x5b\xdb\xbb\x81\xff\xfe\x05\x45\x57\x72\x2e\xd5\xa2\x05\x6d\x07\xf3\x68\x27\xc8\xb0\xd8\xa9\x21\xad\xa4\x6f\x1b\x34\x59\xbd\x8c\x76\xe5\x20\x47\x83\xcd\xf4\x5b\xc1\x34\x1f\x0d\xa0\x92\x96\x0b\xfc\x8e\xae\x44\x94\x11\x7e\xc4\x1f\x1b\x09\x6e\x09\x40\x57\xd2\x7f\xfc\xbe\xeb\x7e\xff\x5d\x9d\xbe\x03\x93\x93\x10\x75\x6b\x13\x6e\xdc\x33\x60\x34\x3d\x3f\x1d\xbc\xfb\xc1\x9f\x87\xdd\x7f\x84\x88\xa2\x58\xc9\x94\x67\xa3\xbe\xae\x64\xbf\xc9\xdd\xfe\x1f\xfd\x47\x71\xd9\x38\xf4\x96\xac\x10\x58\xad\x7a\x9f\xea\xb3\x5f\x49\xcf\x94\xe1\x76\xe9\x6f\x68\x3c\x28\x7b\xd4\xfd\xbb\xb7\x1e\xdc\x01\x84\xbe\x48\x37\x80\xdd\xa8\xa6\x6f\x3c\x75\x6c\x1f\x9e\x21\xa2\x3f\x31\x70\xe4\x6d\x4e\xd2\x3b\x02\x33\x4d\x6c\xee\xff\x4e\x79\x43\xfa\x0b\x81\x09\xa1\x16\x5b\xea\xf2\xa3\x32\xb0\x0a\x25\x33\xe6\xb9\x1c\xc3\xe7\x72\xc8\x51\xf7\xe8\x48\xe2\x2d\x4e\x8f\x6b\xbd\x18\xe1\xae\xe0\xd3\x1f\xdb\xe5\x7f\x1c\x5e\xd2\x03\x0a\x7b\x3a\xb6\x4a\xa1\x60\x72\xd9\x14\x7d\xd2\x7e\x88\x1e\x6b\x57\xca\xeb\x3b\xf4\x91\xcf\xff\x46\x76\x4e\x74\x4a\x47\x09\xa7\xe8\xd0\x55\xb4\xa7\xba\x27\xa4\xf5\xb4\xb0\xfe\x9f\xb2\x3a\x24\xaa\x57\x48\xea\xf5\xd3\x48\x99\x65\xa2\x1e\xc5\xcb\x26\xb1\xfd\x70\x09\x76\x56\xbd\x25\x03\xc7\x1e\x65\xee\xae\xf5\x7b\x89\x46\x11\xcf\xa4\xd2\x14\x6d\x4c\x51\x2d\x80\xd1\x07\xae\xc7\xb7\x8c\x0b\xd7\xe5\xc8\x3d\x97\xa2\xf9\xe6\x47\x4e\x54\x30\xc9\x53\x32\xd6\x3c\xae\x80\x67\x8b\x88\xeb\x80\xa8\x74\x11\x2e\x7f\xc9\x34\x21\x51\x0b\x29\x14\x4b\xa2\x98\xb4\x35\xaf\x45\xf9\x9f\x82\x9d\x63\xad\x92\xe0\x29\xc9\xbd\xb6\xb4\x6d\xeb\x6a\xe5\xdf\x79\xad\x0a\x3f\x33\x1b\xe7\x64\xd6\x6b\xa7\xcc\xf5\x7a\xb5\x22\x99\xac\x77\x87\xfb\x6c\x56\x67\x12\x64\xdd\x63\x59\xdb\xc7\xc7\xf3\x14\xb3\xfd\x9d\x7d\x9e\x9e\x3f\x70\x8f\xea\x97\x51\xfa\xc6\xe6\xf9\x83\xe6\xd7\x40\xfd\x5a\x7b\x15\x42\xc1\xf4\x3c\xda\xb1\xef\x2e\xce\xde\x5b\x3a\xf8\xef\x00\x5f\x1a\xd3\x7d\xfa\x0f\x00\x00")
 
 func bootstrapKubeadmInternalCloudinitKubeadmBootstrapScriptShBytes() ([]byte, error) {
 	return bindataRead(
@@ -108,7 +108,7 @@ func bootstrapKubeadmInternalCloudinitKubeadmBootstrapScriptSh() (*asset, error)
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "bootstrap/kubeadm/internal/cloudinit/kubeadm-bootstrap-script.sh", size: 3960, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "bootstrap/kubeadm/internal/cloudinit/kubeadm-bootstrap-script.sh", size: 4090, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ignition renders the bootstrap data of the kubeadm bootstrap provider as an Ignition config,
+// for operating systems like Flatcar Container Linux and Fedora CoreOS which do not support cloud-init.
+package ignition
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+)
+
+const (
+	ignitionVersion = "3.1.0"
+
+	joinCommand = "kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml %s"
+	initCommand = "kubeadm init --config /run/kubeadm/kubeadm.yaml %s"
+	// sentinelFileCommand writes a file to /run/cluster-api to signal successful Kubernetes bootstrapping.
+	sentinelFileCommand = "mkdir -p /run/cluster-api && echo success > /run/cluster-api/bootstrap-success.complete"
+
+	// kubeadmScriptPath is the path of the script running the kubeadm command along with the user provided commands.
+	kubeadmScriptPath = "/etc/kubeadm.sh"
+	// kubeadmCompletePath is written once the kubeadm script ran, so it is not run again on reboots;
+	// this matches cloud-init running user commands only once per instance.
+	kubeadmCompletePath = "/etc/kubeadm.complete"
+
+	kubeadmUnit = `[Unit]
+Description=kubeadm
+Wants=network-online.target
+After=network-online.target
+ConditionPathExists=!` + kubeadmCompletePath + `
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=` + kubeadmScriptPath + `
+
+[Install]
+WantedBy=multi-user.target
+`
+)
+
+// NewNode returns the Ignition config to be used on a node instance.
+func NewNode(input *cloudinit.NodeInput) ([]byte, error) {
+	files := append([]bootstrapv1.File{}, input.AdditionalFiles...)
+	files = append(files, bootstrapv1.File{
+		Path:        "/run/kubeadm/kubeadm-join-config.yaml",
+		Owner:       "root:root",
+		Permissions: "0640",
+		Content:     "---\n" + input.JoinConfiguration,
+	})
+	return render(&input.BaseUserData, files, joinCommand)
+}
+
+// NewJoinControlPlane returns the Ignition config to be used on a new control plane instance.
+func NewJoinControlPlane(input *cloudinit.ControlPlaneJoinInput) ([]byte, error) {
+	input.ControlPlane = true
+	files := input.Certificates.AsFiles()
+	files = append(files, input.AdditionalFiles...)
+	files = append(files, bootstrapv1.File{
+		Path:        "/run/kubeadm/kubeadm-join-config.yaml",
+		Owner:       "root:root",
+		Permissions: "0640",
+		Content:     input.JoinConfiguration,
+	})
+	return render(&input.BaseUserData, files, joinCommand)
+}
+
+// NewInitControlPlane returns the Ignition config to be used on a controlplane instance.
+func NewInitControlPlane(input *cloudinit.ControlPlaneInput) ([]byte, error) {
+	// Retries are only supported for joins.
+	input.UseExperimentalRetry = false
+	files := input.Certificates.AsFiles()
+	files = append(files, input.AdditionalFiles...)
+	files = append(files, bootstrapv1.File{
+		Path:        "/run/kubeadm/kubeadm.yaml",
+		Owner:       "root:root",
+		Permissions: "0640",
+		Content:     "---\n" + input.ClusterConfiguration + "\n---\n" + input.InitConfiguration,
+	})
+	return render(&input.BaseUserData, files, initCommand)
+}
+
+// render returns an Ignition config writing the given files and running the kubeadm command, formatted with
+// the kubeadm command flags, between the pre and post kubeadm commands of the input.
+func render(input *cloudinit.BaseUserData, files []bootstrapv1.File, kubeadmCommand string) ([]byte, error) {
+	if input.DiskSetup != nil || len(input.Mounts) > 0 {
+		return nil, errors.New("disk setup and mounts are not supported with the ignition format")
+	}
+
+	kubeadmCommand = fmt.Sprintf(kubeadmCommand, input.KubeadmCommandFlags())
+	if input.UseExperimentalRetry {
+		script, err := cloudinit.NewBootstrapScript(input)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, *script)
+		kubeadmCommand = script.Path
+	}
+
+	commands := append([]string{}, input.PreKubeadmCommands...)
+	commands = append(commands, kubeadmCommand+" && "+sentinelFileCommand)
+	commands = append(commands, input.PostKubeadmCommands...)
+	commands = append(commands, "touch "+kubeadmCompletePath)
+	files = append(files, bootstrapv1.File{
+		Path:        kubeadmScriptPath,
+		Owner:       "root:root",
+		Permissions: "0700",
+		Content:     "#!/bin/bash\n" + strings.Join(commands, "\n") + "\n",
+	})
+
+	// Like cloud-init, NTP is enabled unless explicitly disabled.
+	if input.NTP != nil && (input.NTP.Enabled == nil || *input.NTP.Enabled) && len(input.NTP.Servers) > 0 {
+		files = append(files, bootstrapv1.File{
+			Path:        "/etc/systemd/timesyncd.conf",
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content:     "[Time]\nNTP=" + strings.Join(input.NTP.Servers, " ") + "\n",
+		})
+	}
+
+	config := Config{
+		Ignition: Ignition{Version: ignitionVersion},
+		Systemd: Systemd{
+			Units: []Unit{
+				{
+					Name:     "kubeadm.service",
+					Enabled:  pointer.BoolPtr(true),
+					Contents: kubeadmUnit,
+				},
+			},
+		},
+	}
+
+	for _, user := range input.Users {
+		config.Passwd.Users = append(config.Passwd.Users, convertUser(user))
+		if user.Sudo != nil {
+			files = append(files, bootstrapv1.File{
+				Path:        "/etc/sudoers.d/" + user.Name,
+				Owner:       "root:root",
+				Permissions: "0440",
+				Content:     fmt.Sprintf("%s %s\n", user.Name, *user.Sudo),
+			})
+		}
+	}
+
+	// Ignition rejects configs with more than one file for the same path; like cloud-init,
+	// the last file for a path wins.
+	seen := map[string]int{}
+	for _, file := range files {
+		f, err := convertFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := seen[f.Path]; ok {
+			config.Storage.Files[i] = f
+			continue
+		}
+		seen[f.Path] = len(config.Storage.Files)
+		config.Storage.Files = append(config.Storage.Files, f)
+	}
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal ignition config")
+	}
+	return out, nil
+}
+
+// convertUser converts a cloud-init user to an Ignition user.
+// Inactive and LockPassword have no Ignition equivalent; users without a password cannot log in with one anyway.
+func convertUser(user bootstrapv1.User) PasswdUser {
+	u := PasswdUser{
+		Name:              user.Name,
+		Gecos:             user.Gecos,
+		HomeDir:           user.HomeDir,
+		PasswordHash:      user.Passwd,
+		PrimaryGroup:      user.PrimaryGroup,
+		Shell:             user.Shell,
+		SSHAuthorizedKeys: user.SSHAuthorizedKeys,
+	}
+	if user.Groups != nil {
+		for _, group := range strings.Split(*user.Groups, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				u.Groups = append(u.Groups, group)
+			}
+		}
+	}
+	return u
+}
+
+// convertFile converts a cloud-init file to an Ignition file, embedding its content as a data URL.
+func convertFile(file bootstrapv1.File) (File, error) {
+	f := File{
+		Path:      file.Path,
+		Overwrite: pointer.BoolPtr(true),
+	}
+
+	if file.Owner != "" {
+		owner := strings.SplitN(file.Owner, ":", 2)
+		f.User = &NodeUser{Name: owner[0]}
+		if len(owner) == 2 {
+			f.Group = &NodeGroup{Name: owner[1]}
+		}
+	}
+
+	if file.Permissions != "" {
+		mode, err := strconv.ParseInt(file.Permissions, 8, 32)
+		if err != nil {
+			return File{}, errors.Wrapf(err, "invalid permissions %q for file %q", file.Permissions, file.Path)
+		}
+		m := int(mode)
+		f.Mode = &m
+	}
+
+	switch file.Encoding {
+	case bootstrapv1.Base64:
+		f.Contents.Source = "data:;base64," + file.Content
+	case bootstrapv1.Gzip:
+		f.Contents.Source = "data:;base64," + base64.StdEncoding.EncodeToString([]byte(file.Content))
+		f.Contents.Compression = "gzip"
+	case bootstrapv1.GzipBase64:
+		f.Contents.Source = "data:;base64," + file.Content
+		f.Contents.Compression = "gzip"
+	default:
+		f.Contents.Source = "data:;base64," + base64.StdEncoding.EncodeToString([]byte(file.Content))
+	}
+	return f, nil
+}
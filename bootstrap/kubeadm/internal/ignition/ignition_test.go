@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignition
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+func TestNewNode(t *testing.T) {
+	g := NewWithT(t)
+
+	out, err := NewNode(&cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			PreKubeadmCommands:  []string{"echo pre"},
+			PostKubeadmCommands: []string{"echo post"},
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:        "/etc/my-file",
+					Owner:       "core:core",
+					Permissions: "0600",
+					Content:     "hi",
+				},
+				{
+					Path:     "/etc/my-encoded-file",
+					Encoding: bootstrapv1.Base64,
+					Content:  "aGk=",
+				},
+				{
+					Path:     "/etc/my-compressed-file",
+					Encoding: bootstrapv1.GzipBase64,
+					Content:  "H4sIAAAAAAAA/8rIBAQAAP//rCoNrAIAAAA=",
+				},
+			},
+			Users: []bootstrapv1.User{
+				{
+					Name:              "core",
+					Groups:            pointer.StringPtr("wheel, docker"),
+					Sudo:              pointer.StringPtr("ALL=(ALL) NOPASSWD:ALL"),
+					SSHAuthorizedKeys: []string{"ssh-rsa AAAA"},
+				},
+			},
+			NTP: &bootstrapv1.NTP{
+				Servers: []string{"0.pool.ntp.org", "1.pool.ntp.org"},
+			},
+			KubeadmVerbosity: "--v 5",
+		},
+		JoinConfiguration: "kind: JoinConfiguration\n",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config := &Config{}
+	g.Expect(json.Unmarshal(out, config)).To(Succeed())
+	g.Expect(config.Ignition.Version).To(Equal("3.1.0"))
+
+	files := map[string]File{}
+	for _, f := range config.Storage.Files {
+		files[f.Path] = f
+	}
+	g.Expect(files).To(HaveKey("/run/kubeadm/kubeadm-join-config.yaml"))
+	g.Expect(fileContent(g, files["/run/kubeadm/kubeadm-join-config.yaml"])).To(Equal("---\nkind: JoinConfiguration\n"))
+
+	g.Expect(files).To(HaveKey("/etc/my-file"))
+	g.Expect(files["/etc/my-file"].User).To(Equal(&NodeUser{Name: "core"}))
+	g.Expect(files["/etc/my-file"].Group).To(Equal(&NodeGroup{Name: "core"}))
+	g.Expect(files["/etc/my-file"].Mode).NotTo(BeNil())
+	g.Expect(*files["/etc/my-file"].Mode).To(Equal(0600))
+	g.Expect(fileContent(g, files["/etc/my-file"])).To(Equal("hi"))
+	g.Expect(fileContent(g, files["/etc/my-encoded-file"])).To(Equal("hi"))
+	g.Expect(files["/etc/my-compressed-file"].Contents.Compression).To(Equal("gzip"))
+
+	g.Expect(files).To(HaveKey("/etc/kubeadm.sh"))
+	g.Expect(fileContent(g, files["/etc/kubeadm.sh"])).To(Equal(strings.Join([]string{
+		"#!/bin/bash",
+		"echo pre",
+		"kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml --v 5 && " + sentinelFileCommand,
+		"echo post",
+		"touch /etc/kubeadm.complete",
+		"",
+	}, "\n")))
+
+	g.Expect(fileContent(g, files["/etc/sudoers.d/core"])).To(Equal("core ALL=(ALL) NOPASSWD:ALL\n"))
+	g.Expect(fileContent(g, files["/etc/systemd/timesyncd.conf"])).To(Equal("[Time]\nNTP=0.pool.ntp.org 1.pool.ntp.org\n"))
+
+	g.Expect(config.Passwd.Users).To(ConsistOf(PasswdUser{
+		Name:              "core",
+		Groups:            []string{"wheel", "docker"},
+		SSHAuthorizedKeys: []string{"ssh-rsa AAAA"},
+	}))
+
+	g.Expect(config.Systemd.Units).To(HaveLen(1))
+	g.Expect(config.Systemd.Units[0].Name).To(Equal("kubeadm.service"))
+	g.Expect(config.Systemd.Units[0].Enabled).To(Equal(pointer.BoolPtr(true)))
+	g.Expect(config.Systemd.Units[0].Contents).To(ContainSubstring("ExecStart=/etc/kubeadm.sh"))
+}
+
+func TestNewNodeExperimentalRetry(t *testing.T) {
+	g := NewWithT(t)
+
+	out, err := NewNode(&cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			UseExperimentalRetry: true,
+		},
+		JoinConfiguration: "kind: JoinConfiguration\n",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config := &Config{}
+	g.Expect(json.Unmarshal(out, config)).To(Succeed())
+	files := map[string]File{}
+	for _, f := range config.Storage.Files {
+		files[f.Path] = f
+	}
+	g.Expect(files).To(HaveKey("/usr/local/bin/kubeadm-bootstrap-script"))
+	g.Expect(fileContent(g, files["/etc/kubeadm.sh"])).To(ContainSubstring("/usr/local/bin/kubeadm-bootstrap-script && "))
+}
+
+func TestNewInitControlPlane(t *testing.T) {
+	g := NewWithT(t)
+
+	out, err := NewInitControlPlane(&cloudinit.ControlPlaneInput{
+		BaseUserData: cloudinit.BaseUserData{
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:    "/etc/kubernetes/pki/ca.crt",
+					Content: "user provided certificate",
+				},
+			},
+		},
+		Certificates:         secret.Certificates{},
+		ClusterConfiguration: "kind: ClusterConfiguration",
+		InitConfiguration:    "kind: InitConfiguration",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config := &Config{}
+	g.Expect(json.Unmarshal(out, config)).To(Succeed())
+	files := map[string]File{}
+	for _, f := range config.Storage.Files {
+		g.Expect(files).NotTo(HaveKey(f.Path))
+		files[f.Path] = f
+	}
+	g.Expect(fileContent(g, files["/run/kubeadm/kubeadm.yaml"])).To(Equal("---\nkind: ClusterConfiguration\n---\nkind: InitConfiguration"))
+	g.Expect(fileContent(g, files["/etc/kubeadm.sh"])).To(ContainSubstring("kubeadm init --config /run/kubeadm/kubeadm.yaml"))
+}
+
+func TestNewJoinControlPlaneDeduplicatesFiles(t *testing.T) {
+	g := NewWithT(t)
+
+	out, err := NewJoinControlPlane(&cloudinit.ControlPlaneJoinInput{
+		BaseUserData: cloudinit.BaseUserData{
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:    "/etc/my-file",
+					Content: "first",
+				},
+				{
+					Path:    "/etc/my-file",
+					Content: "second",
+				},
+			},
+		},
+		JoinConfiguration: "kind: JoinConfiguration",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config := &Config{}
+	g.Expect(json.Unmarshal(out, config)).To(Succeed())
+	var myFiles []File
+	for _, f := range config.Storage.Files {
+		if f.Path == "/etc/my-file" {
+			myFiles = append(myFiles, f)
+		}
+	}
+	g.Expect(myFiles).To(HaveLen(1))
+	g.Expect(fileContent(g, myFiles[0])).To(Equal("second"))
+}
+
+func TestRenderRejectsUnsupportedFields(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewNode(&cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			Mounts: []bootstrapv1.MountPoints{{"LABEL=etcd_disk", "/var/lib/etcddisk"}},
+		},
+	})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = NewNode(&cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:        "/etc/my-file",
+					Permissions: "rw-r--r--",
+				},
+			},
+		},
+	})
+	g.Expect(err).To(HaveOccurred())
+}
+
+// fileContent returns the decoded content of a file, which must not be compressed.
+func fileContent(g *WithT, f File) string {
+	g.Expect(f.Contents.Source).To(HavePrefix("data:;base64,"))
+	content, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(f.Contents.Source, "data:;base64,"))
+	g.Expect(err).NotTo(HaveOccurred())
+	return string(content)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignition
+
+// The types below are the subset of the Ignition v3.1 config specification used by the kubeadm bootstrap provider.
+// See https://coreos.github.io/ignition/configuration-v3_1/ for the full specification.
+
+// Config is the root of an Ignition config.
+type Config struct {
+	Ignition Ignition `json:"ignition"`
+	Passwd   Passwd   `json:"passwd"`
+	Storage  Storage  `json:"storage"`
+	Systemd  Systemd  `json:"systemd"`
+}
+
+// Ignition contains metadata about the config itself.
+type Ignition struct {
+	Version string `json:"version"`
+}
+
+// Passwd contains the users to add to the system.
+type Passwd struct {
+	Users []PasswdUser `json:"users,omitempty"`
+}
+
+// PasswdUser is a user to add to the system.
+type PasswdUser struct {
+	Name              string   `json:"name"`
+	Gecos             *string  `json:"gecos,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+	HomeDir           *string  `json:"homeDir,omitempty"`
+	PasswordHash      *string  `json:"passwordHash,omitempty"`
+	PrimaryGroup      *string  `json:"primaryGroup,omitempty"`
+	Shell             *string  `json:"shell,omitempty"`
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+}
+
+// Storage contains the files to write to the system.
+type Storage struct {
+	Files []File `json:"files,omitempty"`
+}
+
+// File is a file to write to the system.
+type File struct {
+	Path      string       `json:"path"`
+	Overwrite *bool        `json:"overwrite,omitempty"`
+	User      *NodeUser    `json:"user,omitempty"`
+	Group     *NodeGroup   `json:"group,omitempty"`
+	Mode      *int         `json:"mode,omitempty"`
+	Contents  FileContents `json:"contents"`
+}
+
+// NodeUser is the owner of a file.
+type NodeUser struct {
+	Name string `json:"name"`
+}
+
+// NodeGroup is the group of a file.
+type NodeGroup struct {
+	Name string `json:"name"`
+}
+
+// FileContents defines the contents of a file.
+type FileContents struct {
+	Source      string `json:"source"`
+	Compression string `json:"compression,omitempty"`
+}
+
+// Systemd contains the systemd units to install on the system.
+type Systemd struct {
+	Units []Unit `json:"units,omitempty"`
+}
+
+// Unit is a systemd unit.
+type Unit struct {
+	Name     string `json:"name"`
+	Enabled  *bool  `json:"enabled,omitempty"`
+	Contents string `json:"contents,omitempty"`
+}
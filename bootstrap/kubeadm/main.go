@@ -27,6 +27,7 @@ import (
 
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog"
@@ -36,6 +37,7 @@ import (
 	kubeadmbootstrapcontrollers "sigs.k8s.io/cluster-api/bootstrap/kubeadm/controllers"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/multinamespace"
 	"sigs.k8s.io/cluster-api/version"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -65,8 +67,12 @@ var (
 	leaderElectionRenewDeadline time.Duration
 	leaderElectionRetryPeriod   time.Duration
 	watchNamespace              string
+	watchNamespaces             []string
 	profilerAddress             string
 	kubeadmConfigConcurrency    int
+	maxActiveTokensPerCluster   int
+	tokenGCGracePeriod          time.Duration
+	tokenSecretNamespace        string
 	syncPeriod                  time.Duration
 	webhookPort                 int
 	webhookCertDir              string
@@ -91,6 +97,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&watchNamespace, "namespace", "",
 		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.")
 
+	fs.StringSliceVar(&watchNamespaces, "watch-namespaces", nil,
+		"Comma-separated list of namespaces that the controller watches to reconcile cluster-api objects, in addition to the one set with --namespace. If both are unspecified, the controller watches for cluster-api objects across all namespaces.")
+
 	fs.StringVar(&profilerAddress, "profiler-address", "",
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)")
 
@@ -103,6 +112,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&kubeadmbootstrapcontrollers.DefaultTokenTTL, "bootstrap-token-ttl", 15*time.Minute,
 		"The amount of time the bootstrap token will be valid")
 
+	fs.Float64Var(&kubeadmbootstrapcontrollers.TokenRotationJitter, "bootstrap-token-rotation-jitter", 0.1,
+		"The maximum fraction by which the rotation of bootstrap tokens for MachinePools is moved back or forth from half of their TTL (e.g. 0.1 for +/- 10%)")
+
+	fs.IntVar(&maxActiveTokensPerCluster, "bootstrap-token-max-active-per-cluster", 0,
+		"The maximum number of active bootstrap tokens created in each workload cluster; no new token is created while the limit is reached. 0 means no limit")
+
+	fs.StringVar(&tokenSecretNamespace, "bootstrap-token-secret-namespace", metav1.NamespaceSystem,
+		"The namespace of the workload clusters where the bootstrap token Secrets are stored. Note that the API server only authenticates bootstrap tokens stored in kube-system")
+
+	fs.DurationVar(&tokenGCGracePeriod, "bootstrap-token-gc-grace-period", time.Hour,
+		"The amount of time after their expiration bootstrap tokens are deleted from workload clusters; requires the BootstrapTokenGarbageCollection feature gate")
+
 	fs.IntVar(&webhookPort, "webhook-port", 9443,
 		"Webhook Server port")
 
@@ -128,7 +149,7 @@ func main() {
 		}()
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	options := ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsBindAddr,
 		LeaderElection:     enableLeaderElection,
@@ -136,7 +157,6 @@ func main() {
 		LeaseDuration:      &leaderElectionLeaseDuration,
 		RenewDeadline:      &leaderElectionRenewDeadline,
 		RetryPeriod:        &leaderElectionRetryPeriod,
-		Namespace:          watchNamespace,
 		SyncPeriod:         &syncPeriod,
 		ClientDisableCacheFor: []client.Object{
 			&corev1.ConfigMap{},
@@ -144,7 +164,10 @@ func main() {
 		},
 		Port:    webhookPort,
 		CertDir: webhookCertDir,
-	})
+	}
+	multinamespace.SetManagerOptions(&options, append([]string{watchNamespace}, watchNamespaces...)...)
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -166,11 +189,25 @@ func main() {
 
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if err := (&kubeadmbootstrapcontrollers.KubeadmConfigReconciler{
-		Client: mgr.GetClient(),
+		Client:                    mgr.GetClient(),
+		MaxActiveTokensPerCluster: maxActiveTokensPerCluster,
+		TokenSecretNamespace:      tokenSecretNamespace,
 	}).SetupWithManager(ctx, mgr, concurrency(kubeadmConfigConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeadmConfig")
 		os.Exit(1)
 	}
+
+	// The token garbage collector always runs, given it deletes the orphaned tokens and keeps track of the active
+	// ones, but it only deletes the expired tokens when the BootstrapTokenGarbageCollection feature gate is enabled.
+	if err := (&kubeadmbootstrapcontrollers.TokenGarbageCollectorReconciler{
+		Client:               mgr.GetClient(),
+		DeleteExpiredTokens:  feature.Gates.Enabled(feature.BootstrapTokenGarbageCollection),
+		GracePeriod:          tokenGCGracePeriod,
+		TokenSecretNamespace: tokenSecretNamespace,
+	}).SetupWithManager(ctx, mgr, concurrency(1)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BootstrapTokenGarbageCollector")
+		os.Exit(1)
+	}
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
@@ -33,6 +33,11 @@ const (
 
 	// ClusterctlMoveLabelName can be set on CRDs that providers wish to move that are not part of a cluster
 	ClusterctlMoveLabelName = "clusterctl.cluster.x-k8s.io/move"
+
+	// ClusterctlMoveSourceUIDAnnotation is set by clusterctl move on the objects created in the target management cluster,
+	// and it stores the UID of the corresponding object in the source management cluster. It is used to detect objects
+	// already moved when re-running an interrupted move.
+	ClusterctlMoveSourceUIDAnnotation = "clusterctl.cluster.x-k8s.io/move-source-uid"
 )
 
 // ResourceLifecycle configures the lifecycle of a resource
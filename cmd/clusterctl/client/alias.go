@@ -48,6 +48,29 @@ type CertManagerUpgradePlan cluster.CertManagerUpgradePlan
 // Kubeconfig is a type that specifies inputs related to the actual kubeconfig.
 type Kubeconfig cluster.Kubeconfig
 
+// MoveProgress reports the progress of a move operation.
+type MoveProgress cluster.MoveProgress
+
+// MovePhase defines a phase of the move operation; it is a type alias, so it can be compared with MoveProgress.Phase.
+type MovePhase = cluster.MovePhase
+
+const (
+	// MovePhasePauseSource is the phase pausing the Clusters in the source management cluster.
+	MovePhasePauseSource = cluster.MovePhasePauseSource
+
+	// MovePhaseCreateTarget is the phase creating the objects in the target management cluster.
+	MovePhaseCreateTarget = cluster.MovePhaseCreateTarget
+
+	// MovePhaseDeleteSource is the phase deleting the objects from the source management cluster.
+	MovePhaseDeleteSource = cluster.MovePhaseDeleteSource
+
+	// MovePhaseResumeTarget is the phase resuming the Clusters in the target management cluster.
+	MovePhaseResumeTarget = cluster.MovePhaseResumeTarget
+)
+
+// CustomResourceDefinitionSummary describes a CRD of a provider, and how many custom resources of its Kind exist.
+type CustomResourceDefinitionSummary cluster.CustomResourceDefinitionSummary
+
 // Processor defines the methods necessary for creating a specific yaml
 // processor.
 type Processor yaml.Processor
@@ -21,8 +21,10 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -39,11 +41,24 @@ type DeleteOptions struct {
 	IncludeCRDs      bool
 }
 
+// CustomResourceDefinitionSummary describes a CRD of a provider, and how many custom resources of its Kind exist.
+type CustomResourceDefinitionSummary struct {
+	// Name of the CRD.
+	Name string
+
+	// CustomResources is the number of custom resources of the Kind defined by the CRD, across all the namespaces.
+	CustomResources int
+}
+
 // ComponentsClient has methods to work with provider components in the cluster.
 type ComponentsClient interface {
 	// Create creates the provider components in the management cluster.
 	Create(objs []unstructured.Unstructured) error
 
+	// ListCRDs returns the CRDs of the provider, with the number of custom resources which would be
+	// deleted together with each of them.
+	ListCRDs(provider clusterctlv1.Provider) ([]CustomResourceDefinitionSummary, error)
+
 	// Delete deletes the provider components from the management cluster.
 	// The operation is designed to prevent accidental deletion of user created objects, so
 	// it is required to explicitly opt-in for the deletion of the namespace where the provider components are hosted
@@ -211,6 +226,36 @@ func (p *providerComponents) Delete(options DeleteOptions) error {
 	return kerrors.NewAggregate(errList)
 }
 
+func (p *providerComponents) ListCRDs(provider clusterctlv1.Provider) ([]CustomResourceDefinitionSummary, error) {
+	c, err := p.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crdList, client.MatchingLabels{clusterv1.ProviderLabelName: provider.ManifestLabel()}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the CRDs of the %q provider", provider.Name)
+	}
+
+	summaries := make([]CustomResourceDefinitionSummary, 0, len(crdList.Items))
+	for _, crd := range crdList.Items {
+		summary := CustomResourceDefinitionSummary{Name: crd.Name}
+		for _, version := range crd.Spec.Versions {
+			if !version.Storage {
+				continue
+			}
+			groupVersion := schema.GroupVersion{Group: crd.Spec.Group, Version: version.Name}
+			objList, err := listObjByGVK(c, groupVersion.String(), crd.Spec.Names.ListKind, nil)
+			if err != nil {
+				return nil, err
+			}
+			summary.CustomResources = len(objList.Items)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
 // newComponentsClient returns a providerComponents.
 func newComponentsClient(proxy Proxy) *providerComponents {
 	return &providerComponents{
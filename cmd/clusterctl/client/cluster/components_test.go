@@ -23,6 +23,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -295,3 +296,85 @@ func Test_providerComponents_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_providerComponents_ListCRDs(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := clusterctlv1.Provider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-api",
+			Namespace: "capi-system",
+		},
+		ProviderName: "cluster-api",
+		Type:         string(clusterctlv1.CoreProviderType),
+	}
+
+	crd := func(name, kind string, labels map[string]string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "CustomResourceDefinition",
+				APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: labels,
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: clusterv1.GroupVersion.Group,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Kind:     kind,
+					ListKind: kind + "List",
+				},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{
+						Name:    "v1alpha3",
+						Storage: false,
+					},
+					{
+						Name:    clusterv1.GroupVersion.Version,
+						Storage: true,
+					},
+				},
+			},
+		}
+	}
+
+	initObjs := []client.Object{
+		// CRDs of the provider
+		crd("clusters.cluster.x-k8s.io", "Cluster", map[string]string{clusterv1.ProviderLabelName: provider.ManifestLabel()}),
+		crd("machines.cluster.x-k8s.io", "Machine", map[string]string{clusterv1.ProviderLabelName: provider.ManifestLabel()}),
+		// A CRD of another provider (should never be listed)
+		crd("machinepools.exp.cluster.x-k8s.io", "MachinePool", map[string]string{clusterv1.ProviderLabelName: "infrastructure-infra"}),
+		// Custom resources of the CRDs, across namespaces
+		&clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Cluster",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+				Name:      "cluster1",
+			},
+		},
+		&clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Cluster",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns2",
+				Name:      "cluster2",
+			},
+		},
+	}
+
+	proxy := test.NewFakeProxy().WithObjs(initObjs...)
+	c := newComponentsClient(proxy)
+
+	got, err := c.ListCRDs(provider)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(ConsistOf(
+		CustomResourceDefinitionSummary{Name: "clusters.cluster.x-k8s.io", CustomResources: 2},
+		CustomResourceDefinitionSummary{Name: "machines.cluster.x-k8s.io", CustomResources: 0},
+	))
+}
@@ -184,6 +184,14 @@ func (i *providerInstaller) Validate() error {
 		return err
 	}
 
+	// Gets the configuration of the providers in the install queue, so their metadata are read from the same repository
+	// their components are read from, e.g. a local repository replacing the provider repository.
+	providerInstanceConfigs := map[string]config.Provider{}
+	for _, components := range i.installQueue {
+		provider := components.InventoryObject()
+		providerInstanceConfigs[provider.InstanceName()] = components
+	}
+
 	// Checks if all the providers supports the same API Version of Cluster API (contract) of the corresponding management group.
 	providerInstanceContracts := map[string]string{}
 	for _, components := range i.installQueue {
@@ -192,13 +200,13 @@ func (i *providerInstaller) Validate() error {
 		// Gets the management group the providers belongs to, and then retrieve the API Version of Cluster API (contract)
 		// all the providers in the management group must support.
 		managementGroup := managementGroups.FindManagementGroupByProviderInstanceName(provider.InstanceName())
-		managementGroupContract, err := i.getProviderContract(providerInstanceContracts, managementGroup.CoreProvider)
+		managementGroupContract, err := i.getProviderContract(providerInstanceConfigs, providerInstanceContracts, managementGroup.CoreProvider)
 		if err != nil {
 			return err
 		}
 
 		// Gets the API Version of Cluster API (contract) the provider support and compare it with the  management group contract.
-		providerContract, err := i.getProviderContract(providerInstanceContracts, provider)
+		providerContract, err := i.getProviderContract(providerInstanceConfigs, providerInstanceContracts, provider)
 		if err != nil {
 			return err
 		}
@@ -210,7 +218,9 @@ func (i *providerInstaller) Validate() error {
 }
 
 // getProviderContract returns the API Version of Cluster API (contract) for a provider instance.
-func (i *providerInstaller) getProviderContract(providerInstanceContracts map[string]string, provider clusterctlv1.Provider) (string, error) {
+// The metadata of the provider instances in providerInstanceConfigs are read from the repository in their configuration,
+// the metadata of the other provider instances from the repository in the clusterctl configuration.
+func (i *providerInstaller) getProviderContract(providerInstanceConfigs map[string]config.Provider, providerInstanceContracts map[string]string, provider clusterctlv1.Provider) (string, error) {
 	// If the contract for the provider instance is already known, return it.
 	if contract, ok := providerInstanceContracts[provider.InstanceName()]; ok {
 		return contract, nil
@@ -219,9 +229,12 @@ func (i *providerInstaller) getProviderContract(providerInstanceContracts map[st
 	// Otherwise get the contract for the providers instance.
 
 	// Gets the providers metadata.
-	configRepository, err := i.configClient.Providers().Get(provider.ProviderName, provider.GetProviderType())
-	if err != nil {
-		return "", err
+	configRepository, ok := providerInstanceConfigs[provider.InstanceName()]
+	if !ok {
+		var err error
+		if configRepository, err = i.configClient.Providers().Get(provider.ProviderName, provider.GetProviderType()); err != nil {
+			return "", err
+		}
 	}
 
 	providerRepository, err := i.repositoryClientFactory(configRepository, i.configClient)
@@ -18,6 +18,7 @@ package cluster
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -29,14 +30,78 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/version"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ObjectMover defines methods for moving Cluster API objects to another management cluster.
 type ObjectMover interface {
-	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
-	Move(namespace string, toCluster Client, dryRun bool) error
+	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster;
+	// if progress is not nil, it gets called each time the move operation makes a step forward.
+	Move(namespace string, toCluster Client, dryRun bool, progress MoveProgressFunc) error
+}
+
+// MovePhase defines a phase of the move operation.
+type MovePhase string
+
+const (
+	// MovePhasePauseSource is the phase pausing the Clusters in the source management cluster.
+	MovePhasePauseSource MovePhase = "PauseSource"
+
+	// MovePhaseCreateTarget is the phase creating the objects in the target management cluster.
+	MovePhaseCreateTarget MovePhase = "CreateTarget"
+
+	// MovePhaseDeleteSource is the phase deleting the objects from the source management cluster.
+	MovePhaseDeleteSource MovePhase = "DeleteSource"
+
+	// MovePhaseResumeTarget is the phase resuming the Clusters in the target management cluster.
+	MovePhaseResumeTarget MovePhase = "ResumeTarget"
+)
+
+// MoveProgress reports the progress of a move operation.
+type MoveProgress struct {
+	// Phase is the current phase of the move operation.
+	Phase MovePhase `json:"phase"`
+
+	// Processed is the number of objects already processed in the current phase.
+	Processed int `json:"processed"`
+
+	// Total is the number of objects to be processed in the current phase.
+	Total int `json:"total"`
+}
+
+// MoveProgressFunc is a callback receiving the progress of a move operation.
+type MoveProgressFunc func(MoveProgress)
+
+// moveProgressReporter reports the progress of the move operation to a MoveProgressFunc, if any.
+type moveProgressReporter struct {
+	progressFunc MoveProgressFunc
+	current      MoveProgress
+}
+
+// startPhase reports the start of a phase processing total objects.
+func (r *moveProgressReporter) startPhase(phase MovePhase, total int) {
+	r.current = MoveProgress{Phase: phase, Total: total}
+	r.report()
+}
+
+// objectProcessed reports that an object has been processed in the current phase.
+func (r *moveProgressReporter) objectProcessed() {
+	r.current.Processed++
+	r.report()
+}
+
+// completePhase reports that all the objects in the current phase have been processed.
+func (r *moveProgressReporter) completePhase() {
+	r.current.Processed = r.current.Total
+	r.report()
+}
+
+func (r *moveProgressReporter) report() {
+	if r.progressFunc != nil {
+		r.progressFunc(r.current)
+	}
 }
 
 // objectMover implements the ObjectMover interface.
@@ -44,15 +109,17 @@ type objectMover struct {
 	fromProxy             Proxy
 	fromProviderInventory InventoryClient
 	dryRun                bool
+	progress              moveProgressReporter
 }
 
 // ensure objectMover implements the ObjectMover interface.
 var _ ObjectMover = &objectMover{}
 
-func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) error {
+func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool, progress MoveProgressFunc) error {
 	log := logf.Log
 	log.Info("Performing move...")
 	o.dryRun = dryRun
+	o.progress = moveProgressReporter{progressFunc: progress}
 	if o.dryRun {
 		log.Info("********************************************************")
 		log.Info("This is a dry-run move, will not perform any real action")
@@ -214,9 +281,11 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 
 	// Sets the pause field on the Cluster object in the source management cluster, so the controllers stop reconciling it.
 	log.V(1).Info("Pausing the source cluster")
+	o.progress.startPhase(MovePhasePauseSource, len(clusters))
 	if err := setClusterPause(o.fromProxy, clusters, true, o.dryRun); err != nil {
 		return err
 	}
+	o.progress.completePhase()
 
 	// Ensure all the expected target namespaces are in place before creating objects.
 	log.V(1).Info("Creating target namespaces, if missing")
@@ -231,8 +300,18 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 	// - then all the MachineSets, then all the Machines, etc.
 	moveSequence := getMoveSequence(graph)
 
+	// In case of dry run, print the objects that are going to be moved, in the same order they are going to be created
+	// in the target cluster.
+	if o.dryRun {
+		log.Info("Objects to be moved, in dependency order", "Groups", len(moveSequence.groups))
+		for _, line := range moveSequence.describe() {
+			log.Info(line)
+		}
+	}
+
 	// Create all objects group by group, ensuring all the ownerReferences are re-created.
 	log.Info("Creating objects in the target cluster")
+	o.progress.startPhase(MovePhaseCreateTarget, moveSequence.len())
 	for groupIndex := 0; groupIndex < len(moveSequence.groups); groupIndex++ {
 		if err := o.createGroup(moveSequence.getGroup(groupIndex), toProxy); err != nil {
 			return err
@@ -241,6 +320,7 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 
 	// Delete all objects group by group in reverse order.
 	log.Info("Deleting objects from the source cluster")
+	o.progress.startPhase(MovePhaseDeleteSource, moveSequence.lenNonGlobal())
 	for groupIndex := len(moveSequence.groups) - 1; groupIndex >= 0; groupIndex-- {
 		if err := o.deleteGroup(moveSequence.getGroup(groupIndex)); err != nil {
 			return err
@@ -249,9 +329,11 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 
 	// Reset the pause field on the Cluster object in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target cluster")
+	o.progress.startPhase(MovePhaseResumeTarget, len(clusters))
 	if err := setClusterPause(toProxy, clusters, false, o.dryRun); err != nil {
 		return err
 	}
+	o.progress.completePhase()
 
 	return nil
 }
@@ -262,6 +344,30 @@ type moveSequence struct {
 	nodesMap map[*node]empty
 }
 
+// describe returns a line for each node in the move sequence, in the same order nodes are going to be moved;
+// nodes in the same group are sorted by kind, namespace and name.
+func (s *moveSequence) describe() []string {
+	lines := []string{}
+	for groupIndex, group := range s.groups {
+		nodes := make([]*node, len(group))
+		copy(nodes, group)
+		sort.Slice(nodes, func(i, j int) bool {
+			if nodes[i].identity.Kind != nodes[j].identity.Kind {
+				return nodes[i].identity.Kind < nodes[j].identity.Kind
+			}
+			if nodes[i].identity.Namespace != nodes[j].identity.Namespace {
+				return nodes[i].identity.Namespace < nodes[j].identity.Namespace
+			}
+			return nodes[i].identity.Name < nodes[j].identity.Name
+		})
+
+		for _, n := range nodes {
+			lines = append(lines, fmt.Sprintf("[%d] %s, %s/%s", groupIndex+1, n.identity.GroupVersionKind(), n.identity.Namespace, n.identity.Name))
+		}
+	}
+	return lines
+}
+
 // moveGroup defines is a list of nodes read from the object graph that can be moved in parallel.
 type moveGroup []*node
 
@@ -279,6 +385,22 @@ func (s *moveSequence) hasNode(n *node) bool {
 	return ok
 }
 
+// len returns the number of nodes in the move sequence.
+func (s *moveSequence) len() int {
+	return len(s.nodesMap)
+}
+
+// lenNonGlobal returns the number of nodes in the move sequence that are not cluster-wide.
+func (s *moveSequence) lenNonGlobal() int {
+	l := 0
+	for n := range s.nodesMap {
+		if !n.isGlobal {
+			l++
+		}
+	}
+	return l
+}
+
 func (s *moveSequence) getGroup(i int) moveGroup {
 	return s.groups[i]
 }
@@ -492,7 +614,9 @@ func (o *objectMover) createGroup(group moveGroup, toProxy Proxy) error {
 		})
 		if err != nil {
 			errList = append(errList, err)
+			continue
 		}
+		o.progress.objectProcessed()
 	}
 
 	if len(errList) > 0 {
@@ -536,6 +660,14 @@ func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy) erro
 	// Removes current OwnerReferences
 	obj.SetOwnerReferences(nil)
 
+	// Records the UID of the source object, so it is possible to detect the object was already moved in case of re-run.
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterctlv1.ClusterctlMoveSourceUIDAnnotation] = string(nodeToCreate.identity.UID)
+	obj.SetAnnotations(annotations)
+
 	// Recreate all the OwnerReferences using the newUID of the owner nodes.
 	if len(nodeToCreate.owners) > 0 {
 		ownerRefs := []metav1.OwnerReference{}
@@ -571,10 +703,6 @@ func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy) erro
 				obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 		}
 
-		// If the object already exists, try to update it.
-		// Nb. This should not happen, but it is supported to make move more resilient to unexpected interrupt/restarts of the move process.
-		log.V(5).Info("Object already exists, updating", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
-
 		// Retrieve the UID and the resource version for the update.
 		existingTargetObj := &unstructured.Unstructured{}
 		existingTargetObj.SetAPIVersion(obj.GetAPIVersion())
@@ -584,6 +712,17 @@ func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy) erro
 				existingTargetObj.GroupVersionKind(), existingTargetObj.GetNamespace(), existingTargetObj.GetName())
 		}
 
+		// If the object was already created by a previous, interrupted, move operation, skip it.
+		if existingTargetObj.GetAnnotations()[clusterctlv1.ClusterctlMoveSourceUIDAnnotation] == string(nodeToCreate.identity.UID) {
+			log.V(5).Info("Object already moved, skipping", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
+			nodeToCreate.newUID = existingTargetObj.GetUID()
+			return nil
+		}
+
+		// If the object already exists, try to update it.
+		// Nb. This should not happen, but it is supported to make move more resilient to unexpected interrupt/restarts of the move process.
+		log.V(5).Info("Object already exists, updating", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
+
 		obj.SetUID(existingTargetObj.GetUID())
 		obj.SetResourceVersion(existingTargetObj.GetResourceVersion())
 		if err := cTo.Update(ctx, obj); err != nil {
@@ -618,7 +757,9 @@ func (o *objectMover) deleteGroup(group moveGroup) error {
 
 		if err != nil {
 			errList = append(errList, err)
+			continue
 		}
+		o.progress.objectProcessed()
 	}
 
 	return kerrors.NewAggregate(errList)
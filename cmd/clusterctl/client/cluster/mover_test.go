@@ -17,6 +17,7 @@ limitations under the License.
 package cluster
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -471,6 +472,49 @@ func Test_getMoveSequence(t *testing.T) {
 	}
 }
 
+func Test_moveSequence_describe(t *testing.T) {
+	// NB. we are testing the move and move sequence using the same set of moveTests, but checking the results at different stages of the move process
+	for _, tt := range moveTests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+
+			// Get all the types to be considered for discovery
+			err := getFakeDiscoveryTypes(graph)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// trigger discovery the content of the source cluster
+			g.Expect(graph.Discovery("")).To(Succeed())
+
+			// NB. test objects have an UID in the same format used for describing nodes.
+			wantLines := []string{}
+			for i, wantGroup := range tt.wantMoveGroups {
+				groupLines := []string{}
+				for _, uid := range wantGroup {
+					groupLines = append(groupLines, fmt.Sprintf("[%d] %s", i+1, uid))
+				}
+				wantLines = append(wantLines, groupLines...)
+			}
+
+			gotLines := getMoveSequence(graph).describe()
+			g.Expect(gotLines).To(ConsistOf(wantLines))
+
+			// Lines must be in move order, so group indexes are never decreasing.
+			for i := 1; i < len(gotLines); i++ {
+				g.Expect(groupIndexOf(gotLines[i])).To(BeNumerically(">=", groupIndexOf(gotLines[i-1])))
+			}
+		})
+	}
+}
+
+func groupIndexOf(line string) int {
+	var groupIndex int
+	_, _ = fmt.Sscanf(line, "[%d]", &groupIndex)
+	return groupIndex
+}
+
 func Test_objectMover_move_dryRun(t *testing.T) {
 	// NB. we are testing the move and move sequence using the same set of moveTests, but checking the results at different stages of the move process
 	for _, tt := range moveTests {
@@ -545,6 +589,52 @@ func Test_objectMover_move_dryRun(t *testing.T) {
 	}
 }
 
+func Test_objectMover_move_progress(t *testing.T) {
+	g := NewWithT(t)
+
+	// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+	graph := getObjectGraphWithObjs(test.NewFakeCluster("ns1", "foo").Objs())
+
+	// Get all the types to be considered for discovery
+	g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+
+	// trigger discovery the content of the source cluster
+	g.Expect(graph.Discovery("")).To(Succeed())
+
+	// gets a fakeProxy to an empty cluster with all the required CRDs
+	toProxy := getFakeProxyWithCRDs()
+
+	// Run move capturing the progress callback invocations.
+	var got []MoveProgress
+	mover := objectMover{
+		fromProxy: graph.proxy,
+		progress: moveProgressReporter{
+			progressFunc: func(p MoveProgress) {
+				got = append(got, p)
+			},
+		},
+	}
+	g.Expect(mover.move(graph, toProxy)).To(Succeed())
+
+	// The cluster is moved together with its two secrets and its infrastructure cluster.
+	g.Expect(got).To(Equal([]MoveProgress{
+		{Phase: MovePhasePauseSource, Processed: 0, Total: 1},
+		{Phase: MovePhasePauseSource, Processed: 1, Total: 1},
+		{Phase: MovePhaseCreateTarget, Processed: 0, Total: 4},
+		{Phase: MovePhaseCreateTarget, Processed: 1, Total: 4},
+		{Phase: MovePhaseCreateTarget, Processed: 2, Total: 4},
+		{Phase: MovePhaseCreateTarget, Processed: 3, Total: 4},
+		{Phase: MovePhaseCreateTarget, Processed: 4, Total: 4},
+		{Phase: MovePhaseDeleteSource, Processed: 0, Total: 4},
+		{Phase: MovePhaseDeleteSource, Processed: 1, Total: 4},
+		{Phase: MovePhaseDeleteSource, Processed: 2, Total: 4},
+		{Phase: MovePhaseDeleteSource, Processed: 3, Total: 4},
+		{Phase: MovePhaseDeleteSource, Processed: 4, Total: 4},
+		{Phase: MovePhaseResumeTarget, Processed: 0, Total: 1},
+		{Phase: MovePhaseResumeTarget, Processed: 1, Total: 1},
+	}))
+}
+
 func Test_objectMover_move(t *testing.T) {
 	// NB. we are testing the move and move sequence using the same set of moveTests, but checking the results at different stages of the move process
 	for _, tt := range moveTests {
@@ -620,6 +710,105 @@ func Test_objectMover_move(t *testing.T) {
 	}
 }
 
+func Test_objectMover_move_resume(t *testing.T) {
+	// NB. we are testing the move and move sequence using the same set of moveTests, but checking the results at different stages of the move process
+	for _, tt := range moveTests {
+		if tt.wantErr {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+
+			// Get all the types to be considered for discovery
+			g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+
+			// trigger discovery the content of the source cluster
+			g.Expect(graph.Discovery("")).To(Succeed())
+
+			// gets a fakeProxy to an empty cluster with all the required CRDs
+			toProxy := getFakeProxyWithCRDs()
+
+			mover := objectMover{
+				fromProxy: graph.proxy,
+			}
+
+			// Simulate a move interrupted while deleting objects from the source cluster: all the objects are
+			// created in the target cluster, but only the last group is deleted from the source cluster.
+			moveSequence := getMoveSequence(graph)
+			for groupIndex := 0; groupIndex < len(moveSequence.groups); groupIndex++ {
+				g.Expect(mover.createGroup(moveSequence.getGroup(groupIndex), toProxy)).To(Succeed())
+			}
+			g.Expect(mover.deleteGroup(moveSequence.getGroup(len(moveSequence.groups) - 1))).To(Succeed())
+
+			csFrom, err := graph.proxy.NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			csTo, err := toProxy.NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// Record the resource versions of the objects already moved.
+			resourceVersions := map[string]string{}
+			for _, node := range graph.uidToNode {
+				oTo := &unstructured.Unstructured{}
+				oTo.SetAPIVersion(node.identity.APIVersion)
+				oTo.SetKind(node.identity.Kind)
+				key := client.ObjectKey{Namespace: node.identity.Namespace, Name: node.identity.Name}
+				if err := csTo.Get(ctx, key, oTo); err == nil {
+					resourceVersions[string(node.identity.UID)] = oTo.GetResourceVersion()
+				}
+			}
+
+			// Re-run move, starting from a new discovery of the source cluster.
+			resumeGraph := newObjectGraph(graph.proxy)
+			g.Expect(getFakeDiscoveryTypes(resumeGraph)).To(Succeed())
+			g.Expect(resumeGraph.Discovery("")).To(Succeed())
+			g.Expect(mover.move(resumeGraph, toProxy)).To(Succeed())
+
+			for _, node := range graph.uidToNode {
+				key := client.ObjectKey{
+					Namespace: node.identity.Namespace,
+					Name:      node.identity.Name,
+				}
+
+				// objects are deleted from the source cluster
+				oFrom := &unstructured.Unstructured{}
+				oFrom.SetAPIVersion(node.identity.APIVersion)
+				oFrom.SetKind(node.identity.Kind)
+
+				err := csFrom.Get(ctx, key, oFrom)
+				if err == nil {
+					if oFrom.GetNamespace() != "" {
+						t.Errorf("%v not deleted in source cluster", key)
+						continue
+					}
+				} else if !apierrors.IsNotFound(err) {
+					t.Errorf("error = %v when checking for %v deleted in source cluster", err, key)
+					continue
+				}
+
+				// objects are created in the target cluster
+				oTo := &unstructured.Unstructured{}
+				oTo.SetAPIVersion(node.identity.APIVersion)
+				oTo.SetKind(node.identity.Kind)
+
+				if err := csTo.Get(ctx, key, oTo); err != nil {
+					t.Errorf("error = %v when checking for %v created in target cluster", err, key)
+					continue
+				}
+				g.Expect(oTo.GetAnnotations()).To(HaveKeyWithValue(clusterctlv1.ClusterctlMoveSourceUIDAnnotation, string(node.identity.UID)))
+
+				// objects already moved are not created again (Clusters are changed when resuming reconciliation).
+				if node.identity.Kind != "Cluster" {
+					g.Expect(oTo.GetResourceVersion()).To(Equal(resourceVersions[string(node.identity.UID)]), "%v was changed in target cluster", key)
+				}
+			}
+		})
+	}
+}
+
 func Test_objectMover_checkProvisioningCompleted(t *testing.T) {
 	type fields struct {
 		objs []client.Object
@@ -231,6 +231,11 @@ func InjectKubeconfigPaths(paths []string) ProxyOption {
 	}
 }
 
+// NewProxy returns a Proxy to the cluster defined by the kubeconfig.
+func NewProxy(kubeconfig Kubeconfig, opts ...ProxyOption) Proxy {
+	return newProxy(kubeconfig, opts...)
+}
+
 func newProxy(kubeconfig Kubeconfig, opts ...ProxyOption) Proxy {
 	// If a kubeconfig file isn't provided, find one in the standard locations.
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
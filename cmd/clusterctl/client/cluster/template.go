@@ -18,12 +18,15 @@ package cluster
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/github"
 	"github.com/pkg/errors"
@@ -42,6 +45,10 @@ type TemplateClient interface {
 
 	// GetFromURL returns a workload cluster template from the given URL.
 	GetFromURL(templateURL, targetNamespace string, listVariablesOnly bool) (repository.Template, error)
+
+	// GetFromHTTPSURL returns a workload cluster template downloaded from the given HTTPS URL; if a SHA256 checksum
+	// is provided, the downloaded template must match it.
+	GetFromHTTPSURL(templateURL, checksum, targetNamespace string, listVariablesOnly bool) (repository.Template, error)
 }
 
 // templateClient implements TemplateClient.
@@ -49,6 +56,7 @@ type templateClient struct {
 	proxy               Proxy
 	configClient        config.Client
 	gitHubClientFactory func(configVariablesClient config.VariablesClient) (*github.Client, error)
+	httpClient          *http.Client
 	processor           yaml.Processor
 }
 
@@ -67,6 +75,7 @@ func newTemplateClient(input TemplateClientInput) *templateClient {
 		proxy:               input.proxy,
 		configClient:        input.configClient,
 		gitHubClientFactory: getGitHubClient,
+		httpClient:          &http.Client{Timeout: 30 * time.Second},
 		processor:           input.processor,
 	}
 }
@@ -127,6 +136,68 @@ func (t *templateClient) GetFromURL(templateURL, targetNamespace string, listVar
 	})
 }
 
+func (t *templateClient) GetFromHTTPSURL(templateURL, checksum, targetNamespace string, listVariablesOnly bool) (repository.Template, error) {
+	if templateURL == "" {
+		return nil, errors.New("invalid GetFromHTTPSURL operation: missing templateURL value")
+	}
+
+	content, err := t.getHTTPSContent(templateURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid GetFromHTTPSURL operation")
+	}
+
+	if checksum != "" {
+		if err := verifyChecksum(content, checksum); err != nil {
+			return nil, errors.Wrapf(err, "invalid GetFromHTTPSURL operation: failed to verify %q", templateURL)
+		}
+	}
+
+	return repository.NewTemplate(repository.TemplateInput{
+		RawArtifact:           content,
+		ConfigVariablesClient: t.configClient.Variables(),
+		Processor:             t.processor,
+		TargetNamespace:       targetNamespace,
+		ListVariablesOnly:     listVariablesOnly,
+	})
+}
+
+func (t *templateClient) getHTTPSContent(templateURL string) ([]byte, error) {
+	rURL, err := url.Parse(templateURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %q", templateURL)
+	}
+	if rURL.Scheme != "https" {
+		return nil, errors.Errorf("unable to read content from %q. Only https URLs are supported", templateURL)
+	}
+
+	resp, err := t.httpClient.Get(rURL.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q", templateURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get %q: unexpected status code %d", templateURL, resp.StatusCode)
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q", templateURL)
+	}
+	return content, nil
+}
+
+// verifyChecksum checks the SHA256 checksum of content, expressed as an hex string
+// optionally prefixed by "sha256:", matches the expected one.
+func verifyChecksum(content []byte, checksum string) error {
+	expected := strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return errors.Errorf("checksum mismatch: expected sha256 %s, got %s", expected, actual)
+	}
+	return nil
+}
+
 func (t *templateClient) getURLContent(templateURL string) ([]byte, error) {
 	rURL, err := url.Parse(templateURL)
 	if err != nil {
@@ -17,10 +17,13 @@ limitations under the License.
 package cluster
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -373,6 +376,119 @@ func Test_templateClient_GetFromURL(t *testing.T) {
 	}
 }
 
+func Test_templateClient_GetFromHTTPSURL(t *testing.T) {
+	g := NewWithT(t)
+
+	configClient, err := config.New("", config.InjectReader(test.NewFakeReader()))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cluster-template.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, template)
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	sum := sha256.Sum256([]byte(template))
+	checksum := hex.EncodeToString(sum[:])
+
+	type args struct {
+		templateURL       string
+		checksum          string
+		targetNamespace   string
+		listVariablesOnly bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Get from HTTPS URL",
+			args: args{
+				templateURL:       server.URL + "/cluster-template.yaml",
+				targetNamespace:   "",
+				listVariablesOnly: false,
+			},
+			want:    template,
+			wantErr: false,
+		},
+		{
+			name: "Get from HTTPS URL with matching checksum",
+			args: args{
+				templateURL:       server.URL + "/cluster-template.yaml",
+				checksum:          checksum,
+				targetNamespace:   "ns1",
+				listVariablesOnly: false,
+			},
+			want:    template,
+			wantErr: false,
+		},
+		{
+			name: "Get from HTTPS URL with matching prefixed checksum",
+			args: args{
+				templateURL:       server.URL + "/cluster-template.yaml",
+				checksum:          "sha256:" + checksum,
+				targetNamespace:   "",
+				listVariablesOnly: true,
+			},
+			want:    template,
+			wantErr: false,
+		},
+		{
+			name: "Fails if the checksum does not match",
+			args: args{
+				templateURL: server.URL + "/cluster-template.yaml",
+				checksum:    "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fails if the template does not exist",
+			args: args{
+				templateURL: server.URL + "/does-not-exist.yaml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fails if the URL is not https",
+			args: args{
+				templateURL: "http://example.com/cluster-template.yaml",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			processor := yaml.NewSimpleProcessor()
+			c := newTemplateClient(TemplateClientInput{nil, configClient, processor})
+			// override the http client so the test server certificate is trusted
+			c.httpClient = server.Client()
+
+			got, err := c.GetFromHTTPSURL(tt.args.templateURL, tt.args.checksum, tt.args.targetNamespace, tt.args.listVariablesOnly)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			wantTemplate, err := repository.NewTemplate(repository.TemplateInput{
+				RawArtifact:           []byte(tt.want),
+				ConfigVariablesClient: configClient.Variables(),
+				Processor:             processor,
+				TargetNamespace:       tt.args.targetNamespace,
+				ListVariablesOnly:     tt.args.listVariablesOnly,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(wantTemplate))
+		})
+	}
+}
+
 func mustParseURL(rawURL string) *url.URL {
 	rURL, err := url.Parse(rawURL)
 	if err != nil {
@@ -17,6 +17,8 @@ limitations under the License.
 package cluster
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/version"
@@ -48,6 +50,10 @@ type UpgradePlan struct {
 	Contract     string
 	CoreProvider clusterctlv1.Provider
 	Providers    []UpgradeItem
+
+	// IncompatibleProviders lists the providers without a release supporting the Contract, when the plan moves the
+	// management group to a different contract; such a plan cannot be applied.
+	IncompatibleProviders []string
 }
 
 // UpgradeRef returns a string identifying the upgrade plan; this string is derived by the core provider which is
@@ -56,14 +62,31 @@ func (u *UpgradePlan) UpgradeRef() string {
 	return u.CoreProvider.InstanceName()
 }
 
-// isPartialUpgrade returns true if at least one upgradeItem in the plan does not have a target version.
-func (u *UpgradePlan) isPartialUpgrade() bool {
+// checkContractBoundary returns an error if the upgrade plan moves the management group from the currentContract
+// to a different API Version of Cluster API (contract), but not all the providers have a release supporting the target contract;
+// applying such a plan would leave the management group with providers supporting incompatible contracts.
+func (u *UpgradePlan) checkContractBoundary(currentContract string) error {
+	if incompatible := u.incompatibleProviders(currentContract); len(incompatible) > 0 {
+		return errors.Errorf("unable to upgrade the management group %s from the %s to the %s API Version of Cluster API (contract): the following providers do not have a release supporting the %[3]s contract: %s",
+			u.UpgradeRef(), currentContract, u.Contract, strings.Join(incompatible, ", "))
+	}
+	return nil
+}
+
+// incompatibleProviders returns the providers that do not have a release supporting the contract of the upgrade plan,
+// if it differs from the currentContract.
+func (u *UpgradePlan) incompatibleProviders(currentContract string) []string {
+	if u.Contract == currentContract {
+		return nil
+	}
+
+	var incompatible []string
 	for _, i := range u.Providers {
 		if i.NextVersion == "" {
-			return true
+			incompatible = append(incompatible, i.InstanceName())
 		}
 	}
-	return false
+	return incompatible
 }
 
 // UpgradeItem defines a possible upgrade target for a provider in the management group.
@@ -130,12 +153,10 @@ func (u *providerUpgrader) Plan() ([]UpgradePlan, error) {
 				return nil, err
 			}
 
-			// If the upgrade plan is partial (at least one upgradeItem in the plan does not have a target version) and
-			// the upgrade plan requires a change of the contract for this management group, then drop it
-			// (all the provider in a management group are required to change contract at the same time).
-			if upgradePlan.isPartialUpgrade() && coreUpgradeInfo.currentContract != contract {
-				continue
-			}
+			// If the upgrade plan requires a change of the contract for this management group, but it is partial
+			// (at least one upgradeItem in the plan does not have a target version), then report the providers
+			// blocking it, given all the provider in a management group are required to change contract at the same time.
+			upgradePlan.IncompatibleProviders = upgradePlan.incompatibleProviders(coreUpgradeInfo.currentContract)
 
 			ret = append(ret, *upgradePlan)
 		}
@@ -164,6 +185,15 @@ func (u *providerUpgrader) ApplyPlan(coreProvider clusterctlv1.Provider, contrac
 		return err
 	}
 
+	// Ensures all the providers in the management group are going to support the target contract.
+	coreContract, err := u.getProviderContractByVersion(managementGroup.CoreProvider, managementGroup.CoreProvider.Version)
+	if err != nil {
+		return err
+	}
+	if err := upgradePlan.checkContractBoundary(coreContract); err != nil {
+		return err
+	}
+
 	// Do the upgrade
 	return u.doUpgrade(upgradePlan)
 }
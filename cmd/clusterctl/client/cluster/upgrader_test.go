@@ -744,7 +744,21 @@ func Test_providerUpgrader_Plan(t *testing.T) {
 						},
 					},
 				},
-				// the upgrade plan with the latest releases in the next contract should be dropped because all the provider are required to change the contract at the same time
+				{ // one upgrade plan with the latest releases in the next contract, reporting the infra provider as incompatible because all the provider are required to change the contract at the same time
+					Contract:     test.NextCAPIContractNotSupported,
+					CoreProvider: fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system", ""),
+					Providers: []UpgradeItem{
+						{
+							Provider:    fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system", ""),
+							NextVersion: "v2.0.0",
+						},
+						{
+							Provider:    fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system", ""),
+							NextVersion: "",
+						},
+					},
+					IncompatibleProviders: []string{"infra-system/infrastructure-infra"},
+				},
 			},
 			wantErr: false,
 		},
@@ -1246,3 +1260,153 @@ func Test_providerUpgrader_createCustomPlan(t *testing.T) {
 		})
 	}
 }
+
+func Test_providerUpgrader_ApplyPlan(t *testing.T) {
+	type fields struct {
+		reader     config.Reader
+		repository map[string]repository.Repository
+		proxy      Proxy
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		contract string
+		wantErr  string
+	}{
+		{
+			name: "fail if upgrade to the current contract would leave the infra provider on the previous contract",
+			fields: fields{
+				// config for two providers
+				reader: test.NewFakeReader().
+					WithProvider("cluster-api", clusterctlv1.CoreProviderType, "https://somewhere.com").
+					WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com"),
+				repository: map[string]repository.Repository{
+					"cluster-api": test.NewFakeRepository().
+						WithVersions("v1.0.0", "v2.0.0").
+						WithMetadata("v2.0.0", &clusterctlv1.Metadata{
+							ReleaseSeries: []clusterctlv1.ReleaseSeries{
+								{Major: 1, Minor: 0, Contract: test.PreviousCAPIContractNotSupported},
+								{Major: 2, Minor: 0, Contract: test.CurrentCAPIContract},
+							},
+						}),
+					"infrastructure-infra": test.NewFakeRepository().
+						WithVersions("v2.0.0", "v2.0.1"). // no releases available for the current contract
+						WithMetadata("v2.0.1", &clusterctlv1.Metadata{
+							ReleaseSeries: []clusterctlv1.ReleaseSeries{
+								{Major: 2, Minor: 0, Contract: test.PreviousCAPIContractNotSupported},
+							},
+						}),
+				},
+				// two providers existing in the cluster
+				proxy: test.NewFakeProxy().
+					WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system", "").
+					WithProviderInventory("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system", ""),
+			},
+			contract: test.CurrentCAPIContract,
+			wantErr:  "the following providers do not have a release supporting the " + test.CurrentCAPIContract + " contract: infra-system/infra",
+		},
+		{
+			name: "fail if upgrade to the next contract", // not supported in current clusterctl release.
+			fields: fields{
+				reader: test.NewFakeReader().
+					WithProvider("cluster-api", clusterctlv1.CoreProviderType, "https://somewhere.com"),
+				repository: map[string]repository.Repository{
+					"cluster-api": test.NewFakeRepository().
+						WithVersions("v1.0.0", "v2.0.0").
+						WithMetadata("v2.0.0", &clusterctlv1.Metadata{
+							ReleaseSeries: []clusterctlv1.ReleaseSeries{
+								{Major: 1, Minor: 0, Contract: test.CurrentCAPIContract},
+								{Major: 2, Minor: 0, Contract: test.NextCAPIContractNotSupported},
+							},
+						}),
+				},
+				proxy: test.NewFakeProxy().
+					WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system", ""),
+			},
+			contract: test.NextCAPIContractNotSupported,
+			wantErr:  "current version of clusterctl could only upgrade to " + test.CurrentCAPIContract + " contract",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			configClient, _ := config.New("", config.InjectReader(tt.fields.reader))
+
+			u := &providerUpgrader{
+				configClient: configClient,
+				repositoryClientFactory: func(provider config.Provider, configClient config.Client, options ...repository.Option) (repository.Client, error) {
+					return repository.New(provider, configClient, repository.InjectRepository(tt.fields.repository[provider.ManifestLabel()]))
+				},
+				providerInventory: newInventoryClient(tt.fields.proxy, nil),
+			}
+			err := u.ApplyPlan(fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "", "cluster-api-system", ""), tt.contract)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+		})
+	}
+}
+
+func TestUpgradePlan_checkContractBoundary(t *testing.T) {
+	core := fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system", "")
+	infra := fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system", "")
+
+	tests := []struct {
+		name            string
+		plan            UpgradePlan
+		currentContract string
+		wantErr         bool
+	}{
+		{
+			name: "pass if all the providers can be upgraded to the next contract",
+			plan: UpgradePlan{
+				Contract:     test.CurrentCAPIContract,
+				CoreProvider: core,
+				Providers: []UpgradeItem{
+					{Provider: core, NextVersion: "v2.0.0"},
+					{Provider: infra, NextVersion: "v3.0.0"},
+				},
+			},
+			currentContract: test.PreviousCAPIContractNotSupported,
+			wantErr:         false,
+		},
+		{
+			name: "pass if a provider is not upgraded within the current contract",
+			plan: UpgradePlan{
+				Contract:     test.CurrentCAPIContract,
+				CoreProvider: core,
+				Providers: []UpgradeItem{
+					{Provider: core, NextVersion: "v1.0.1"},
+					{Provider: infra, NextVersion: ""},
+				},
+			},
+			currentContract: test.CurrentCAPIContract,
+			wantErr:         false,
+		},
+		{
+			name: "fail if a provider does not have a release for the next contract",
+			plan: UpgradePlan{
+				Contract:     test.CurrentCAPIContract,
+				CoreProvider: core,
+				Providers: []UpgradeItem{
+					{Provider: core, NextVersion: "v2.0.0"},
+					{Provider: infra, NextVersion: ""},
+				},
+			},
+			currentContract: test.PreviousCAPIContractNotSupported,
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.plan.checkContractBoundary(tt.currentContract)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
@@ -17,26 +17,66 @@ limitations under the License.
 package cluster
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
 	utilkubeconfig "sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// kubeconfigTokenGroupPrefix is the prefix of the group of a bootstrap token created for a temporary kubeconfig;
+	// each token has its own group, so the ClusterRoleBinding of a token does not grant permissions to the others.
+	kubeconfigTokenGroupPrefix = "system:bootstrappers:clusterctl:kubeconfig:"
+
+	// kubeconfigTokenClusterRoleBindingNamePrefix is the prefix of the name of the ClusterRoleBinding granting
+	// a ClusterRole to the group of a bootstrap token created for a temporary kubeconfig.
+	kubeconfigTokenClusterRoleBindingNamePrefix = "clusterctl:kubeconfig-token:"
+
+	// kubeconfigTokenLabel is the label set on the ClusterRoleBindings of the bootstrap tokens created for
+	// temporary kubeconfigs, with the token ID as value.
+	kubeconfigTokenLabel = "clusterctl.cluster.x-k8s.io/kubeconfig-token"
+
+	// kubeconfigTokenExpirationAnnotation is the annotation set on the ClusterRoleBinding of a bootstrap token
+	// created for a temporary kubeconfig to the expiration of the token, so the binding is deleted once expired.
+	kubeconfigTokenExpirationAnnotation = "clusterctl.cluster.x-k8s.io/kubeconfig-token-expiration"
+
+	// kubeconfigTokenDescription is the description of the bootstrap tokens created for temporary kubeconfigs.
+	kubeconfigTokenDescription = "token generated by clusterctl get kubeconfig"
+)
+
 // WorkloadCluster has methods for fetching kubeconfig of workload cluster from management cluster.
 type WorkloadCluster interface {
 	// GetKubeconfig returns the kubeconfig of the workload cluster.
 	GetKubeconfig(workloadClusterName string, namespace string) (string, error)
+
+	// GetKubeconfigWithTTL returns a kubeconfig of the workload cluster using a bootstrap token which
+	// expires after the given TTL instead of the admin credentials; the token is granted the given ClusterRole.
+	GetKubeconfigWithTTL(workloadClusterName string, namespace string, ttl time.Duration, clusterRole string) (string, error)
 }
 
 // workloadCluster implements WorkloadCluster.
 type workloadCluster struct {
 	proxy Proxy
+
+	// newWorkloadClient returns a client for the workload cluster with the given kubeconfig.
+	newWorkloadClient func(kubeconfig []byte) (client.Client, error)
 }
 
 // newWorkloadCluster returns a workloadCluster.
 func newWorkloadCluster(proxy Proxy) *workloadCluster {
 	return &workloadCluster{
-		proxy: proxy,
+		proxy:             proxy,
+		newWorkloadClient: newWorkloadClient,
 	}
 }
 
@@ -56,3 +96,151 @@ func (p *workloadCluster) GetKubeconfig(workloadClusterName string, namespace st
 	}
 	return string(dataBytes), nil
 }
+
+func (p *workloadCluster) GetKubeconfigWithTTL(workloadClusterName string, namespace string, ttl time.Duration, clusterRole string) (string, error) {
+	if ttl <= 0 {
+		return "", errors.Errorf("invalid TTL %s, it must be greater than zero", ttl)
+	}
+	if clusterRole == "" {
+		return "", errors.New("the ClusterRole to grant to the temporary kubeconfig must be set")
+	}
+
+	adminKubeconfig, err := p.GetKubeconfig(workloadClusterName, namespace)
+	if err != nil {
+		return "", err
+	}
+	config, err := clientcmd.Load([]byte(adminKubeconfig))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the kubeconfig of the workload cluster %q", workloadClusterName)
+	}
+	kubeContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return "", errors.Errorf("the kubeconfig of the workload cluster %q has no current context", workloadClusterName)
+	}
+
+	c, err := p.newWorkloadClient([]byte(adminKubeconfig))
+	if err != nil {
+		return "", err
+	}
+	if err := deleteExpiredKubeconfigTokenClusterRoleBindings(c); err != nil {
+		return "", err
+	}
+
+	token, err := bootstraputil.GenerateBootstrapToken()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate a bootstrap token")
+	}
+	substrs := bootstraputil.BootstrapTokenRegexp.FindStringSubmatch(token)
+	if len(substrs) != 3 {
+		return "", errors.Errorf("the bootstrap token %q was not of the form %q", token, bootstrapapi.BootstrapTokenPattern)
+	}
+	tokenID, tokenSecret := substrs[1], substrs[2]
+	expiration := time.Now().UTC().Add(ttl)
+
+	if err := createKubeconfigTokenClusterRoleBinding(c, tokenID, clusterRole, expiration); err != nil {
+		return "", err
+	}
+	if err := createKubeconfigToken(c, tokenID, tokenSecret, expiration); err != nil {
+		return "", err
+	}
+
+	// Replace the admin credentials with the token, keeping only the current context.
+	userName := fmt.Sprintf("%s-token", workloadClusterName)
+	kubeContext.AuthInfo = userName
+	config.Contexts = map[string]*clientcmdapi.Context{config.CurrentContext: kubeContext}
+	config.AuthInfos = map[string]*clientcmdapi.AuthInfo{userName: {Token: token}}
+	config.Clusters = map[string]*clientcmdapi.Cluster{kubeContext.Cluster: config.Clusters[kubeContext.Cluster]}
+
+	out, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize the kubeconfig")
+	}
+	return string(out), nil
+}
+
+// createKubeconfigTokenClusterRoleBinding creates the ClusterRoleBinding granting the given ClusterRole to the group
+// of the bootstrap token with the given ID, annotated with the expiration of the token.
+func createKubeconfigTokenClusterRoleBinding(c client.Client, tokenID, clusterRole string, expiration time.Time) error {
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        kubeconfigTokenClusterRoleBindingNamePrefix + tokenID,
+			Labels:      map[string]string{kubeconfigTokenLabel: tokenID},
+			Annotations: map[string]string{kubeconfigTokenExpirationAnnotation: expiration.Format(time.RFC3339)},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.GroupKind,
+				Name:     kubeconfigTokenGroupPrefix + tokenID,
+			},
+		},
+	}
+	if err := c.Create(ctx, binding); err != nil {
+		return errors.Wrapf(err, "failed to create the %q ClusterRoleBinding in the workload cluster", binding.Name)
+	}
+	return nil
+}
+
+// deleteExpiredKubeconfigTokenClusterRoleBindings deletes the ClusterRoleBindings of the bootstrap tokens created for
+// temporary kubeconfigs which have expired; a ClusterRoleBinding can't be owned by the namespaced token Secret, so
+// it is not garbage collected when the token cleaner deletes the expired token.
+func deleteExpiredKubeconfigTokenClusterRoleBindings(c client.Client) error {
+	bindings := &rbacv1.ClusterRoleBindingList{}
+	if err := c.List(ctx, bindings, client.HasLabels{kubeconfigTokenLabel}); err != nil {
+		return errors.Wrap(err, "failed to list the ClusterRoleBindings of the temporary kubeconfigs in the workload cluster")
+	}
+	now := time.Now()
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		expiration, err := time.Parse(time.RFC3339, binding.Annotations[kubeconfigTokenExpirationAnnotation])
+		if err == nil && expiration.After(now) {
+			continue
+		}
+		if err := c.Delete(ctx, binding); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete the expired %q ClusterRoleBinding in the workload cluster", binding.Name)
+		}
+	}
+	return nil
+}
+
+// createKubeconfigToken creates a bootstrap token valid for authentication only, which expires at the given time;
+// expired tokens are rejected by the API server and eventually deleted by the token cleaner.
+func createKubeconfigToken(c client.Client, tokenID, tokenSecret string, expiration time.Time) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstraputil.BootstrapTokenSecretName(tokenID),
+			Namespace: metav1.NamespaceSystem,
+		},
+		Type: bootstrapapi.SecretTypeBootstrapToken,
+		Data: map[string][]byte{
+			bootstrapapi.BootstrapTokenIDKey:               []byte(tokenID),
+			bootstrapapi.BootstrapTokenSecretKey:           []byte(tokenSecret),
+			bootstrapapi.BootstrapTokenExpirationKey:       []byte(expiration.Format(time.RFC3339)),
+			bootstrapapi.BootstrapTokenUsageAuthentication: []byte("true"),
+			bootstrapapi.BootstrapTokenExtraGroupsKey:      []byte(kubeconfigTokenGroupPrefix + tokenID),
+			bootstrapapi.BootstrapTokenDescriptionKey:      []byte(kubeconfigTokenDescription),
+		},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		return errors.Wrap(err, "failed to create the bootstrap token secret in the workload cluster")
+	}
+	return nil
+}
+
+// newWorkloadClient returns a client for the workload cluster with the given kubeconfig.
+func newWorkloadClient(kubeconfig []byte) (client.Client, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a REST config for the workload cluster")
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: Scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the workload cluster")
+	}
+	return c, nil
+}
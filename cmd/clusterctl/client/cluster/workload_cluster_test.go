@@ -18,13 +18,20 @@ package cluster
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
 	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_WorkloadCluster_GetKubeconfig(t *testing.T) {
@@ -98,3 +105,179 @@ users:
 	}
 
 }
+
+func Test_WorkloadCluster_GetKubeconfigWithTTL(t *testing.T) {
+	validKubeConfig := `
+clusters:
+- cluster:
+    certificate-authority-data: c3R1ZmY=
+    server: https://test-cluster-api:6443
+  name: test1
+contexts:
+- context:
+    cluster: test1
+    user: test1-admin
+  name: test1-admin@test1
+current-context: test1-admin@test1
+kind: Config
+preferences: {}
+users:
+- name: test1-admin
+  user:
+    client-certificate-data: c3R1ZmYtY2VydC1kYXRh
+    client-key-data: c3R1ZmYta2V5LWRhdGE=
+`
+	validSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-kubeconfig",
+			Namespace: "test",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "test1"},
+		},
+		Data: map[string][]byte{
+			secret.KubeconfigDataName: []byte(validKubeConfig),
+		},
+	}
+	tokenBinding := func(tokenID string, expiration time.Time) *rbacv1.ClusterRoleBinding {
+		return &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        kubeconfigTokenClusterRoleBindingNamePrefix + tokenID,
+				Labels:      map[string]string{kubeconfigTokenLabel: tokenID},
+				Annotations: map[string]string{kubeconfigTokenExpirationAnnotation: expiration.Format(time.RFC3339)},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     "view",
+			},
+		}
+	}
+	expiredBinding := tokenBinding("expird", time.Now().Add(-time.Minute))
+	validBinding := tokenBinding("valid1", time.Now().Add(time.Hour))
+	otherBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "other",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+	}
+
+	tests := []struct {
+		name             string
+		proxy            Proxy
+		workloadObjs     []client.Object
+		ttl              time.Duration
+		clusterRole      string
+		expectErr        bool
+		expectedBindings []string
+	}{
+		{
+			name:        "return a kubeconfig using a bootstrap token",
+			proxy:       test.NewFakeProxy().WithObjs(validSecret),
+			ttl:         time.Hour,
+			clusterRole: "view",
+		},
+		{
+			name:        "return a kubeconfig using a bootstrap token with the given ClusterRole",
+			proxy:       test.NewFakeProxy().WithObjs(validSecret),
+			ttl:         10 * time.Minute,
+			clusterRole: "edit",
+		},
+		{
+			name:             "delete the ClusterRoleBindings of the expired tokens",
+			proxy:            test.NewFakeProxy().WithObjs(validSecret),
+			workloadObjs:     []client.Object{expiredBinding, validBinding, otherBinding},
+			ttl:              time.Hour,
+			clusterRole:      "view",
+			expectedBindings: []string{validBinding.Name, otherBinding.Name},
+		},
+		{
+			name:        "return error if cannot find secret",
+			proxy:       test.NewFakeProxy(),
+			ttl:         time.Hour,
+			clusterRole: "view",
+			expectErr:   true,
+		},
+		{
+			name:        "return error if the TTL is not positive",
+			proxy:       test.NewFakeProxy().WithObjs(validSecret),
+			ttl:         -time.Hour,
+			clusterRole: "view",
+			expectErr:   true,
+		},
+		{
+			name:      "return error if the ClusterRole is not set",
+			proxy:     test.NewFakeProxy().WithObjs(validSecret),
+			ttl:       time.Hour,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			workloadClient := fake.NewClientBuilder().WithScheme(test.FakeScheme).WithObjects(tt.workloadObjs...).Build()
+			wc := newWorkloadCluster(tt.proxy)
+			wc.newWorkloadClient = func(kubeconfig []byte) (client.Client, error) {
+				g.Expect(string(kubeconfig)).To(Equal(validKubeConfig))
+				return workloadClient, nil
+			}
+
+			before := time.Now().Truncate(time.Second)
+			data, err := wc.GetKubeconfigWithTTL("test1", "test", tt.ttl, tt.clusterRole)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			config, err := clientcmd.Load([]byte(data))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(config.CurrentContext).To(Equal("test1-admin@test1"))
+			g.Expect(config.Contexts).To(HaveLen(1))
+			g.Expect(config.Contexts[config.CurrentContext].Cluster).To(Equal("test1"))
+			g.Expect(config.Clusters).To(HaveKey("test1"))
+			g.Expect(config.Clusters["test1"].Server).To(Equal("https://test-cluster-api:6443"))
+			g.Expect(config.Clusters["test1"].CertificateAuthorityData).To(Equal([]byte("stuff")))
+			g.Expect(config.AuthInfos).To(HaveLen(1))
+			user := config.AuthInfos[config.Contexts[config.CurrentContext].AuthInfo]
+			g.Expect(user).ToNot(BeNil())
+			g.Expect(user.ClientCertificateData).To(BeEmpty())
+			g.Expect(user.ClientKeyData).To(BeEmpty())
+			g.Expect(bootstraputil.IsValidBootstrapToken(user.Token)).To(BeTrue())
+
+			// The token is backed by a bootstrap token Secret expiring after the TTL.
+			substrs := bootstraputil.BootstrapTokenRegexp.FindStringSubmatch(user.Token)
+			tokenSecret := &corev1.Secret{}
+			g.Expect(workloadClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: bootstraputil.BootstrapTokenSecretName(substrs[1])}, tokenSecret)).To(Succeed())
+			g.Expect(tokenSecret.Type).To(Equal(bootstrapapi.SecretTypeBootstrapToken))
+			g.Expect(string(tokenSecret.Data[bootstrapapi.BootstrapTokenSecretKey])).To(Equal(substrs[2]))
+			g.Expect(string(tokenSecret.Data[bootstrapapi.BootstrapTokenUsageAuthentication])).To(Equal("true"))
+			g.Expect(tokenSecret.Data).ToNot(HaveKey(bootstrapapi.BootstrapTokenUsageSigningKey))
+			g.Expect(string(tokenSecret.Data[bootstrapapi.BootstrapTokenExtraGroupsKey])).To(Equal(kubeconfigTokenGroupPrefix + substrs[1]))
+			expiration, err := time.Parse(time.RFC3339, string(tokenSecret.Data[bootstrapapi.BootstrapTokenExpirationKey]))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(expiration).To(BeTemporally(">=", before.Add(tt.ttl)))
+			g.Expect(expiration).To(BeTemporally("<=", time.Now().Add(tt.ttl)))
+
+			// The group of the token only is bound to the ClusterRole, until the token expires.
+			binding := &rbacv1.ClusterRoleBinding{}
+			g.Expect(workloadClient.Get(ctx, client.ObjectKey{Name: kubeconfigTokenClusterRoleBindingNamePrefix + substrs[1]}, binding)).To(Succeed())
+			g.Expect(binding.RoleRef.Name).To(Equal(tt.clusterRole))
+			g.Expect(binding.Subjects).To(ConsistOf(rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: kubeconfigTokenGroupPrefix + substrs[1]}))
+			g.Expect(binding.Labels).To(HaveKeyWithValue(kubeconfigTokenLabel, substrs[1]))
+			g.Expect(binding.Annotations).To(HaveKeyWithValue(kubeconfigTokenExpirationAnnotation, string(tokenSecret.Data[bootstrapapi.BootstrapTokenExpirationKey])))
+
+			bindings := &rbacv1.ClusterRoleBindingList{}
+			g.Expect(workloadClient.List(ctx, bindings)).To(Succeed())
+			bindingNames := []string{}
+			for _, b := range bindings.Items {
+				bindingNames = append(bindingNames, b.Name)
+			}
+			g.Expect(bindingNames).To(ConsistOf(append(tt.expectedBindings, binding.Name)))
+		})
+	}
+}
@@ -27,7 +27,9 @@ import (
 
 // getComponentsByName is a utility method that returns components
 // for a given provider with options including targetNamespace, and watchingNamespace.
-func (c *clusterctlClient) getComponentsByName(provider string, providerType clusterctlv1.ProviderType, options repository.ComponentsOptions) (repository.Components, error) {
+// If repositoryDir is not empty, the components are read from the local filesystem repository in repositoryDir
+// instead of the provider repository.
+func (c *clusterctlClient) getComponentsByName(provider string, providerType clusterctlv1.ProviderType, repositoryDir string, options repository.ComponentsOptions) (repository.Components, error) {
 
 	// Parse the abbreviated syntax for name[:version]
 	name, version, err := parseProviderName(provider)
@@ -41,6 +43,11 @@ func (c *clusterctlClient) getComponentsByName(provider string, providerType clu
 	if err != nil {
 		return nil, err
 	}
+	if repositoryDir != "" {
+		if providerConfig, err = repository.LocalRepositoryProvider(repositoryDir, providerConfig, version); err != nil {
+			return nil, err
+		}
+	}
 
 	// Get a client for the provider repository and read the provider components;
 	// during the process, provider components will be processed performing variable substitution, customization of target
@@ -54,7 +54,7 @@ func (c *clusterctlClient) GetProviderComponents(provider string, providerType c
 		WatchingNamespace: options.WatchingNamespace,
 		SkipVariables:     options.SkipVariables,
 	}
-	components, err := c.getComponentsByName(provider, providerType, inputOptions)
+	components, err := c.getComponentsByName(provider, providerType, "", inputOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -76,9 +76,15 @@ type ProcessYAMLOptions struct {
 	// ListVariablesOnly return the list of variables expected by the template
 	// without executing any further processing.
 	ListVariablesOnly bool
+
+	// ExtraVariables defines additional variables to be used for the template processing; they take
+	// precedence over the values from the os env variables and the clusterctl config file.
+	ExtraVariables map[string]string
 }
 
 func (c *clusterctlClient) ProcessYAML(options ProcessYAMLOptions) (YamlPrinter, error) {
+	c.extraVariablesToVariables(options.ExtraVariables)
+
 	if options.ReaderSource != nil {
 		// NOTE: Beware of potentially reading in large files all at once
 		// since this is inefficient and increases memory utilziation.
@@ -130,6 +136,9 @@ type GetClusterTemplateOptions struct {
 	// URLSource to be used for reading the workload cluster template; only one template source can be used at time.
 	URLSource *URLSourceOptions
 
+	// HTTPSSource to be used for downloading the workload cluster template; only one template source can be used at time.
+	HTTPSSource *HTTPSSourceOptions
+
 	// ConfigMapSource to be used for reading the workload cluster template; only one template source can be used at time.
 	ConfigMapSource *ConfigMapSourceOptions
 
@@ -159,6 +168,10 @@ type GetClusterTemplateOptions struct {
 	// YamlProcessor defines the yaml processor to use for the cluster
 	// template processing. If not defined, SimpleProcessor will be used.
 	YamlProcessor Processor
+
+	// ExtraVariables defines additional variables to be used for the template processing; they take
+	// precedence over the values from the os env variables, the clusterctl config file and the other options.
+	ExtraVariables map[string]string
 }
 
 // numSources return the number of template sources currently set on a GetClusterTemplateOptions.
@@ -173,6 +186,9 @@ func (o *GetClusterTemplateOptions) numSources() int {
 	if o.URLSource != nil {
 		numSources++
 	}
+	if o.HTTPSSource != nil {
+		numSources++
+	}
 	return numSources
 }
 
@@ -194,6 +210,16 @@ type URLSourceOptions struct {
 	URL string
 }
 
+// HTTPSSourceOptions defines the options to be used when downloading a workload cluster template from an HTTPS URL.
+type HTTPSSourceOptions struct {
+	// URL to download the workload cluster template from.
+	URL string
+
+	// Checksum is the SHA256 checksum of the workload cluster template, expressed as an hex string optionally
+	// prefixed by "sha256:". If set, the downloaded template is rejected if it does not match.
+	Checksum string
+}
+
 // DefaultCustomTemplateConfigMapKey  where the workload cluster template is hosted.
 const DefaultCustomTemplateConfigMapKey = "template"
 
@@ -245,6 +271,7 @@ func (c *clusterctlClient) GetClusterTemplate(options GetClusterTemplateOptions)
 	if err := c.templateOptionsToVariables(options); err != nil {
 		return nil, err
 	}
+	c.extraVariablesToVariables(options.ExtraVariables)
 
 	// Gets the workload cluster template from the selected source
 	if options.ProviderRepositorySource != nil {
@@ -256,6 +283,9 @@ func (c *clusterctlClient) GetClusterTemplate(options GetClusterTemplateOptions)
 	if options.URLSource != nil {
 		return c.getTemplateFromURL(cluster, *options.URLSource, options.TargetNamespace, options.ListVariablesOnly)
 	}
+	if options.HTTPSSource != nil {
+		return c.getTemplateFromHTTPS(cluster, *options.HTTPSSource, options.TargetNamespace, options.ListVariablesOnly)
+	}
 
 	return nil, errors.New("unable to read custom template. Please specify a template source")
 }
@@ -356,6 +386,19 @@ func (c *clusterctlClient) getTemplateFromURL(cluster cluster.Client, source URL
 	return cluster.Template().GetFromURL(source.URL, targetNamespace, listVariablesOnly)
 }
 
+// getTemplateFromHTTPS returns a workload cluster template downloaded from an HTTPS URL.
+func (c *clusterctlClient) getTemplateFromHTTPS(cluster cluster.Client, source HTTPSSourceOptions, targetNamespace string, listVariablesOnly bool) (Template, error) {
+	return cluster.Template().GetFromHTTPSURL(source.URL, source.Checksum, targetNamespace, listVariablesOnly)
+}
+
+// extraVariablesToVariables injects the extra variables into the configClient so they can be consumed as variables
+// from the template, overriding the values from os env variables and the clusterctl config file.
+func (c *clusterctlClient) extraVariablesToVariables(variables map[string]string) {
+	for key, value := range variables {
+		c.configClient.Variables().Set(key, value)
+	}
+}
+
 // templateOptionsToVariables injects some of the templateOptions to the configClient so they can be consumed as a variables from the template.
 func (c *clusterctlClient) templateOptionsToVariables(options GetClusterTemplateOptions) error {
 
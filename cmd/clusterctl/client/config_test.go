@@ -679,6 +679,20 @@ v2: default2
 v3: default3`,
 			expectedVars: []string{"VAR1", "VAR2", "VAR3"},
 		},
+		{
+			name: "processes yaml with extra variables",
+			options: ProcessYAMLOptions{
+				URLSource: &URLSourceOptions{
+					URL: templateFile,
+				},
+				ExtraVariables: map[string]string{"VAR1": "extra1", "VAR3": "extra3"},
+			},
+			expectErr: false,
+			expectedYaml: `v1: extra1
+v2: default2
+v3: extra3`,
+			expectedVars: []string{"VAR1", "VAR2", "VAR3"},
+		},
 		{
 			name: "returns error if unable to read from reader",
 			options: ProcessYAMLOptions{
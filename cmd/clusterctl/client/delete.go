@@ -19,6 +19,7 @@ package client
 import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
@@ -58,6 +59,10 @@ type DeleteOptions struct {
 	// IncludeCRDs forces the deletion of the provider's CRDs (and of all the related objects).
 	// By Extension, this forces the deletion of all the resources shared among provider instances, like e.g. web-hooks.
 	IncludeCRDs bool
+
+	// ConfirmCRDsDeletion, if set, is called with the CRDs to be deleted when IncludeCRDs is set, before any provider
+	// is deleted; if it returns an error, the deletion is aborted.
+	ConfirmCRDsDeletion func(crds []CustomResourceDefinitionSummary) error
 }
 
 func (c *clusterctlClient) Delete(options DeleteOptions) error {
@@ -124,6 +129,31 @@ func (c *clusterctlClient) Delete(options DeleteOptions) error {
 		}
 	}
 
+	// Let the caller confirm the deletion of the CRDs, which also deletes all the custom resources of their Kinds.
+	if options.IncludeCRDs && options.ConfirmCRDsDeletion != nil {
+		var crds []CustomResourceDefinitionSummary
+		crdNames := sets.NewString()
+		for _, provider := range providersToDelete {
+			summaries, err := clusterClient.ProviderComponents().ListCRDs(provider)
+			if err != nil {
+				return err
+			}
+			// Instances of the same provider share the CRDs.
+			for _, summary := range summaries {
+				if crdNames.Has(summary.Name) {
+					continue
+				}
+				crdNames.Insert(summary.Name)
+				crds = append(crds, CustomResourceDefinitionSummary(summary))
+			}
+		}
+		if len(crds) > 0 {
+			if err := options.ConfirmCRDsDeletion(crds); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Delete the selected providers
 	for _, provider := range providersToDelete {
 		if err := clusterClient.ProviderComponents().Delete(cluster.DeleteOptions{Provider: provider, IncludeNamespace: options.IncludeNamespace, IncludeCRDs: options.IncludeCRDs}); err != nil {
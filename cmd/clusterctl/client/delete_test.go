@@ -21,7 +21,11 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
@@ -187,6 +191,97 @@ func Test_clusterctlClient_Delete(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_Delete_ConfirmCRDsDeletion(t *testing.T) {
+	kubeconfig := Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}
+
+	tests := []struct {
+		name          string
+		includeCRDs   bool
+		confirmErr    error
+		wantConfirm   bool
+		wantProviders sets.String
+		wantErr       bool
+	}{
+		{
+			name:          "CRDs deletion confirmed",
+			includeCRDs:   true,
+			wantConfirm:   true,
+			wantProviders: sets.NewString(),
+			wantErr:       false,
+		},
+		{
+			name:        "CRDs deletion not confirmed",
+			includeCRDs: true,
+			confirmErr:  errors.New("deletion aborted"),
+			wantConfirm: true,
+			wantProviders: sets.NewString(
+				capiProviderConfig.Name(),
+				clusterctlv1.ManifestLabel(bootstrapProviderConfig.Name(), bootstrapProviderConfig.Type()),
+				clusterctlv1.ManifestLabel(controlPlaneProviderConfig.Name(), controlPlaneProviderConfig.Type()),
+				clusterctlv1.ManifestLabel(infraProviderConfig.Name(), infraProviderConfig.Type())),
+			wantErr: true,
+		},
+		{
+			name:          "CRDs not deleted",
+			includeCRDs:   false,
+			wantConfirm:   false,
+			wantProviders: sets.NewString(),
+			wantErr:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client := fakeClusterForDelete()
+			fakeCluster := client.clusters[cluster.Kubeconfig(kubeconfig)].(*fakeClusterClient)
+			fakeCluster.WithObjs(&apiextensionsv1.CustomResourceDefinition{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "CustomResourceDefinition",
+					APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "clusters.cluster.x-k8s.io",
+					Labels: map[string]string{clusterv1.ProviderLabelName: clusterctlv1.ManifestLabel(capiProviderConfig.Name(), capiProviderConfig.Type())},
+				},
+			})
+
+			var gotCRDs []CustomResourceDefinitionSummary
+			err := client.Delete(DeleteOptions{
+				Kubeconfig:  kubeconfig,
+				IncludeCRDs: tt.includeCRDs,
+				DeleteAll:   true,
+				ConfirmCRDsDeletion: func(crds []CustomResourceDefinitionSummary) error {
+					gotCRDs = crds
+					return tt.confirmErr
+				},
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			if tt.wantConfirm {
+				g.Expect(gotCRDs).To(ConsistOf(CustomResourceDefinitionSummary{Name: "clusters.cluster.x-k8s.io"}))
+			} else {
+				g.Expect(gotCRDs).To(BeEmpty())
+			}
+
+			c, err := fakeCluster.Proxy().NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+			gotProviders := &clusterctlv1.ProviderList{}
+			g.Expect(c.List(ctx, gotProviders)).To(Succeed())
+
+			gotProvidersSet := sets.NewString()
+			for _, gotProvider := range gotProviders.Items {
+				gotProvidersSet.Insert(gotProvider.Name)
+			}
+			g.Expect(gotProvidersSet).To(Equal(tt.wantProviders))
+		})
+	}
+}
+
 // clusterctl client for a management cluster with capi and bootstrap provider
 func fakeClusterForDelete() *fakeClient {
 	config1 := newFakeConfig().
@@ -38,12 +38,17 @@ type DescribeClusterOptions struct {
 	// to signal to the presentation layer to show all the conditions for the objects.
 	ShowOtherConditions string
 
+	// ShowConditionTypes is a list of comma separated condition types to be shown for the objects selected by
+	// ShowOtherConditions; use 'errors' to show only the conditions with Status not True, or 'all' (the default)
+	// to show all the conditions.
+	ShowConditionTypes string
+
 	// DisableNoEcho disable hiding MachineInfrastructure or BootstrapConfig objects if the object's ready condition is true
 	// or it has the same Status, Severity and Reason of the parent's object ready condition (it is an echo)
 	DisableNoEcho bool
 
-	// DisableGrouping disable grouping machines objects in case the ready condition
-	// has the same Status, Severity and Reason
+	// DisableGrouping disable grouping machines objects in case they have the same phase
+	// and their conditions have the same Status, Severity and Reason
 	DisableGrouping bool
 }
 
@@ -73,6 +78,7 @@ func (c *clusterctlClient) DescribeCluster(options DescribeClusterOptions) (*tre
 	// Gets the object tree representing the status of a Cluster API cluster.
 	return tree.Discovery(context.TODO(), client, options.Namespace, options.ClusterName, tree.DiscoverOptions{
 		ShowOtherConditions: options.ShowOtherConditions,
+		ShowConditionTypes:  options.ShowConditionTypes,
 		DisableNoEcho:       options.DisableNoEcho,
 		DisableGrouping:     options.DisableGrouping,
 	})
@@ -16,7 +16,11 @@ limitations under the License.
 
 package client
 
-import "github.com/pkg/errors"
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
 
 //GetKubeconfigOptions carries all the options supported by GetKubeconfig
 type GetKubeconfigOptions struct {
@@ -29,6 +33,14 @@ type GetKubeconfigOptions struct {
 
 	// WorkloadClusterName is the name of the workload cluster.
 	WorkloadClusterName string
+
+	// TTL, if set, causes GetKubeconfig to return a kubeconfig using a bootstrap token which expires after
+	// the given duration, instead of the admin kubeconfig of the workload cluster.
+	TTL time.Duration
+
+	// ClusterRole is the ClusterRole granted to the bootstrap token when TTL is set.
+	// If empty, the "view" ClusterRole is used.
+	ClusterRole string
 }
 
 func (c *clusterctlClient) GetKubeconfig(options GetKubeconfigOptions) (string, error) {
@@ -49,6 +61,12 @@ func (c *clusterctlClient) GetKubeconfig(options GetKubeconfigOptions) (string,
 		options.Namespace = currentNamespace
 	}
 
+	if options.TTL != 0 {
+		if options.ClusterRole == "" {
+			options.ClusterRole = "view"
+		}
+		return clusterClient.WorkloadCluster().GetKubeconfigWithTTL(options.WorkloadClusterName, options.Namespace, options.TTL, options.ClusterRole)
+	}
 	return clusterClient.WorkloadCluster().GetKubeconfig(options.WorkloadClusterName, options.Namespace)
 
 }
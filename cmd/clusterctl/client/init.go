@@ -17,7 +17,9 @@ limitations under the License.
 package client
 
 import (
+	"os"
 	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
@@ -58,6 +60,13 @@ type InitOptions struct {
 	// If unspecified, the providers watches for Cluster API objects across all namespaces.
 	WatchingNamespace string
 
+	// RepositoryDir defines a local filesystem directory the provider components are read from instead of the provider
+	// repositories, e.g. for air-gapped installations. The directory must contain the metadata.yaml and the components
+	// file of each provider version, with the layout {RepositoryDir}/{provider-label}/{version}/{file},
+	// e.g. infrastructure-aws/v0.6.4/infrastructure-components.yaml; if the version is not specified, the latest
+	// version in the directory is used.
+	RepositoryDir string
+
 	// LogUsageInstructions instructs the init command to print the usage instructions in case of first run.
 	LogUsageInstructions bool
 
@@ -87,6 +96,10 @@ func (c *clusterctlClient) Init(options InitOptions) ([]Components, error) {
 	log.Info("Fetching providers")
 	firstRun := c.addDefaultProviders(cluster, &options)
 
+	if err := c.validateRepositoryDir(options); err != nil {
+		return nil, err
+	}
+
 	// create an installer service, add the requested providers to the install queue and then perform validation
 	// of the target state of the management cluster before starting the installation.
 	installer, err := c.setupInstaller(cluster, options)
@@ -151,6 +164,10 @@ func (c *clusterctlClient) InitImages(options InitOptions) ([]string, error) {
 	// a bootstrap provider and a control-plane provider (if not already explicitly requested by the user)
 	c.addDefaultProviders(cluster, &options)
 
+	if err := c.validateRepositoryDir(options); err != nil {
+		return nil, err
+	}
+
 	// skip variable parsing when listing images
 	options.skipVariables = true
 
@@ -186,6 +203,7 @@ func (c *clusterctlClient) setupInstaller(cluster cluster.Client, options InitOp
 		installer:         installer,
 		targetNamespace:   options.TargetNamespace,
 		watchingNamespace: options.WatchingNamespace,
+		repositoryDir:     options.RepositoryDir,
 		skipVariables:     options.skipVariables,
 	}
 
@@ -234,10 +252,62 @@ func (c *clusterctlClient) addDefaultProviders(cluster cluster.Client, options *
 	return firstRun
 }
 
+// validateRepositoryDir checks the local filesystem repository in RepositoryDir, if any, contains the files
+// required to install all the providers, and returns an error listing the missing files otherwise.
+func (c *clusterctlClient) validateRepositoryDir(options InitOptions) error {
+	if options.RepositoryDir == "" {
+		return nil
+	}
+
+	if f, err := os.Stat(options.RepositoryDir); err != nil || !f.IsDir() {
+		return errors.Errorf("the repository directory %q does not exist or is not a directory", options.RepositoryDir)
+	}
+
+	providers := map[clusterctlv1.ProviderType][]string{
+		clusterctlv1.CoreProviderType:           {options.CoreProvider},
+		clusterctlv1.BootstrapProviderType:      options.BootstrapProviders,
+		clusterctlv1.ControlPlaneProviderType:   options.ControlPlaneProviders,
+		clusterctlv1.InfrastructureProviderType: options.InfrastructureProviders,
+	}
+	var missing []string
+	for _, providerType := range []clusterctlv1.ProviderType{
+		clusterctlv1.CoreProviderType,
+		clusterctlv1.BootstrapProviderType,
+		clusterctlv1.ControlPlaneProviderType,
+		clusterctlv1.InfrastructureProviderType,
+	} {
+		for _, provider := range providers[providerType] {
+			if provider == "" || provider == NoopProvider {
+				continue
+			}
+			name, version, err := parseProviderName(provider)
+			if err != nil {
+				return err
+			}
+			providerConfig, err := c.configClient.Providers().Get(name, providerType)
+			if err != nil {
+				return err
+			}
+			files, err := repository.MissingLocalRepositoryFiles(options.RepositoryDir, providerConfig, version)
+			if err != nil {
+				return err
+			}
+			missing = append(missing, files...)
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.Errorf("the repository directory %q is missing the following files required to install the providers:\n%s",
+			options.RepositoryDir, strings.Join(missing, "\n"))
+	}
+	return nil
+}
+
 type addToInstallerOptions struct {
 	installer         cluster.ProviderInstaller
 	targetNamespace   string
 	watchingNamespace string
+	repositoryDir     string
 	skipVariables     bool
 }
 
@@ -256,7 +326,7 @@ func (c *clusterctlClient) addToInstaller(options addToInstallerOptions, provide
 			WatchingNamespace: options.watchingNamespace,
 			SkipVariables:     options.skipVariables,
 		}
-		components, err := c.getComponentsByName(provider, providerType, componentsOptions)
+		components, err := c.getComponentsByName(provider, providerType, options.repositoryDir, componentsOptions)
 		if err != nil {
 			return errors.Wrapf(err, "failed to get provider components for the %q provider", provider)
 		}
@@ -18,6 +18,9 @@ package client
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -577,6 +580,152 @@ func Test_clusterctlClient_Init(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_validateRepositoryDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clusterctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The repository directory has all the files of the core provider, and the metadata.yaml only of the infrastructure provider.
+	for _, file := range []string{"cluster-api/v1.0.0/metadata.yaml", "cluster-api/v1.0.0/url", "infrastructure-infra/v3.0.0/metadata.yaml"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte("foo: bar"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		options     InitOptions
+		wantErr     bool
+		wantMissing []string
+	}{
+		{
+			name: "no repository directory",
+			options: InitOptions{
+				CoreProvider:            "cluster-api:v1.0.0",
+				InfrastructureProviders: []string{"infra:v3.0.0"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "repository directory with all the files of the providers",
+			options: InitOptions{
+				CoreProvider:          "cluster-api:v1.0.0",
+				BootstrapProviders:    []string{NoopProvider},
+				ControlPlaneProviders: []string{NoopProvider},
+				RepositoryDir:         dir,
+			},
+			wantErr: false,
+		},
+		{
+			name: "repository directory does not exist",
+			options: InitOptions{
+				CoreProvider:  "cluster-api:v1.0.0",
+				RepositoryDir: filepath.Join(dir, "does-not-exist"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "repository directory missing files of the providers",
+			options: InitOptions{
+				CoreProvider:            "cluster-api:v1.0.0",
+				BootstrapProviders:      []string{"kubeadm:v2.0.0"},
+				InfrastructureProviders: []string{"infra:v3.0.0"},
+				RepositoryDir:           dir,
+			},
+			wantErr: true,
+			wantMissing: []string{
+				filepath.Join(dir, "bootstrap-kubeadm", "v2.0.0", "metadata.yaml"),
+				filepath.Join(dir, "bootstrap-kubeadm", "v2.0.0", "url"),
+				filepath.Join(dir, "infrastructure-infra", "v3.0.0", "url"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := fakeEmptyCluster().internalClient.validateRepositoryDir(tt.options)
+			if !tt.wantErr {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			for _, file := range tt.wantMissing {
+				g.Expect(err.Error()).To(ContainSubstring(file))
+			}
+		})
+	}
+}
+
+func Test_clusterctlClient_InitWithRepositoryDir(t *testing.T) {
+	g := NewWithT(t)
+
+	dir, err := ioutil.TempDir("", "clusterctl")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	// The providers are configured with repositories which can't be read, so the installation succeeds only
+	// if both the components and the metadata of the providers are read from the repository directory.
+	providers := []struct {
+		config  config.Provider
+		version string
+	}{
+		{config: config.NewProvider(config.ClusterAPIProviderName, "https://example.com/core-components.yaml", clusterctlv1.CoreProviderType), version: "v1.0.0"},
+		{config: config.NewProvider(config.KubeadmBootstrapProviderName, "https://example.com/bootstrap-components.yaml", clusterctlv1.BootstrapProviderType), version: "v1.0.0"},
+		{config: config.NewProvider(config.KubeadmControlPlaneProviderName, "https://example.com/control-plane-components.yaml", clusterctlv1.ControlPlaneProviderType), version: "v1.0.0"},
+		{config: config.NewProvider("infra", "https://example.com/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType), version: "v3.0.0"},
+	}
+	config1 := newFakeConfig()
+	for i, p := range providers {
+		config1 = config1.WithProvider(p.config)
+
+		versionDir := filepath.Join(dir, p.config.ManifestLabel(), p.version)
+		g.Expect(os.MkdirAll(versionDir, 0755)).To(Succeed())
+		metadata := fmt.Sprintf("apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3\n"+
+			"kind: Metadata\n"+
+			"releaseSeries:\n"+
+			"- major: %s\n"+
+			"  minor: 0\n"+
+			"  contract: %s\n", p.version[1:2], test.CurrentCAPIContract)
+		g.Expect(ioutil.WriteFile(filepath.Join(versionDir, "metadata.yaml"), []byte(metadata), 0600)).To(Succeed())
+		g.Expect(ioutil.WriteFile(filepath.Join(versionDir, filepath.Base(p.config.URL())), componentsYAML(fmt.Sprintf("ns%d", i+1)), 0600)).To(Succeed())
+	}
+
+	// Both the clusterctl client and the management cluster client use the default repository clients.
+	cluster1 := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, config1)
+	cluster1.internalclient = cluster.New(cluster1.kubeconfig, config1, cluster.InjectProxy(cluster1.fakeProxy))
+	client, err := newClusterctlClient("fake-config",
+		InjectConfig(config1),
+		InjectClusterClientFactory(func(ClusterClientFactoryInput) (cluster.Client, error) {
+			return cluster1, nil
+		}),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	got, err := client.Init(InitOptions{
+		Kubeconfig:              Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		CoreProvider:            fmt.Sprintf("%s:v1.0.0", config.ClusterAPIProviderName),
+		BootstrapProviders:      []string{fmt.Sprintf("%s:v1.0.0", config.KubeadmBootstrapProviderName)},
+		ControlPlaneProviders:   []string{fmt.Sprintf("%s:v1.0.0", config.KubeadmControlPlaneProviderName)},
+		InfrastructureProviders: []string{"infra:v3.0.0"},
+		RepositoryDir:           dir,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(got).To(HaveLen(len(providers)))
+	for i, gItem := range got {
+		g.Expect(gItem.Name()).To(Equal(providers[i].config.Name()))
+		g.Expect(gItem.Type()).To(Equal(providers[i].config.Type()))
+		g.Expect(gItem.Version()).To(Equal(providers[i].version))
+		g.Expect(gItem.URL()).To(HavePrefix("file://"))
+	}
+}
+
 var (
 	capiProviderConfig         = config.NewProvider(config.ClusterAPIProviderName, "url", clusterctlv1.CoreProviderType)
 	bootstrapProviderConfig    = config.NewProvider(config.KubeadmBootstrapProviderName, "url", clusterctlv1.BootstrapProviderType)
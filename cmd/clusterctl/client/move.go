@@ -34,8 +34,13 @@ type MoveOptions struct {
 	// namespace will be used.
 	Namespace string
 
-	// DryRun means the move action is a dry run, no real action will be performed
+	// DryRun means the move action is a dry run, no real action will be performed; instead, the list of
+	// objects that would be moved is printed in the same order they would be moved.
 	DryRun bool
+
+	// Progress, if not nil, gets called each time the move operation makes a step forward, e.g. after
+	// each object is created in the target management cluster.
+	Progress func(MoveProgress)
 }
 
 func (c *clusterctlClient) Move(options MoveOptions) error {
@@ -46,8 +51,11 @@ func (c *clusterctlClient) Move(options MoveOptions) error {
 	}
 
 	// Ensures the custom resource definitions required by clusterctl are in place.
-	if err := fromCluster.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
-		return err
+	// NOTE: In case of a dry run this is skipped, so the source cluster is not modified.
+	if !options.DryRun {
+		if err := fromCluster.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
+			return err
+		}
 	}
 
 	var toCluster cluster.Client
@@ -73,7 +81,14 @@ func (c *clusterctlClient) Move(options MoveOptions) error {
 		options.Namespace = currentNamespace
 	}
 
-	if err := fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun); err != nil {
+	var progress cluster.MoveProgressFunc
+	if options.Progress != nil {
+		progress = func(p cluster.MoveProgress) {
+			options.Progress(MoveProgress(p))
+		}
+	}
+
+	if err := fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun, progress); err != nil {
 		return err
 	}
 
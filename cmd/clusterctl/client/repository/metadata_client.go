@@ -59,7 +59,7 @@ func (f *metadataClient) Get() (*clusterctlv1.Metadata, error) {
 
 	// gets the metadata file from the repository
 	version := f.version
-	name := "metadata.yaml"
+	name := metadataFile
 
 	file, err := getLocalOverride(&newOverrideInput{
 		configVariablesClient: f.configVarClient,
@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -29,6 +30,9 @@ import (
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 )
 
+// metadataFile is the name of the provider metadata file in a provider repository.
+const metadataFile = "metadata.yaml"
+
 // localRepository provides support for providers located on the local filesystem.
 // As part of the provider object, the URL is expected to contain the absolute
 // path to the components yaml on the local filesystem.
@@ -242,3 +246,57 @@ func (r *localRepository) getLatestRelease() (string, error) {
 	}
 	return latestTag, nil
 }
+
+// LocalRepositoryProvider returns the configuration of a provider whose repository is replaced by a local filesystem
+// repository in dir, with the layout {dir}/{provider-label}/{version}/{components.yaml}; the components file is named
+// like the one in the URL of the provider repository, e.g. infrastructure-components.yaml. When the version is
+// empty, the latest version in dir is used.
+func LocalRepositoryProvider(dir string, provider config.Provider, version string) (config.Provider, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the absolute path of the repository directory %q", dir)
+	}
+	if version == "" {
+		version = "latest"
+	}
+
+	componentsPath := filepath.Join(absDir, provider.ManifestLabel(), version, path.Base(provider.URL()))
+	componentsURL := &url.URL{Scheme: "file", Path: filepath.ToSlash(componentsPath)}
+	if runtime.GOOS == "windows" {
+		// in case of windows, the URI standard requires an additional / before the drive letter.
+		componentsURL.Path = "/" + componentsURL.Path
+	}
+	return config.NewProvider(provider.Name(), componentsURL.String(), provider.Type()), nil
+}
+
+// MissingLocalRepositoryFiles returns the files required to install a provider version which are missing from the
+// local filesystem repository in dir returned by LocalRepositoryProvider: the metadata.yaml and the components file.
+// When the version is empty, the latest version in dir is checked.
+func MissingLocalRepositoryFiles(dir string, provider config.Provider, version string) ([]string, error) {
+	localProvider, err := LocalRepositoryProvider(dir, provider, version)
+	if err != nil {
+		return nil, err
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the absolute path of the repository directory %q", dir)
+	}
+	providerDir := filepath.Join(absDir, provider.ManifestLabel())
+	if version == "" {
+		repo := &localRepository{providerConfig: localProvider, basepath: absDir, providerLabel: provider.ManifestLabel()}
+		if version, err = repo.getLatestRelease(); err != nil {
+			// without any release, the files are reported as missing for any version.
+			version = "{version}"
+		}
+	}
+
+	var missing []string
+	for _, file := range []string{metadataFile, path.Base(provider.URL())} {
+		filePath := filepath.Join(providerDir, version, file)
+		if f, err := os.Stat(filePath); err != nil || f.IsDir() {
+			missing = append(missing, filePath)
+		}
+	}
+	return missing, nil
+}
@@ -381,3 +381,79 @@ func Test_localRepository_GetVersions(t *testing.T) {
 		})
 	}
 }
+
+func Test_LocalRepositoryProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	tmpDir := createTempDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	provider := config.NewProvider("foo", "https://github.com/o/r/releases/latest/bootstrap-components.yaml", clusterctlv1.BootstrapProviderType)
+
+	got, err := LocalRepositoryProvider(tmpDir, provider, "v1.0.0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.Name()).To(Equal("foo"))
+	g.Expect(got.Type()).To(Equal(clusterctlv1.BootstrapProviderType))
+	g.Expect(got.URL()).To(HaveSuffix(filepath.ToSlash(filepath.Join(tmpDir, "bootstrap-foo", "v1.0.0", "bootstrap-components.yaml"))))
+	g.Expect(got.URL()).To(HavePrefix("file://"))
+
+	got, err = LocalRepositoryProvider(tmpDir, provider, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.URL()).To(HaveSuffix(filepath.ToSlash(filepath.Join(tmpDir, "bootstrap-foo", "latest", "bootstrap-components.yaml"))))
+}
+
+func Test_MissingLocalRepositoryFiles(t *testing.T) {
+	tmpDir := createTempDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createLocalTestProviderFile(t, tmpDir, "bootstrap-foo/v1.0.0/metadata.yaml", "foo: bar")
+	createLocalTestProviderFile(t, tmpDir, "bootstrap-foo/v1.0.0/bootstrap-components.yaml", "foo: bar")
+	createLocalTestProviderFile(t, tmpDir, "bootstrap-foo/v1.1.0/metadata.yaml", "foo: bar")
+
+	provider := config.NewProvider("foo", "https://github.com/o/r/releases/latest/bootstrap-components.yaml", clusterctlv1.BootstrapProviderType)
+	missingProvider := config.NewProvider("bar", "https://github.com/o/r/releases/latest/bootstrap-components.yaml", clusterctlv1.BootstrapProviderType)
+
+	tests := []struct {
+		name     string
+		provider config.Provider
+		version  string
+		want     []string
+	}{
+		{
+			name:     "all the files of the version are in the repository",
+			provider: provider,
+			version:  "v1.0.0",
+			want:     nil,
+		},
+		{
+			name:     "the components file of the version is missing",
+			provider: provider,
+			version:  "v1.1.0",
+			want:     []string{filepath.Join(tmpDir, "bootstrap-foo", "v1.1.0", "bootstrap-components.yaml")},
+		},
+		{
+			name:     "the files of the latest version are checked if the version is empty",
+			provider: provider,
+			version:  "",
+			want:     []string{filepath.Join(tmpDir, "bootstrap-foo", "v1.1.0", "bootstrap-components.yaml")},
+		},
+		{
+			name:     "all the files are missing if there are no releases of the provider",
+			provider: missingProvider,
+			version:  "",
+			want: []string{
+				filepath.Join(tmpDir, "bootstrap-bar", "{version}", "metadata.yaml"),
+				filepath.Join(tmpDir, "bootstrap-bar", "{version}", "bootstrap-components.yaml"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := MissingLocalRepositoryFiles(tmpDir, tt.provider, tt.version)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
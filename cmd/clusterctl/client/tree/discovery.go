@@ -31,6 +31,11 @@ type DiscoverOptions struct {
 	// to signal to the presentation layer to show all the conditions for the objects.
 	ShowOtherConditions string
 
+	// ShowConditionTypes is a list of comma separated condition types to be shown for the objects selected by
+	// ShowOtherConditions; use 'errors' to show only the conditions with Status not True, or 'all' (the default)
+	// to show all the conditions.
+	ShowConditionTypes string
+
 	// DisableNoEcho disable hiding MachineInfrastructure or BootstrapConfig objects if the object's ready condition is true
 	// or it has the same Status, Severity and Reason of the parent's object ready condition (it is an echo)
 	DisableNoEcho bool
@@ -43,6 +48,7 @@ type DiscoverOptions struct {
 func (d DiscoverOptions) toObjectTreeOptions() ObjectTreeOptions {
 	return ObjectTreeOptions{
 		ShowOtherConditions: d.ShowOtherConditions,
+		ShowConditionTypes:  d.ShowConditionTypes,
 		DisableNoEcho:       d.DisableNoEcho,
 		DisableGrouping:     d.DisableGrouping,
 	}
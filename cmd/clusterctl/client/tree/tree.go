@@ -35,12 +35,17 @@ type ObjectTreeOptions struct {
 	// to signal to the presentation layer to show all the conditions for the objects.
 	ShowOtherConditions string
 
+	// ShowConditionTypes is a list of comma separated condition types to be shown for the objects selected by
+	// ShowOtherConditions; use 'errors' to show only the conditions with Status not True, or 'all' (the default)
+	// to show all the conditions.
+	ShowConditionTypes string
+
 	// DisableNoEcho disables hiding objects if the object's ready condition has the
 	// same Status, Severity and Reason of the parent's object ready condition (it is an echo)
 	DisableNoEcho bool
 
-	// DisableGrouping disables grouping sibling objects in case the ready condition
-	// has the same Status, Severity and Reason
+	// DisableGrouping disables grouping sibling objects in case they have the same phase
+	// and their conditions have the same Status, Severity and Reason
 	DisableGrouping bool
 }
 
@@ -74,8 +79,12 @@ func (od ObjectTree) Add(parent, obj client.Object, opts ...AddObjectOption) (ad
 
 	// If it is requested to show all the conditions for the object, add
 	// the ShowObjectConditionsAnnotation to signal this to the presentation layer.
+	// NOTE: If a filter on condition types is set, objects without any condition passing the filter are collapsed
+	// to their ready condition.
 	if isObjDebug(obj, od.options.ShowOtherConditions) {
-		addAnnotation(obj, ShowObjectConditionsAnnotation, "True")
+		if od.options.ShowConditionTypes == "" || len(od.GetOtherConditions(obj)) > 0 {
+			addAnnotation(obj, ShowObjectConditionsAnnotation, "True")
+		}
 	}
 
 	// If the object should be hidden if the object's ready condition is true ot it has the
@@ -93,8 +102,8 @@ func (od ObjectTree) Add(parent, obj client.Object, opts ...AddObjectOption) (ad
 		addAnnotation(obj, ObjectMetaNameAnnotation, addOpts.MetaName)
 	}
 
-	// If it is requested that this object and its sibling should be grouped in case the ready condition
-	// has the same Status, Severity and Reason, process all the sibling nodes.
+	// If it is requested that this object and its sibling should be grouped in case they have the same phase
+	// and their conditions have the same Status, Severity and Reason, process all the sibling nodes.
 	if IsGroupingObject(parent) {
 		siblings := od.GetObjectsByParent(parent.GetUID())
 
@@ -102,9 +111,9 @@ func (od ObjectTree) Add(parent, obj client.Object, opts ...AddObjectOption) (ad
 			s := siblings[i]
 			sReady := GetReadyCondition(s)
 
-			// If the object's ready condition has a different Status, Severity and Reason than the sibling object,
-			// move on (they should not be grouped).
-			if !hasSameReadyStatusSeverityAndReason(objReady, sReady) {
+			// If the object has a different phase or its conditions have a different Status, Severity and Reason
+			// than the sibling object, move on (they should not be grouped).
+			if !canBeGrouped(obj, s) {
 				continue
 			}
 
@@ -126,8 +135,8 @@ func (od ObjectTree) Add(parent, obj client.Object, opts ...AddObjectOption) (ad
 		}
 	}
 
-	// If it is requested that the child of this node should be grouped in case the ready condition
-	// has the same Status, Severity and Reason, add the GroupingObjectAnnotation to signal
+	// If it is requested that the child of this node should be grouped in case they have the same phase
+	// and their conditions have the same Status, Severity and Reason, add the GroupingObjectAnnotation to signal
 	// this to the presentation layer.
 	if addOpts.GroupingObject && !od.options.DisableGrouping {
 		addAnnotation(obj, GroupingObjectAnnotation, "True")
@@ -163,6 +172,18 @@ func (od ObjectTree) IsObjectWithChild(id types.UID) bool {
 	return len(od.ownership[id]) > 0
 }
 
+// GetOtherConditions returns the other conditions (all the conditions except ready) for an object
+// passing the ShowConditionTypes filter.
+func (od ObjectTree) GetOtherConditions(obj client.Object) []*clusterv1.Condition {
+	var conditions []*clusterv1.Condition
+	for _, c := range GetOtherConditions(obj) {
+		if isConditionShown(c, od.options.ShowConditionTypes) {
+			conditions = append(conditions, c)
+		}
+	}
+	return conditions
+}
+
 func (od ObjectTree) GetObjectsByParent(id types.UID) []client.Object {
 	out := make([]client.Object, 0, len(od.ownership[id]))
 	for k := range od.ownership[id] {
@@ -184,6 +205,29 @@ func hasSameReadyStatusSeverityAndReason(a, b *clusterv1.Condition) bool {
 		a.Reason == b.Reason
 }
 
+// canBeGrouped returns true if two objects have the same phase and their conditions
+// have the same Status, Severity and Reason.
+func canBeGrouped(a, b client.Object) bool {
+	if !hasSameReadyStatusSeverityAndReason(GetReadyCondition(a), GetReadyCondition(b)) {
+		return false
+	}
+	if getPhase(a) != getPhase(b) {
+		return false
+	}
+
+	aConditions := GetOtherConditions(a)
+	bConditions := GetOtherConditions(b)
+	if len(aConditions) != len(bConditions) {
+		return false
+	}
+	for i := range aConditions {
+		if aConditions[i].Type != bConditions[i].Type || !hasSameReadyStatusSeverityAndReason(aConditions[i], bConditions[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 func createGroupNode(sibling client.Object, siblingReady *clusterv1.Condition, obj client.Object, objReady *clusterv1.Condition) *unstructured.Unstructured {
 	kind := fmt.Sprintf("%sGroup", obj.GetObjectKind().GroupVersionKind().Kind)
 
@@ -204,6 +248,14 @@ func createGroupNode(sibling client.Object, siblingReady *clusterv1.Condition, o
 		objReady.Message = ""
 		setReadyCondition(groupNode, objReady)
 	}
+
+	// Copy the phase and the other conditions of the object to the group, so it is possible to
+	// check if the next siblings can be included in the group.
+	setPhase(groupNode, getPhase(obj))
+	for _, c := range GetOtherConditions(obj) {
+		c.Message = ""
+		setCondition(groupNode, c)
+	}
 	return groupNode
 }
 
@@ -271,3 +323,27 @@ func isObjDebug(obj client.Object, debugFilter string) bool {
 	}
 	return false
 }
+
+func isConditionShown(c *clusterv1.Condition, typesFilter string) bool {
+	if typesFilter == "" {
+		return true
+	}
+	for _, filter := range strings.Split(typesFilter, ",") {
+		filter = strings.TrimSpace(filter)
+		switch strings.ToLower(filter) {
+		case "":
+			continue
+		case "all":
+			return true
+		case "errors":
+			if c.Status != corev1.ConditionTrue {
+				return true
+			}
+			continue
+		}
+		if string(c.Type) == filter {
+			return true
+		}
+	}
+	return false
+}
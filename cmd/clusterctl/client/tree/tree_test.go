@@ -221,6 +221,70 @@ func Test_isObjDebug(t *testing.T) {
 	}
 }
 
+func Test_isConditionShown(t *testing.T) {
+	trueCondition := conditions.TrueCondition("A")
+	falseCondition := conditions.FalseCondition("B", "Reason", clusterv1.ConditionSeverityError, "Message")
+	unknownCondition := conditions.UnknownCondition("C", "Reason", "Message")
+
+	tests := []struct {
+		name      string
+		condition *clusterv1.Condition
+		filter    string
+		want      bool
+	}{
+		{
+			name:      "empty filter should return true",
+			condition: trueCondition,
+			filter:    "",
+			want:      true,
+		},
+		{
+			name:      "all filter should return true",
+			condition: trueCondition,
+			filter:    "all",
+			want:      true,
+		},
+		{
+			name:      "errors filter should return false for a True condition",
+			condition: trueCondition,
+			filter:    "errors",
+			want:      false,
+		},
+		{
+			name:      "errors filter should return true for a False condition",
+			condition: falseCondition,
+			filter:    "errors",
+			want:      true,
+		},
+		{
+			name:      "errors filter should return true for an Unknown condition",
+			condition: unknownCondition,
+			filter:    "errors",
+			want:      true,
+		},
+		{
+			name:      "type filter should return true",
+			condition: trueCondition,
+			filter:    "B, A",
+			want:      true,
+		},
+		{
+			name:      "another type filter should return false",
+			condition: trueCondition,
+			filter:    "B",
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := isConditionShown(tt.condition, tt.filter)
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
 func Test_createGroupNode(t *testing.T) {
 	now := metav1.Now()
 	beforeNow := metav1.Time{Time: now.Time.Add(-1 * time.Hour)}
@@ -418,6 +482,52 @@ func Test_Add_setsShowObjectConditionsAnnotation(t *testing.T) {
 	}
 }
 
+func Test_Add_ShowConditionTypes(t *testing.T) {
+	parent := fakeCluster("parent")
+	treeOptions := ObjectTreeOptions{ShowOtherConditions: "all", ShowConditionTypes: "errors"}
+
+	t.Run("errors filter should show only the conditions that are not True", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := fakeMachine("my-machine",
+			withMachineCondition(conditions.TrueCondition("A")),
+			withMachineCondition(conditions.FalseCondition("B", "Reason", clusterv1.ConditionSeverityWarning, "Message")),
+			withMachineCondition(conditions.UnknownCondition("C", "Reason", "Message")),
+		)
+
+		root := parent.DeepCopy()
+		tree := NewObjectTree(root, treeOptions)
+		tree.Add(root, obj)
+
+		gotObj := tree.GetObject("my-machine")
+		g.Expect(gotObj).ToNot(BeNil())
+		g.Expect(gotObj.GetAnnotations()).To(HaveKeyWithValue(ShowObjectConditionsAnnotation, "True"))
+
+		gotConditions := tree.GetOtherConditions(gotObj)
+		g.Expect(gotConditions).To(HaveLen(2))
+		g.Expect(gotConditions[0].Type).To(Equal(clusterv1.ConditionType("B")))
+		g.Expect(gotConditions[1].Type).To(Equal(clusterv1.ConditionType("C")))
+	})
+
+	t.Run("errors filter should collapse objects with only True conditions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := fakeMachine("my-machine",
+			withMachineCondition(conditions.TrueCondition("A")),
+			withMachineCondition(conditions.TrueCondition("B")),
+		)
+
+		root := parent.DeepCopy()
+		tree := NewObjectTree(root, treeOptions)
+		tree.Add(root, obj)
+
+		gotObj := tree.GetObject("my-machine")
+		g.Expect(gotObj).ToNot(BeNil())
+		g.Expect(gotObj.GetAnnotations()).ToNot(HaveKey(ShowObjectConditionsAnnotation))
+		g.Expect(tree.GetOtherConditions(gotObj)).To(BeEmpty())
+	})
+}
+
 func Test_Add_setsGroupingObjectAnnotation(t *testing.T) {
 	parent := fakeCluster("parent")
 	obj := fakeMachine("my-machine")
@@ -670,6 +780,87 @@ func Test_Add_Grouping(t *testing.T) {
 			wantVisible:     false,
 			wantItems:       "first-machine, second-machine, third-machine",
 		},
+		{
+			name: "should not group child node if it has a different phase than an existing one",
+			args: args{
+				siblings: []*clusterv1.Machine{
+					fakeMachine("first-machine",
+						withMachinePhase("Running"),
+						withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+					),
+				},
+				obj: fakeMachine("second-machine",
+					withMachinePhase("Deleting"),
+					withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+				),
+			},
+			wantNodesPrefix: []string{"first-machine", "second-machine"},
+			wantVisible:     true,
+		},
+		{
+			name: "should not group child node if its other conditions differ from an existing one",
+			args: args{
+				siblings: []*clusterv1.Machine{
+					fakeMachine("first-machine",
+						withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+						withMachineCondition(conditions.TrueCondition(clusterv1.BootstrapReadyCondition)),
+					),
+				},
+				obj: fakeMachine("second-machine",
+					withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+					withMachineCondition(conditions.FalseCondition(clusterv1.BootstrapReadyCondition, "Reason", clusterv1.ConditionSeverityWarning, "")),
+				),
+			},
+			wantNodesPrefix: []string{"first-machine", "second-machine"},
+			wantVisible:     true,
+		},
+		{
+			name: "should group child node if it has same phase and conditions of an existing group",
+			args: args{
+				siblings: []*clusterv1.Machine{
+					fakeMachine("first-machine",
+						withMachinePhase("Running"),
+						withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+						withMachineCondition(conditions.TrueCondition(clusterv1.BootstrapReadyCondition)),
+					),
+					fakeMachine("second-machine",
+						withMachinePhase("Running"),
+						withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+						withMachineCondition(conditions.TrueCondition(clusterv1.BootstrapReadyCondition)),
+					),
+				},
+				obj: fakeMachine("third-machine",
+					withMachinePhase("Running"),
+					withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+					withMachineCondition(conditions.TrueCondition(clusterv1.BootstrapReadyCondition)),
+				),
+			},
+			wantNodesPrefix: []string{"zz_True"},
+			wantVisible:     false,
+			wantItems:       "first-machine, second-machine, third-machine",
+		},
+		{
+			name: "should not group child node if its other conditions differ from an existing group",
+			args: args{
+				siblings: []*clusterv1.Machine{
+					fakeMachine("first-machine",
+						withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+						withMachineCondition(conditions.TrueCondition(clusterv1.BootstrapReadyCondition)),
+					),
+					fakeMachine("second-machine",
+						withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+						withMachineCondition(conditions.TrueCondition(clusterv1.BootstrapReadyCondition)),
+					),
+				},
+				obj: fakeMachine("third-machine",
+					withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+					withMachineCondition(conditions.FalseCondition(clusterv1.BootstrapReadyCondition, "Reason", clusterv1.ConditionSeverityWarning, "")),
+				),
+			},
+			wantNodesPrefix: []string{"zz_True", "third-machine"},
+			wantVisible:     true,
+			wantItems:       "first-machine, second-machine",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -763,3 +954,9 @@ func withMachineCondition(c *clusterv1.Condition) func(*clusterv1.Machine) {
 		conditions.Set(m, c)
 	}
 }
+
+func withMachinePhase(phase string) func(*clusterv1.Machine) {
+	return func(m *clusterv1.Machine) {
+		m.Status.Phase = phase
+	}
+}
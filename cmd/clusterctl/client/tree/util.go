@@ -55,11 +55,36 @@ func GetOtherConditions(obj client.Object) []*clusterv1.Condition {
 }
 
 func setReadyCondition(obj client.Object, ready *clusterv1.Condition) {
+	setCondition(obj, ready)
+}
+
+func setCondition(obj client.Object, condition *clusterv1.Condition) {
 	setter := objToSetter(obj)
 	if setter == nil {
 		return
 	}
-	conditions.Set(setter, ready)
+	conditions.Set(setter, condition)
+}
+
+// getPhase returns the phase for an object, if defined.
+func getPhase(obj client.Object) string {
+	if machine, ok := obj.(*clusterv1.Machine); ok {
+		return machine.Status.Phase
+	}
+
+	objUnstructured, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return ""
+	}
+	phase, _, _ := unstructured.NestedString(objUnstructured.Object, "status", "phase")
+	return phase
+}
+
+func setPhase(obj *unstructured.Unstructured, phase string) {
+	if phase == "" {
+		return
+	}
+	_ = unstructured.SetNestedField(obj.Object, phase, "status", "phase")
 }
 
 func objToGetter(obj client.Object) conditions.Getter {
@@ -68,9 +68,10 @@ func (c *clusterctlClient) PlanUpgrade(options PlanUpgradeOptions) ([]UpgradePla
 	aliasUpgradePlan := make([]UpgradePlan, len(upgradePlans))
 	for i, plan := range upgradePlans {
 		aliasUpgradePlan[i] = UpgradePlan{
-			Contract:     plan.Contract,
-			CoreProvider: plan.CoreProvider,
-			Providers:    plan.Providers,
+			Contract:              plan.Contract,
+			CoreProvider:          plan.CoreProvider,
+			Providers:             plan.Providers,
+			IncompatibleProviders: plan.IncompatibleProviders,
 		}
 	}
 
@@ -18,13 +18,24 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// completionTimeout is the timeout of the requests to the management cluster made to complete the commands.
+const completionTimeout = 2 * time.Second
+
 const completionBoilerPlate = `# Copyright 2021 The Kubernetes Authors.
 #
 # Licensed under the Apache License, Version 2.0 (the "License");
@@ -88,6 +99,12 @@ var (
 		"bash": runCompletionBash,
 		"zsh":  runCompletionZsh,
 	}
+
+	// completionProxy returns the proxy to the management cluster queried by the completion functions.
+	// The completion runs while the user is typing, so the requests use a short timeout instead of the default one.
+	completionProxy = func(kubeconfig cluster.Kubeconfig) cluster.Proxy {
+		return cluster.NewProxy(kubeconfig, cluster.InjectProxyTimeout(completionTimeout))
+	}
 )
 
 // GetSupportedShells returns a list of supported shells
@@ -138,3 +155,86 @@ func runCompletionZsh(out io.Writer, cmd *cobra.Command) error {
 
 	return nil
 }
+
+// completionFunc is the signature of the cobra functions completing the positional arguments and the flags of a command.
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// clusterNameCompletionFunc returns a completion function completing the first positional argument with the names
+// of the Clusters in the namespace of the management cluster defined by the given flags.
+func clusterNameCompletionFunc(kubeconfig, kubeconfigContext, namespace *string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		proxy := completionProxy(cluster.Kubeconfig{Path: *kubeconfig, Context: *kubeconfigContext})
+		return completeClusterNames(proxy, *namespace, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// namespaceCompletionFunc returns a completion function completing a flag with the names of the namespaces
+// of the management cluster defined by the given flags.
+func namespaceCompletionFunc(kubeconfig, kubeconfigContext *string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		proxy := completionProxy(cluster.Kubeconfig{Path: *kubeconfig, Context: *kubeconfigContext})
+		return completeNamespaces(proxy, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeClusterNames returns the sorted names of the Clusters in the namespace, or the current namespace if empty,
+// starting with toComplete. No names are returned if the management cluster cannot be reached, e.g. because
+// there is no kubeconfig.
+func completeClusterNames(proxy cluster.Proxy, namespace, toComplete string) []string {
+	if namespace == "" {
+		currentNamespace, err := proxy.CurrentNamespace()
+		if err != nil {
+			return nil
+		}
+		namespace = currentNamespace
+	}
+
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil
+	}
+	clusters := &clusterv1.ClusterList{}
+	if err := c.List(context.Background(), clusters, ctrlclient.InNamespace(namespace)); err != nil {
+		return nil
+	}
+
+	names := []string{}
+	for _, cluster := range clusters.Items {
+		names = append(names, cluster.Name)
+	}
+	return filterCompletions(names, toComplete)
+}
+
+// completeNamespaces returns the sorted names of the namespaces starting with toComplete. No names are returned
+// if the management cluster cannot be reached, e.g. because there is no kubeconfig.
+func completeNamespaces(proxy cluster.Proxy, toComplete string) []string {
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := c.List(context.Background(), namespaces); err != nil {
+		return nil
+	}
+
+	names := []string{}
+	for _, namespace := range namespaces.Items {
+		names = append(names, namespace.Name)
+	}
+	return filterCompletions(names, toComplete)
+}
+
+// filterCompletions returns the sorted names starting with toComplete.
+func filterCompletions(names []string, toComplete string) []string {
+	completions := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+	sort.Strings(completions)
+	return completions
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_completeClusterNames(t *testing.T) {
+	proxy := test.NewFakeProxy().WithNamespace("default").WithObjs(
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-2", Namespace: "default"}},
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-1", Namespace: "default"}},
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}},
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-3", Namespace: "ns1"}},
+	)
+
+	tests := []struct {
+		name       string
+		namespace  string
+		toComplete string
+		want       []string
+	}{
+		{
+			name: "clusters in the current namespace",
+			want: []string{"other", "test-1", "test-2"},
+		},
+		{
+			name:       "clusters in the current namespace starting with the text to complete",
+			toComplete: "test",
+			want:       []string{"test-1", "test-2"},
+		},
+		{
+			name:      "clusters in the given namespace",
+			namespace: "ns1",
+			want:      []string{"test-3"},
+		},
+		{
+			name:      "no clusters in the given namespace",
+			namespace: "ns2",
+			want:      []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(completeClusterNames(proxy, tt.namespace, tt.toComplete)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_completeNamespaces(t *testing.T) {
+	g := NewWithT(t)
+
+	proxy := test.NewFakeProxy().WithObjs(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-public"}},
+	)
+
+	g.Expect(completeNamespaces(proxy, "")).To(Equal([]string{"default", "kube-public", "kube-system"}))
+	g.Expect(completeNamespaces(proxy, "kube-")).To(Equal([]string{"kube-public", "kube-system"}))
+	g.Expect(completeNamespaces(proxy, "foo")).To(Equal([]string{}))
+}
+
+func Test_completionWithoutKubeconfig(t *testing.T) {
+	g := NewWithT(t)
+
+	// The proxy fails to load the kubeconfig, so there are no suggestions.
+	proxy := cluster.New(cluster.Kubeconfig{Path: filepath.Join(t.TempDir(), "kubeconfig")}, nil).Proxy()
+	g.Expect(completeClusterNames(proxy, "", "")).To(BeEmpty())
+	g.Expect(completeClusterNames(proxy, "default", "")).To(BeEmpty())
+	g.Expect(completeNamespaces(proxy, "")).To(BeEmpty())
+}
+
+func Test_completionProxy(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeconfigFile := filepath.Join(t.TempDir(), "kubeconfig")
+	g.Expect(ioutil.WriteFile(kubeconfigFile, []byte(`---
+apiVersion: v1
+clusters:
+- cluster:
+    server: https://management-server:1234
+  name: management
+contexts:
+- context:
+    cluster: management
+    user: management
+  name: management
+current-context: management
+kind: Config
+users:
+- name: management
+  user:
+    token: token
+`), 0600)).To(Succeed())
+
+	// The completion does not wait for the default timeout of the requests to an unreachable management cluster.
+	conf, err := completionProxy(cluster.Kubeconfig{Path: kubeconfigFile}).GetConfig()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conf.Timeout).To(Equal(completionTimeout))
+}
+
+func Test_clusterNameCompletionFunc(t *testing.T) {
+	defer func(f func(kubeconfig cluster.Kubeconfig) cluster.Proxy) { completionProxy = f }(completionProxy)
+
+	var gotKubeconfig cluster.Kubeconfig
+	completionProxy = func(kubeconfig cluster.Kubeconfig) cluster.Proxy {
+		gotKubeconfig = kubeconfig
+		return test.NewFakeProxy().WithNamespace("default").WithObjs(
+			&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-1", Namespace: "default"}},
+			&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-2", Namespace: "ns1"}},
+		)
+	}
+
+	kubeconfig, kubeconfigContext, namespace := "kubeconfig", "context", "ns1"
+	complete := clusterNameCompletionFunc(&kubeconfig, &kubeconfigContext, &namespace)
+
+	t.Run("completes the cluster names in the namespace of the flag", func(t *testing.T) {
+		g := NewWithT(t)
+
+		completions, directive := complete(&cobra.Command{}, nil, "")
+		g.Expect(completions).To(Equal([]string{"test-2"}))
+		g.Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp))
+		g.Expect(gotKubeconfig).To(Equal(cluster.Kubeconfig{Path: "kubeconfig", Context: "context"}))
+	})
+
+	t.Run("completes only the first positional argument", func(t *testing.T) {
+		g := NewWithT(t)
+
+		completions, directive := complete(&cobra.Command{}, []string{"test-2"}, "")
+		g.Expect(completions).To(BeEmpty())
+		g.Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp))
+	})
+}
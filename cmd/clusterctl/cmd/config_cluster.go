@@ -37,10 +37,14 @@ type configClusterOptions struct {
 	workerMachineCount       int64
 
 	url                string
+	templateURL        string
+	templateChecksum   string
 	configMapNamespace string
 	configMapName      string
 	configMapDataKey   string
 
+	variables []string
+
 	listVariables bool
 }
 
@@ -77,12 +81,20 @@ var configClusterClusterCmd = &cobra.Command{
 		# custom number of nodes (if supported by the provider's templates).
 		clusterctl config cluster my-cluster --control-plane-machine-count=3 --worker-machine-count=10
 
+		# Generates a configuration file for creating workload clusters setting an additional template variable.
+		clusterctl config cluster my-cluster --set AWS_REGION=eu-west-1
+
 		# Generates a configuration file for creating workload clusters using a template stored in a ConfigMap.
 		clusterctl config cluster my-cluster --from-config-map MyTemplates
 
 		# Generates a configuration file for creating workload clusters using a template from a specific URL.
 		clusterctl config cluster my-cluster --from https://github.com/foo-org/foo-repository/blob/master/cluster-template.yaml
 
+		# Generates a configuration file for creating workload clusters using a template downloaded from an HTTPS URL,
+		# verifying its SHA256 checksum.
+		clusterctl config cluster my-cluster --from-template-url https://example.com/templates/cluster-template.yaml \
+			--from-template-url-checksum 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+
 		# Generates a configuration file for creating workload clusters using a template stored locally.
 		clusterctl config cluster my-cluster --from ~/workspace/cluster-template.yaml`),
 
@@ -107,6 +119,8 @@ func init() {
 		"The number of control plane machines for the workload cluster.")
 	configClusterClusterCmd.Flags().Int64Var(&cc.workerMachineCount, "worker-machine-count", 0,
 		"The number of worker machines for the workload cluster.")
+	configClusterClusterCmd.Flags().StringArrayVar(&cc.variables, "set", nil,
+		"A variable for the template processing in the KEY=VALUE format, taking precedence over the os env variables, the clusterctl config file and the other flags. Can be repeated")
 
 	// flags for the repository source
 	configClusterClusterCmd.Flags().StringVarP(&cc.infrastructureProvider, "infrastructure", "i", "",
@@ -118,6 +132,12 @@ func init() {
 	configClusterClusterCmd.Flags().StringVar(&cc.url, "from", "",
 		"The URL to read the workload cluster template from. If unspecified, the infrastructure provider repository URL will be used")
 
+	// flags for the https source
+	configClusterClusterCmd.Flags().StringVar(&cc.templateURL, "from-template-url", "",
+		"The HTTPS URL to download the workload cluster template from. This can be used as alternative to read from the provider repository, from an URL or from a ConfigMap")
+	configClusterClusterCmd.Flags().StringVar(&cc.templateChecksum, "from-template-url-checksum", "",
+		"The SHA256 checksum of the template downloaded using --from-template-url. If set, the command fails if the downloaded template does not match")
+
 	// flags for the config map source
 	configClusterClusterCmd.Flags().StringVar(&cc.configMapName, "from-config-map", "",
 		"The ConfigMap to read the workload cluster template from. This can be used as alternative to read from the provider repository or from an URL")
@@ -139,12 +159,18 @@ func runGetClusterTemplate(cmd *cobra.Command, name string) error {
 		return err
 	}
 
+	extraVariables, err := parseVariables(cc.variables)
+	if err != nil {
+		return err
+	}
+
 	templateOptions := client.GetClusterTemplateOptions{
 		Kubeconfig:        client.Kubeconfig{Path: cc.kubeconfig, Context: cc.kubeconfigContext},
 		ClusterName:       name,
 		TargetNamespace:   cc.targetNamespace,
 		KubernetesVersion: cc.kubernetesVersion,
 		ListVariablesOnly: cc.listVariables,
+		ExtraVariables:    extraVariables,
 	}
 
 	if cmd.Flags().Changed("control-plane-machine-count") {
@@ -160,6 +186,15 @@ func runGetClusterTemplate(cmd *cobra.Command, name string) error {
 		}
 	}
 
+	if cc.templateURL != "" {
+		templateOptions.HTTPSSource = &client.HTTPSSourceOptions{
+			URL:      cc.templateURL,
+			Checksum: cc.templateChecksum,
+		}
+	} else if cc.templateChecksum != "" {
+		return errors.New("--from-template-url-checksum can be used only together with --from-template-url")
+	}
+
 	if cc.configMapNamespace != "" || cc.configMapName != "" || cc.configMapDataKey != "" {
 		templateOptions.ConfigMapSource = &client.ConfigMapSourceOptions{
 			Namespace: cc.configMapNamespace,
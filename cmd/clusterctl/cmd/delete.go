@@ -17,6 +17,13 @@ limitations under the License.
 package cmd
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
@@ -33,6 +40,7 @@ type deleteOptions struct {
 	includeNamespace        bool
 	includeCRDs             bool
 	deleteAll               bool
+	yes                     bool
 }
 
 var dd = &deleteOptions{}
@@ -84,8 +92,13 @@ var deleteCmd = &cobra.Command{
 		# all the related objects (e.g. AWSClusters, AWSMachines etc.).
 		# Important! As a consequence of this operation, all the corresponding resources managed by
 		# the AWS infrastructure provider are orphaned and there might be ongoing costs incurred as a result of this.
+		# The CRDs and the number of related objects are listed, and the deletion must be confirmed.
 		clusterctl delete --infrastructure aws --include-crd
 
+		# Delete the AWS infrastructure provider and related CRDs without asking for confirmation,
+		# e.g. when running in a script.
+		clusterctl delete --infrastructure aws --include-crd --yes
+
 		# Delete the AWS infrastructure provider and its hosting Namespace. Please note that this forces deletion of
 		# all objects existing in the namespace.
 		# Important! As a consequence of this operation, all the corresponding resources managed by
@@ -113,6 +126,8 @@ func init() {
 		"Forces the deletion of the namespace where the providers are hosted (and of all the contained objects)")
 	deleteCmd.Flags().BoolVar(&dd.includeCRDs, "include-crd", false,
 		"Forces the deletion of the provider's CRDs (and of all the related objects)")
+	deleteCmd.Flags().BoolVarP(&dd.yes, "yes", "y", false,
+		"Skip the confirmation of the deletion of the provider's CRDs; required with --include-crd when not running in a terminal")
 
 	deleteCmd.Flags().StringVar(&dd.coreProvider, "core", "",
 		"Core provider version (e.g. cluster-api:v0.3.0) to delete from the management cluster")
@@ -148,7 +163,7 @@ func runDelete() error {
 		return errors.New("At least one of --core, --bootstrap, --control-plane, --infrastructure should be specified or the --all flag should be set")
 	}
 
-	if err := c.Delete(client.DeleteOptions{
+	options := client.DeleteOptions{
 		Kubeconfig:              client.Kubeconfig{Path: dd.kubeconfig, Context: dd.kubeconfigContext},
 		IncludeNamespace:        dd.includeNamespace,
 		IncludeCRDs:             dd.includeCRDs,
@@ -158,9 +173,53 @@ func runDelete() error {
 		InfrastructureProviders: dd.infrastructureProviders,
 		ControlPlaneProviders:   dd.controlPlaneProviders,
 		DeleteAll:               dd.deleteAll,
-	}); err != nil {
+	}
+	if !dd.yes {
+		options.ConfirmCRDsDeletion = confirmCRDsDeletion(os.Stdin, os.Stdout, isTerminal(os.Stdin))
+	}
+
+	if err := c.Delete(options); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// confirmCRDsDeletion returns a function listing the CRDs to be deleted, with the number of objects deleted
+// together with each of them, and asking for confirmation on in; when not interactive, the deletion is refused
+// because it can only be confirmed with the --yes flag.
+func confirmCRDsDeletion(in io.Reader, out io.Writer, interactive bool) func(crds []client.CustomResourceDefinitionSummary) error {
+	return func(crds []client.CustomResourceDefinitionSummary) error {
+		if !interactive {
+			return errors.New("Deleting the CRDs deletes all the related objects in the management cluster; use the --yes flag to confirm the deletion when not running in a terminal")
+		}
+
+		fmt.Fprintln(out, "The following CRDs, and all the related objects in the management cluster, will be deleted:")
+		w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+		fmt.Fprintln(w, "CRD\tOBJECTS")
+		for _, crd := range crds {
+			fmt.Fprintf(w, "%s\t%d\n", crd.Name, crd.CustomResources)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprint(out, "Are you sure you want to continue? [y/N] ")
+
+		answer, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return errors.Wrap(err, "failed to read the confirmation")
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return nil
+		default:
+			return errors.New("Deletion aborted")
+		}
+	}
+}
+
+// isTerminal returns true if the file is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+func Test_confirmCRDsDeletion(t *testing.T) {
+	crds := []client.CustomResourceDefinitionSummary{
+		{Name: "clusters.cluster.x-k8s.io", CustomResources: 2},
+		{Name: "machines.cluster.x-k8s.io", CustomResources: 6},
+	}
+
+	tests := []struct {
+		name        string
+		interactive bool
+		input       string
+		wantPrompt  bool
+		wantErr     bool
+	}{
+		{
+			name:        "non-interactive deletion is refused",
+			interactive: false,
+			input:       "y\n",
+			wantPrompt:  false,
+			wantErr:     true,
+		},
+		{
+			name:        "interactive deletion confirmed with y",
+			interactive: true,
+			input:       "y\n",
+			wantPrompt:  true,
+			wantErr:     false,
+		},
+		{
+			name:        "interactive deletion confirmed with yes",
+			interactive: true,
+			input:       "Yes\n",
+			wantPrompt:  true,
+			wantErr:     false,
+		},
+		{
+			name:        "interactive deletion not confirmed",
+			interactive: true,
+			input:       "n\n",
+			wantPrompt:  true,
+			wantErr:     true,
+		},
+		{
+			name:        "interactive deletion not confirmed by default",
+			interactive: true,
+			input:       "\n",
+			wantPrompt:  true,
+			wantErr:     true,
+		},
+		{
+			name:        "interactive deletion not confirmed without input",
+			interactive: true,
+			input:       "",
+			wantPrompt:  true,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			out := &bytes.Buffer{}
+			err := confirmCRDsDeletion(strings.NewReader(tt.input), out, tt.interactive)(crds)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			if tt.wantPrompt {
+				g.Expect(out.String()).To(ContainSubstring("clusters.cluster.x-k8s.io   2"))
+				g.Expect(out.String()).To(ContainSubstring("machines.cluster.x-k8s.io   6"))
+				g.Expect(out.String()).To(HaveSuffix("Are you sure you want to continue? [y/N] "))
+			} else {
+				g.Expect(out.String()).To(BeEmpty())
+			}
+		})
+	}
+}
@@ -56,7 +56,9 @@ type describeClusterOptions struct {
 
 	namespace           string
 	showOtherConditions string
+	showConditionTypes  string
 	disableNoEcho       bool
+	grouping            bool
 	disableGrouping     bool
 }
 
@@ -80,9 +82,12 @@ var describeClusterClusterCmd = &cobra.Command{
 		# Describe the cluster named test-1 showing all the conditions for a specific machine.
 		clusterctl describe cluster test-1 --show-conditions Machine/m1
 
-		# Describe the cluster named test-1 disabling automatic grouping of objects with the same ready condition 
+		# Describe the cluster named test-1 showing, for all the objects, only the conditions that are not True.
+		clusterctl describe cluster test-1 --show-conditions all --show-condition-types errors
+
+		# Describe the cluster named test-1 disabling automatic grouping of machines with the same phase and conditions
 		# e.g. un-group all the machines with Ready=true instead of showing a single group node.
-		clusterctl describe cluster test-1 --disable-grouping
+		clusterctl describe cluster test-1 --grouping=false
 
 		# Describe the cluster named test-1 disabling automatic echo suppression 
         # e.g. show the infrastructure machine objects, no matter if the current state is already reported by the machine's Ready condition.
@@ -104,10 +109,18 @@ func init() {
 
 	describeClusterClusterCmd.Flags().StringVar(&dc.showOtherConditions, "show-conditions", "",
 		" list of comma separated kind or kind/name for which the command should show all the object's conditions (use 'all' to show conditions for everything).")
+	describeClusterClusterCmd.Flags().StringVar(&dc.showConditionTypes, "show-condition-types", "all",
+		" list of comma separated condition types to show for the objects selected by --show-conditions (use 'errors' to show only the conditions that are not True).")
 	describeClusterClusterCmd.Flags().BoolVar(&dc.disableNoEcho, "disable-no-echo", false, ""+
 		"Disable hiding of a MachineInfrastructure and BootstrapConfig when ready condition is true or it has the Status, Severity and Reason of the machine's object.")
+	describeClusterClusterCmd.Flags().BoolVar(&dc.grouping, "grouping", true,
+		"Groups machines when they have the same phase and their conditions have the same Status, Severity and Reason.")
 	describeClusterClusterCmd.Flags().BoolVar(&dc.disableGrouping, "disable-grouping", false,
 		"Disable grouping machines when ready condition has the same Status, Severity and Reason.")
+	_ = describeClusterClusterCmd.Flags().MarkDeprecated("disable-grouping", "use --grouping=false instead")
+
+	describeClusterClusterCmd.ValidArgsFunction = clusterNameCompletionFunc(&dc.kubeconfig, &dc.kubeconfigContext, &dc.namespace)
+	_ = describeClusterClusterCmd.RegisterFlagCompletionFunc("namespace", namespaceCompletionFunc(&dc.kubeconfig, &dc.kubeconfigContext))
 
 	describeCmd.AddCommand(describeClusterClusterCmd)
 }
@@ -123,8 +136,9 @@ func runDescribeCluster(name string) error {
 		Namespace:           dc.namespace,
 		ClusterName:         name,
 		ShowOtherConditions: dc.showOtherConditions,
+		ShowConditionTypes:  dc.showConditionTypes,
 		DisableNoEcho:       dc.disableNoEcho,
-		DisableGrouping:     dc.disableGrouping,
+		DisableGrouping:     !dc.grouping || dc.disableGrouping,
 	})
 	if err != nil {
 		return err
@@ -211,7 +225,7 @@ func addOtherConditions(prefix string, tbl *uitable.Table, objectTree *tree.Obje
 		childrenPipe = pipe
 	}
 
-	otherConditions := tree.GetOtherConditions(obj)
+	otherConditions := objectTree.GetOtherConditions(obj)
 	for i := range otherConditions {
 		otherCondition := otherConditions[i]
 		otherDescriptor := newConditionDescriptor(otherCondition)
@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 )
@@ -28,6 +30,7 @@ import (
 type generateYAMLOptions struct {
 	url           string
 	listVariables bool
+	variables     []string
 }
 
 var gyOpts = &generateYAMLOptions{}
@@ -41,8 +44,8 @@ var generateYamlCmd = &cobra.Command{
 		clusterctl ships with a simple yaml processor that performs variable
 		substitution that takes into account of default values.
 
-		Variable values are either sourced from the clusterctl config file or
-		from environment variables`),
+		Variable values are either sourced from the clusterctl config file,
+		from environment variables or from the --set flag, which takes precedence`),
 
 	Example: Examples(`
 		# Generates a configuration file with variable values using
@@ -53,6 +56,10 @@ var generateYamlCmd = &cobra.Command{
 		a template stored locally.
 		clusterctl generate yaml --from ~/workspace/cluster-template.yaml
 
+		# Generates a configuration file with variable values using
+		a template stored locally, overriding the value of a variable.
+		clusterctl generate yaml --from ~/workspace/cluster-template.yaml --set CLUSTER_NAME=my-cluster
+
 		# Prints list of variables used in the local template
 		clusterctl generate yaml --from ~/workspace/cluster-template.yaml --list-variables
 
@@ -70,6 +77,10 @@ func init() {
 	generateYamlCmd.Flags().StringVar(&gyOpts.url, "from", "-",
 		"The URL to read the template from. It defaults to '-' which reads from stdin.")
 
+	// flags for the template variables
+	generateYamlCmd.Flags().StringArrayVar(&gyOpts.variables, "set", nil,
+		"A variable for the template processing in the KEY=VALUE format, taking precedence over the os env variables and the clusterctl config file. Can be repeated")
+
 	// other flags
 	generateYamlCmd.Flags().BoolVar(&gyOpts.listVariables, "list-variables", false,
 		"Returns the list of variables expected by the template instead of the template yaml")
@@ -82,8 +93,13 @@ func generateYAML(r io.Reader, w io.Writer) error {
 	if err != nil {
 		return err
 	}
+	extraVariables, err := parseVariables(gyOpts.variables)
+	if err != nil {
+		return err
+	}
 	options := client.ProcessYAMLOptions{
 		ListVariablesOnly: gyOpts.listVariables,
+		ExtraVariables:    extraVariables,
 	}
 	if gyOpts.url != "" {
 		if gyOpts.url == "-" {
@@ -118,3 +134,20 @@ func generateYAML(r io.Reader, w io.Writer) error {
 	_, err = fmt.Fprintln(w, string(out))
 	return err
 }
+
+// parseVariables parses the KEY=VALUE variables of the --set flags. The value is everything after the first "=",
+// so it can contain any character, "=" included.
+func parseVariables(variables []string) (map[string]string, error) {
+	if len(variables) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(variables))
+	for _, v := range variables {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, errors.Errorf("invalid variable %q, it must be in the KEY=VALUE format", v)
+		}
+		out[strings.TrimSpace(kv[0])] = kv[1]
+	}
+	return out, nil
+}
@@ -116,6 +116,101 @@ v3: default3
 
 }
 
+func Test_generateYAMLVariablesPrecedence(t *testing.T) {
+	g := NewWithT(t)
+
+	template, cleanupTemplate := createTempFile(g, `config: ${PRECEDENCE_CONFIG}
+env: ${PRECEDENCE_ENV}
+set: ${PRECEDENCE_SET}
+special: "${PRECEDENCE_SPECIAL}"`)
+	defer cleanupTemplate()
+
+	config, cleanupConfig := createTempFile(g, `PRECEDENCE_CONFIG: from-config
+PRECEDENCE_ENV: from-config
+PRECEDENCE_SET: from-config`)
+	defer cleanupConfig()
+
+	defer func(oldCfgFile string) { cfgFile = oldCfgFile }(cfgFile)
+	cfgFile = config
+
+	g.Expect(os.Setenv("PRECEDENCE_ENV", "from-env")).To(Succeed())
+	defer os.Unsetenv("PRECEDENCE_ENV")
+	g.Expect(os.Setenv("PRECEDENCE_SET", "from-env")).To(Succeed())
+	defer os.Unsetenv("PRECEDENCE_SET")
+
+	gyOpts = &generateYAMLOptions{
+		url:       template,
+		variables: []string{"PRECEDENCE_SET=from-set", "PRECEDENCE_SPECIAL=a=b, c:d ${e}"},
+	}
+	buf := bytes.NewBufferString("")
+	g.Expect(generateYAML(nil, buf)).To(Succeed())
+	// The values are substituted as they are, the variables they contain are not expanded.
+	g.Expect(buf.String()).To(Equal(`config: from-config
+env: from-env
+set: from-set
+special: a=b, c:d ${e}
+`))
+
+	// The variables set with --set are still listed as variables of the template.
+	gyOpts.listVariables = true
+	buf = bytes.NewBufferString("")
+	g.Expect(generateYAML(nil, buf)).To(Succeed())
+	g.Expect(buf.String()).To(Equal(`Variables:
+  - PRECEDENCE_CONFIG
+  - PRECEDENCE_ENV
+  - PRECEDENCE_SET
+  - PRECEDENCE_SPECIAL
+`))
+}
+
+func Test_parseVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []string
+		want      map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "no variables",
+			variables: nil,
+			want:      nil,
+		},
+		{
+			name:      "variables with special characters in the value",
+			variables: []string{"FOO=bar", "BAZ=a=b,c", "EMPTY="},
+			want:      map[string]string{"FOO": "bar", "BAZ": "a=b,c", "EMPTY": ""},
+		},
+		{
+			name:      "the last value of a repeated variable wins",
+			variables: []string{"FOO=bar", "FOO=baz"},
+			want:      map[string]string{"FOO": "baz"},
+		},
+		{
+			name:      "variable without value",
+			variables: []string{"FOO"},
+			wantErr:   true,
+		},
+		{
+			name:      "variable without key",
+			variables: []string{"=bar"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseVariables(tt.variables)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
 // createTempFile creates a temporary yaml file inside a temp dir. It returns
 // the filepath and a cleanup function for the temp directory.
 func createTempFile(g *WithT, contents string) (string, func()) {
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
@@ -27,6 +28,8 @@ type getKubeconfigOptions struct {
 	kubeconfig        string
 	kubeconfigContext string
 	namespace         string
+	ttl               time.Duration
+	clusterRole       string
 }
 
 var gk = &getKubeconfigOptions{}
@@ -42,7 +45,13 @@ var getKubeconfigCmd = &cobra.Command{
 		clusterctl get kubeconfig <name of workload cluster>
 
 		# Get the workload cluster's kubeconfig in a particular namespace.
-		clusterctl get kubeconfig <name of workload cluster> --namespace foo`),
+		clusterctl get kubeconfig <name of workload cluster> --namespace foo
+
+		# Get a kubeconfig for the workload cluster using a token which expires after one hour.
+		clusterctl get kubeconfig <name of workload cluster> --ttl 1h
+
+		# Get a kubeconfig for the workload cluster using a token which expires after one hour, with the edit ClusterRole.
+		clusterctl get kubeconfig <name of workload cluster> --ttl 1h --cluster-role edit`),
 
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -57,6 +66,14 @@ func init() {
 		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
 	getKubeconfigCmd.Flags().StringVar(&gk.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	getKubeconfigCmd.Flags().DurationVar(&gk.ttl, "ttl", 0,
+		"If set, the returned kubeconfig uses a bootstrap token which expires after the given duration, instead of the admin credentials of the workload cluster.")
+	getKubeconfigCmd.Flags().StringVar(&gk.clusterRole, "cluster-role", "view",
+		"ClusterRole granted to the bootstrap token of the returned kubeconfig. Only used when --ttl is set.")
+
+	getKubeconfigCmd.ValidArgsFunction = clusterNameCompletionFunc(&gk.kubeconfig, &gk.kubeconfigContext, &gk.namespace)
+	_ = getKubeconfigCmd.RegisterFlagCompletionFunc("namespace", namespaceCompletionFunc(&gk.kubeconfig, &gk.kubeconfigContext))
+
 	getCmd.AddCommand(getKubeconfigCmd)
 }
 
@@ -70,6 +87,8 @@ func runGetKubeconfig(workloadClusterName string) error {
 		Kubeconfig:          client.Kubeconfig{Path: gk.kubeconfig, Context: gk.kubeconfigContext},
 		WorkloadClusterName: workloadClusterName,
 		Namespace:           gk.namespace,
+		TTL:                 gk.ttl,
+		ClusterRole:         gk.clusterRole,
 	}
 
 	out, err := c.GetKubeconfig(options)
@@ -32,6 +32,7 @@ type initOptions struct {
 	infrastructureProviders []string
 	targetNamespace         string
 	watchingNamespace       string
+	repositoryDir           string
 	listImages              bool
 }
 
@@ -79,6 +80,12 @@ var initCmd = &cobra.Command{
 		# Initialize a management cluster with a custom watching namespace for the given provider.
 		clusterctl init --infrastructure aws --watching-namespace=foo
 
+		# Initialize a management cluster with the provider components read from a local directory, e.g. for air-gapped environments.
+		#
+		# Note: the directory must contain the metadata.yaml and components files of each provider version,
+		#       e.g. /tmp/repository/infrastructure-aws/v0.6.4/infrastructure-components.yaml.
+		clusterctl init --infrastructure aws --repository-dir /tmp/repository
+
 		# Lists the container images required for initializing the management cluster.
 		#
 		# Note: This command is a dry-run; it won't perform any action other than printing to screen.
@@ -106,6 +113,8 @@ func init() {
 		"The target namespace where the providers should be deployed. If unspecified, the provider components' default namespace is used.")
 	initCmd.Flags().StringVar(&initOpts.watchingNamespace, "watching-namespace", "",
 		"Namespace the providers should watch when reconciling objects. If unspecified, all namespaces are watched.")
+	initCmd.Flags().StringVar(&initOpts.repositoryDir, "repository-dir", "",
+		"Local directory the provider components are read from instead of the provider repositories, with the layout {provider-label}/{version}/{file}. If unspecified, the provider repositories are used.")
 
 	// TODO: Move this to a sub-command or similar, it shouldn't really be a flag.
 	initCmd.Flags().BoolVar(&initOpts.listImages, "list-images", false,
@@ -128,6 +137,7 @@ func runInit() error {
 		InfrastructureProviders: initOpts.infrastructureProviders,
 		TargetNamespace:         initOpts.targetNamespace,
 		WatchingNamespace:       initOpts.watchingNamespace,
+		RepositoryDir:           initOpts.repositoryDir,
 		LogUsageInstructions:    true,
 	}
 
@@ -17,11 +17,36 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 )
 
+const (
+	// MoveOutputText is an option used to print the move progress in text format.
+	MoveOutputText = "text"
+	// MoveOutputJSON is an option used to print the move progress in json format, one object per line.
+	MoveOutputJSON = "json"
+)
+
+var (
+	// MoveOutputs is a list of valid move progress outputs.
+	MoveOutputs = []string{MoveOutputText, MoveOutputJSON}
+
+	// movePhaseDescriptions maps each move phase to the text printed while reporting its progress.
+	movePhaseDescriptions = map[client.MovePhase]string{
+		client.MovePhasePauseSource:  "Pausing the source Clusters",
+		client.MovePhaseCreateTarget: "Creating objects in the target cluster",
+		client.MovePhaseDeleteSource: "Deleting objects from the source cluster",
+		client.MovePhaseResumeTarget: "Resuming the target Clusters",
+	}
+)
+
 type moveOptions struct {
 	fromKubeconfig        string
 	fromKubeconfigContext string
@@ -29,6 +54,7 @@ type moveOptions struct {
 	toKubeconfigContext   string
 	namespace             string
 	dryRun                bool
+	output                string
 }
 
 var mo = &moveOptions{}
@@ -43,7 +69,13 @@ var moveCmd = &cobra.Command{
 
 	Example: Examples(`
 		Move Cluster API objects and all dependencies between management clusters.
-		clusterctl move --to-kubeconfig=target-kubeconfig.yaml`),
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml
+
+		Print the Cluster API objects that would be moved, in the order they would be moved, without changing any cluster.
+		clusterctl move --dry-run
+
+		Move Cluster API objects and all dependencies between management clusters, printing the progress in json format.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml -o json`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runMove()
@@ -62,7 +94,11 @@ func init() {
 	moveCmd.Flags().StringVarP(&mo.namespace, "namespace", "n", "",
 		"The namespace where the workload cluster is hosted. If unspecified, the current context's namespace is used.")
 	moveCmd.Flags().BoolVar(&mo.dryRun, "dry-run", false,
-		"Enable dry run, don't really perform the move actions")
+		"Enable dry run, don't really perform the move actions but print the objects that would be moved")
+	moveCmd.Flags().StringVarP(&mo.output, "output", "o", MoveOutputText,
+		fmt.Sprintf("Output format for the move progress. Valid values: %v.", MoveOutputs))
+
+	_ = moveCmd.RegisterFlagCompletionFunc("namespace", namespaceCompletionFunc(&mo.fromKubeconfig, &mo.fromKubeconfigContext))
 
 	RootCmd.AddCommand(moveCmd)
 }
@@ -73,6 +109,10 @@ func runMove() error {
 		return errors.New("please specify a target cluster using the --to-kubeconfig flag")
 	}
 
+	if mo.output != MoveOutputText && mo.output != MoveOutputJSON {
+		return errors.Errorf("Invalid output format %q. Valid values: %v.", mo.output, MoveOutputs)
+	}
+
 	c, err := client.New(cfgFile)
 	if err != nil {
 		return err
@@ -83,8 +123,21 @@ func runMove() error {
 		ToKubeconfig:   client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
 		Namespace:      mo.namespace,
 		DryRun:         mo.dryRun,
+		Progress:       printMoveProgress(os.Stdout, mo.output),
 	}); err != nil {
 		return err
 	}
 	return nil
 }
+
+// printMoveProgress returns a function printing the progress of a move operation to out, in the given output format.
+func printMoveProgress(out io.Writer, output string) func(client.MoveProgress) {
+	return func(p client.MoveProgress) {
+		switch output {
+		case MoveOutputJSON:
+			_ = json.NewEncoder(out).Encode(p)
+		default:
+			fmt.Fprintf(out, "%s: %d/%d\n", movePhaseDescriptions[p.Phase], p.Processed, p.Total)
+		}
+	}
+}
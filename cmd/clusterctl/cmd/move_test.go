@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+func Test_printMoveProgress(t *testing.T) {
+	progress := client.MoveProgress{Phase: client.MovePhaseCreateTarget, Processed: 3, Total: 10}
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "text output",
+			output: MoveOutputText,
+			want:   "Creating objects in the target cluster: 3/10\n",
+		},
+		{
+			name:   "json output",
+			output: MoveOutputJSON,
+			want:   "{\"phase\":\"CreateTarget\",\"processed\":3,\"total\":10}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			out := &bytes.Buffer{}
+			printMoveProgress(out, tt.output)(progress)
+			g.Expect(out.String()).To(Equal(tt.want))
+		})
+	}
+}
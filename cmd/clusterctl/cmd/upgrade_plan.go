@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
@@ -116,7 +117,10 @@ func runUpgradePlan() error {
 		w.Flush()
 		fmt.Println("")
 
-		if upgradeAvailable {
+		if len(plan.IncompatibleProviders) > 0 {
+			fmt.Printf("The management group could not be upgraded to the %s contract: the following providers do not have a release supporting it: %s.\n",
+				plan.Contract, strings.Join(plan.IncompatibleProviders, ", "))
+		} else if upgradeAvailable {
 			if plan.Contract == clusterv1.GroupVersion.Version {
 				fmt.Println("You can now apply the upgrade by executing the following command:")
 				fmt.Println("")
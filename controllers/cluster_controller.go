@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -113,6 +114,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, cluster) {
 		log.Info("Reconciliation is paused for this object")
+		r.recorder.Event(cluster, corev1.EventTypeNormal, EventReconciliationPaused, "Reconciliation is paused for this object")
 		return ctrl.Result{}, nil
 	}
 
@@ -154,12 +156,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 
 func patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clusterv1.Cluster, options ...patch.Option) error {
 	// Always update the readyCondition by summarizing the state of other conditions.
-	conditions.SetSummary(cluster,
-		conditions.WithConditions(
-			clusterv1.ControlPlaneReadyCondition,
-			clusterv1.InfrastructureReadyCondition,
-		),
-	)
+	setReadyCondition(cluster)
 
 	// Patch the object, ignoring conflicts on the conditions owned by this controller.
 	// Also, if requested, we are adding additional options like e.g. Patch ObservedGeneration when issuing the
@@ -174,6 +171,25 @@ func patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clust
 	return patchHelper.Patch(ctx, cluster, options...)
 }
 
+// setReadyCondition sets the Ready condition of the cluster to the summary of the readiness of its infrastructure
+// and control plane, in this order of priority for the reason and message of the condition.
+// The readiness of a referenced infrastructure or control plane object not reported yet is considered false.
+func setReadyCondition(cluster *clusterv1.Cluster) {
+	if cluster.Spec.InfrastructureRef != nil && !conditions.Has(cluster, clusterv1.InfrastructureReadyCondition) {
+		conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, "")
+	}
+	if cluster.Spec.ControlPlaneRef != nil && !conditions.Has(cluster, clusterv1.ControlPlaneReadyCondition) {
+		conditions.MarkFalse(cluster, clusterv1.ControlPlaneReadyCondition, clusterv1.WaitingForControlPlaneFallbackReason, clusterv1.ConditionSeverityInfo, "")
+	}
+
+	conditions.SetSummary(cluster,
+		conditions.WithConditions(
+			clusterv1.InfrastructureReadyCondition,
+			clusterv1.ControlPlaneReadyCondition,
+		),
+	)
+}
+
 // reconcile handles cluster reconciliation.
 func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	phases := []func(context.Context, *clusterv1.Cluster) (ctrl.Result, error){
@@ -220,11 +236,21 @@ func (r *ClusterReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 
 		var errs []error
 
+		// Control plane machines are deleted only after all the worker nodes are gone, so the API server
+		// stays available for draining them.
+		workers := descendants.workerLength()
+		if workers > 0 && len(descendants.controlPlaneMachines.Items) > 0 {
+			log.Info("Cluster still has worker descendants - deleting them before the control plane machines", "count", workers)
+		}
+
 		for _, child := range children {
 			if !child.GetDeletionTimestamp().IsZero() {
 				// Don't handle deleted child
 				continue
 			}
+			if machine, ok := child.(*clusterv1.Machine); ok && workers > 0 && util.IsControlPlaneMachine(machine) {
+				continue
+			}
 			gvk := child.GetObjectKind().GroupVersionKind().String()
 
 			log.Info("Deleting child object", "gvk", gvk, "name", child.GetName())
@@ -323,10 +349,16 @@ type clusterDescendants struct {
 
 // length returns the number of descendants
 func (c *clusterDescendants) length() int {
+	return c.workerLength() +
+		len(c.controlPlaneMachines.Items)
+}
+
+// workerLength returns the number of descendants running or managing worker nodes.
+func (c *clusterDescendants) workerLength() int {
 	return len(c.machineDeployments.Items) +
 		len(c.machineSets.Items) +
-		len(c.controlPlaneMachines.Items) +
-		len(c.workerMachines.Items)
+		len(c.workerMachines.Items) +
+		len(c.machinePools.Items)
 }
 
 func (c *clusterDescendants) descendantNames() string {
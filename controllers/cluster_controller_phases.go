@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/external"
@@ -133,6 +134,26 @@ func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clus
 	return external.ReconcileOutput{Result: obj}, nil
 }
 
+// markExternalFailure marks the condition of the cluster false with Severity=Error if the external object reports
+// a failure, so the failure is surfaced by the Ready condition of the cluster even if the conditions of
+// the external object do not reflect it.
+func markExternalFailure(cluster *clusterv1.Cluster, t clusterv1.ConditionType, reason string, obj *unstructured.Unstructured) error {
+	failureReason, failureMessage, err := external.FailuresFrom(obj)
+	if err != nil {
+		return err
+	}
+	if failureReason == "" && failureMessage == "" {
+		return nil
+	}
+
+	message := failureMessage
+	if message == "" {
+		message = failureReason
+	}
+	conditions.MarkFalse(cluster, t, reason, clusterv1.ConditionSeverityError, "%s %q failed: %s", obj.GetKind(), obj.GetName(), message)
+	return nil
+}
+
 // reconcileInfrastructure reconciles the Spec.InfrastructureRef object on a Cluster.
 func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -173,6 +194,9 @@ func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster
 		conditions.UnstructuredGetter(infraConfig),
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
 	)
+	if err := markExternalFailure(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureFailedReason, infraConfig); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	if !ready {
 		log.V(3).Info("Infrastructure provider is not ready yet")
@@ -234,6 +258,9 @@ func (r *ClusterReconciler) reconcileControlPlane(ctx context.Context, cluster *
 		conditions.UnstructuredGetter(controlPlaneConfig),
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForControlPlaneFallbackReason, clusterv1.ConditionSeverityInfo, ""),
 	)
+	if err := markExternalFailure(cluster, clusterv1.ControlPlaneReadyCondition, clusterv1.ControlPlaneFailedReason, controlPlaneConfig); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// Update cluster.Status.ControlPlaneInitialized if it hasn't already been set
 	// Determine if the control plane provider is initialized.
@@ -30,6 +30,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -382,3 +383,179 @@ func TestClusterReconciler_reconcilePhase(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterReconcileReadyCondition(t *testing.T) {
+	newExternalObject := func(name string, status map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "test-namespace",
+			},
+		}}
+		if status != nil {
+			obj.Object["status"] = status
+		}
+		return obj
+	}
+	newCluster := func() *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneEndpoint: clusterv1.APIEndpoint{
+					Host: "1.2.3.4",
+					Port: 8443,
+				},
+				InfrastructureRef: &corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+					Kind:       "InfrastructureMachine",
+					Name:       "test-infrastructure",
+				},
+				// The control plane uses the generic infrastructure kind, the controller does not depend on it.
+				ControlPlaneRef: &corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+					Kind:       "InfrastructureMachine",
+					Name:       "test-control-plane",
+				},
+			},
+		}
+	}
+	reconcileReady := func(g *WithT, c client.Client, cluster *clusterv1.Cluster) {
+		r := &ClusterReconciler{
+			Client: c,
+		}
+		_, err := r.reconcileInfrastructure(ctx, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, err = r.reconcileControlPlane(ctx, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		setReadyCondition(cluster)
+	}
+
+	ready := map[string]interface{}{"ready": true}
+	notReady := map[string]interface{}{"ready": false}
+
+	tests := []struct {
+		name          string
+		infraStatus   map[string]interface{}
+		cpStatus      map[string]interface{}
+		cpMissing     bool
+		wantReady     bool
+		wantReason    string
+		wantSeverity  clusterv1.ConditionSeverity
+		wantInMessage string
+	}{
+		{
+			name:        "infrastructure and control plane ready",
+			infraStatus: ready,
+			cpStatus:    ready,
+			wantReady:   true,
+		},
+		{
+			name:         "infrastructure not ready",
+			infraStatus:  notReady,
+			cpStatus:     ready,
+			wantReason:   clusterv1.WaitingForInfrastructureFallbackReason,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:         "control plane not ready",
+			infraStatus:  ready,
+			cpStatus:     notReady,
+			wantReason:   clusterv1.WaitingForControlPlaneFallbackReason,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:         "control plane not found",
+			infraStatus:  ready,
+			cpMissing:    true,
+			wantReason:   clusterv1.WaitingForControlPlaneFallbackReason,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:          "infrastructure reporting a failure",
+			infraStatus:   map[string]interface{}{"ready": true, "failureReason": "InvalidConfiguration", "failureMessage": "invalid network"},
+			cpStatus:      ready,
+			wantReason:    clusterv1.InfrastructureFailedReason,
+			wantSeverity:  clusterv1.ConditionSeverityError,
+			wantInMessage: "invalid network",
+		},
+		{
+			name:          "control plane reporting a failure",
+			infraStatus:   ready,
+			cpStatus:      map[string]interface{}{"ready": false, "failureReason": "UpdateError"},
+			wantReason:    clusterv1.ControlPlaneFailedReason,
+			wantSeverity:  clusterv1.ConditionSeverityError,
+			wantInMessage: "UpdateError",
+		},
+		{
+			name:          "infrastructure and control plane reporting a failure",
+			infraStatus:   map[string]interface{}{"ready": false, "failureMessage": "invalid network"},
+			cpStatus:      map[string]interface{}{"ready": false, "failureMessage": "invalid version"},
+			wantReason:    clusterv1.InfrastructureFailedReason,
+			wantSeverity:  clusterv1.ConditionSeverityError,
+			wantInMessage: "invalid network",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+			g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := newCluster()
+			objects := []client.Object{
+				external.TestGenericInfrastructureCRD.DeepCopy(),
+				cluster,
+				newExternalObject("test-infrastructure", tt.infraStatus),
+			}
+			if !tt.cpMissing {
+				objects = append(objects, newExternalObject("test-control-plane", tt.cpStatus))
+			}
+			c := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+			reconcileReady(g, c, cluster)
+
+			readyCondition := conditions.Get(cluster, clusterv1.ReadyCondition)
+			g.Expect(readyCondition).NotTo(BeNil())
+			if tt.wantReady {
+				g.Expect(readyCondition.Status).To(Equal(corev1.ConditionTrue))
+				return
+			}
+			g.Expect(readyCondition.Status).To(Equal(corev1.ConditionFalse))
+			g.Expect(readyCondition.Reason).To(Equal(tt.wantReason))
+			g.Expect(readyCondition.Severity).To(Equal(tt.wantSeverity))
+			g.Expect(readyCondition.Message).To(ContainSubstring(tt.wantInMessage))
+		})
+	}
+
+	t.Run("ready condition turns false when a component degrades", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+		g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+		cluster := newCluster()
+		infraConfig := newExternalObject("test-infrastructure", ready)
+		c := fake.NewClientBuilder().WithObjects(
+			external.TestGenericInfrastructureCRD.DeepCopy(),
+			cluster,
+			infraConfig,
+			newExternalObject("test-control-plane", ready),
+		).Build()
+
+		reconcileReady(g, c, cluster)
+		g.Expect(conditions.IsTrue(cluster, clusterv1.ReadyCondition)).To(BeTrue())
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(infraConfig), infraConfig)).To(Succeed())
+		g.Expect(unstructured.SetNestedField(infraConfig.Object, "host unreachable", "status", "failureMessage")).To(Succeed())
+		g.Expect(c.Update(ctx, infraConfig)).To(Succeed())
+
+		reconcileReady(g, c, cluster)
+		g.Expect(conditions.IsFalse(cluster, clusterv1.ReadyCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(cluster, clusterv1.ReadyCondition)).To(Equal(clusterv1.InfrastructureFailedReason))
+		g.Expect(conditions.GetMessage(cluster, clusterv1.ReadyCondition)).To(ContainSubstring("host unreachable"))
+	})
+}
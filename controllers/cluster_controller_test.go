@@ -18,12 +18,14 @@ package controllers
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
@@ -667,6 +669,93 @@ func TestFilterOwnedDescendants(t *testing.T) {
 	g.Expect(actual).To(Equal(expected))
 }
 
+func TestClusterReconcilerReconcileDeleteControlPlaneMachinesLast(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	ownerRef := metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Cluster",
+		Name:       cluster.Name,
+	}
+	controlPlaneMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "control-plane",
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:             cluster.Name,
+				clusterv1.MachineControlPlaneLabelName: "",
+			},
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+		Spec: clusterv1.MachineSpec{ClusterName: cluster.Name},
+	}
+	drainingWorkerMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "draining-worker",
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: cluster.Name,
+			},
+			OwnerReferences:   []metav1.OwnerReference{ownerRef},
+			DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			Finalizers:        []string{clusterv1.MachineFinalizer},
+		},
+		Spec: clusterv1.MachineSpec{ClusterName: cluster.Name},
+	}
+	workerMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "worker",
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: cluster.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+		Spec: clusterv1.MachineSpec{ClusterName: cluster.Name},
+	}
+
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cluster, controlPlaneMachine, drainingWorkerMachine, workerMachine).Build()
+	r := &ClusterReconciler{
+		Client: c,
+	}
+
+	// The worker Machines are deleted first, while the control plane Machine is kept.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, util.ObjectKey(workerMachine), &clusterv1.Machine{}))).To(BeTrue())
+	controlPlane := &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(controlPlaneMachine), controlPlane)).To(Succeed())
+	g.Expect(controlPlane.DeletionTimestamp.IsZero()).To(BeTrue())
+
+	// The control plane Machine is not deleted as long as a worker Machine is being deleted.
+	res, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(c.Get(ctx, util.ObjectKey(controlPlaneMachine), controlPlane)).To(Succeed())
+	g.Expect(controlPlane.DeletionTimestamp.IsZero()).To(BeTrue())
+
+	// Once the worker Machines are gone, the control plane Machine is deleted.
+	g.Expect(c.Delete(ctx, drainingWorkerMachine)).To(Succeed())
+
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, util.ObjectKey(controlPlaneMachine), controlPlane))).To(BeTrue())
+}
+
 func TestReconcileControlPlaneInitializedControlPlaneRef(t *testing.T) {
 	g := NewWithT(t)
 
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drainutil implements utilities to drain the Nodes of workload clusters.
+package drainutil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+	kubedrain "sigs.k8s.io/cluster-api/third_party/kubernetes-drain"
+)
+
+// NewHelper returns a drain helper for the Nodes of a workload cluster, which evicts all the pods
+// except the ones managed by DaemonSets, and logs the evicted or deleted pods to log.
+func NewHelper(log logr.Logger, kubeClient kubernetes.Interface) *kubedrain.Helper {
+	return &kubedrain.Helper{
+		Client:              kubeClient,
+		Force:               true,
+		IgnoreAllDaemonSets: true,
+		DeleteLocalData:     true,
+		GracePeriodSeconds:  -1,
+		// If a pod is not evicted in 20 seconds, retry the eviction next time the
+		// object gets reconciled again (to allow other objects to be reconciled).
+		Timeout: 20 * time.Second,
+		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
+			verbStr := "Deleted"
+			if usingEviction {
+				verbStr = "Evicted"
+			}
+			log.Info(fmt.Sprintf("%s pod from Node", verbStr),
+				"pod", fmt.Sprintf("%s/%s", pod.Name, pod.Namespace))
+		},
+		Out:    Writer{klog.Info},
+		ErrOut: Writer{klog.Error},
+		DryRun: false,
+	}
+}
+
+// Writer implements io.Writer interface as a pass-through for klog.
+type Writer struct {
+	LogFunc func(args ...interface{})
+}
+
+// Write passes string(p) into writer's LogFunc and always returns len(p)
+func (w Writer) Write(p []byte) (n int, err error) {
+	w.LogFunc(string(p))
+	return len(p), nil
+}
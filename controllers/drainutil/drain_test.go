@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drainutil
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestNewHelper(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeClient := fake.NewSimpleClientset()
+	drainer := NewHelper(log.NullLogger{}, kubeClient)
+	g.Expect(drainer.Client).To(Equal(kubeClient))
+	g.Expect(drainer.Force).To(BeTrue())
+	g.Expect(drainer.IgnoreAllDaemonSets).To(BeTrue())
+	g.Expect(drainer.DeleteLocalData).To(BeTrue())
+	g.Expect(drainer.GracePeriodSeconds).To(Equal(-1))
+	g.Expect(drainer.Timeout).ToNot(BeZero())
+	g.Expect(drainer.DryRun).To(BeFalse())
+}
+
+func TestWriter(t *testing.T) {
+	g := NewWithT(t)
+
+	var logged []interface{}
+	w := Writer{LogFunc: func(args ...interface{}) { logged = append(logged, args...) }}
+
+	n, err := fmt.Fprint(w, "evicting pod default/app")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(n).To(Equal(len("evicting pod default/app")))
+	g.Expect(logged).To(ConsistOf("evicting pod default/app"))
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcilePaused(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	newObjects := func(clusterPaused bool, objectAnnotations map[string]string) []client.Object {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec:       clusterv1.ClusterSpec{Paused: clusterPaused},
+		}
+		objectMeta := func(name string) metav1.ObjectMeta {
+			return metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Labels:      map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+				Annotations: objectAnnotations,
+			}
+		}
+		if objectAnnotations != nil {
+			cluster.Annotations = objectAnnotations
+		}
+		return []client.Object{
+			cluster,
+			&clusterv1.Machine{
+				ObjectMeta: objectMeta("test-machine"),
+				Spec:       clusterv1.MachineSpec{ClusterName: cluster.Name},
+			},
+			&clusterv1.MachineSet{
+				ObjectMeta: objectMeta("test-machineset"),
+				Spec:       clusterv1.MachineSetSpec{ClusterName: cluster.Name},
+			},
+			&clusterv1.MachineDeployment{
+				ObjectMeta: objectMeta("test-machinedeployment"),
+				Spec:       clusterv1.MachineDeploymentSpec{ClusterName: cluster.Name},
+			},
+			&clusterv1.MachineHealthCheck{
+				ObjectMeta: objectMeta("test-machinehealthcheck"),
+				Spec:       clusterv1.MachineHealthCheckSpec{ClusterName: cluster.Name},
+			},
+		}
+	}
+
+	reconcilers := []struct {
+		name          string
+		newReconciler func(c client.Client, recorder record.EventRecorder) reconcile.Reconciler
+		newObject     func() client.Object
+		objectName    string
+	}{
+		{
+			name: "Cluster",
+			newReconciler: func(c client.Client, recorder record.EventRecorder) reconcile.Reconciler {
+				return &ClusterReconciler{Client: c, recorder: recorder}
+			},
+			newObject:  func() client.Object { return &clusterv1.Cluster{} },
+			objectName: "test-cluster",
+		},
+		{
+			name: "Machine",
+			newReconciler: func(c client.Client, recorder record.EventRecorder) reconcile.Reconciler {
+				return &MachineReconciler{Client: c, recorder: recorder}
+			},
+			newObject:  func() client.Object { return &clusterv1.Machine{} },
+			objectName: "test-machine",
+		},
+		{
+			name: "MachineSet",
+			newReconciler: func(c client.Client, recorder record.EventRecorder) reconcile.Reconciler {
+				return &MachineSetReconciler{Client: c, recorder: recorder}
+			},
+			newObject:  func() client.Object { return &clusterv1.MachineSet{} },
+			objectName: "test-machineset",
+		},
+		{
+			name: "MachineDeployment",
+			newReconciler: func(c client.Client, recorder record.EventRecorder) reconcile.Reconciler {
+				return &MachineDeploymentReconciler{Client: c, recorder: recorder}
+			},
+			newObject:  func() client.Object { return &clusterv1.MachineDeployment{} },
+			objectName: "test-machinedeployment",
+		},
+		{
+			name: "MachineHealthCheck",
+			newReconciler: func(c client.Client, recorder record.EventRecorder) reconcile.Reconciler {
+				return &MachineHealthCheckReconciler{Client: c, recorder: recorder}
+			},
+			newObject:  func() client.Object { return &clusterv1.MachineHealthCheck{} },
+			objectName: "test-machinehealthcheck",
+		},
+	}
+
+	pausedBy := []struct {
+		name              string
+		clusterPaused     bool
+		objectAnnotations map[string]string
+	}{
+		{
+			name:          "paused Cluster",
+			clusterPaused: true,
+		},
+		{
+			name:              "paused annotation",
+			objectAnnotations: map[string]string{clusterv1.PausedAnnotation: ""},
+		},
+	}
+
+	for _, rc := range reconcilers {
+		for _, pc := range pausedBy {
+			t.Run(rc.name+" with "+pc.name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(newObjects(pc.clusterPaused, pc.objectAnnotations)...).Build()
+				key := client.ObjectKey{Namespace: "default", Name: rc.objectName}
+
+				before := rc.newObject()
+				g.Expect(c.Get(ctx, key, before)).To(Succeed())
+
+				recorder := record.NewFakeRecorder(32)
+				result, err := rc.newReconciler(c, recorder).Reconcile(ctx, ctrl.Request{NamespacedName: key})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result).To(Equal(ctrl.Result{}))
+
+				// Neither the object nor its status are mutated while paused.
+				after := rc.newObject()
+				g.Expect(c.Get(ctx, util.ObjectKey(before), after)).To(Succeed())
+				g.Expect(after).To(Equal(before))
+
+				g.Expect(recorder.Events).To(Receive(ContainSubstring(EventReconciliationPaused)))
+			})
+		}
+	}
+}
@@ -227,3 +227,23 @@ func IsInitialized(obj *unstructured.Unstructured) (bool, error) {
 	}
 	return initialized && found, nil
 }
+
+// IsCompleted returns true if the Status.Completed field on an external object is true.
+func IsCompleted(obj *unstructured.Unstructured) (bool, error) {
+	completed, found, err := unstructured.NestedBool(obj.Object, "status", "completed")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to determine %v %q completed",
+			obj.GroupVersionKind(), obj.GetName())
+	}
+	return completed && found, nil
+}
+
+// RetryCountFrom returns the Status.RetryCount field from the external object status, or 0 if it is not set.
+func RetryCountFrom(obj *unstructured.Unstructured) (int64, error) {
+	retryCount, _, err := unstructured.NestedInt64(obj.Object, "status", "retryCount")
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to determine retryCount on %v %q",
+			obj.GroupVersionKind(), obj.GetName())
+	}
+	return retryCount, nil
+}
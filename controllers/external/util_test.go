@@ -321,3 +321,59 @@ func TestCloneTemplateMissingSpecTemplate(t *testing.T) {
 	})
 	g.Expect(err).To(HaveOccurred())
 }
+
+func TestRemediationStatusFrom(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         map[string]interface{}
+		wantCompleted  bool
+		wantRetryCount int64
+		wantErr        bool
+	}{
+		{
+			name: "no status",
+		},
+		{
+			name:           "completed after some retries",
+			status:         map[string]interface{}{"completed": true, "retryCount": int64(2)},
+			wantCompleted:  true,
+			wantRetryCount: 2,
+		},
+		{
+			name:           "retrying",
+			status:         map[string]interface{}{"completed": false, "retryCount": int64(1)},
+			wantRetryCount: 1,
+		},
+		{
+			name:    "invalid fields",
+			status:  map[string]interface{}{"completed": "yes", "retryCount": "one"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tt.status != nil {
+				obj.Object["status"] = tt.status
+			}
+
+			completed, err := IsCompleted(obj)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(completed).To(Equal(tt.wantCompleted))
+			}
+
+			retryCount, err := RetryCountFrom(obj)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(retryCount).To(Equal(tt.wantRetryCount))
+			}
+		})
+	}
+}
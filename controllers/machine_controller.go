@@ -19,10 +19,13 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -31,8 +34,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/klog"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/drainutil"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
@@ -69,6 +72,7 @@ var (
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedrainrules,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 // MachineReconciler reconciles a Machine object
@@ -77,6 +81,15 @@ type MachineReconciler struct {
 	Tracker          *remote.ClusterCacheTracker
 	WatchFilterValue string
 
+	// NodeLabelPrefix is the prefix of the Machine labels which are synced to the Machine's Node.
+	// When empty, no labels are synced.
+	NodeLabelPrefix string
+
+	// NodeHealthyConditions are the Node conditions summarized by the NodeHealthy condition of the Machines:
+	// the Ready condition is healthy when true, any other condition when false.
+	// When empty, DefaultNodeHealthyConditions are summarized.
+	NodeHealthyConditions []corev1.NodeConditionType
+
 	controller      controller.Controller
 	restConfig      *rest.Config
 	recorder        record.EventRecorder
@@ -166,6 +179,7 @@ func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, m) {
 		log.Info("Reconciliation is paused for this object")
+		r.recorder.Event(m, corev1.EventTypeNormal, EventReconciliationPaused, "Reconciliation is paused for this object")
 		return ctrl.Result{}, nil
 	}
 
@@ -175,6 +189,9 @@ func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 		return ctrl.Result{}, err
 	}
 
+	// The provisioning duration is observed once the first transition of the Machine to Running has been persisted.
+	wasRunning := m.Status.PhaseTransitionTimes != nil && m.Status.PhaseTransitionTimes.Running != nil
+
 	defer func() {
 		r.reconcilePhase(ctx, m)
 
@@ -186,6 +203,8 @@ func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 		}
 		if err := patchMachine(ctx, patchHelper, m, patchOpts...); err != nil {
 			reterr = kerrors.NewAggregate([]error{reterr, err})
+		} else if !wasRunning {
+			observeProvisionDuration(m)
 		}
 	}()
 
@@ -214,22 +233,34 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clust
 	// Always update the readyCondition by summarizing the state of other conditions.
 	// A step counter is added to represent progress during the provisioning process (instead we are hiding it
 	// after provisioning - e.g. when a MHC condition exists - or during the deletion process).
+	summaryConditions := []clusterv1.ConditionType{
+		// Infrastructure problems should take precedence over all the other conditions
+		clusterv1.InfrastructureReadyCondition,
+		// Boostrap comes after, but it is relevant only during initial machine provisioning.
+		clusterv1.BootstrapReadyCondition,
+		// MHC reported condition should take precedence over the remediation progress
+		clusterv1.MachineHealthCheckSuccededCondition,
+		clusterv1.MachineOwnerRemediatedCondition,
+	}
+	// Readiness gates come last, they are set by external controllers once the machine is otherwise ready.
+	for _, gate := range machine.Spec.ReadinessGates {
+		summaryConditions = append(summaryConditions, gate.ConditionType)
+	}
 	conditions.SetSummary(machine,
-		conditions.WithConditions(
-			// Infrastructure problems should take precedence over all the other conditions
-			clusterv1.InfrastructureReadyCondition,
-			// Boostrap comes after, but it is relevant only during initial machine provisioning.
-			clusterv1.BootstrapReadyCondition,
-			// MHC reported condition should take precedence over the remediation progress
-			clusterv1.MachineHealthCheckSuccededCondition,
-			clusterv1.MachineOwnerRemediatedCondition,
-		),
+		conditions.WithConditions(summaryConditions...),
 		conditions.WithStepCounterIf(machine.ObjectMeta.DeletionTimestamp.IsZero()),
 		conditions.WithStepCounterIfOnly(
 			clusterv1.BootstrapReadyCondition,
 			clusterv1.InfrastructureReadyCondition,
 		),
 	)
+	// The summary ignores the readiness gates not reported yet, which still prevent the machine from being ready.
+	if conditions.IsTrue(machine, clusterv1.ReadyCondition) {
+		if unmet := unmetReadinessGates(machine); len(unmet) > 0 {
+			conditions.MarkFalse(machine, clusterv1.ReadyCondition, clusterv1.ReadinessGatesNotMetReason, clusterv1.ConditionSeverityInfo,
+				"Waiting for %s", strings.Join(unmet, ", "))
+		}
+	}
 
 	// Patch the object, ignoring conflicts on the conditions owned by this controller.
 	// Also, if requested, we are adding additional options like e.g. Patch ObservedGeneration when issuing the
@@ -248,6 +279,18 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clust
 	return patchHelper.Patch(ctx, machine, options...)
 }
 
+// unmetReadinessGates returns the condition types of the readiness gates of the machine which are not True.
+// The machine is only Ready, and counted as a ready replica by its MachineSet, if there are none.
+func unmetReadinessGates(machine *clusterv1.Machine) []string {
+	var unmet []string
+	for _, gate := range machine.Spec.ReadinessGates {
+		if !conditions.IsTrue(machine, gate.ConditionType) {
+			unmet = append(unmet, string(gate.ConditionType))
+		}
+	}
+	return unmet
+}
+
 func (r *MachineReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -308,12 +351,24 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 	if isDeleteNodeAllowed {
 		// pre-drain.delete lifecycle hook
 		// Return early without error, will requeue if/when the hook owner removes the annotation.
-		if annotations.HasWithPrefix(clusterv1.PreDrainDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations) {
-			conditions.MarkFalse(m, clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
+		if hooks := deleteHooksWithPrefix(clusterv1.PreDrainDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations); len(hooks) > 0 {
+			log.Info("Waiting for pre-drain delete hooks to be removed", "hooks", hooks)
+			conditions.MarkFalse(m, clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo,
+				"Waiting for hooks %s", strings.Join(hooks, ", "))
 			return ctrl.Result{}, nil
 		}
 		conditions.MarkTrue(m, clusterv1.PreDrainDeleteHookSucceededCondition)
 
+		// Stop retrying to drain the node once NodeDrainTimeout is exceeded, e.g. because a PodDisruptionBudget
+		// blocks the eviction of a pod, and proceed with the deletion. The failure is recorded only once, and never
+		// overrides a drain that already succeeded.
+		if r.nodeDrainTimeoutExceeded(m) && !conditions.IsTrue(m, clusterv1.DrainingSucceededCondition) &&
+			conditions.GetReason(m, clusterv1.DrainingSucceededCondition) != clusterv1.DrainingFailedReason {
+			log.Info("Node drain timeout exceeded, proceeding with deletion", "node", m.Status.NodeRef.Name, "timeout", m.Spec.NodeDrainTimeout.Duration)
+			conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning,
+				"Node drain did not complete within the NodeDrainTimeout of %s", m.Spec.NodeDrainTimeout.Duration)
+		}
+
 		// Drain node before deletion and issue a patch in order to make this operation visible to the users.
 		if r.isNodeDrainAllowed(m) {
 			patchHelper, err := patch.NewHelper(m, r.Client)
@@ -322,18 +377,20 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 			}
 
 			log.Info("Draining node", "node", m.Status.NodeRef.Name)
-			// The DrainingSucceededCondition never exists before the node is drained for the first time,
-			// so its transition time can be used to record the first time draining.
-			// This `if` condition prevents the transition time to be changed more than once.
 			if conditions.Get(m, clusterv1.DrainingSucceededCondition) == nil {
 				conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, "Draining the node before deletion")
 			}
+			// Record the first time draining, NodeDrainTimeout is measured from it.
+			if m.Status.NodeDrainStartTime == nil {
+				now := metav1.Now()
+				m.Status.NodeDrainStartTime = &now
+			}
 
 			if err := patchMachine(ctx, patchHelper, m); err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to patch Machine")
 			}
 
-			if result, err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name); !result.IsZero() || err != nil {
+			if result, err := r.drainNode(ctx, cluster, m); !result.IsZero() || err != nil {
 				if err != nil {
 					conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDrainNode", "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
@@ -344,12 +401,39 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 			conditions.MarkTrue(m, clusterv1.DrainingSucceededCondition)
 			r.recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulDrainNode", "success draining Machine's node %q", m.Status.NodeRef.Name)
 		}
+
+		// Wait for the volumes attached to the node to be detached before deleting the infrastructure,
+		// otherwise they could fail to be attached to the replacement node.
+		// The VolumeDetachSucceededCondition is set when waiting for the first time, NodeVolumeDetachTimeout is measured from it.
+		// Waiting is skipped independently of draining, when the Machine has the exclude-wait-for-node-volume-detach annotation.
+		if r.isWaitForNodeVolumeDetachAllowed(m) {
+			if conditions.Get(m, clusterv1.VolumeDetachSucceededCondition) == nil {
+				conditions.MarkFalse(m, clusterv1.VolumeDetachSucceededCondition, clusterv1.WaitingForVolumeDetachReason, clusterv1.ConditionSeverityInfo, "Waiting for node volumes to be detached")
+			}
+			if r.nodeVolumeDetachTimeoutExceeded(m) {
+				log.Info("Node volume detach timeout exceeded, proceeding with deletion", "node", m.Status.NodeRef.Name, "timeout", m.Spec.NodeVolumeDetachTimeout.Duration)
+				conditions.MarkFalse(m, clusterv1.VolumeDetachSucceededCondition, clusterv1.VolumeDetachTimeoutReason, clusterv1.ConditionSeverityWarning,
+					"Node volumes were not detached within the NodeVolumeDetachTimeout of %s", m.Spec.NodeVolumeDetachTimeout.Duration)
+			} else if !conditions.IsTrue(m, clusterv1.VolumeDetachSucceededCondition) {
+				attached, err := r.nodeHasAttachedVolumes(ctx, cluster, m.Status.NodeRef.Name)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if attached {
+					log.Info("Waiting for node volumes to be detached", "node", m.Status.NodeRef.Name)
+					return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+				}
+				conditions.MarkTrue(m, clusterv1.VolumeDetachSucceededCondition)
+			}
+		}
 	}
 
 	// pre-term.delete lifecycle hook
 	// Return early without error, will requeue if/when the hook owner removes the annotation.
-	if annotations.HasWithPrefix(clusterv1.PreTerminateDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations) {
-		conditions.MarkFalse(m, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
+	if hooks := deleteHooksWithPrefix(clusterv1.PreTerminateDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations); len(hooks) > 0 {
+		log.Info("Waiting for pre-terminate delete hooks to be removed", "hooks", hooks)
+		conditions.MarkFalse(m, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for hooks %s", strings.Join(hooks, ", "))
 		return ctrl.Result{}, nil
 	}
 	conditions.MarkTrue(m, clusterv1.PreTerminateDeleteHookSucceededCondition)
@@ -398,6 +482,19 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 	return ctrl.Result{}, nil
 }
 
+// deleteHooksWithPrefix returns the sorted keys of the delete hook annotations with the given prefix,
+// which block the corresponding deletion phase until they are removed by the hook owners.
+func deleteHooksWithPrefix(prefix string, machineAnnotations map[string]string) []string {
+	var hooks []string
+	for key := range machineAnnotations {
+		if strings.HasPrefix(key, prefix) {
+			hooks = append(hooks, key)
+		}
+	}
+	sort.Strings(hooks)
+	return hooks
+}
+
 func (r *MachineReconciler) isNodeDrainAllowed(m *clusterv1.Machine) bool {
 	if _, exists := m.ObjectMeta.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; exists {
 		return false
@@ -411,23 +508,71 @@ func (r *MachineReconciler) isNodeDrainAllowed(m *clusterv1.Machine) bool {
 
 }
 
+func (r *MachineReconciler) isWaitForNodeVolumeDetachAllowed(m *clusterv1.Machine) bool {
+	_, exists := m.ObjectMeta.Annotations[clusterv1.ExcludeWaitForNodeVolumeDetachAnnotation]
+	return !exists
+}
+
 func (r *MachineReconciler) nodeDrainTimeoutExceeded(machine *clusterv1.Machine) bool {
 	// if the NodeDrainTineout type is not set by user
 	if machine.Spec.NodeDrainTimeout == nil || machine.Spec.NodeDrainTimeout.Seconds() <= 0 {
 		return false
 	}
 
-	// if the draining succeeded condition does not exist
-	if conditions.Get(machine, clusterv1.DrainingSucceededCondition) == nil {
-		return false
+	firstTimeDrain := machine.Status.NodeDrainStartTime
+	if firstTimeDrain == nil {
+		// if the draining succeeded condition does not exist
+		if conditions.Get(machine, clusterv1.DrainingSucceededCondition) == nil {
+			return false
+		}
+		// Machines which started draining before NodeDrainStartTime was recorded only have
+		// the transition time of the DrainingSucceededCondition.
+		firstTimeDrain = conditions.GetLastTransitionTime(machine, clusterv1.DrainingSucceededCondition)
 	}
 
-	now := time.Now()
-	firstTimeDrain := conditions.GetLastTransitionTime(machine, clusterv1.DrainingSucceededCondition)
-	diff := now.Sub(firstTimeDrain.Time)
+	diff := time.Since(firstTimeDrain.Time)
 	return diff.Seconds() >= machine.Spec.NodeDrainTimeout.Seconds()
 }
 
+func (r *MachineReconciler) nodeVolumeDetachTimeoutExceeded(machine *clusterv1.Machine) bool {
+	// if the NodeVolumeDetachTimeout is not set by user
+	if machine.Spec.NodeVolumeDetachTimeout == nil || machine.Spec.NodeVolumeDetachTimeout.Seconds() <= 0 {
+		return false
+	}
+
+	// if the volume detach succeeded condition does not exist
+	if conditions.Get(machine, clusterv1.VolumeDetachSucceededCondition) == nil {
+		return false
+	}
+
+	firstTimeWait := conditions.GetLastTransitionTime(machine, clusterv1.VolumeDetachSucceededCondition)
+	diff := time.Since(firstTimeWait.Time)
+	return diff.Seconds() >= machine.Spec.NodeVolumeDetachTimeout.Seconds()
+}
+
+// nodeHasAttachedVolumes returns true if there are VolumeAttachments referencing the node in the workload cluster.
+func (r *MachineReconciler) nodeHasAttachedVolumes(ctx context.Context, cluster *clusterv1.Cluster, nodeName string) (bool, error) {
+	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name, "node", nodeName)
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		log.Error(err, "Error creating a remote client for cluster while deleting Machine, won't wait for volumes to be detached")
+		return false, nil
+	}
+
+	volumeAttachments := &storagev1.VolumeAttachmentList{}
+	if err := remoteClient.List(ctx, volumeAttachments); err != nil {
+		return false, errors.Wrapf(err, "failed to list VolumeAttachments for node %q", nodeName)
+	}
+
+	for _, va := range volumeAttachments.Items {
+		if va.Spec.NodeName == nodeName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // isDeleteNodeAllowed returns nil only if the Machine's NodeRef is not nil
 // and if the Machine is not the last control plane node in the cluster.
 func (r *MachineReconciler) isDeleteNodeAllowed(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) error {
@@ -491,14 +636,23 @@ func (r *MachineReconciler) isDeleteNodeAllowed(ctx context.Context, cluster *cl
 	}
 }
 
-func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, nodeName string) (ctrl.Result, error) {
+func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (ctrl.Result, error) {
+	nodeName := machine.Status.NodeRef.Name
 	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name, "node", nodeName)
 
+	rules, err := r.getMachineDrainRules(ctx, machine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	restConfig, err := remote.RESTConfig(ctx, MachineControllerName, r.Client, util.ObjectKey(cluster))
 	if err != nil {
 		log.Error(err, "Error creating a remote client while deleting Machine, won't retry")
 		return ctrl.Result{}, nil
 	}
+	if r.Tracker != nil {
+		r.Tracker.ApplyClientOptions(restConfig)
+	}
 	kubeClient, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		log.Error(err, "Error creating a remote client while deleting Machine, won't retry")
@@ -515,33 +669,24 @@ func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cl
 		return ctrl.Result{}, errors.Errorf("unable to get node %q: %v", nodeName, err)
 	}
 
-	drainer := &kubedrain.Helper{
-		Client:              kubeClient,
-		Force:               true,
-		IgnoreAllDaemonSets: true,
-		DeleteLocalData:     true,
-		GracePeriodSeconds:  -1,
-		// If a pod is not evicted in 20 seconds, retry the eviction next time the
-		// machine gets reconciled again (to allow other machines to be reconciled).
-		Timeout: 20 * time.Second,
-		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
-			verbStr := "Deleted"
-			if usingEviction {
-				verbStr = "Evicted"
-			}
-			log.Info(fmt.Sprintf("%s pod from Node", verbStr),
-				"pod", fmt.Sprintf("%s/%s", pod.Name, pod.Namespace))
-		},
-		Out:    writer{klog.Info},
-		ErrOut: writer{klog.Error},
-		DryRun: false,
-	}
+	drainer := drainutil.NewHelper(log, kubeClient)
 
 	if noderefutil.IsNodeUnreachable(node) {
 		// When the node is unreachable and some pods are not evicted for as long as this timeout, we ignore them.
 		drainer.SkipWaitForDeleteTimeoutSeconds = 60 * 5 // 5 minutes
 	}
 
+	// The MachineDrainRules matching a pod override the default drain behavior.
+	if len(rules) > 0 {
+		namespaceLabels, err := getNamespaceLabels(ctx, kubeClient)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		drainer.DrainBehavior = func(pod corev1.Pod) kubedrain.PodDrainBehavior {
+			return drainBehaviorForPod(rules, pod, namespaceLabels[pod.Namespace])
+		}
+	}
+
 	if err := kubedrain.RunCordonOrUncordon(ctx, drainer, node, true); err != nil {
 		// Machine will be re-reconciled after a cordon failure.
 		log.Error(err, "Cordon failed")
@@ -549,6 +694,14 @@ func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cl
 	}
 
 	if err := kubedrain.RunNodeDrain(ctx, drainer, node.Name); err != nil {
+		// The drain is still in progress while it waits for pods to complete.
+		var waitingErr *kubedrain.WaitingForPodsError
+		if errors.As(err, &waitingErr) {
+			log.Info("Draining in progress, retry in 20s", "pods", waitingErr.Pods)
+			conditions.MarkFalse(machine, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo,
+				"Draining in progress: %s", waitingErr.Error())
+			return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+		}
 		// Machine will be re-reconciled after a drain failure.
 		log.Error(err, "Drain failed, retry in 20s")
 		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
@@ -702,14 +855,3 @@ func (r *MachineReconciler) indexMachineByNodeName(o client.Object) []string {
 
 	return nil
 }
-
-// writer implements io.Writer interface as a pass-through for klog.
-type writer struct {
-	logFunc func(args ...interface{})
-}
-
-// Write passes string(p) into writer's logFunc and always returns len(p)
-func (w writer) Write(p []byte) (n int, err error) {
-	w.logFunc(string(p))
-	return len(p), nil
-}
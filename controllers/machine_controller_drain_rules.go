@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	kubedrain "sigs.k8s.io/cluster-api/third_party/kubernetes-drain"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// machineDrainRule is a MachineDrainRule with parsed selectors.
+type machineDrainRule struct {
+	name     string
+	priority int32
+	behavior kubedrain.PodDrainBehavior
+	pods     []machineDrainRulePodSelector
+}
+
+type machineDrainRulePodSelector struct {
+	selector          labels.Selector
+	namespaceSelector labels.Selector
+}
+
+// getMachineDrainRules returns the MachineDrainRules selecting the machine, in the order they must be evaluated.
+func (r *MachineReconciler) getMachineDrainRules(ctx context.Context, m *clusterv1.Machine) ([]machineDrainRule, error) {
+	ruleList := &clusterv1.MachineDrainRuleList{}
+	if err := r.Client.List(ctx, ruleList, client.InNamespace(m.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDrainRules")
+	}
+
+	rules := make([]machineDrainRule, 0, len(ruleList.Items))
+	for _, item := range ruleList.Items {
+		machineSelector, err := selectorOrEverything(item.Spec.MachineSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the machine selector of MachineDrainRule %q", item.Name)
+		}
+		if !machineSelector.Matches(labels.Set(m.Labels)) {
+			continue
+		}
+
+		rule := machineDrainRule{
+			name:     item.Name,
+			priority: item.Spec.Priority,
+			behavior: kubedrain.PodDrainBehavior(item.Spec.Behavior),
+		}
+		for _, pods := range item.Spec.Pods {
+			selector, err := selectorOrEverything(pods.Selector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse a pod selector of MachineDrainRule %q", item.Name)
+			}
+			namespaceSelector, err := selectorOrEverything(pods.NamespaceSelector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse a namespace selector of MachineDrainRule %q", item.Name)
+			}
+			rule.pods = append(rule.pods, machineDrainRulePodSelector{selector: selector, namespaceSelector: namespaceSelector})
+		}
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].priority != rules[j].priority {
+			return rules[i].priority > rules[j].priority
+		}
+		return rules[i].name < rules[j].name
+	})
+	return rules, nil
+}
+
+// matches returns true if the rule matches the pod running in a namespace with the given labels.
+func (r machineDrainRule) matches(pod corev1.Pod, namespaceLabels labels.Set) bool {
+	if len(r.pods) == 0 {
+		return true
+	}
+	for _, pods := range r.pods {
+		if pods.selector.Matches(labels.Set(pod.Labels)) && pods.namespaceSelector.Matches(namespaceLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// drainBehaviorForPod returns the drain behavior defined by the first rule matching the pod,
+// or the default behavior if no rule matches.
+func drainBehaviorForPod(rules []machineDrainRule, pod corev1.Pod, namespaceLabels labels.Set) kubedrain.PodDrainBehavior {
+	for _, rule := range rules {
+		if rule.matches(pod, namespaceLabels) {
+			return rule.behavior
+		}
+	}
+	return kubedrain.PodDrainBehaviorDefault
+}
+
+// getNamespaceLabels returns the labels of the namespaces of the workload cluster, by namespace name.
+func getNamespaceLabels(ctx context.Context, kubeClient kubernetes.Interface) (map[string]labels.Set, error) {
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces")
+	}
+	namespaceLabels := make(map[string]labels.Set, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		namespaceLabels[ns.Name] = labels.Set(ns.Labels)
+	}
+	return namespaceLabels, nil
+}
+
+// selectorOrEverything converts the label selector, matching everything if nil.
+func selectorOrEverything(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
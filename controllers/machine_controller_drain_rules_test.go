@@ -0,0 +1,263 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/drainutil"
+	"sigs.k8s.io/cluster-api/test/helpers"
+	kubedrain "sigs.k8s.io/cluster-api/third_party/kubernetes-drain"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newMachineDrainRule(name string, behavior clusterv1.MachineDrainRuleDrainBehavior, priority int32, pods ...clusterv1.MachineDrainRulePodSelector) *clusterv1.MachineDrainRule {
+	return &clusterv1.MachineDrainRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: clusterv1.MachineDrainRuleSpec{
+			Behavior: behavior,
+			Priority: priority,
+			Pods:     pods,
+		},
+	}
+}
+
+func TestGetMachineDrainRules(t *testing.T) {
+	g := NewWithT(t)
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "machine",
+			Labels:    map[string]string{"pool": "gpu"},
+		},
+	}
+	gpuRule := newMachineDrainRule("gpu", clusterv1.MachineDrainRuleDrainBehaviorSkip, 0)
+	gpuRule.Spec.MachineSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}}
+	otherPoolRule := newMachineDrainRule("other-pool", clusterv1.MachineDrainRuleDrainBehaviorSkip, 100)
+	otherPoolRule.Spec.MachineSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "other"}}
+	otherNamespaceRule := newMachineDrainRule("other-namespace", clusterv1.MachineDrainRuleDrainBehaviorSkip, 100)
+	otherNamespaceRule.Namespace = "other"
+
+	r := &MachineReconciler{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme,
+			newMachineDrainRule("b-low", clusterv1.MachineDrainRuleDrainBehaviorDrain, 0),
+			newMachineDrainRule("a-low", clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted, 0),
+			newMachineDrainRule("high", clusterv1.MachineDrainRuleDrainBehaviorSkip, 10),
+			gpuRule,
+			otherPoolRule,
+			otherNamespaceRule,
+		),
+	}
+
+	rules, err := r.getMachineDrainRules(ctx, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Rules are sorted by descending priority and then by name, and only the rules selecting the machine are returned.
+	names := []string{}
+	for _, rule := range rules {
+		names = append(names, rule.name)
+	}
+	g.Expect(names).To(Equal([]string{"high", "a-low", "b-low", "gpu"}))
+}
+
+func TestDrainBehaviorForPod(t *testing.T) {
+	systemPods := clusterv1.MachineDrainRulePodSelector{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "system"}},
+	}
+	batchPods := clusterv1.MachineDrainRulePodSelector{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "batch"}},
+	}
+
+	testCases := []struct {
+		name             string
+		rules            []*clusterv1.MachineDrainRule
+		pod              corev1.Pod
+		namespaceLabels  labels.Set
+		expectedBehavior kubedrain.PodDrainBehavior
+	}{
+		{
+			name:             "should use the default behavior when there are no rules",
+			pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}},
+			expectedBehavior: kubedrain.PodDrainBehaviorDefault,
+		},
+		{
+			name:             "should use the default behavior when no rule matches",
+			rules:            []*clusterv1.MachineDrainRule{newMachineDrainRule("skip-system", clusterv1.MachineDrainRuleDrainBehaviorSkip, 0, systemPods)},
+			pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}},
+			namespaceLabels:  labels.Set{"tier": "apps"},
+			expectedBehavior: kubedrain.PodDrainBehaviorDefault,
+		},
+		{
+			name:             "should skip pods in the matching namespaces",
+			rules:            []*clusterv1.MachineDrainRule{newMachineDrainRule("skip-system", clusterv1.MachineDrainRuleDrainBehaviorSkip, 0, systemPods)},
+			pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "pod"}},
+			namespaceLabels:  labels.Set{"tier": "system"},
+			expectedBehavior: kubedrain.PodDrainBehaviorSkip,
+		},
+		{
+			name:             "should match a rule without pod selectors",
+			rules:            []*clusterv1.MachineDrainRule{newMachineDrainRule("drain-all", clusterv1.MachineDrainRuleDrainBehaviorDrain, 0)},
+			pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}},
+			expectedBehavior: kubedrain.PodDrainBehaviorDrain,
+		},
+		{
+			name:             "should match any of the pod selectors of a rule",
+			rules:            []*clusterv1.MachineDrainRule{newMachineDrainRule("wait", clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted, 0, systemPods, batchPods)},
+			pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", Labels: map[string]string{"app": "batch"}}},
+			expectedBehavior: kubedrain.PodDrainBehaviorWaitCompleted,
+		},
+		{
+			name: "should use the first matching rule",
+			rules: []*clusterv1.MachineDrainRule{
+				newMachineDrainRule("force-drain-system", clusterv1.MachineDrainRuleDrainBehaviorDrain, 10, systemPods),
+				newMachineDrainRule("skip-all", clusterv1.MachineDrainRuleDrainBehaviorSkip, 0),
+			},
+			pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "pod"}},
+			namespaceLabels:  labels.Set{"tier": "system"},
+			expectedBehavior: kubedrain.PodDrainBehaviorDrain,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := []client.Object{}
+			for _, rule := range tc.rules {
+				objs = append(objs, rule)
+			}
+			r := &MachineReconciler{
+				Client: helpers.NewFakeClientWithScheme(scheme.Scheme, objs...),
+			}
+			rules, err := r.getMachineDrainRules(ctx, &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "machine"}})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(drainBehaviorForPod(rules, tc.pod, tc.namespaceLabels)).To(Equal(tc.expectedBehavior))
+		})
+	}
+}
+
+func TestRunNodeDrainWithMachineDrainRules(t *testing.T) {
+	g := NewWithT(t)
+
+	nodeName := "node"
+	daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "ds"}}
+	newPod := func(namespace, name string, podLabels map[string]string, owner *metav1.OwnerReference) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: podLabels},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if owner != nil {
+			pod.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return pod
+	}
+	controller := true
+	replicaSetOwner := &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs", Controller: &controller}
+	daemonSetOwner := &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "DaemonSet", Name: daemonSet.Name, Controller: &controller}
+
+	appPod := newPod("default", "app", nil, replicaSetOwner)
+	skippedPod := newPod("default", "skipped", map[string]string{"drain": "skip"}, replicaSetOwner)
+	batchPod := newPod("default", "batch", map[string]string{"app": "batch"}, replicaSetOwner)
+	daemonSetPod := newPod("kube-system", "ds-pod", nil, daemonSetOwner)
+	forcedDaemonSetPod := newPod("monitoring", "ds-pod", nil, daemonSetOwner)
+	forcedDaemonSetPod.OwnerReferences[0].Name = "monitoring-ds"
+
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring", Labels: map[string]string{"drain": "force"}}},
+		daemonSet,
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "monitoring-ds"}},
+		appPod, skippedPod, batchPod, daemonSetPod, forcedDaemonSetPod,
+	)
+
+	r := &MachineReconciler{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme,
+			newMachineDrainRule("skip", clusterv1.MachineDrainRuleDrainBehaviorSkip, 0, clusterv1.MachineDrainRulePodSelector{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"drain": "skip"}},
+			}),
+			newMachineDrainRule("force-drain", clusterv1.MachineDrainRuleDrainBehaviorDrain, 0, clusterv1.MachineDrainRulePodSelector{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"drain": "force"}},
+			}),
+			newMachineDrainRule("wait", clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted, 0, clusterv1.MachineDrainRulePodSelector{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "batch"}},
+			}),
+		),
+	}
+	rules, err := r.getMachineDrainRules(ctx, &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "machine"}})
+	g.Expect(err).ToNot(HaveOccurred())
+	namespaceLabels, err := getNamespaceLabels(ctx, kubeClient)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	drainer := &kubedrain.Helper{
+		Client:              kubeClient,
+		Force:               true,
+		IgnoreAllDaemonSets: true,
+		DeleteLocalData:     true,
+		GracePeriodSeconds:  -1,
+		Out:                 drainutil.Writer{LogFunc: func(...interface{}) {}},
+		ErrOut:              drainutil.Writer{LogFunc: func(...interface{}) {}},
+		DrainBehavior: func(pod corev1.Pod) kubedrain.PodDrainBehavior {
+			return drainBehaviorForPod(rules, pod, namespaceLabels[pod.Namespace])
+		},
+	}
+
+	podExists := func(pod *corev1.Pod) bool {
+		_, err := kubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	// The drain waits for the batch pod to complete.
+	err = kubedrain.RunNodeDrain(ctx, drainer, nodeName)
+	var waitingErr *kubedrain.WaitingForPodsError
+	g.Expect(errors.As(err, &waitingErr)).To(BeTrue())
+	g.Expect(waitingErr.Pods).To(ConsistOf("default/batch"))
+
+	g.Expect(podExists(appPod)).To(BeFalse())
+	g.Expect(podExists(forcedDaemonSetPod)).To(BeFalse())
+	g.Expect(podExists(skippedPod)).To(BeTrue())
+	g.Expect(podExists(daemonSetPod)).To(BeTrue())
+	g.Expect(podExists(batchPod)).To(BeTrue())
+
+	// The drain completes once the batch pod has completed, without deleting it.
+	batchPod.Status.Phase = corev1.PodSucceeded
+	_, err = kubeClient.CoreV1().Pods(batchPod.Namespace).UpdateStatus(ctx, batchPod, metav1.UpdateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(kubedrain.RunNodeDrain(ctx, drainer, nodeName)).To(Succeed())
+	g.Expect(podExists(batchPod)).To(BeTrue())
+	g.Expect(podExists(skippedPod)).To(BeTrue())
+	g.Expect(podExists(daemonSetPod)).To(BeTrue())
+}
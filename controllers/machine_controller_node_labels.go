@@ -18,6 +18,8 @@ package controllers
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	apicorev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -92,3 +94,59 @@ func (r *MachineReconciler) setInterruptibleNodeLabel(ctx context.Context, remot
 
 	return patchHelper.Patch(ctx, node)
 }
+
+// syncNodeLabels syncs the Machine labels carrying the configured prefix to the Node, and removes from the Node
+// the labels previously synced which are no longer set on the Machine. The keys of the synced labels are tracked
+// in the LabelsFromMachineAnnotation, so labels set on the Node by others are never removed.
+// It returns true if the Node has been changed.
+func (r *MachineReconciler) syncNodeLabels(machine *clusterv1.Machine, node *apicorev1.Node) bool {
+	if r.NodeLabelPrefix == "" {
+		return false
+	}
+
+	desired := map[string]string{}
+	for k, v := range machine.Labels {
+		if strings.HasPrefix(k, r.NodeLabelPrefix) {
+			desired[k] = v
+		}
+	}
+
+	changed := false
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	for _, k := range strings.Split(node.Annotations[clusterv1.LabelsFromMachineAnnotation], ",") {
+		if _, ok := desired[k]; ok || k == "" {
+			continue
+		}
+		if _, ok := node.Labels[k]; ok {
+			delete(node.Labels, k)
+			changed = true
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for k, v := range desired {
+		keys = append(keys, k)
+		if current, ok := node.Labels[k]; !ok || current != v {
+			node.Labels[k] = v
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+
+	tracked := strings.Join(keys, ",")
+	if node.Annotations[clusterv1.LabelsFromMachineAnnotation] != tracked {
+		if tracked == "" {
+			delete(node.Annotations, clusterv1.LabelsFromMachineAnnotation)
+		} else {
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+			node.Annotations[clusterv1.LabelsFromMachineAnnotation] = tracked
+		}
+		changed = true
+	}
+
+	return changed
+}
@@ -28,9 +28,11 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/test/helpers"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -138,3 +140,187 @@ func TestReconcileInterruptibleNodeLabel(t *testing.T) {
 		return ok
 	}, 10*time.Second).Should(BeTrue())
 }
+
+func TestSyncNodeLabels(t *testing.T) {
+	tests := []struct {
+		name                string
+		prefix              string
+		machineLabels       map[string]string
+		nodeLabels          map[string]string
+		nodeAnnotations     map[string]string
+		expectChanged       bool
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:   "should sync the labels with the prefix",
+			prefix: clusterv1.NodeLabelPrefix,
+			machineLabels: map[string]string{
+				"node.cluster.x-k8s.io/pool": "gpu",
+				"node.cluster.x-k8s.io/zone": "a",
+				"other":                      "value",
+			},
+			nodeLabels:    map[string]string{"kubernetes.io/hostname": "node-1"},
+			expectChanged: true,
+			expectedLabels: map[string]string{
+				"kubernetes.io/hostname":     "node-1",
+				"node.cluster.x-k8s.io/pool": "gpu",
+				"node.cluster.x-k8s.io/zone": "a",
+			},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/pool,node.cluster.x-k8s.io/zone",
+			},
+		},
+		{
+			name:          "should update a synced label when the Machine label changes",
+			prefix:        clusterv1.NodeLabelPrefix,
+			machineLabels: map[string]string{"node.cluster.x-k8s.io/pool": "cpu"},
+			nodeLabels:    map[string]string{"node.cluster.x-k8s.io/pool": "gpu"},
+			nodeAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/pool",
+			},
+			expectChanged:  true,
+			expectedLabels: map[string]string{"node.cluster.x-k8s.io/pool": "cpu"},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/pool",
+			},
+		},
+		{
+			name:          "should remove a synced label when it is removed from the Machine",
+			prefix:        clusterv1.NodeLabelPrefix,
+			machineLabels: map[string]string{"node.cluster.x-k8s.io/zone": "a"},
+			nodeLabels: map[string]string{
+				"node.cluster.x-k8s.io/pool": "gpu",
+				"node.cluster.x-k8s.io/zone": "a",
+			},
+			nodeAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/pool,node.cluster.x-k8s.io/zone",
+			},
+			expectChanged:  true,
+			expectedLabels: map[string]string{"node.cluster.x-k8s.io/zone": "a"},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/zone",
+			},
+		},
+		{
+			name:   "should remove the annotation when no labels are synced anymore",
+			prefix: clusterv1.NodeLabelPrefix,
+			nodeLabels: map[string]string{
+				"node.cluster.x-k8s.io/pool": "gpu",
+			},
+			nodeAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/pool",
+				"other":                               "value",
+			},
+			expectChanged:       true,
+			expectedLabels:      map[string]string{},
+			expectedAnnotations: map[string]string{"other": "value"},
+		},
+		{
+			name:          "should not remove labels with the prefix which were not synced from the Machine",
+			prefix:        clusterv1.NodeLabelPrefix,
+			machineLabels: map[string]string{"node.cluster.x-k8s.io/pool": "gpu"},
+			nodeLabels: map[string]string{
+				"node.cluster.x-k8s.io/pool":   "gpu",
+				"node.cluster.x-k8s.io/custom": "set-by-someone-else",
+			},
+			nodeAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/pool",
+			},
+			expectChanged: false,
+			expectedLabels: map[string]string{
+				"node.cluster.x-k8s.io/pool":   "gpu",
+				"node.cluster.x-k8s.io/custom": "set-by-someone-else",
+			},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/pool",
+			},
+		},
+		{
+			name:           "should not sync labels when the prefix is empty",
+			prefix:         "",
+			machineLabels:  map[string]string{"node.cluster.x-k8s.io/pool": "gpu"},
+			nodeLabels:     map[string]string{"kubernetes.io/hostname": "node-1"},
+			expectChanged:  false,
+			expectedLabels: map[string]string{"kubernetes.io/hostname": "node-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-1", Labels: tt.machineLabels},
+			}
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: tt.nodeLabels, Annotations: tt.nodeAnnotations},
+			}
+
+			r := &MachineReconciler{NodeLabelPrefix: tt.prefix}
+			g.Expect(r.syncNodeLabels(machine, node)).To(Equal(tt.expectChanged))
+			g.Expect(node.Labels).To(Equal(tt.expectedLabels))
+			if tt.expectedAnnotations == nil {
+				g.Expect(node.Annotations).To(BeEmpty())
+			} else {
+				g.Expect(node.Annotations).To(Equal(tt.expectedAnnotations))
+			}
+		})
+	}
+}
+
+func TestReconcileNodeSyncsMachineLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				"node.cluster.x-k8s.io/removed": "true",
+				"unrelated":                     "value",
+			},
+			Annotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "node.cluster.x-k8s.io/removed",
+			},
+		},
+		Spec: corev1.NodeSpec{ProviderID: "aws:///us-east-1/i-1"},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-1",
+			Namespace: "default",
+			Labels: map[string]string{
+				"node.cluster.x-k8s.io/pool": "gpu",
+				"not-synced":                 "value",
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: cluster.Name,
+			ProviderID:  pointer.StringPtr("aws:///us-east-1/i-1"),
+		},
+	}
+
+	// The fake client is used both as the management and as the workload cluster client.
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, machine, node)
+	r := &MachineReconciler{
+		Client:          c,
+		Tracker:         remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(cluster)),
+		NodeLabelPrefix: clusterv1.NodeLabelPrefix,
+		recorder:        record.NewFakeRecorder(32),
+	}
+
+	_, err := r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	updatedNode := &corev1.Node{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), updatedNode)).To(Succeed())
+	g.Expect(updatedNode.Labels).To(Equal(map[string]string{
+		"node.cluster.x-k8s.io/pool": "gpu",
+		"unrelated":                  "value",
+	}))
+	g.Expect(updatedNode.Annotations).To(HaveKeyWithValue(clusterv1.LabelsFromMachineAnnotation, "node.cluster.x-k8s.io/pool"))
+	g.Expect(updatedNode.Annotations).To(HaveKeyWithValue(clusterv1.MachineAnnotation, machine.Name))
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// cloudProviderUninitializedTaintKey is the key of the taint the kubelet sets on the Node when it is started with an
+// external cloud provider, which the cloud provider removes once it has initialized the Node.
+// It matches TaintExternalCloudProvider of k8s.io/cloud-provider/api.
+const cloudProviderUninitializedTaintKey = "node.cloudprovider.kubernetes.io/uninitialized"
+
+// reconcileStartupTaint applies the startup taint defined by the StartupTaintAnnotation to the Node the first time
+// the Node is reconciled, and removes it once the Machine is running, i.e. its infrastructure is ready, and the Node is ready.
+// It returns true if the Node has been changed.
+func (r *MachineReconciler) reconcileStartupTaint(ctx context.Context, machine *clusterv1.Machine, node *corev1.Node, firstReconcile bool) bool {
+	value, ok := machine.Annotations[clusterv1.StartupTaintAnnotation]
+	if !ok {
+		return false
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+
+	taint, err := parseStartupTaint(value)
+	if err != nil {
+		log.Error(err, "Failed to parse the startup taint", "annotation", clusterv1.StartupTaintAnnotation)
+		r.recorder.Eventf(machine, corev1.EventTypeWarning, "FailedParseStartupTaint", "Invalid %s annotation: %v", clusterv1.StartupTaintAnnotation, err)
+		return false
+	}
+
+	if machine.Status.InfrastructureReady && noderefutil.IsNodeReady(node) {
+		if removeTaint(node, taint) {
+			log.Info("Removed startup taint from Machine's Node", "taint", taint.ToString(), "node", node.Name)
+			return true
+		}
+		return false
+	}
+
+	if firstReconcile && addTaint(node, taint) {
+		log.Info("Added startup taint to Machine's Node", "taint", taint.ToString(), "node", node.Name)
+		return true
+	}
+	return false
+}
+
+// parseStartupTaint parses a taint in the key[=value]:effect format.
+func parseStartupTaint(value string) (*corev1.Taint, error) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return nil, errors.Errorf("taint %q must be in the key[=value]:effect format", value)
+	}
+
+	taint := &corev1.Taint{Effect: corev1.TaintEffect(value[i+1:])}
+	switch taint.Effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return nil, errors.Errorf("taint %q has an invalid effect %q", value, taint.Effect)
+	}
+
+	taint.Key = value[:i]
+	if j := strings.Index(taint.Key, "="); j >= 0 {
+		taint.Value = taint.Key[j+1:]
+		taint.Key = taint.Key[:j]
+	}
+	if taint.Key == "" {
+		return nil, errors.Errorf("taint %q must have a key", value)
+	}
+	return taint, nil
+}
+
+// addTaint adds the taint to the Node, unless a taint with the same key and effect already exists.
+func addTaint(node *corev1.Node, taint *corev1.Taint) bool {
+	for i := range node.Spec.Taints {
+		if node.Spec.Taints[i].MatchTaint(taint) {
+			return false
+		}
+	}
+	node.Spec.Taints = append(node.Spec.Taints, *taint)
+	return true
+}
+
+// removeTaint removes the taints with the same key and effect from the Node.
+func removeTaint(node *corev1.Node, taint *corev1.Taint) bool {
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints))
+	for i := range node.Spec.Taints {
+		if !node.Spec.Taints[i].MatchTaint(taint) {
+			taints = append(taints, node.Spec.Taints[i])
+		}
+	}
+	if len(taints) == len(node.Spec.Taints) {
+		return false
+	}
+	node.Spec.Taints = taints
+	return true
+}
+
+// startupTaint returns the startup taint defined by the StartupTaintAnnotation of the Machine, if any and valid.
+func startupTaint(machine *clusterv1.Machine) *corev1.Taint {
+	value, ok := machine.Annotations[clusterv1.StartupTaintAnnotation]
+	if !ok {
+		return nil
+	}
+	// An invalid annotation is reported when reconciling the startup taint.
+	taint, err := parseStartupTaint(value)
+	if err != nil {
+		return nil
+	}
+	return taint
+}
+
+// isNodeUninitialized returns true if the Node has not been initialized by the cloud provider yet, and has no other
+// taint than the startup taint of its Machine, which is only removed once the Node is ready. A Node with other taints,
+// e.g. the ones of the node lifecycle controller for a NotReady Node, reports more than the pending initialization,
+// so it goes through the regular node health checks.
+func isNodeUninitialized(node *corev1.Node, startupTaint *corev1.Taint) bool {
+	uninitialized := false
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		switch {
+		case taint.Key == cloudProviderUninitializedTaintKey:
+			uninitialized = true
+		case startupTaint != nil && taint.MatchTaint(startupTaint):
+		default:
+			return false
+		}
+	}
+	return uninitialized
+}
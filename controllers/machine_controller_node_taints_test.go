@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/test/helpers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestParseStartupTaint(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  *corev1.Taint
+		expectErr bool
+	}{
+		{
+			name:     "key and effect",
+			value:    "node.cluster.x-k8s.io/uninitialized:NoSchedule",
+			expected: &corev1.Taint{Key: "node.cluster.x-k8s.io/uninitialized", Effect: corev1.TaintEffectNoSchedule},
+		},
+		{
+			name:     "key, value and effect",
+			value:    "startup=pending:NoExecute",
+			expected: &corev1.Taint{Key: "startup", Value: "pending", Effect: corev1.TaintEffectNoExecute},
+		},
+		{
+			name:      "missing effect",
+			value:     "startup",
+			expectErr: true,
+		},
+		{
+			name:      "invalid effect",
+			value:     "startup:NoRun",
+			expectErr: true,
+		},
+		{
+			name:      "missing key",
+			value:     "=pending:NoSchedule",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			taint, err := parseStartupTaint(tt.value)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(taint).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestReconcileNodeStartupTaint(t *testing.T) {
+	g := NewWithT(t)
+
+	startupTaint := corev1.Taint{Key: "node.cluster.x-k8s.io/uninitialized", Effect: corev1.TaintEffectNoSchedule}
+	otherTaint := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec: corev1.NodeSpec{
+			ProviderID: "aws:///us-east-1/i-1",
+			Taints:     []corev1.Taint{otherTaint},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "machine-1",
+			Namespace:   "default",
+			Annotations: map[string]string{clusterv1.StartupTaintAnnotation: "node.cluster.x-k8s.io/uninitialized:NoSchedule"},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: cluster.Name,
+			ProviderID:  pointer.StringPtr("aws:///us-east-1/i-1"),
+		},
+	}
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, machine, node)
+	r := &MachineReconciler{
+		Client:   c,
+		Tracker:  remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(cluster)),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	getNodeTaints := func() []corev1.Taint {
+		n := &corev1.Node{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), n)).To(Succeed())
+		return n.Spec.Taints
+	}
+	setNodeReady := func(status corev1.ConditionStatus) {
+		n := &corev1.Node{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), n)).To(Succeed())
+		n.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}
+		g.Expect(c.Update(ctx, n)).To(Succeed())
+	}
+
+	// The startup taint is added when the Node first joins.
+	_, err := r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getNodeTaints()).To(ConsistOf(otherTaint, startupTaint))
+
+	// Reconciling again does not add the taint twice.
+	_, err = r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getNodeTaints()).To(ConsistOf(otherTaint, startupTaint))
+
+	// The taint is kept while the infrastructure is not ready, even if the Node is ready.
+	setNodeReady(corev1.ConditionTrue)
+	_, err = r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getNodeTaints()).To(ConsistOf(otherTaint, startupTaint))
+
+	// The taint is kept while the Node is not ready, even if the infrastructure is ready.
+	setNodeReady(corev1.ConditionFalse)
+	machine.Status.InfrastructureReady = true
+	_, err = r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getNodeTaints()).To(ConsistOf(otherTaint, startupTaint))
+
+	// The taint is removed once the Machine is running and the Node is ready.
+	setNodeReady(corev1.ConditionTrue)
+	_, err = r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getNodeTaints()).To(ConsistOf(otherTaint))
+
+	// The taint is not added back if the Node is not ready anymore.
+	setNodeReady(corev1.ConditionFalse)
+	_, err = r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getNodeTaints()).To(ConsistOf(otherTaint))
+}
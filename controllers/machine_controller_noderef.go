@@ -88,7 +88,7 @@ func (r *MachineReconciler) reconcileNode(ctx context.Context, cluster *clusterv
 		r.recorder.Event(machine, corev1.EventTypeNormal, "SuccessfulSetNodeRef", machine.Status.NodeRef.Name)
 	}
 
-	// Reconcile node annotations.
+	// Reconcile node annotations, labels and taints.
 	patchHelper, err := patch.NewHelper(node, remoteClient)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -102,15 +102,32 @@ func (r *MachineReconciler) reconcileNode(ctx context.Context, cluster *clusterv
 		desired[clusterv1.OwnerKindAnnotation] = owner.Kind
 		desired[clusterv1.OwnerNameAnnotation] = owner.Name
 	}
-	if annotations.AddAnnotations(node, desired) {
+	// The Machine annotation is set the first time the Node is reconciled, together with the startup taint.
+	firstReconcile := node.Annotations[clusterv1.MachineAnnotation] == ""
+	annotationsChanged := annotations.AddAnnotations(node, desired)
+	labelsChanged := r.syncNodeLabels(machine, node)
+	taintsChanged := r.reconcileStartupTaint(ctx, machine, node, firstReconcile)
+	if annotationsChanged || labelsChanged || taintsChanged {
 		if err := patchHelper.Patch(ctx, node); err != nil {
-			log.V(2).Info("Failed patch node to set annotations", "err", err, "node name", node.Name)
+			log.V(2).Info("Failed patch node to set annotations, labels and taints", "err", err, "node name", node.Name)
 			return ctrl.Result{}, err
 		}
 	}
 
+	// The conditions of a Node are not meaningful until the cloud provider initializes it, so a Node which is only
+	// tainted as uninitialized is reported as provisioned but not yet ready, rather than flapping the node health.
+	if isNodeUninitialized(node, startupTaint(machine)) {
+		conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.NodeUninitializedReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for the cloud provider to initialize the Node")
+		return ctrl.Result{}, nil
+	}
+
 	// Do the remaining node health checks, then set the node health to true if all checks pass.
-	status, message := summarizeNodeConditions(node)
+	nodeHealthyConditions := r.NodeHealthyConditions
+	if len(nodeHealthyConditions) == 0 {
+		nodeHealthyConditions = DefaultNodeHealthyConditions
+	}
+	status, message := summarizeNodeConditions(node, nodeHealthyConditions)
 	if status == corev1.ConditionFalse {
 		conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.NodeConditionsFailedReason, clusterv1.ConditionSeverityWarning, message)
 		return ctrl.Result{}, nil
@@ -120,37 +137,46 @@ func (r *MachineReconciler) reconcileNode(ctx context.Context, cluster *clusterv
 	return ctrl.Result{}, nil
 }
 
-// summarizeNodeConditions summarizes a Node's conditions and returns the summary of condition statuses and concatenate failed condition messages:
+// DefaultNodeHealthyConditions are the Node conditions summarized by the NodeHealthy condition of a Machine
+// when the MachineReconciler does not set any.
+var DefaultNodeHealthyConditions = []corev1.NodeConditionType{
+	corev1.NodeReady,
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+}
+
+// summarizeNodeConditions summarizes the given conditions of a Node and returns the summary of condition statuses and concatenate failed condition messages:
 // if there is at least 1 semantically-negative condition, summarized status = False;
 // if there is at least 1 semantically-positive condition when there is 0 semantically negative condition, summarized status = True;
 // if all conditions are unknown,  summarized status = Unknown.
-// (semantically true conditions: NodeMemoryPressure/NodeDiskPressure/NodePIDPressure == false or Ready == true.)
-func summarizeNodeConditions(node *corev1.Node) (corev1.ConditionStatus, string) {
-	totalNumOfConditionsChecked := 4
+// (semantically true conditions: Ready == true, or any other condition, e.g. NodeMemoryPressure/NodeDiskPressure/NodePIDPressure, == false.)
+func summarizeNodeConditions(node *corev1.Node, conditionTypes []corev1.NodeConditionType) (corev1.ConditionStatus, string) {
+	totalNumOfConditionsChecked := len(conditionTypes)
 	semanticallyFalseStatus := 0
 	unknownStatus := 0
 
+	checked := make(map[corev1.NodeConditionType]bool, len(conditionTypes))
+	for _, conditionType := range conditionTypes {
+		checked[conditionType] = true
+	}
+
 	message := ""
 	for _, condition := range node.Status.Conditions {
-		switch condition.Type {
-		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
-			if condition.Status != corev1.ConditionFalse {
-				message += fmt.Sprintf("Node condition %s is %s", condition.Type, condition.Status) + ". "
-				if condition.Status == corev1.ConditionUnknown {
-					unknownStatus++
-					continue
-				}
-				semanticallyFalseStatus++
-			}
-		case corev1.NodeReady:
-			if condition.Status != corev1.ConditionTrue {
-				message += fmt.Sprintf("Node condition %s is %s", condition.Type, condition.Status) + ". "
-				if condition.Status == corev1.ConditionUnknown {
-					unknownStatus++
-					continue
-				}
-				semanticallyFalseStatus++
+		if !checked[condition.Type] {
+			continue
+		}
+		healthyStatus := corev1.ConditionFalse
+		if condition.Type == corev1.NodeReady {
+			healthyStatus = corev1.ConditionTrue
+		}
+		if condition.Status != healthyStatus {
+			message += fmt.Sprintf("Node condition %s is %s", condition.Type, condition.Status) + ". "
+			if condition.Status == corev1.ConditionUnknown {
+				unknownStatus++
+				continue
 			}
+			semanticallyFalseStatus++
 		}
 	}
 	if semanticallyFalseStatus > 0 {
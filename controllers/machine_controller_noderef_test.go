@@ -25,10 +25,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/test/helpers"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 func TestGetNodeReference(t *testing.T) {
@@ -126,9 +131,11 @@ func TestGetNodeReference(t *testing.T) {
 
 func TestSummarizeNodeConditions(t *testing.T) {
 	testCases := []struct {
-		name       string
-		conditions []corev1.NodeCondition
-		status     corev1.ConditionStatus
+		name           string
+		conditionTypes []corev1.NodeConditionType
+		conditions     []corev1.NodeCondition
+		status         corev1.ConditionStatus
+		message        string
 	}{
 		{
 			name: "node is healthy",
@@ -170,6 +177,40 @@ func TestSummarizeNodeConditions(t *testing.T) {
 			},
 			status: corev1.ConditionTrue,
 		},
+		{
+			name: "node under memory and disk pressure",
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodePIDPressure, Status: corev1.ConditionFalse},
+			},
+			status:  corev1.ConditionFalse,
+			message: "Node condition MemoryPressure is True. Node condition DiskPressure is True. ",
+		},
+		{
+			name:           "pressure conditions not summarized",
+			conditionTypes: []corev1.NodeConditionType{corev1.NodeReady},
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodePIDPressure, Status: corev1.ConditionTrue},
+			},
+			status: corev1.ConditionTrue,
+		},
+		{
+			name:           "custom condition summarized",
+			conditionTypes: []corev1.NodeConditionType{corev1.NodeReady, corev1.NodePIDPressure, "KernelDeadlock"},
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodePIDPressure, Status: corev1.ConditionUnknown},
+				{Type: "KernelDeadlock", Status: corev1.ConditionTrue},
+			},
+			status:  corev1.ConditionFalse,
+			message: "Node condition PIDPressure is Unknown. Node condition KernelDeadlock is True. ",
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -182,8 +223,209 @@ func TestSummarizeNodeConditions(t *testing.T) {
 					Conditions: test.conditions,
 				},
 			}
-			status, _ := summarizeNodeConditions(node)
+			conditionTypes := test.conditionTypes
+			if conditionTypes == nil {
+				conditionTypes = DefaultNodeHealthyConditions
+			}
+			status, message := summarizeNodeConditions(node, conditionTypes)
 			g.Expect(status).To(Equal(test.status))
+			if test.message != "" {
+				g.Expect(message).To(Equal(test.message))
+			}
+		})
+	}
+}
+
+func TestReconcileNodeHealthyCondition(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		nodeHealthyConditions []corev1.NodeConditionType
+		nodeConditions        []corev1.NodeCondition
+		nodeTaints            []corev1.Taint
+		machineAnnotations    map[string]string
+		expectedStatus        corev1.ConditionStatus
+		expectedReason        string
+		expectedMessage       string
+	}{
+		{
+			name: "node without pressure",
+			nodeConditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodePIDPressure, Status: corev1.ConditionFalse},
+			},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "node under PID pressure",
+			nodeConditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodePIDPressure, Status: corev1.ConditionTrue},
+			},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  clusterv1.NodeConditionsFailedReason,
+			expectedMessage: "Node condition PIDPressure is True.",
+		},
+		{
+			name:                  "node under memory pressure not summarized",
+			nodeHealthyConditions: []corev1.NodeConditionType{corev1.NodeReady, corev1.NodeDiskPressure},
+			nodeConditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+			},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:                  "node under disk pressure summarized",
+			nodeHealthyConditions: []corev1.NodeConditionType{corev1.NodeReady, corev1.NodeDiskPressure},
+			nodeConditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+			},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  clusterv1.NodeConditionsFailedReason,
+			expectedMessage: "Node condition DiskPressure is True.",
+		},
+		{
+			name: "node not initialized by the cloud provider",
+			nodeConditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+			nodeTaints: []corev1.Taint{
+				{Key: cloudProviderUninitializedTaintKey, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  clusterv1.NodeUninitializedReason,
+			expectedMessage: "Waiting for the cloud provider to initialize the Node",
+		},
+		{
+			name: "node not initialized by the cloud provider with the startup taint of the machine",
+			machineAnnotations: map[string]string{
+				clusterv1.StartupTaintAnnotation: "node.cluster.x-k8s.io/uninitialized:NoSchedule",
+			},
+			nodeConditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+			nodeTaints: []corev1.Taint{
+				{Key: cloudProviderUninitializedTaintKey, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.cluster.x-k8s.io/uninitialized", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  clusterv1.NodeUninitializedReason,
+			expectedMessage: "Waiting for the cloud provider to initialize the Node",
+		},
+		{
+			name: "node not ready and not initialized by the cloud provider",
+			nodeConditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+			},
+			nodeTaints: []corev1.Taint{
+				{Key: cloudProviderUninitializedTaintKey, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  clusterv1.NodeConditionsFailedReason,
+			expectedMessage: "Node condition Ready is False.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			}
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Spec:       corev1.NodeSpec{ProviderID: "aws:///us-east-1/i-1", Taints: tc.nodeTaints},
+				Status:     corev1.NodeStatus{Conditions: tc.nodeConditions},
+			}
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-1", Namespace: "default", Annotations: tc.machineAnnotations},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: cluster.Name,
+					ProviderID:  pointer.StringPtr("aws:///us-east-1/i-1"),
+				},
+			}
+
+			// The fake client is used both as the management and as the workload cluster client.
+			c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, machine, node)
+			r := &MachineReconciler{
+				Client:                c,
+				Tracker:               remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(cluster)),
+				NodeHealthyConditions: tc.nodeHealthyConditions,
+				recorder:              record.NewFakeRecorder(32),
+			}
+
+			_, err := r.reconcileNode(ctx, cluster, machine)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			nodeHealthyCondition := conditions.Get(machine, clusterv1.MachineNodeHealthyCondition)
+			g.Expect(nodeHealthyCondition).ToNot(BeNil())
+			g.Expect(nodeHealthyCondition.Status).To(Equal(tc.expectedStatus))
+			if tc.expectedStatus == corev1.ConditionFalse {
+				g.Expect(nodeHealthyCondition.Reason).To(Equal(tc.expectedReason))
+				g.Expect(nodeHealthyCondition.Message).To(ContainSubstring(tc.expectedMessage))
+			}
 		})
 	}
 }
+
+func TestReconcileNodeHealthyConditionCloudProviderInitialization(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec: corev1.NodeSpec{
+			ProviderID: "aws:///us-east-1/i-1",
+			Taints: []corev1.Taint{
+				{Key: cloudProviderUninitializedTaintKey, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine-1", Namespace: "default"},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: cluster.Name,
+			ProviderID:  pointer.StringPtr("aws:///us-east-1/i-1"),
+		},
+	}
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, machine, node)
+	r := &MachineReconciler{
+		Client:   c,
+		Tracker:  remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(cluster)),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The Node is provisioned, but not yet initialized by the cloud provider.
+	_, err := r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(machine.Status.NodeRef).ToNot(BeNil())
+	g.Expect(conditions.IsFalse(machine, clusterv1.MachineNodeHealthyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(machine, clusterv1.MachineNodeHealthyCondition)).To(Equal(clusterv1.NodeUninitializedReason))
+	g.Expect(*conditions.GetSeverity(machine, clusterv1.MachineNodeHealthyCondition)).To(Equal(clusterv1.ConditionSeverityInfo))
+
+	// The cloud provider removes the taint once the Node is initialized.
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), node)).To(Succeed())
+	node.Spec.Taints = nil
+	g.Expect(c.Update(ctx, node)).To(Succeed())
+
+	_, err = r.reconcileNode(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsTrue(machine, clusterv1.MachineNodeHealthyCondition)).To(BeTrue())
+}
@@ -82,6 +82,51 @@ func (r *MachineReconciler) reconcilePhase(_ context.Context, m *clusterv1.Machi
 	if m.Status.Phase != originalPhase {
 		now := metav1.Now()
 		m.Status.LastUpdated = &now
+		recordPhaseTransitionTime(m, now)
+	}
+}
+
+// recordPhaseTransitionTime records when the Machine first entered the Provisioning, Provisioned or Running phase.
+func recordPhaseTransitionTime(m *clusterv1.Machine, now metav1.Time) {
+	if m.Status.PhaseTransitionTimes == nil {
+		m.Status.PhaseTransitionTimes = &clusterv1.MachinePhaseTransitionTimes{}
+	}
+	times := m.Status.PhaseTransitionTimes
+
+	switch m.Status.GetTypedPhase() {
+	case clusterv1.MachinePhaseProvisioning:
+		if times.Provisioning == nil {
+			times.Provisioning = &now
+		}
+	case clusterv1.MachinePhaseProvisioned:
+		if times.Provisioned == nil {
+			times.Provisioned = &now
+		}
+	case clusterv1.MachinePhaseRunning:
+		if times.Running == nil {
+			times.Running = &now
+		}
+	}
+
+	if times.Provisioning == nil && times.Provisioned == nil && times.Running == nil {
+		m.Status.PhaseTransitionTimes = nil
+	}
+}
+
+// observeProvisionDuration observes how long the Machine took to become Running, measured from when it entered the
+// Provisioning phase or, if it skipped it, from its creation. It must be called only once the first transition of
+// the Machine to Running has been persisted, so the duration is observed once per Machine.
+func observeProvisionDuration(m *clusterv1.Machine) {
+	times := m.Status.PhaseTransitionTimes
+	if times == nil || times.Running == nil {
+		return
+	}
+	start := m.CreationTimestamp
+	if times.Provisioning != nil {
+		start = *times.Provisioning
+	}
+	if !start.IsZero() {
+		machineProvisionDurationSeconds.Observe(times.Running.Sub(start.Time).Seconds())
 	}
 }
 
@@ -279,6 +324,10 @@ func (r *MachineReconciler) reconcileInfrastructure(ctx context.Context, cluster
 
 	// If the infrastructure provider is not ready, return early.
 	if !ready {
+		if infrastructureReadyTimeoutExceeded(m, infraConfig) {
+			log.Info("Infrastructure provider did not report ready within the infrastructure ready timeout", "timeout", m.Spec.InfrastructureReadyTimeout.Duration)
+			markInfrastructureProvisionFailed(m, infraConfig)
+		}
 		log.Info("Infrastructure provider is not ready, requeuing")
 		return ctrl.Result{RequeueAfter: externalReadyWait}, nil
 	}
@@ -311,3 +360,28 @@ func (r *MachineReconciler) reconcileInfrastructure(ctx context.Context, cluster
 	m.Spec.ProviderID = pointer.StringPtr(providerID)
 	return ctrl.Result{}, nil
 }
+
+// infrastructureReadyTimeoutExceeded returns true if the infrastructure machine did not report being ready
+// within the machine's InfrastructureReadyTimeout, measured from the creation of the infrastructure machine.
+func infrastructureReadyTimeoutExceeded(m *clusterv1.Machine, infraConfig *unstructured.Unstructured) bool {
+	// if the InfrastructureReadyTimeout is not set by user
+	if m.Spec.InfrastructureReadyTimeout == nil || m.Spec.InfrastructureReadyTimeout.Seconds() <= 0 {
+		return false
+	}
+
+	// if the infrastructure machine already reported a failure
+	if m.Status.FailureReason != nil || m.Status.FailureMessage != nil {
+		return false
+	}
+
+	diff := time.Since(infraConfig.GetCreationTimestamp().Time)
+	return diff.Seconds() >= m.Spec.InfrastructureReadyTimeout.Seconds()
+}
+
+// markInfrastructureProvisionFailed reports the infrastructure machine failing to become ready in time in the
+// InfrastructureReady condition of the machine; the MachineHealthChecks targeting the machine decide whether to
+// remediate it, according to its InfrastructureReadyTimeoutPolicy.
+func markInfrastructureProvisionFailed(m *clusterv1.Machine, infraConfig *unstructured.Unstructured) {
+	conditions.MarkFalse(m, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureProvisionFailedReason, clusterv1.ConditionSeverityError,
+		"%s %q did not report ready within the InfrastructureReadyTimeout of %s", infraConfig.GetKind(), infraConfig.GetName(), m.Spec.InfrastructureReadyTimeout.Duration)
+}
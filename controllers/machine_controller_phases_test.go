@@ -22,6 +22,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -1034,6 +1035,81 @@ func TestReconcileInfrastructure(t *testing.T) {
 				g.Expect(m.Status.InfrastructureReady).To(BeFalse())
 			},
 		},
+		{
+			name:    "infrastructure config not ready, infrastructure ready timeout not exceeded",
+			machine: machineWithInfrastructureReadyTimeout(""),
+			infraConfig: map[string]interface{}{
+				"kind":       "InfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+				"metadata": map[string]interface{}{
+					"name":              "infra-config1",
+					"namespace":         "default",
+					"creationTimestamp": metav1.Now().Format(time.RFC3339),
+				},
+				"spec": map[string]interface{}{},
+				"status": map[string]interface{}{
+					"ready": false,
+				},
+			},
+			expectResult: ctrl.Result{RequeueAfter: externalReadyWait},
+			expectError:  false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeFalse())
+				g.Expect(conditions.GetReason(m, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.WaitingForInfrastructureFallbackReason))
+			},
+		},
+		{
+			name:    "infrastructure config not ready, infrastructure ready timeout exceeded",
+			machine: machineWithInfrastructureReadyTimeout(""),
+			infraConfig: map[string]interface{}{
+				"kind":       "InfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+				"metadata": map[string]interface{}{
+					"name":              "infra-config1",
+					"namespace":         "default",
+					"creationTimestamp": metav1.NewTime(time.Now().Add(-time.Hour)).Format(time.RFC3339),
+				},
+				"spec": map[string]interface{}{},
+				"status": map[string]interface{}{
+					"ready": false,
+				},
+			},
+			expectResult: ctrl.Result{RequeueAfter: externalReadyWait},
+			expectError:  false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeFalse())
+				g.Expect(conditions.IsFalse(m, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(m, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureProvisionFailedReason))
+				g.Expect(conditions.Get(m, clusterv1.InfrastructureReadyCondition).Severity).To(Equal(clusterv1.ConditionSeverityError))
+				g.Expect(conditions.Has(m, clusterv1.MachineOwnerRemediatedCondition)).To(BeFalse())
+			},
+		},
+		{
+			name:    "infrastructure config not ready, infrastructure ready timeout exceeded with the remediate policy",
+			machine: machineWithInfrastructureReadyTimeout(clusterv1.InfrastructureReadyTimeoutRemediatePolicy),
+			infraConfig: map[string]interface{}{
+				"kind":       "InfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+				"metadata": map[string]interface{}{
+					"name":              "infra-config1",
+					"namespace":         "default",
+					"creationTimestamp": metav1.NewTime(time.Now().Add(-time.Hour)).Format(time.RFC3339),
+				},
+				"spec": map[string]interface{}{},
+				"status": map[string]interface{}{
+					"ready": false,
+				},
+			},
+			expectResult: ctrl.Result{RequeueAfter: externalReadyWait},
+			expectError:  false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeFalse())
+				g.Expect(conditions.GetReason(m, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureProvisionFailedReason))
+				// Remediating the machine is left to the MachineHealthChecks.
+				g.Expect(conditions.Has(m, clusterv1.MachineHealthCheckSuccededCondition)).To(BeFalse())
+				g.Expect(conditions.Has(m, clusterv1.MachineOwnerRemediatedCondition)).To(BeFalse())
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1072,3 +1148,170 @@ func TestReconcileInfrastructure(t *testing.T) {
 		})
 	}
 }
+
+func machineWithInfrastructureReadyTimeout(policy clusterv1.InfrastructureReadyTimeoutPolicy) *clusterv1.Machine {
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-test",
+			Namespace: "default",
+		},
+		Spec: clusterv1.MachineSpec{
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+				Kind:       "InfrastructureMachine",
+				Name:       "infra-config1",
+			},
+			InfrastructureReadyTimeout:       &metav1.Duration{Duration: 10 * time.Minute},
+			InfrastructureReadyTimeoutPolicy: policy,
+		},
+	}
+}
+
+func TestReconcilePhaseTransitionTimes(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &MachineReconciler{}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "machine-test",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+	}
+	observations := provisionDurationSampleCount(g)
+
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhasePending))
+	g.Expect(machine.Status.PhaseTransitionTimes).To(BeNil())
+
+	machine.Status.BootstrapReady = true
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseProvisioning))
+	g.Expect(machine.Status.PhaseTransitionTimes).ToNot(BeNil())
+	provisioning := machine.Status.PhaseTransitionTimes.Provisioning
+	g.Expect(provisioning).ToNot(BeNil())
+	g.Expect(machine.Status.PhaseTransitionTimes.Provisioned).To(BeNil())
+	g.Expect(machine.Status.PhaseTransitionTimes.Running).To(BeNil())
+
+	// Reconciling again in the same phase must not move the timestamp.
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.PhaseTransitionTimes.Provisioning).To(BeIdenticalTo(provisioning))
+
+	machine.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: "machine-test-node"}
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseProvisioned))
+	provisioned := machine.Status.PhaseTransitionTimes.Provisioned
+	g.Expect(provisioned).ToNot(BeNil())
+	g.Expect(machine.Status.PhaseTransitionTimes.Provisioning).To(BeIdenticalTo(provisioning))
+	g.Expect(machine.Status.PhaseTransitionTimes.Running).To(BeNil())
+	g.Expect(provisionDurationSampleCount(g)).To(Equal(observations))
+
+	machine.Status.InfrastructureReady = true
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseRunning))
+	running := machine.Status.PhaseTransitionTimes.Running
+	g.Expect(running).ToNot(BeNil())
+	g.Expect(machine.Status.PhaseTransitionTimes.Provisioning).To(BeIdenticalTo(provisioning))
+	g.Expect(machine.Status.PhaseTransitionTimes.Provisioned).To(BeIdenticalTo(provisioned))
+	// The provisioning duration is observed only once the transition has been persisted.
+	g.Expect(provisionDurationSampleCount(g)).To(Equal(observations))
+
+	// Going through Provisioned and Running again, e.g. after the Node has been replaced, does not record
+	// new timestamps.
+	machine.Status.InfrastructureReady = false
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseProvisioned))
+	machine.Status.InfrastructureReady = true
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseRunning))
+	g.Expect(machine.Status.PhaseTransitionTimes.Provisioned).To(BeIdenticalTo(provisioned))
+	g.Expect(machine.Status.PhaseTransitionTimes.Running).To(BeIdenticalTo(running))
+	g.Expect(provisionDurationSampleCount(g)).To(Equal(observations))
+}
+
+func TestReconcilePhaseTransitionTimesSkippedPhases(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &MachineReconciler{}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "machine-test",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Status: clusterv1.MachineStatus{
+			BootstrapReady:      true,
+			InfrastructureReady: true,
+			NodeRef:             &corev1.ObjectReference{Kind: "Node", Name: "machine-test-node"},
+		},
+	}
+	observations := provisionDurationSampleCount(g)
+
+	// A Machine becoming Running within a single reconcile only records the Running timestamp,
+	// and the provisioning duration is measured from its creation.
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseRunning))
+	g.Expect(machine.Status.PhaseTransitionTimes).ToNot(BeNil())
+	g.Expect(machine.Status.PhaseTransitionTimes.Provisioning).To(BeNil())
+	g.Expect(machine.Status.PhaseTransitionTimes.Provisioned).To(BeNil())
+	g.Expect(machine.Status.PhaseTransitionTimes.Running).ToNot(BeNil())
+
+	observeProvisionDuration(machine)
+	g.Expect(provisionDurationSampleCount(g)).To(Equal(observations + 1))
+}
+
+func TestObserveProvisionDuration(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	provisioning := metav1.NewTime(created.Add(10 * time.Minute))
+	running := metav1.NewTime(created.Add(30 * time.Minute))
+
+	tests := []struct {
+		name                 string
+		times                *clusterv1.MachinePhaseTransitionTimes
+		expectedObservations uint64
+		expectedSum          float64
+	}{
+		{
+			name:                 "does not observe a Machine which has not been Running",
+			times:                &clusterv1.MachinePhaseTransitionTimes{Provisioning: &provisioning},
+			expectedObservations: 0,
+		},
+		{
+			name:                 "measures the duration from the Provisioning phase",
+			times:                &clusterv1.MachinePhaseTransitionTimes{Provisioning: &provisioning, Running: &running},
+			expectedObservations: 1,
+			expectedSum:          (20 * time.Minute).Seconds(),
+		},
+		{
+			name:                 "measures the duration from the creation if the Provisioning phase was skipped",
+			times:                &clusterv1.MachinePhaseTransitionTimes{Running: &running},
+			expectedObservations: 1,
+			expectedSum:          (30 * time.Minute).Seconds(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-test", Namespace: "default", CreationTimestamp: created},
+				Status:     clusterv1.MachineStatus{PhaseTransitionTimes: tt.times},
+			}
+			before := &dto.Metric{}
+			g.Expect(machineProvisionDurationSeconds.Write(before)).To(Succeed())
+
+			observeProvisionDuration(machine)
+
+			after := &dto.Metric{}
+			g.Expect(machineProvisionDurationSeconds.Write(after)).To(Succeed())
+			g.Expect(after.GetHistogram().GetSampleCount() - before.GetHistogram().GetSampleCount()).To(Equal(tt.expectedObservations))
+			g.Expect(after.GetHistogram().GetSampleSum() - before.GetHistogram().GetSampleSum()).To(BeNumerically("~", tt.expectedSum, 0.001))
+		})
+	}
+}
+
+func provisionDurationSampleCount(g *WithT) uint64 {
+	metric := &dto.Metric{}
+	g.Expect(machineProvisionDurationSeconds.Write(metric)).To(Succeed())
+	return metric.GetHistogram().GetSampleCount()
+}
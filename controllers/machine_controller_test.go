@@ -17,16 +17,19 @@ limitations under the License.
 package controllers
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/external"
@@ -975,6 +978,54 @@ func TestMachineConditions(t *testing.T) {
 				conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.NodeNotFoundReason, clusterv1.ConditionSeverityWarning, ""),
 			},
 		},
+		// Assert readiness gates
+		{
+			name:           "ready condition waits for the readiness gates not reported yet",
+			infraReady:     true,
+			bootstrapReady: true,
+			beforeFunc: func(bootstrap, infra *unstructured.Unstructured, m *clusterv1.Machine) {
+				m.Spec.ReadinessGates = []clusterv1.MachineReadinessGate{{ConditionType: "SecurityAgentRegistered"}}
+			},
+			conditionsToAssert: []*clusterv1.Condition{
+				conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.ReadinessGatesNotMetReason, clusterv1.ConditionSeverityInfo, "Waiting for SecurityAgentRegistered"),
+			},
+		},
+		{
+			name:           "ready condition summary consumes reason from an unmet readiness gate",
+			infraReady:     true,
+			bootstrapReady: true,
+			beforeFunc: func(bootstrap, infra *unstructured.Unstructured, m *clusterv1.Machine) {
+				m.Spec.ReadinessGates = []clusterv1.MachineReadinessGate{{ConditionType: "SecurityAgentRegistered"}}
+				conditions.MarkFalse(m, "SecurityAgentRegistered", "AgentNotRunning", clusterv1.ConditionSeverityWarning, "agent is not running")
+			},
+			conditionsToAssert: []*clusterv1.Condition{
+				conditions.FalseCondition(clusterv1.ReadyCondition, "AgentNotRunning", clusterv1.ConditionSeverityWarning, "agent is not running"),
+			},
+		},
+		{
+			name:           "ready condition true when the readiness gates are met",
+			infraReady:     true,
+			bootstrapReady: true,
+			beforeFunc: func(bootstrap, infra *unstructured.Unstructured, m *clusterv1.Machine) {
+				m.Spec.ReadinessGates = []clusterv1.MachineReadinessGate{{ConditionType: "SecurityAgentRegistered"}}
+				conditions.MarkTrue(m, "SecurityAgentRegistered")
+			},
+			conditionsToAssert: []*clusterv1.Condition{
+				conditions.TrueCondition("SecurityAgentRegistered"),
+				conditions.TrueCondition(clusterv1.ReadyCondition),
+			},
+		},
+		{
+			name:           "ready condition ignores the conditions of removed readiness gates",
+			infraReady:     true,
+			bootstrapReady: true,
+			beforeFunc: func(bootstrap, infra *unstructured.Unstructured, m *clusterv1.Machine) {
+				conditions.MarkFalse(m, "SecurityAgentRegistered", "AgentNotRunning", clusterv1.ConditionSeverityWarning, "agent is not running")
+			},
+			conditionsToAssert: []*clusterv1.Condition{
+				conditions.TrueCondition(clusterv1.ReadyCondition),
+			},
+		},
 	}
 
 	for _, tt := range testcases {
@@ -1306,6 +1357,33 @@ func TestIsNodeDrainedAllowed(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "Node draining timeout recorded in status is over",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-machine",
+					Namespace:  "default",
+					Finalizers: []string{clusterv1.MachineFinalizer},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName:       "test-cluster",
+					InfrastructureRef: corev1.ObjectReference{},
+					Bootstrap:         clusterv1.Bootstrap{DataSecretName: pointer.StringPtr("data")},
+					NodeDrainTimeout:  &metav1.Duration{Duration: time.Second * 60},
+				},
+				Status: clusterv1.MachineStatus{
+					NodeDrainStartTime: &metav1.Time{Time: time.Now().Add(-(time.Second * 70)).UTC()},
+					Conditions: clusterv1.Conditions{
+						{
+							Type:               clusterv1.DrainingSucceededCondition,
+							Status:             corev1.ConditionFalse,
+							LastTransitionTime: metav1.Time{Time: time.Now().Add(-(time.Second * 10)).UTC()},
+						},
+					},
+				},
+			},
+			expected: false,
+		},
 		{
 			name: "NodeDrainTimeout option is set to its default value 0",
 			machine: &clusterv1.Machine{
@@ -1349,6 +1427,456 @@ func TestIsNodeDrainedAllowed(t *testing.T) {
 	}
 }
 
+func TestReconcileDeleteNodeDrainTimeout(t *testing.T) {
+	testCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+	}
+	// A pod which could not be evicted, e.g. because of a PodDisruptionBudget, keeping the node from being drained.
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unevictable"},
+		Spec:       corev1.PodSpec{NodeName: node.Name},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	controlPlaneMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "control-plane",
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:             testCluster.Name,
+				clusterv1.MachineControlPlaneLabelName: "",
+			},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		drainingCondition clusterv1.Condition
+		expectedCondition clusterv1.Condition
+	}{
+		{
+			name: "marks the drain as failed while draining",
+			drainingCondition: clusterv1.Condition{
+				Type:     clusterv1.DrainingSucceededCondition,
+				Status:   corev1.ConditionFalse,
+				Reason:   clusterv1.DrainingReason,
+				Severity: clusterv1.ConditionSeverityInfo,
+				Message:  "Draining the node before deletion",
+			},
+			expectedCondition: clusterv1.Condition{
+				Type:     clusterv1.DrainingSucceededCondition,
+				Status:   corev1.ConditionFalse,
+				Reason:   clusterv1.DrainingFailedReason,
+				Severity: clusterv1.ConditionSeverityWarning,
+				Message:  "Node drain did not complete within the NodeDrainTimeout of 1m0s",
+			},
+		},
+		{
+			name: "keeps an existing drain failure",
+			drainingCondition: clusterv1.Condition{
+				Type:     clusterv1.DrainingSucceededCondition,
+				Status:   corev1.ConditionFalse,
+				Reason:   clusterv1.DrainingFailedReason,
+				Severity: clusterv1.ConditionSeverityWarning,
+				Message:  "previous failure",
+			},
+			expectedCondition: clusterv1.Condition{
+				Type:     clusterv1.DrainingSucceededCondition,
+				Status:   corev1.ConditionFalse,
+				Reason:   clusterv1.DrainingFailedReason,
+				Severity: clusterv1.ConditionSeverityWarning,
+				Message:  "previous failure",
+			},
+		},
+		{
+			name: "keeps a drain which already succeeded",
+			drainingCondition: clusterv1.Condition{
+				Type:   clusterv1.DrainingSucceededCondition,
+				Status: corev1.ConditionTrue,
+			},
+			expectedCondition: clusterv1.Condition{
+				Type:   clusterv1.DrainingSucceededCondition,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			drainingCondition := tt.drainingCondition
+			drainingCondition.LastTransitionTime = metav1.Now()
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              "delete-me",
+					Labels:            map[string]string{clusterv1.ClusterLabelName: testCluster.Name},
+					Finalizers:        []string{clusterv1.MachineFinalizer},
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: testCluster.Name,
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+						Kind:       "GenericInfrastructureMachine",
+						Name:       "infra-config1",
+					},
+					Bootstrap:        clusterv1.Bootstrap{DataSecretName: pointer.StringPtr("data")},
+					NodeDrainTimeout: &metav1.Duration{Duration: time.Minute},
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: &corev1.ObjectReference{Kind: "Node", Name: node.Name},
+					// The controller started draining the node longer ago than the NodeDrainTimeout.
+					NodeDrainStartTime: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+					Conditions:         clusterv1.Conditions{drainingCondition},
+				},
+			}
+
+			c := helpers.NewFakeClientWithScheme(scheme.Scheme, testCluster, controlPlaneMachine, machine, node.DeepCopy(), pod.DeepCopy(), external.TestGenericInfrastructureCRD.DeepCopy())
+			r := &MachineReconciler{
+				Client:   c,
+				Tracker:  remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(testCluster)),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			_, err := r.reconcileDelete(ctx, testCluster, machine)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// The controller stopped draining and proceeded with the deletion.
+			g.Expect(machine.Finalizers).To(BeEmpty())
+			condition := conditions.Get(machine, clusterv1.DrainingSucceededCondition)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition.Status).To(Equal(tt.expectedCondition.Status))
+			g.Expect(condition.Reason).To(Equal(tt.expectedCondition.Reason))
+			g.Expect(condition.Severity).To(Equal(tt.expectedCondition.Severity))
+			g.Expect(condition.Message).To(Equal(tt.expectedCondition.Message))
+
+			g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{}))).To(BeTrue())
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{})).To(Succeed())
+		})
+	}
+}
+
+func TestReconcileDeleteExcludeNodeDraining(t *testing.T) {
+	g := NewWithT(t)
+
+	testCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+	}
+	// A pod which would be evicted when draining the node.
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "workload"},
+		Spec:       corev1.PodSpec{NodeName: node.Name},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	controlPlaneMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "control-plane",
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:             testCluster.Name,
+				clusterv1.MachineControlPlaneLabelName: "",
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "delete-me",
+			Labels:            map[string]string{clusterv1.ClusterLabelName: testCluster.Name},
+			Finalizers:        []string{clusterv1.MachineFinalizer},
+			DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			Annotations:       map[string]string{clusterv1.ExcludeNodeDrainingAnnotation: ""},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: testCluster.Name,
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+				Kind:       "GenericInfrastructureMachine",
+				Name:       "infra-config1",
+			},
+			Bootstrap: clusterv1.Bootstrap{DataSecretName: pointer.StringPtr("data")},
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Kind: "Node", Name: node.Name},
+		},
+	}
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, testCluster, controlPlaneMachine, machine, node, pod, external.TestGenericInfrastructureCRD.DeepCopy())
+	r := &MachineReconciler{
+		Client:   c,
+		Tracker:  remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(testCluster)),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	_, err := r.reconcileDelete(ctx, testCluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The controller skipped draining the node, but still waited for its volumes to be detached.
+	g.Expect(machine.Finalizers).To(BeEmpty())
+	g.Expect(conditions.Get(machine, clusterv1.DrainingSucceededCondition)).To(BeNil())
+	g.Expect(machine.Status.NodeDrainStartTime).To(BeNil())
+	g.Expect(conditions.IsTrue(machine, clusterv1.VolumeDetachSucceededCondition)).To(BeTrue())
+
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{}))).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{})).To(Succeed())
+}
+
+func TestReconcileDeleteWaitForVolumeDetach(t *testing.T) {
+	testCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+	}
+	volumeAttachment := &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-volume-attachment"},
+		Spec: storagev1.VolumeAttachmentSpec{
+			Attacher: "test-attacher",
+			NodeName: node.Name,
+		},
+	}
+	controlPlaneMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "control-plane",
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:             testCluster.Name,
+				clusterv1.MachineControlPlaneLabelName: "",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		volumeAttachments []client.Object
+		waitingSince      *metav1.Time
+		excludeWait       bool
+		expectRequeue     bool
+		expectedStatus    corev1.ConditionStatus
+		expectedReason    string
+	}{
+		{
+			name:              "should wait for the volumes attached to the node to be detached",
+			volumeAttachments: []client.Object{volumeAttachment.DeepCopy()},
+			expectRequeue:     true,
+			expectedStatus:    corev1.ConditionFalse,
+			expectedReason:    clusterv1.WaitingForVolumeDetachReason,
+		},
+		{
+			name:           "should proceed with the deletion when no volume is attached to the node",
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:              "should proceed with the deletion when the NodeVolumeDetachTimeout is exceeded",
+			volumeAttachments: []client.Object{volumeAttachment.DeepCopy()},
+			waitingSince:      &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+			expectedStatus:    corev1.ConditionFalse,
+			expectedReason:    clusterv1.VolumeDetachTimeoutReason,
+		},
+		{
+			name:              "should proceed with the deletion without waiting when the machine has the exclude wait for node volume detach annotation",
+			volumeAttachments: []client.Object{volumeAttachment.DeepCopy()},
+			excludeWait:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              "delete-me",
+					Labels:            map[string]string{clusterv1.ClusterLabelName: testCluster.Name},
+					Finalizers:        []string{clusterv1.MachineFinalizer},
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+					Annotations:       map[string]string{clusterv1.ExcludeNodeDrainingAnnotation: ""},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: testCluster.Name,
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+						Kind:       "GenericInfrastructureMachine",
+						Name:       "infra-config1",
+					},
+					Bootstrap:               clusterv1.Bootstrap{DataSecretName: pointer.StringPtr("data")},
+					NodeVolumeDetachTimeout: &metav1.Duration{Duration: time.Minute},
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: &corev1.ObjectReference{Kind: "Node", Name: node.Name},
+				},
+			}
+			if tc.excludeWait {
+				machine.Annotations[clusterv1.ExcludeWaitForNodeVolumeDetachAnnotation] = ""
+			}
+			if tc.waitingSince != nil {
+				machine.Status.Conditions = clusterv1.Conditions{
+					{
+						Type:               clusterv1.VolumeDetachSucceededCondition,
+						Status:             corev1.ConditionFalse,
+						Reason:             clusterv1.WaitingForVolumeDetachReason,
+						Severity:           clusterv1.ConditionSeverityInfo,
+						LastTransitionTime: *tc.waitingSince,
+					},
+				}
+			}
+
+			objs := []client.Object{testCluster.DeepCopy(), controlPlaneMachine.DeepCopy(), machine, node.DeepCopy(), external.TestGenericInfrastructureCRD.DeepCopy()}
+			objs = append(objs, tc.volumeAttachments...)
+			c := helpers.NewFakeClientWithScheme(scheme.Scheme, objs...)
+			r := &MachineReconciler{
+				Client:   c,
+				Tracker:  remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(testCluster)),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			res, err := r.reconcileDelete(ctx, testCluster, machine)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			if tc.expectRequeue {
+				g.Expect(res.RequeueAfter).ToNot(BeZero())
+				g.Expect(machine.Finalizers).To(ContainElement(clusterv1.MachineFinalizer))
+				g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{})).To(Succeed())
+			} else {
+				g.Expect(res.IsZero()).To(BeTrue())
+				g.Expect(machine.Finalizers).To(BeEmpty())
+				g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{}))).To(BeTrue())
+			}
+
+			volumeDetachCondition := conditions.Get(machine, clusterv1.VolumeDetachSucceededCondition)
+			if tc.excludeWait {
+				g.Expect(volumeDetachCondition).To(BeNil())
+				return
+			}
+			g.Expect(volumeDetachCondition).ToNot(BeNil())
+			g.Expect(volumeDetachCondition.Status).To(Equal(tc.expectedStatus))
+			g.Expect(volumeDetachCondition.Reason).To(Equal(tc.expectedReason))
+		})
+	}
+}
+
+func TestReconcileDeleteLifecycleHooks(t *testing.T) {
+	testCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+	}
+	controlPlaneMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "control-plane",
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:             testCluster.Name,
+				clusterv1.MachineControlPlaneLabelName: "",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		hooks             []string
+		expectedCondition clusterv1.ConditionType
+		expectDrained     bool
+	}{
+		{
+			name: "should block draining until the pre-drain hooks are removed",
+			hooks: []string{
+				clusterv1.PreDrainDeleteHookAnnotationPrefix + "/b-hook",
+				clusterv1.PreDrainDeleteHookAnnotationPrefix + "/a-hook",
+			},
+			expectedCondition: clusterv1.PreDrainDeleteHookSucceededCondition,
+		},
+		{
+			name: "should block termination until the pre-terminate hooks are removed",
+			hooks: []string{
+				clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/b-hook",
+				clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/a-hook",
+			},
+			expectedCondition: clusterv1.PreTerminateDeleteHookSucceededCondition,
+			expectDrained:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              "delete-me",
+					Labels:            map[string]string{clusterv1.ClusterLabelName: testCluster.Name},
+					Finalizers:        []string{clusterv1.MachineFinalizer},
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+					Annotations:       map[string]string{},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: testCluster.Name,
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+						Kind:       "GenericInfrastructureMachine",
+						Name:       "infra-config1",
+					},
+					Bootstrap: clusterv1.Bootstrap{DataSecretName: pointer.StringPtr("data")},
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: &corev1.ObjectReference{Kind: "Node", Name: node.Name},
+				},
+			}
+			for _, hook := range tc.hooks {
+				machine.Annotations[hook] = "owner"
+			}
+
+			c := helpers.NewFakeClientWithScheme(scheme.Scheme, testCluster.DeepCopy(), controlPlaneMachine.DeepCopy(), machine, node.DeepCopy(), external.TestGenericInfrastructureCRD.DeepCopy())
+			r := &MachineReconciler{
+				Client:   c,
+				Tracker:  remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(testCluster)),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			res, err := r.reconcileDelete(ctx, testCluster, machine)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res.IsZero()).To(BeTrue())
+
+			// The deletion is blocked while the hooks are outstanding.
+			g.Expect(machine.Finalizers).To(ContainElement(clusterv1.MachineFinalizer))
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{})).To(Succeed())
+			g.Expect(conditions.IsTrue(machine, clusterv1.DrainingSucceededCondition)).To(Equal(tc.expectDrained))
+
+			hookCondition := conditions.Get(machine, tc.expectedCondition)
+			g.Expect(hookCondition).ToNot(BeNil())
+			g.Expect(hookCondition.Status).To(Equal(corev1.ConditionFalse))
+			g.Expect(hookCondition.Reason).To(Equal(clusterv1.WaitingExternalHookReason))
+			g.Expect(hookCondition.Message).To(Equal(fmt.Sprintf("Waiting for hooks %s, %s", tc.hooks[1], tc.hooks[0])))
+
+			// Removing one of the hooks still blocks the deletion.
+			delete(machine.Annotations, tc.hooks[0])
+			_, err = r.reconcileDelete(ctx, testCluster, machine)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(machine.Finalizers).To(ContainElement(clusterv1.MachineFinalizer))
+			g.Expect(conditions.Get(machine, tc.expectedCondition).Message).To(Equal(fmt.Sprintf("Waiting for hooks %s", tc.hooks[1])))
+
+			// The deletion proceeds once all the hooks are removed.
+			delete(machine.Annotations, tc.hooks[1])
+			_, err = r.reconcileDelete(ctx, testCluster, machine)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(machine.Finalizers).To(BeEmpty())
+			g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{}))).To(BeTrue())
+			g.Expect(conditions.IsTrue(machine, clusterv1.PreDrainDeleteHookSucceededCondition)).To(BeTrue())
+			g.Expect(conditions.IsTrue(machine, clusterv1.PreTerminateDeleteHookSucceededCondition)).To(BeTrue())
+		})
+	}
+}
+
 func TestIsDeleteNodeAllowed(t *testing.T) {
 	deletionts := metav1.Now()
 
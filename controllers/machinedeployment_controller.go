@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -118,6 +119,7 @@ func (r *MachineDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, deployment) {
 		log.Info("Reconciliation is paused for this object")
+		r.recorder.Event(deployment, corev1.EventTypeNormal, EventReconciliationPaused, "Reconciliation is paused for this object")
 		return ctrl.Result{}, nil
 	}
 
@@ -195,11 +197,35 @@ func (r *MachineDeploymentReconciler) reconcile(ctx context.Context, cluster *cl
 		return ctrl.Result{}, r.sync(ctx, d, msList)
 	}
 
-	if d.Spec.Strategy.Type == clusterv1.RollingUpdateMachineDeploymentStrategyType {
-		return ctrl.Result{}, r.rolloutRolling(ctx, d, msList)
+	if _, ok := d.Annotations[clusterv1.RollbackToRevisionAnnotation]; ok {
+		return ctrl.Result{}, r.rollback(ctx, d, msList)
 	}
 
-	return ctrl.Result{}, errors.Errorf("unexpected deployment strategy type: %s", d.Spec.Strategy.Type)
+	// Scaling a deployment in the middle of a rollout distributes the new replicas proportionally
+	// across all active machine sets instead of handing them all to the new machine set.
+	scalingEvent, err := r.isScalingEvent(ctx, d, msList)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if scalingEvent {
+		return ctrl.Result{}, r.sync(ctx, d, msList)
+	}
+
+	switch d.Spec.Strategy.Type {
+	case clusterv1.RollingUpdateMachineDeploymentStrategyType:
+		err = r.rolloutRolling(ctx, d, msList)
+	case clusterv1.OnDeleteMachineDeploymentStrategyType:
+		err = r.rolloutOnDelete(ctx, d, msList)
+	default:
+		return ctrl.Result{}, errors.Errorf("unexpected deployment strategy type: %s", d.Spec.Strategy.Type)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Requeue to detect a rollout which does not make progress anymore, given that there would be no
+	// MachineSet changes triggering a new reconciliation.
+	return ctrl.Result{RequeueAfter: progressDeadlineRequeueAfter(d, time.Now())}, nil
 }
 
 // getMachineSetsForDeployment returns a list of MachineSets associated with a MachineDeployment.
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -25,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
@@ -244,11 +246,89 @@ var _ = Describe("MachineDeployment Reconciler", func() {
 		}, timeout).Should(BeEquivalentTo(3))
 
 		//
-		// Update a MachineDeployment, expect Reconcile to be called and a new MachineSet to appear.
+		// Update the labels of the MachineDeployment template, expect them to be propagated in place
+		// to the existing MachineSet and Machines without a rollout.
 		//
-		By("Setting a label on the MachineDeployment")
+		By("Setting a label on the MachineDeployment template")
 		modifyFunc = func(d *clusterv1.MachineDeployment) { d.Spec.Template.Labels["updated"] = "true" }
 		Expect(updateMachineDeployment(ctx, testEnv, deployment, modifyFunc)).To(Succeed())
+		Eventually(func() bool {
+			key := client.ObjectKey{Name: secondMachineSet.Name, Namespace: secondMachineSet.Namespace}
+			if err := testEnv.Get(ctx, key, &secondMachineSet); err != nil {
+				return false
+			}
+			return secondMachineSet.Spec.Template.Labels["updated"] == "true"
+		}, timeout).Should(BeTrue())
+		Eventually(func() bool {
+			if err := testEnv.List(ctx, machines, client.InNamespace(namespace.Name)); err != nil {
+				return false
+			}
+			updated := 0
+			for i := range machines.Items {
+				m := machines.Items[i]
+				if !metav1.IsControlledBy(&m, &secondMachineSet) || !m.DeletionTimestamp.IsZero() {
+					continue
+				}
+				if m.Labels["updated"] != "true" {
+					return false
+				}
+				updated++
+			}
+			return updated == 3
+		}, timeout).Should(BeTrue())
+		Consistently(func() int {
+			if err := testEnv.List(ctx, machineSets, msListOpts...); err != nil {
+				return -1
+			}
+			return len(machineSets.Items)
+		}, 5*time.Second).Should(BeEquivalentTo(1))
+
+		//
+		// Update the node drain timeout of the MachineDeployment template, expect it to be propagated in place
+		// to the existing MachineSet and Machines without a rollout.
+		//
+		By("Setting a node drain timeout on the MachineDeployment template")
+		nodeDrainTimeout := &metav1.Duration{Duration: 10 * time.Minute}
+		modifyFunc = func(d *clusterv1.MachineDeployment) { d.Spec.Template.Spec.NodeDrainTimeout = nodeDrainTimeout }
+		Expect(updateMachineDeployment(ctx, testEnv, deployment, modifyFunc)).To(Succeed())
+		Eventually(func() bool {
+			key := client.ObjectKey{Name: secondMachineSet.Name, Namespace: secondMachineSet.Namespace}
+			if err := testEnv.Get(ctx, key, &secondMachineSet); err != nil {
+				return false
+			}
+			return secondMachineSet.Spec.Template.Spec.NodeDrainTimeout != nil &&
+				secondMachineSet.Spec.Template.Spec.NodeDrainTimeout.Duration == nodeDrainTimeout.Duration
+		}, timeout).Should(BeTrue())
+		Eventually(func() bool {
+			if err := testEnv.List(ctx, machines, client.InNamespace(namespace.Name)); err != nil {
+				return false
+			}
+			updated := 0
+			for i := range machines.Items {
+				m := machines.Items[i]
+				if !metav1.IsControlledBy(&m, &secondMachineSet) || !m.DeletionTimestamp.IsZero() {
+					continue
+				}
+				if m.Spec.NodeDrainTimeout == nil || m.Spec.NodeDrainTimeout.Duration != nodeDrainTimeout.Duration {
+					return false
+				}
+				updated++
+			}
+			return updated == 3
+		}, timeout).Should(BeTrue())
+		Consistently(func() int {
+			if err := testEnv.List(ctx, machineSets, msListOpts...); err != nil {
+				return -1
+			}
+			return len(machineSets.Items)
+		}, 5*time.Second).Should(BeEquivalentTo(1))
+
+		//
+		// Update a MachineDeployment, expect Reconcile to be called and a new MachineSet to appear.
+		//
+		By("Setting a new version on the MachineDeployment template")
+		modifyFunc = func(d *clusterv1.MachineDeployment) { d.Spec.Template.Spec.Version = pointer.StringPtr("v1.10.4") }
+		Expect(updateMachineDeployment(ctx, testEnv, deployment, modifyFunc)).To(Succeed())
 		Eventually(func() int {
 			if err := testEnv.List(ctx, machineSets, msListOpts...); err != nil {
 				return -1
@@ -394,6 +474,158 @@ var _ = Describe("MachineDeployment Reconciler", func() {
 	})
 })
 
+func TestMachineDeploymentScaleSubresource(t *testing.T) {
+	g := NewWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "md-scale")
+	g.Expect(err).ToNot(HaveOccurred())
+	testCluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: ns.Name, Name: "test-cluster"}}
+	g.Expect(testEnv.Create(ctx, testCluster)).To(Succeed())
+	g.Expect(testEnv.CreateKubeconfigSecret(ctx, testCluster)).To(Succeed())
+	defer func() {
+		g.Expect(testEnv.Cleanup(ctx, testCluster, ns)).To(Succeed())
+	}()
+
+	// Create infrastructure template resource.
+	infraTmpl := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"kind":       "InfrastructureMachine",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+					"metadata":   map[string]interface{}{},
+				},
+			},
+		},
+	}
+	infraTmpl.SetKind("InfrastructureMachineTemplate")
+	infraTmpl.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1alpha4")
+	infraTmpl.SetName("md-scale-template")
+	infraTmpl.SetNamespace(ns.Name)
+	g.Expect(testEnv.Create(ctx, infraTmpl)).To(Succeed())
+
+	deployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md-scale",
+			Namespace: ns.Name,
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: testCluster.Name,
+			Replicas:    pointer.Int32Ptr(1),
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"md-scale": "true",
+				},
+			},
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels: map[string]string{
+						"md-scale": "true",
+					},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: testCluster.Name,
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+						Kind:       "InfrastructureMachineTemplate",
+						Name:       "md-scale-template",
+					},
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("data-secret-name"),
+					},
+				},
+			},
+		},
+	}
+	g.Expect(testEnv.Create(ctx, deployment)).To(Succeed())
+
+	mdResource := clusterv1.GroupVersion.WithResource("machinedeployments")
+	// ownedMachines returns the names of the Machines belonging to the MachineSets owned by the MachineDeployment,
+	// and the sum of the replicas of those MachineSets.
+	ownedMachines := func() ([]string, int32) {
+		machineSets := &clusterv1.MachineSetList{}
+		if err := testEnv.List(ctx, machineSets, client.InNamespace(ns.Name)); err != nil {
+			return nil, -1
+		}
+		machines := &clusterv1.MachineList{}
+		if err := testEnv.List(ctx, machines, client.InNamespace(ns.Name)); err != nil {
+			return nil, -1
+		}
+		names := []string{}
+		replicas := int32(0)
+		for i := range machineSets.Items {
+			ms := &machineSets.Items[i]
+			if !metav1.IsControlledBy(ms, deployment) {
+				continue
+			}
+			replicas += *ms.Spec.Replicas
+			for j := range machines.Items {
+				if metav1.IsControlledBy(&machines.Items[j], ms) {
+					names = append(names, machines.Items[j].Name)
+				}
+			}
+		}
+		return names, replicas
+	}
+	// selectedMachines returns the names of the Machines matching the selector of the scale subresource.
+	selectedMachines := func(selector string) []string {
+		labelSelector, err := labels.Parse(selector)
+		if err != nil {
+			return nil
+		}
+		machines := &clusterv1.MachineList{}
+		if err := testEnv.List(ctx, machines, client.InNamespace(ns.Name), client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+			return nil
+		}
+		names := []string{}
+		for _, m := range machines.Items {
+			names = append(names, m.Name)
+		}
+		return names
+	}
+	// scaleTo scales the MachineDeployment through the subresource and waits for the MachineSets to reconcile.
+	scaleTo := func(replicas int32) {
+		g.Expect(setScaleReplicas(mdResource, ns.Name, deployment.Name, replicas)).To(Succeed())
+		g.Eventually(func() int32 {
+			_, msReplicas := ownedMachines()
+			return msReplicas
+		}, timeout).Should(Equal(replicas))
+		g.Eventually(func() int {
+			names, _ := ownedMachines()
+			return len(names)
+		}, timeout).Should(BeEquivalentTo(replicas))
+		g.Eventually(func() (int32, error) {
+			scale, err := getScale(mdResource, ns.Name, deployment.Name)
+			if err != nil {
+				return -1, err
+			}
+			g.Expect(scale.Spec.Replicas).To(Equal(replicas))
+			return scale.Status.Replicas, nil
+		}, timeout).Should(Equal(replicas))
+
+		// The selector of the scale subresource selects exactly the Machines owned by the MachineDeployment.
+		scale, err := getScale(mdResource, ns.Name, deployment.Name)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(scale.Status.Selector).ToNot(BeEmpty())
+		names, _ := ownedMachines()
+		g.Expect(selectedMachines(scale.Status.Selector)).To(ConsistOf(names))
+	}
+
+	scaleTo(1)
+	scaleTo(3)
+	scaleTo(2)
+
+	// Scale to zero and delete the MachineDeployment and its MachineSets, so the controllers are done with them
+	// before the test environment is torn down.
+	scaleTo(0)
+	machineSets := &clusterv1.MachineSetList{}
+	g.Expect(testEnv.List(ctx, machineSets, client.InNamespace(ns.Name))).To(Succeed())
+	for i := range machineSets.Items {
+		g.Expect(testEnv.Delete(ctx, &machineSets.Items[i])).To(Succeed())
+	}
+	g.Expect(testEnv.Delete(ctx, deployment)).To(Succeed())
+}
+
 func TestMachineSetToDeployments(t *testing.T) {
 	g := NewWithT(t)
 
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rolloutOnDelete implements the logic for the OnDelete MachineDeploymentStrategyType.
+// Old machines are never deleted by the controller; the new machine set is scaled up
+// as the user deletes machines belonging to the old machine sets.
+func (r *MachineDeploymentReconciler) rolloutOnDelete(ctx context.Context, d *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) error {
+	newMS, oldMSs, err := r.getAllMachineSetsAndSyncRevision(ctx, d, msList, true)
+	if err != nil {
+		return err
+	}
+
+	// newMS can be nil in case there is already a MachineSet associated with this deployment,
+	// but there are only either changes in annotations or MinReadySeconds. Or in other words,
+	// this can be nil if there are changes, but no replacement of existing machines is needed.
+	if newMS == nil {
+		return nil
+	}
+
+	allMSs := append(oldMSs, newMS)
+
+	// Scale up, if we can.
+	if err := r.reconcileNewMachineSetOnDelete(ctx, allMSs, newMS, d); err != nil {
+		return err
+	}
+
+	if err := r.syncDeploymentStatus(allMSs, newMS, d); err != nil {
+		return err
+	}
+
+	// Scale down, if we can.
+	if err := r.reconcileOldMachineSetsOnDelete(ctx, oldMSs, allMSs, d); err != nil {
+		return err
+	}
+
+	if err := r.syncDeploymentStatus(allMSs, newMS, d); err != nil {
+		return err
+	}
+
+	if mdutil.DeploymentComplete(d, &d.Status) {
+		if err := r.cleanupDeployment(ctx, oldMSs, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileOldMachineSetsOnDelete handles reconciliation of old machine sets in the OnDelete MachineDeploymentStrategyType.
+// Old machine sets are prevented from creating new machines and their replicas are lowered to match the machines
+// which have not been deleted by the user.
+func (r *MachineDeploymentReconciler) reconcileOldMachineSetsOnDelete(ctx context.Context, oldMSs []*clusterv1.MachineSet, allMSs []*clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if deployment.Spec.Replicas == nil {
+		return errors.Errorf("spec replicas for deployment %v is nil, this is unexpected", deployment.Name)
+	}
+
+	totalReplicas := mdutil.GetReplicaCountForMachineSets(allMSs)
+	scaleDownAmount := totalReplicas - *deployment.Spec.Replicas
+
+	for _, oldMS := range oldMSs {
+		if oldMS.Spec.Replicas == nil || *oldMS.Spec.Replicas <= 0 {
+			log.V(4).Info("Machine set is fully scaled down", "machineset", oldMS.Name)
+			continue
+		}
+
+		if _, ok := oldMS.Annotations[clusterv1.DisableMachineCreate]; !ok {
+			log.V(4).Info("Disabling machine creation on old machine set", "machineset", oldMS.Name)
+			patchHelper, err := patch.NewHelper(oldMS, r.Client)
+			if err != nil {
+				return err
+			}
+			if oldMS.Annotations == nil {
+				oldMS.Annotations = map[string]string{}
+			}
+			oldMS.Annotations[clusterv1.DisableMachineCreate] = "true"
+			if err := patchHelper.Patch(ctx, oldMS); err != nil {
+				return err
+			}
+		}
+
+		selectorMap, err := metav1.LabelSelectorAsMap(&oldMS.Spec.Selector)
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert label selector of machine set %v to a map", oldMS.Name)
+		}
+
+		machines := &clusterv1.MachineList{}
+		if err := r.Client.List(ctx, machines, client.InNamespace(oldMS.Namespace), client.MatchingLabels(selectorMap)); err != nil {
+			return errors.Wrapf(err, "failed to list machines for machine set %v", oldMS.Name)
+		}
+
+		// Machines being deleted are no longer counted, which lets the new machine set replace them.
+		remainingReplicas := int32(len(machines.Items)) - mdutil.GetDeletingMachineCount(machines)
+		if remainingReplicas > *oldMS.Spec.Replicas {
+			remainingReplicas = *oldMS.Spec.Replicas
+		}
+		scaleDownAmount -= *oldMS.Spec.Replicas - remainingReplicas
+
+		if err := r.scaleMachineSet(ctx, oldMS, remainingReplicas, deployment); err != nil {
+			return err
+		}
+	}
+
+	// Any replicas still above the desired count come from the deployment being scaled down,
+	// starting from the oldest machine sets.
+	for _, oldMS := range oldMSs {
+		if scaleDownAmount <= 0 {
+			break
+		}
+		if oldMS.Spec.Replicas == nil || *oldMS.Spec.Replicas <= 0 {
+			continue
+		}
+
+		newReplicas := *oldMS.Spec.Replicas
+		if newReplicas >= scaleDownAmount {
+			newReplicas -= scaleDownAmount
+			scaleDownAmount = 0
+		} else {
+			scaleDownAmount -= newReplicas
+			newReplicas = 0
+		}
+
+		if err := r.scaleMachineSet(ctx, oldMS, newReplicas, deployment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileNewMachineSetOnDelete handles reconciliation of the new machine set in the OnDelete MachineDeploymentStrategyType.
+func (r *MachineDeploymentReconciler) reconcileNewMachineSetOnDelete(ctx context.Context, allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	// The new machine set might have been an old one before a rollback, make sure it can create machines again.
+	if _, ok := newMS.Annotations[clusterv1.DisableMachineCreate]; ok {
+		log.V(4).Info("Enabling machine creation on new machine set", "machineset", newMS.Name)
+		patchHelper, err := patch.NewHelper(newMS, r.Client)
+		if err != nil {
+			return err
+		}
+		delete(newMS.Annotations, clusterv1.DisableMachineCreate)
+		if err := patchHelper.Patch(ctx, newMS); err != nil {
+			return err
+		}
+	}
+
+	return r.reconcileNewMachineSet(ctx, allMSs, newMS, deployment)
+}
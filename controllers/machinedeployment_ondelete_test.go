@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMachineDeploymentRolloutOnDelete(t *testing.T) {
+	tests := []struct {
+		name                string
+		deploymentReplicas  int32
+		oldReplicas         int32
+		deletingMachines    int
+		expectedOldReplicas int32
+		expectedNewReplicas int32
+	}{
+		{
+			name:                "should not create machines while no old machine is deleted",
+			deploymentReplicas:  3,
+			oldReplicas:         3,
+			deletingMachines:    0,
+			expectedOldReplicas: 3,
+			expectedNewReplicas: 0,
+		},
+		{
+			name:                "should replace a deleted machine with a new one",
+			deploymentReplicas:  3,
+			oldReplicas:         3,
+			deletingMachines:    1,
+			expectedOldReplicas: 2,
+			expectedNewReplicas: 1,
+		},
+		{
+			name:                "should scale down old machine sets when the deployment is scaled down",
+			deploymentReplicas:  1,
+			oldReplicas:         3,
+			deletingMachines:    0,
+			expectedOldReplicas: 1,
+			expectedNewReplicas: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			deployment := &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "md",
+					Namespace: "default",
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Replicas: pointer.Int32Ptr(tt.deploymentReplicas),
+					Strategy: &clusterv1.MachineDeploymentStrategy{
+						Type: clusterv1.OnDeleteMachineDeploymentStrategyType,
+					},
+				},
+			}
+			oldMS := &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "old",
+					Namespace: "default",
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas: pointer.Int32Ptr(tt.oldReplicas),
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"machineset": "old"},
+					},
+				},
+			}
+			newMS := &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "new",
+					Namespace: "default",
+					// Left over from a time when this machine set was an old one.
+					Annotations: map[string]string{clusterv1.DisableMachineCreate: "true"},
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas: pointer.Int32Ptr(0),
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"machineset": "new"},
+					},
+				},
+			}
+
+			objs := []client.Object{deployment, oldMS, newMS}
+			for i := 0; i < int(tt.oldReplicas); i++ {
+				machine := &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("old-%d", i),
+						Namespace: "default",
+						Labels:    map[string]string{"machineset": "old"},
+					},
+				}
+				if i < tt.deletingMachines {
+					now := metav1.Now()
+					machine.DeletionTimestamp = &now
+					machine.Finalizers = []string{clusterv1.MachineFinalizer}
+				}
+				objs = append(objs, machine)
+			}
+
+			r := &MachineDeploymentReconciler{
+				Client:   fake.NewClientBuilder().WithObjects(objs...).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			allMSs := []*clusterv1.MachineSet{oldMS, newMS}
+			g.Expect(r.reconcileOldMachineSetsOnDelete(ctx, []*clusterv1.MachineSet{oldMS}, allMSs, deployment)).To(Succeed())
+			g.Expect(r.reconcileNewMachineSetOnDelete(ctx, allMSs, newMS, deployment)).To(Succeed())
+
+			gotOld := &clusterv1.MachineSet{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(oldMS), gotOld)).To(Succeed())
+			g.Expect(*gotOld.Spec.Replicas).To(Equal(tt.expectedOldReplicas))
+			g.Expect(gotOld.Annotations).To(HaveKey(clusterv1.DisableMachineCreate))
+
+			gotNew := &clusterv1.MachineSet{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(newMS), gotNew)).To(Succeed())
+			g.Expect(*gotNew.Spec.Replicas).To(Equal(tt.expectedNewReplicas))
+			g.Expect(gotNew.Annotations).NotTo(HaveKey(clusterv1.DisableMachineCreate))
+
+			// The controller must never delete machines itself.
+			machines := &clusterv1.MachineList{}
+			g.Expect(r.Client.List(ctx, machines)).To(Succeed())
+			g.Expect(machines.Items).To(HaveLen(int(tt.oldReplicas)))
+		})
+	}
+}
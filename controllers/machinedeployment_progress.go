@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// syncProgressingCondition tracks the progress of the rollout of the deployment by comparing its status with the
+// previous one, and marks the MachineDeploymentProgressingCondition as false once no progress has been observed
+// within the ProgressDeadlineSeconds. Progress is not estimated while the deployment is paused.
+func syncProgressingCondition(d *clusterv1.MachineDeployment, oldStatus *clusterv1.MachineDeploymentStatus, now time.Time) {
+	if d.Spec.ProgressDeadlineSeconds == nil {
+		d.Status.LastProgressTime = nil
+		conditions.Delete(d, clusterv1.MachineDeploymentProgressingCondition)
+		return
+	}
+
+	if d.Spec.Paused {
+		d.Status.LastProgressTime = nil
+		return
+	}
+
+	if mdutil.DeploymentComplete(d, &d.Status) {
+		d.Status.LastProgressTime = nil
+		conditions.MarkTrue(d, clusterv1.MachineDeploymentProgressingCondition)
+		return
+	}
+
+	// A new rollout, e.g. because the spec changed or the deployment was resumed, restarts the clock.
+	if d.Status.LastProgressTime == nil || oldStatus.ObservedGeneration < d.Generation ||
+		d.Status.UpdatedReplicas > oldStatus.UpdatedReplicas || d.Status.AvailableReplicas > oldStatus.AvailableReplicas {
+		lastProgressTime := metav1.NewTime(now)
+		d.Status.LastProgressTime = &lastProgressTime
+	}
+
+	if remainingProgressDeadline(d, now) <= 0 {
+		conditions.MarkFalse(d, clusterv1.MachineDeploymentProgressingCondition, clusterv1.ProgressDeadlineExceededReason, clusterv1.ConditionSeverityWarning,
+			"MachineDeployment did not make progress for more than %ds", *d.Spec.ProgressDeadlineSeconds)
+		return
+	}
+	conditions.MarkTrue(d, clusterv1.MachineDeploymentProgressingCondition)
+}
+
+// progressDeadlineRequeueAfter returns the time after which the deployment must be reconciled to check if the
+// ProgressDeadlineSeconds of a rollout in progress is exceeded, or zero if there is nothing to check.
+func progressDeadlineRequeueAfter(d *clusterv1.MachineDeployment, now time.Time) time.Duration {
+	if d.Status.LastProgressTime == nil || conditions.IsFalse(d, clusterv1.MachineDeploymentProgressingCondition) {
+		return 0
+	}
+	if remaining := remainingProgressDeadline(d, now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+func remainingProgressDeadline(d *clusterv1.MachineDeployment, now time.Time) time.Duration {
+	deadline := time.Duration(*d.Spec.ProgressDeadlineSeconds) * time.Second
+	return d.Status.LastProgressTime.Add(deadline).Sub(now)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// rolloutStep is the status observed while rolling out a deployment at a given time.
+type rolloutStep struct {
+	after                  time.Duration
+	updatedReplicas        int32
+	availableReplicas      int32
+	expectedStatus         corev1.ConditionStatus
+	expectedProgressOffset *time.Duration
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func newProgressTestDeployment() *clusterv1.MachineDeployment {
+	return &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "md",
+			Namespace:  "default",
+			Generation: 2,
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas:                pointer.Int32Ptr(3),
+			ProgressDeadlineSeconds: pointer.Int32Ptr(600),
+		},
+		Status: clusterv1.MachineDeploymentStatus{
+			// The previous rollout completed, and the template was just changed.
+			ObservedGeneration: 1,
+			Replicas:           3,
+			UpdatedReplicas:    3,
+			AvailableReplicas:  3,
+		},
+	}
+}
+
+func runRolloutSteps(t *testing.T, d *clusterv1.MachineDeployment, steps []rolloutStep) {
+	start := time.Now()
+	for i, step := range steps {
+		g := NewWithT(t)
+
+		oldStatus := d.Status
+		d.Status.ObservedGeneration = d.Generation
+		d.Status.UpdatedReplicas = step.updatedReplicas
+		d.Status.AvailableReplicas = step.availableReplicas
+		syncProgressingCondition(d, &oldStatus, start.Add(step.after))
+
+		condition := conditions.Get(d, clusterv1.MachineDeploymentProgressingCondition)
+		g.Expect(condition).ToNot(BeNil(), "step %d", i)
+		g.Expect(condition.Status).To(Equal(step.expectedStatus), "step %d", i)
+		if step.expectedStatus == corev1.ConditionFalse {
+			g.Expect(condition.Reason).To(Equal(clusterv1.ProgressDeadlineExceededReason), "step %d", i)
+			g.Expect(condition.Severity).To(Equal(clusterv1.ConditionSeverityWarning), "step %d", i)
+		}
+
+		if step.expectedProgressOffset == nil {
+			g.Expect(d.Status.LastProgressTime).To(BeNil(), "step %d", i)
+			continue
+		}
+		g.Expect(d.Status.LastProgressTime).ToNot(BeNil(), "step %d", i)
+		g.Expect(d.Status.LastProgressTime.Time).To(BeTemporally("~", start.Add(*step.expectedProgressOffset), time.Second), "step %d", i)
+	}
+}
+
+func TestSyncProgressingConditionStalledRollout(t *testing.T) {
+	d := newProgressTestDeployment()
+
+	// A single new machine gets created, but it never becomes available.
+	runRolloutSteps(t, d, []rolloutStep{
+		{after: 0, updatedReplicas: 1, availableReplicas: 2, expectedStatus: corev1.ConditionTrue, expectedProgressOffset: durationPtr(0)},
+		{after: 5 * time.Minute, updatedReplicas: 1, availableReplicas: 2, expectedStatus: corev1.ConditionTrue, expectedProgressOffset: durationPtr(0)},
+		{after: 9 * time.Minute, updatedReplicas: 1, availableReplicas: 2, expectedStatus: corev1.ConditionTrue, expectedProgressOffset: durationPtr(0)},
+		{after: 11 * time.Minute, updatedReplicas: 1, availableReplicas: 2, expectedStatus: corev1.ConditionFalse, expectedProgressOffset: durationPtr(0)},
+		{after: 20 * time.Minute, updatedReplicas: 1, availableReplicas: 2, expectedStatus: corev1.ConditionFalse, expectedProgressOffset: durationPtr(0)},
+	})
+
+	g := NewWithT(t)
+	g.Expect(conditions.Get(d, clusterv1.MachineDeploymentProgressingCondition).Message).To(ContainSubstring("600s"))
+	// There is no need to requeue once the deadline is exceeded.
+	g.Expect(progressDeadlineRequeueAfter(d, time.Now())).To(BeZero())
+}
+
+func TestSyncProgressingConditionSteadyRollout(t *testing.T) {
+	d := newProgressTestDeployment()
+
+	// Every step takes longer than half of the deadline, but the rollout takes much longer than the deadline.
+	runRolloutSteps(t, d, []rolloutStep{
+		{after: 0, updatedReplicas: 1, availableReplicas: 2, expectedStatus: corev1.ConditionTrue, expectedProgressOffset: durationPtr(0)},
+		{after: 8 * time.Minute, updatedReplicas: 1, availableReplicas: 3, expectedStatus: corev1.ConditionTrue, expectedProgressOffset: durationPtr(8 * time.Minute)},
+		{after: 16 * time.Minute, updatedReplicas: 2, availableReplicas: 2, expectedStatus: corev1.ConditionTrue, expectedProgressOffset: durationPtr(16 * time.Minute)},
+		{after: 24 * time.Minute, updatedReplicas: 2, availableReplicas: 3, expectedStatus: corev1.ConditionTrue, expectedProgressOffset: durationPtr(24 * time.Minute)},
+		{after: 32 * time.Minute, updatedReplicas: 3, availableReplicas: 2, expectedStatus: corev1.ConditionTrue, expectedProgressOffset: durationPtr(32 * time.Minute)},
+		// The rollout is completed.
+		{after: 40 * time.Minute, updatedReplicas: 3, availableReplicas: 3, expectedStatus: corev1.ConditionTrue},
+		// The deadline is not enforced once the rollout is completed.
+		{after: 60 * time.Minute, updatedReplicas: 3, availableReplicas: 3, expectedStatus: corev1.ConditionTrue},
+	})
+
+	g := NewWithT(t)
+	g.Expect(progressDeadlineRequeueAfter(d, time.Now())).To(BeZero())
+}
+
+func TestSyncProgressingConditionNewRollout(t *testing.T) {
+	g := NewWithT(t)
+
+	d := newProgressTestDeployment()
+	d.Status.ObservedGeneration = 2
+	d.Status.UpdatedReplicas = 1
+	d.Status.LastProgressTime = &metav1.Time{Time: time.Now().Add(-20 * time.Minute)}
+	conditions.MarkFalse(d, clusterv1.MachineDeploymentProgressingCondition, clusterv1.ProgressDeadlineExceededReason, clusterv1.ConditionSeverityWarning, "")
+
+	// The template is changed again while the rollout is stalled.
+	d.Generation = 3
+	oldStatus := d.Status
+	d.Status.ObservedGeneration = 3
+	d.Status.UpdatedReplicas = 0
+	now := time.Now()
+	syncProgressingCondition(d, &oldStatus, now)
+
+	g.Expect(conditions.IsTrue(d, clusterv1.MachineDeploymentProgressingCondition)).To(BeTrue())
+	g.Expect(d.Status.LastProgressTime.Time).To(BeTemporally("==", now))
+	g.Expect(progressDeadlineRequeueAfter(d, now)).To(Equal(10 * time.Minute))
+}
+
+func TestSyncProgressingConditionPaused(t *testing.T) {
+	g := NewWithT(t)
+
+	d := newProgressTestDeployment()
+	d.Spec.Paused = true
+	d.Status.UpdatedReplicas = 1
+	d.Status.LastProgressTime = &metav1.Time{Time: time.Now().Add(-20 * time.Minute)}
+
+	oldStatus := d.Status
+	syncProgressingCondition(d, &oldStatus, time.Now())
+
+	g.Expect(d.Status.LastProgressTime).To(BeNil())
+	g.Expect(conditions.Has(d, clusterv1.MachineDeploymentProgressingCondition)).To(BeFalse())
+	g.Expect(progressDeadlineRequeueAfter(d, time.Now())).To(BeZero())
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// rollback restores the machine template of the machine set with the revision requested through the
+// RollbackToRevisionAnnotation into the machine deployment; the restored template is then rolled out
+// by the deployment strategy in the following reconciliations, like any other template change.
+// The annotation is always removed, so a rollback to a revision which doesn't exist is not retried.
+func (r *MachineDeploymentReconciler) rollback(ctx context.Context, d *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	value := d.Annotations[clusterv1.RollbackToRevisionAnnotation]
+	delete(d.Annotations, clusterv1.RollbackToRevisionAnnotation)
+
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || revision <= 0 {
+		r.recorder.Eventf(d, corev1.EventTypeWarning, "RollbackRevisionInvalid", "Unable to roll back to revision %q: it must be a positive integer", value)
+		return nil
+	}
+
+	ms := machineSetForRevision(msList, revision)
+	if ms == nil {
+		r.recorder.Eventf(d, corev1.EventTypeWarning, "RollbackRevisionNotFound", "Unable to roll back to revision %d: no MachineSet found for this revision", revision)
+		return nil
+	}
+
+	if mdutil.EqualMachineTemplate(&d.Spec.Template, &ms.Spec.Template) {
+		r.recorder.Eventf(d, corev1.EventTypeNormal, "RollbackTemplateUnchanged", "Revision %d has the same template as the current one, skipping rollback", revision)
+		return nil
+	}
+
+	log.Info("Rolling back MachineDeployment", "revision", revision, "machineset", ms.Name)
+	template := ms.Spec.Template.DeepCopy()
+	delete(template.Labels, mdutil.DefaultMachineDeploymentUniqueLabelKey)
+	d.Spec.Template = *template
+	r.recorder.Eventf(d, corev1.EventTypeNormal, "RollbackDone", "Rolled back to revision %d using MachineSet %q", revision, ms.Name)
+	return nil
+}
+
+// machineSetForRevision returns the machine set which is serving, or has served, the given revision.
+func machineSetForRevision(msList []*clusterv1.MachineSet, revision int64) *clusterv1.MachineSet {
+	target := strconv.FormatInt(revision, 10)
+	for _, ms := range msList {
+		if ms.Annotations[clusterv1.RevisionAnnotation] == target {
+			return ms
+		}
+		for _, old := range strings.Split(ms.Annotations[clusterv1.RevisionHistoryAnnotation], ",") {
+			if old == target {
+				return ms
+			}
+		}
+	}
+	return nil
+}
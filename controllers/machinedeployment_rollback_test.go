@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+)
+
+func TestMachineDeploymentRollback(t *testing.T) {
+	newTemplate := func(version string) clusterv1.MachineTemplateSpec {
+		return clusterv1.MachineTemplateSpec{
+			ObjectMeta: clusterv1.ObjectMeta{
+				Labels: map[string]string{"foo": "bar"},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: "test-cluster",
+				Version:     pointer.StringPtr(version),
+			},
+		}
+	}
+	newMachineSet := func(name, version, revision, revisionHistory string) *clusterv1.MachineSet {
+		template := newTemplate(version)
+		template.Labels[mdutil.DefaultMachineDeploymentUniqueLabelKey] = name
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					clusterv1.RevisionAnnotation:        revision,
+					clusterv1.RevisionHistoryAnnotation: revisionHistory,
+				},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Template: template,
+			},
+		}
+	}
+	// ms-1 served revisions 1 and 3, ms-2 served revision 2, ms-4 is the current machine set at revision 4.
+	msList := []*clusterv1.MachineSet{
+		newMachineSet("ms-1", "v1.19.1", "3", "1"),
+		newMachineSet("ms-2", "v1.19.2", "2", ""),
+		newMachineSet("ms-4", "v1.19.4", "4", ""),
+	}
+
+	tests := []struct {
+		name            string
+		revision        string
+		expectedVersion string
+		expectedEvent   string
+	}{
+		{
+			name:            "should restore the template of the machine set with the given revision",
+			revision:        "2",
+			expectedVersion: "v1.19.2",
+			expectedEvent:   "RollbackDone",
+		},
+		{
+			name:            "should restore the template of the machine set which served the given revision in the past",
+			revision:        "1",
+			expectedVersion: "v1.19.1",
+			expectedEvent:   "RollbackDone",
+		},
+		{
+			name:            "should not change the template when rolling back to the current revision",
+			revision:        "4",
+			expectedVersion: "v1.19.4",
+			expectedEvent:   "RollbackTemplateUnchanged",
+		},
+		{
+			name:            "should not change the template when the revision does not exist",
+			revision:        "5",
+			expectedVersion: "v1.19.4",
+			expectedEvent:   "RollbackRevisionNotFound",
+		},
+		{
+			name:            "should not change the template when the revision is invalid",
+			revision:        "latest",
+			expectedVersion: "v1.19.4",
+			expectedEvent:   "RollbackRevisionInvalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			deployment := &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "md",
+					Namespace:   "default",
+					Annotations: map[string]string{clusterv1.RollbackToRevisionAnnotation: tt.revision},
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Template: newTemplate("v1.19.4"),
+				},
+			}
+
+			recorder := record.NewFakeRecorder(32)
+			r := &MachineDeploymentReconciler{
+				recorder: recorder,
+			}
+			g.Expect(r.rollback(ctx, deployment, msList)).To(Succeed())
+
+			g.Expect(deployment.Annotations).NotTo(HaveKey(clusterv1.RollbackToRevisionAnnotation))
+			g.Expect(*deployment.Spec.Template.Spec.Version).To(Equal(tt.expectedVersion))
+			g.Expect(deployment.Spec.Template.Labels).To(Equal(map[string]string{"foo": "bar"}))
+			g.Expect(recorder.Events).To(Receive(ContainSubstring(tt.expectedEvent)))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMachineDeploymentReconcileOldMachineSets(t *testing.T) {
+	maxUnavailable := intstr.FromInt(0)
+	maxSurge := intstr.FromInt(1)
+	deployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "md", Namespace: "default"},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas:        pointer.Int32Ptr(3),
+			MinReadySeconds: pointer.Int32Ptr(60),
+			Strategy: &clusterv1.MachineDeploymentStrategy{
+				Type: clusterv1.RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+					MaxUnavailable: &maxUnavailable,
+					MaxSurge:       &maxSurge,
+				},
+			},
+		},
+	}
+
+	newMachineSet := func(name string, replicas, readyReplicas, availableReplicas int32, creationTimestamp time.Time) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(creationTimestamp),
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas:        pointer.Int32Ptr(replicas),
+				MinReadySeconds: 60,
+			},
+			Status: clusterv1.MachineSetStatus{
+				Replicas:          replicas,
+				ReadyReplicas:     readyReplicas,
+				AvailableReplicas: availableReplicas,
+			},
+		}
+	}
+
+	tests := []struct {
+		name                   string
+		newMSAvailableReplicas int32
+		expectedOldReplicas    int32
+	}{
+		{
+			name:                   "old machine set is not scaled down while the new machines are not ready for min ready seconds",
+			newMSAvailableReplicas: 0,
+			expectedOldReplicas:    3,
+		},
+		{
+			name:                   "old machine set is scaled down once the new machines are available",
+			newMSAvailableReplicas: 1,
+			expectedOldReplicas:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			now := time.Now()
+			oldMS := newMachineSet("old", 3, 3, 3, now.Add(-time.Hour))
+			// The new machine is ready, but it is available only after min ready seconds.
+			newMS := newMachineSet("new", 1, 1, tt.newMSAvailableReplicas, now)
+
+			r := &MachineDeploymentReconciler{
+				Client:   fake.NewClientBuilder().WithObjects(oldMS, newMS).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			allMSs := []*clusterv1.MachineSet{oldMS, newMS}
+			g.Expect(r.reconcileOldMachineSets(ctx, allMSs, []*clusterv1.MachineSet{oldMS}, newMS, deployment)).To(Succeed())
+
+			gotOld := &clusterv1.MachineSet{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(oldMS), gotOld)).To(Succeed())
+			g.Expect(*gotOld.Spec.Replicas).To(Equal(tt.expectedOldReplicas))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -58,6 +59,33 @@ func (r *MachineDeploymentReconciler) sync(ctx context.Context, d *clusterv1.Mac
 	return r.syncDeploymentStatus(allMSs, newMS, d)
 }
 
+// isScalingEvent checks whether the provided deployment has been updated with a scaling event
+// by looking at the desired-replicas annotation in the active machine sets of the deployment.
+//
+// msList should come from getMachineSetsForDeployment(d).
+func (r *MachineDeploymentReconciler) isScalingEvent(ctx context.Context, d *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) (bool, error) {
+	if d.Spec.Replicas == nil {
+		return false, errors.Errorf("spec replicas for deployment %v is nil, this is unexpected", d.Name)
+	}
+
+	newMS, oldMSs, err := r.getAllMachineSetsAndSyncRevision(ctx, d, msList, false)
+	if err != nil {
+		return false, err
+	}
+
+	allMSs := append(oldMSs, newMS)
+	for _, ms := range mdutil.FilterActiveMachineSets(allMSs) {
+		desired, ok := mdutil.GetDesiredReplicasAnnotation(ms, ctrl.LoggerFrom(ctx))
+		if !ok {
+			continue
+		}
+		if desired != *(d.Spec.Replicas) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // getAllMachineSetsAndSyncRevision returns all the machine sets for the provided deployment (new and all old), with new MS's and deployment's revision updated.
 //
 // msList should come from getMachineSetsForDeployment(d).
@@ -67,6 +95,7 @@ func (r *MachineDeploymentReconciler) sync(ctx context.Context, d *clusterv1.Mac
 // 2. Get new MS this deployment targets (whose machine template matches deployment's), and update new MS's revision number to (maxOldV + 1),
 //    only if its revision number is smaller than (maxOldV + 1). If this step failed, we'll update it in the next deployment sync loop.
 // 3. Copy new MS's revision number to deployment (update deployment's revision). If this step failed, we'll update it in the next deployment sync loop.
+// 4. Propagate the node drain and volume detach timeouts to all old MSes, so they apply to the machines deleted by the rollout.
 //
 // Note that currently the deployment controller is using caches to avoid querying the server for reads.
 // This may lead to stale reads of machine sets, thus incorrect deployment status.
@@ -79,9 +108,31 @@ func (r *MachineDeploymentReconciler) getAllMachineSetsAndSyncRevision(ctx conte
 		return nil, nil, err
 	}
 
+	if err := r.syncOldMachineSetsTimeouts(ctx, d, allOldMSs); err != nil {
+		return nil, nil, err
+	}
+
 	return newMS, allOldMSs, nil
 }
 
+// syncOldMachineSetsTimeouts propagates the node drain and volume detach timeouts of the deployment to the old machine sets,
+// given that their machines are the ones being deleted during a rollout.
+func (r *MachineDeploymentReconciler) syncOldMachineSetsTimeouts(ctx context.Context, d *clusterv1.MachineDeployment, oldMSs []*clusterv1.MachineSet) error {
+	for _, ms := range oldMSs {
+		patchHelper, err := patch.NewHelper(ms, r.Client)
+		if err != nil {
+			return err
+		}
+		if !mdutil.SetMachineSetTemplateTimeouts(d, ms) {
+			continue
+		}
+		if err := patchHelper.Patch(ctx, ms); err != nil {
+			return errors.Wrapf(err, "failed to update the timeouts of MachineSet %q", ms.Name)
+		}
+	}
+	return nil
+}
+
 // Returns a machine set that matches the intent of the given deployment. Returns nil if the new machine set doesn't exist yet.
 // 1. Get existing new MS (the MS that the given deployment targets, whose machine template is the same as deployment's).
 // 2. If there's existing new MS, update its revision number if it's smaller than (maxOldRevision + 1), where maxOldRevision is the max revision number among all old MSes.
@@ -112,9 +163,14 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 		// Set existing new machine set's annotation
 		annotationsUpdated := mdutil.SetNewMachineSetAnnotations(d, msCopy, newRevision, true, log)
 
+		// Propagate in place changes to the labels, annotations and timeouts of the machine template,
+		// which do not require a rollout; the machine set takes care of updating its machines.
+		templateMetadataUpdated := mdutil.SetMachineSetTemplateMetadata(d, msCopy)
+		templateTimeoutsUpdated := mdutil.SetMachineSetTemplateTimeouts(d, msCopy)
+
 		minReadySecondsNeedsUpdate := msCopy.Spec.MinReadySeconds != *d.Spec.MinReadySeconds
-		deletePolicyNeedsUpdate := d.Spec.Strategy.RollingUpdate.DeletePolicy != nil && msCopy.Spec.DeletePolicy != *d.Spec.Strategy.RollingUpdate.DeletePolicy
-		if annotationsUpdated || minReadySecondsNeedsUpdate || deletePolicyNeedsUpdate {
+		deletePolicyNeedsUpdate := d.Spec.Strategy.RollingUpdate != nil && d.Spec.Strategy.RollingUpdate.DeletePolicy != nil && msCopy.Spec.DeletePolicy != *d.Spec.Strategy.RollingUpdate.DeletePolicy
+		if annotationsUpdated || templateMetadataUpdated || templateTimeoutsUpdated || minReadySecondsNeedsUpdate || deletePolicyNeedsUpdate {
 			msCopy.Spec.MinReadySeconds = *d.Spec.MinReadySeconds
 
 			if deletePolicyNeedsUpdate {
@@ -168,7 +224,7 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 		},
 	}
 
-	if d.Spec.Strategy.RollingUpdate.DeletePolicy != nil {
+	if d.Spec.Strategy.RollingUpdate != nil && d.Spec.Strategy.RollingUpdate.DeletePolicy != nil {
 		newMS.Spec.DeletePolicy = *d.Spec.Strategy.RollingUpdate.DeletePolicy
 	}
 
@@ -352,7 +408,9 @@ func (r *MachineDeploymentReconciler) scale(ctx context.Context, deployment *clu
 
 // syncDeploymentStatus checks if the status is up-to-date and sync it if necessary
 func (r *MachineDeploymentReconciler) syncDeploymentStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet, d *clusterv1.MachineDeployment) error {
+	oldStatus := d.Status
 	d.Status = calculateStatus(allMSs, newMS, d)
+	syncProgressingCondition(d, &oldStatus, time.Now())
 	return nil
 }
 
@@ -380,6 +438,8 @@ func calculateStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet
 		ReadyReplicas:       mdutil.GetReadyReplicaCountForMachineSets(allMSs),
 		AvailableReplicas:   availableReplicas,
 		UnavailableReplicas: unavailableReplicas,
+		LastProgressTime:    deployment.Status.LastProgressTime,
+		Conditions:          deployment.Status.Conditions,
 	}
 
 	if *deployment.Spec.Replicas == status.ReadyReplicas {
@@ -17,14 +17,21 @@ limitations under the License.
 package controllers
 
 import (
+	"strconv"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestMachineDeploymentSyncStatus(t *testing.T) {
@@ -220,3 +227,340 @@ func TestMachineDeploymentSyncStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineDeploymentScale(t *testing.T) {
+	newMachineSet := func(name string, replicas int32, created metav1.Time) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: created,
+				Annotations: map[string]string{
+					clusterv1.DesiredReplicasAnnotation: "10",
+					clusterv1.MaxReplicasAnnotation:     "13",
+				},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32Ptr(replicas),
+			},
+		}
+	}
+	newDeployment := func(replicas int32) *clusterv1.MachineDeployment {
+		maxSurge := intstr.FromInt(3)
+		maxUnavailable := intstr.FromInt(0)
+		return &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "md",
+				Namespace: "default",
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				Replicas: pointer.Int32Ptr(replicas),
+				Paused:   true,
+				Strategy: &clusterv1.MachineDeploymentStrategy{
+					Type: clusterv1.RollingUpdateMachineDeploymentStrategyType,
+					RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+						MaxSurge:       &maxSurge,
+						MaxUnavailable: &maxUnavailable,
+					},
+				},
+			},
+			Status: clusterv1.MachineDeploymentStatus{
+				Replicas: 13,
+			},
+		}
+	}
+
+	older := metav1.NewTime(time.Now().Add(-time.Hour))
+	newer := metav1.NewTime(time.Now())
+
+	tests := []struct {
+		name                string
+		deployment          *clusterv1.MachineDeployment
+		expectedOldReplicas int32
+		expectedNewReplicas int32
+	}{
+		{
+			name:                "scale up a paused rollout",
+			deployment:          newDeployment(20),
+			expectedOldReplicas: 12,
+			expectedNewReplicas: 11,
+		},
+		{
+			name:                "scale down a paused rollout",
+			deployment:          newDeployment(5),
+			expectedOldReplicas: 4,
+			expectedNewReplicas: 4,
+		},
+		{
+			name:                "scale a paused rollout to zero",
+			deployment:          newDeployment(0),
+			expectedOldReplicas: 0,
+			expectedNewReplicas: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			oldMS := newMachineSet("old", 7, older)
+			newMS := newMachineSet("new", 6, newer)
+
+			r := &MachineDeploymentReconciler{
+				Client:   fake.NewClientBuilder().WithObjects(oldMS, newMS).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			g.Expect(r.scale(ctx, tt.deployment, newMS, []*clusterv1.MachineSet{oldMS})).To(Succeed())
+
+			gotOld := &clusterv1.MachineSet{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(oldMS), gotOld)).To(Succeed())
+			g.Expect(*gotOld.Spec.Replicas).To(Equal(tt.expectedOldReplicas))
+
+			gotNew := &clusterv1.MachineSet{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(newMS), gotNew)).To(Succeed())
+			g.Expect(*gotNew.Spec.Replicas).To(Equal(tt.expectedNewReplicas))
+
+			// Every MachineSet that was scaled must record the new desired size of the deployment.
+			desired := strconv.Itoa(int(*tt.deployment.Spec.Replicas))
+			if tt.expectedOldReplicas > 0 {
+				g.Expect(gotOld.Annotations).To(HaveKeyWithValue(clusterv1.DesiredReplicasAnnotation, desired))
+			}
+			if tt.expectedNewReplicas > 0 {
+				g.Expect(gotNew.Annotations).To(HaveKeyWithValue(clusterv1.DesiredReplicasAnnotation, desired))
+			}
+		})
+	}
+}
+
+func TestMachineDeploymentIsScalingEvent(t *testing.T) {
+	newMachineSet := func(replicas int32, desired string) *clusterv1.MachineSet {
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ms",
+				Namespace: "default",
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32Ptr(replicas),
+			},
+		}
+		if desired != "" {
+			ms.Annotations = map[string]string{clusterv1.DesiredReplicasAnnotation: desired}
+		}
+		return ms
+	}
+
+	tests := []struct {
+		name       string
+		machineSet *clusterv1.MachineSet
+		expected   bool
+	}{
+		{
+			name:       "desired replicas match the deployment",
+			machineSet: newMachineSet(3, "3"),
+			expected:   false,
+		},
+		{
+			name:       "desired replicas differ from the deployment",
+			machineSet: newMachineSet(3, "5"),
+			expected:   true,
+		},
+		{
+			name:       "inactive machine set is ignored",
+			machineSet: newMachineSet(0, "5"),
+			expected:   false,
+		},
+		{
+			name:       "missing annotation is ignored",
+			machineSet: newMachineSet(3, ""),
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			deployment := &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "md",
+					Namespace: "default",
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Replicas: pointer.Int32Ptr(3),
+					// A different template makes the machine set an old one, as it would be during a rollout.
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Version: pointer.StringPtr("v1.20.0"),
+						},
+					},
+				},
+			}
+
+			r := &MachineDeploymentReconciler{
+				Client:   fake.NewClientBuilder().WithObjects(deployment, tt.machineSet).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			got, err := r.isScalingEvent(ctx, deployment, []*clusterv1.MachineSet{tt.machineSet})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestMachineDeploymentSyncOldMachineSetsTimeouts(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	deployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md",
+			Namespace: "default",
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Version:                 pointer.StringPtr("v1.20.0"),
+					NodeDrainTimeout:        &metav1.Duration{Duration: 10 * time.Minute},
+					NodeVolumeDetachTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		},
+	}
+	// An old machine set, which is still running machines with a previous version during the rollout.
+	oldMS := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "old-ms",
+			Namespace: "default",
+		},
+		Spec: clusterv1.MachineSetSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Version:          pointer.StringPtr("v1.19.0"),
+					NodeDrainTimeout: &metav1.Duration{Duration: time.Minute},
+				},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithObjects(deployment, oldMS).Build()
+	r := &MachineDeploymentReconciler{
+		Client:   c,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	g.Expect(r.syncOldMachineSetsTimeouts(ctx, deployment, []*clusterv1.MachineSet{oldMS})).To(Succeed())
+
+	updatedMS := &clusterv1.MachineSet{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(oldMS), updatedMS)).To(Succeed())
+	g.Expect(updatedMS.Spec.Template.Spec.NodeDrainTimeout).To(Equal(deployment.Spec.Template.Spec.NodeDrainTimeout))
+	g.Expect(updatedMS.Spec.Template.Spec.NodeVolumeDetachTimeout).To(Equal(deployment.Spec.Template.Spec.NodeVolumeDetachTimeout))
+	// The rest of the template is not changed, so the machine set is still an old one.
+	g.Expect(updatedMS.Spec.Template.Spec.Version).To(Equal(pointer.StringPtr("v1.19.0")))
+}
+
+func TestMachineDeploymentCleanupDeployment(t *testing.T) {
+	now := metav1.Now()
+	newMachineSet := func(name string, replicas int32, created time.Duration) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(now.Add(created)),
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32Ptr(replicas),
+			},
+			Status: clusterv1.MachineSetStatus{
+				Replicas: replicas,
+			},
+		}
+	}
+
+	tests := []struct {
+		name                 string
+		revisionHistoryLimit *int32
+		oldReplicas          []int32
+		expectedMachineSets  []string
+	}{
+		{
+			name:                 "should keep the latest old machine set after multiple rollouts",
+			revisionHistoryLimit: pointer.Int32Ptr(1),
+			oldReplicas:          []int32{0, 0, 0, 0},
+			expectedMachineSets:  []string{"ms-4", "ms-new"},
+		},
+		{
+			name:                 "should delete all the old machine sets if the limit is zero",
+			revisionHistoryLimit: pointer.Int32Ptr(0),
+			oldReplicas:          []int32{0, 0, 0, 0},
+			expectedMachineSets:  []string{"ms-new"},
+		},
+		{
+			name:                 "should not delete old machine sets within the limit",
+			revisionHistoryLimit: pointer.Int32Ptr(10),
+			oldReplicas:          []int32{0, 0, 0, 0},
+			expectedMachineSets:  []string{"ms-1", "ms-2", "ms-3", "ms-4", "ms-new"},
+		},
+		{
+			name:                 "should not delete old machine sets which are not scaled down",
+			revisionHistoryLimit: pointer.Int32Ptr(1),
+			oldReplicas:          []int32{1, 0, 0, 0},
+			expectedMachineSets:  []string{"ms-1", "ms-4", "ms-new"},
+		},
+		{
+			name:                 "should not delete old machine sets if the limit is not set",
+			revisionHistoryLimit: nil,
+			oldReplicas:          []int32{0, 0, 0, 0},
+			expectedMachineSets:  []string{"ms-1", "ms-2", "ms-3", "ms-4", "ms-new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			deployment := &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "md",
+					Namespace: "default",
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					RevisionHistoryLimit: tt.revisionHistoryLimit,
+				},
+			}
+
+			// Each rollout leaves an old machine set behind; the new machine set is the active one.
+			newMS := newMachineSet("ms-new", 3, time.Duration(len(tt.oldReplicas))*time.Minute)
+			objs := []client.Object{deployment, newMS}
+			oldMSs := make([]*clusterv1.MachineSet, 0, len(tt.oldReplicas))
+			for i, replicas := range tt.oldReplicas {
+				ms := newMachineSet("ms-"+strconv.Itoa(i+1), replicas, time.Duration(i)*time.Minute-time.Hour)
+				oldMSs = append(oldMSs, ms)
+				objs = append(objs, ms)
+			}
+
+			r := &MachineDeploymentReconciler{
+				Client:   fake.NewClientBuilder().WithObjects(objs...).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+			g.Expect(r.cleanupDeployment(ctx, oldMSs, deployment)).To(Succeed())
+
+			machineSets := &clusterv1.MachineSetList{}
+			g.Expect(r.Client.List(ctx, machineSets)).To(Succeed())
+			names := make([]string, 0, len(machineSets.Items))
+			for _, ms := range machineSets.Items {
+				names = append(names, ms.Name)
+			}
+			g.Expect(names).To(ConsistOf(tt.expectedMachineSets))
+		})
+	}
+}
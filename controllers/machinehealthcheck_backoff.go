@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// applyRemediationBackoff filters out the unhealthy targets whose remediation must be delayed because
+// machines with the same owner were recently remediated, and records an attempt for every new remediation
+// which is allowed to proceed. It returns the targets to be remediated, the delayed targets and the time
+// after which the first delayed target can be remediated.
+func (r *MachineHealthCheckReconciler) applyRemediationBackoff(ctx context.Context, logger logr.Logger, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck, targets, unhealthy []healthCheckTarget) ([]healthCheckTarget, []healthCheckTarget, time.Duration) {
+	if m.Spec.RemediationBackoff == nil {
+		m.Status.RemediationAttempts = nil
+		conditions.Delete(m, clusterv1.RemediationBackoffCondition)
+		return unhealthy, nil, 0
+	}
+
+	now := time.Now()
+	resetRemediationAttempts(m, targets, now)
+
+	var remediate, delayed []healthCheckTarget
+	var requeueAfter time.Duration
+	for _, t := range unhealthy {
+		if !r.isNewRemediation(ctx, cluster, m, t) {
+			remediate = append(remediate, t)
+			continue
+		}
+
+		owner := remediationOwner(t.Machine)
+		if remaining := remainingRemediationDelay(m, owner, now); remaining > 0 {
+			logger.Info("Target has failed health check, but its remediation is delayed by the remediation backoff", "target", t.string(), "owner", owner, "remainingDelay", remaining.Truncate(time.Second).String())
+			delayed = append(delayed, t)
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+
+		recordRemediationAttempt(m, owner, now)
+		remediate = append(remediate, t)
+	}
+
+	if len(delayed) > 0 {
+		conditions.MarkFalse(m, clusterv1.RemediationBackoffCondition, clusterv1.RemediationDelayedReason, clusterv1.ConditionSeverityWarning,
+			"Remediation of %d unhealthy machines is delayed by the remediation backoff", len(delayed))
+	} else {
+		conditions.MarkTrue(m, clusterv1.RemediationBackoffCondition)
+	}
+
+	return remediate, delayed, requeueAfter
+}
+
+// isNewRemediation returns true if marking the target for remediation triggers a new remediation, as opposed to
+// a remediation which is already in progress.
+func (r *MachineHealthCheckReconciler) isNewRemediation(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck, t healthCheckTarget) bool {
+	if annotations.IsPaused(cluster, t.Machine) {
+		return false
+	}
+
+	if m.Spec.RemediationTemplate != nil {
+		// Errors other than not found are surfaced when patching the unhealthy targets.
+		_, err := r.getExternalRemediationRequest(ctx, m, t.Machine.Name)
+		return err != nil && apierrors.IsNotFound(errors.Cause(err))
+	}
+
+	return !conditions.Has(t.Machine, clusterv1.MachineOwnerRemediatedCondition) || conditions.IsTrue(t.Machine, clusterv1.MachineOwnerRemediatedCondition)
+}
+
+// remediationOwner returns the key used to track the remediation attempts of the machine, which is
+// its controller owner or the machine itself if it has none.
+func remediationOwner(machine *clusterv1.Machine) string {
+	if ref := metav1.GetControllerOf(machine); ref != nil {
+		return fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+	}
+	return fmt.Sprintf("Machine/%s", machine.Name)
+}
+
+// remediationBackoffDelay returns the delay to be enforced after the given number of consecutive remediations.
+func remediationBackoffDelay(backoff *clusterv1.RemediationBackoff, attempts int32) time.Duration {
+	if backoff == nil || attempts <= 0 {
+		return 0
+	}
+
+	delay := clusterv1.DefaultRemediationBackoffInitialDelay
+	if backoff.InitialDelay != nil {
+		delay = backoff.InitialDelay.Duration
+	}
+	for i := int32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= backoff.MaxDelay.Duration {
+			return backoff.MaxDelay.Duration
+		}
+	}
+	if delay > backoff.MaxDelay.Duration {
+		return backoff.MaxDelay.Duration
+	}
+	return delay
+}
+
+// remainingRemediationDelay returns how long the next remediation of a machine with the given owner must be delayed.
+func remainingRemediationDelay(m *clusterv1.MachineHealthCheck, owner string, now time.Time) time.Duration {
+	attempt := getRemediationAttempt(m, owner)
+	if attempt == nil {
+		return 0
+	}
+	return attempt.LastAttemptTime.Add(remediationBackoffDelay(m.Spec.RemediationBackoff, attempt.Count)).Sub(now)
+}
+
+func getRemediationAttempt(m *clusterv1.MachineHealthCheck, owner string) *clusterv1.RemediationAttempt {
+	for i := range m.Status.RemediationAttempts {
+		if m.Status.RemediationAttempts[i].Owner == owner {
+			return &m.Status.RemediationAttempts[i]
+		}
+	}
+	return nil
+}
+
+func recordRemediationAttempt(m *clusterv1.MachineHealthCheck, owner string, now time.Time) {
+	if attempt := getRemediationAttempt(m, owner); attempt != nil {
+		attempt.Count++
+		attempt.LastAttemptTime = metav1.NewTime(now)
+		return
+	}
+	m.Status.RemediationAttempts = append(m.Status.RemediationAttempts, clusterv1.RemediationAttempt{
+		Owner:           owner,
+		Count:           1,
+		LastAttemptTime: metav1.NewTime(now),
+	})
+}
+
+// resetRemediationAttempts drops the remediation attempts of owners whose machines are all healthy once the
+// backoff delay is expired; waiting for the delay to expire prevents resetting the count before the replacement
+// machine is created. Attempts of owners without targets are dropped as well.
+func resetRemediationAttempts(m *clusterv1.MachineHealthCheck, targets []healthCheckTarget, now time.Time) {
+	hasTargets := map[string]bool{}
+	hasUnhealthyTargets := map[string]bool{}
+	for _, t := range targets {
+		owner := remediationOwner(t.Machine)
+		hasTargets[owner] = true
+		if !conditions.IsTrue(t.Machine, clusterv1.MachineHealthCheckSuccededCondition) {
+			hasUnhealthyTargets[owner] = true
+		}
+	}
+
+	attempts := []clusterv1.RemediationAttempt{}
+	for _, attempt := range m.Status.RemediationAttempts {
+		if !hasTargets[attempt.Owner] {
+			continue
+		}
+		if !hasUnhealthyTargets[attempt.Owner] && remainingRemediationDelay(m, attempt.Owner, now) <= 0 {
+			continue
+		}
+		attempts = append(attempts, attempt)
+	}
+	if len(attempts) == 0 {
+		attempts = nil
+	}
+	m.Status.RemediationAttempts = attempts
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func newRemediationBackoffTarget(mhc *clusterv1.MachineHealthCheck, name, owner string, healthy bool) healthCheckTarget {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "MachineSet",
+					Name:       owner,
+					Controller: pointer.BoolPtr(true),
+				},
+			},
+		},
+	}
+	if healthy {
+		conditions.MarkTrue(machine, clusterv1.MachineHealthCheckSuccededCondition)
+	} else {
+		conditions.MarkFalse(machine, clusterv1.MachineHealthCheckSuccededCondition, clusterv1.NodeNotFoundReason, clusterv1.ConditionSeverityWarning, "")
+	}
+	return healthCheckTarget{Machine: machine, MHC: mhc}
+}
+
+func TestApplyRemediationBackoff(t *testing.T) {
+	t.Run("consecutive failed remediations are delayed increasingly", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &MachineHealthCheckReconciler{}
+		cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}}
+		mhc := &clusterv1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-mhc", Namespace: "default"},
+			Spec: clusterv1.MachineHealthCheckSpec{
+				RemediationBackoff: &clusterv1.RemediationBackoff{
+					InitialDelay: &metav1.Duration{Duration: time.Minute},
+					MaxDelay:     metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+		}
+
+		// The first remediation is never delayed.
+		target := newRemediationBackoffTarget(mhc, "machine-0", "ms", false)
+		remediate, delayed, requeueAfter := r.applyRemediationBackoff(ctx, ctrl.Log, cluster, mhc, []healthCheckTarget{target}, []healthCheckTarget{target})
+		g.Expect(remediate).To(HaveLen(1))
+		g.Expect(delayed).To(BeEmpty())
+		g.Expect(requeueAfter).To(BeZero())
+		g.Expect(conditions.IsTrue(mhc, clusterv1.RemediationBackoffCondition)).To(BeTrue())
+
+		// Each replacement fails as well, so every further remediation is delayed twice as long as the previous one.
+		for i, expectedDelay := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+			target := newRemediationBackoffTarget(mhc, fmt.Sprintf("machine-%d", i+1), "ms", false)
+
+			remediate, delayed, requeueAfter := r.applyRemediationBackoff(ctx, ctrl.Log, cluster, mhc, []healthCheckTarget{target}, []healthCheckTarget{target})
+			g.Expect(remediate).To(BeEmpty())
+			g.Expect(delayed).To(HaveLen(1))
+			g.Expect(requeueAfter).To(BeNumerically("~", expectedDelay, time.Second))
+			g.Expect(conditions.IsFalse(mhc, clusterv1.RemediationBackoffCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(mhc, clusterv1.RemediationBackoffCondition)).To(Equal(clusterv1.RemediationDelayedReason))
+
+			// Simulate the delay expiring.
+			g.Expect(mhc.Status.RemediationAttempts).To(HaveLen(1))
+			mhc.Status.RemediationAttempts[0].LastAttemptTime = metav1.NewTime(mhc.Status.RemediationAttempts[0].LastAttemptTime.Add(-expectedDelay))
+
+			remediate, delayed, requeueAfter = r.applyRemediationBackoff(ctx, ctrl.Log, cluster, mhc, []healthCheckTarget{target}, []healthCheckTarget{target})
+			g.Expect(remediate).To(HaveLen(1))
+			g.Expect(delayed).To(BeEmpty())
+			g.Expect(requeueAfter).To(BeZero())
+			g.Expect(conditions.IsTrue(mhc, clusterv1.RemediationBackoffCondition)).To(BeTrue())
+			g.Expect(mhc.Status.RemediationAttempts[0].Owner).To(Equal("MachineSet/ms"))
+			g.Expect(mhc.Status.RemediationAttempts[0].Count).To(Equal(int32(i + 2)))
+		}
+	})
+
+	t.Run("remediations in progress and machines with other owners are not delayed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &MachineHealthCheckReconciler{}
+		cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}}
+		mhc := &clusterv1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-mhc", Namespace: "default"},
+			Spec: clusterv1.MachineHealthCheckSpec{
+				RemediationBackoff: &clusterv1.RemediationBackoff{
+					InitialDelay: &metav1.Duration{Duration: time.Minute},
+					MaxDelay:     metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			Status: clusterv1.MachineHealthCheckStatus{
+				RemediationAttempts: []clusterv1.RemediationAttempt{
+					{Owner: "MachineSet/ms", Count: 1, LastAttemptTime: metav1.Now()},
+				},
+			},
+		}
+
+		inProgress := newRemediationBackoffTarget(mhc, "machine-0", "ms", false)
+		conditions.MarkFalse(inProgress.Machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+		otherOwner := newRemediationBackoffTarget(mhc, "machine-1", "other-ms", false)
+
+		targets := []healthCheckTarget{inProgress, otherOwner}
+		remediate, delayed, requeueAfter := r.applyRemediationBackoff(ctx, ctrl.Log, cluster, mhc, targets, targets)
+		g.Expect(remediate).To(HaveLen(2))
+		g.Expect(delayed).To(BeEmpty())
+		g.Expect(requeueAfter).To(BeZero())
+		g.Expect(getRemediationAttempt(mhc, "MachineSet/ms").Count).To(Equal(int32(1)))
+		g.Expect(getRemediationAttempt(mhc, "MachineSet/other-ms").Count).To(Equal(int32(1)))
+	})
+
+	t.Run("without a backoff attempts are not tracked", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &MachineHealthCheckReconciler{}
+		cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}}
+		mhc := &clusterv1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-mhc", Namespace: "default"},
+			Status: clusterv1.MachineHealthCheckStatus{
+				RemediationAttempts: []clusterv1.RemediationAttempt{
+					{Owner: "MachineSet/ms", Count: 3, LastAttemptTime: metav1.Now()},
+				},
+			},
+		}
+		conditions.MarkTrue(mhc, clusterv1.RemediationBackoffCondition)
+
+		target := newRemediationBackoffTarget(mhc, "machine-0", "ms", false)
+		remediate, delayed, requeueAfter := r.applyRemediationBackoff(ctx, ctrl.Log, cluster, mhc, []healthCheckTarget{target}, []healthCheckTarget{target})
+		g.Expect(remediate).To(HaveLen(1))
+		g.Expect(delayed).To(BeEmpty())
+		g.Expect(requeueAfter).To(BeZero())
+		g.Expect(mhc.Status.RemediationAttempts).To(BeEmpty())
+		g.Expect(conditions.Has(mhc, clusterv1.RemediationBackoffCondition)).To(BeFalse())
+	})
+}
+
+func TestRemediationBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		backoff  *clusterv1.RemediationBackoff
+		attempts int32
+		expected time.Duration
+	}{
+		{
+			name:     "no delay without a backoff",
+			attempts: 3,
+			expected: 0,
+		},
+		{
+			name:     "no delay without previous attempts",
+			backoff:  &clusterv1.RemediationBackoff{InitialDelay: &metav1.Duration{Duration: time.Minute}, MaxDelay: metav1.Duration{Duration: 10 * time.Minute}},
+			attempts: 0,
+			expected: 0,
+		},
+		{
+			name:     "initial delay after the first attempt",
+			backoff:  &clusterv1.RemediationBackoff{InitialDelay: &metav1.Duration{Duration: time.Minute}, MaxDelay: metav1.Duration{Duration: 10 * time.Minute}},
+			attempts: 1,
+			expected: time.Minute,
+		},
+		{
+			name:     "delay doubles at each attempt",
+			backoff:  &clusterv1.RemediationBackoff{InitialDelay: &metav1.Duration{Duration: time.Minute}, MaxDelay: metav1.Duration{Duration: 10 * time.Minute}},
+			attempts: 4,
+			expected: 8 * time.Minute,
+		},
+		{
+			name:     "delay is capped to the max delay",
+			backoff:  &clusterv1.RemediationBackoff{InitialDelay: &metav1.Duration{Duration: time.Minute}, MaxDelay: metav1.Duration{Duration: 10 * time.Minute}},
+			attempts: 5,
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "delay does not overflow with many attempts",
+			backoff:  &clusterv1.RemediationBackoff{InitialDelay: &metav1.Duration{Duration: time.Minute}, MaxDelay: metav1.Duration{Duration: 10 * time.Minute}},
+			attempts: 1000,
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "initial delay defaults to one minute",
+			backoff:  &clusterv1.RemediationBackoff{MaxDelay: metav1.Duration{Duration: 10 * time.Minute}},
+			attempts: 2,
+			expected: 2 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(remediationBackoffDelay(tt.backoff, tt.attempts)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestResetRemediationAttempts(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	mhc := &clusterv1.MachineHealthCheck{
+		Spec: clusterv1.MachineHealthCheckSpec{
+			RemediationBackoff: &clusterv1.RemediationBackoff{
+				InitialDelay: &metav1.Duration{Duration: time.Minute},
+				MaxDelay:     metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+		Status: clusterv1.MachineHealthCheckStatus{
+			RemediationAttempts: []clusterv1.RemediationAttempt{
+				{Owner: "MachineSet/healthy-expired", Count: 2, LastAttemptTime: metav1.NewTime(now.Add(-5 * time.Minute))},
+				{Owner: "MachineSet/healthy-not-expired", Count: 2, LastAttemptTime: metav1.NewTime(now.Add(-time.Minute))},
+				{Owner: "MachineSet/unhealthy", Count: 2, LastAttemptTime: metav1.NewTime(now.Add(-5 * time.Minute))},
+				{Owner: "MachineSet/gone", Count: 2, LastAttemptTime: metav1.NewTime(now.Add(-time.Minute))},
+			},
+		},
+	}
+
+	targets := []healthCheckTarget{
+		newRemediationBackoffTarget(mhc, "machine-0", "healthy-expired", true),
+		newRemediationBackoffTarget(mhc, "machine-1", "healthy-not-expired", true),
+		newRemediationBackoffTarget(mhc, "machine-2", "unhealthy", true),
+		newRemediationBackoffTarget(mhc, "machine-3", "unhealthy", false),
+	}
+	resetRemediationAttempts(mhc, targets, now)
+
+	owners := []string{}
+	for _, attempt := range mhc.Status.RemediationAttempts {
+		owners = append(owners, attempt.Owner)
+	}
+	g.Expect(owners).To(ConsistOf("MachineSet/healthy-not-expired", "MachineSet/unhealthy"))
+}
@@ -31,6 +31,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -70,8 +71,9 @@ type MachineHealthCheckReconciler struct {
 	Tracker          *remote.ClusterCacheTracker
 	WatchFilterValue string
 
-	controller controller.Controller
-	recorder   record.EventRecorder
+	controller      controller.Controller
+	recorder        record.EventRecorder
+	externalTracker external.ObjectTracker
 }
 
 func (r *MachineHealthCheckReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -99,6 +101,9 @@ func (r *MachineHealthCheckReconciler) SetupWithManager(ctx context.Context, mgr
 
 	r.controller = controller
 	r.recorder = mgr.GetEventRecorderFor("machinehealthcheck-controller")
+	r.externalTracker = external.ObjectTracker{
+		Controller: controller,
+	}
 	return nil
 }
 
@@ -132,6 +137,7 @@ func (r *MachineHealthCheckReconciler) Reconcile(ctx context.Context, req ctrl.R
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, m) {
 		log.Info("Reconciliation is paused for this object")
+		r.recorder.Event(m, corev1.EventTypeNormal, EventReconciliationPaused, "Reconciliation is paused for this object")
 		return ctrl.Result{}, nil
 	}
 
@@ -287,8 +293,19 @@ func (r *MachineHealthCheckReconciler) reconcile(ctx context.Context, logger log
 	m.Status.RemediationsAllowed = remediationCount
 	conditions.MarkTrue(m, clusterv1.RemediationAllowedCondition)
 
+	// Delay the remediation of targets whose owner was recently remediated, if a backoff is configured.
+	unhealthy, delayed, backoffRequeue := r.applyRemediationBackoff(ctx, logger, cluster, m, targets, unhealthy)
+	if backoffRequeue > 0 {
+		nextCheckTimes = append(nextCheckTimes, backoffRequeue)
+	}
+
 	errList := r.PatchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)
 	errList = append(errList, r.PatchHealthyTargets(ctx, logger, healthy, cluster, m)...)
+	for _, t := range delayed {
+		if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to patch unhealthy machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+		}
+	}
 
 	// handle update errors
 	if len(errList) > 0 {
@@ -349,53 +366,39 @@ func (r *MachineHealthCheckReconciler) PatchUnhealthyTargets(ctx context.Context
 			logger.Info("Machine has failed health check, but machine is paused so skipping remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message)
 		} else {
 			if m.Spec.RemediationTemplate != nil {
-				// If external remediation request already exists,
-				// return early
-				if r.externalRemediationRequestExists(ctx, m, t.Machine.Name) {
-					return errList
-				}
-
-				cloneOwnerRef := &metav1.OwnerReference{
-					APIVersion: clusterv1.GroupVersion.String(),
-					Kind:       "Machine",
-					Name:       t.Machine.Name,
-					UID:        t.Machine.UID,
-				}
-
-				from, err := external.Get(ctx, r.Client, m.Spec.RemediationTemplate, t.Machine.Namespace)
+				obj, err := r.getExternalRemediationRequest(ctx, m, t.Machine.Name)
 				if err != nil {
-					conditions.MarkFalse(m, clusterv1.ExternalRemediationTemplateAvailable, clusterv1.ExternalRemediationTemplateNotFound, clusterv1.ConditionSeverityError, err.Error())
-					errList = append(errList, errors.Wrapf(err, "error retrieving remediation template %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, t.Machine.Name, t.Machine.Namespace, m.Spec.ClusterName))
-					return errList
+					if !apierrors.IsNotFound(errors.Cause(err)) {
+						errList = append(errList, errors.Wrapf(err, "failed to fetch remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.ClusterName))
+						continue
+					}
+
+					obj, err = r.createExternalRemediationRequest(ctx, logger, m, t)
+					if err != nil {
+						errList = append(errList, err)
+						return errList
+					}
 				}
 
-				generateTemplateInput := &external.GenerateTemplateInput{
-					Template:    from,
-					TemplateRef: m.Spec.RemediationTemplate,
-					Namespace:   t.Machine.Namespace,
-					ClusterName: t.Machine.ClusterName,
-					OwnerRef:    cloneOwnerRef,
+				// Watch the remediation requests, so failures are acted upon as soon as they are reported.
+				if err := r.externalTracker.Watch(logger, obj, handler.EnqueueRequestsFromMapFunc(r.externalRemediationRequestToMachineHealthCheck)); err != nil {
+					errList = append(errList, err)
+					continue
 				}
-				to, err := external.GenerateTemplate(generateTemplateInput)
+
+				// If the external remediation failed, fall back to having the owner of the Machine delete it.
+				failureReason, failureMessage, err := external.FailuresFrom(obj)
 				if err != nil {
-					errList = append(errList, errors.Wrapf(err, "failed to create template for remediation request %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, t.Machine.Name, t.Machine.Namespace, m.Spec.ClusterName))
-					return errList
+					errList = append(errList, err)
+					continue
 				}
-
-				// Set the Remediation Request to match the Machine name, the name is used to
-				// guarantee uniqueness between runs. A Machine should only ever have a single
-				// remediation object of a specific GVK created.
-				//
-				// NOTE: This doesn't guarantee uniqueness across different MHC objects watching
-				// the same Machine, users are in charge of setting health checks and remediation properly.
-				to.SetName(t.Machine.Name)
-
-				logger.Info("Target has failed health check, creating an external remediation request", "remediation request name", to.GetName(), "target", t.string(), "reason", condition.Reason, "message", condition.Message)
-				// Create the external clone.
-				if err := r.Client.Create(ctx, to); err != nil {
-					conditions.MarkFalse(m, clusterv1.ExternalRemediationRequestAvailable, clusterv1.ExternalRemediationRequestCreationFailed, clusterv1.ConditionSeverityError, err.Error())
-					errList = append(errList, errors.Wrapf(err, "error creating remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.ClusterName))
-					return errList
+				if failureReason != "" || failureMessage != "" {
+					logger.Info("External remediation failed, marking for remediation by the owner", "remediation request name", obj.GetName(), "target", t.string(), "failureReason", failureReason, "failureMessage", failureMessage)
+					conditions.MarkFalse(t.Machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.ExternalRemediationFailedReason, clusterv1.ConditionSeverityWarning,
+						"%v %q failed: %s", obj.GroupVersionKind().Kind, obj.GetName(), strings.TrimSpace(failureReason+" "+failureMessage))
+				} else if err := r.checkExternalRemediationRetries(logger, m, t, obj); err != nil {
+					errList = append(errList, err)
+					continue
 				}
 			} else {
 				logger.Info("Target has failed health check, marking for remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message)
@@ -470,14 +473,35 @@ func (r *MachineHealthCheckReconciler) machineToMachineHealthCheck(o client.Obje
 	var requests []reconcile.Request
 	for k := range mhcList.Items {
 		mhc := &mhcList.Items[k]
-		if hasMatchingLabels(mhc.Spec.Selector, m.Labels) {
-			key := util.ObjectKey(mhc)
-			requests = append(requests, reconcile.Request{NamespacedName: key})
+		for _, selector := range machineSelectors(mhc) {
+			if hasMatchingLabels(selector, m.Labels) {
+				key := util.ObjectKey(mhc)
+				requests = append(requests, reconcile.Request{NamespacedName: key})
+				break
+			}
 		}
 	}
 	return requests
 }
 
+// externalRemediationRequestToMachineHealthCheck maps events from External Remediation Requests to
+// MachineHealthCheck objects that monitor the Machine being remediated
+func (r *MachineHealthCheckReconciler) externalRemediationRequestToMachineHealthCheck(o client.Object) []reconcile.Request {
+	for _, ref := range o.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || ref.Kind != "Machine" || gv.Group != clusterv1.GroupVersion.Group {
+			continue
+		}
+
+		machine, err := util.GetMachineByName(context.TODO(), r.Client, o.GetNamespace(), ref.Name)
+		if err != nil {
+			return nil
+		}
+		return r.machineToMachineHealthCheck(machine)
+	}
+	return nil
+}
+
 func (r *MachineHealthCheckReconciler) nodeToMachineHealthCheck(o client.Object) []reconcile.Request {
 	node, ok := o.(*corev1.Node)
 	if !ok {
@@ -587,11 +611,15 @@ func getUnhealthyRange(mhc *clusterv1.MachineHealthCheck) (int, int, error) {
 	return int(min), int(max), nil
 }
 
+// getMaxUnhealthy returns the number of unhealthy machines allowed by the MaxUnhealthy field.
+// An absolute value is used as is, so 0 never allows remediation. A percentage is rounded up,
+// which ensures a non-zero percentage allows remediating at least one machine when only a few
+// machines are targeted.
 func getMaxUnhealthy(mhc *clusterv1.MachineHealthCheck) (int, error) {
 	if mhc.Spec.MaxUnhealthy == nil {
 		return 0, errors.New("spec.maxUnhealthy must be set")
 	}
-	maxUnhealthy, err := intstr.GetValueFromIntOrPercent(mhc.Spec.MaxUnhealthy, int(mhc.Status.ExpectedMachines), false)
+	maxUnhealthy, err := intstr.GetValueFromIntOrPercent(mhc.Spec.MaxUnhealthy, int(mhc.Status.ExpectedMachines), true)
 	if err != nil {
 		return 0, err
 	}
@@ -612,6 +640,80 @@ func machineNames(machines []*clusterv1.Machine) []string {
 	return result
 }
 
+// createExternalRemediationRequest creates the External Remediation Request for the target machine, cloning it from
+// the remediation template of the MachineHealthCheck.
+func (r *MachineHealthCheckReconciler) createExternalRemediationRequest(ctx context.Context, logger logr.Logger, m *clusterv1.MachineHealthCheck, t healthCheckTarget) (*unstructured.Unstructured, error) {
+	condition := conditions.Get(t.Machine, clusterv1.MachineHealthCheckSuccededCondition)
+
+	cloneOwnerRef := &metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Machine",
+		Name:       t.Machine.Name,
+		UID:        t.Machine.UID,
+	}
+
+	from, err := external.Get(ctx, r.Client, m.Spec.RemediationTemplate, t.Machine.Namespace)
+	if err != nil {
+		conditions.MarkFalse(m, clusterv1.ExternalRemediationTemplateAvailable, clusterv1.ExternalRemediationTemplateNotFound, clusterv1.ConditionSeverityError, err.Error())
+		return nil, errors.Wrapf(err, "error retrieving remediation template %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, t.Machine.Name, t.Machine.Namespace, m.Spec.ClusterName)
+	}
+
+	generateTemplateInput := &external.GenerateTemplateInput{
+		Template:    from,
+		TemplateRef: m.Spec.RemediationTemplate,
+		Namespace:   t.Machine.Namespace,
+		ClusterName: t.Machine.ClusterName,
+		OwnerRef:    cloneOwnerRef,
+	}
+	to, err := external.GenerateTemplate(generateTemplateInput)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create template for remediation request %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, t.Machine.Name, t.Machine.Namespace, m.Spec.ClusterName)
+	}
+
+	// Set the Remediation Request to match the Machine name, the name is used to
+	// guarantee uniqueness between runs. A Machine should only ever have a single
+	// remediation object of a specific GVK created.
+	//
+	// NOTE: This doesn't guarantee uniqueness across different MHC objects watching
+	// the same Machine, users are in charge of setting health checks and remediation properly.
+	to.SetName(t.Machine.Name)
+
+	logger.Info("Target has failed health check, creating an external remediation request", "remediation request name", to.GetName(), "target", t.string(), "reason", condition.Reason, "message", condition.Message)
+	// Create the external clone.
+	if err := r.Client.Create(ctx, to); err != nil {
+		conditions.MarkFalse(m, clusterv1.ExternalRemediationRequestAvailable, clusterv1.ExternalRemediationRequestCreationFailed, clusterv1.ConditionSeverityError, err.Error())
+		return nil, errors.Wrapf(err, "error creating remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.ClusterName)
+	}
+	return to, nil
+}
+
+// checkExternalRemediationRetries marks the target machine for remediation by its owner if the External Remediation
+// Request was retried RemediationMaxRetries times without completing.
+func (r *MachineHealthCheckReconciler) checkExternalRemediationRetries(logger logr.Logger, m *clusterv1.MachineHealthCheck, t healthCheckTarget, obj *unstructured.Unstructured) error {
+	completed, err := external.IsCompleted(obj)
+	if err != nil {
+		return err
+	}
+	retryCount, err := external.RetryCountFrom(obj)
+	if err != nil {
+		return err
+	}
+
+	if completed {
+		// The machine is expected to pass the health check soon, at which point the remediation request is deleted.
+		logger.V(3).Info("External remediation completed, waiting for the target to become healthy", "remediation request name", obj.GetName(), "target", t.string(), "retryCount", retryCount)
+		return nil
+	}
+	if m.Spec.RemediationMaxRetries == nil || retryCount < int64(*m.Spec.RemediationMaxRetries) {
+		return nil
+	}
+
+	logger.Info("External remediation exhausted its retries, marking for remediation by the owner", "remediation request name", obj.GetName(), "target", t.string(), "retryCount", retryCount)
+	conditions.MarkFalse(t.Machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.ExternalRemediationRetriesExhaustedReason, clusterv1.ConditionSeverityWarning,
+		"%v %q did not complete after %d retries", obj.GroupVersionKind().Kind, obj.GetName(), retryCount)
+	return nil
+}
+
 // getExternalRemediationRequest gets reference to External Remediation Request, unstructured object.
 func (r *MachineHealthCheckReconciler) getExternalRemediationRequest(ctx context.Context, m *clusterv1.MachineHealthCheck, machineName string) (*unstructured.Unstructured, error) {
 	remediationRef := &corev1.ObjectReference{
@@ -625,13 +727,3 @@ func (r *MachineHealthCheckReconciler) getExternalRemediationRequest(ctx context
 	}
 	return remediationReq, nil
 }
-
-// externalRemediationRequestExists checks if the External Remediation Request is created
-// for the machine.
-func (r *MachineHealthCheckReconciler) externalRemediationRequestExists(ctx context.Context, m *clusterv1.MachineHealthCheck, machineName string) bool {
-	remediationReq, err := r.getExternalRemediationRequest(ctx, m, machineName)
-	if err != nil {
-		return false
-	}
-	return remediationReq != nil
-}
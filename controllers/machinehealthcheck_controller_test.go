@@ -281,7 +281,7 @@ func TestMachineHealthCheck_Reconcile(t *testing.T) {
 		cluster := createNamespaceAndCluster(g)
 
 		mhc := newMachineHealthCheck(cluster.Namespace, cluster.Name)
-		maxUnhealthy := intstr.Parse("40%")
+		maxUnhealthy := intstr.Parse("0%")
 		mhc.Spec.MaxUnhealthy = &maxUnhealthy
 
 		g.Expect(testEnv.Create(ctx, mhc)).To(Succeed())
@@ -331,7 +331,7 @@ func TestMachineHealthCheck_Reconcile(t *testing.T) {
 					Status:   corev1.ConditionFalse,
 					Severity: clusterv1.ConditionSeverityWarning,
 					Reason:   clusterv1.TooManyUnhealthyReason,
-					Message:  "Remediation is not allowed, the number of not started or unhealthy machines exceeds maxUnhealthy (total: 3, unhealthy: 2, maxUnhealthy: 40%)",
+					Message:  "Remediation is not allowed, the number of not started or unhealthy machines exceeds maxUnhealthy (total: 3, unhealthy: 2, maxUnhealthy: 0%)",
 				},
 			},
 		}))
@@ -1534,6 +1534,524 @@ func TestMachineHealthCheck_Reconcile(t *testing.T) {
 			return obj
 		}, timeout, 100*time.Millisecond).Should(BeNil())
 	})
+
+	t.Run("When remediationTemplate is set and the Remediation Request reports a failure, the Machine should be marked for remediation by its owner", func(t *testing.T) {
+		g := NewWithT(t)
+		cluster := createNamespaceAndCluster(g)
+
+		// Create remediation template resource.
+		infraRemediationResource := map[string]interface{}{
+			"kind":       "InfrastructureRemediation",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata":   map[string]interface{}{},
+			"spec": map[string]interface{}{
+				"size": "3xlarge",
+			},
+		}
+		infraRemediationTmpl := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": infraRemediationResource,
+				},
+			},
+		}
+		infraRemediationTmpl.SetKind("InfrastructureRemediationTemplate")
+		infraRemediationTmpl.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1alpha3")
+		infraRemediationTmpl.SetGenerateName("remediation-template-name-")
+		infraRemediationTmpl.SetNamespace(cluster.Namespace)
+		g.Expect(testEnv.Create(ctx, infraRemediationTmpl)).To(Succeed())
+
+		remediationTemplate := &corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+			Kind:       "InfrastructureRemediationTemplate",
+			Name:       infraRemediationTmpl.GetName(),
+		}
+
+		mhc := newMachineHealthCheck(cluster.Namespace, cluster.Name)
+		mhc.Spec.RemediationTemplate = remediationTemplate
+		g.Expect(testEnv.Create(ctx, mhc)).To(Succeed())
+		defer func(do ...client.Object) {
+			g.Expect(testEnv.Cleanup(ctx, do...)).To(Succeed())
+		}(cluster, mhc, infraRemediationTmpl)
+
+		// Healthy nodes and machines.
+		nodes, machines, cleanup := createMachinesWithNodes(g, cluster,
+			count(1),
+			createNodeRefForMachine(true),
+			markNodeAsHealthy(true),
+			machineLabels(mhc.Spec.Selector.MatchLabels),
+		)
+		defer cleanup()
+		targetMachines := make([]string, len(machines))
+		for i, m := range machines {
+			targetMachines[i] = m.Name
+		}
+		sort.Strings(targetMachines)
+
+		// Make sure the status matches.
+		g.Eventually(func() *clusterv1.MachineHealthCheckStatus {
+			err := testEnv.Get(ctx, util.ObjectKey(mhc), mhc)
+			if err != nil {
+				return nil
+			}
+			return &mhc.Status
+		}).Should(MatchMachineHealthCheckStatus(&clusterv1.MachineHealthCheckStatus{
+			ExpectedMachines:    1,
+			CurrentHealthy:      1,
+			RemediationsAllowed: 1,
+			ObservedGeneration:  1,
+			Targets:             targetMachines,
+			Conditions: clusterv1.Conditions{
+				{
+					Type:   clusterv1.RemediationAllowedCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		}))
+
+		// Transition the node to unhealthy.
+		node := nodes[0]
+		nodePatch := client.MergeFrom(node.DeepCopy())
+		node.Status.Conditions = []corev1.NodeCondition{
+			{
+				Type:               corev1.NodeReady,
+				Status:             corev1.ConditionUnknown,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+			},
+		}
+		g.Expect(testEnv.Status().Patch(ctx, node, nodePatch)).To(Succeed())
+
+		// Make sure the status matches.
+		g.Eventually(func() *clusterv1.MachineHealthCheckStatus {
+			err := testEnv.Get(ctx, util.ObjectKey(mhc), mhc)
+			if err != nil {
+				return nil
+			}
+			return &mhc.Status
+		}).Should(MatchMachineHealthCheckStatus(&clusterv1.MachineHealthCheckStatus{
+			ExpectedMachines:    1,
+			CurrentHealthy:      0,
+			RemediationsAllowed: 0,
+			ObservedGeneration:  1,
+			Targets:             targetMachines,
+			Conditions: clusterv1.Conditions{
+				{
+					Type:   clusterv1.RemediationAllowedCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		}))
+
+		// Calculate how many Machines have health check succeeded = false.
+		g.Eventually(func() (unhealthy int) {
+			machines := &clusterv1.MachineList{}
+			err := testEnv.List(ctx, machines, client.MatchingLabels{
+				"selector": mhc.Spec.Selector.MatchLabels["selector"],
+			})
+			if err != nil {
+				return -1
+			}
+
+			for i := range machines.Items {
+				if conditions.IsFalse(&machines.Items[i], clusterv1.MachineHealthCheckSuccededCondition) {
+					unhealthy++
+				}
+			}
+			return
+		}).Should(Equal(1))
+
+		ref := corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+			Kind:       "InfrastructureRemediation",
+		}
+
+		obj := util.ObjectReferenceToUnstructured(ref)
+		// Make sure the Remeditaion Request is created.
+		g.Eventually(func() *unstructured.Unstructured {
+			key := client.ObjectKey{
+				Namespace: machines[0].Namespace,
+				Name:      machines[0].Name,
+			}
+			err := testEnv.Get(ctx, key, obj)
+			if err != nil {
+				return nil
+			}
+			return obj
+		}, timeout, 100*time.Millisecond).ShouldNot(BeNil())
+		g.Expect(obj.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(obj.GetOwnerReferences()[0].Name).To(Equal(machines[0].Name))
+
+		// Report a failure on the Remediation Request.
+		remediationPatch := client.MergeFrom(obj.DeepCopy())
+		g.Expect(unstructured.SetNestedField(obj.Object, "RebootFailed", "status", "failureReason")).To(Succeed())
+		g.Expect(unstructured.SetNestedField(obj.Object, "failed to reboot the host", "status", "failureMessage")).To(Succeed())
+		g.Expect(testEnv.Status().Patch(ctx, obj, remediationPatch)).To(Succeed())
+
+		// Make sure the Machine is marked for remediation by its owner.
+		g.Eventually(func() *clusterv1.Condition {
+			machine := &clusterv1.Machine{}
+			if err := testEnv.Get(ctx, util.ObjectKey(machines[0]), machine); err != nil {
+				return nil
+			}
+			return conditions.Get(machine, clusterv1.MachineOwnerRemediatedCondition)
+		}, timeout, 100*time.Millisecond).Should(And(
+			Not(BeNil()),
+			WithTransform(func(c *clusterv1.Condition) corev1.ConditionStatus { return c.Status }, Equal(corev1.ConditionFalse)),
+			WithTransform(func(c *clusterv1.Condition) string { return c.Reason }, Equal(clusterv1.ExternalRemediationFailedReason)),
+			WithTransform(func(c *clusterv1.Condition) string { return c.Message }, ContainSubstring("RebootFailed failed to reboot the host")),
+		))
+	})
+
+	t.Run("When remediationTemplate is set and the Remediation Request completes, the Machine should not be marked for remediation by its owner", func(t *testing.T) {
+		g := NewWithT(t)
+		cluster := createNamespaceAndCluster(g)
+
+		// Create remediation template resource.
+		infraRemediationResource := map[string]interface{}{
+			"kind":       "InfrastructureRemediation",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata":   map[string]interface{}{},
+			"spec": map[string]interface{}{
+				"size": "3xlarge",
+			},
+		}
+		infraRemediationTmpl := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": infraRemediationResource,
+				},
+			},
+		}
+		infraRemediationTmpl.SetKind("InfrastructureRemediationTemplate")
+		infraRemediationTmpl.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1alpha3")
+		infraRemediationTmpl.SetGenerateName("remediation-template-name-")
+		infraRemediationTmpl.SetNamespace(cluster.Namespace)
+		g.Expect(testEnv.Create(ctx, infraRemediationTmpl)).To(Succeed())
+
+		remediationTemplate := &corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+			Kind:       "InfrastructureRemediationTemplate",
+			Name:       infraRemediationTmpl.GetName(),
+		}
+
+		mhc := newMachineHealthCheck(cluster.Namespace, cluster.Name)
+		mhc.Spec.RemediationTemplate = remediationTemplate
+		mhc.Spec.RemediationMaxRetries = pointer.Int32Ptr(1)
+		g.Expect(testEnv.Create(ctx, mhc)).To(Succeed())
+		defer func(do ...client.Object) {
+			g.Expect(testEnv.Cleanup(ctx, do...)).To(Succeed())
+		}(cluster, mhc, infraRemediationTmpl)
+
+		// Healthy nodes and machines.
+		nodes, machines, cleanup := createMachinesWithNodes(g, cluster,
+			count(1),
+			createNodeRefForMachine(true),
+			markNodeAsHealthy(true),
+			machineLabels(mhc.Spec.Selector.MatchLabels),
+		)
+		defer cleanup()
+		targetMachines := make([]string, len(machines))
+		for i, m := range machines {
+			targetMachines[i] = m.Name
+		}
+		sort.Strings(targetMachines)
+
+		// Make sure the status matches.
+		g.Eventually(func() *clusterv1.MachineHealthCheckStatus {
+			err := testEnv.Get(ctx, util.ObjectKey(mhc), mhc)
+			if err != nil {
+				return nil
+			}
+			return &mhc.Status
+		}).Should(MatchMachineHealthCheckStatus(&clusterv1.MachineHealthCheckStatus{
+			ExpectedMachines:    1,
+			CurrentHealthy:      1,
+			RemediationsAllowed: 1,
+			ObservedGeneration:  1,
+			Targets:             targetMachines,
+			Conditions: clusterv1.Conditions{
+				{
+					Type:   clusterv1.RemediationAllowedCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		}))
+
+		// Transition the node to unhealthy.
+		node := nodes[0]
+		nodePatch := client.MergeFrom(node.DeepCopy())
+		node.Status.Conditions = []corev1.NodeCondition{
+			{
+				Type:               corev1.NodeReady,
+				Status:             corev1.ConditionUnknown,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+			},
+		}
+		g.Expect(testEnv.Status().Patch(ctx, node, nodePatch)).To(Succeed())
+
+		// Make sure the status matches.
+		g.Eventually(func() *clusterv1.MachineHealthCheckStatus {
+			err := testEnv.Get(ctx, util.ObjectKey(mhc), mhc)
+			if err != nil {
+				return nil
+			}
+			return &mhc.Status
+		}).Should(MatchMachineHealthCheckStatus(&clusterv1.MachineHealthCheckStatus{
+			ExpectedMachines:    1,
+			CurrentHealthy:      0,
+			RemediationsAllowed: 0,
+			ObservedGeneration:  1,
+			Targets:             targetMachines,
+			Conditions: clusterv1.Conditions{
+				{
+					Type:   clusterv1.RemediationAllowedCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		}))
+
+		// Calculate how many Machines have health check succeeded = false.
+		g.Eventually(func() (unhealthy int) {
+			machines := &clusterv1.MachineList{}
+			err := testEnv.List(ctx, machines, client.MatchingLabels{
+				"selector": mhc.Spec.Selector.MatchLabels["selector"],
+			})
+			if err != nil {
+				return -1
+			}
+
+			for i := range machines.Items {
+				if conditions.IsFalse(&machines.Items[i], clusterv1.MachineHealthCheckSuccededCondition) {
+					unhealthy++
+				}
+			}
+			return
+		}).Should(Equal(1))
+
+		ref := corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+			Kind:       "InfrastructureRemediation",
+		}
+
+		obj := util.ObjectReferenceToUnstructured(ref)
+		// Make sure the Remeditaion Request is created.
+		g.Eventually(func() *unstructured.Unstructured {
+			key := client.ObjectKey{
+				Namespace: machines[0].Namespace,
+				Name:      machines[0].Name,
+			}
+			err := testEnv.Get(ctx, key, obj)
+			if err != nil {
+				return nil
+			}
+			return obj
+		}, timeout, 100*time.Millisecond).ShouldNot(BeNil())
+		g.Expect(obj.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(obj.GetOwnerReferences()[0].Name).To(Equal(machines[0].Name))
+
+		// Report the completion of the Remediation Request, after as many retries as allowed.
+		remediationPatch := client.MergeFrom(obj.DeepCopy())
+		g.Expect(unstructured.SetNestedField(obj.Object, true, "status", "completed")).To(Succeed())
+		g.Expect(unstructured.SetNestedField(obj.Object, int64(1), "status", "retryCount")).To(Succeed())
+		g.Expect(testEnv.Status().Patch(ctx, obj, remediationPatch)).To(Succeed())
+
+		// Make sure the Machine is not marked for remediation by its owner.
+		g.Consistently(func() *clusterv1.Condition {
+			machine := &clusterv1.Machine{}
+			if err := testEnv.Get(ctx, util.ObjectKey(machines[0]), machine); err != nil {
+				return nil
+			}
+			return conditions.Get(machine, clusterv1.MachineOwnerRemediatedCondition)
+		}, 2*time.Second, 100*time.Millisecond).Should(BeNil())
+
+		// Transition the node back to healthy.
+		nodePatch = client.MergeFrom(node.DeepCopy())
+		node.Status.Conditions = []corev1.NodeCondition{
+			{
+				Type:               corev1.NodeReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(time.Now()),
+			},
+		}
+		g.Expect(testEnv.Status().Patch(ctx, node, nodePatch)).To(Succeed())
+
+		// Make sure the Remediation Request is deleted.
+		g.Eventually(func() bool {
+			key := client.ObjectKey{
+				Namespace: machines[0].Namespace,
+				Name:      machines[0].Name,
+			}
+			return apierrors.IsNotFound(testEnv.Get(ctx, key, obj))
+		}, timeout, 100*time.Millisecond).Should(BeTrue())
+	})
+	t.Run("When remediationTemplate is set and the Remediation Request exhausts its retries, the Machine should be marked for remediation by its owner", func(t *testing.T) {
+		g := NewWithT(t)
+		cluster := createNamespaceAndCluster(g)
+
+		// Create remediation template resource.
+		infraRemediationResource := map[string]interface{}{
+			"kind":       "InfrastructureRemediation",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata":   map[string]interface{}{},
+			"spec": map[string]interface{}{
+				"size": "3xlarge",
+			},
+		}
+		infraRemediationTmpl := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": infraRemediationResource,
+				},
+			},
+		}
+		infraRemediationTmpl.SetKind("InfrastructureRemediationTemplate")
+		infraRemediationTmpl.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1alpha3")
+		infraRemediationTmpl.SetGenerateName("remediation-template-name-")
+		infraRemediationTmpl.SetNamespace(cluster.Namespace)
+		g.Expect(testEnv.Create(ctx, infraRemediationTmpl)).To(Succeed())
+
+		remediationTemplate := &corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+			Kind:       "InfrastructureRemediationTemplate",
+			Name:       infraRemediationTmpl.GetName(),
+		}
+
+		mhc := newMachineHealthCheck(cluster.Namespace, cluster.Name)
+		mhc.Spec.RemediationTemplate = remediationTemplate
+		mhc.Spec.RemediationMaxRetries = pointer.Int32Ptr(2)
+		g.Expect(testEnv.Create(ctx, mhc)).To(Succeed())
+		defer func(do ...client.Object) {
+			g.Expect(testEnv.Cleanup(ctx, do...)).To(Succeed())
+		}(cluster, mhc, infraRemediationTmpl)
+
+		// Healthy nodes and machines.
+		nodes, machines, cleanup := createMachinesWithNodes(g, cluster,
+			count(1),
+			createNodeRefForMachine(true),
+			markNodeAsHealthy(true),
+			machineLabels(mhc.Spec.Selector.MatchLabels),
+		)
+		defer cleanup()
+		targetMachines := make([]string, len(machines))
+		for i, m := range machines {
+			targetMachines[i] = m.Name
+		}
+		sort.Strings(targetMachines)
+
+		// Make sure the status matches.
+		g.Eventually(func() *clusterv1.MachineHealthCheckStatus {
+			err := testEnv.Get(ctx, util.ObjectKey(mhc), mhc)
+			if err != nil {
+				return nil
+			}
+			return &mhc.Status
+		}).Should(MatchMachineHealthCheckStatus(&clusterv1.MachineHealthCheckStatus{
+			ExpectedMachines:    1,
+			CurrentHealthy:      1,
+			RemediationsAllowed: 1,
+			ObservedGeneration:  1,
+			Targets:             targetMachines,
+			Conditions: clusterv1.Conditions{
+				{
+					Type:   clusterv1.RemediationAllowedCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		}))
+
+		// Transition the node to unhealthy.
+		node := nodes[0]
+		nodePatch := client.MergeFrom(node.DeepCopy())
+		node.Status.Conditions = []corev1.NodeCondition{
+			{
+				Type:               corev1.NodeReady,
+				Status:             corev1.ConditionUnknown,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+			},
+		}
+		g.Expect(testEnv.Status().Patch(ctx, node, nodePatch)).To(Succeed())
+
+		// Make sure the status matches.
+		g.Eventually(func() *clusterv1.MachineHealthCheckStatus {
+			err := testEnv.Get(ctx, util.ObjectKey(mhc), mhc)
+			if err != nil {
+				return nil
+			}
+			return &mhc.Status
+		}).Should(MatchMachineHealthCheckStatus(&clusterv1.MachineHealthCheckStatus{
+			ExpectedMachines:    1,
+			CurrentHealthy:      0,
+			RemediationsAllowed: 0,
+			ObservedGeneration:  1,
+			Targets:             targetMachines,
+			Conditions: clusterv1.Conditions{
+				{
+					Type:   clusterv1.RemediationAllowedCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		}))
+
+		// Calculate how many Machines have health check succeeded = false.
+		g.Eventually(func() (unhealthy int) {
+			machines := &clusterv1.MachineList{}
+			err := testEnv.List(ctx, machines, client.MatchingLabels{
+				"selector": mhc.Spec.Selector.MatchLabels["selector"],
+			})
+			if err != nil {
+				return -1
+			}
+
+			for i := range machines.Items {
+				if conditions.IsFalse(&machines.Items[i], clusterv1.MachineHealthCheckSuccededCondition) {
+					unhealthy++
+				}
+			}
+			return
+		}).Should(Equal(1))
+
+		ref := corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+			Kind:       "InfrastructureRemediation",
+		}
+
+		obj := util.ObjectReferenceToUnstructured(ref)
+		// Make sure the Remeditaion Request is created.
+		g.Eventually(func() *unstructured.Unstructured {
+			key := client.ObjectKey{
+				Namespace: machines[0].Namespace,
+				Name:      machines[0].Name,
+			}
+			err := testEnv.Get(ctx, key, obj)
+			if err != nil {
+				return nil
+			}
+			return obj
+		}, timeout, 100*time.Millisecond).ShouldNot(BeNil())
+		g.Expect(obj.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(obj.GetOwnerReferences()[0].Name).To(Equal(machines[0].Name))
+
+		// Report the retries of the Remediation Request, without completing it.
+		remediationPatch := client.MergeFrom(obj.DeepCopy())
+		g.Expect(unstructured.SetNestedField(obj.Object, false, "status", "completed")).To(Succeed())
+		g.Expect(unstructured.SetNestedField(obj.Object, int64(2), "status", "retryCount")).To(Succeed())
+		g.Expect(testEnv.Status().Patch(ctx, obj, remediationPatch)).To(Succeed())
+
+		// Make sure the Machine is marked for remediation by its owner.
+		g.Eventually(func() *clusterv1.Condition {
+			machine := &clusterv1.Machine{}
+			if err := testEnv.Get(ctx, util.ObjectKey(machines[0]), machine); err != nil {
+				return nil
+			}
+			return conditions.Get(machine, clusterv1.MachineOwnerRemediatedCondition)
+		}, timeout, 100*time.Millisecond).Should(And(
+			Not(BeNil()),
+			WithTransform(func(c *clusterv1.Condition) corev1.ConditionStatus { return c.Status }, Equal(corev1.ConditionFalse)),
+			WithTransform(func(c *clusterv1.Condition) string { return c.Reason }, Equal(clusterv1.ExternalRemediationRetriesExhaustedReason)),
+			WithTransform(func(c *clusterv1.Condition) string { return c.Message }, ContainSubstring("did not complete after 2 retries")),
+		))
+	})
+
 }
 
 func TestClusterToMachineHealthCheck(t *testing.T) {
@@ -1635,6 +2153,16 @@ func TestMachineToMachineHealthCheck(t *testing.T) {
 	mhc2Req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: mhc2.Namespace, Name: mhc2.Name}}
 	mhc3 := newMachineHealthCheckWithLabels("mhc3", namespace, clusterName, map[string]string{"cluster": "foo", "nodepool": "other"})
 	mhc4 := newMachineHealthCheckWithLabels("mhc4", "othernamespace", clusterName, labels)
+	mhc5 := newMachineHealthCheckWithLabels("mhc5", namespace, clusterName, nil)
+	mhc5.Spec.Selector = metav1.LabelSelector{}
+	mhc5.Spec.Selectors = []metav1.LabelSelector{
+		{MatchLabels: map[string]string{"nodepool": "other"}},
+		{MatchLabels: map[string]string{"nodepool": "bar"}},
+		{MatchLabels: map[string]string{"cluster": "foo"}},
+	}
+	mhc5Req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: mhc5.Namespace, Name: mhc5.Name}}
+	mhc6 := newMachineHealthCheckWithLabels("mhc6", namespace, clusterName, map[string]string{"nodepool": "other"})
+	mhc6.Spec.Selectors = []metav1.LabelSelector{{MatchLabels: map[string]string{"nodepool": "another"}}}
 	machine1 := newTestMachine("machine1", namespace, clusterName, nodeName, labels)
 
 	testCases := []struct {
@@ -1667,6 +2195,18 @@ func TestMachineToMachineHealthCheck(t *testing.T) {
 			object:   machine1,
 			expected: []reconcile.Request{},
 		},
+		{
+			name:     "when several selectors of a MachineHealthCheck match labels for the Machine",
+			toCreate: []clusterv1.MachineHealthCheck{*mhc5},
+			object:   machine1,
+			expected: []reconcile.Request{mhc5Req},
+		},
+		{
+			name:     "when no selector of a MachineHealthCheck matches labels for the Machine",
+			toCreate: []clusterv1.MachineHealthCheck{*mhc6},
+			object:   machine1,
+			expected: []reconcile.Request{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1866,7 +2406,7 @@ func TestIsAllowedRemediation(t *testing.T) {
 			name:             "when maxUnhealthy is a percentage less than current unhealthy",
 			maxUnhealthy:     &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
 			expectedMachines: int32(5),
-			currentHealthy:   int32(2),
+			currentHealthy:   int32(1),
 			allowed:          false,
 		},
 		{
@@ -1883,6 +2423,62 @@ func TestIsAllowedRemediation(t *testing.T) {
 			currentHealthy:   int32(2),
 			allowed:          true,
 		},
+		{
+			name:             "when maxUnhealthy is an int of 0 with 1 unhealthy of 1",
+			maxUnhealthy:     &intstr.IntOrString{Type: intstr.Int, IntVal: int32(0)},
+			expectedMachines: int32(1),
+			currentHealthy:   int32(0),
+			allowed:          false,
+		},
+		{
+			name:             "when maxUnhealthy is an int of 0 with 1 unhealthy of 3",
+			maxUnhealthy:     &intstr.IntOrString{Type: intstr.Int, IntVal: int32(0)},
+			expectedMachines: int32(3),
+			currentHealthy:   int32(2),
+			allowed:          false,
+		},
+		{
+			name:             "when maxUnhealthy is 0% with 1 unhealthy of 3",
+			maxUnhealthy:     &intstr.IntOrString{Type: intstr.String, StrVal: "0%"},
+			expectedMachines: int32(3),
+			currentHealthy:   int32(2),
+			allowed:          false,
+		},
+		{
+			name:             "when maxUnhealthy is 40% with 1 unhealthy of 1",
+			maxUnhealthy:     &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			expectedMachines: int32(1),
+			currentHealthy:   int32(0),
+			allowed:          true,
+		},
+		{
+			name:             "when maxUnhealthy is 40% with 1 unhealthy of 2",
+			maxUnhealthy:     &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			expectedMachines: int32(2),
+			currentHealthy:   int32(1),
+			allowed:          true,
+		},
+		{
+			name:             "when maxUnhealthy is 40% with 2 unhealthy of 2",
+			maxUnhealthy:     &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			expectedMachines: int32(2),
+			currentHealthy:   int32(0),
+			allowed:          false,
+		},
+		{
+			name:             "when maxUnhealthy is 40% with 2 unhealthy of 3",
+			maxUnhealthy:     &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			expectedMachines: int32(3),
+			currentHealthy:   int32(1),
+			allowed:          true,
+		},
+		{
+			name:             "when maxUnhealthy is 40% with 3 unhealthy of 3",
+			maxUnhealthy:     &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			expectedMachines: int32(3),
+			currentHealthy:   int32(0),
+			allowed:          false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1947,7 +2543,35 @@ func TestGetMaxUnhealthy(t *testing.T) {
 			name:                 "when maxUnhealthy is a 60% (of 7)",
 			maxUnhealthy:         &intstr.IntOrString{Type: intstr.String, StrVal: "60%"},
 			actualMachineCount:   7,
-			expectedMaxUnhealthy: 4,
+			expectedMaxUnhealthy: 5,
+			expectedErr:          nil,
+		},
+		{
+			name:                 "when maxUnhealthy is an int of 0",
+			maxUnhealthy:         &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+			actualMachineCount:   3,
+			expectedMaxUnhealthy: 0,
+			expectedErr:          nil,
+		},
+		{
+			name:                 "when maxUnhealthy is a 40% (of 1)",
+			maxUnhealthy:         &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			actualMachineCount:   1,
+			expectedMaxUnhealthy: 1,
+			expectedErr:          nil,
+		},
+		{
+			name:                 "when maxUnhealthy is a 40% (of 2)",
+			maxUnhealthy:         &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			actualMachineCount:   2,
+			expectedMaxUnhealthy: 1,
+			expectedErr:          nil,
+		},
+		{
+			name:                 "when maxUnhealthy is a 40% (of 3)",
+			maxUnhealthy:         &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			actualMachineCount:   3,
+			expectedMaxUnhealthy: 2,
 			expectedErr:          nil,
 		},
 	}
@@ -108,6 +108,16 @@ func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachi
 		return true, time.Duration(0)
 	}
 
+	// the infrastructure machine did not report ready within the InfrastructureReadyTimeout, and the machine
+	// asks to be remediated in this case
+	if t.Machine.Spec.InfrastructureReadyTimeoutPolicy == clusterv1.InfrastructureReadyTimeoutRemediatePolicy &&
+		conditions.IsFalse(t.Machine, clusterv1.InfrastructureReadyCondition) &&
+		conditions.GetReason(t.Machine, clusterv1.InfrastructureReadyCondition) == clusterv1.InfrastructureProvisionFailedReason {
+		conditions.MarkFalse(t.Machine, clusterv1.MachineHealthCheckSuccededCondition, clusterv1.InfrastructureProvisionFailedReason, clusterv1.ConditionSeverityWarning, "%s", conditions.GetMessage(t.Machine, clusterv1.InfrastructureReadyCondition))
+		logger.V(3).Info("Target is unhealthy: infrastructure machine did not report ready within the infrastructure ready timeout")
+		return true, time.Duration(0)
+	}
+
 	// the node does not exist
 	if t.nodeMissing {
 		logger.V(3).Info("Target is unhealthy: node is missing")
@@ -201,26 +211,49 @@ func (r *MachineHealthCheckReconciler) getTargetsFromMHC(ctx context.Context, lo
 	return targets, nil
 }
 
-//getMachinesFromMHC fetches Machines matched by the MachineHealthCheck's
-// label selector
+// getMachinesFromMHC fetches Machines matched by any of the MachineHealthCheck's
+// label selectors
 func (r *MachineHealthCheckReconciler) getMachinesFromMHC(ctx context.Context, mhc *clusterv1.MachineHealthCheck) ([]clusterv1.Machine, error) {
-	selector, err := metav1.LabelSelectorAsSelector(metav1.CloneSelectorAndAddLabel(
-		&mhc.Spec.Selector, clusterv1.ClusterLabelName, mhc.Spec.ClusterName,
-	))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to build selector")
+	var machines []clusterv1.Machine
+	seen := map[string]bool{}
+	labelSelectors := machineSelectors(mhc)
+	for i := range labelSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(metav1.CloneSelectorAndAddLabel(
+			&labelSelectors[i], clusterv1.ClusterLabelName, mhc.Spec.ClusterName,
+		))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build selector")
+		}
+
+		var machineList clusterv1.MachineList
+		if err := r.Client.List(
+			ctx,
+			&machineList,
+			client.MatchingLabelsSelector{Selector: selector},
+			client.InNamespace(mhc.GetNamespace()),
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to list machines")
+		}
+
+		// Machines matched by more than one selector are targeted once.
+		for _, m := range machineList.Items {
+			if seen[m.Name] {
+				continue
+			}
+			seen[m.Name] = true
+			machines = append(machines, m)
+		}
 	}
+	return machines, nil
+}
 
-	var machineList clusterv1.MachineList
-	if err := r.Client.List(
-		ctx,
-		&machineList,
-		client.MatchingLabelsSelector{Selector: selector},
-		client.InNamespace(mhc.GetNamespace()),
-	); err != nil {
-		return nil, errors.Wrap(err, "failed to list machines")
+// machineSelectors returns the label selectors of the MachineHealthCheck: the selector, unless it is empty
+// because only selectors are set, and the selectors.
+func machineSelectors(mhc *clusterv1.MachineHealthCheck) []metav1.LabelSelector {
+	if len(mhc.Spec.Selector.MatchLabels) == 0 && len(mhc.Spec.Selector.MatchExpressions) == 0 && len(mhc.Spec.Selectors) > 0 {
+		return mhc.Spec.Selectors
 	}
-	return machineList.Items, nil
+	return append([]metav1.LabelSelector{mhc.Spec.Selector}, mhc.Spec.Selectors...)
 }
 
 // getNodeFromMachine fetches the node from a local or remote cluster for a
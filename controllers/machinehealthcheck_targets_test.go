@@ -27,6 +27,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -176,6 +177,87 @@ func TestGetTargetsFromMHC(t *testing.T) {
 	}
 }
 
+func TestGetMachinesFromMHCWithSelectors(t *testing.T) {
+	namespace := "test-mhc"
+	clusterName := "test-cluster"
+
+	poolA := newTestMachine("machine-a", namespace, clusterName, "node-a", map[string]string{"pool": "a"})
+	poolB := newTestMachine("machine-b", namespace, clusterName, "node-b", map[string]string{"pool": "b"})
+	poolAGPU := newTestMachine("machine-a-gpu", namespace, clusterName, "node-a-gpu", map[string]string{"pool": "a", "gpu": "true"})
+	poolC := newTestMachine("machine-c", namespace, clusterName, "node-c", map[string]string{"pool": "c"})
+	otherCluster := newTestMachine("machine-other", namespace, "other-cluster", "node-other", map[string]string{"pool": "a"})
+
+	testCases := []struct {
+		desc             string
+		selector         metav1.LabelSelector
+		selectors        []metav1.LabelSelector
+		expectedMachines []string
+	}{
+		{
+			desc:             "with the selector only",
+			selector:         metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+			expectedMachines: []string{"machine-a", "machine-a-gpu"},
+		},
+		{
+			desc: "with disjoint selectors",
+			selectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"pool": "a"}},
+				{MatchLabels: map[string]string{"pool": "b"}},
+			},
+			expectedMachines: []string{"machine-a", "machine-a-gpu", "machine-b"},
+		},
+		{
+			desc:     "with the selector and disjoint selectors",
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "c"}},
+			selectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"pool": "b"}},
+			},
+			expectedMachines: []string{"machine-b", "machine-c"},
+		},
+		{
+			desc: "with overlapping selectors",
+			selectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"pool": "a"}},
+				{MatchLabels: map[string]string{"gpu": "true"}},
+				{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "pool", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}}}},
+			},
+			expectedMachines: []string{"machine-a", "machine-a-gpu", "machine-b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gs := NewWithT(t)
+
+			gs.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+			k8sClient := fake.NewClientBuilder().WithObjects(poolA, poolB, poolAGPU, poolC, otherCluster).Build()
+			reconciler := &MachineHealthCheckReconciler{
+				Client: k8sClient,
+			}
+
+			mhc := &clusterv1.MachineHealthCheck{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-mhc",
+					Namespace: namespace,
+				},
+				Spec: clusterv1.MachineHealthCheckSpec{
+					ClusterName: clusterName,
+					Selector:    tc.selector,
+					Selectors:   tc.selectors,
+				},
+			}
+			machines, err := reconciler.getMachinesFromMHC(ctx, mhc)
+			gs.Expect(err).ToNot(HaveOccurred())
+
+			machineNames := make([]string, 0, len(machines))
+			for _, m := range machines {
+				machineNames = append(machineNames, m.Name)
+			}
+			gs.Expect(machineNames).To(ConsistOf(tc.expectedMachines))
+		})
+	}
+}
+
 func TestHealthCheckTargets(t *testing.T) {
 	namespace := "test-mhc"
 	clusterName := "test-cluster"
@@ -220,6 +302,44 @@ func TestHealthCheckTargets(t *testing.T) {
 		Node:    nil,
 	}
 
+	// Target for when the node has not been seen by the Machine controller for longer than the startup timeout
+	testMachineLastUpdated1200s := testMachine.DeepCopy()
+	nowMinus1200s := metav1.NewTime(time.Now().Add(-1200 * time.Second))
+	testMachineLastUpdated1200s.Status.LastUpdated = &nowMinus1200s
+
+	nodeNotStartedTarget := healthCheckTarget{
+		MHC:     testMHC,
+		Machine: testMachineLastUpdated1200s,
+		Node:    nil,
+	}
+
+	// Targets for when the infrastructure machine did not report ready within the InfrastructureReadyTimeout
+	testMachineInfrastructureProvisionFailed := testMachineLastUpdated400s.DeepCopy()
+	testMachineInfrastructureProvisionFailed.Spec.InfrastructureReadyTimeoutPolicy = clusterv1.InfrastructureReadyTimeoutRemediatePolicy
+	conditions.MarkFalse(testMachineInfrastructureProvisionFailed, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureProvisionFailedReason, clusterv1.ConditionSeverityError, "")
+	infrastructureProvisionFailedRemediate := healthCheckTarget{
+		MHC:     testMHC,
+		Machine: testMachineInfrastructureProvisionFailed,
+		Node:    nil,
+	}
+
+	testMachineInfrastructureProvisionFailedReport := testMachineInfrastructureProvisionFailed.DeepCopy()
+	testMachineInfrastructureProvisionFailedReport.Spec.InfrastructureReadyTimeoutPolicy = clusterv1.InfrastructureReadyTimeoutReportPolicy
+	infrastructureProvisionFailedReport := healthCheckTarget{
+		MHC:     testMHC,
+		Machine: testMachineInfrastructureProvisionFailedReport,
+		Node:    nil,
+	}
+
+	// Target for when the node has been seen before the startup timeout, then went unknown for shorter than the timeout
+	testMachineWithNodeLastUpdated1200s := testMachineLastUpdated1200s.DeepCopy()
+	nodeUnknown200AfterStartupTimeout := healthCheckTarget{
+		MHC:         testMHC,
+		Machine:     testMachineWithNodeLastUpdated1200s,
+		Node:        newTestUnhealthyNode("node1", corev1.NodeReady, corev1.ConditionUnknown, 200*time.Second),
+		nodeMissing: false,
+	}
+
 	// Target for when the Node has been seen, but has now gone
 	nodeGoneAway := healthCheckTarget{
 		MHC:         testMHC,
@@ -279,6 +399,34 @@ func TestHealthCheckTargets(t *testing.T) {
 			expectedNeedsRemediation: []healthCheckTarget{},
 			expectedNextCheckTimes:   []time.Duration{200 * time.Second},
 		},
+		{
+			desc:                     "when the node has not started for longer than the startup timeout",
+			targets:                  []healthCheckTarget{nodeNotStartedTarget},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{nodeNotStartedTarget},
+			expectedNextCheckTimes:   []time.Duration{},
+		},
+		{
+			desc:                     "when the node started and has been in an unknown state for shorter than the timeout, after the startup timeout",
+			targets:                  []healthCheckTarget{nodeUnknown200AfterStartupTimeout},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{100 * time.Second},
+		},
+		{
+			desc:                     "when the infrastructure machine did not report ready in time, with the remediate policy",
+			targets:                  []healthCheckTarget{infrastructureProvisionFailedRemediate},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{infrastructureProvisionFailedRemediate},
+			expectedNextCheckTimes:   []time.Duration{},
+		},
+		{
+			desc:                     "when the infrastructure machine did not report ready in time, with the report policy",
+			targets:                  []healthCheckTarget{infrastructureProvisionFailedReport},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{200 * time.Second},
+		},
 		{
 			desc:                     "when the node has gone away",
 			targets:                  []healthCheckTarget{nodeGoneAway},
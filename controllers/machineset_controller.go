@@ -24,6 +24,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -36,8 +37,10 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/cluster-api/util/failuredomains"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -132,6 +135,7 @@ func (r *MachineSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, machineSet) {
 		log.Info("Reconciliation is paused for this object")
+		r.recorder.Event(machineSet, corev1.EventTypeNormal, EventReconciliationPaused, "Reconciliation is paused for this object")
 		return ctrl.Result{}, nil
 	}
 
@@ -269,7 +273,11 @@ func (r *MachineSetReconciler) reconcile(ctx context.Context, cluster *clusterv1
 		return ctrl.Result{}, errors.Wrap(err, "failed to remediate machines")
 	}
 
-	syncErr := r.syncReplicas(ctx, machineSet, filteredMachines)
+	if err := r.syncMachinesInPlace(ctx, machineSet, filteredMachines); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to propagate in place changes to machines")
+	}
+
+	syncErr := r.syncReplicas(ctx, cluster, machineSet, filteredMachines)
 
 	// Always updates status as machines come up or die.
 	if err := r.updateStatus(ctx, cluster, machineSet, filteredMachines); err != nil {
@@ -308,8 +316,61 @@ func (r *MachineSetReconciler) reconcile(ctx context.Context, cluster *clusterv1
 	return ctrl.Result{}, nil
 }
 
+// syncMachinesInPlace propagates in place the labels, annotations and timeouts of the machine template to the existing
+// machines, so changes to them do not require a rollout. Labels and annotations removed from the machine template are
+// left untouched on the machines, given that they could have been set by other controllers or users.
+func (r *MachineSetReconciler) syncMachinesInPlace(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+	var errs []error
+	for _, machine := range machines {
+		if !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		patch := client.MergeFrom(machine.DeepCopy())
+		labelsChanged := mergeMetadata(&machine.Labels, ms.Spec.Template.Labels)
+		annotationsChanged := mergeMetadata(&machine.Annotations, ms.Spec.Template.Annotations)
+		timeoutsChanged := syncTimeouts(&machine.Spec, &ms.Spec.Template.Spec)
+		if !labelsChanged && !annotationsChanged && !timeoutsChanged {
+			continue
+		}
+
+		if err := r.Client.Patch(ctx, machine, patch); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to patch Machine %q", machine.Name))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// syncTimeouts sets the node drain and volume detach timeouts of the desired machine spec into the machine spec
+// and returns true if the machine spec has changed.
+func syncTimeouts(spec *clusterv1.MachineSpec, desired *clusterv1.MachineSpec) bool {
+	if apiequality.Semantic.DeepEqual(spec.NodeDrainTimeout, desired.NodeDrainTimeout) &&
+		apiequality.Semantic.DeepEqual(spec.NodeVolumeDetachTimeout, desired.NodeVolumeDetachTimeout) {
+		return false
+	}
+	spec.NodeDrainTimeout = desired.NodeDrainTimeout.DeepCopy()
+	spec.NodeVolumeDetachTimeout = desired.NodeVolumeDetachTimeout.DeepCopy()
+	return true
+}
+
+// mergeMetadata sets the desired keys into the metadata map and returns true if the map has changed.
+func mergeMetadata(metadata *map[string]string, desired map[string]string) bool {
+	changed := false
+	for k, v := range desired {
+		if cur, ok := (*metadata)[k]; ok && cur == v {
+			continue
+		}
+		if *metadata == nil {
+			*metadata = map[string]string{}
+		}
+		(*metadata)[k] = v
+		changed = true
+	}
+	return changed
+}
+
 // syncReplicas scales Machine resources up or down.
-func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+func (r *MachineSetReconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluster, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
 	log := ctrl.LoggerFrom(ctx)
 	if ms.Spec.Replicas == nil {
 		return errors.Errorf("the Replicas field in Spec for machineset %v is nil, this should not be allowed", ms.Name)
@@ -319,6 +380,12 @@ func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.M
 	switch {
 	case diff < 0:
 		diff *= -1
+		if ms.Annotations != nil {
+			if _, ok := ms.Annotations[clusterv1.DisableMachineCreate]; ok {
+				log.V(2).Info("Automatic creation of new machines disabled for machine set")
+				return nil
+			}
+		}
 		log.Info("Too few replicas", "need", *(ms.Spec.Replicas), "creating", diff)
 
 		var (
@@ -332,6 +399,11 @@ func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.M
 
 			machine := r.getNewMachine(ms)
 
+			// Spread the Machines across the failure domains of the Cluster, unless the template sets one.
+			if machine.Spec.FailureDomain == nil {
+				machine.Spec.FailureDomain = pickMachineFailureDomain(cluster, append(machines, machineList...))
+			}
+
 			// Clone and set the infrastructure and bootstrap references.
 			var (
 				infraRef, bootstrapRef *corev1.ObjectReference
@@ -421,6 +493,21 @@ func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.M
 	return nil
 }
 
+// pickMachineFailureDomain returns the failure domain with the fewest Machines, not being deleted, among the
+// given ones. Only the failure domains not suitable for control plane Machines are considered; it returns nil
+// if the Cluster has none, so worker Machines are never placed in failure domains meant for the control plane.
+func pickMachineFailureDomain(cluster *clusterv1.Cluster, machines []*clusterv1.Machine) *string {
+	failureDomains := clusterv1.FailureDomains{}
+	for id, spec := range cluster.Status.FailureDomains {
+		if !spec.ControlPlane {
+			failureDomains[id] = spec
+		}
+	}
+
+	activeMachines := collections.FromMachines(machines...).Filter(collections.Not(collections.HasDeletionTimestamp))
+	return failuredomains.PickFewest(failureDomains, activeMachines)
+}
+
 // getNewMachine creates a new Machine object. The name of the newly created resource is going
 // to be created by the API server, we set the generateName field.
 func (r *MachineSetReconciler) getNewMachine(machineSet *clusterv1.MachineSet) *clusterv1.Machine {
@@ -636,7 +723,8 @@ func (r *MachineSetReconciler) updateStatus(ctx context.Context, cluster *cluste
 			continue
 		}
 
-		if noderefutil.IsNodeReady(node) {
+		// The readiness gates of the machine must be met as well for the machine to be ready.
+		if noderefutil.IsNodeReady(node) && len(unmetReadinessGates(machine)) == 0 {
 			readyReplicasCount++
 			if noderefutil.IsNodeAvailable(node, ms.Spec.MinReadySeconds, metav1.Now()) {
 				availableReplicasCount++
@@ -17,23 +17,29 @@ limitations under the License.
 package controllers
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -254,6 +260,138 @@ var _ = Describe("MachineSet Reconciler", func() {
 	})
 })
 
+func TestMachineSetScaleSubresource(t *testing.T) {
+	g := NewWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "ms-scale")
+	g.Expect(err).ToNot(HaveOccurred())
+	testCluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: ns.Name, Name: "test-cluster"}}
+	g.Expect(testEnv.Create(ctx, testCluster)).To(Succeed())
+	g.Expect(testEnv.CreateKubeconfigSecret(ctx, testCluster)).To(Succeed())
+	defer func() {
+		g.Expect(testEnv.Cleanup(ctx, testCluster, ns)).To(Succeed())
+	}()
+
+	// Create bootstrap and infrastructure template resources.
+	for _, tmpl := range []struct{ apiVersion, kind string }{
+		{apiVersion: "bootstrap.cluster.x-k8s.io/v1alpha4", kind: "BootstrapMachine"},
+		{apiVersion: "infrastructure.cluster.x-k8s.io/v1alpha4", kind: "InfrastructureMachine"},
+	} {
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"kind":       tmpl.kind,
+						"apiVersion": tmpl.apiVersion,
+						"metadata":   map[string]interface{}{},
+					},
+				},
+			},
+		}
+		obj.SetKind(tmpl.kind + "Template")
+		obj.SetAPIVersion(tmpl.apiVersion)
+		obj.SetName("ms-scale-template")
+		obj.SetNamespace(ns.Name)
+		g.Expect(testEnv.Create(ctx, obj)).To(Succeed())
+	}
+
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ms-scale",
+			Namespace: ns.Name,
+		},
+		Spec: clusterv1.MachineSetSpec{
+			ClusterName: testCluster.Name,
+			Replicas:    pointer.Int32Ptr(1),
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"ms-scale": "true",
+				},
+			},
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels: map[string]string{
+						"ms-scale": "true",
+					},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: testCluster.Name,
+					Bootstrap: clusterv1.Bootstrap{
+						ConfigRef: &corev1.ObjectReference{
+							APIVersion: "bootstrap.cluster.x-k8s.io/v1alpha4",
+							Kind:       "BootstrapMachineTemplate",
+							Name:       "ms-scale-template",
+						},
+					},
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+						Kind:       "InfrastructureMachineTemplate",
+						Name:       "ms-scale-template",
+					},
+				},
+			},
+		},
+	}
+	g.Expect(testEnv.Create(ctx, ms)).To(Succeed())
+
+	msResource := clusterv1.GroupVersion.WithResource("machinesets")
+	getMachineSetScale := func() (*autoscalingv1.Scale, error) {
+		return getScale(msResource, ns.Name, ms.Name)
+	}
+	scaleMachineSet := func(replicas int32) error {
+		return setScaleReplicas(msResource, ns.Name, ms.Name, replicas)
+	}
+	machineCount := func() int {
+		machines := &clusterv1.MachineList{}
+		if err := testEnv.List(ctx, machines, client.InNamespace(ns.Name), client.MatchingLabels{"ms-scale": "true"}); err != nil {
+			return -1
+		}
+		return len(machines.Items)
+	}
+
+	// The scale subresource reports the desired and the observed replicas, and the selector of the MachineSet.
+	g.Eventually(func() (int32, error) {
+		scale, err := getMachineSetScale()
+		if err != nil {
+			return -1, err
+		}
+		g.Expect(scale.Spec.Replicas).To(BeEquivalentTo(1))
+		return scale.Status.Replicas, nil
+	}, timeout).Should(BeEquivalentTo(1))
+	scale, err := getMachineSetScale()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(scale.Status.Selector).To(ContainSubstring("ms-scale=true"))
+
+	// Scale up through the subresource.
+	g.Expect(scaleMachineSet(3)).To(Succeed())
+	g.Eventually(machineCount, timeout).Should(Equal(3))
+	g.Eventually(func() (int32, error) {
+		scale, err := getMachineSetScale()
+		if err != nil {
+			return -1, err
+		}
+		return scale.Status.Replicas, nil
+	}, timeout).Should(BeEquivalentTo(3))
+
+	// Scale down through the subresource.
+	g.Expect(scaleMachineSet(1)).To(Succeed())
+	g.Eventually(machineCount, timeout).Should(Equal(1))
+	g.Eventually(func() (int32, error) {
+		scale, err := getMachineSetScale()
+		if err != nil {
+			return -1, err
+		}
+		return scale.Status.Replicas, nil
+	}, timeout).Should(BeEquivalentTo(1))
+	g.Expect(testEnv.Get(ctx, util.ObjectKey(ms), ms)).To(Succeed())
+	g.Expect(*ms.Spec.Replicas).To(BeEquivalentTo(1))
+
+	// Scale to zero and delete the MachineSet, so the controllers are done with it before the test environment is torn down.
+	g.Expect(scaleMachineSet(0)).To(Succeed())
+	g.Eventually(machineCount, timeout).Should(Equal(0))
+	g.Expect(testEnv.Delete(ctx, ms)).To(Succeed())
+}
+
 func TestMachineSetOwnerReference(t *testing.T) {
 	testCluster := &clusterv1.Cluster{
 		TypeMeta:   metav1.TypeMeta{Kind: "Cluster", APIVersion: clusterv1.GroupVersion.String()},
@@ -765,6 +903,192 @@ func TestHasMatchingLabels(t *testing.T) {
 	}
 }
 
+func TestMachineSetUpdateStatusAvailableReplicas(t *testing.T) {
+	testCluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"}}
+
+	newNode := func(name string, ready corev1.ConditionStatus, readyFor time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             ready,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-readyFor)),
+					},
+				},
+			},
+		}
+	}
+	newMachineWithNode := func(name string, node *corev1.Node) *clusterv1.Machine {
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "test-cluster",
+				},
+			},
+		}
+		if node != nil {
+			machine.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: node.Name}
+		}
+		return machine
+	}
+
+	nodes := []*corev1.Node{
+		newNode("node-ready-for-an-hour", corev1.ConditionTrue, time.Hour),
+		newNode("node-ready-for-a-minute", corev1.ConditionTrue, time.Minute),
+		newNode("node-just-ready", corev1.ConditionTrue, 0),
+		newNode("node-not-ready", corev1.ConditionFalse, time.Hour),
+	}
+	machines := []*clusterv1.Machine{
+		newMachineWithNode("machine-ready-for-an-hour", nodes[0]),
+		newMachineWithNode("machine-ready-for-a-minute", nodes[1]),
+		newMachineWithNode("machine-just-ready", nodes[2]),
+		newMachineWithNode("machine-not-ready", nodes[3]),
+		newMachineWithNode("machine-without-node", nil),
+	}
+
+	tests := []struct {
+		name                  string
+		minReadySeconds       int32
+		wantAvailableReplicas int32
+	}{
+		{
+			name:                  "all the ready machines are available without min ready seconds",
+			minReadySeconds:       0,
+			wantAvailableReplicas: 3,
+		},
+		{
+			name:                  "only the machines ready for longer than min ready seconds are available",
+			minReadySeconds:       30,
+			wantAvailableReplicas: 2,
+		},
+		{
+			name:                  "no machine is available until it is ready for longer than min ready seconds",
+			minReadySeconds:       2 * 60 * 60,
+			wantAvailableReplicas: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			ms := newMachineSet("machineset1", "test-cluster")
+			ms.Spec.Replicas = pointer.Int32Ptr(int32(len(machines)))
+			ms.Spec.MinReadySeconds = tt.minReadySeconds
+
+			objects := []client.Object{testCluster, ms}
+			for _, node := range nodes {
+				objects = append(objects, node.DeepCopy())
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+
+			msr := &MachineSetReconciler{
+				Client:  c,
+				Tracker: remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(testCluster)),
+			}
+			g.Expect(msr.updateStatus(ctx, testCluster, ms, machines)).To(Succeed())
+
+			g.Expect(ms.Status.Replicas).To(Equal(int32(5)))
+			g.Expect(ms.Status.ReadyReplicas).To(Equal(int32(3)))
+			g.Expect(ms.Status.AvailableReplicas).To(Equal(tt.wantAvailableReplicas))
+		})
+	}
+}
+
+func TestMachineSetUpdateStatusReadinessGates(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	testCluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"}}
+	gate := clusterv1.ConditionType("SecurityAgentRegistered")
+
+	tests := []struct {
+		name      string
+		gates     []clusterv1.MachineReadinessGate
+		condition *clusterv1.Condition
+		wantReady bool
+	}{
+		{
+			name:      "machine without readiness gates",
+			wantReady: true,
+		},
+		{
+			name:      "machine with a readiness gate met",
+			gates:     []clusterv1.MachineReadinessGate{{ConditionType: gate}},
+			condition: conditions.TrueCondition(gate),
+			wantReady: true,
+		},
+		{
+			name:      "machine with a readiness gate not reported yet",
+			gates:     []clusterv1.MachineReadinessGate{{ConditionType: gate}},
+			wantReady: false,
+		},
+		{
+			name:      "machine with a readiness gate not met",
+			gates:     []clusterv1.MachineReadinessGate{{ConditionType: gate}},
+			condition: conditions.FalseCondition(gate, "AgentNotRegistered", clusterv1.ConditionSeverityWarning, ""),
+			wantReady: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-ready"},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{
+							Type:               corev1.NodeReady,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+						},
+					},
+				},
+			}
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machine",
+					Namespace: "default",
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName: "test-cluster",
+					},
+				},
+				Spec: clusterv1.MachineSpec{
+					ReadinessGates: tt.gates,
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: &corev1.ObjectReference{Kind: "Node", Name: node.Name},
+				},
+			}
+			if tt.condition != nil {
+				conditions.Set(machine, tt.condition)
+			}
+
+			ms := newMachineSet("machineset1", "test-cluster")
+			ms.Spec.Replicas = pointer.Int32Ptr(1)
+
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(testCluster, ms, node).Build()
+			msr := &MachineSetReconciler{
+				Client:  c,
+				Tracker: remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(testCluster)),
+			}
+			g.Expect(msr.updateStatus(ctx, testCluster, ms, []*clusterv1.Machine{machine})).To(Succeed())
+
+			wantReplicas := int32(0)
+			if tt.wantReady {
+				wantReplicas = 1
+			}
+			g.Expect(ms.Status.ReadyReplicas).To(Equal(wantReplicas))
+			g.Expect(ms.Status.AvailableReplicas).To(Equal(wantReplicas))
+		})
+	}
+}
+
 func newMachineSet(name, cluster string) *clusterv1.MachineSet {
 	var replicas int32
 	return &clusterv1.MachineSet{
@@ -793,3 +1117,136 @@ func newMachineSet(name, cluster string) *clusterv1.MachineSet {
 		},
 	}
 }
+
+func TestPickMachineFailureDomain(t *testing.T) {
+	machinesIn := func(counts map[string]int) []*clusterv1.Machine {
+		var machines []*clusterv1.Machine
+		for fd, count := range counts {
+			for i := 0; i < count; i++ {
+				machines = append(machines, &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%d", fd, i)},
+					Spec:       clusterv1.MachineSpec{FailureDomain: pointer.StringPtr(fd)},
+				})
+			}
+		}
+		return machines
+	}
+
+	testCases := []struct {
+		name           string
+		failureDomains clusterv1.FailureDomains
+		existing       map[string]int
+		deleting       map[string]int
+		newMachines    int
+		expected       map[string]int
+	}{
+		{
+			name:        "no failure domains",
+			existing:    map[string]int{},
+			newMachines: 2,
+			expected:    map[string]int{"": 2},
+		},
+		{
+			name: "spread new machines evenly",
+			failureDomains: clusterv1.FailureDomains{
+				"a": {},
+				"b": {},
+				"c": {},
+			},
+			existing:    map[string]int{},
+			newMachines: 6,
+			expected:    map[string]int{"a": 2, "b": 2, "c": 2},
+		},
+		{
+			name: "fill the least loaded failure domains first with an uneven distribution",
+			failureDomains: clusterv1.FailureDomains{
+				"a": {},
+				"b": {},
+				"c": {},
+			},
+			existing:    map[string]int{"a": 3, "b": 1},
+			newMachines: 5,
+			expected:    map[string]int{"a": 3, "b": 3, "c": 3},
+		},
+		{
+			name: "ignore machines being deleted",
+			failureDomains: clusterv1.FailureDomains{
+				"a": {},
+				"b": {},
+			},
+			existing:    map[string]int{"a": 1},
+			deleting:    map[string]int{"b": 2},
+			newMachines: 1,
+			expected:    map[string]int{"a": 1, "b": 1},
+		},
+		{
+			name: "only use failure domains not suitable for control plane machines",
+			failureDomains: clusterv1.FailureDomains{
+				"a": {ControlPlane: true},
+				"b": {},
+				"c": {},
+			},
+			existing:    map[string]int{"b": 2},
+			newMachines: 2,
+			expected:    map[string]int{"b": 2, "c": 2},
+		},
+		{
+			name: "do not assign control plane failure domains when there are no others",
+			failureDomains: clusterv1.FailureDomains{
+				"a": {ControlPlane: true},
+				"b": {ControlPlane: true},
+			},
+			existing:    map[string]int{"a": 1},
+			newMachines: 3,
+			expected:    map[string]int{"a": 1, "": 3},
+		},
+		{
+			name: "spread new machines according to the weights of the failure domains",
+			failureDomains: clusterv1.FailureDomains{
+				"a": {Weight: pointer.Int32Ptr(2)},
+				"b": {},
+			},
+			existing:    map[string]int{},
+			newMachines: 6,
+			expected:    map[string]int{"a": 4, "b": 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				Status: clusterv1.ClusterStatus{
+					FailureDomains: tc.failureDomains,
+				},
+			}
+			machines := machinesIn(tc.existing)
+			for _, m := range machinesIn(tc.deleting) {
+				m.Name = "deleting-" + m.Name
+				m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				machines = append(machines, m)
+			}
+
+			for i := 0; i < tc.newMachines; i++ {
+				machines = append(machines, &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("new-%d", i)},
+					Spec:       clusterv1.MachineSpec{FailureDomain: pickMachineFailureDomain(cluster, machines)},
+				})
+			}
+
+			got := map[string]int{}
+			for _, m := range machines {
+				if !m.DeletionTimestamp.IsZero() {
+					continue
+				}
+				fd := ""
+				if m.Spec.FailureDomain != nil {
+					fd = *m.Spec.FailureDomain
+				}
+				got[fd]++
+			}
+			g.Expect(got).To(Equal(tc.expected))
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 type (
@@ -39,60 +40,78 @@ const (
 	secondsPerTenDays float64 = 864000
 )
 
-// maps the creation timestamp onto the 0-100 priority range
-func oldestDeletePriority(machine *clusterv1.Machine) deletePriority {
-	if !machine.DeletionTimestamp.IsZero() {
-		return mustDelete
-	}
-	if _, ok := machine.ObjectMeta.Annotations[clusterv1.DeleteMachineAnnotation]; ok {
-		return mustDelete
-	}
+// Machines are prioritized for deletion in the following order, regardless of the delete policy:
+// 1. Machines being deleted or with the DeleteMachineAnnotation (mustDelete).
+// 2. Machines that are unhealthy: without a NodeRef, with a failure reason or message, or
+//    failing a MachineHealthCheck (betterDelete).
+// 3. All the other Machines, ordered according to the delete policy (at most couldDelete).
+
+// isMachineUnhealthy returns true if the Machine has no Node yet, has failed, or has been
+// reported as unhealthy by a MachineHealthCheck.
+func isMachineUnhealthy(machine *clusterv1.Machine) bool {
 	if machine.Status.NodeRef == nil {
-		return mustDelete
+		return true
 	}
 	if machine.Status.FailureReason != nil || machine.Status.FailureMessage != nil {
-		return mustDelete
-	}
-	if machine.ObjectMeta.CreationTimestamp.Time.IsZero() {
-		return mustNotDelete
-	}
-	d := metav1.Now().Sub(machine.ObjectMeta.CreationTimestamp.Time)
-	if d.Seconds() < 0 {
-		return mustNotDelete
+		return true
 	}
-	return deletePriority(float64(mustDelete) * (1.0 - math.Exp(-d.Seconds()/secondsPerTenDays)))
+	return conditions.IsFalse(machine, clusterv1.MachineHealthCheckSuccededCondition)
 }
 
-func newestDeletePriority(machine *clusterv1.Machine) deletePriority {
+// mustDeleteMachine returns true if the Machine is being deleted or has been explicitly
+// marked for deletion with the DeleteMachineAnnotation.
+func mustDeleteMachine(machine *clusterv1.Machine) bool {
 	if !machine.DeletionTimestamp.IsZero() {
-		return mustDelete
+		return true
 	}
-	if _, ok := machine.ObjectMeta.Annotations[clusterv1.DeleteMachineAnnotation]; ok {
+	_, ok := machine.ObjectMeta.Annotations[clusterv1.DeleteMachineAnnotation]
+	return ok
+}
+
+// oldestDeletePriority prioritizes the oldest Machines, after the ones that must be deleted and the unhealthy ones.
+func oldestDeletePriority(machine *clusterv1.Machine) deletePriority {
+	if mustDeleteMachine(machine) {
 		return mustDelete
 	}
-	if machine.Status.NodeRef == nil {
-		return mustDelete
+	if isMachineUnhealthy(machine) {
+		return betterDelete
 	}
-	if machine.Status.FailureReason != nil || machine.Status.FailureMessage != nil {
+	return machineAgeDeletePriority(machine)
+}
+
+// newestDeletePriority prioritizes the newest Machines, after the ones that must be deleted and the unhealthy ones.
+func newestDeletePriority(machine *clusterv1.Machine) deletePriority {
+	if mustDeleteMachine(machine) {
 		return mustDelete
 	}
-	return mustDelete - oldestDeletePriority(machine)
+	if isMachineUnhealthy(machine) {
+		return betterDelete
+	}
+	return couldDelete - machineAgeDeletePriority(machine)
 }
 
+// randomDeletePolicy does not prioritize any Machine, after the ones that must be deleted and the unhealthy ones.
 func randomDeletePolicy(machine *clusterv1.Machine) deletePriority {
-	if !machine.DeletionTimestamp.IsZero() {
+	if mustDeleteMachine(machine) {
 		return mustDelete
 	}
-	if _, ok := machine.ObjectMeta.Annotations[clusterv1.DeleteMachineAnnotation]; ok {
+	if isMachineUnhealthy(machine) {
 		return betterDelete
 	}
-	if machine.Status.NodeRef == nil {
-		return betterDelete
+	return couldDelete
+}
+
+// machineAgeDeletePriority maps the creation timestamp of the Machine onto the
+// mustNotDelete-couldDelete priority range, the older the Machine the higher the priority.
+func machineAgeDeletePriority(machine *clusterv1.Machine) deletePriority {
+	if machine.ObjectMeta.CreationTimestamp.Time.IsZero() {
+		return mustNotDelete
 	}
-	if machine.Status.FailureReason != nil || machine.Status.FailureMessage != nil {
-		return betterDelete
+	d := metav1.Now().Sub(machine.ObjectMeta.CreationTimestamp.Time)
+	if d.Seconds() < 0 {
+		return mustNotDelete
 	}
-	return couldDelete
+	return deletePriority(float64(couldDelete) * (1.0 - math.Exp(-d.Seconds()/secondsPerTenDays)))
 }
 
 type sortableMachines struct {
@@ -44,6 +44,14 @@ func TestMachineToDelete(t *testing.T) {
 		Status:     clusterv1.MachineStatus{NodeRef: nodeRef},
 	}
 	deleteMachineWithoutNodeRef := &clusterv1.Machine{}
+	deleteMachineWithFailedHealthCheck := &clusterv1.Machine{
+		Status: clusterv1.MachineStatus{
+			NodeRef: nodeRef,
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.MachineHealthCheckSuccededCondition, Status: corev1.ConditionFalse},
+			},
+		},
+	}
 
 	tests := []struct {
 		desc     string
@@ -174,6 +182,30 @@ func TestMachineToDelete(t *testing.T) {
 				deleteMachineWithoutNodeRef,
 			},
 		},
+		{
+			desc: "func=randomDeletePolicy, DeleteMachineAnnotation before unhealthy, diff=1",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				betterDeleteMachine,
+				healthyMachine,
+				deleteMachineWithMachineAnnotation,
+			},
+			expect: []*clusterv1.Machine{
+				deleteMachineWithMachineAnnotation,
+			},
+		},
+		{
+			desc: "func=randomDeletePolicy, MachineWithFailedHealthCheck, diff=1",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				healthyMachine,
+				deleteMachineWithFailedHealthCheck,
+				healthyMachine,
+			},
+			expect: []*clusterv1.Machine{
+				deleteMachineWithFailedHealthCheck,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -221,6 +253,19 @@ func TestMachineNewestDelete(t *testing.T) {
 	deleteMachineWithoutNodeRef := &clusterv1.Machine{
 		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -1))},
 	}
+	newestUnhealthyMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time)},
+		Status:     clusterv1.MachineStatus{FailureReason: &statusError, NodeRef: nodeRef},
+	}
+	deleteMachineWithFailedHealthCheck := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -10))},
+		Status: clusterv1.MachineStatus{
+			NodeRef: nodeRef,
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.MachineHealthCheckSuccededCondition, Status: corev1.ConditionFalse},
+			},
+		},
+	}
 
 	tests := []struct {
 		desc     string
@@ -276,6 +321,38 @@ func TestMachineNewestDelete(t *testing.T) {
 			},
 			expect: []*clusterv1.Machine{unhealthyMachine},
 		},
+		{
+			desc: "func=newestDeletePriority, diff=1 (unhealthy before newest)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				new, oldest, newest, old, unhealthyMachine,
+			},
+			expect: []*clusterv1.Machine{unhealthyMachine},
+		},
+		{
+			desc: "func=newestDeletePriority, diff=1 (DeleteMachineAnnotation before newer unhealthy)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				newestUnhealthyMachine, newest, deleteMachineWithMachineAnnotation,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithMachineAnnotation},
+		},
+		{
+			desc: "func=newestDeletePriority, diff=3 (DeleteMachineAnnotation, unhealthy, newest)",
+			diff: 3,
+			machines: []*clusterv1.Machine{
+				old, newest, newestUnhealthyMachine, new, deleteMachineWithMachineAnnotation,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithMachineAnnotation, newestUnhealthyMachine, newest},
+		},
+		{
+			desc: "func=newestDeletePriority, diff=1 (deleteMachineWithFailedHealthCheck)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				new, oldest, old, newest, deleteMachineWithFailedHealthCheck,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithFailedHealthCheck},
+		},
 	}
 
 	for _, test := range tests {
@@ -322,6 +399,19 @@ func TestMachineOldestDelete(t *testing.T) {
 	deleteMachineWithoutNodeRef := &clusterv1.Machine{
 		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -10))},
 	}
+	newestUnhealthyMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time)},
+		Status:     clusterv1.MachineStatus{FailureReason: &statusError, NodeRef: nodeRef},
+	}
+	deleteMachineWithFailedHealthCheck := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -1))},
+		Status: clusterv1.MachineStatus{
+			NodeRef: nodeRef,
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.MachineHealthCheckSuccededCondition, Status: corev1.ConditionFalse},
+			},
+		},
+	}
 
 	tests := []struct {
 		desc     string
@@ -385,6 +475,30 @@ func TestMachineOldestDelete(t *testing.T) {
 			},
 			expect: []*clusterv1.Machine{unhealthyMachine},
 		},
+		{
+			desc: "func=oldestDeletePriority, diff=1 (newer unhealthy before oldest)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				new, oldest, old, newest, newestUnhealthyMachine,
+			},
+			expect: []*clusterv1.Machine{newestUnhealthyMachine},
+		},
+		{
+			desc: "func=oldestDeletePriority, diff=3 (DeleteMachineAnnotation, unhealthy, oldest)",
+			diff: 3,
+			machines: []*clusterv1.Machine{
+				new, newestUnhealthyMachine, oldest, newest, deleteMachineWithMachineAnnotation,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithMachineAnnotation, newestUnhealthyMachine, oldest},
+		},
+		{
+			desc: "func=oldestDeletePriority, diff=1 (deleteMachineWithFailedHealthCheck)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				empty, new, oldest, old, newest, deleteMachineWithFailedHealthCheck,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithFailedHealthCheck},
+		},
 	}
 
 	for _, test := range tests {
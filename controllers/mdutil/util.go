@@ -30,6 +30,7 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/integer"
@@ -140,11 +141,12 @@ func Revision(obj runtime.Object) (int64, error) {
 }
 
 var annotationsToSkip = map[string]bool{
-	corev1.LastAppliedConfigAnnotation:  true,
-	clusterv1.RevisionAnnotation:        true,
-	clusterv1.RevisionHistoryAnnotation: true,
-	clusterv1.DesiredReplicasAnnotation: true,
-	clusterv1.MaxReplicasAnnotation:     true,
+	corev1.LastAppliedConfigAnnotation:     true,
+	clusterv1.RevisionAnnotation:           true,
+	clusterv1.RevisionHistoryAnnotation:    true,
+	clusterv1.RollbackToRevisionAnnotation: true,
+	clusterv1.DesiredReplicasAnnotation:    true,
+	clusterv1.MaxReplicasAnnotation:        true,
 
 	// Exclude the conversion annotation, to avoid infinite loops between the conversion webhook
 	// and the MachineDeployment controller syncing the annotations between a MachineDeployment
@@ -182,6 +184,11 @@ func copyDeploymentAnnotationsToMachineSet(deployment *clusterv1.MachineDeployme
 	return msAnnotationsChanged
 }
 
+// GetDesiredReplicasAnnotation returns the number of desired replicas recorded on the machine set.
+func GetDesiredReplicasAnnotation(ms *clusterv1.MachineSet, logger logr.Logger) (int32, bool) {
+	return getIntFromAnnotation(ms, clusterv1.DesiredReplicasAnnotation, logger)
+}
+
 func getMaxReplicasAnnotation(ms *clusterv1.MachineSet, logger logr.Logger) (int32, bool) {
 	return getIntFromAnnotation(ms, clusterv1.MaxReplicasAnnotation, logger)
 }
@@ -413,7 +420,95 @@ func EqualMachineTemplate(template1, template2 *clusterv1.MachineTemplateSpec) b
 	return apiequality.Semantic.DeepEqual(t1Copy, t2Copy)
 }
 
-// FindNewMachineSet returns the new MS this given deployment targets (the one with the same machine template).
+// EqualMachineTemplateIgnoringInPlaceFields returns true if two given machineTemplateSpec are equal,
+// ignoring the diff in the fields which are propagated in place to the machines, i.e. their labels and annotations
+// and the node drain and volume detach timeouts, and the version from external references.
+func EqualMachineTemplateIgnoringInPlaceFields(template1, template2 *clusterv1.MachineTemplateSpec) bool {
+	t1Copy := template1.DeepCopy()
+	t2Copy := template2.DeepCopy()
+
+	t1Copy.Labels, t1Copy.Annotations = nil, nil
+	t2Copy.Labels, t2Copy.Annotations = nil, nil
+	t1Copy.Spec.NodeDrainTimeout, t1Copy.Spec.NodeVolumeDetachTimeout = nil, nil
+	t2Copy.Spec.NodeDrainTimeout, t2Copy.Spec.NodeVolumeDetachTimeout = nil, nil
+
+	return EqualMachineTemplate(t1Copy, t2Copy)
+}
+
+// MachineSetUpToDate returns true if the given machine set can serve the machine template of the deployment,
+// either because the templates are equal or because they differ only in fields propagated in place, i.e. labels,
+// annotations and timeouts; such changes do not require a rollout as long as the deployment selector did not change
+// and the new labels still match the machine set selector.
+func MachineSetUpToDate(ms *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) bool {
+	if EqualMachineTemplate(&ms.Spec.Template, &deployment.Spec.Template) {
+		return true
+	}
+	if !EqualMachineTemplateIgnoringInPlaceFields(&ms.Spec.Template, &deployment.Spec.Template) {
+		return false
+	}
+
+	msSelector := ms.Spec.Selector.DeepCopy()
+	delete(msSelector.MatchLabels, DefaultMachineDeploymentUniqueLabelKey)
+	if !apiequality.Semantic.DeepEqual(msSelector, &deployment.Spec.Selector) {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&ms.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	templateLabels := deployment.Spec.Template.Labels
+	if hash, ok := ms.Spec.Template.Labels[DefaultMachineDeploymentUniqueLabelKey]; ok {
+		templateLabels = CloneAndAddLabel(templateLabels, DefaultMachineDeploymentUniqueLabelKey, hash)
+	}
+	return selector.Matches(labels.Set(templateLabels))
+}
+
+// SetMachineSetTemplateMetadata copies the labels and annotations of the deployment's machine template into the
+// machine template of the given machine set, preserving its machine-template-hash label.
+// Returns true if the machine set has been changed.
+func SetMachineSetTemplateMetadata(deployment *clusterv1.MachineDeployment, ms *clusterv1.MachineSet) bool {
+	templateLabels := deployment.Spec.Template.Labels
+	if hash, ok := ms.Spec.Template.Labels[DefaultMachineDeploymentUniqueLabelKey]; ok {
+		templateLabels = CloneAndAddLabel(templateLabels, DefaultMachineDeploymentUniqueLabelKey, hash)
+	}
+	templateAnnotations := deployment.Spec.Template.Annotations
+
+	if apiequality.Semantic.DeepEqual(ms.Spec.Template.Labels, templateLabels) &&
+		apiequality.Semantic.DeepEqual(ms.Spec.Template.Annotations, templateAnnotations) {
+		return false
+	}
+
+	ms.Spec.Template.Labels = make(map[string]string, len(templateLabels))
+	for k, v := range templateLabels {
+		ms.Spec.Template.Labels[k] = v
+	}
+	ms.Spec.Template.Annotations = nil
+	if templateAnnotations != nil {
+		ms.Spec.Template.Annotations = make(map[string]string, len(templateAnnotations))
+		for k, v := range templateAnnotations {
+			ms.Spec.Template.Annotations[k] = v
+		}
+	}
+	return true
+}
+
+// SetMachineSetTemplateTimeouts copies the node drain and volume detach timeouts of the deployment's machine template
+// into the machine template of the given machine set.
+// Returns true if the machine set has been changed.
+func SetMachineSetTemplateTimeouts(deployment *clusterv1.MachineDeployment, ms *clusterv1.MachineSet) bool {
+	if apiequality.Semantic.DeepEqual(ms.Spec.Template.Spec.NodeDrainTimeout, deployment.Spec.Template.Spec.NodeDrainTimeout) &&
+		apiequality.Semantic.DeepEqual(ms.Spec.Template.Spec.NodeVolumeDetachTimeout, deployment.Spec.Template.Spec.NodeVolumeDetachTimeout) {
+		return false
+	}
+
+	ms.Spec.Template.Spec.NodeDrainTimeout = deployment.Spec.Template.Spec.NodeDrainTimeout.DeepCopy()
+	ms.Spec.Template.Spec.NodeVolumeDetachTimeout = deployment.Spec.Template.Spec.NodeVolumeDetachTimeout.DeepCopy()
+	return true
+}
+
+// FindNewMachineSet returns the new MS this given deployment targets (the one with the same machine template,
+// or with a machine template differing only in fields which can be updated in place).
 func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) *clusterv1.MachineSet {
 	sort.Sort(MachineSetsByCreationTimestamp(msList))
 	for i := range msList {
@@ -425,6 +520,13 @@ func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*cluste
 			return msList[i]
 		}
 	}
+	// Otherwise choose the newest MachineSet which differs only in fields which can be updated in place,
+	// given that it is the one the deployment most recently rolled out to.
+	for i := len(msList) - 1; i >= 0; i-- {
+		if MachineSetUpToDate(msList[i], deployment) {
+			return msList[i]
+		}
+	}
 	// new MachineSet does not exist.
 	return nil
 }
@@ -488,6 +590,18 @@ func TotalMachineSetsReplicaSum(machineSets []*clusterv1.MachineSet) int32 {
 	return totalReplicas
 }
 
+// GetDeletingMachineCount gets the number of machines that are in the process of being deleted
+// in a machineList.
+func GetDeletingMachineCount(machineList *clusterv1.MachineList) int32 {
+	var deletingMachineCount int32
+	for _, machine := range machineList.Items {
+		if !machine.GetDeletionTimestamp().IsZero() {
+			deletingMachineCount++
+		}
+	}
+	return deletingMachineCount
+}
+
 // GetReadyReplicaCountForMachineSets returns the number of ready machines corresponding to the given machine sets.
 func GetReadyReplicaCountForMachineSets(machineSets []*clusterv1.MachineSet) int32 {
 	totalReadyReplicas := int32(0)
@@ -548,6 +662,17 @@ func NewMSNewReplicas(deployment *clusterv1.MachineDeployment, allMSs []*cluster
 		// Do not exceed the number of desired replicas.
 		scaleUpCount = integer.Int32Min(scaleUpCount, *(deployment.Spec.Replicas)-*(newMS.Spec.Replicas))
 		return *(newMS.Spec.Replicas) + scaleUpCount, nil
+	case clusterv1.OnDeleteMachineDeploymentStrategyType:
+		// Find the total number of machines
+		currentMachineCount := TotalMachineSetsReplicaSum(allMSs)
+		if currentMachineCount >= *(deployment.Spec.Replicas) {
+			// Cannot scale up as more replicas exist than desired number of replicas in the MachineDeployment.
+			return *(newMS.Spec.Replicas), nil
+		}
+		// Scale up the latest MachineSet so the total amount of replicas across all MachineSets match
+		// the desired number of replicas in the MachineDeployment
+		scaleUpCount := *(deployment.Spec.Replicas) - currentMachineCount
+		return *(newMS.Spec.Replicas) + scaleUpCount, nil
 	default:
 		return 0, fmt.Errorf("deployment strategy %v isn't supported", deployment.Spec.Strategy.Type)
 	}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/klog/klogr"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
@@ -286,6 +287,17 @@ func TestFindNewMachineSet(t *testing.T) {
 	oldMS := generateMS(oldDeployment)
 	oldMS.Status.FullyLabeledReplicas = *(oldMS.Spec.Replicas)
 
+	// MachineSets which differ from the Deployment only in their template metadata.
+	metadataMS := generateMS(deployment)
+	metadataMS.Labels[DefaultMachineDeploymentUniqueLabelKey] = "metadata-hash"
+	metadataMS.Spec.Template.Annotations = map[string]string{"foo": "bar"}
+	metadataMS.CreationTimestamp = now
+
+	newerMetadataMS := generateMS(deployment)
+	newerMetadataMS.Labels[DefaultMachineDeploymentUniqueLabelKey] = "newer-metadata-hash"
+	newerMetadataMS.Spec.Template.Annotations = map[string]string{"foo": "baz"}
+	newerMetadataMS.CreationTimestamp = later
+
 	tests := []struct {
 		Name       string
 		deployment clusterv1.MachineDeployment
@@ -310,6 +322,18 @@ func TestFindNewMachineSet(t *testing.T) {
 			msList:     []*clusterv1.MachineSet{&oldMS},
 			expected:   nil,
 		},
+		{
+			Name:       "Get the MachineSet with the same template over a newer MachineSet differing only in metadata",
+			deployment: deployment,
+			msList:     []*clusterv1.MachineSet{&newerMetadataMS, &oldMS, &newMS},
+			expected:   &newMS,
+		},
+		{
+			Name:       "Get the newest MachineSet when there are more than one MachineSet differing only in metadata",
+			deployment: deployment,
+			msList:     []*clusterv1.MachineSet{&metadataMS, &oldMS, &newerMetadataMS},
+			expected:   &newerMetadataMS,
+		},
 	}
 
 	for _, test := range tests {
@@ -528,6 +552,16 @@ func TestNewMSNewReplicas(t *testing.T) {
 			clusterv1.RollingUpdateMachineDeploymentStrategyType,
 			6, 2, 10, 6,
 		},
+		{
+			"on delete can not scale up - to newMSReplicas",
+			clusterv1.OnDeleteMachineDeploymentStrategyType,
+			1, 5, 0, 5,
+		},
+		{
+			"on delete scale up - replace deleted machines",
+			clusterv1.OnDeleteMachineDeploymentStrategyType,
+			8, 2, 0, 5,
+		},
 	}
 	newDeployment := generateDeployment("nginx")
 	newRC := generateMS(newDeployment)
@@ -701,7 +735,7 @@ func TestMaxUnavailable(t *testing.T) {
 	}
 }
 
-//Set of simple tests for annotation related util functions
+// Set of simple tests for annotation related util functions
 func TestAnnotationUtils(t *testing.T) {
 	//Setup
 	tDeployment := generateDeployment("nginx")
@@ -817,3 +851,142 @@ func TestReplicasAnnotationsNeedUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineSetUpToDate(t *testing.T) {
+	deployment := generateDeployment("nginx")
+	deployment.Spec.Template.Annotations = map[string]string{"foo": "bar"}
+
+	newMachineSet := func() *clusterv1.MachineSet {
+		ms := generateMS(deployment)
+		ms.Spec.Template.Labels = CloneAndAddLabel(deployment.Spec.Template.Labels, DefaultMachineDeploymentUniqueLabelKey, "hash")
+		ms.Spec.Selector = *CloneSelectorAndAddLabel(&deployment.Spec.Selector, DefaultMachineDeploymentUniqueLabelKey, "hash")
+		return &ms
+	}
+
+	tests := []struct {
+		Name     string
+		modify   func(d *clusterv1.MachineDeployment)
+		expected bool
+	}{
+		{
+			Name:     "Same template",
+			modify:   func(d *clusterv1.MachineDeployment) {},
+			expected: true,
+		},
+		{
+			Name: "Label added to the template",
+			modify: func(d *clusterv1.MachineDeployment) {
+				d.Spec.Template.Labels = CloneAndAddLabel(d.Spec.Template.Labels, "new-label", "true")
+			},
+			expected: true,
+		},
+		{
+			Name: "Annotation changed in the template",
+			modify: func(d *clusterv1.MachineDeployment) {
+				d.Spec.Template.Annotations = map[string]string{"foo": "baz"}
+			},
+			expected: true,
+		},
+		{
+			Name: "Spec changed in the template",
+			modify: func(d *clusterv1.MachineDeployment) {
+				d.Spec.Template.Spec.Version = pointer.StringPtr("v1.19.1")
+			},
+			expected: false,
+		},
+		{
+			Name: "Timeouts changed in the template",
+			modify: func(d *clusterv1.MachineDeployment) {
+				d.Spec.Template.Spec.NodeDrainTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+				d.Spec.Template.Spec.NodeVolumeDetachTimeout = &metav1.Duration{Duration: 5 * time.Minute}
+			},
+			expected: true,
+		},
+		{
+			Name: "Spec and timeouts changed in the template",
+			modify: func(d *clusterv1.MachineDeployment) {
+				d.Spec.Template.Spec.NodeDrainTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+				d.Spec.Template.Spec.Version = pointer.StringPtr("v1.19.1")
+			},
+			expected: false,
+		},
+		{
+			Name: "Spec and labels changed in the template",
+			modify: func(d *clusterv1.MachineDeployment) {
+				d.Spec.Template.Labels = CloneAndAddLabel(d.Spec.Template.Labels, "new-label", "true")
+				d.Spec.Template.Spec.Version = pointer.StringPtr("v1.19.1")
+			},
+			expected: false,
+		},
+		{
+			Name: "Selector changed together with the template labels",
+			modify: func(d *clusterv1.MachineDeployment) {
+				d.Spec.Selector.MatchLabels = map[string]string{"name": "apache"}
+				d.Spec.Template.Labels = map[string]string{"name": "apache"}
+			},
+			expected: false,
+		},
+		{
+			Name: "Label required by the selector removed from the template",
+			modify: func(d *clusterv1.MachineDeployment) {
+				d.Spec.Template.Labels = map[string]string{"other": "label"}
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			d := deployment.DeepCopy()
+			test.modify(d)
+			g.Expect(MachineSetUpToDate(newMachineSet(), d)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestSetMachineSetTemplateMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := generateDeployment("nginx")
+	ms := generateMS(deployment)
+	ms.Spec.Template.Labels = CloneAndAddLabel(deployment.Spec.Template.Labels, DefaultMachineDeploymentUniqueLabelKey, "hash")
+
+	// Nothing to change when the metadata is the same.
+	g.Expect(SetMachineSetTemplateMetadata(&deployment, &ms)).To(BeFalse())
+
+	deployment.Spec.Template.Labels = map[string]string{"name": "nginx", "new-label": "true"}
+	deployment.Spec.Template.Annotations = map[string]string{"foo": "bar"}
+	g.Expect(SetMachineSetTemplateMetadata(&deployment, &ms)).To(BeTrue())
+	g.Expect(ms.Spec.Template.Labels).To(Equal(map[string]string{
+		"name":                                 "nginx",
+		"new-label":                            "true",
+		DefaultMachineDeploymentUniqueLabelKey: "hash",
+	}))
+	g.Expect(ms.Spec.Template.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+
+	// The deployment template must not be changed.
+	g.Expect(deployment.Spec.Template.Labels).NotTo(HaveKey(DefaultMachineDeploymentUniqueLabelKey))
+}
+
+func TestSetMachineSetTemplateTimeouts(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := generateDeployment("nginx")
+	ms := generateMS(deployment)
+
+	// Nothing to change when the timeouts are the same.
+	g.Expect(SetMachineSetTemplateTimeouts(&deployment, &ms)).To(BeFalse())
+
+	deployment.Spec.Template.Spec.NodeDrainTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+	g.Expect(SetMachineSetTemplateTimeouts(&deployment, &ms)).To(BeTrue())
+	g.Expect(ms.Spec.Template.Spec.NodeDrainTimeout).To(Equal(&metav1.Duration{Duration: 10 * time.Minute}))
+	g.Expect(ms.Spec.Template.Spec.NodeVolumeDetachTimeout).To(BeNil())
+	g.Expect(SetMachineSetTemplateTimeouts(&deployment, &ms)).To(BeFalse())
+
+	// A timeout removed from the deployment template is removed from the machine set template.
+	deployment.Spec.Template.Spec.NodeDrainTimeout = nil
+	g.Expect(SetMachineSetTemplateTimeouts(&deployment, &ms)).To(BeTrue())
+	g.Expect(ms.Spec.Template.Spec.NodeDrainTimeout).To(BeNil())
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// machineProvisionDurationSeconds is a prometheus metric which tracks how long Machines take to become Running,
+	// from when they entered the Provisioning phase or, if they skipped it, from their creation.
+	machineProvisionDurationSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "capi_machine_provision_duration_seconds",
+		Help:    "Duration in seconds of the provisioning of Machines until they are Running",
+		Buckets: []float64{30, 60, 120, 180, 300, 450, 600, 900, 1200, 1800, 3600},
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		machineProvisionDurationSeconds,
+	)
+}
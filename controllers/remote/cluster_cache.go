@@ -48,24 +48,80 @@ const (
 	ClusterCacheControllerName    = "cluster-cache-tracker"
 )
 
+// rebuildAccessorBackoff is the backoff used to rebuild the clusterAccessor of an evicted cluster.
+var rebuildAccessorBackoff = wait.Backoff{
+	Duration: 1 * time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+	Cap:      1 * time.Minute,
+}
+
 // ClusterCacheTracker manages client caches for workload clusters.
 type ClusterCacheTracker struct {
-	log    logr.Logger
-	client client.Client
-	scheme *runtime.Scheme
+	log     logr.Logger
+	client  client.Client
+	scheme  *runtime.Scheme
+	options ClusterCacheTrackerOptions
+
+	// ctx is cancelled when the manager running the tracker stops, so the clusterAccessors rebuilt by the
+	// tracker itself, and their goroutines, are stopped on shutdown.
+	ctx context.Context
 
 	lock             sync.RWMutex
 	clusterAccessors map[client.ObjectKey]*clusterAccessor
 }
 
+// ClusterCacheTrackerOptions defines the options to configure the ClusterCacheTracker.
+type ClusterCacheTrackerOptions struct {
+	// ClientQPS is the maximum queries per second from the clients and caches created for the workload clusters.
+	// If zero, the client-go default is used.
+	ClientQPS float32
+
+	// ClientBurst is the maximum burst for throttling the clients and caches created for the workload clusters.
+	// If zero, the client-go default is used.
+	ClientBurst int
+}
+
 // NewClusterCacheTracker creates a new ClusterCacheTracker.
 func NewClusterCacheTracker(log logr.Logger, manager ctrl.Manager) (*ClusterCacheTracker, error) {
-	return &ClusterCacheTracker{
+	return NewClusterCacheTrackerWithOptions(log, manager, ClusterCacheTrackerOptions{})
+}
+
+// NewClusterCacheTrackerWithOptions creates a new ClusterCacheTracker with the given options.
+func NewClusterCacheTrackerWithOptions(log logr.Logger, manager ctrl.Manager, options ClusterCacheTrackerOptions) (*ClusterCacheTracker, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &ClusterCacheTracker{
 		log:              log,
 		client:           manager.GetClient(),
 		scheme:           manager.GetScheme(),
+		options:          options,
+		ctx:              ctx,
 		clusterAccessors: make(map[client.ObjectKey]*clusterAccessor),
-	}, nil
+	}
+
+	// Stop the tracker's context when the manager stops.
+	if err := manager.Add(trackerStopper(cancel)); err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "failed to add ClusterCacheTracker to the manager")
+	}
+
+	return t, nil
+}
+
+// trackerStopper cancels the context of a ClusterCacheTracker once the manager it has been added to stops.
+type trackerStopper context.CancelFunc
+
+// Start blocks until ctx is done and then cancels the tracker's context.
+func (s trackerStopper) Start(ctx context.Context) error {
+	<-ctx.Done()
+	s()
+	return nil
+}
+
+// NeedLeaderElection returns false, so the tracker is stopped on shutdown also when not elected as leader.
+func (s trackerStopper) NeedLeaderElection() bool {
+	return false
 }
 
 // GetClient returns a cached client for the given cluster.
@@ -102,9 +158,11 @@ func (t *ClusterCacheTracker) clusterAccessorExists(cluster client.ObjectKey) bo
 func (t *ClusterCacheTracker) getClusterAccessorLH(ctx context.Context, cluster client.ObjectKey) (*clusterAccessor, error) {
 	a := t.clusterAccessors[cluster]
 	if a != nil {
+		clusterCacheHitsTotal.Inc()
 		return a, nil
 	}
 
+	clusterCacheMissesTotal.Inc()
 	a, err := t.newClusterAccessor(ctx, cluster)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating client and cache for remote cluster")
@@ -118,9 +176,9 @@ func (t *ClusterCacheTracker) getClusterAccessorLH(ctx context.Context, cluster
 // newClusterAccessor creates a new clusterAccessor.
 func (t *ClusterCacheTracker) newClusterAccessor(ctx context.Context, cluster client.ObjectKey) (*clusterAccessor, error) {
 	// Get a rest config for the remote cluster
-	config, err := RESTConfig(ctx, ClusterCacheControllerName, t.client, cluster)
+	config, err := t.restConfig(ctx, cluster)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error fetching REST client config for remote cluster %q", cluster.String())
+		return nil, err
 	}
 
 	// Create a mapper for it
@@ -181,6 +239,27 @@ func (t *ClusterCacheTracker) newClusterAccessor(ctx context.Context, cluster cl
 	}, nil
 }
 
+// restConfig returns the rest config used by the client and the cache for the remote cluster.
+func (t *ClusterCacheTracker) restConfig(ctx context.Context, cluster client.ObjectKey) (*rest.Config, error) {
+	config, err := RESTConfig(ctx, ClusterCacheControllerName, t.client, cluster)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error fetching REST client config for remote cluster %q", cluster.String())
+	}
+	t.ApplyClientOptions(config)
+	return config, nil
+}
+
+// ApplyClientOptions sets the QPS and burst configured for the tracker on the rest config of a remote cluster,
+// so clients created outside of the tracker are throttled the same way as the tracker's clients.
+func (t *ClusterCacheTracker) ApplyClientOptions(config *rest.Config) {
+	if t.options.ClientQPS > 0 {
+		config.QPS = t.options.ClientQPS
+	}
+	if t.options.ClientBurst > 0 {
+		config.Burst = t.options.ClientBurst
+	}
+}
+
 // deleteAccessor stops a clusterAccessor's cache and removes the clusterAccessor from the tracker.
 func (t *ClusterCacheTracker) deleteAccessor(cluster client.ObjectKey) {
 	t.lock.Lock()
@@ -200,6 +279,42 @@ func (t *ClusterCacheTracker) deleteAccessor(cluster client.ObjectKey) {
 	delete(t.clusterAccessors, cluster)
 }
 
+// rebuildAccessor creates a new clusterAccessor for a cluster whose clusterAccessor has been evicted, so the
+// connection to the cluster is re-established without waiting for the next caller. The clusterAccessor is
+// created without holding t.lock, retrying with backoff until the tracker is stopped; if the cluster is still
+// unreachable afterwards, the clusterAccessor is created again by the next caller.
+func (t *ClusterCacheTracker) rebuildAccessor(cluster client.ObjectKey) {
+	err := wait.ExponentialBackoffWithContext(t.ctx, rebuildAccessorBackoff, func() (bool, error) {
+		if t.clusterAccessorExists(cluster) {
+			// A caller has already created a new clusterAccessor.
+			return true, nil
+		}
+
+		// The context of the evicted clusterAccessor has been cancelled, so the tracker's context is used;
+		// the new clusterAccessor lives until it is deleted or the tracker is stopped.
+		a, err := t.newClusterAccessor(t.ctx, cluster)
+		if err != nil {
+			t.log.V(4).Info("Failed to rebuild clusterAccessor, retrying", "cluster", cluster.String(), "err", err.Error())
+			return false, nil
+		}
+
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		if _, exists := t.clusterAccessors[cluster]; exists {
+			// A caller created a new clusterAccessor in the meantime, so stop the one just created.
+			a.cache.Stop()
+			return true, nil
+		}
+		t.clusterAccessors[cluster] = a
+		return true, nil
+	})
+	if err != nil {
+		t.log.V(2).Info("Failed to rebuild clusterAccessor, it will be rebuilt on the next access", "cluster", cluster.String(), "err", err.Error())
+		return
+	}
+	t.log.V(2).Info("Rebuilt clusterAccessor", "cluster", cluster.String())
+}
+
 // Watcher is a scoped-down interface from Controller that only knows how to watch.
 type Watcher interface {
 	// Watch watches src for changes, sending events to eventHandler if they pass predicates.
@@ -284,12 +399,14 @@ func (h *healthCheckInput) setDefaults() {
 
 // healthCheckCluster will poll the cluster's API at the path given and, if there are
 // `unhealthyThreshold` consecutive failures, will deem the cluster unhealthy.
-// Once the cluster is deemed unhealthy, the cluster's cache is stopped and removed.
+// Once the cluster is deemed unhealthy, the cluster's cache is stopped and removed, and then rebuilt
+// so a dead connection is not kept around; if the cluster is deleted, the cache is only removed.
 func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *healthCheckInput) {
 	// populate optional params for healthCheckInput
 	in.setDefaults()
 
 	unhealthyCount := 0
+	unhealthy := false
 
 	// This gets us a client that can make raw http(s) calls to the remote apiserver. We only need to create it once
 	// and we can reuse it inside the polling loop.
@@ -335,6 +452,7 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 
 		if unhealthyCount >= in.unhealthyThreshold {
 			// Cluster is now considered unhealthy.
+			unhealthy = true
 			return false, err
 		}
 
@@ -349,5 +467,9 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 	if err != nil && err != wait.ErrWaitTimeout {
 		t.log.Error(err, "Error health checking cluster", "cluster", in.cluster.String())
 		t.deleteAccessor(in.cluster)
+		if unhealthy {
+			clusterCacheEvictionsTotal.Inc()
+			t.rebuildAccessor(in.cluster)
+		}
 	}
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package remote
 
 import (
+	"context"
+
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -29,6 +31,7 @@ func NewTestClusterCacheTracker(log logr.Logger, cl client.Client, scheme *runti
 		log:              log,
 		client:           cl,
 		scheme:           scheme,
+		ctx:              context.Background(),
 		clusterAccessors: make(map[client.ObjectKey]*clusterAccessor),
 	}
 
@@ -20,10 +20,14 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -51,6 +55,12 @@ var _ = Describe("ClusterCache HealthCheck suite", func() {
 		var testPollTimeout = 50 * time.Millisecond
 		var testUnhealthyThreshold = 3
 
+		getAccessor := func() *clusterAccessor {
+			cct.lock.RLock()
+			defer cct.lock.RUnlock()
+			return cct.clusterAccessors[testClusterKey]
+		}
+
 		BeforeEach(func() {
 			By("Setting up a new manager")
 			var err error
@@ -100,6 +110,8 @@ var _ = Describe("ClusterCache HealthCheck suite", func() {
 		})
 
 		AfterEach(func() {
+			By("Deleting any rebuilt clusterAccessor")
+			cct.deleteAccessor(testClusterKey)
 			By("Deleting any Secrets")
 			Expect(cleanupTestSecrets(ctx, k8sClient)).To(Succeed())
 			By("Deleting any Clusters")
@@ -131,6 +143,9 @@ var _ = Describe("ClusterCache HealthCheck suite", func() {
 			ctx, cancel := context.WithCancel(ctx)
 			defer cancel()
 
+			accessor := getAccessor()
+			evictions := testutil.ToFloat64(clusterCacheEvictionsTotal)
+
 			// TODO(community): Fill in these field names.
 			go cct.healthCheckCluster(ctx,
 				&healthCheckInput{
@@ -142,14 +157,22 @@ var _ = Describe("ClusterCache HealthCheck suite", func() {
 					"/clusterAccessor",
 				})
 
-			// This should succeed after N consecutive failed requests.
-			Eventually(func() bool { return cct.clusterAccessorExists(testClusterKey) }, 2*time.Second, 100*time.Millisecond).Should(BeFalse())
+			// This should succeed after N consecutive failed requests; the evicted clusterAccessor is rebuilt
+			// from the cluster kubeconfig, which points to a healthy API server.
+			Eventually(func() bool {
+				a := getAccessor()
+				return a != nil && a != accessor
+			}, 5*time.Second, 100*time.Millisecond).Should(BeTrue())
+			Expect(testutil.ToFloat64(clusterCacheEvictionsTotal)).To(Equal(evictions + 1))
 		})
 
 		It("with an invalid config", func() {
 			ctx, cancel := context.WithCancel(ctx)
 			defer cancel()
 
+			accessor := getAccessor()
+			evictions := testutil.ToFloat64(clusterCacheEvictionsTotal)
+
 			// Set the host to a random free port on localhost
 			addr, err := net.ResolveTCPAddr("tcp", "localhost:0")
 			Expect(err).ToNot(HaveOccurred())
@@ -170,8 +193,85 @@ var _ = Describe("ClusterCache HealthCheck suite", func() {
 				"/",
 			})
 
-			// This should succeed after N consecutive failed requests.
-			Eventually(func() bool { return cct.clusterAccessorExists(testClusterKey) }, 2*time.Second, 100*time.Millisecond).Should(BeFalse())
+			// This should succeed after N consecutive failed requests; the evicted clusterAccessor is rebuilt
+			// from the cluster kubeconfig, which points to a healthy API server.
+			Eventually(func() bool {
+				a := getAccessor()
+				return a != nil && a != accessor
+			}, 5*time.Second, 100*time.Millisecond).Should(BeTrue())
+			Expect(testutil.ToFloat64(clusterCacheEvictionsTotal)).To(Equal(evictions + 1))
+		})
+
+		It("with a flaky API server failing intermittently", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			accessor := getAccessor()
+			evictions := testutil.ToFloat64(clusterCacheEvictionsTotal)
+
+			// Every other request fails, so the failures are never consecutive.
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1)%2 == 0 {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			go cct.healthCheckCluster(ctx, &healthCheckInput{
+				cluster:            testClusterKey,
+				cfg:                &rest.Config{Host: server.URL},
+				interval:           testPollInterval,
+				requestTimeout:     testPollTimeout,
+				unhealthyThreshold: testUnhealthyThreshold,
+				path:               "/",
+			})
+
+			Consistently(func() bool { return getAccessor() == accessor }, 2*time.Second, 100*time.Millisecond).Should(BeTrue())
+			Expect(atomic.LoadInt32(&requests)).To(BeNumerically(">", int32(testUnhealthyThreshold)))
+			Expect(testutil.ToFloat64(clusterCacheEvictionsTotal)).To(Equal(evictions))
+		})
+
+		It("with a flaky API server going down", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			accessor := getAccessor()
+			evictions := testutil.ToFloat64(clusterCacheEvictionsTotal)
+
+			// The API server is healthy at first, then all requests fail.
+			var failing int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.LoadInt32(&failing) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			go cct.healthCheckCluster(ctx, &healthCheckInput{
+				cluster:            testClusterKey,
+				cfg:                &rest.Config{Host: server.URL},
+				interval:           testPollInterval,
+				requestTimeout:     testPollTimeout,
+				unhealthyThreshold: testUnhealthyThreshold,
+				path:               "/",
+			})
+
+			Consistently(func() bool { return getAccessor() == accessor }, 1*time.Second, 100*time.Millisecond).Should(BeTrue())
+
+			atomic.StoreInt32(&failing, 1)
+
+			// The clusterAccessor is evicted after N consecutive failed requests, and rebuilt from the
+			// cluster kubeconfig, which points to a healthy API server.
+			Eventually(func() bool {
+				a := getAccessor()
+				return a != nil && a != accessor
+			}, 5*time.Second, 100*time.Millisecond).Should(BeTrue())
+			Expect(testutil.ToFloat64(clusterCacheEvictionsTotal)).To(Equal(evictions + 1))
 		})
 	})
 })
@@ -18,9 +18,12 @@ package remote
 
 import (
 	"context"
+	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -29,6 +32,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -213,3 +217,102 @@ func cleanupTestClusters(ctx context.Context, c client.Client) error {
 	}
 	return nil
 }
+
+func TestClusterCacheTrackerMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	cachedCluster := client.ObjectKey{Namespace: "test", Name: "cached"}
+	unknownCluster := client.ObjectKey{Namespace: "test", Name: "unknown"}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	cct := NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, cachedCluster)
+
+	hits := testutil.ToFloat64(clusterCacheHitsTotal)
+	misses := testutil.ToFloat64(clusterCacheMissesTotal)
+
+	_, err := cct.GetClient(ctx, cachedCluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(testutil.ToFloat64(clusterCacheHitsTotal)).To(Equal(hits + 1))
+	g.Expect(testutil.ToFloat64(clusterCacheMissesTotal)).To(Equal(misses))
+
+	// There is no kubeconfig for the cluster, so the client cannot be created.
+	_, err = cct.GetClient(ctx, unknownCluster)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(testutil.ToFloat64(clusterCacheHitsTotal)).To(Equal(hits + 1))
+	g.Expect(testutil.ToFloat64(clusterCacheMissesTotal)).To(Equal(misses + 1))
+}
+
+func TestClusterCacheTrackerRESTConfig(t *testing.T) {
+	t.Run("uses the configured QPS and burst", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(validSecret).Build()
+		cct := &ClusterCacheTracker{
+			client:  c,
+			options: ClusterCacheTrackerOptions{ClientQPS: 42, ClientBurst: 84},
+		}
+
+		config, err := cct.restConfig(ctx, clusterWithValidKubeConfig)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.Host).To(Equal("https://test-cluster-api.nodomain.example.com:6443"))
+		g.Expect(config.QPS).To(Equal(float32(42)))
+		g.Expect(config.Burst).To(Equal(84))
+	})
+
+	t.Run("uses the client-go defaults if QPS and burst are not configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(validSecret).Build()
+		cct := &ClusterCacheTracker{
+			client: c,
+		}
+
+		config, err := cct.restConfig(ctx, clusterWithValidKubeConfig)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(config.QPS).To(BeZero())
+		g.Expect(config.Burst).To(BeZero())
+	})
+
+	t.Run("applies the configured QPS and burst to rest configs created outside of the tracker", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(validSecret).Build()
+		cct := &ClusterCacheTracker{
+			client:  c,
+			options: ClusterCacheTrackerOptions{ClientQPS: 42, ClientBurst: 84},
+		}
+
+		config, err := RESTConfig(ctx, "test", c, clusterWithValidKubeConfig)
+		g.Expect(err).ToNot(HaveOccurred())
+		cct.ApplyClientOptions(config)
+		g.Expect(config.QPS).To(Equal(float32(42)))
+		g.Expect(config.Burst).To(Equal(84))
+	})
+}
+
+func TestClusterCacheTrackerRebuildAccessorStopsOnShutdown(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := client.ObjectKey{Namespace: "test", Name: "unknown"}
+
+	trackerCtx, trackerCancel := context.WithCancel(ctx)
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	cct := &ClusterCacheTracker{
+		log:              log.NullLogger{},
+		client:           c,
+		scheme:           scheme.Scheme,
+		ctx:              trackerCtx,
+		clusterAccessors: make(map[client.ObjectKey]*clusterAccessor),
+	}
+
+	// There is no kubeconfig for the cluster, so the rebuild keeps retrying until the tracker is stopped.
+	done := make(chan struct{})
+	go func() {
+		cct.rebuildAccessor(cluster)
+		close(done)
+	}()
+	trackerCancel()
+
+	g.Eventually(done, 5*time.Second).Should(BeClosed())
+	g.Expect(cct.clusterAccessorExists(cluster)).To(BeFalse())
+}
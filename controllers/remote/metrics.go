@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// clusterCacheHitsTotal is a prometheus metric which counts the lookups of a cached workload cluster client.
+	clusterCacheHitsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capi_cluster_cache_hits_total",
+		Help: "Total number of lookups served by a cached workload cluster client",
+	})
+
+	// clusterCacheMissesTotal is a prometheus metric which counts the lookups requiring a new workload cluster client.
+	clusterCacheMissesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capi_cluster_cache_misses_total",
+		Help: "Total number of lookups requiring a new workload cluster client to be created",
+	})
+
+	// clusterCacheEvictionsTotal is a prometheus metric which counts the workload cluster clients evicted
+	// because the cluster failed the health check.
+	clusterCacheEvictionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capi_cluster_cache_evictions_total",
+		Help: "Total number of workload cluster clients evicted because the cluster failed the health check",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		clusterCacheHitsTotal,
+		clusterCacheMissesTotal,
+		clusterCacheEvictionsTotal,
+	)
+}
@@ -21,11 +21,16 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -122,3 +127,40 @@ func fakeMachineNodeRef(m *clusterv1.Machine, pid string) {
 	}
 	Expect(testEnv.Status().Patch(ctx, m, patchMachine)).To(Succeed())
 }
+
+// getScale returns the scale subresource of an object in the test environment.
+func getScale(resource schema.GroupVersionResource, namespace, name string) (*autoscalingv1.Scale, error) {
+	scaleClient, err := dynamic.NewForConfig(testEnv.Config)
+	if err != nil {
+		return nil, err
+	}
+	u, err := scaleClient.Resource(resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}, "scale")
+	if err != nil {
+		return nil, err
+	}
+	scale := &autoscalingv1.Scale{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, scale); err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// setScaleReplicas sets the replicas of an object in the test environment through its scale subresource,
+// retrying on conflicts with the updates of the controllers.
+func setScaleReplicas(resource schema.GroupVersionResource, namespace, name string, replicas int32) error {
+	scaleClient, err := dynamic.NewForConfig(testEnv.Config)
+	if err != nil {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		u, err := scaleClient.Resource(resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}, "scale")
+		if err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(u.Object, int64(replicas), "spec", "replicas"); err != nil {
+			return err
+		}
+		_, err = scaleClient.Resource(resource).Namespace(namespace).Update(ctx, u, metav1.UpdateOptions{}, "scale")
+		return err
+	})
+}
@@ -38,6 +38,19 @@ func (src *KubeadmControlPlane) ConvertTo(destRaw conversion.Hub) error {
 	}
 
 	dest.Spec.RolloutStrategy = restored.Spec.RolloutStrategy
+	dest.Spec.RolloutBefore = restored.Spec.RolloutBefore
+	dest.Spec.EtcdDefragmentation = restored.Spec.EtcdDefragmentation
+	dest.Spec.Remediation = restored.Spec.Remediation
+	dest.Spec.FailureDomainSpread = restored.Spec.FailureDomainSpread
+	dest.Status.EtcdMembers = restored.Status.EtcdMembers
+	dest.Spec.KubeadmConfigSpec.TokenTTL = restored.Spec.KubeadmConfigSpec.TokenTTL
+	dest.Spec.KubeadmConfigSpec.TokenExtraGroups = restored.Spec.KubeadmConfigSpec.TokenExtraGroups
+	dest.Spec.KubeadmConfigSpec.TokenUsages = restored.Spec.KubeadmConfigSpec.TokenUsages
+	dest.Spec.KubeadmConfigSpec.ImageRepository = restored.Spec.KubeadmConfigSpec.ImageRepository
+	dest.Spec.KubeadmConfigSpec.AdditionalDataSecretKeys = restored.Spec.KubeadmConfigSpec.AdditionalDataSecretKeys
+	dest.Spec.KubeadmConfigSpec.GzipDataSecret = restored.Spec.KubeadmConfigSpec.GzipDataSecret
+	dest.Spec.KubeadmConfigSpec.Patches = restored.Spec.KubeadmConfigSpec.Patches
+	dest.Spec.KubeadmConfigSpec.SkipPhases = restored.Spec.KubeadmConfigSpec.SkipPhases
 
 	return nil
 }
@@ -74,3 +87,7 @@ func Convert_v1alpha4_KubeadmControlPlaneSpec_To_v1alpha3_KubeadmControlPlaneSpe
 func Convert_v1alpha3_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in *KubeadmControlPlaneSpec, out *v1alpha4.KubeadmControlPlaneSpec, s apiconversion.Scope) error { //nolint
 	return autoConvert_v1alpha3_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in, out, s)
 }
+
+func Convert_v1alpha4_KubeadmControlPlaneStatus_To_v1alpha3_KubeadmControlPlaneStatus(in *v1alpha4.KubeadmControlPlaneStatus, out *KubeadmControlPlaneStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_KubeadmControlPlaneStatus_To_v1alpha3_KubeadmControlPlaneStatus(in, out, s)
+}
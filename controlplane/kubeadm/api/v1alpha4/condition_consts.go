@@ -34,6 +34,15 @@ const (
 	// an error while generating certificates; those kind of errors are usually temporary and the controller
 	// automatically recover from them.
 	CertificatesGenerationFailedReason = "CertificatesGenerationFailed"
+
+	// CertificatesMissingReason (Severity=Error) documents a KubeadmControlPlane controller detecting
+	// that a certificate which must be provided by the user, e.g. the external etcd client certificate, does not exist.
+	CertificatesMissingReason = "CertificatesMissing"
+
+	// CertificatesInvalidReason (Severity=Error) documents a KubeadmControlPlane controller detecting
+	// that a pre-existing certificate secret, e.g. a user provided CA, is malformed or its private key does not
+	// match the certificate; this requires the user to fix the secret.
+	CertificatesInvalidReason = "CertificatesInvalid"
 )
 
 const (
@@ -122,6 +131,14 @@ const (
 	// EtcdClusterUnhealthyReason (Severity=Error) is set when the etcd cluster is unhealthy.
 	EtcdClusterUnhealthyReason = "EtcdClusterUnhealthy"
 
+	// ExternalEtcdReachableCondition documents the reachability of the endpoints of an external etcd cluster.
+	// NOTE: This conditions exists only if an external etcd cluster is used.
+	ExternalEtcdReachableCondition clusterv1.ConditionType = "ExternalEtcdReachable"
+
+	// ExternalEtcdUnreachableReason (Severity=Warning) documents one or more endpoints of an external etcd cluster not being
+	// reachable; if none of the endpoints is reachable the Severity is Error.
+	ExternalEtcdUnreachableReason = "ExternalEtcdUnreachable"
+
 	// MachineEtcdMemberHealthyCondition report the machine's etcd member's health status.
 	// NOTE: This conditions exists only if a stacked etcd cluster is used.
 	MachineEtcdMemberHealthyCondition clusterv1.ConditionType = "EtcdMemberHealthy"
@@ -149,3 +166,15 @@ const (
 	// generate a machine object
 	MachineGenerationFailedReason = "MachineGenerationFailed"
 )
+
+const (
+	// FailureDomainSpreadSatisfiedCondition documents that the control plane failure domains of the cluster can satisfy
+	// the failure domain spread of the KubeadmControlPlane.
+	// NOTE: This condition exists only if the failure domain spread is set.
+	FailureDomainSpreadSatisfiedCondition clusterv1.ConditionType = "FailureDomainSpreadSatisfied"
+
+	// FailureDomainSpreadUnsatisfiableReason (Severity=Warning) documents a KubeadmControlPlane whose desired replicas
+	// cannot be spread across the control plane failure domains of the cluster as required by its failure domain spread,
+	// e.g. because there are fewer failure domains than the minimum.
+	FailureDomainSpreadUnsatisfiableReason = "FailureDomainSpreadUnsatisfiable"
+)
@@ -18,6 +18,7 @@ package v1alpha4
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -46,6 +47,15 @@ const (
 	// KubeadmClusterConfigurationAnnotation is a machine annotation that stores the json-marshalled string of KCP ClusterConfiguration.
 	// This annotation is used to detect any changes in ClusterConfiguration and trigger machine rollout in KCP.
 	KubeadmClusterConfigurationAnnotation = "controlplane.cluster.x-k8s.io/kubeadm-cluster-configuration"
+
+	// APIServerCertificateMissingSANsAnnotation is a machine annotation that stores the comma separated list of the SANs
+	// which are required by KCP but missing in the serving certificate of the kube-apiserver running on the machine.
+	// This annotation is used to trigger machine rollout in KCP, so the certificate gets regenerated.
+	APIServerCertificateMissingSANsAnnotation = "controlplane.cluster.x-k8s.io/apiserver-certificate-missing-sans"
+
+	// EtcdLastDefragmentationTimeAnnotation is a machine annotation that stores the time, in RFC3339 format, the etcd member
+	// running on the machine was last defragmented by KCP.
+	EtcdLastDefragmentationTimeAnnotation = "controlplane.cluster.x-k8s.io/etcd-last-defragmentation-time"
 )
 
 // KubeadmControlPlaneSpec defines the desired state of KubeadmControlPlane.
@@ -73,6 +83,11 @@ type KubeadmControlPlaneSpec struct {
 	// +optional
 	UpgradeAfter *metav1.Time `json:"upgradeAfter,omitempty"`
 
+	// RolloutBefore is a field to indicate a rollout should be performed
+	// if the specified criteria is met.
+	// +optional
+	RolloutBefore *RolloutBefore `json:"rolloutBefore,omitempty"`
+
 	// NodeDrainTimeout is the total amount of time that the controller will spend on draining a controlplane node
 	// The default value is 0, meaning that the node can be drained without any time limitations.
 	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
@@ -83,6 +98,32 @@ type KubeadmControlPlaneSpec struct {
 	// new ones.
 	// +optional
 	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// EtcdDefragmentation configures the periodic defragmentation of the stacked etcd members.
+	// When not set, etcd members are never defragmented by the controller.
+	// NOTE: This is not supported when using external etcd.
+	// +optional
+	EtcdDefragmentation *EtcdDefragmentation `json:"etcdDefragmentation,omitempty"`
+
+	// Remediation configures the remediation of the control plane machines reported unhealthy by the
+	// health checks of the controller itself, in addition to the machines marked unhealthy by a MachineHealthCheck.
+	// When not set, only the machines marked unhealthy by a MachineHealthCheck are remediated.
+	// +optional
+	Remediation *Remediation `json:"remediation,omitempty"`
+
+	// FailureDomainSpread constrains the spread of the control plane machines across the control plane
+	// failure domains of the cluster. When not set, the machines are spread across the failure domains best effort.
+	// +optional
+	FailureDomainSpread *FailureDomainSpread `json:"failureDomainSpread,omitempty"`
+}
+
+// RolloutBefore describes when a rollout should be performed on the KCP machines.
+type RolloutBefore struct {
+	// CertificatesExpiryDays indicates a rollout needs to be performed if the
+	// certificates of the machine will expire within the specified days.
+	// +kubebuilder:validation:Minimum=7
+	// +optional
+	CertificatesExpiryDays *int32 `json:"certificatesExpiryDays,omitempty"`
 }
 
 // RolloutStrategy describes how to replace existing machines
@@ -112,6 +153,57 @@ type RollingUpdate struct {
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 }
 
+// Remediation describes when the control plane machines failing the health checks of the controller should be remediated.
+// A machine fails the health checks when its etcd member or one of its control plane component pods is reported
+// unhealthy with severity Error.
+type Remediation struct {
+	// UnhealthyTimeout is how long a machine must be failing the health checks before it is remediated.
+	UnhealthyTimeout metav1.Duration `json:"unhealthyTimeout"`
+}
+
+// FailureDomainSpread describes the constraints on the spread of the control plane machines across failure domains.
+type FailureDomainSpread struct {
+	// MaxMachinesPerFailureDomain is the maximum number of up-to-date control plane machines in a single failure domain.
+	// No machine is created in a failure domain which already has this number of machines, so the control plane
+	// is not scaled up, nor rolled out, when all the failure domains have it.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxMachinesPerFailureDomain *int32 `json:"maxMachinesPerFailureDomain,omitempty"`
+
+	// MinFailureDomains is the minimum number of failure domains the control plane machines must be spread across.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinFailureDomains *int32 `json:"minFailureDomains,omitempty"`
+}
+
+// EtcdDefragmentation describes when the stacked etcd members should be defragmented.
+// Members are defragmented one at a time, never concurrently, in order to preserve quorum;
+// the etcd leader is always defragmented last.
+type EtcdDefragmentation struct {
+	// Interval is the minimum amount of time between two defragmentations of the same etcd member.
+	Interval metav1.Duration `json:"interval"`
+
+	// DBSizeThreshold is the minimum size of the etcd database of a member for it to be defragmented;
+	// members with a smaller database are not defragmented, even if the interval has elapsed.
+	// Defaults to 0, meaning that every member is defragmented at each interval.
+	// +optional
+	DBSizeThreshold *resource.Quantity `json:"dbSizeThreshold,omitempty"`
+}
+
+// EtcdMemberStatus reports the observed state of a stacked etcd member.
+type EtcdMemberStatus struct {
+	// Name is the name of the node hosting the etcd member.
+	Name string `json:"name"`
+
+	// DBSize is the size of the etcd database of the member, as observed by the last check.
+	// +optional
+	DBSize resource.Quantity `json:"dbSize,omitempty"`
+
+	// LastDefragmentationTime is the last time the etcd member was defragmented by the controller.
+	// +optional
+	LastDefragmentationTime *metav1.Time `json:"lastDefragmentationTime,omitempty"`
+}
+
 // KubeadmControlPlaneStatus defines the observed state of KubeadmControlPlane.
 type KubeadmControlPlaneStatus struct {
 	// Selector is the label selector in string format to avoid introspection
@@ -172,6 +264,11 @@ type KubeadmControlPlaneStatus struct {
 	// Conditions defines current service state of the KubeadmControlPlane.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// EtcdMembers reports the database size and the last defragmentation time of the stacked etcd members.
+	// This is populated only when spec.etcdDefragmentation is set.
+	// +optional
+	EtcdMembers []EtcdMemberStatus `json:"etcdMembers,omitempty"`
 }
 
 // +kubebuilder:object:root=true
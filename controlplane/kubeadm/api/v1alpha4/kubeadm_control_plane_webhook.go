@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	cabpkv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
 	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	"sigs.k8s.io/cluster-api/util/container"
 	"sigs.k8s.io/cluster-api/util/version"
@@ -121,6 +122,7 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, kubeadmConfigSpec, clusterConfiguration, "dns", "imageRepository"},
 		{spec, kubeadmConfigSpec, clusterConfiguration, "dns", "imageTag"},
 		{spec, kubeadmConfigSpec, clusterConfiguration, "imageRepository"},
+		{spec, kubeadmConfigSpec, "imageRepository"},
 		{spec, kubeadmConfigSpec, clusterConfiguration, apiServer, "*"},
 		{spec, kubeadmConfigSpec, clusterConfiguration, controllerManager, "*"},
 		{spec, kubeadmConfigSpec, clusterConfiguration, scheduler, "*"},
@@ -129,14 +131,19 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, kubeadmConfigSpec, preKubeadmCommands},
 		{spec, kubeadmConfigSpec, postKubeadmCommands},
 		{spec, kubeadmConfigSpec, files},
+		{spec, kubeadmConfigSpec, "patches", "*"},
 		{spec, kubeadmConfigSpec, "verbosity"},
 		{spec, kubeadmConfigSpec, users},
 		{spec, "infrastructureTemplate", "name"},
 		{spec, "replicas"},
 		{spec, "version"},
 		{spec, "upgradeAfter"},
+		{spec, "rolloutBefore", "*"},
 		{spec, "nodeDrainTimeout"},
 		{spec, "rolloutStrategy"},
+		{spec, "etcdDefragmentation", "*"},
+		{spec, "remediation", "*"},
+		{spec, "failureDomainSpread", "*"},
 	}
 
 	allErrs := in.validateCommon()
@@ -264,12 +271,14 @@ func (in *KubeadmControlPlane) validateCommon() (allErrs field.ErrorList) {
 	}
 
 	if !externalEtcd {
-		if in.Spec.Replicas != nil && *in.Spec.Replicas%2 == 0 {
+		// An even number of etcd members tolerates no more failures than the odd number below it,
+		// while requiring one more member to keep quorum.
+		if in.Spec.Replicas != nil && *in.Spec.Replicas > 0 && *in.Spec.Replicas%2 == 0 {
 			allErrs = append(
 				allErrs,
 				field.Forbidden(
 					field.NewPath("spec", "replicas"),
-					"cannot be an even number when using managed etcd",
+					fmt.Sprintf("cannot be an even number when using managed etcd, as etcd requires an odd number of members to keep quorum; use 1, 3 or 5 instead of %d", *in.Spec.Replicas),
 				),
 			)
 		}
@@ -290,6 +299,72 @@ func (in *KubeadmControlPlane) validateCommon() (allErrs field.ErrorList) {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "version"), in.Spec.Version, "must be a valid semantic version"))
 	}
 
+	if in.Spec.RolloutBefore != nil && in.Spec.RolloutBefore.CertificatesExpiryDays != nil && *in.Spec.RolloutBefore.CertificatesExpiryDays < 7 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				field.NewPath("spec", "rolloutBefore", "certificatesExpiryDays"),
+				*in.Spec.RolloutBefore.CertificatesExpiryDays,
+				"must be greater than or equal to 7",
+			),
+		)
+	}
+
+	if in.Spec.EtcdDefragmentation != nil {
+		if externalEtcd {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					field.NewPath("spec", "etcdDefragmentation"),
+					"cannot be set when using external etcd",
+				),
+			)
+		}
+		if in.Spec.EtcdDefragmentation.Interval.Duration <= 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("spec", "etcdDefragmentation", "interval"),
+					in.Spec.EtcdDefragmentation.Interval.Duration.String(),
+					"must be greater than 0",
+				),
+			)
+		}
+		if in.Spec.EtcdDefragmentation.DBSizeThreshold != nil && in.Spec.EtcdDefragmentation.DBSizeThreshold.Sign() < 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("spec", "etcdDefragmentation", "dbSizeThreshold"),
+					in.Spec.EtcdDefragmentation.DBSizeThreshold.String(),
+					"must be greater than or equal to 0",
+				),
+			)
+		}
+	}
+
+	if in.Spec.Remediation != nil && in.Spec.Remediation.UnhealthyTimeout.Duration <= 0 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				field.NewPath("spec", "remediation", "unhealthyTimeout"),
+				in.Spec.Remediation.UnhealthyTimeout.Duration.String(),
+				"must be greater than 0",
+			),
+		)
+	}
+
+	if in.Spec.FailureDomainSpread != nil && in.Spec.FailureDomainSpread.MinFailureDomains != nil &&
+		in.Spec.Replicas != nil && *in.Spec.FailureDomainSpread.MinFailureDomains > *in.Spec.Replicas {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				field.NewPath("spec", "failureDomainSpread", "minFailureDomains"),
+				*in.Spec.FailureDomainSpread.MinFailureDomains,
+				"must be less than or equal to replicas",
+			),
+		)
+	}
+
 	if in.Spec.RolloutStrategy != nil {
 
 		if in.Spec.RolloutStrategy.Type != RollingUpdateStrategyType {
@@ -326,11 +401,44 @@ func (in *KubeadmControlPlane) validateCommon() (allErrs field.ErrorList) {
 		}
 	}
 
+	allErrs = append(allErrs, cabpkv1.ValidateTokenTTL(in.Spec.KubeadmConfigSpec.TokenTTL, field.NewPath("spec", "kubeadmConfigSpec", "tokenTTL"))...)
+	allErrs = append(allErrs, cabpkv1.ValidateTokenExtraGroups(in.Spec.KubeadmConfigSpec.TokenExtraGroups, field.NewPath("spec", "kubeadmConfigSpec", "tokenExtraGroups"))...)
+	allErrs = append(allErrs, cabpkv1.ValidateIgnitionFormat(&in.Spec.KubeadmConfigSpec, field.NewPath("spec", "kubeadmConfigSpec"))...)
+	allErrs = append(allErrs, cabpkv1.ValidateTokenUsages(in.Spec.KubeadmConfigSpec.TokenUsages, field.NewPath("spec", "kubeadmConfigSpec", "tokenUsages"))...)
+	allErrs = append(allErrs, cabpkv1.ValidateImageRepository(&in.Spec.KubeadmConfigSpec, field.NewPath("spec", "kubeadmConfigSpec"))...)
+	allErrs = append(allErrs, cabpkv1.ValidateAdditionalDataSecretKeys(in.Spec.KubeadmConfigSpec.AdditionalDataSecretKeys, field.NewPath("spec", "kubeadmConfigSpec", "additionalDataSecretKeys"))...)
+	allErrs = append(allErrs, cabpkv1.ValidatePatches(in.Spec.KubeadmConfigSpec.Patches, field.NewPath("spec", "kubeadmConfigSpec", "patches"))...)
+	allErrs = append(allErrs, cabpkv1.ValidateSkipPhases(in.Spec.KubeadmConfigSpec.SkipPhases, field.NewPath("spec", "kubeadmConfigSpec", "skipPhases"))...)
+
 	allErrs = append(allErrs, in.validateCoreDNSImage()...)
+	allErrs = append(allErrs, in.validatePatchesVersion()...)
 
 	return allErrs
 }
 
+// validatePatchesVersion rejects kubeadm patches with Kubernetes versions older than v1.19, which kubeadm
+// does not support them with.
+func (in *KubeadmControlPlane) validatePatchesVersion() (allErrs field.ErrorList) {
+	if in.Spec.KubeadmConfigSpec.Patches == nil {
+		return allErrs
+	}
+	// An invalid version is reported by the version validation.
+	v, err := version.ParseMajorMinorPatch(in.Spec.Version)
+	if err != nil {
+		return allErrs
+	}
+	if v.LT(semver.MustParse("1.19.0")) {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				field.NewPath("spec", "kubeadmConfigSpec", "patches"),
+				fmt.Sprintf("kubeadm patches require Kubernetes v1.19.0 or later, got %s", in.Spec.Version),
+			),
+		)
+	}
+	return allErrs
+}
+
 func (in *KubeadmControlPlane) validateCoreDNSImage() (allErrs field.ErrorList) {
 	if in.Spec.KubeadmConfigSpec.ClusterConfiguration == nil {
 		return allErrs
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
@@ -91,6 +92,15 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	evenReplicas := valid.DeepCopy()
 	evenReplicas.Spec.Replicas = pointer.Int32Ptr(2)
 
+	threeReplicas := valid.DeepCopy()
+	threeReplicas.Spec.Replicas = pointer.Int32Ptr(3)
+
+	fiveReplicas := valid.DeepCopy()
+	fiveReplicas.Spec.Replicas = pointer.Int32Ptr(5)
+
+	fourReplicas := valid.DeepCopy()
+	fourReplicas.Spec.Replicas = pointer.Int32Ptr(4)
+
 	evenReplicasExternalEtcd := evenReplicas.DeepCopy()
 	evenReplicasExternalEtcd.Spec.KubeadmConfigSpec = bootstrapv1.KubeadmConfigSpec{
 		ClusterConfiguration: &kubeadmv1beta1.ClusterConfiguration{
@@ -109,6 +119,85 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	invalidVersion2 := valid.DeepCopy()
 	invalidVersion2.Spec.Version = "1.16.6"
 
+	invalidTokenTTL := valid.DeepCopy()
+	invalidTokenTTL.Spec.KubeadmConfigSpec.TokenTTL = &metav1.Duration{Duration: 10 * time.Second}
+
+	invalidTokenExtraGroups := valid.DeepCopy()
+	invalidTokenExtraGroups.Spec.KubeadmConfigSpec.TokenExtraGroups = []string{"system:masters"}
+
+	invalidTokenUsages := valid.DeepCopy()
+	invalidTokenUsages.Spec.KubeadmConfigSpec.TokenUsages = []string{"encryption"}
+
+	invalidImageRepository := valid.DeepCopy()
+	invalidImageRepository.Spec.KubeadmConfigSpec.ImageRepository = "registry.example.com/k8s"
+	invalidImageRepository.Spec.KubeadmConfigSpec.ClusterConfiguration = &kubeadmv1beta1.ClusterConfiguration{
+		ImageRepository: "k8s.gcr.io",
+	}
+
+	invalidAdditionalDataSecretKeys := valid.DeepCopy()
+	invalidAdditionalDataSecretKeys.Spec.KubeadmConfigSpec.AdditionalDataSecretKeys = []string{"userData", "format"}
+
+	duplicateAdditionalDataSecretKeys := valid.DeepCopy()
+	duplicateAdditionalDataSecretKeys.Spec.KubeadmConfigSpec.AdditionalDataSecretKeys = []string{"userData", "userData"}
+
+	invalidPatchFileName := valid.DeepCopy()
+	invalidPatchFileName.Spec.KubeadmConfigSpec.Patches = &bootstrapv1.Patches{
+		Files: []bootstrapv1.PatchFile{{Name: "kube-apiserver.yml", Content: "foo: bar"}},
+	}
+
+	validPatches := valid.DeepCopy()
+	validPatches.Spec.KubeadmConfigSpec.Patches = &bootstrapv1.Patches{
+		Files: []bootstrapv1.PatchFile{{Name: "kube-apiserver+merge.yaml", Content: "foo: bar"}},
+	}
+
+	patchesUnsupportedVersion := validPatches.DeepCopy()
+	patchesUnsupportedVersion.Spec.Version = "v1.18.8"
+
+	invalidSkipPhases := valid.DeepCopy()
+	invalidSkipPhases.Spec.KubeadmConfigSpec.SkipPhases = []string{"kube-proxy"}
+
+	invalidIgnitionMounts := valid.DeepCopy()
+	invalidIgnitionMounts.Spec.KubeadmConfigSpec.Format = bootstrapv1.Ignition
+	invalidIgnitionMounts.Spec.KubeadmConfigSpec.Mounts = []bootstrapv1.MountPoints{{"LABEL=etcd_disk", "/var/lib/etcddisk"}}
+
+	validCertificatesExpiryDays := valid.DeepCopy()
+	validCertificatesExpiryDays.Spec.RolloutBefore = &RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(21)}
+
+	invalidCertificatesExpiryDays := valid.DeepCopy()
+	invalidCertificatesExpiryDays.Spec.RolloutBefore = &RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(5)}
+
+	validEtcdDefragmentation := valid.DeepCopy()
+	validEtcdDefragmentation.Spec.EtcdDefragmentation = &EtcdDefragmentation{
+		Interval:        metav1.Duration{Duration: 24 * time.Hour},
+		DBSizeThreshold: resource.NewQuantity(100*1024*1024, resource.BinarySI),
+	}
+
+	invalidEtcdDefragmentationInterval := valid.DeepCopy()
+	invalidEtcdDefragmentationInterval.Spec.EtcdDefragmentation = &EtcdDefragmentation{}
+
+	invalidEtcdDefragmentationThreshold := validEtcdDefragmentation.DeepCopy()
+	invalidEtcdDefragmentationThreshold.Spec.EtcdDefragmentation.DBSizeThreshold = resource.NewQuantity(-1, resource.BinarySI)
+
+	invalidEtcdDefragmentationExternalEtcd := evenReplicasExternalEtcd.DeepCopy()
+	invalidEtcdDefragmentationExternalEtcd.Spec.EtcdDefragmentation = validEtcdDefragmentation.Spec.EtcdDefragmentation.DeepCopy()
+
+	validRemediation := valid.DeepCopy()
+	validRemediation.Spec.Remediation = &Remediation{UnhealthyTimeout: metav1.Duration{Duration: 10 * time.Minute}}
+
+	invalidRemediationUnhealthyTimeout := valid.DeepCopy()
+	invalidRemediationUnhealthyTimeout.Spec.Remediation = &Remediation{}
+
+	validFailureDomainSpread := valid.DeepCopy()
+	validFailureDomainSpread.Spec.FailureDomainSpread = &FailureDomainSpread{
+		MaxMachinesPerFailureDomain: pointer.Int32Ptr(1),
+		MinFailureDomains:           pointer.Int32Ptr(1),
+	}
+
+	invalidFailureDomainSpreadMinFailureDomains := valid.DeepCopy()
+	invalidFailureDomainSpreadMinFailureDomains.Spec.FailureDomainSpread = &FailureDomainSpread{
+		MinFailureDomains: pointer.Int32Ptr(3),
+	}
+
 	tests := []struct {
 		name      string
 		expectErr bool
@@ -139,6 +228,21 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       evenReplicas,
 		},
+		{
+			name:      "should succeed when replicas is 3",
+			expectErr: false,
+			kcp:       threeReplicas,
+		},
+		{
+			name:      "should succeed when replicas is 5",
+			expectErr: false,
+			kcp:       fiveReplicas,
+		},
+		{
+			name:      "should return error when replicas is 4",
+			expectErr: true,
+			kcp:       fourReplicas,
+		},
 		{
 			name:      "should allow even replicas when using external etcd",
 			expectErr: false,
@@ -164,6 +268,111 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidMaxSurge,
 		},
+		{
+			name:      "should return error when tokenTTL is shorter than a minute",
+			expectErr: true,
+			kcp:       invalidTokenTTL,
+		},
+		{
+			name:      "should return error when tokenExtraGroups do not start with system:bootstrappers:",
+			expectErr: true,
+			kcp:       invalidTokenExtraGroups,
+		},
+		{
+			name:      "should return error when tokenUsages contains an unknown usage",
+			expectErr: true,
+			kcp:       invalidTokenUsages,
+		},
+		{
+			name:      "should return error when imageRepository does not match clusterConfiguration.imageRepository",
+			expectErr: true,
+			kcp:       invalidImageRepository,
+		},
+		{
+			name:      "should return error when additionalDataSecretKeys contains a reserved key",
+			expectErr: true,
+			kcp:       invalidAdditionalDataSecretKeys,
+		},
+		{
+			name:      "should return error when additionalDataSecretKeys contains a duplicate key",
+			expectErr: true,
+			kcp:       duplicateAdditionalDataSecretKeys,
+		},
+		{
+			name:      "should return error when a patch file name does not follow the kubeadm naming convention",
+			expectErr: true,
+			kcp:       invalidPatchFileName,
+		},
+		{
+			name:      "should succeed when patches are set with Kubernetes v1.19 or later",
+			expectErr: false,
+			kcp:       validPatches,
+		},
+		{
+			name:      "should return error when patches are set with a Kubernetes version older than v1.19",
+			expectErr: true,
+			kcp:       patchesUnsupportedVersion,
+		},
+		{
+			name:      "should return error when a skip phase is not a phase of kubeadm init",
+			expectErr: true,
+			kcp:       invalidSkipPhases,
+		},
+		{
+			name:      "should return error when mounts are set with the ignition format",
+			expectErr: true,
+			kcp:       invalidIgnitionMounts,
+		},
+		{
+			name:      "should succeed when certificatesExpiryDays is at least 7",
+			expectErr: false,
+			kcp:       validCertificatesExpiryDays,
+		},
+		{
+			name:      "should return error when certificatesExpiryDays is less than 7",
+			expectErr: true,
+			kcp:       invalidCertificatesExpiryDays,
+		},
+		{
+			name:      "should succeed when etcdDefragmentation is valid",
+			expectErr: false,
+			kcp:       validEtcdDefragmentation,
+		},
+		{
+			name:      "should return error when etcdDefragmentation interval is not set",
+			expectErr: true,
+			kcp:       invalidEtcdDefragmentationInterval,
+		},
+		{
+			name:      "should return error when etcdDefragmentation dbSizeThreshold is negative",
+			expectErr: true,
+			kcp:       invalidEtcdDefragmentationThreshold,
+		},
+		{
+			name:      "should return error when etcdDefragmentation is set with external etcd",
+			expectErr: true,
+			kcp:       invalidEtcdDefragmentationExternalEtcd,
+		},
+		{
+			name:      "should succeed when given a valid remediation",
+			expectErr: false,
+			kcp:       validRemediation,
+		},
+		{
+			name:      "should return error when remediation unhealthyTimeout is not greater than 0",
+			expectErr: true,
+			kcp:       invalidRemediationUnhealthyTimeout,
+		},
+		{
+			name:      "should succeed when given a valid failure domain spread",
+			expectErr: false,
+			kcp:       validFailureDomainSpread,
+		},
+		{
+			name:      "should return error when the failure domain spread minFailureDomains is greater than replicas",
+			expectErr: true,
+			kcp:       invalidFailureDomainSpreadMinFailureDomains,
+		},
 	}
 
 	for _, tt := range tests {
@@ -179,6 +388,29 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	}
 }
 
+func TestKubeadmControlPlaneValidateEvenReplicasMessage(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "foo",
+		},
+		Spec: KubeadmControlPlaneSpec{
+			InfrastructureTemplate: corev1.ObjectReference{
+				Namespace: "foo",
+				Name:      "infraTemplate",
+			},
+			Replicas: pointer.Int32Ptr(2),
+			Version:  "v1.19.0",
+		},
+	}
+
+	err := kcp.ValidateCreate()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("use 1, 3 or 5 instead of 2"))
+}
+
 func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	before := &KubeadmControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
@@ -285,6 +517,10 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	validUpdate.Spec.Replicas = pointer.Int32Ptr(5)
 	now := metav1.NewTime(time.Now())
 	validUpdate.Spec.UpgradeAfter = &now
+	validUpdate.Spec.RolloutBefore = &RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(14)}
+	validUpdate.Spec.EtcdDefragmentation = &EtcdDefragmentation{Interval: metav1.Duration{Duration: 24 * time.Hour}}
+	validUpdate.Spec.Remediation = &Remediation{UnhealthyTimeout: metav1.Duration{Duration: 10 * time.Minute}}
+	validUpdate.Spec.FailureDomainSpread = &FailureDomainSpread{MaxMachinesPerFailureDomain: pointer.Int32Ptr(1)}
 
 	scaleToZero := before.DeepCopy()
 	scaleToZero.Spec.Replicas = pointer.Int32Ptr(0)
@@ -415,6 +651,10 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	imageRepository := before.DeepCopy()
 	imageRepository.Spec.KubeadmConfigSpec.ClusterConfiguration.ImageRepository = "a new image repository"
 
+	joinImageRepository := before.DeepCopy()
+	joinImageRepository.Spec.KubeadmConfigSpec.ClusterConfiguration.ImageRepository = "a new image repository"
+	joinImageRepository.Spec.KubeadmConfigSpec.ImageRepository = "a new image repository"
+
 	useHyperKubeImage := before.DeepCopy()
 	useHyperKubeImage.Spec.KubeadmConfigSpec.ClusterConfiguration.UseHyperKubeImage = true
 
@@ -484,6 +724,15 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 		DataDir: "/data",
 	}
 
+	beforeV119 := before.DeepCopy()
+	beforeV119.Spec.Version = "v1.19.1"
+	validUpdatePatches := beforeV119.DeepCopy()
+	validUpdatePatches.Spec.KubeadmConfigSpec.Patches = &bootstrapv1.Patches{
+		Files: []bootstrapv1.PatchFile{{Name: "kube-apiserver+merge.yaml", Content: "foo: bar"}},
+	}
+	invalidUpdatePatchesVersion := before.DeepCopy()
+	invalidUpdatePatchesVersion.Spec.KubeadmConfigSpec.Patches = validUpdatePatches.Spec.KubeadmConfigSpec.Patches.DeepCopy()
+
 	disallowedUpgrade118Prev := prevKCPWithVersion("v1.18.8")
 	disallowedUpgrade119Version := before.DeepCopy()
 	disallowedUpgrade119Version.Spec.Version = "v1.19.0"
@@ -500,6 +749,18 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    before,
 			kcp:       validUpdate,
 		},
+		{
+			name:      "should succeed when adding patches with Kubernetes v1.19 or later",
+			expectErr: false,
+			before:    beforeV119,
+			kcp:       validUpdatePatches,
+		},
+		{
+			name:      "should return error when adding patches with a Kubernetes version older than v1.19",
+			expectErr: true,
+			before:    before,
+			kcp:       invalidUpdatePatchesVersion,
+		},
 		{
 			name:      "should return error when trying to mutate the kubeadmconfigspec initconfiguration",
 			expectErr: true,
@@ -662,6 +923,12 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    before,
 			kcp:       imageRepository,
 		},
+		{
+			name:      "should succeed when making a change to the imageRepository along with the cluster config's imageRepository",
+			expectErr: false,
+			before:    before,
+			kcp:       joinImageRepository,
+		},
 		{
 			name:      "should fail when making a change to the cluster config's useHyperKubeImage field",
 			expectErr: true,
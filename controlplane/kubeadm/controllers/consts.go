@@ -30,4 +30,8 @@ const (
 	// dependentCertRequeueAfter is how long to wait before checking again to see if
 	// dependent certificates have been created.
 	dependentCertRequeueAfter = 30 * time.Second
+
+	// etcdDefragmentationRequeueAfter is how long to wait after defragmenting an etcd member
+	// before defragmenting the next one.
+	etcdDefragmentationRequeueAfter = 30 * time.Second
 )
@@ -18,8 +18,11 @@ package controllers
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
@@ -27,6 +30,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
@@ -212,6 +216,7 @@ func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kc
 			controlplanev1.MachinesReadyCondition,
 			controlplanev1.AvailableCondition,
 			controlplanev1.CertificatesAvailableCondition,
+			controlplanev1.FailureDomainSpreadSatisfiedCondition,
 		),
 	)
 
@@ -227,10 +232,40 @@ func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kc
 			controlplanev1.MachinesReadyCondition,
 			controlplanev1.AvailableCondition,
 			controlplanev1.CertificatesAvailableCondition,
+			controlplanev1.FailureDomainSpreadSatisfiedCondition,
 		}},
 	)
 }
 
+// reconcileCertificates looks up the cluster certificates, using the ones already existing as secrets, e.g. user
+// provided CAs, and generating the missing ones. Pre-existing certificates are validated before being used.
+func (r *KubeadmControlPlaneReconciler) reconcileCertificates(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, certificates secret.Certificates, owner metav1.OwnerReference) error {
+	if err := certificates.Lookup(ctx, r.Client, util.ObjectKey(cluster)); err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			conditions.MarkFalse(kcp, controlplanev1.CertificatesAvailableCondition, controlplanev1.CertificatesMissingReason, clusterv1.ConditionSeverityError, err.Error())
+			return err
+		}
+		conditions.MarkFalse(kcp, controlplanev1.CertificatesAvailableCondition, controlplanev1.CertificatesGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	if err := certificates.Validate(); err != nil {
+		conditions.MarkFalse(kcp, controlplanev1.CertificatesAvailableCondition, controlplanev1.CertificatesInvalidReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	if err := certificates.Generate(); err != nil {
+		conditions.MarkFalse(kcp, controlplanev1.CertificatesAvailableCondition, controlplanev1.CertificatesGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	if err := certificates.SaveGenerated(ctx, r.Client, util.ObjectKey(cluster), owner); err != nil {
+		conditions.MarkFalse(kcp, controlplanev1.CertificatesAvailableCondition, controlplanev1.CertificatesGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+	return nil
+}
+
 // reconcile handles KubeadmControlPlane reconciliation.
 func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane) (res ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
@@ -249,9 +284,8 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 	}
 	certificates := secret.NewCertificatesForInitialControlPlane(config.ClusterConfiguration)
 	controllerRef := metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane"))
-	if err := certificates.LookupOrGenerate(ctx, r.Client, util.ObjectKey(cluster), *controllerRef); err != nil {
+	if err := r.reconcileCertificates(ctx, cluster, kcp, certificates, *controllerRef); err != nil {
 		log.Error(err, "unable to lookup or create cluster certificates")
-		conditions.MarkFalse(kcp, controlplanev1.CertificatesAvailableCondition, controlplanev1.CertificatesGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 	conditions.MarkTrue(kcp, controlplanev1.CertificatesAvailableCondition)
@@ -299,6 +333,9 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 	// source ref (reason@machine/name) so the problem can be easily tracked down to its source machine.
 	conditions.SetAggregate(controlPlane.KCP, controlplanev1.MachinesReadyCondition, ownedMachines.ConditionGetters(), conditions.AddSourceRef(), conditions.WithStepCounterIf(false))
 
+	// Reports whether the control plane machines can be spread across the failure domains as required.
+	setFailureDomainSpreadCondition(controlPlane)
+
 	// Updates conditions reporting the status of static pods and the status of the etcd cluster.
 	// NOTE: Conditions reporting KCP operation progress like e.g. Resized or SpecUpToDate are inlined with the rest of the execution.
 	if result, err := r.reconcileControlPlaneConditions(ctx, controlPlane); err != nil || !result.IsZero() {
@@ -311,12 +348,26 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return result, err
 	}
 
+	// Records the certificates expiry date on the control plane machines, so machines with
+	// certificates about to expire can be rolled out.
+	if err := r.reconcileCertificateExpiries(ctx, controlPlane); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Reconcile unhealthy machines by triggering deletion and requeue if it is considered safe to remediate,
 	// otherwise continue with the other KCP operations.
 	if result, err := r.reconcileUnhealthyMachines(ctx, controlPlane); err != nil || !result.IsZero() {
 		return result, err
 	}
 
+	// Requeue when the next machine failing the KCP health checks reaches the remediation unhealthy timeout,
+	// so it gets remediated without waiting for the next resync.
+	if requeueAfter, ok := controlPlane.NextUnhealthyTimeout(); ok {
+		defer func() {
+			res = util.LowestNonZeroResult(res, ctrl.Result{RequeueAfter: requeueAfter})
+		}()
+	}
+
 	// Control plane machines rollout due to configuration changes (e.g. upgrades) takes precedence over other operations.
 	needRollout := controlPlane.MachinesNeedingRollout()
 	switch {
@@ -368,10 +419,12 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return ctrl.Result{}, errors.Wrap(err, "failed to set role and role binding for kubeadm")
 	}
 
-	// Update kube-proxy daemonset.
-	if err := workloadCluster.UpdateKubeProxyImageInfo(ctx, kcp); err != nil {
-		log.Error(err, "failed to update kube-proxy daemonset")
-		return ctrl.Result{}, err
+	// Update kube-proxy daemonset, unless kube-proxy was not installed by kubeadm init.
+	if !kcp.Spec.KubeadmConfigSpec.SkipsPhase(bootstrapv1.KubeProxyAddonPhase) {
+		if err := workloadCluster.UpdateKubeProxyImageInfo(ctx, kcp); err != nil {
+			log.Error(err, "failed to update kube-proxy daemonset")
+			return ctrl.Result{}, err
+		}
 	}
 
 	// Update CoreDNS deployment.
@@ -379,7 +432,9 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return ctrl.Result{}, errors.Wrap(err, "failed to update CoreDNS deployment")
 	}
 
-	return ctrl.Result{}, nil
+	// Defragment etcd members, if required; this is done only when the control plane is stable,
+	// given that defragmentation temporarily blocks the member being defragmented.
+	return r.reconcileEtcdDefragmentation(ctx, controlPlane)
 }
 
 // reconcileDelete handles KubeadmControlPlane deletion.
@@ -542,6 +597,216 @@ func (r *KubeadmControlPlaneReconciler) reconcileEtcdMembers(ctx context.Context
 	return ctrl.Result{}, nil
 }
 
+// reconcileCertificateExpiries sets Machine.Status.CertificatesExpiryDate on the control plane machines
+// which have a node and for which the expiry date is not known yet; at the same time, machines whose kube-apiserver
+// certificate is missing some of the SANs required by KCP are annotated so they get rolled out.
+// NOTE: the certificate is read only once, given that certificates are not renewed during the lifetime of a machine;
+// changes to the certSANs of the KCP ClusterConfiguration are rolled out like any other change to the ClusterConfiguration.
+func (r *KubeadmControlPlaneReconciler) reconcileCertificateExpiries(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	log := ctrl.LoggerFrom(ctx, "cluster", controlPlane.Cluster.Name)
+
+	machines := controlPlane.Machines.Filter(
+		collections.Not(collections.HasDeletionTimestamp),
+		func(machine *clusterv1.Machine) bool {
+			return machine.Status.NodeRef != nil && machine.Status.CertificatesExpiryDate == nil
+		},
+	)
+	if machines.Len() == 0 {
+		return nil
+	}
+
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+	if err != nil {
+		return errors.Wrap(err, "cannot get remote client to workload cluster")
+	}
+
+	requiredSANs := controlPlane.APIServerCertSANs()
+	for _, machine := range machines {
+		certificate, err := workloadCluster.GetAPIServerCertificate(ctx, machine.Status.NodeRef.Name)
+		if err != nil {
+			// The expiry date is retried at the next reconcile, without blocking other KCP operations.
+			log.Error(err, "Failed to get the certificates expiry date", "machine", machine.Name)
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(machine, r.Client)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create patch helper for machine %s", machine.Name)
+		}
+		machine.Status.CertificatesExpiryDate = &metav1.Time{Time: certificate.NotAfter}
+		if missing := missingCertificateSANs(certificate, requiredSANs); len(missing) > 0 {
+			log.Info("The kube-apiserver certificate is missing some required SANs, the machine will be rolled out", "machine", machine.Name, "missingSANs", missing)
+			annotations.AddAnnotations(machine, map[string]string{
+				controlplanev1.APIServerCertificateMissingSANsAnnotation: strings.Join(missing, ","),
+			})
+		}
+		if err := patchHelper.Patch(ctx, machine); err != nil {
+			return errors.Wrapf(err, "failed to patch machine %s", machine.Name)
+		}
+	}
+
+	return nil
+}
+
+// missingCertificateSANs returns the SANs which are not valid for the certificate.
+func missingCertificateSANs(certificate *x509.Certificate, sans []string) []string {
+	var missing []string
+	for _, san := range sans {
+		if err := certificate.VerifyHostname(san); err != nil {
+			missing = append(missing, san)
+		}
+	}
+	return missing
+}
+
+// reconcileEtcdDefragmentation defragments the stacked etcd members which were not defragmented within
+// KCP.Spec.EtcdDefragmentation.Interval, recording the database size and the last defragmentation time
+// of each member in KCP.Status.EtcdMembers.
+// NOTE: At most one member is defragmented at each reconcile, so members are never defragmented concurrently;
+// followers are defragmented before the leader, and the defragmentations are staggered across the interval.
+// The last defragmentation time is also recorded in an annotation on the member's Machine, given that
+// the KCP status is not preserved when moving the cluster to another management cluster.
+func (r *KubeadmControlPlaneReconciler) reconcileEtcdDefragmentation(ctx context.Context, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx, "cluster", controlPlane.Cluster.Name)
+	kcp := controlPlane.KCP
+
+	if kcp.Spec.EtcdDefragmentation == nil || !controlPlane.IsEtcdManaged() {
+		kcp.Status.EtcdMembers = nil
+		return ctrl.Result{}, nil
+	}
+
+	// Defragmenting a member while another one is unhealthy could lead to losing quorum.
+	if !conditions.IsTrue(kcp, controlplanev1.EtcdClusterHealthyCondition) {
+		return ctrl.Result{}, nil
+	}
+
+	nodeNames := []string{}
+	machinesByNode := map[string]*clusterv1.Machine{}
+	for _, machine := range controlPlane.Machines {
+		if machine.Status.NodeRef == nil || !machine.DeletionTimestamp.IsZero() {
+			// Wait for provisioning or deleting machines to settle.
+			return ctrl.Result{}, nil
+		}
+		nodeNames = append(nodeNames, machine.Status.NodeRef.Name)
+		machinesByNode[machine.Status.NodeRef.Name] = machine
+	}
+	sort.Strings(nodeNames)
+
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "cannot get remote client to workload cluster")
+	}
+
+	statuses, err := workloadCluster.EtcdMembersStatus(ctx, nodeNames)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to get the status of the etcd members")
+	}
+
+	// The time recorded on the machines takes precedence over the status, which is lost when the cluster is moved.
+	lastDefragmentationTimes := map[string]*metav1.Time{}
+	for _, member := range kcp.Status.EtcdMembers {
+		lastDefragmentationTimes[member.Name] = member.LastDefragmentationTime
+	}
+	for nodeName, machine := range machinesByNode {
+		if last := etcdLastDefragmentationTime(machine); last != nil {
+			lastDefragmentationTimes[nodeName] = last
+		}
+	}
+
+	// Followers come first, so the leader is defragmented last.
+	kcp.Status.EtcdMembers = make([]controlplanev1.EtcdMemberStatus, 0, len(nodeNames))
+	candidates := make([]int, 0, len(nodeNames))
+	leader := -1
+	var latestDefragmentation *metav1.Time
+	for i, nodeName := range nodeNames {
+		kcp.Status.EtcdMembers = append(kcp.Status.EtcdMembers, controlplanev1.EtcdMemberStatus{
+			Name:                    nodeName,
+			DBSize:                  *resource.NewQuantity(statuses[nodeName].DBSize, resource.BinarySI),
+			LastDefragmentationTime: lastDefragmentationTimes[nodeName],
+		})
+		if last := lastDefragmentationTimes[nodeName]; last != nil && (latestDefragmentation == nil || last.After(latestDefragmentation.Time)) {
+			latestDefragmentation = last
+		}
+		if statuses[nodeName].IsLeader {
+			leader = i
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+	if leader >= 0 {
+		candidates = append(candidates, leader)
+	}
+
+	interval := kcp.Spec.EtcdDefragmentation.Interval.Duration
+	threshold := kcp.Spec.EtcdDefragmentation.DBSizeThreshold
+
+	// Spread the defragmentations across the interval, so the members don't all become due at the same time.
+	stagger := interval / time.Duration(len(nodeNames))
+	if stagger < etcdDefragmentationRequeueAfter {
+		stagger = etcdDefragmentationRequeueAfter
+	}
+	if latestDefragmentation != nil {
+		if wait := stagger - time.Since(latestDefragmentation.Time); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	requeueAfter := interval
+	for _, i := range candidates {
+		member := &kcp.Status.EtcdMembers[i]
+		if member.LastDefragmentationTime != nil {
+			if due := interval - time.Since(member.LastDefragmentationTime.Time); due > 0 {
+				if due < requeueAfter {
+					requeueAfter = due
+				}
+				continue
+			}
+		}
+		if threshold != nil && member.DBSize.Cmp(*threshold) < 0 {
+			continue
+		}
+
+		log.Info("Defragmenting etcd member", "node", member.Name, "dbSize", member.DBSize.String())
+		if err := workloadCluster.DefragmentEtcdMember(ctx, member.Name); err != nil {
+			r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedEtcdDefragmentation", "Failed to defragment etcd member on node %s: %v", member.Name, err)
+			return ctrl.Result{}, errors.Wrapf(err, "failed to defragment etcd member on node %s", member.Name)
+		}
+		now := metav1.Now()
+		member.LastDefragmentationTime = &now
+		r.recorder.Eventf(kcp, corev1.EventTypeNormal, "SuccessfulEtcdDefragmentation", "Defragmented etcd member on node %s", member.Name)
+
+		machine := machinesByNode[member.Name]
+		patchHelper, err := patch.NewHelper(machine, r.Client)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to create patch helper for machine %s", machine.Name)
+		}
+		annotations.AddAnnotations(machine, map[string]string{
+			controlplanev1.EtcdLastDefragmentationTimeAnnotation: now.UTC().Format(time.RFC3339),
+		})
+		if err := patchHelper.Patch(ctx, machine); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to patch machine %s", machine.Name)
+		}
+
+		// Give the member time to recover before defragmenting the next one.
+		return ctrl.Result{RequeueAfter: stagger}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// etcdLastDefragmentationTime returns the last defragmentation time of the etcd member recorded on the machine, if any.
+func etcdLastDefragmentationTime(machine *clusterv1.Machine) *metav1.Time {
+	value, ok := machine.GetAnnotations()[controlplanev1.EtcdLastDefragmentationTimeAnnotation]
+	if !ok {
+		return nil
+	}
+	last, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &metav1.Time{Time: last}
+}
+
 func (r *KubeadmControlPlaneReconciler) adoptMachines(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, machines collections.Machines, cluster *clusterv1.Cluster) error {
 	// We do an uncached full quorum read against the KCP to avoid re-adopting Machines the garbage collector just intentionally orphaned
 	// See https://github.com/kubernetes/kubernetes/issues/42639
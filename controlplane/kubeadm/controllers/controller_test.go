@@ -30,6 +30,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -44,6 +45,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
 	"sigs.k8s.io/cluster-api/test/helpers"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -352,6 +354,102 @@ func TestReconcileClusterNoEndpoints(t *testing.T) {
 	g.Expect(machineList.Items).To(BeEmpty())
 }
 
+func TestKubeadmControlPlaneReconciler_reconcileCertificates(t *testing.T) {
+	cluster := newCluster(&types.NamespacedName{Name: "foo", Namespace: "test"})
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      "foo",
+		},
+	}
+	owner := *metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane"))
+
+	userCerts := secret.NewCertificatesForInitialControlPlane(nil)
+	NewWithT(t).Expect(userCerts.Generate()).To(Succeed())
+	userCA := userCerts.GetByPurpose(secret.ClusterCA)
+	otherCA := userCerts.GetByPurpose(secret.EtcdCA)
+
+	caSecret := func(cert, key []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      secret.Name(cluster.Name, secret.ClusterCA),
+			},
+			Data: map[string][]byte{
+				secret.TLSCrtDataName: cert,
+				secret.TLSKeyDataName: key,
+			},
+		}
+	}
+
+	t.Run("uses a user provided CA and generates the missing certificates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := newFakeClient(g, caSecret(userCA.KeyPair.Cert, userCA.KeyPair.Key))
+		r := &KubeadmControlPlaneReconciler{Client: fakeClient}
+
+		kcp := kcp.DeepCopy()
+		certificates := secret.NewCertificatesForInitialControlPlane(nil)
+		g.Expect(r.reconcileCertificates(ctx, cluster, kcp, certificates, owner)).To(Succeed())
+
+		ca, err := secret.GetFromNamespacedName(ctx, fakeClient, util.ObjectKey(cluster), secret.ClusterCA)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ca.Data[secret.TLSCrtDataName]).To(Equal(userCA.KeyPair.Cert))
+		g.Expect(certificates.GetByPurpose(secret.ClusterCA).Generated).To(BeFalse())
+
+		for _, purpose := range []secret.Purpose{secret.EtcdCA, secret.FrontProxyCA, secret.ServiceAccount} {
+			_, err := secret.GetFromNamespacedName(ctx, fakeClient, util.ObjectKey(cluster), purpose)
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	t.Run("rejects a user provided CA with a key not matching the certificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := newFakeClient(g, caSecret(userCA.KeyPair.Cert, otherCA.KeyPair.Key))
+		r := &KubeadmControlPlaneReconciler{Client: fakeClient}
+
+		kcp := kcp.DeepCopy()
+		certificates := secret.NewCertificatesForInitialControlPlane(nil)
+		g.Expect(r.reconcileCertificates(ctx, cluster, kcp, certificates, owner)).NotTo(Succeed())
+		g.Expect(conditions.IsFalse(kcp, controlplanev1.CertificatesAvailableCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(kcp, controlplanev1.CertificatesAvailableCondition)).To(Equal(controlplanev1.CertificatesInvalidReason))
+		g.Expect(*conditions.GetSeverity(kcp, controlplanev1.CertificatesAvailableCondition)).To(Equal(clusterv1.ConditionSeverityError))
+
+		// No other certificates must be generated until the user provided CA is fixed.
+		_, err := secret.GetFromNamespacedName(ctx, fakeClient, util.ObjectKey(cluster), secret.EtcdCA)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("rejects a malformed user provided CA", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := newFakeClient(g, caSecret([]byte("foo"), []byte("bar")))
+		r := &KubeadmControlPlaneReconciler{Client: fakeClient}
+
+		kcp := kcp.DeepCopy()
+		certificates := secret.NewCertificatesForInitialControlPlane(nil)
+		g.Expect(r.reconcileCertificates(ctx, cluster, kcp, certificates, owner)).NotTo(Succeed())
+		g.Expect(conditions.GetReason(kcp, controlplanev1.CertificatesAvailableCondition)).To(Equal(controlplanev1.CertificatesInvalidReason))
+	})
+
+	t.Run("reports missing external certificates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := newFakeClient(g)
+		r := &KubeadmControlPlaneReconciler{Client: fakeClient}
+
+		kcp := kcp.DeepCopy()
+		certificates := secret.NewCertificatesForInitialControlPlane(&kubeadmv1.ClusterConfiguration{
+			Etcd: kubeadmv1.Etcd{
+				External: &kubeadmv1.ExternalEtcd{},
+			},
+		})
+		g.Expect(r.reconcileCertificates(ctx, cluster, kcp, certificates, owner)).NotTo(Succeed())
+		g.Expect(conditions.GetReason(kcp, controlplanev1.CertificatesAvailableCondition)).To(Equal(controlplanev1.CertificatesMissingReason))
+	})
+}
+
 func TestKubeadmControlPlaneReconciler_adoption(t *testing.T) {
 	version := "v2.0.0"
 	t.Run("adopts existing Machines", func(t *testing.T) {
@@ -1102,6 +1200,264 @@ kubernetesVersion: metav1.16.1`,
 	})
 }
 
+func TestKubeadmControlPlaneReconciler_reconcileCertificateExpiries(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster, kcp, _ := createClusterWithControlPlane()
+
+	knownExpiry := metav1.NewTime(time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second))
+	nearExpiry := time.Now().Add(3 * 24 * time.Hour).Truncate(time.Second)
+
+	// A machine with a known expiry date, which should not be inspected again.
+	machineWithExpiry, _ := createMachineNodePair("machine-with-expiry", cluster, kcp, true)
+	machineWithExpiry.Status.CertificatesExpiryDate = &knownExpiry
+	// A machine with certificates about to expire.
+	machineNearExpiry, _ := createMachineNodePair("machine-near-expiry", cluster, kcp, true)
+	// A machine for which reading the certificates fails.
+	machineUnreachable, _ := createMachineNodePair("machine-unreachable", cluster, kcp, true)
+	// A machine without a node yet.
+	machineWithoutNode, _ := createMachineNodePair("machine-without-node", cluster, kcp, true)
+	machineWithoutNode.Status.NodeRef = nil
+	// A machine whose certificate is missing the control plane endpoint SAN.
+	machineMissingSAN, _ := createMachineNodePair("machine-missing-san", cluster, kcp, true)
+
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "cp.example.com", Port: 6443}
+	kcp.Spec.KubeadmConfigSpec.ClusterConfiguration = &kubeadmv1.ClusterConfiguration{
+		APIServer: kubeadmv1.APIServer{CertSANs: []string{"lb.example.com"}},
+	}
+
+	machines := collections.FromMachines(machineWithExpiry, machineNearExpiry, machineUnreachable, machineWithoutNode, machineMissingSAN)
+	fakeClient := newFakeClient(g, machineWithExpiry.DeepCopy(), machineNearExpiry.DeepCopy(), machineUnreachable.DeepCopy(), machineWithoutNode.DeepCopy(), machineMissingSAN.DeepCopy())
+
+	r := &KubeadmControlPlaneReconciler{
+		Client: fakeClient,
+		managementCluster: &fakeManagementCluster{
+			Workload: fakeWorkloadCluster{
+				APIServerCertificateExpiry: map[string]time.Time{
+					machineWithExpiry.Status.NodeRef.Name: time.Now().Add(100 * 24 * time.Hour),
+					machineNearExpiry.Status.NodeRef.Name: nearExpiry,
+					machineMissingSAN.Status.NodeRef.Name: nearExpiry,
+				},
+				APIServerCertificateDNSNames: map[string][]string{
+					machineNearExpiry.Status.NodeRef.Name: {"cp.example.com", "lb.example.com"},
+					machineMissingSAN.Status.NodeRef.Name: {"lb.example.com"},
+				},
+			},
+		},
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	controlPlane := &internal.ControlPlane{
+		KCP:      kcp,
+		Cluster:  cluster,
+		Machines: machines,
+	}
+	g.Expect(r.reconcileCertificateExpiries(ctx, controlPlane)).To(Succeed())
+
+	gotMachine := &clusterv1.Machine{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machineWithExpiry), gotMachine)).To(Succeed())
+	g.Expect(gotMachine.Status.CertificatesExpiryDate.Time).To(BeTemporally("==", knownExpiry.Time))
+
+	gotMachine = &clusterv1.Machine{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machineNearExpiry), gotMachine)).To(Succeed())
+	g.Expect(gotMachine.Status.CertificatesExpiryDate).ToNot(BeNil())
+	g.Expect(gotMachine.Status.CertificatesExpiryDate.Time).To(BeTemporally("==", nearExpiry))
+	g.Expect(gotMachine.Annotations).ToNot(HaveKey(controlplanev1.APIServerCertificateMissingSANsAnnotation))
+
+	gotMachine = &clusterv1.Machine{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machineMissingSAN), gotMachine)).To(Succeed())
+	g.Expect(gotMachine.Status.CertificatesExpiryDate).ToNot(BeNil())
+	g.Expect(gotMachine.Annotations).To(HaveKeyWithValue(controlplanev1.APIServerCertificateMissingSANsAnnotation, "cp.example.com"))
+
+	gotMachine = &clusterv1.Machine{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machineUnreachable), gotMachine)).To(Succeed())
+	g.Expect(gotMachine.Status.CertificatesExpiryDate).To(BeNil())
+
+	gotMachine = &clusterv1.Machine{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machineWithoutNode), gotMachine)).To(Succeed())
+	g.Expect(gotMachine.Status.CertificatesExpiryDate).To(BeNil())
+}
+
+func TestKubeadmControlPlaneReconciler_reconcileEtcdDefragmentation(t *testing.T) {
+	setup := func(lastDefragmentation map[string]time.Time) (*KubeadmControlPlaneReconciler, *internal.ControlPlane, *[]string) {
+		cluster, kcp, _ := createClusterWithControlPlane()
+		kcp.Spec.EtcdDefragmentation = &controlplanev1.EtcdDefragmentation{
+			Interval:        metav1.Duration{Duration: 24 * time.Hour},
+			DBSizeThreshold: resource.NewQuantity(100, resource.BinarySI),
+		}
+		conditions.MarkTrue(kcp, controlplanev1.EtcdClusterHealthyCondition)
+		for name, last := range lastDefragmentation {
+			kcp.Status.EtcdMembers = append(kcp.Status.EtcdMembers, controlplanev1.EtcdMemberStatus{
+				Name:                    name,
+				LastDefragmentationTime: &metav1.Time{Time: last},
+			})
+		}
+
+		machines := collections.New()
+		objs := []client.Object{}
+		for _, name := range []string{"machine-a", "machine-b", "machine-c", "machine-small"} {
+			m, _ := createMachineNodePair(name, cluster, kcp, true)
+			machines.Insert(m)
+			objs = append(objs, m)
+		}
+
+		defragmented := &[]string{}
+		r := &KubeadmControlPlaneReconciler{
+			Client: helpers.NewFakeClientWithScheme(scheme.Scheme, objs...),
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{
+					EtcdMembersStatusResult: map[string]*etcd.MemberStatus{
+						"machine-a":     {ID: 1, IsLeader: true, DBSize: 1000},
+						"machine-b":     {ID: 2, DBSize: 1000},
+						"machine-c":     {ID: 3, DBSize: 1000},
+						"machine-small": {ID: 4, DBSize: 10},
+					},
+					DefragmentedEtcdMembers: defragmented,
+				},
+			},
+			recorder: record.NewFakeRecorder(32),
+		}
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcp,
+			Cluster:  cluster,
+			Machines: machines,
+		}
+		return r, controlPlane, defragmented
+	}
+
+	// ageDefragmentations moves the recorded defragmentation times back, as if the given time had passed.
+	ageDefragmentations := func(controlPlane *internal.ControlPlane, d time.Duration) {
+		for i := range controlPlane.KCP.Status.EtcdMembers {
+			if last := controlPlane.KCP.Status.EtcdMembers[i].LastDefragmentationTime; last != nil {
+				controlPlane.KCP.Status.EtcdMembers[i].LastDefragmentationTime = &metav1.Time{Time: last.Add(-d)}
+			}
+		}
+		for _, m := range controlPlane.Machines {
+			if last := etcdLastDefragmentationTime(m); last != nil {
+				m.Annotations[controlplanev1.EtcdLastDefragmentationTimeAnnotation] = last.Add(-d).UTC().Format(time.RFC3339)
+			}
+		}
+	}
+
+	t.Run("defragments one member at a time, with the leader last", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, defragmented := setup(nil)
+
+		// The 24h interval is staggered across the 4 members.
+		for i, expected := range []string{"machine-b", "machine-c", "machine-a"} {
+			result, err := r.reconcileEtcdDefragmentation(ctx, controlPlane)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(Equal(6 * time.Hour))
+			g.Expect(*defragmented).To(HaveLen(i + 1))
+			g.Expect((*defragmented)[i]).To(Equal(expected))
+			g.Expect(controlPlane.Machines[expected].Annotations).To(HaveKey(controlplanev1.EtcdLastDefragmentationTimeAnnotation))
+
+			ageDefragmentations(controlPlane, 6*time.Hour)
+		}
+
+		// All the members above the threshold are defragmented, so nothing is done until the first of them is due.
+		result, err := r.reconcileEtcdDefragmentation(ctx, controlPlane)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(BeNumerically("~", 6*time.Hour, time.Minute))
+		g.Expect(*defragmented).To(HaveLen(3))
+
+		g.Expect(controlPlane.KCP.Status.EtcdMembers).To(HaveLen(4))
+		for _, member := range controlPlane.KCP.Status.EtcdMembers {
+			if member.Name == "machine-small" {
+				g.Expect(member.LastDefragmentationTime).To(BeNil())
+				g.Expect(member.DBSize.Value()).To(Equal(int64(10)))
+				continue
+			}
+			g.Expect(member.LastDefragmentationTime).NotTo(BeNil())
+			g.Expect(member.DBSize.Value()).To(Equal(int64(1000)))
+		}
+	})
+
+	t.Run("skips members defragmented within the interval", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, defragmented := setup(map[string]time.Time{
+			"machine-b": time.Now().Add(-7 * time.Hour),
+			"machine-c": time.Now().Add(-25 * time.Hour),
+		})
+
+		_, err := r.reconcileEtcdDefragmentation(ctx, controlPlane)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(*defragmented).To(Equal([]string{"machine-c"}))
+	})
+
+	t.Run("waits between the defragmentations of two members", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, defragmented := setup(map[string]time.Time{
+			"machine-b": time.Now().Add(-1 * time.Hour),
+		})
+
+		result, err := r.reconcileEtcdDefragmentation(ctx, controlPlane)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(BeNumerically("~", 5*time.Hour, time.Minute))
+		g.Expect(*defragmented).To(BeEmpty())
+	})
+
+	t.Run("uses the defragmentation times recorded on the machines", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The status is empty, e.g. because the cluster was moved to another management cluster.
+		r, controlPlane, defragmented := setup(nil)
+		for _, name := range []string{"machine-a", "machine-b"} {
+			controlPlane.Machines[name].Annotations = map[string]string{
+				controlplanev1.EtcdLastDefragmentationTimeAnnotation: time.Now().Add(-7 * time.Hour).UTC().Format(time.RFC3339),
+			}
+		}
+
+		_, err := r.reconcileEtcdDefragmentation(ctx, controlPlane)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(*defragmented).To(Equal([]string{"machine-c"}))
+		for _, member := range controlPlane.KCP.Status.EtcdMembers {
+			if member.Name == "machine-a" || member.Name == "machine-b" {
+				g.Expect(member.LastDefragmentationTime).NotTo(BeNil())
+			}
+		}
+	})
+
+	t.Run("does nothing when etcd is not healthy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, defragmented := setup(nil)
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityError, "")
+
+		result, err := r.reconcileEtcdDefragmentation(ctx, controlPlane)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(*defragmented).To(BeEmpty())
+	})
+
+	t.Run("does nothing when a member cannot be contacted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, defragmented := setup(nil)
+		m, _ := createMachineNodePair("machine-unreachable", controlPlane.Cluster, controlPlane.KCP, true)
+		controlPlane.Machines.Insert(m)
+
+		_, err := r.reconcileEtcdDefragmentation(ctx, controlPlane)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(*defragmented).To(BeEmpty())
+	})
+
+	t.Run("clears the members status when defragmentation is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, defragmented := setup(map[string]time.Time{"machine-b": time.Now()})
+		controlPlane.KCP.Spec.EtcdDefragmentation = nil
+
+		_, err := r.reconcileEtcdDefragmentation(ctx, controlPlane)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(*defragmented).To(BeEmpty())
+		g.Expect(controlPlane.KCP.Status.EtcdMembers).To(BeNil())
+	})
+}
+
 func TestKubeadmControlPlaneReconciler_reconcileDelete(t *testing.T) {
 	t.Run("removes all control plane Machines", func(t *testing.T) {
 		g := NewWithT(t)
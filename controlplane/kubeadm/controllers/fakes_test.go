@@ -18,9 +18,14 @@ package controllers
 
 import (
 	"context"
+	"crypto/x509"
+	"time"
+
 	"github.com/blang/semver"
+	"github.com/pkg/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -56,6 +61,33 @@ type fakeWorkloadCluster struct {
 	*internal.Workload
 	Status            internal.ClusterStatus
 	EtcdMembersResult []string
+	// EtcdLeaderResult is the name of the node hosting the etcd leader.
+	EtcdLeaderResult string
+	// APIServerCertificateExpiry maps node names to the expiry date of the kube-apiserver certificate.
+	APIServerCertificateExpiry map[string]time.Time
+	// APIServerCertificateDNSNames maps node names to the DNS names of the kube-apiserver certificate.
+	APIServerCertificateDNSNames map[string][]string
+	// EtcdMembersStatusResult maps node names to the status of the etcd member hosted on the node.
+	EtcdMembersStatusResult map[string]*etcd.MemberStatus
+	// DefragmentedEtcdMembers records the nodes for which the etcd member was defragmented, in order.
+	DefragmentedEtcdMembers *[]string
+}
+
+func (f fakeWorkloadCluster) EtcdMembersStatus(_ context.Context, nodeNames []string) (map[string]*etcd.MemberStatus, error) {
+	statuses := map[string]*etcd.MemberStatus{}
+	for _, nodeName := range nodeNames {
+		status, ok := f.EtcdMembersStatusResult[nodeName]
+		if !ok {
+			return nil, errors.Errorf("failed to connect to the etcd member on node %s", nodeName)
+		}
+		statuses[nodeName] = status
+	}
+	return statuses, nil
+}
+
+func (f fakeWorkloadCluster) DefragmentEtcdMember(_ context.Context, nodeName string) error {
+	*f.DefragmentedEtcdMembers = append(*f.DefragmentedEtcdMembers, nodeName)
+	return nil
 }
 
 func (f fakeWorkloadCluster) ForwardEtcdLeadership(_ context.Context, _ *clusterv1.Machine, _ *clusterv1.Machine) error {
@@ -106,6 +138,18 @@ func (f fakeWorkloadCluster) EtcdMembers(_ context.Context) ([]string, error) {
 	return f.EtcdMembersResult, nil
 }
 
+func (f fakeWorkloadCluster) EtcdLeader(_ context.Context) (string, error) {
+	return f.EtcdLeaderResult, nil
+}
+
+func (f fakeWorkloadCluster) GetAPIServerCertificate(_ context.Context, nodeName string) (*x509.Certificate, error) {
+	expiry, ok := f.APIServerCertificateExpiry[nodeName]
+	if !ok {
+		return nil, errors.Errorf("failed to connect to the kube-apiserver on node %s", nodeName)
+	}
+	return &x509.Certificate{NotAfter: expiry, DNSNames: f.APIServerCertificateDNSNames[nodeName]}, nil
+}
+
 type fakeMigrator struct {
 	migrateCalled    bool
 	migrateErr       error
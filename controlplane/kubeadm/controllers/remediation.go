@@ -37,7 +37,8 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 	log := ctrl.LoggerFrom(ctx)
 
 	// Gets all machines that have `MachineHealthCheckSucceeded=False` (indicating a problem was detected on the machine)
-	// and `MachineOwnerRemediated` present, indicating that this controller is responsible for performing remediation.
+	// and `MachineOwnerRemediated` present, indicating that this controller is responsible for performing remediation,
+	// as well as, when spec.remediation is set, the machines failing the KCP health checks for longer than the unhealthy timeout.
 	unhealthyMachines := controlPlane.UnhealthyMachines()
 
 	// If there are no unhealthy machines, return so KCP can proceed with other operations (ctrl.Result nil).
@@ -199,7 +200,16 @@ func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Co
 		return false, nil
 	}
 
-	targetTotalMembers := currentTotalMembers - 1
+	return etcdQuorumPreservedWithoutMember(ctx, controlPlane, etcdMembers, machineToBeRemediated), nil
+}
+
+// etcdQuorumPreservedWithoutMember assess if the etcd cluster keeps quorum once the member hosted on machineToBeRemoved
+// is removed, considering as unhealthy all the other members without a corresponding machine or reporting the
+// MachineEtcdMemberHealthyCondition as not true.
+func etcdQuorumPreservedWithoutMember(ctx context.Context, controlPlane *internal.ControlPlane, etcdMembers []string, machineToBeRemoved *clusterv1.Machine) bool {
+	log := ctrl.LoggerFrom(ctx)
+
+	targetTotalMembers := len(etcdMembers) - 1
 	targetQuorum := targetTotalMembers/2.0 + 1
 	targetUnhealthyMembers := 0
 
@@ -207,7 +217,7 @@ func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Co
 	unhealthyMembers := []string{}
 	for _, etcdMember := range etcdMembers {
 		// Skip the machine to be deleted because it won't be part of the target etcd cluster.
-		if machineToBeRemediated.Status.NodeRef != nil && machineToBeRemediated.Status.NodeRef.Name == etcdMember {
+		if machineToBeRemoved.Status.NodeRef != nil && machineToBeRemoved.Status.NodeRef.Name == etcdMember {
 			continue
 		}
 
@@ -241,15 +251,12 @@ func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Co
 		healthyMembers = append(healthyMembers, fmt.Sprintf("%s (%s)", etcdMember, machine.Name))
 	}
 
-	log.Info(fmt.Sprintf("etcd cluster projected after remediation of %s", machineToBeRemediated.Name),
+	log.Info(fmt.Sprintf("etcd cluster projected after removal of %s", machineToBeRemoved.Name),
 		"healthyMembers", healthyMembers,
 		"unhealthyMembers", unhealthyMembers,
 		"targetTotalMembers", targetTotalMembers,
 		"targetQuorum", targetQuorum,
 		"targetUnhealthyMembers", targetUnhealthyMembers,
 		"projectedQuorum", targetTotalMembers-targetUnhealthyMembers)
-	if targetTotalMembers-targetUnhealthyMembers >= targetQuorum {
-		return true, nil
-	}
-	return false, nil
+	return targetTotalMembers-targetUnhealthyMembers >= targetQuorum
 }
@@ -51,7 +51,11 @@ func (r *KubeadmControlPlaneReconciler) initializeControlPlane(ctx context.Conte
 	}
 
 	bootstrapSpec := controlPlane.InitialControlPlaneConfig()
-	fd := controlPlane.NextFailureDomainForScaleUp()
+	fd, err := controlPlane.NextFailureDomainForScaleUp()
+	if err != nil {
+		logger.Info("Cannot place the initial control plane Machine within the failure domain spread, waiting", "cause", err.Error())
+		return ctrl.Result{}, nil
+	}
 	if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, fd); err != nil {
 		logger.Error(err, "Failed to create initial control plane Machine")
 		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedInitialization", "Failed to create initial control plane Machine for cluster %s/%s control plane: %v", cluster.Namespace, cluster.Name, err)
@@ -72,7 +76,11 @@ func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context,
 
 	// Create the bootstrap configuration
 	bootstrapSpec := controlPlane.JoinControlPlaneConfig()
-	fd := controlPlane.NextFailureDomainForScaleUp()
+	fd, err := controlPlane.NextFailureDomainForScaleUp()
+	if err != nil {
+		logger.Info("Cannot place an additional control plane Machine within the failure domain spread, waiting", "cause", err.Error())
+		return ctrl.Result{}, nil
+	}
 	if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, fd); err != nil {
 		logger.Error(err, "Failed to create additional control plane Machine")
 		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedScaleUp", "Failed to create additional control plane Machine for cluster %s/%s control plane: %v", cluster.Namespace, cluster.Name, err)
@@ -92,8 +100,24 @@ func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 ) (ctrl.Result, error) {
 	logger := controlPlane.Logger()
 
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		logger.Error(err, "Failed to create client to workload cluster")
+		return ctrl.Result{}, errors.Wrapf(err, "failed to create client to workload cluster")
+	}
+
+	// Determine the node hosting the etcd leader, so it can be deleted last; this is best effort,
+	// and the selection falls back to ignoring the leader if it can't be determined.
+	etcdLeaderNodeName := ""
+	if controlPlane.IsEtcdManaged() {
+		etcdLeaderNodeName, err = workloadCluster.EtcdLeader(ctx)
+		if err != nil {
+			logger.V(2).Info("Failed to determine the etcd leader, ignoring it while selecting the machine to delete", "err", err.Error())
+		}
+	}
+
 	// Pick the Machine that we should scale down.
-	machineToDelete, err := selectMachineForScaleDown(controlPlane, outdatedMachines)
+	machineToDelete, err := selectMachineForScaleDown(controlPlane, outdatedMachines, etcdLeaderNodeName)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to select machine for scale down")
 	}
@@ -104,17 +128,18 @@ func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 		return result, err
 	}
 
-	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(cluster))
-	if err != nil {
-		logger.Error(err, "Failed to create client to workload cluster")
-		return ctrl.Result{}, errors.Wrapf(err, "failed to create client to workload cluster")
-	}
-
 	if machineToDelete == nil {
 		logger.Info("Failed to pick control plane Machine to delete")
 		return ctrl.Result{}, errors.New("failed to pick control plane Machine to delete")
 	}
 
+	// If KCP should manage etcd, make sure that removing the etcd member hosted on the machine to delete preserves etcd quorum; if not, wait.
+	if controlPlane.IsEtcdManaged() {
+		if result, err := r.preflightChecksEtcdQuorum(ctx, controlPlane, workloadCluster, machineToDelete); err != nil || !result.IsZero() {
+			return result, err
+		}
+	}
+
 	// If KCP should manage etcd, If etcd leadership is on machine that is about to be deleted, move it to the newest member available.
 	if controlPlane.IsEtcdManaged() {
 		etcdLeaderCandidate := controlPlane.Machines.Newest()
@@ -211,6 +236,50 @@ loopmachines:
 	return ctrl.Result{}, nil
 }
 
+// preflightChecksEtcdQuorum checks if the etcd member hosted on the machine to delete can be removed without etcd
+// losing quorum, where it is required that:
+// - The list of etcd members can be retrieved from the etcd cluster.
+// - Once the member is removed, the remaining healthy members are still a majority of the etcd cluster.
+// If the etcd cluster is not passing preflight checks, the EtcdClusterHealthy condition on KCP reports the failing check
+// and it requeue.
+//
+// NOTE: this func uses machine conditions, it is required to call reconcileControlPlaneConditions before this.
+func (r *KubeadmControlPlaneReconciler) preflightChecksEtcdQuorum(ctx context.Context, controlPlane *internal.ControlPlane, workloadCluster internal.WorkloadCluster, machineToDelete *clusterv1.Machine) (ctrl.Result, error) {
+	logger := controlPlane.Logger()
+
+	etcdMembers, err := workloadCluster.EtcdMembers(ctx)
+	if err != nil {
+		conditions.MarkUnknown(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterInspectionFailedReason, "Failed to get etcd members")
+		return ctrl.Result{}, errors.Wrap(err, "failed to get etcd members")
+	}
+
+	// A machine not hosting an etcd member can be removed without any impact on etcd quorum.
+	hostsEtcdMember := false
+	if machineToDelete.Status.NodeRef != nil {
+		for _, etcdMember := range etcdMembers {
+			if etcdMember == machineToDelete.Status.NodeRef.Name {
+				hostsEtcdMember = true
+				break
+			}
+		}
+	}
+	if !hostsEtcdMember {
+		return ctrl.Result{}, nil
+	}
+
+	if !etcdQuorumPreservedWithoutMember(ctx, controlPlane, etcdMembers, machineToDelete) {
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityError,
+			"Removing the etcd member hosted on Machine %s would result in etcd losing quorum", machineToDelete.Name)
+		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "ControlPlaneUnhealthy",
+			"Waiting for control plane to pass preflight checks to continue reconciliation: removing the etcd member hosted on Machine %s would result in etcd losing quorum", machineToDelete.Name)
+		logger.Info("Waiting for control plane to pass preflight checks", "failures", "removing the etcd member would result in etcd losing quorum", "machine", machineToDelete.Name)
+
+		return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
 func preflightCheckCondition(kind string, obj conditions.Getter, condition clusterv1.ConditionType) error {
 	c := conditions.Get(obj, condition)
 	if c == nil {
@@ -225,7 +294,11 @@ func preflightCheckCondition(kind string, obj conditions.Getter, condition clust
 	return nil
 }
 
-func selectMachineForScaleDown(controlPlane *internal.ControlPlane, outdatedMachines collections.Machines) (*clusterv1.Machine, error) {
+// selectMachineForScaleDown picks the machine to delete, preferring machines with the delete annotation,
+// then outdated machines, then machines in the failure domain with the most machines.
+// Among the otherwise equal candidates, the machine hosting the etcd leader is selected last, so that
+// the etcd leadership (and the connection the management cluster has with it) is moved only when necessary.
+func selectMachineForScaleDown(controlPlane *internal.ControlPlane, outdatedMachines collections.Machines, etcdLeaderNodeName string) (*clusterv1.Machine, error) {
 	machines := controlPlane.Machines
 	switch {
 	case controlPlane.MachineWithDeleteAnnotation(outdatedMachines).Len() > 0:
@@ -235,5 +308,31 @@ func selectMachineForScaleDown(controlPlane *internal.ControlPlane, outdatedMach
 	case outdatedMachines.Len() > 0:
 		machines = outdatedMachines
 	}
+
+	// The machine hosting the etcd leader is deleted last, to avoid an unnecessary leader election.
+	if etcdLeaderNodeName != "" {
+		return controlPlane.MachineInFailureDomainWithMostMachines(machines, hasNodeName(etcdLeaderNodeName))
+	}
 	return controlPlane.MachineInFailureDomainWithMostMachines(machines)
 }
+
+// hasNodeName returns a filter to find the machine with the given node name.
+func hasNodeName(nodeName string) collections.Func {
+	return func(machine *clusterv1.Machine) bool {
+		return machine.Status.NodeRef != nil && machine.Status.NodeRef.Name == nodeName
+	}
+}
+
+// setFailureDomainSpreadCondition reports whether the control plane failure domains of the cluster can satisfy
+// the failure domain spread of the KubeadmControlPlane, if set.
+func setFailureDomainSpreadCondition(controlPlane *internal.ControlPlane) {
+	if controlPlane.KCP.Spec.FailureDomainSpread == nil {
+		conditions.Delete(controlPlane.KCP, controlplanev1.FailureDomainSpreadSatisfiedCondition)
+		return
+	}
+	if err := controlPlane.FailureDomainSpreadError(); err != nil {
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.FailureDomainSpreadSatisfiedCondition, controlplanev1.FailureDomainSpreadUnsatisfiableReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return
+	}
+	conditions.MarkTrue(controlPlane.KCP, controlplanev1.FailureDomainSpreadSatisfiedCondition)
+}
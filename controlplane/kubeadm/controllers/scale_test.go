@@ -27,11 +27,14 @@ import (
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api/util/conditions"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -122,6 +125,53 @@ func TestKubeadmControlPlaneReconciler_scaleUpControlPlane(t *testing.T) {
 		g.Expect(fakeClient.List(ctx, &controlPlaneMachines)).To(Succeed())
 		g.Expect(controlPlaneMachines.Items).To(HaveLen(3))
 	})
+	t.Run("does not create a control plane Machine if all the failure domains have the maximum number of machines", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster, kcp, genericMachineTemplate := createClusterWithControlPlane()
+		cluster.Status.FailureDomains = clusterv1.FailureDomains{
+			"one": clusterv1.FailureDomainSpec{ControlPlane: true},
+		}
+		kcp.Spec.FailureDomainSpread = &controlplanev1.FailureDomainSpread{MaxMachinesPerFailureDomain: pointer.Int32Ptr(2)}
+		setKCPHealthy(kcp)
+		initObjs := []client.Object{cluster.DeepCopy(), kcp.DeepCopy(), genericMachineTemplate.DeepCopy()}
+
+		fmc := &fakeManagementCluster{
+			Machines: collections.New(),
+			Workload: fakeWorkloadCluster{},
+		}
+
+		for i := 0; i < 2; i++ {
+			m, _ := createMachineNodePair(fmt.Sprintf("test-%d", i), cluster, kcp, true)
+			m.Spec.Version = &kcp.Spec.Version
+			m.Spec.FailureDomain = pointer.StringPtr("one")
+			setMachineHealthy(m)
+			fmc.Machines.Insert(m)
+			initObjs = append(initObjs, m.DeepCopy())
+		}
+
+		fakeClient := newFakeClient(g, initObjs...)
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:                    fakeClient,
+			managementCluster:         fmc,
+			managementClusterUncached: fmc,
+			recorder:                  record.NewFakeRecorder(32),
+		}
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcp,
+			Cluster:  cluster,
+			Machines: fmc.Machines,
+		}
+
+		result, err := r.scaleUpControlPlane(ctx, cluster, kcp, controlPlane)
+		g.Expect(result).To(Equal(ctrl.Result{}))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		controlPlaneMachines := clusterv1.MachineList{}
+		g.Expect(fakeClient.List(ctx, &controlPlaneMachines)).To(Succeed())
+		g.Expect(controlPlaneMachines.Items).To(HaveLen(2))
+	})
 	t.Run("does not create a control plane Machine if preflight checks fail", func(t *testing.T) {
 		cluster, kcp, genericMachineTemplate := createClusterWithControlPlane()
 		initObjs := []client.Object{cluster.DeepCopy(), kcp.DeepCopy(), genericMachineTemplate.DeepCopy()}
@@ -279,6 +329,139 @@ func TestKubeadmControlPlaneReconciler_scaleDownControlPlane_NoError(t *testing.
 	})
 }
 
+func TestKubeadmControlPlaneReconciler_scaleDownControlPlane_EtcdQuorum(t *testing.T) {
+	tests := []struct {
+		name                 string
+		machines             int
+		orphanEtcdMembers    []string
+		excludeFromEtcd      bool
+		externalEtcd         bool
+		expectResult         ctrl.Result
+		expectMachinesLeft   int
+		expectEtcdNotHealthy bool
+	}{
+		{
+			name:               "deletes the control plane Machine if etcd keeps quorum on a 3 members cluster",
+			machines:           3,
+			expectResult:       ctrl.Result{Requeue: true},
+			expectMachinesLeft: 2,
+		},
+		{
+			name:               "deletes the control plane Machine if etcd keeps quorum with an additional member without machine",
+			machines:           3,
+			orphanEtcdMembers:  []string{"orphan-1"},
+			expectResult:       ctrl.Result{Requeue: true},
+			expectMachinesLeft: 2,
+		},
+		{
+			name:                 "does not delete the control plane Machine if etcd loses quorum on a 3 machines cluster with 2 members without machine",
+			machines:             3,
+			orphanEtcdMembers:    []string{"orphan-1", "orphan-2"},
+			expectResult:         ctrl.Result{RequeueAfter: preflightFailedRequeueAfter},
+			expectMachinesLeft:   3,
+			expectEtcdNotHealthy: true,
+		},
+		{
+			name:               "deletes the control plane Machine if etcd keeps quorum on a 5 machines cluster with 2 members without machine",
+			machines:           5,
+			orphanEtcdMembers:  []string{"orphan-1", "orphan-2"},
+			expectResult:       ctrl.Result{Requeue: true},
+			expectMachinesLeft: 4,
+		},
+		{
+			name:                 "does not delete the control plane Machine if etcd loses quorum on a 5 machines cluster with 4 members without machine",
+			machines:             5,
+			orphanEtcdMembers:    []string{"orphan-1", "orphan-2", "orphan-3", "orphan-4"},
+			expectResult:         ctrl.Result{RequeueAfter: preflightFailedRequeueAfter},
+			expectMachinesLeft:   5,
+			expectEtcdNotHealthy: true,
+		},
+		{
+			name:               "deletes the control plane Machine without checking etcd quorum if etcd is external",
+			machines:           3,
+			orphanEtcdMembers:  []string{"orphan-1", "orphan-2"},
+			externalEtcd:       true,
+			expectResult:       ctrl.Result{Requeue: true},
+			expectMachinesLeft: 2,
+		},
+		{
+			name:               "deletes the control plane Machine if it does not host an etcd member",
+			machines:           3,
+			orphanEtcdMembers:  []string{"orphan-1", "orphan-2"},
+			excludeFromEtcd:    true,
+			expectResult:       ctrl.Result{Requeue: true},
+			expectMachinesLeft: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machines := collections.New()
+			objs := []client.Object{}
+			etcdMembers := []string{}
+			for i := 0; i < tt.machines; i++ {
+				name := fmt.Sprintf("machine-%d", i)
+				// The first machine is the oldest one, and thus the one selected for scale down.
+				m := machine(name, withTimestamp(time.Now().Add(time.Duration(i)*time.Minute)))
+				m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: fmt.Sprintf("node-%d", i)}
+				setMachineHealthy(m)
+				machines.Insert(m)
+				objs = append(objs, m)
+				if i == 0 && tt.excludeFromEtcd {
+					continue
+				}
+				etcdMembers = append(etcdMembers, m.Status.NodeRef.Name)
+			}
+			etcdMembers = append(etcdMembers, tt.orphanEtcdMembers...)
+			fakeClient := newFakeClient(g, objs...)
+
+			r := &KubeadmControlPlaneReconciler{
+				recorder: record.NewFakeRecorder(32),
+				Client:   fakeClient,
+				managementCluster: &fakeManagementCluster{
+					Workload: fakeWorkloadCluster{
+						EtcdMembersResult: etcdMembers,
+					},
+				},
+			}
+
+			cluster := &clusterv1.Cluster{}
+			kcp := &controlplanev1.KubeadmControlPlane{}
+			if tt.externalEtcd {
+				kcp.Spec.KubeadmConfigSpec.ClusterConfiguration = &kubeadmv1.ClusterConfiguration{
+					Etcd: kubeadmv1.Etcd{
+						External: &kubeadmv1.ExternalEtcd{Endpoints: []string{"https://external-etcd:2379"}},
+					},
+				}
+			}
+			setKCPHealthy(kcp)
+			controlPlane := &internal.ControlPlane{
+				KCP:      kcp,
+				Cluster:  cluster,
+				Machines: machines,
+			}
+
+			result, err := r.scaleDownControlPlane(context.Background(), cluster, kcp, controlPlane, controlPlane.Machines)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(tt.expectResult))
+
+			controlPlaneMachines := clusterv1.MachineList{}
+			g.Expect(fakeClient.List(context.Background(), &controlPlaneMachines)).To(Succeed())
+			g.Expect(controlPlaneMachines.Items).To(HaveLen(tt.expectMachinesLeft))
+
+			if tt.expectEtcdNotHealthy {
+				g.Expect(conditions.IsFalse(kcp, controlplanev1.EtcdClusterHealthyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(kcp, controlplanev1.EtcdClusterHealthyCondition)).To(Equal(controlplanev1.EtcdClusterUnhealthyReason))
+				g.Expect(conditions.GetMessage(kcp, controlplanev1.EtcdClusterHealthyCondition)).To(ContainSubstring("machine-0"))
+			} else {
+				g.Expect(conditions.IsTrue(kcp, controlplanev1.EtcdClusterHealthyCondition)).To(BeTrue())
+			}
+		})
+	}
+}
+
 func TestSelectMachineForScaleDown(t *testing.T) {
 	kcp := controlplanev1.KubeadmControlPlane{
 		Spec: controlplanev1.KubeadmControlPlaneSpec{},
@@ -293,8 +476,13 @@ func TestSelectMachineForScaleDown(t *testing.T) {
 	m7 := machine("machine-7", withFailureDomain("two"), withTimestamp(startDate.Add(-5*time.Hour)), withAnnotation("cluster.x-k8s.io/delete-machine"))
 	m8 := machine("machine-8", withFailureDomain("two"), withTimestamp(startDate.Add(-6*time.Hour)), withAnnotation("cluster.x-k8s.io/delete-machine"))
 
+	m9 := machine("machine-9", withFailureDomain("one"), withTimestamp(startDate.Add(-3*time.Hour)), withNodeName("node-9"))
+	m10 := machine("machine-10", withFailureDomain("one"), withTimestamp(startDate.Add(-2*time.Hour)), withNodeName("node-10"))
+	m11 := machine("machine-11", withFailureDomain("one"), withTimestamp(startDate.Add(-time.Hour)), withNodeName("node-11"))
+
 	mc3 := collections.FromMachines(m1, m2, m3, m4, m5)
 	mc6 := collections.FromMachines(m6, m7, m8)
+	mc9 := collections.FromMachines(m9, m10, m11)
 	fd := clusterv1.FailureDomains{
 		"one": failureDomain(true),
 		"two": failureDomain(true),
@@ -318,12 +506,19 @@ func TestSelectMachineForScaleDown(t *testing.T) {
 		Machines: mc6,
 	}
 
+	etcdLeaderControlPlane := &internal.ControlPlane{
+		KCP:      &kcp,
+		Cluster:  &clusterv1.Cluster{Status: clusterv1.ClusterStatus{FailureDomains: fd}},
+		Machines: mc9,
+	}
+
 	testCases := []struct {
-		name             string
-		cp               *internal.ControlPlane
-		outDatedMachines collections.Machines
-		expectErr        bool
-		expectedMachine  clusterv1.Machine
+		name               string
+		cp                 *internal.ControlPlane
+		outDatedMachines   collections.Machines
+		etcdLeaderNodeName string
+		expectErr          bool
+		expectedMachine    clusterv1.Machine
 	}{
 		{
 			name:             "when there are machines needing upgrade, it returns the oldest machine in the failure domain with the most machines needing upgrade",
@@ -374,6 +569,38 @@ func TestSelectMachineForScaleDown(t *testing.T) {
 			expectErr:        false,
 			expectedMachine:  clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine-8"}},
 		},
+		{
+			name:               "when the oldest machine hosts the etcd leader, it returns the oldest machine not hosting the etcd leader",
+			cp:                 etcdLeaderControlPlane,
+			outDatedMachines:   collections.New(),
+			etcdLeaderNodeName: "node-9",
+			expectErr:          false,
+			expectedMachine:    clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine-10"}},
+		},
+		{
+			name:               "when the oldest machine does not host the etcd leader, it returns the oldest machine",
+			cp:                 etcdLeaderControlPlane,
+			outDatedMachines:   collections.New(),
+			etcdLeaderNodeName: "node-10",
+			expectErr:          false,
+			expectedMachine:    clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine-9"}},
+		},
+		{
+			name:               "when the outdated machines host the etcd leader, it returns the outdated machine not hosting the etcd leader",
+			cp:                 etcdLeaderControlPlane,
+			outDatedMachines:   collections.FromMachines(m9, m11),
+			etcdLeaderNodeName: "node-9",
+			expectErr:          false,
+			expectedMachine:    clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine-11"}},
+		},
+		{
+			name:               "when the only outdated machine hosts the etcd leader, it returns the machine hosting the etcd leader",
+			cp:                 etcdLeaderControlPlane,
+			outDatedMachines:   collections.FromMachines(m9),
+			etcdLeaderNodeName: "node-9",
+			expectErr:          false,
+			expectedMachine:    clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine-9"}},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -382,7 +609,7 @@ func TestSelectMachineForScaleDown(t *testing.T) {
 
 			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
 
-			selectedMachine, err := selectMachineForScaleDown(tc.cp, tc.outDatedMachines)
+			selectedMachine, err := selectMachineForScaleDown(tc.cp, tc.outDatedMachines, tc.etcdLeaderNodeName)
 
 			if tc.expectErr {
 				g.Expect(err).To(HaveOccurred())
@@ -568,3 +795,52 @@ func withTimestamp(t time.Time) machineOpt {
 		m.CreationTimestamp = metav1.NewTime(t)
 	}
 }
+
+func withNodeName(nodeName string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: nodeName}
+	}
+}
+
+func TestSetFailureDomainSpreadCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &internal.ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Replicas: pointer.Int32Ptr(3),
+			},
+		},
+		Cluster: &clusterv1.Cluster{
+			Status: clusterv1.ClusterStatus{
+				FailureDomains: clusterv1.FailureDomains{
+					"one": clusterv1.FailureDomainSpec{ControlPlane: true},
+					"two": clusterv1.FailureDomainSpec{ControlPlane: true},
+				},
+			},
+		},
+	}
+
+	// Without spread, there is no condition.
+	setFailureDomainSpreadCondition(controlPlane)
+	g.Expect(conditions.Has(controlPlane.KCP, controlplanev1.FailureDomainSpreadSatisfiedCondition)).To(BeFalse())
+
+	// A satisfiable spread.
+	controlPlane.KCP.Spec.FailureDomainSpread = &controlplanev1.FailureDomainSpread{
+		MaxMachinesPerFailureDomain: pointer.Int32Ptr(2),
+		MinFailureDomains:           pointer.Int32Ptr(2),
+	}
+	setFailureDomainSpreadCondition(controlPlane)
+	g.Expect(conditions.IsTrue(controlPlane.KCP, controlplanev1.FailureDomainSpreadSatisfiedCondition)).To(BeTrue())
+
+	// An unsatisfiable spread, with 3 replicas and at most one machine in each of the 2 failure domains.
+	controlPlane.KCP.Spec.FailureDomainSpread.MaxMachinesPerFailureDomain = pointer.Int32Ptr(1)
+	setFailureDomainSpreadCondition(controlPlane)
+	g.Expect(conditions.IsFalse(controlPlane.KCP, controlplanev1.FailureDomainSpreadSatisfiedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(controlPlane.KCP, controlplanev1.FailureDomainSpreadSatisfiedCondition)).To(Equal(controlplanev1.FailureDomainSpreadUnsatisfiableReason))
+
+	// Removing the spread removes the condition.
+	controlPlane.KCP.Spec.FailureDomainSpread = nil
+	setFailureDomainSpreadCondition(controlPlane)
+	g.Expect(conditions.Has(controlPlane.KCP, controlplanev1.FailureDomainSpreadSatisfiedCondition)).To(BeFalse())
+}
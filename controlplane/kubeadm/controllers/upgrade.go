@@ -88,7 +88,10 @@ func (r *KubeadmControlPlaneReconciler) upgradeControlPlane(
 	}
 
 	if kcp.Spec.KubeadmConfigSpec.ClusterConfiguration != nil {
-		if err := workloadCluster.UpdateAPIServerInKubeadmConfigMap(ctx, kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer); err != nil {
+		// The certSANs in the kubeadm config map are used by kubeadm when generating the certificates of the joining machines.
+		apiServer := *kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.DeepCopy()
+		apiServer.CertSANs = controlPlane.APIServerCertSANs()
+		if err := workloadCluster.UpdateAPIServerInKubeadmConfigMap(ctx, apiServer); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to update api server in the kubeadm config map")
 		}
 
@@ -18,6 +18,8 @@ package internal
 
 import (
 	"context"
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -27,11 +29,14 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/klog/klogr"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/failuredomains"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -131,9 +136,13 @@ func (c *ControlPlane) EtcdImageData() (string, string) {
 }
 
 // MachineInFailureDomainWithMostMachines returns the first matching failure domain with machines that has the most control-plane machines on it.
-func (c *ControlPlane) MachineInFailureDomainWithMostMachines(machines collections.Machines) (*clusterv1.Machine, error) {
+// Machines matching any of the deprioritized filters are only returned if there are no other machines in that failure domain.
+func (c *ControlPlane) MachineInFailureDomainWithMostMachines(machines collections.Machines, deprioritized ...collections.Func) (*clusterv1.Machine, error) {
 	fd := c.FailureDomainWithMostMachines(machines)
 	machinesInFailureDomain := machines.Filter(collections.InFailureDomains(fd))
+	if preferred := machinesInFailureDomain.Filter(collections.Not(collections.Or(deprioritized...))); preferred.Len() > 0 {
+		machinesInFailureDomain = preferred
+	}
 	machineToMark := machinesInFailureDomain.Oldest()
 	if machineToMark == nil {
 		return nil, errors.New("failed to pick control plane Machine to mark for deletion")
@@ -166,17 +175,59 @@ func (c *ControlPlane) FailureDomainWithMostMachines(machines collections.Machin
 }
 
 // NextFailureDomainForScaleUp returns the failure domain with the fewest number of up-to-date machines.
-func (c *ControlPlane) NextFailureDomainForScaleUp() *string {
-	if len(c.Cluster.Status.FailureDomains.FilterControlPlane()) == 0 {
+// When the failure domain spread limits the number of machines per failure domain, only the failure domains
+// below the limit are considered, and an error is returned if there are none.
+func (c *ControlPlane) NextFailureDomainForScaleUp() (*string, error) {
+	failureDomains := c.FailureDomains().FilterControlPlane()
+	upToDateMachines := c.UpToDateMachines()
+
+	if spread := c.KCP.Spec.FailureDomainSpread; spread != nil && spread.MaxMachinesPerFailureDomain != nil {
+		if len(failureDomains) == 0 {
+			return nil, errors.New("there are no control plane failure domains to place the machine in")
+		}
+		belowMax := clusterv1.FailureDomains{}
+		for id, fd := range failureDomains {
+			if len(upToDateMachines.Filter(collections.InFailureDomains(pointer.StringPtr(id)))) < int(*spread.MaxMachinesPerFailureDomain) {
+				belowMax[id] = fd
+			}
+		}
+		if len(belowMax) == 0 {
+			return nil, errors.Errorf("all the control plane failure domains have the maximum of %d up-to-date machines", *spread.MaxMachinesPerFailureDomain)
+		}
+		failureDomains = belowMax
+	}
+
+	if len(failureDomains) == 0 {
+		return nil, nil
+	}
+	return failuredomains.PickFewest(failureDomains, upToDateMachines), nil
+}
+
+// FailureDomainSpreadError returns an error describing why the desired replicas cannot be spread across the
+// control plane failure domains as required by the failure domain spread, or nil if they can or there is no spread.
+func (c *ControlPlane) FailureDomainSpreadError() error {
+	spread := c.KCP.Spec.FailureDomainSpread
+	if spread == nil {
 		return nil
 	}
-	return failuredomains.PickFewest(c.FailureDomains().FilterControlPlane(), c.UpToDateMachines())
+
+	failureDomains := int32(len(c.FailureDomains().FilterControlPlane()))
+	if spread.MinFailureDomains != nil && failureDomains < *spread.MinFailureDomains {
+		return errors.Errorf("the cluster has %d control plane failure domains, at least %d are required", failureDomains, *spread.MinFailureDomains)
+	}
+	if spread.MaxMachinesPerFailureDomain != nil && c.KCP.Spec.Replicas != nil &&
+		failureDomains*(*spread.MaxMachinesPerFailureDomain) < *c.KCP.Spec.Replicas {
+		return errors.Errorf("%d replicas cannot be placed in %d control plane failure domains with at most %d machines each",
+			*c.KCP.Spec.Replicas, failureDomains, *spread.MaxMachinesPerFailureDomain)
+	}
+	return nil
 }
 
 // InitialControlPlaneConfig returns a new KubeadmConfigSpec that is to be used for an initializing control plane.
 func (c *ControlPlane) InitialControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
 	bootstrapSpec := c.KCP.Spec.KubeadmConfigSpec.DeepCopy()
 	bootstrapSpec.JoinConfiguration = nil
+	c.setAPIServerCertSANs(bootstrapSpec)
 	return bootstrapSpec
 }
 
@@ -187,9 +238,42 @@ func (c *ControlPlane) JoinControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
 	// NOTE: For the joining we are preserving the ClusterConfiguration in order to determine if the
 	// cluster is using an external etcd in the kubeadm bootstrap provider (even if this is not required by kubeadm Join).
 	// TODO: Determine if this copy of cluster configuration can be used for rollouts (thus allowing to remove the annotation at machine level)
+	c.setAPIServerCertSANs(bootstrapSpec)
 	return bootstrapSpec
 }
 
+// APIServerCertSANs returns the SANs the serving certificate of the kube-apiserver must include, i.e. the certSANs
+// of the KCP ClusterConfiguration and the host of the Cluster control plane endpoint.
+func (c *ControlPlane) APIServerCertSANs() []string {
+	var sans []string
+	if c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration != nil {
+		sans = append(sans, c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.CertSANs...)
+	}
+	if c.Cluster == nil || c.Cluster.Spec.ControlPlaneEndpoint.Host == "" {
+		return sans
+	}
+	host := c.Cluster.Spec.ControlPlaneEndpoint.Host
+	for _, san := range sans {
+		if san == host {
+			return sans
+		}
+	}
+	return append(sans, host)
+}
+
+// setAPIServerCertSANs sets the certSANs of the ClusterConfiguration of the bootstrap spec to APIServerCertSANs,
+// so kubeadm includes all of them in the serving certificate of the kube-apiserver.
+func (c *ControlPlane) setAPIServerCertSANs(bootstrapSpec *bootstrapv1.KubeadmConfigSpec) {
+	sans := c.APIServerCertSANs()
+	if len(sans) == 0 {
+		return
+	}
+	if bootstrapSpec.ClusterConfiguration == nil {
+		bootstrapSpec.ClusterConfiguration = &kubeadmv1.ClusterConfiguration{}
+	}
+	bootstrapSpec.ClusterConfiguration.APIServer.CertSANs = sans
+}
+
 // GenerateKubeadmConfig generates a new kubeadm config for creating new control plane nodes.
 func (c *ControlPlane) GenerateKubeadmConfig(spec *bootstrapv1.KubeadmConfigSpec) *bootstrapv1.KubeadmConfig {
 	// Create an owner reference without a controller reference because the owning controller is the machine controller
@@ -259,8 +343,12 @@ func (c *ControlPlane) MachinesNeedingRollout() collections.Machines {
 	return machines.AnyFilter(
 		// Machines that are scheduled for rollout (KCP.Spec.UpgradeAfter set, the UpgradeAfter deadline is expired, and the machine was created before the deadline).
 		collections.ShouldRolloutAfter(&c.reconciliationTime, c.KCP.Spec.UpgradeAfter),
+		// Machines whose certificates are about to expire.
+		collections.ShouldRolloutBefore(&c.reconciliationTime, c.KCP.Spec.RolloutBefore),
 		// Machines that do not match with KCP config.
 		collections.Not(MatchesKCPConfiguration(c.infraResources, c.kubeadmConfigs, c.KCP)),
+		// Machines whose kube-apiserver certificate is missing some of the required SANs.
+		collections.HasAnnotationKey(controlplanev1.APIServerCertificateMissingSANsAnnotation),
 	)
 }
 
@@ -311,21 +399,82 @@ func (c *ControlPlane) IsEtcdManaged() bool {
 	return c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration == nil || c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.External == nil
 }
 
-// UnhealthyMachines returns the list of control plane machines marked as unhealthy by MHC.
+// UnhealthyMachines returns the list of control plane machines marked as unhealthy by MHC, or failing the
+// KCP health checks for longer than the remediation unhealthy timeout.
 func (c *ControlPlane) UnhealthyMachines() collections.Machines {
-	return c.Machines.Filter(collections.HasUnhealthyCondition)
+	return c.Machines.Filter(collections.Or(collections.HasUnhealthyCondition, c.isFailingHealthChecks))
 }
 
-// HealthyMachines returns the list of control plane machines not marked as unhealthy by MHC.
+// HealthyMachines returns the list of control plane machines not marked as unhealthy by MHC, nor failing the
+// KCP health checks for longer than the remediation unhealthy timeout.
 func (c *ControlPlane) HealthyMachines() collections.Machines {
-	return c.Machines.Filter(collections.Not(collections.HasUnhealthyCondition))
+	return c.Machines.Filter(collections.Not(collections.Or(collections.HasUnhealthyCondition, c.isFailingHealthChecks)))
 }
 
-// HasUnhealthyMachine returns true if any machine in the control plane is marked as unhealthy by MHC.
+// HasUnhealthyMachine returns true if any machine in the control plane is marked as unhealthy by MHC, or failing the
+// KCP health checks for longer than the remediation unhealthy timeout.
 func (c *ControlPlane) HasUnhealthyMachine() bool {
 	return len(c.UnhealthyMachines()) > 0
 }
 
+// machineHealthCheckConditions are the conditions reporting the KCP health checks of the etcd member and
+// the control plane components of a machine.
+var machineHealthCheckConditions = []clusterv1.ConditionType{
+	controlplanev1.MachineAPIServerPodHealthyCondition,
+	controlplanev1.MachineControllerManagerPodHealthyCondition,
+	controlplanev1.MachineSchedulerPodHealthyCondition,
+	controlplanev1.MachineEtcdPodHealthyCondition,
+	controlplanev1.MachineEtcdMemberHealthyCondition,
+}
+
+// NextUnhealthyTimeout returns how long until the first of the machines failing the KCP health checks, but not yet
+// for longer than the remediation unhealthy timeout, is considered unhealthy, and false if there are no such machines.
+func (c *ControlPlane) NextUnhealthyTimeout() (time.Duration, bool) {
+	var next time.Duration
+	found := false
+	for _, machine := range c.Machines {
+		remaining, failing := c.unhealthyTimeoutRemaining(machine)
+		if !failing || remaining <= 0 {
+			continue
+		}
+		if !found || remaining < next {
+			next = remaining
+			found = true
+		}
+	}
+	return next, found
+}
+
+// isFailingHealthChecks returns true if any of the KCP health check conditions of the machine is false with severity
+// error since longer than the remediation unhealthy timeout. It is always false when remediation is not configured.
+func (c *ControlPlane) isFailingHealthChecks(machine *clusterv1.Machine) bool {
+	remaining, failing := c.unhealthyTimeoutRemaining(machine)
+	return failing && remaining <= 0
+}
+
+// unhealthyTimeoutRemaining returns how long until the machine failing the KCP health checks reaches the remediation
+// unhealthy timeout, i.e. the time remaining for the KCP health check condition which has been false with severity
+// error the longest. It returns false if the machine is not failing the health checks, or remediation is not configured.
+func (c *ControlPlane) unhealthyTimeoutRemaining(machine *clusterv1.Machine) (time.Duration, bool) {
+	if machine == nil || c.KCP == nil || c.KCP.Spec.Remediation == nil {
+		return 0, false
+	}
+	var remaining time.Duration
+	failing := false
+	for _, conditionType := range machineHealthCheckConditions {
+		condition := conditions.Get(machine, conditionType)
+		if condition == nil || condition.Status != corev1.ConditionFalse || condition.Severity != clusterv1.ConditionSeverityError {
+			continue
+		}
+		conditionRemaining := c.KCP.Spec.Remediation.UnhealthyTimeout.Duration - time.Since(condition.LastTransitionTime.Time)
+		if !failing || conditionRemaining < remaining {
+			remaining = conditionRemaining
+			failing = true
+		}
+	}
+	return remaining, failing
+}
+
 func (c *ControlPlane) PatchMachines(ctx context.Context) error {
 	errList := []error{}
 	for i := range c.Machines {
@@ -19,6 +19,7 @@ package internal
 import (
 	"sigs.k8s.io/cluster-api/util/collections"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -28,6 +29,7 @@ import (
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -59,6 +61,28 @@ func TestControlPlane(t *testing.T) {
 			g.Expect(*controlPlane.FailureDomainWithMostMachines(controlPlane.Machines)).To(Equal("two"))
 		})
 
+		t.Run("With deprioritized machines, should return a machine in the FD with most number of machines not matching them", func(t *testing.T) {
+			hasName := func(names ...string) collections.Func {
+				return func(m *clusterv1.Machine) bool {
+					for _, name := range names {
+						if m.Name == name {
+							return true
+						}
+					}
+					return false
+				}
+			}
+
+			m, err := controlPlane.MachineInFailureDomainWithMostMachines(controlPlane.Machines, hasName("machine-2"))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(m.Name).To(Equal("machine-3"))
+
+			// A deprioritized machine is returned if there are no other machines in the FD.
+			m, err = controlPlane.MachineInFailureDomainWithMostMachines(controlPlane.Machines, hasName("machine-2", "machine-3"))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*m.Spec.FailureDomain).To(Equal("two"))
+		})
+
 		t.Run(("With some machines in non defined failure domains"), func(t *testing.T) {
 			controlPlane.Machines.Insert(machine("machine-5", withFailureDomain("unknown")))
 			g.Expect(*controlPlane.FailureDomainWithMostMachines(controlPlane.Machines)).To(Equal("unknown"))
@@ -94,6 +118,167 @@ func TestControlPlane(t *testing.T) {
 	})
 }
 
+func TestNextFailureDomainForScaleUp(t *testing.T) {
+	failureDomains := clusterv1.FailureDomains{
+		"one":   failureDomain(true),
+		"two":   failureDomain(true),
+		"three": failureDomain(false),
+	}
+
+	tests := []struct {
+		name           string
+		spread         *controlplanev1.FailureDomainSpread
+		failureDomains clusterv1.FailureDomains
+		machines       collections.Machines
+		expectErr      bool
+		expected       *string
+	}{
+		{
+			name:           "without failure domains",
+			failureDomains: nil,
+			expected:       nil,
+		},
+		{
+			name:           "without spread, the failure domain with the fewest machines",
+			failureDomains: failureDomains,
+			machines: collections.FromMachines(
+				machine("machine-1", withVersion("v1.19.1"), withFailureDomain("one")),
+				machine("machine-2", withVersion("v1.19.1"), withFailureDomain("one")),
+				machine("machine-3", withVersion("v1.19.1"), withFailureDomain("two")),
+			),
+			expected: pointer.StringPtr("two"),
+		},
+		{
+			name:           "with spread, the failure domain with the fewest machines below the maximum",
+			spread:         &controlplanev1.FailureDomainSpread{MaxMachinesPerFailureDomain: pointer.Int32Ptr(2)},
+			failureDomains: failureDomains,
+			machines: collections.FromMachines(
+				machine("machine-1", withVersion("v1.19.1"), withFailureDomain("one")),
+				machine("machine-2", withVersion("v1.19.1"), withFailureDomain("two")),
+				machine("machine-3", withVersion("v1.19.1"), withFailureDomain("two")),
+			),
+			expected: pointer.StringPtr("one"),
+		},
+		{
+			name:           "with spread, machines needing rollout are not counted",
+			spread:         &controlplanev1.FailureDomainSpread{MaxMachinesPerFailureDomain: pointer.Int32Ptr(1)},
+			failureDomains: failureDomains,
+			machines: collections.FromMachines(
+				machine("machine-1", withVersion("v1.19.1"), withFailureDomain("one")),
+				machine("machine-2", withVersion("v1.19.0"), withFailureDomain("two")),
+			),
+			expected: pointer.StringPtr("two"),
+		},
+		{
+			name:           "with spread, all the failure domains at the maximum",
+			spread:         &controlplanev1.FailureDomainSpread{MaxMachinesPerFailureDomain: pointer.Int32Ptr(1)},
+			failureDomains: failureDomains,
+			machines: collections.FromMachines(
+				machine("machine-1", withVersion("v1.19.1"), withFailureDomain("one")),
+				machine("machine-2", withVersion("v1.19.1"), withFailureDomain("two")),
+			),
+			expectErr: true,
+		},
+		{
+			name:           "with spread, without failure domains",
+			spread:         &controlplanev1.FailureDomainSpread{MaxMachinesPerFailureDomain: pointer.Int32Ptr(1)},
+			failureDomains: nil,
+			expectErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &ControlPlane{
+				KCP: &controlplanev1.KubeadmControlPlane{
+					Spec: controlplanev1.KubeadmControlPlaneSpec{
+						Version:             "v1.19.1",
+						FailureDomainSpread: tt.spread,
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					Status: clusterv1.ClusterStatus{FailureDomains: tt.failureDomains},
+				},
+				Machines: tt.machines,
+			}
+
+			fd, err := c.NextFailureDomainForScaleUp()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(fd).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestFailureDomainSpreadError(t *testing.T) {
+	failureDomains := clusterv1.FailureDomains{
+		"one":   failureDomain(true),
+		"two":   failureDomain(true),
+		"three": failureDomain(false),
+	}
+
+	tests := []struct {
+		name      string
+		replicas  int32
+		spread    *controlplanev1.FailureDomainSpread
+		expectErr bool
+	}{
+		{
+			name:     "without spread",
+			replicas: 5,
+		},
+		{
+			name:     "satisfiable maximum number of machines per failure domain",
+			replicas: 3,
+			spread:   &controlplanev1.FailureDomainSpread{MaxMachinesPerFailureDomain: pointer.Int32Ptr(2)},
+		},
+		{
+			name:      "unsatisfiable maximum number of machines per failure domain",
+			replicas:  5,
+			spread:    &controlplanev1.FailureDomainSpread{MaxMachinesPerFailureDomain: pointer.Int32Ptr(2)},
+			expectErr: true,
+		},
+		{
+			name:     "satisfiable minimum number of failure domains",
+			replicas: 3,
+			spread:   &controlplanev1.FailureDomainSpread{MinFailureDomains: pointer.Int32Ptr(2)},
+		},
+		{
+			name:      "unsatisfiable minimum number of failure domains, not counting the worker failure domains",
+			replicas:  3,
+			spread:    &controlplanev1.FailureDomainSpread{MinFailureDomains: pointer.Int32Ptr(3)},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &ControlPlane{
+				KCP: &controlplanev1.KubeadmControlPlane{
+					Spec: controlplanev1.KubeadmControlPlaneSpec{
+						Replicas:            pointer.Int32Ptr(tt.replicas),
+						FailureDomainSpread: tt.spread,
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					Status: clusterv1.ClusterStatus{FailureDomains: failureDomains},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(c.FailureDomainSpreadError()).NotTo(Succeed())
+			} else {
+				g.Expect(c.FailureDomainSpreadError()).To(Succeed())
+			}
+		})
+	}
+}
+
 func TestHasUnhealthyMachine(t *testing.T) {
 	// healthy machine (without MachineHealthCheckSucceded condition)
 	healthyMachine1 := &clusterv1.Machine{}
@@ -121,6 +306,184 @@ func TestHasUnhealthyMachine(t *testing.T) {
 	g.Expect(c.HasUnhealthyMachine()).To(BeTrue())
 }
 
+func TestUnhealthyMachinesFailingHealthChecks(t *testing.T) {
+	g := NewWithT(t)
+
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	// machine with the etcd member failing the health checks since longer than the timeout
+	failingEtcdMember := machine("failing-etcd-member")
+	conditions.Set(failingEtcdMember, &clusterv1.Condition{
+		Type:               controlplanev1.MachineEtcdMemberHealthyCondition,
+		Status:             corev1.ConditionFalse,
+		Severity:           clusterv1.ConditionSeverityError,
+		Reason:             controlplanev1.EtcdMemberUnhealthyReason,
+		LastTransitionTime: longAgo,
+	})
+	// machine with the kube-apiserver pod failing the health checks since longer than the timeout
+	failingAPIServer := machine("failing-api-server")
+	conditions.Set(failingAPIServer, &clusterv1.Condition{
+		Type:               controlplanev1.MachineAPIServerPodHealthyCondition,
+		Status:             corev1.ConditionFalse,
+		Severity:           clusterv1.ConditionSeverityError,
+		Reason:             controlplanev1.PodFailedReason,
+		LastTransitionTime: longAgo,
+	})
+	// machine with the etcd member failing the health checks since less than the timeout
+	recentlyFailing := machine("recently-failing")
+	conditions.MarkFalse(recentlyFailing, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "")
+	// machine with the kube-apiserver pod still being provisioned
+	provisioning := machine("provisioning")
+	conditions.Set(provisioning, &clusterv1.Condition{
+		Type:               controlplanev1.MachineAPIServerPodHealthyCondition,
+		Status:             corev1.ConditionFalse,
+		Severity:           clusterv1.ConditionSeverityInfo,
+		Reason:             controlplanev1.PodProvisioningReason,
+		LastTransitionTime: longAgo,
+	})
+	healthy := machine("healthy")
+	conditions.MarkTrue(healthy, controlplanev1.MachineEtcdMemberHealthyCondition)
+
+	c := ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{},
+		Machines: collections.FromMachines(
+			failingEtcdMember,
+			failingAPIServer,
+			recentlyFailing,
+			provisioning,
+			healthy,
+		),
+	}
+
+	// Without remediation, machines failing the health checks are not considered unhealthy.
+	g.Expect(c.HasUnhealthyMachine()).To(BeFalse())
+	g.Expect(c.HealthyMachines().Len()).To(Equal(5))
+	_, ok := c.NextUnhealthyTimeout()
+	g.Expect(ok).To(BeFalse())
+
+	c.KCP.Spec.Remediation = &controlplanev1.Remediation{UnhealthyTimeout: metav1.Duration{Duration: 10 * time.Minute}}
+	g.Expect(c.UnhealthyMachines().Names()).To(ConsistOf("failing-etcd-member", "failing-api-server"))
+	g.Expect(c.HealthyMachines().Names()).To(ConsistOf("recently-failing", "provisioning", "healthy"))
+
+	// The machine failing the health checks since less than the timeout is considered unhealthy when the timeout is reached.
+	next, ok := c.NextUnhealthyTimeout()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(next).To(BeNumerically("~", 10*time.Minute, time.Minute))
+
+	c.Machines = collections.FromMachines(failingEtcdMember, provisioning, healthy)
+	_, ok = c.NextUnhealthyTimeout()
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestMachinesNeedingRollout(t *testing.T) {
+	g := NewWithT(t)
+
+	reconciliationTime := metav1.Now()
+	expiringSoon := metav1.NewTime(reconciliationTime.Add(5 * 24 * time.Hour))
+	expiringLater := metav1.NewTime(reconciliationTime.Add(60 * 24 * time.Hour))
+
+	c := ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Version: "v1.19.1",
+				RolloutBefore: &controlplanev1.RolloutBefore{
+					CertificatesExpiryDays: pointer.Int32Ptr(7),
+				},
+			},
+		},
+		Machines: collections.FromMachines(
+			machine("machine-expiring-soon", withVersion("v1.19.1"), withCertificatesExpiryDate(expiringSoon)),
+			machine("machine-expiring-later", withVersion("v1.19.1"), withCertificatesExpiryDate(expiringLater)),
+			machine("machine-expiry-unknown", withVersion("v1.19.1")),
+		),
+		reconciliationTime: reconciliationTime,
+	}
+
+	g.Expect(c.MachinesNeedingRollout().Names()).To(ConsistOf("machine-expiring-soon"))
+
+	// Without RolloutBefore, certificates expiry dates are not considered.
+	c.KCP.Spec.RolloutBefore = nil
+	g.Expect(c.MachinesNeedingRollout()).To(BeEmpty())
+
+	// Machines whose kube-apiserver certificate is missing some SANs are rolled out.
+	c.Machines.Insert(machine("machine-missing-sans", withVersion("v1.19.1"), withAnnotation(controlplanev1.APIServerCertificateMissingSANsAnnotation, "cp.example.com")))
+	g.Expect(c.MachinesNeedingRollout().Names()).To(ConsistOf("machine-missing-sans"))
+}
+
+func TestAPIServerCertSANs(t *testing.T) {
+	tests := []struct {
+		name         string
+		host         string
+		certSANs     []string
+		expectedSANs []string
+	}{
+		{
+			name: "no SANs",
+		},
+		{
+			name:         "only the control plane endpoint host",
+			host:         "cp.example.com",
+			expectedSANs: []string{"cp.example.com"},
+		},
+		{
+			name:         "only the certSANs",
+			certSANs:     []string{"lb.example.com", "10.0.0.1"},
+			expectedSANs: []string{"lb.example.com", "10.0.0.1"},
+		},
+		{
+			name:         "the certSANs and the control plane endpoint host",
+			host:         "cp.example.com",
+			certSANs:     []string{"lb.example.com"},
+			expectedSANs: []string{"lb.example.com", "cp.example.com"},
+		},
+		{
+			name:         "the control plane endpoint host is not duplicated",
+			host:         "cp.example.com",
+			certSANs:     []string{"cp.example.com", "lb.example.com"},
+			expectedSANs: []string{"cp.example.com", "lb.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &ControlPlane{
+				KCP: &controlplanev1.KubeadmControlPlane{},
+				Cluster: &clusterv1.Cluster{
+					Spec: clusterv1.ClusterSpec{
+						ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: tt.host, Port: 6443},
+					},
+				},
+			}
+			if tt.certSANs != nil {
+				c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration = &kubeadmv1.ClusterConfiguration{
+					APIServer: kubeadmv1.APIServer{CertSANs: tt.certSANs},
+				}
+			}
+
+			g.Expect(c.APIServerCertSANs()).To(Equal(tt.expectedSANs))
+
+			initSpec := c.InitialControlPlaneConfig()
+			joinSpec := c.JoinControlPlaneConfig()
+			if tt.expectedSANs == nil {
+				g.Expect(initSpec.ClusterConfiguration).To(BeNil())
+				g.Expect(joinSpec.ClusterConfiguration).To(BeNil())
+				return
+			}
+			g.Expect(initSpec.ClusterConfiguration.APIServer.CertSANs).To(Equal(tt.expectedSANs))
+			g.Expect(joinSpec.ClusterConfiguration.APIServer.CertSANs).To(Equal(tt.expectedSANs))
+
+			// The KCP spec is not modified.
+			if tt.certSANs != nil {
+				g.Expect(c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.CertSANs).To(Equal(tt.certSANs))
+			} else {
+				g.Expect(c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration).To(BeNil())
+			}
+		})
+	}
+}
+
 type machineOpt func(*clusterv1.Machine)
 
 func failureDomain(controlPlane bool) clusterv1.FailureDomainSpec {
@@ -135,6 +498,27 @@ func withFailureDomain(fd string) machineOpt {
 	}
 }
 
+func withVersion(version string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.Spec.Version = &version
+	}
+}
+
+func withCertificatesExpiryDate(expiry metav1.Time) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.Status.CertificatesExpiryDate = &expiry
+	}
+}
+
+func withAnnotation(key, value string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		if m.Annotations == nil {
+			m.Annotations = map[string]string{}
+		}
+		m.Annotations[key] = value
+	}
+}
+
 func machine(name string, opts ...machineOpt) *clusterv1.Machine {
 	m := &clusterv1.Machine{
 		ObjectMeta: metav1.ObjectMeta{
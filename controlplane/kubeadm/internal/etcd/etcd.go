@@ -40,6 +40,7 @@ type GRPCDial func(ctx context.Context, addr string) (net.Conn, error)
 type etcd interface {
 	AlarmList(ctx context.Context) (*clientv3.AlarmResponse, error)
 	Close() error
+	Defragment(ctx context.Context, endpoint string) (*clientv3.DefragmentResponse, error)
 	Endpoints() []string
 	MemberList(ctx context.Context) (*clientv3.MemberListResponse, error)
 	MemberRemove(ctx context.Context, id uint64) (*clientv3.MemberRemoveResponse, error)
@@ -85,6 +86,21 @@ var AlarmTypeName = map[AlarmType]string{
 	AlarmCorrupt: "CORRUPT",
 }
 
+// MemberStatus reports the status of the etcd member a client is connected to.
+type MemberStatus struct {
+	// ID is the ID of the cluster member.
+	ID uint64
+
+	// IsLeader indicates if the member is the leader of the cluster.
+	IsLeader bool
+
+	// DBSize is the size of the backend database of the member, in bytes.
+	DBSize int64
+
+	// DBSizeInUse is the size of the backend database of the member which is logically in use, in bytes.
+	DBSizeInUse int64
+}
+
 // Adapted from kubeadm
 
 // Member struct defines an etcd member; it is used to avoid spreading
@@ -243,3 +259,26 @@ func (c *Client) Alarms(ctx context.Context) ([]MemberAlarm, error) {
 
 	return memberAlarms, nil
 }
+
+// MemberStatus retrieves the status of the etcd member the client is connected to.
+func (c *Client) MemberStatus(ctx context.Context) (*MemberStatus, error) {
+	response, err := c.EtcdClient.Status(ctx, c.Endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get status for etcd member %s", c.Endpoint)
+	}
+
+	return &MemberStatus{
+		ID:          response.Header.GetMemberId(),
+		IsLeader:    response.Header.GetMemberId() == response.Leader,
+		DBSize:      response.DbSize,
+		DBSizeInUse: response.DbSizeInUse,
+	}, nil
+}
+
+// Defragment defragments the backend database of the etcd member the client is connected to.
+// NOTE: Defragmentation blocks reads and writes on the member while it is in progress, so it
+// must not be run concurrently on multiple members of the same cluster.
+func (c *Client) Defragment(ctx context.Context) error {
+	_, err := c.EtcdClient.Defragment(ctx, c.Endpoint)
+	return errors.Wrapf(err, "failed to defragment etcd member %s", c.Endpoint)
+}
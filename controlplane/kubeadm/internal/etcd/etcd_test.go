@@ -62,6 +62,8 @@ func TestEtcdMembers_WithErrors(t *testing.T) {
 	err = client.RemoveMember(ctx, 1234)
 	g.Expect(err).To(HaveOccurred())
 
+	err = client.Defragment(ctx)
+	g.Expect(err).To(HaveOccurred())
 }
 
 func TestEtcdMembers_WithSuccess(t *testing.T) {
@@ -105,3 +107,28 @@ func TestEtcdMembers_WithSuccess(t *testing.T) {
 	g.Expect(len(updatedMembers[0].PeerURLs)).To(Equal(2))
 	g.Expect(updatedMembers[0].PeerURLs).To(Equal([]string{"https://1.2.3.4:2000", "https://4.5.6.7:2000"}))
 }
+
+func TestEtcdMemberStatusAndDefragment(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeEtcdClient := &etcdfake.FakeEtcdClient{
+		EtcdEndpoints: []string{"https://etcd-instance:2379"},
+		StatusResponse: &clientv3.StatusResponse{
+			Header:      &etcdserverpb.ResponseHeader{MemberId: 1234},
+			Leader:      1234,
+			DbSize:      2048,
+			DbSizeInUse: 1024,
+		},
+		DefragmentResponse: &clientv3.DefragmentResponse{},
+	}
+
+	client, err := newEtcdClient(ctx, fakeEtcdClient)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	status, err := client.MemberStatus(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(status).To(Equal(&MemberStatus{ID: 1234, IsLeader: true, DBSize: 2048, DBSizeInUse: 1024}))
+
+	g.Expect(client.Defragment(ctx)).To(Succeed())
+	g.Expect(fakeEtcdClient.DefragmentedMembers).To(Equal([]string{"https://etcd-instance:2379"}))
+}
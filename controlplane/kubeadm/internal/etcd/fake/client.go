@@ -24,6 +24,7 @@ import (
 
 type FakeEtcdClient struct {
 	AlarmResponse        *clientv3.AlarmResponse
+	DefragmentResponse   *clientv3.DefragmentResponse
 	EtcdEndpoints        []string
 	MemberListResponse   *clientv3.MemberListResponse
 	MemberRemoveResponse *clientv3.MemberRemoveResponse
@@ -33,6 +34,7 @@ type FakeEtcdClient struct {
 	ErrorResponse        error
 	MovedLeader          uint64
 	RemovedMember        uint64
+	DefragmentedMembers  []string
 }
 
 func (c *FakeEtcdClient) Endpoints() []string {
@@ -48,6 +50,11 @@ func (c *FakeEtcdClient) Close() error {
 	return nil
 }
 
+func (c *FakeEtcdClient) Defragment(_ context.Context, endpoint string) (*clientv3.DefragmentResponse, error) {
+	c.DefragmentedMembers = append(c.DefragmentedMembers, endpoint)
+	return c.DefragmentResponse, c.ErrorResponse
+}
+
 func (c *FakeEtcdClient) AlarmList(_ context.Context) (*clientv3.AlarmResponse, error) {
 	return c.AlarmResponse, c.ErrorResponse
 }
@@ -35,9 +35,12 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/proxy"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/certs"
 	containerutil "sigs.k8s.io/cluster-api/util/container"
@@ -52,6 +55,10 @@ const (
 	kubeletConfigKey          = "kubelet"
 	cgroupDriverKey           = "cgroupDriver"
 	labelNodeRoleControlPlane = "node-role.kubernetes.io/master"
+	apiServerPort             = 6443
+
+	// apiServerTLSHandshakeTimeout is the time allowed for completing the TLS handshake with a kube-apiserver.
+	apiServerTLSHandshakeTimeout = 10 * time.Second
 )
 
 var (
@@ -68,6 +75,8 @@ type WorkloadCluster interface {
 	UpdateStaticPodConditions(ctx context.Context, controlPlane *ControlPlane)
 	UpdateEtcdConditions(ctx context.Context, controlPlane *ControlPlane)
 	EtcdMembers(ctx context.Context) ([]string, error)
+	EtcdLeader(ctx context.Context) (string, error)
+	EtcdMembersStatus(ctx context.Context, nodeNames []string) (map[string]*etcd.MemberStatus, error)
 
 	// Upgrade related tasks.
 	ReconcileKubeletRBACBinding(ctx context.Context, version semver.Version) error
@@ -86,9 +95,13 @@ type WorkloadCluster interface {
 	RemoveNodeFromKubeadmConfigMap(ctx context.Context, nodeName string) error
 	ForwardEtcdLeadership(ctx context.Context, machine *clusterv1.Machine, leaderCandidate *clusterv1.Machine) error
 	AllowBootstrapTokensToGetNodes(ctx context.Context) error
+	GetAPIServerCertificate(ctx context.Context, nodeName string) (*x509.Certificate, error)
 
 	// State recovery tasks.
 	ReconcileEtcdMembers(ctx context.Context, nodeNames []string) ([]string, error)
+
+	// Maintenance tasks.
+	DefragmentEtcdMember(ctx context.Context, nodeName string) error
 }
 
 // Workload defines operations on workload clusters.
@@ -96,6 +109,7 @@ type Workload struct {
 	Client              ctrlclient.Client
 	CoreDNSMigrator     coreDNSMigrator
 	etcdClientGenerator etcdClientFor
+	restConfig          *rest.Config
 }
 
 var _ WorkloadCluster = &Workload{}
@@ -414,6 +428,63 @@ func newClientCert(caCert *x509.Certificate, key *rsa.PrivateKey, caKey crypto.S
 	return c, errors.WithStack(err)
 }
 
+// GetAPIServerCertificate returns the serving certificate of the kube-apiserver running on the given node.
+func (w *Workload) GetAPIServerCertificate(ctx context.Context, nodeName string) (*x509.Certificate, error) {
+	if w.restConfig == nil {
+		return nil, errors.New("failed to get the kube-apiserver certificate: missing rest config")
+	}
+
+	dialer, err := proxy.NewDialer(proxy.Proxy{
+		Kind:       "pods",
+		Namespace:  metav1.NamespaceSystem,
+		KubeConfig: rest.CopyConfig(w.restConfig),
+		Port:       apiServerPort,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a dialer to the kube-apiserver")
+	}
+
+	podName := staticPodName("kube-apiserver", nodeName)
+	conn, err := dialer.DialContextWithAddr(ctx, podName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to pod %s", podName)
+	}
+
+	// The certificate is only inspected, so there is no need to verify it against the cluster CA.
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	defer tlsConn.Close()
+	if err := handshakeWithTimeout(ctx, tlsConn, apiServerTLSHandshakeTimeout); err != nil {
+		return nil, errors.Wrapf(err, "failed to complete the TLS handshake with pod %s", podName)
+	}
+
+	peerCertificates := tlsConn.ConnectionState().PeerCertificates
+	if len(peerCertificates) == 0 {
+		return nil, errors.Errorf("pod %s did not present any certificate", podName)
+	}
+	return peerCertificates[0], nil
+}
+
+// handshakeWithTimeout runs the TLS handshake, closing the connection if it does not complete within the timeout.
+// NOTE: The deadlines of the port-forwarded connections are not enforced, so they can't be used for limiting the handshake.
+func handshakeWithTimeout(ctx context.Context, conn *tls.Conn, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	handshakeErr := make(chan error, 1)
+	go func() {
+		handshakeErr <- conn.Handshake()
+	}()
+
+	select {
+	case err := <-handshakeErr:
+		return err
+	case <-ctx.Done():
+		// Closing the connection unblocks the handshake.
+		_ = conn.Close()
+		return errors.Wrap(ctx.Err(), "timed out waiting for the TLS handshake")
+	}
+}
+
 func staticPodName(component, nodeName string) string {
 	return fmt.Sprintf("%s-%s", component, nodeName)
 }
@@ -19,9 +19,14 @@ package internal
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +39,9 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// externalEtcdDialTimeout is the time allowed for opening a connection to an external etcd endpoint.
+const externalEtcdDialTimeout = 5 * time.Second
+
 // UpdateEtcdConditions is responsible for updating machine conditions reflecting the status of all the etcd members.
 // This operation is best effort, in the sense that in case of problems in retrieving member status, it sets
 // the condition to Unknown state without returning any error.
@@ -45,13 +53,66 @@ func (w *Workload) UpdateEtcdConditions(ctx context.Context, controlPlane *Contr
 	w.updateExternalEtcdConditions(ctx, controlPlane)
 }
 
-func (w *Workload) updateExternalEtcdConditions(ctx context.Context, controlPlane *ControlPlane) { //nolint:unparam
+func (w *Workload) updateExternalEtcdConditions(ctx context.Context, controlPlane *ControlPlane) {
 	// When KCP is not responsible for external etcd, we are reporting only health at KCP level.
 	conditions.MarkTrue(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition)
 
-	// TODO: check external etcd for alarms an possibly also for member errors
-	// this requires implementing an new type of etcd client generator given that it is not possible to use nodes
-	// as a source for the etcd endpoint address; the address of the external etcd should be available on the kubeadm configuration.
+	// The certificates used by the API server for connecting to the external etcd are files on the nodes, so KCP
+	// is only checking the configured endpoints are reachable.
+	// TODO: check external etcd for alarms an possibly also for member errors.
+	// The endpoints are dialed in parallel, so a single unreachable endpoint does not delay the checks of the others.
+	endpoints := controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.External.Endpoints
+	dialErrors := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i := range endpoints {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dialErrors[i] = w.dialEtcdEndpoint(ctx, endpoints[i])
+		}(i)
+	}
+	wg.Wait()
+
+	unreachableEndpoints := []string{}
+	for i, endpoint := range endpoints {
+		if dialErrors[i] != nil {
+			unreachableEndpoints = append(unreachableEndpoints, endpoint)
+		}
+	}
+
+	switch {
+	case len(unreachableEndpoints) == 0:
+		conditions.MarkTrue(controlPlane.KCP, controlplanev1.ExternalEtcdReachableCondition)
+	case len(unreachableEndpoints) == len(endpoints):
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.ExternalEtcdReachableCondition, controlplanev1.ExternalEtcdUnreachableReason, clusterv1.ConditionSeverityError,
+			"None of the external etcd endpoints is reachable: %s", strings.Join(unreachableEndpoints, ", "))
+	default:
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.ExternalEtcdReachableCondition, controlplanev1.ExternalEtcdUnreachableReason, clusterv1.ConditionSeverityWarning,
+			"Some of the external etcd endpoints are not reachable: %s", strings.Join(unreachableEndpoints, ", "))
+	}
+}
+
+// dialEtcdEndpoint checks if a TCP connection can be opened to an etcd endpoint, e.g. https://10.0.0.1:2379.
+// The connection is opened with the dialer of the workload cluster rest config, if any, so the endpoint is reached
+// through the same path, e.g. a proxy or a tunnel, used for connecting to the workload cluster.
+func (w *Workload) dialEtcdEndpoint(ctx context.Context, endpoint string) error {
+	address := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		address = u.Host
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, externalEtcdDialTimeout)
+	defer cancel()
+
+	dial := (&net.Dialer{}).DialContext
+	if w.restConfig != nil && w.restConfig.Dial != nil {
+		dial = w.restConfig.Dial
+	}
+	conn, err := dial(ctx, "tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to etcd endpoint %s", endpoint)
+	}
+	return conn.Close()
 }
 
 func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane *ControlPlane) {
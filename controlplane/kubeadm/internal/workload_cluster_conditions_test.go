@@ -17,6 +17,9 @@ limitations under the License.
 package internal
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"testing"
 
@@ -28,6 +31,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
@@ -490,6 +494,95 @@ func TestUpdateEtcdConditions(t *testing.T) {
 	}
 }
 
+func TestUpdateExternalEtcdConditions(t *testing.T) {
+	g := NewWithT(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer listener.Close()
+	reachableEndpoint := fmt.Sprintf("https://%s", listener.Addr().String())
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).ToNot(HaveOccurred())
+	unreachableEndpoint := fmt.Sprintf("https://%s", closedListener.Addr().String())
+	g.Expect(closedListener.Close()).To(Succeed())
+
+	// A dialer for the workload cluster which only knows how to reach the endpoint listening on an unresolvable name.
+	tunneledEndpoint := "https://etcd.tunnel:2379"
+	tunnelConfig := &rest.Config{
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if address != "etcd.tunnel:2379" {
+				return nil, errors.Errorf("unknown address %s", address)
+			}
+			return (&net.Dialer{}).DialContext(ctx, network, listener.Addr().String())
+		},
+	}
+
+	tests := []struct {
+		name              string
+		restConfig        *rest.Config
+		endpoints         []string
+		expectedCondition *clusterv1.Condition
+	}{
+		{
+			name:              "all the endpoints are reachable",
+			endpoints:         []string{reachableEndpoint},
+			expectedCondition: conditions.TrueCondition(controlplanev1.ExternalEtcdReachableCondition),
+		},
+		{
+			name:      "some of the endpoints are not reachable",
+			endpoints: []string{reachableEndpoint, unreachableEndpoint},
+			expectedCondition: conditions.FalseCondition(controlplanev1.ExternalEtcdReachableCondition, controlplanev1.ExternalEtcdUnreachableReason, clusterv1.ConditionSeverityWarning,
+				"Some of the external etcd endpoints are not reachable: %s", unreachableEndpoint),
+		},
+		{
+			name:      "none of the endpoints is reachable",
+			endpoints: []string{unreachableEndpoint},
+			expectedCondition: conditions.FalseCondition(controlplanev1.ExternalEtcdReachableCondition, controlplanev1.ExternalEtcdUnreachableReason, clusterv1.ConditionSeverityError,
+				"None of the external etcd endpoints is reachable: %s", unreachableEndpoint),
+		},
+		{
+			name:              "the endpoints are dialed through the workload cluster dialer",
+			restConfig:        tunnelConfig,
+			endpoints:         []string{tunneledEndpoint},
+			expectedCondition: conditions.TrueCondition(controlplanev1.ExternalEtcdReachableCondition),
+		},
+		{
+			name:       "endpoints which are not reachable through the workload cluster dialer",
+			restConfig: tunnelConfig,
+			endpoints:  []string{tunneledEndpoint, reachableEndpoint},
+			expectedCondition: conditions.FalseCondition(controlplanev1.ExternalEtcdReachableCondition, controlplanev1.ExternalEtcdUnreachableReason, clusterv1.ConditionSeverityWarning,
+				"Some of the external etcd endpoints are not reachable: %s", reachableEndpoint),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kcp := &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+						ClusterConfiguration: &v1beta1.ClusterConfiguration{
+							Etcd: v1beta1.Etcd{
+								External: &v1beta1.ExternalEtcd{
+									Endpoints: tt.endpoints,
+								},
+							},
+						},
+					},
+				},
+			}
+			// The etcd client generator and the client are not set, given that the external etcd members must not be
+			// inspected through the nodes.
+			w := &Workload{restConfig: tt.restConfig}
+			w.UpdateEtcdConditions(ctx, &ControlPlane{KCP: kcp})
+
+			g.Expect(*conditions.Get(kcp, controlplanev1.EtcdClusterHealthyCondition)).To(conditions.MatchCondition(*conditions.TrueCondition(controlplanev1.EtcdClusterHealthyCondition)))
+			g.Expect(*conditions.Get(kcp, controlplanev1.ExternalEtcdReachableCondition)).To(conditions.MatchCondition(*tt.expectedCondition))
+		})
+	}
+}
+
 func TestUpdateStaticPodConditions(t *testing.T) {
 	n1APIServerPodName := staticPodName("kube-apiserver", "n1")
 	n1APIServerPodkey := client.ObjectKey{
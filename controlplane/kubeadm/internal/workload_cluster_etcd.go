@@ -234,3 +234,69 @@ func (w *Workload) EtcdMembers(ctx context.Context) ([]string, error) {
 	}
 	return names, nil
 }
+
+// EtcdLeader returns the name of the node hosting the etcd leader, or an empty string
+// if the leader is not among the etcd members.
+func (w *Workload) EtcdLeader(ctx context.Context) (string, error) {
+	nodes, err := w.getControlPlaneNodes(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list control plane nodes")
+	}
+	nodeNames := make([]string, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeNames = append(nodeNames, node.Name)
+	}
+	etcdClient, err := w.etcdClientGenerator.forLeader(ctx, nodeNames)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create etcd client")
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.Members(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list etcd members using etcd client")
+	}
+
+	for _, member := range members {
+		if member.ID == etcdClient.LeaderID {
+			return member.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// EtcdMembersStatus returns the status of the etcd members hosted on the given nodes, indexed by node name.
+// An error is returned if any of the members cannot be contacted.
+func (w *Workload) EtcdMembersStatus(ctx context.Context, nodeNames []string) (map[string]*etcd.MemberStatus, error) {
+	statuses := make(map[string]*etcd.MemberStatus, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		status, err := w.etcdMemberStatus(ctx, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		statuses[nodeName] = status
+	}
+	return statuses, nil
+}
+
+func (w *Workload) etcdMemberStatus(ctx context.Context, nodeName string) (*etcd.MemberStatus, error) {
+	etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, []string{nodeName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create etcd client for node %s", nodeName)
+	}
+	defer etcdClient.Close()
+
+	return etcdClient.MemberStatus(ctx)
+}
+
+// DefragmentEtcdMember defragments the backend database of the etcd member hosted on the given node.
+// NOTE: Callers must ensure that members are defragmented one at a time, in order to preserve quorum.
+func (w *Workload) DefragmentEtcdMember(ctx context.Context, nodeName string) error {
+	etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, []string{nodeName})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create etcd client for node %s", nodeName)
+	}
+	defer etcdClient.Close()
+
+	return etcdClient.Defragment(ctx)
+}
@@ -569,6 +569,133 @@ kind: ClusterStatus`,
 
 }
 
+func TestEtcdLeader(t *testing.T) {
+	tests := []struct {
+		name                string
+		k8sClient           client.Client
+		etcdClientGenerator etcdClientFor
+		expectErr           bool
+		expectedLeader      string
+	}{
+		{
+			name:      "returns an error if it can't retrieve the list of control plane nodes",
+			k8sClient: &fakeClient{listErr: errors.New("failed to list nodes")},
+			expectErr: true,
+		},
+		{
+			name:                "returns an error if it can't create an etcd client",
+			k8sClient:           &fakeClient{list: &corev1.NodeList{}},
+			etcdClientGenerator: &fakeEtcdClientGenerator{forLeaderErr: errors.New("no etcdClient")},
+			expectErr:           true,
+		},
+		{
+			name: "returns the node hosting the etcd leader",
+			k8sClient: &fakeClient{list: &corev1.NodeList{
+				Items: []corev1.Node{nodeNamed("node-1"), nodeNamed("node-2")},
+			}},
+			etcdClientGenerator: &fakeEtcdClientGenerator{
+				forLeaderClient: &etcd.Client{
+					EtcdClient: &fake2.FakeEtcdClient{
+						MemberListResponse: &clientv3.MemberListResponse{
+							Members: []*pb.Member{
+								{Name: "node-1", ID: uint64(101)},
+								{Name: "node-2", ID: uint64(102)},
+							},
+						},
+						AlarmResponse: &clientv3.AlarmResponse{
+							Alarms: []*pb.AlarmMember{},
+						},
+					},
+					LeaderID: 102,
+				},
+			},
+			expectedLeader: "node-2",
+		},
+		{
+			name: "returns an empty string if the leader is not a member",
+			k8sClient: &fakeClient{list: &corev1.NodeList{
+				Items: []corev1.Node{nodeNamed("node-1"), nodeNamed("node-2")},
+			}},
+			etcdClientGenerator: &fakeEtcdClientGenerator{
+				forLeaderClient: &etcd.Client{
+					EtcdClient: &fake2.FakeEtcdClient{
+						MemberListResponse: &clientv3.MemberListResponse{
+							Members: []*pb.Member{
+								{Name: "node-1", ID: uint64(101)},
+							},
+						},
+						AlarmResponse: &clientv3.AlarmResponse{
+							Alarms: []*pb.AlarmMember{},
+						},
+					},
+					LeaderID: 555,
+				},
+			},
+			expectedLeader: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			w := &Workload{
+				Client:              tt.k8sClient,
+				etcdClientGenerator: tt.etcdClientGenerator,
+			}
+			leader, err := w.EtcdLeader(ctx)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(leader).To(Equal(tt.expectedLeader))
+		})
+	}
+}
+
+func TestEtcdMembersStatus(t *testing.T) {
+	fakeClients := map[string]*fake2.FakeEtcdClient{
+		"node-1": {StatusResponse: &clientv3.StatusResponse{Header: &pb.ResponseHeader{MemberId: 1}, Leader: 2, DbSize: 100}},
+		"node-2": {StatusResponse: &clientv3.StatusResponse{Header: &pb.ResponseHeader{MemberId: 2}, Leader: 2, DbSize: 200}},
+	}
+	generator := &fakeEtcdClientGenerator{
+		forNodesClientFunc: func(n []string) (*etcd.Client, error) {
+			fakeClient, ok := fakeClients[n[0]]
+			if !ok {
+				return nil, errors.New("no etcd member")
+			}
+			return &etcd.Client{EtcdClient: fakeClient, Endpoint: n[0]}, nil
+		},
+	}
+
+	t.Run("returns the status of all the members", func(t *testing.T) {
+		g := NewWithT(t)
+
+		w := &Workload{etcdClientGenerator: generator}
+		statuses, err := w.EtcdMembersStatus(ctx, []string{"node-1", "node-2"})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(statuses).To(HaveLen(2))
+		g.Expect(*statuses["node-1"]).To(Equal(etcd.MemberStatus{ID: 1, IsLeader: false, DBSize: 100}))
+		g.Expect(*statuses["node-2"]).To(Equal(etcd.MemberStatus{ID: 2, IsLeader: true, DBSize: 200}))
+	})
+
+	t.Run("returns an error if a member cannot be contacted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		w := &Workload{etcdClientGenerator: generator}
+		_, err := w.EtcdMembersStatus(ctx, []string{"node-1", "node-3"})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("defragments only the member on the given node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		w := &Workload{etcdClientGenerator: generator}
+		g.Expect(w.DefragmentEtcdMember(ctx, "node-2")).To(Succeed())
+		g.Expect(fakeClients["node-1"].DefragmentedMembers).To(BeEmpty())
+		g.Expect(fakeClients["node-2"].DefragmentedMembers).To(Equal([]string{"node-2"}))
+	})
+}
+
 type fakeEtcdClientGenerator struct {
 	forNodesClient     *etcd.Client
 	forNodesClientFunc func([]string) (*etcd.Client, error)
@@ -18,8 +18,10 @@ package internal
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"testing"
 	"time"
 
@@ -1005,3 +1007,19 @@ func newKubeProxyDSWithImage(image string) appsv1.DaemonSet {
 	ds.Spec.Template.Spec.Containers[0].Image = image
 	return ds
 }
+
+func TestHandshakeWithTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	// The server side of the connection never answers the handshake.
+	clientConn, serverConn := net.Pipe()
+	defer serverConn.Close()
+	go func() {
+		_, _ = serverConn.Read(make([]byte, 1024))
+	}()
+
+	tlsConn := tls.Client(clientConn, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	err := handshakeWithTimeout(ctx, tlsConn, 100*time.Millisecond)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("timed out waiting for the TLS handshake"))
+}
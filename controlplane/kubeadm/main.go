@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	kcpv1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	kubeadmcontrolplanecontrollers "sigs.k8s.io/cluster-api/controlplane/kubeadm/controllers"
+	"sigs.k8s.io/cluster-api/util/multinamespace"
 	"sigs.k8s.io/cluster-api/version"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -65,11 +66,14 @@ var (
 	leaderElectionRenewDeadline    time.Duration
 	leaderElectionRetryPeriod      time.Duration
 	watchNamespace                 string
+	watchNamespaces                []string
 	profilerAddress                string
 	kubeadmControlPlaneConcurrency int
 	syncPeriod                     time.Duration
 	webhookPort                    int
 	webhookCertDir                 string
+	workloadClusterQPS             float32
+	workloadClusterBurst           int
 )
 
 // InitFlags initializes the flags.
@@ -92,6 +96,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&watchNamespace, "namespace", "",
 		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.")
 
+	fs.StringSliceVar(&watchNamespaces, "watch-namespaces", nil,
+		"Comma-separated list of namespaces that the controller watches to reconcile cluster-api objects, in addition to the one set with --namespace. If both are unspecified, the controller watches for cluster-api objects across all namespaces.")
+
 	fs.StringVar(&profilerAddress, "profiler-address", "",
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)")
 
@@ -106,6 +113,12 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs/",
 		"Webhook cert dir, only used when webhook-port is specified.")
+
+	fs.Float32Var(&workloadClusterQPS, "workload-cluster-qps", 20,
+		"Maximum queries per second from the clients created for the workload clusters")
+
+	fs.IntVar(&workloadClusterBurst, "workload-cluster-burst", 30,
+		"Maximum burst for throttling the clients created for the workload clusters")
 }
 func main() {
 	rand.Seed(time.Now().UnixNano())
@@ -123,7 +136,7 @@ func main() {
 		}()
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	options := ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsBindAddr,
 		LeaderElection:     enableLeaderElection,
@@ -131,7 +144,6 @@ func main() {
 		LeaseDuration:      &leaderElectionLeaseDuration,
 		RenewDeadline:      &leaderElectionRenewDeadline,
 		RetryPeriod:        &leaderElectionRetryPeriod,
-		Namespace:          watchNamespace,
 		SyncPeriod:         &syncPeriod,
 		ClientDisableCacheFor: []client.Object{
 			&corev1.ConfigMap{},
@@ -139,7 +151,10 @@ func main() {
 		},
 		Port:    webhookPort,
 		CertDir: webhookCertDir,
-	})
+	}
+	multinamespace.SetManagerOptions(&options, append([]string{watchNamespace}, watchNamespaces...)...)
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -162,9 +177,13 @@ func main() {
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	// Set up a ClusterCacheTracker to provide to controllers
 	// requiring a connection to a remote cluster
-	tracker, err := remote.NewClusterCacheTracker(
+	tracker, err := remote.NewClusterCacheTrackerWithOptions(
 		ctrl.Log.WithName("remote").WithName("ClusterCacheTracker"),
 		mgr,
+		remote.ClusterCacheTrackerOptions{
+			ClientQPS:   workloadClusterQPS,
+			ClientBurst: workloadClusterBurst,
+		},
 	)
 	if err != nil {
 		setupLog.Error(err, "unable to create cluster cache tracker")
@@ -25,3 +25,15 @@ import (
 func Convert_v1alpha3_MachinePoolSpec_To_v1alpha4_MachinePoolSpec(in *MachinePoolSpec, out *v1alpha4.MachinePoolSpec, s conversion.Scope) error {
 	return autoConvert_v1alpha3_MachinePoolSpec_To_v1alpha4_MachinePoolSpec(in, out, s)
 }
+
+// Convert_v1alpha4_MachinePoolSpec_To_v1alpha3_MachinePoolSpec converts from the Hub version (v1alpha4) of the MachinePoolSpec to this version.
+// MachinePoolSpec.RolloutStrategy does not exist in v1alpha3.
+func Convert_v1alpha4_MachinePoolSpec_To_v1alpha3_MachinePoolSpec(in *v1alpha4.MachinePoolSpec, out *MachinePoolSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha4_MachinePoolSpec_To_v1alpha3_MachinePoolSpec(in, out, s)
+}
+
+// Convert_v1alpha4_MachinePoolStatus_To_v1alpha3_MachinePoolStatus converts from the Hub version (v1alpha4) of the MachinePoolStatus to this version.
+// MachinePoolStatus.Instances does not exist in v1alpha3.
+func Convert_v1alpha4_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in *v1alpha4.MachinePoolStatus, out *MachinePoolStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha4_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in, out, s)
+}
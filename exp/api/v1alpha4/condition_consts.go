@@ -28,3 +28,26 @@ const (
 	// to be ready.
 	WaitingForReplicasReadyReason = "WaitingForReplicasReady"
 )
+
+const (
+	// InstancesReadyCondition reports how many instances of the MachinePool have a corresponding Node which is ready,
+	// out of the instances listed in Spec.ProviderIDList.
+	InstancesReadyCondition clusterv1.ConditionType = "InstancesReady"
+
+	// WaitingForInstancesReadyReason (Severity=Info) documents a machinepool waiting for the Nodes of some
+	// instances to be created.
+	WaitingForInstancesReadyReason = "WaitingForInstancesReady"
+
+	// InstancesNotReadyReason (Severity=Warning) documents a machinepool with some instances whose Node
+	// exists but it is not ready.
+	InstancesNotReadyReason = "InstancesNotReady"
+)
+
+const (
+	// InstancesUpToDateCondition reports whether all the instances of a MachinePool using the ManagedRollingUpdate
+	// rollout strategy have been created from the current template.
+	InstancesUpToDateCondition clusterv1.ConditionType = "InstancesUpToDate"
+
+	// RollingUpdateInProgressReason (Severity=Info) documents a machinepool replacing its outdated instances.
+	RollingUpdateInProgressReason = "RollingUpdateInProgress"
+)
@@ -19,6 +19,7 @@ package v1alpha4
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 )
@@ -57,10 +58,59 @@ type MachinePoolSpec struct {
 
 	// FailureDomains is the list of failure domains this MachinePool should be attached to.
 	FailureDomains []string `json:"failureDomains,omitempty"`
+
+	// RolloutStrategy defines how the instances of the MachinePool are replaced when the template changes.
+	// If not set, replacing the instances is delegated to the infrastructure provider.
+	// +optional
+	RolloutStrategy *MachinePoolRolloutStrategy `json:"rolloutStrategy,omitempty"`
 }
 
 // ANCHOR_END: MachinePoolSpec
 
+// ANCHOR: MachinePoolRolloutStrategy
+
+// MachinePoolRolloutStrategy describes how to replace the instances of a MachinePool.
+type MachinePoolRolloutStrategy struct {
+	// Type of rollout. Allowed values are "InfrastructureManaged" and "ManagedRollingUpdate".
+	// Default is InfrastructureManaged.
+	// +kubebuilder:validation:Enum=InfrastructureManaged;ManagedRollingUpdate
+	// +optional
+	Type MachinePoolRolloutStrategyType `json:"type,omitempty"`
+
+	// ManagedRollingUpdate config params. Present only if
+	// MachinePoolRolloutStrategyType = ManagedRollingUpdate.
+	// +optional
+	ManagedRollingUpdate *MachinePoolManagedRollingUpdate `json:"managedRollingUpdate,omitempty"`
+}
+
+// MachinePoolRolloutStrategyType defines the type of MachinePool rollout strategies.
+type MachinePoolRolloutStrategyType string
+
+const (
+	// InfrastructureManagedMachinePoolRolloutStrategyType delegates replacing the outdated instances
+	// to the infrastructure provider.
+	InfrastructureManagedMachinePoolRolloutStrategyType = MachinePoolRolloutStrategyType("InfrastructureManaged")
+
+	// ManagedRollingUpdateMachinePoolRolloutStrategyType drains the outdated instances in batches, and
+	// marks them for deletion in Status.Instances; the infrastructure provider is expected to delete
+	// the marked instances and to replace them with instances created from the current template.
+	ManagedRollingUpdateMachinePoolRolloutStrategyType = MachinePoolRolloutStrategyType("ManagedRollingUpdate")
+)
+
+// MachinePoolManagedRollingUpdate is used to control the desired behavior of a managed rolling update.
+type MachinePoolManagedRollingUpdate struct {
+	// The maximum number of instances that can be unavailable during the update.
+	// Value can be an absolute number (ex: 5) or a percentage of desired
+	// instances (ex: 10%).
+	// Absolute number is calculated from percentage by rounding down, but
+	// at least one instance is replaced at a time.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ANCHOR_END: MachinePoolRolloutStrategy
+
 // ANCHOR: MachinePoolStatus
 
 // MachinePoolStatus defines the observed state of MachinePool
@@ -69,6 +119,11 @@ type MachinePoolStatus struct {
 	// +optional
 	NodeRefs []corev1.ObjectReference `json:"nodeRefs,omitempty"`
 
+	// Instances reports the status of each instance of the MachinePool, correlating the provider IDs
+	// listed in Spec.ProviderIDList with the Nodes in the workload cluster.
+	// +optional
+	Instances []MachinePoolInstanceStatus `json:"instances,omitempty"`
+
 	// Replicas is the most recently observed number of replicas.
 	// +optional
 	Replicas int32 `json:"replicas"`
@@ -123,6 +178,32 @@ type MachinePoolStatus struct {
 
 // ANCHOR_END: MachinePoolStatus
 
+// MachinePoolInstanceStatus defines the observed state of an instance of a MachinePool.
+type MachinePoolInstanceStatus struct {
+	// ProviderID is the identification ID of the instance, as listed in Spec.ProviderIDList.
+	ProviderID string `json:"providerID"`
+
+	// NodeRef will point to the corresponding Node if it exists.
+	// +optional
+	NodeRef *corev1.ObjectReference `json:"nodeRef,omitempty"`
+
+	// Ready is true when the corresponding Node exists and it is ready.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// TemplateHash is the hash of the MachinePool template observed when the instance was first
+	// reported by the infrastructure provider; it is used to detect outdated instances.
+	// +optional
+	TemplateHash string `json:"templateHash,omitempty"`
+
+	// Delete is set to true by the MachinePool controller once the Node of the instance has been drained
+	// by a managed rolling update, and it is never reset. The infrastructure provider must then delete the
+	// instance, remove its provider ID from spec.providerIDList and create a replacement from the current
+	// template; the instance is removed from the status once its provider ID is no longer listed.
+	// +optional
+	Delete bool `json:"delete,omitempty"`
+}
+
 // MachinePoolPhase is a string representation of a MachinePool Phase.
 //
 // This type is a high-level indicator of the status of the MachinePool as it is provisioned,
@@ -23,6 +23,7 @@ import (
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -63,6 +64,23 @@ func (m *MachinePool) Default() {
 	if len(m.Spec.Template.Spec.InfrastructureRef.Namespace) == 0 {
 		m.Spec.Template.Spec.InfrastructureRef.Namespace = m.Namespace
 	}
+
+	if m.Spec.RolloutStrategy != nil {
+		if m.Spec.RolloutStrategy.Type == "" {
+			m.Spec.RolloutStrategy.Type = InfrastructureManagedMachinePoolRolloutStrategyType
+		}
+
+		// Default ManagedRollingUpdate only if the rollout strategy type is ManagedRollingUpdate.
+		if m.Spec.RolloutStrategy.Type == ManagedRollingUpdateMachinePoolRolloutStrategyType {
+			if m.Spec.RolloutStrategy.ManagedRollingUpdate == nil {
+				m.Spec.RolloutStrategy.ManagedRollingUpdate = &MachinePoolManagedRollingUpdate{}
+			}
+			if m.Spec.RolloutStrategy.ManagedRollingUpdate.MaxUnavailable == nil {
+				ios1 := intstr.FromInt(1)
+				m.Spec.RolloutStrategy.ManagedRollingUpdate.MaxUnavailable = &ios1
+			}
+		}
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
@@ -118,6 +136,22 @@ func (m *MachinePool) validate(old *MachinePool) error {
 		)
 	}
 
+	if m.Spec.RolloutStrategy != nil && m.Spec.RolloutStrategy.ManagedRollingUpdate != nil {
+		if m.Spec.RolloutStrategy.Type != ManagedRollingUpdateMachinePoolRolloutStrategyType {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(field.NewPath("spec", "rolloutStrategy", "managedRollingUpdate"), "must not be set when rollout strategy type is not ManagedRollingUpdate"),
+			)
+		} else if maxUnavailable := m.Spec.RolloutStrategy.ManagedRollingUpdate.MaxUnavailable; maxUnavailable != nil {
+			if value, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, 100, false); err != nil || value <= 0 {
+				allErrs = append(
+					allErrs,
+					field.Invalid(field.NewPath("spec", "rolloutStrategy", "managedRollingUpdate", "maxUnavailable"), maxUnavailable.String(), "must be a positive integer or percentage"),
+				)
+			}
+		}
+	}
+
 	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
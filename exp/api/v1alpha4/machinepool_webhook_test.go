@@ -23,6 +23,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
@@ -52,6 +53,113 @@ func TestMachinePoolDefault(t *testing.T) {
 	g.Expect(m.Spec.Template.Spec.InfrastructureRef.Namespace).To(Equal(m.Namespace))
 }
 
+func TestMachinePoolDefaultRolloutStrategy(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &MachinePool{
+		Spec: MachinePoolSpec{
+			RolloutStrategy: &MachinePoolRolloutStrategy{Type: ManagedRollingUpdateMachinePoolRolloutStrategyType},
+		},
+	}
+	m.Default()
+
+	ios1 := intstr.FromInt(1)
+	g.Expect(m.Spec.RolloutStrategy.ManagedRollingUpdate).ToNot(BeNil())
+	g.Expect(m.Spec.RolloutStrategy.ManagedRollingUpdate.MaxUnavailable).To(Equal(&ios1))
+
+	m = &MachinePool{
+		Spec: MachinePoolSpec{
+			RolloutStrategy: &MachinePoolRolloutStrategy{},
+		},
+	}
+	m.Default()
+
+	g.Expect(m.Spec.RolloutStrategy.Type).To(Equal(InfrastructureManagedMachinePoolRolloutStrategyType))
+	g.Expect(m.Spec.RolloutStrategy.ManagedRollingUpdate).To(BeNil())
+}
+
+func TestMachinePoolRolloutStrategyValidation(t *testing.T) {
+	ios0 := intstr.FromInt(0)
+	ios2 := intstr.FromInt(2)
+	percent25 := intstr.FromString("25%")
+	invalid := intstr.FromString("foo")
+
+	tests := []struct {
+		name            string
+		rolloutStrategy *MachinePoolRolloutStrategy
+		expectErr       bool
+	}{
+		{
+			name:            "should not return error if the rollout strategy is not set",
+			rolloutStrategy: nil,
+			expectErr:       false,
+		},
+		{
+			name: "should not return error if maxUnavailable is a positive integer",
+			rolloutStrategy: &MachinePoolRolloutStrategy{
+				Type:                 ManagedRollingUpdateMachinePoolRolloutStrategyType,
+				ManagedRollingUpdate: &MachinePoolManagedRollingUpdate{MaxUnavailable: &ios2},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should not return error if maxUnavailable is a percentage",
+			rolloutStrategy: &MachinePoolRolloutStrategy{
+				Type:                 ManagedRollingUpdateMachinePoolRolloutStrategyType,
+				ManagedRollingUpdate: &MachinePoolManagedRollingUpdate{MaxUnavailable: &percent25},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error if maxUnavailable is zero",
+			rolloutStrategy: &MachinePoolRolloutStrategy{
+				Type:                 ManagedRollingUpdateMachinePoolRolloutStrategyType,
+				ManagedRollingUpdate: &MachinePoolManagedRollingUpdate{MaxUnavailable: &ios0},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if maxUnavailable is invalid",
+			rolloutStrategy: &MachinePoolRolloutStrategy{
+				Type:                 ManagedRollingUpdateMachinePoolRolloutStrategyType,
+				ManagedRollingUpdate: &MachinePoolManagedRollingUpdate{MaxUnavailable: &invalid},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if managedRollingUpdate is set with the InfrastructureManaged type",
+			rolloutStrategy: &MachinePoolRolloutStrategy{
+				Type:                 InfrastructureManagedMachinePoolRolloutStrategyType,
+				ManagedRollingUpdate: &MachinePoolManagedRollingUpdate{MaxUnavailable: &ios2},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &MachinePool{
+				Spec: MachinePoolSpec{
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Bootstrap: clusterv1.Bootstrap{DataSecretName: pointer.StringPtr("test")},
+						},
+					},
+					RolloutStrategy: tt.rolloutStrategy,
+				},
+			}
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
+
 func TestMachinePoolBootstrapValidation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -59,6 +59,7 @@ const (
 // MachinePoolReconciler reconciles a MachinePool object
 type MachinePoolReconciler struct {
 	Client           client.Client
+	Tracker          *remote.ClusterCacheTracker
 	WatchFilterValue string
 
 	config           *rest.Config
@@ -154,6 +155,8 @@ func (r *MachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 					clusterv1.BootstrapReadyCondition,
 					clusterv1.InfrastructureReadyCondition,
 					expv1.ReplicasReadyCondition,
+					expv1.InstancesReadyCondition,
+					expv1.InstancesUpToDateCondition,
 				}},
 			)
 		}
@@ -196,6 +199,7 @@ func (r *MachinePoolReconciler) reconcile(ctx context.Context, cluster *clusterv
 		r.reconcileBootstrap,
 		r.reconcileInfrastructure,
 		r.reconcileNodeRefs,
+		r.reconcileRollout,
 	}
 
 	res := ctrl.Result{}
@@ -29,9 +29,9 @@ import (
 	"github.com/pkg/errors"
 	apicorev1 "k8s.io/api/core/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
-	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -44,6 +44,7 @@ var (
 
 type getNodeReferencesResult struct {
 	references []apicorev1.ObjectReference
+	instances  []expv1.MachinePoolInstanceStatus
 	available  int
 	ready      int
 }
@@ -55,12 +56,6 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 		return ctrl.Result{}, nil
 	}
 
-	// Check that the Machine doesn't already have a NodeRefs.
-	if mp.Status.Replicas == mp.Status.ReadyReplicas && len(mp.Status.NodeRefs) == int(mp.Status.ReadyReplicas) {
-		conditions.MarkTrue(mp, expv1.ReplicasReadyCondition)
-		return ctrl.Result{}, nil
-	}
-
 	// Check that Cluster isn't nil.
 	if cluster == nil {
 		log.V(2).Info("MachinePool doesn't have a linked cluster, won't assign NodeRef")
@@ -69,13 +64,26 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 
 	log = log.WithValues("cluster", cluster.Name)
 
+	// Check that the Machine doesn't already have a NodeRefs, and that the status of its instances
+	// has been recorded if it has ready replicas. In this case the ProviderIDs don't have to be matched
+	// to the Nodes again, but the readiness of the Nodes can still change.
+	if mp.Status.Replicas == mp.Status.ReadyReplicas && len(mp.Status.NodeRefs) == int(mp.Status.ReadyReplicas) &&
+		(mp.Status.ReadyReplicas == 0 || len(mp.Status.Instances) == len(mp.Spec.ProviderIDList)) {
+		if mp.Status.ReadyReplicas == 0 {
+			conditions.MarkTrue(mp, expv1.ReplicasReadyCondition)
+			setInstancesReadyCondition(mp)
+			return ctrl.Result{}, nil
+		}
+		return r.reconcileNodeReadiness(ctx, cluster, mp)
+	}
+
 	// Check that the MachinePool has valid ProviderIDList.
 	if len(mp.Spec.ProviderIDList) == 0 {
 		log.V(2).Info("MachinePool doesn't have any ProviderIDs yet")
 		return ctrl.Result{}, nil
 	}
 
-	clusterClient, err := remote.NewClusterClient(ctx, MachinePoolControllerName, r.Client, util.ObjectKey(cluster))
+	clusterClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -89,6 +97,8 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 	if err != nil {
 		if err == ErrNoAvailableNodes {
 			log.Info("Cannot assign NodeRefs to MachinePool, no matching Nodes")
+			mp.Status.Instances = preserveInstanceRolloutStatus(mp.Status.Instances, nodeRefsResult.instances)
+			setInstancesReadyCondition(mp)
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
 		r.recorder.Event(mp, apicorev1.EventTypeWarning, "FailedSetNodeRef", err.Error())
@@ -99,6 +109,8 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 	mp.Status.AvailableReplicas = int32(nodeRefsResult.available)
 	mp.Status.UnavailableReplicas = mp.Status.Replicas - mp.Status.AvailableReplicas
 	mp.Status.NodeRefs = nodeRefsResult.references
+	mp.Status.Instances = preserveInstanceRolloutStatus(mp.Status.Instances, nodeRefsResult.instances)
+	setInstancesReadyCondition(mp)
 
 	log.Info("Set MachinePools's NodeRefs", "noderefs", mp.Status.NodeRefs)
 	r.recorder.Event(mp, apicorev1.EventTypeNormal, "SuccessfulSetNodeRefs", fmt.Sprintf("%+v", mp.Status.NodeRefs))
@@ -139,6 +151,48 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 	return ctrl.Result{}, nil
 }
 
+// reconcileNodeReadiness refreshes the readiness of the Nodes already referenced by the instances of the MachinePool.
+func (r *MachinePoolReconciler) reconcileNodeReadiness(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
+
+	clusterClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var ready int
+	for i := range mp.Status.Instances {
+		instance := &mp.Status.Instances[i]
+		if instance.NodeRef == nil {
+			continue
+		}
+		node := &corev1.Node{}
+		if err := clusterClient.Get(ctx, client.ObjectKey{Name: instance.NodeRef.Name}, node); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, errors.Wrapf(err, "failed to get Node %s", instance.NodeRef.Name)
+			}
+			// The Node is gone, the NodeRefs are going to be matched again on the next reconcile.
+			instance.Ready = false
+			continue
+		}
+		instance.Ready = nodeIsReady(node)
+		if instance.Ready {
+			ready++
+		}
+	}
+	mp.Status.ReadyReplicas = int32(ready)
+	setInstancesReadyCondition(mp)
+
+	if mp.Status.Replicas != mp.Status.ReadyReplicas {
+		log.Info("Some of the MachinePool Nodes are not ready", "Replicas", mp.Status.Replicas, "ReadyReplicas", mp.Status.ReadyReplicas)
+		conditions.MarkFalse(mp, expv1.ReplicasReadyCondition, expv1.WaitingForReplicasReadyReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	conditions.MarkTrue(mp, expv1.ReplicasReadyCondition)
+	return ctrl.Result{}, nil
+}
+
 // deleteRetiredNodes deletes nodes that don't have a corresponding ProviderID in Spec.ProviderIDList.
 // A MachinePool infrastructure provider indicates an instance in the set has been deleted by
 // removing its ProviderID from the slice.
@@ -203,30 +257,64 @@ func (r *MachinePoolReconciler) getNodeReferences(ctx context.Context, c client.
 	}
 
 	var nodeRefs []apicorev1.ObjectReference
+	instances := make([]expv1.MachinePoolInstanceStatus, 0, len(providerIDList))
 	for _, providerID := range providerIDList {
+		instance := expv1.MachinePoolInstanceStatus{ProviderID: providerID}
 		pid, err := noderefutil.NewProviderID(providerID)
 		if err != nil {
 			log.V(2).Info("Failed to parse ProviderID, skipping", "err", err, "providerID", providerID)
+			instances = append(instances, instance)
 			continue
 		}
 		if node, ok := nodeRefsMap[pid.ID()]; ok {
 			available++
-			if nodeIsReady(&node) {
-				ready++
-			}
-			nodeRefs = append(nodeRefs, apicorev1.ObjectReference{
+			nodeRef := apicorev1.ObjectReference{
 				Kind:       node.Kind,
 				APIVersion: node.APIVersion,
 				Name:       node.Name,
 				UID:        node.UID,
-			})
+			}
+			if nodeIsReady(&node) {
+				ready++
+				instance.Ready = true
+			}
+			nodeRefs = append(nodeRefs, nodeRef)
+			instance.NodeRef = &nodeRef
 		}
+		instances = append(instances, instance)
 	}
 
 	if len(nodeRefs) == 0 {
-		return getNodeReferencesResult{}, ErrNoAvailableNodes
+		return getNodeReferencesResult{instances: instances}, ErrNoAvailableNodes
+	}
+	return getNodeReferencesResult{nodeRefs, instances, available, ready}, nil
+}
+
+// setInstancesReadyCondition summarizes Status.Instances into the InstancesReady condition.
+// Instances with a Node which is not ready are reported with a warning, given that they most likely
+// require attention, while instances still waiting for a Node are reported as info.
+func setInstancesReadyCondition(mp *expv1.MachinePool) {
+	var ready, notReady int
+	for _, instance := range mp.Status.Instances {
+		switch {
+		case instance.Ready:
+			ready++
+		case instance.NodeRef != nil:
+			notReady++
+		}
+	}
+
+	total := len(mp.Status.Instances)
+	switch {
+	case ready == total:
+		conditions.MarkTrue(mp, expv1.InstancesReadyCondition)
+	case notReady > 0:
+		conditions.MarkFalse(mp, expv1.InstancesReadyCondition, expv1.InstancesNotReadyReason, clusterv1.ConditionSeverityWarning,
+			"%d of %d instances are ready, %d have a Node which is not ready", ready, total, notReady)
+	default:
+		conditions.MarkFalse(mp, expv1.InstancesReadyCondition, expv1.WaitingForInstancesReadyReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d instances are ready", ready, total)
 	}
-	return getNodeReferencesResult{nodeRefs, available, ready}, nil
 }
 
 func nodeIsReady(node *apicorev1.Node) bool {
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -27,8 +28,12 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestMachinePoolGetNodeReference(t *testing.T) {
@@ -164,3 +169,196 @@ func TestMachinePoolGetNodeReference(t *testing.T) {
 
 	}
 }
+
+func TestMachinePoolGetNodeReferenceInstances(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &MachinePoolReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	nodeList := []client.Object{
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ready-node",
+			},
+			Spec: corev1.NodeSpec{
+				ProviderID: "aws://us-east-1/id-node-1",
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "not-ready-node",
+			},
+			Spec: corev1.NodeSpec{
+				ProviderID: "aws://us-east-1/id-node-2",
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+				},
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().WithObjects(nodeList...).Build()
+
+	providerIDList := []string{"aws://us-east-1/id-node-1", "aws://us-east-1/id-node-2", "aws://us-east-1/id-node-3"}
+	result, err := r.getNodeReferences(ctx, client, providerIDList)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.ready).To(Equal(1))
+	g.Expect(result.available).To(Equal(2))
+	g.Expect(result.instances).To(HaveLen(3))
+
+	g.Expect(result.instances[0].ProviderID).To(Equal("aws://us-east-1/id-node-1"))
+	g.Expect(result.instances[0].NodeRef).NotTo(BeNil())
+	g.Expect(result.instances[0].NodeRef.Name).To(Equal("ready-node"))
+	g.Expect(result.instances[0].Ready).To(BeTrue())
+
+	g.Expect(result.instances[1].ProviderID).To(Equal("aws://us-east-1/id-node-2"))
+	g.Expect(result.instances[1].NodeRef).NotTo(BeNil())
+	g.Expect(result.instances[1].NodeRef.Name).To(Equal("not-ready-node"))
+	g.Expect(result.instances[1].Ready).To(BeFalse())
+
+	g.Expect(result.instances[2].ProviderID).To(Equal("aws://us-east-1/id-node-3"))
+	g.Expect(result.instances[2].NodeRef).To(BeNil())
+	g.Expect(result.instances[2].Ready).To(BeFalse())
+
+	// Instances are still reported when none of them has a Node.
+	result, err = r.getNodeReferences(ctx, client, []string{"aws://us-east-1/id-node-3"})
+	g.Expect(err).To(Equal(ErrNoAvailableNodes))
+	g.Expect(result.instances).To(HaveLen(1))
+	g.Expect(result.instances[0].NodeRef).To(BeNil())
+}
+
+func TestMachinePoolReconcileNodeRefsReadiness(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+	}
+	readyNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{ProviderID: "aws://us-east-1/id-node-1"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	// A Node which became not ready after the NodeRefs of the MachinePool have been assigned.
+	notReadyNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-2"},
+		Spec:       corev1.NodeSpec{ProviderID: "aws://us-west-2/id-node-2"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "machinepool-test"},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName:    cluster.Name,
+			ProviderIDList: []string{readyNode.Spec.ProviderID, notReadyNode.Spec.ProviderID},
+		},
+		Status: expv1.MachinePoolStatus{
+			Replicas:      2,
+			ReadyReplicas: 2,
+			NodeRefs: []corev1.ObjectReference{
+				{Kind: "Node", Name: readyNode.Name},
+				{Kind: "Node", Name: notReadyNode.Name},
+			},
+			Instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: readyNode.Spec.ProviderID, NodeRef: &corev1.ObjectReference{Kind: "Node", Name: readyNode.Name}, Ready: true},
+				{ProviderID: notReadyNode.Spec.ProviderID, NodeRef: &corev1.ObjectReference{Kind: "Node", Name: notReadyNode.Name}, Ready: true},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cluster, readyNode, notReadyNode).Build()
+	r := &MachinePoolReconciler{
+		Client:   c,
+		Tracker:  remote.NewTestClusterCacheTracker(log.NullLogger{}, c, scheme.Scheme, client.ObjectKeyFromObject(cluster)),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	result, err := r.reconcileNodeRefs(ctx, cluster, mp)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+	g.Expect(mp.Status.ReadyReplicas).To(Equal(int32(1)))
+	g.Expect(mp.Status.Instances[0].Ready).To(BeTrue())
+	g.Expect(mp.Status.Instances[1].Ready).To(BeFalse())
+	g.Expect(conditions.IsFalse(mp, expv1.ReplicasReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(mp, expv1.InstancesReadyCondition)).To(Equal(expv1.InstancesNotReadyReason))
+}
+
+func TestSetInstancesReadyCondition(t *testing.T) {
+	nodeRef := &corev1.ObjectReference{Name: "node"}
+
+	testCases := []struct {
+		name             string
+		instances        []expv1.MachinePoolInstanceStatus
+		expectedStatus   corev1.ConditionStatus
+		expectedReason   string
+		expectedSeverity clusterv1.ConditionSeverity
+		expectedMessage  string
+	}{
+		{
+			name: "all instances ready",
+			instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: "aws:///id-1", NodeRef: nodeRef, Ready: true},
+				{ProviderID: "aws:///id-2", NodeRef: nodeRef, Ready: true},
+			},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "instances waiting for nodes",
+			instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: "aws:///id-1", NodeRef: nodeRef, Ready: true},
+				{ProviderID: "aws:///id-2"},
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   expv1.WaitingForInstancesReadyReason,
+			expectedSeverity: clusterv1.ConditionSeverityInfo,
+			expectedMessage:  "1 of 2 instances are ready",
+		},
+		{
+			name: "instances with nodes not ready",
+			instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: "aws:///id-1", NodeRef: nodeRef, Ready: true},
+				{ProviderID: "aws:///id-2", NodeRef: nodeRef},
+				{ProviderID: "aws:///id-3"},
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   expv1.InstancesNotReadyReason,
+			expectedSeverity: clusterv1.ConditionSeverityWarning,
+			expectedMessage:  "1 of 3 instances are ready, 1 have a Node which is not ready",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mp := &expv1.MachinePool{
+				Status: expv1.MachinePoolStatus{
+					Instances: tc.instances,
+				},
+			}
+			setInstancesReadyCondition(mp)
+
+			condition := conditions.Get(mp, expv1.InstancesReadyCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectedStatus))
+			g.Expect(condition.Reason).To(Equal(tc.expectedReason))
+			g.Expect(condition.Severity).To(Equal(tc.expectedSeverity))
+			g.Expect(condition.Message).To(Equal(tc.expectedMessage))
+		})
+	}
+}
@@ -30,10 +30,13 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 func init() {
@@ -43,7 +46,10 @@ func init() {
 var _ = Describe("Reconcile MachinePool Phases", func() {
 	deletionTimestamp := metav1.Now()
 
-	var defaultKubeconfigSecret *corev1.Secret
+	var (
+		defaultKubeconfigSecret *corev1.Secret
+		defaultTracker          *remote.ClusterCacheTracker
+	)
 	defaultCluster := &clusterv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-cluster",
@@ -110,6 +116,7 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 
 	BeforeEach(func() {
 		defaultKubeconfigSecret = kubeconfig.GenerateSecret(defaultCluster, kubeconfig.FromEnvTestConfig(testEnv.Config, defaultCluster))
+		defaultTracker = remote.NewTestClusterCacheTracker(log.NullLogger{}, testEnv, scheme.Scheme, client.ObjectKeyFromObject(defaultCluster))
 	})
 
 	It("Should set OwnerReference and cluster name label on external objects", func() {
@@ -118,7 +125,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		infraConfig := defaultInfra.DeepCopy()
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -144,7 +152,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		infraConfig := defaultInfra.DeepCopy()
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -168,7 +177,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -208,7 +218,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		machinepool.Status.NodeRefs = []corev1.ObjectReference{{Kind: "Node", Name: "machinepool-test-node"}}
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -260,7 +271,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		machinepool.Status.NodeRefs = []corev1.ObjectReference{{Kind: "Node", Name: "machinepool-test-node"}}
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -290,7 +302,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		machinepool.Status.NodeRefs = []corev1.ObjectReference{{Kind: "Node", Name: "machinepool-test-node"}}
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -327,7 +340,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		machinepool.Status.NodeRefs = []corev1.ObjectReference{{Kind: "Node", Name: "machinepool-test-node"}}
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -377,7 +391,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		}
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -432,7 +447,8 @@ var _ = Describe("Reconcile MachinePool Phases", func() {
 		machinepool.SetDeletionTimestamp(&deletionTimestamp)
 
 		r := &MachinePoolReconciler{
-			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(defaultCluster, defaultKubeconfigSecret, machinepool, bootstrapConfig, infraConfig).Build(),
+			Tracker: defaultTracker,
 		}
 
 		res, err := r.reconcile(ctx, defaultCluster, machinepool)
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/drainutil"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	kubedrain "sigs.k8s.io/cluster-api/third_party/kubernetes-drain"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var (
+	// rolloutRequeueAfter is how long to wait before checking again the progress of a managed rolling update.
+	rolloutRequeueAfter = 20 * time.Second
+)
+
+// reconcileRollout records the template the instances of the MachinePool have been created from and, if the
+// MachinePool uses the ManagedRollingUpdate rollout strategy, drains the outdated instances in batches and
+// marks them for deletion, so that the infrastructure provider replaces them.
+func (r *MachinePoolReconciler) reconcileRollout(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool) (ctrl.Result, error) {
+	return r.rollout(ctx, mp, func() (kubernetes.Interface, error) {
+		restConfig, err := remote.RESTConfig(ctx, MachinePoolControllerName, r.Client, util.ObjectKey(cluster))
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.NewForConfig(restConfig)
+	})
+}
+
+func (r *MachinePoolReconciler) rollout(ctx context.Context, mp *expv1.MachinePool, kubeClientFn func() (kubernetes.Interface, error)) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	templateHash := machinePoolTemplateHash(mp)
+	for i := range mp.Status.Instances {
+		if mp.Status.Instances[i].TemplateHash == "" {
+			mp.Status.Instances[i].TemplateHash = templateHash
+		}
+	}
+
+	if mp.Spec.RolloutStrategy == nil || mp.Spec.RolloutStrategy.Type != expv1.ManagedRollingUpdateMachinePoolRolloutStrategyType {
+		conditions.Delete(mp, expv1.InstancesUpToDateCondition)
+		return ctrl.Result{}, nil
+	}
+
+	var upToDate int
+	for _, instance := range mp.Status.Instances {
+		if instance.TemplateHash == templateHash {
+			upToDate++
+		}
+	}
+	total := len(mp.Status.Instances)
+	if upToDate == total {
+		conditions.MarkTrue(mp, expv1.InstancesUpToDateCondition)
+		return ctrl.Result{}, nil
+	}
+	conditions.MarkFalse(mp, expv1.InstancesUpToDateCondition, expv1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo,
+		"%d of %d instances are up to date", upToDate, total)
+
+	batch := instancesToReplace(mp, templateHash)
+	if len(batch) == 0 {
+		log.V(2).Info("Waiting for the replaced instances to become available before continuing the rolling update")
+		return ctrl.Result{RequeueAfter: rolloutRequeueAfter}, nil
+	}
+
+	// The Nodes of the batch are drained without waiting for their pods to be deleted, and the instances are
+	// marked for deletion at a later reconcile, once their Nodes have no pods left to evict.
+	var kubeClient kubernetes.Interface
+	for _, instance := range batch {
+		if instance.NodeRef != nil {
+			if kubeClient == nil {
+				var err error
+				if kubeClient, err = kubeClientFn(); err != nil {
+					return ctrl.Result{}, errors.Wrap(err, "failed to create a client for the workload cluster")
+				}
+			}
+			drained, err := drainNode(ctx, kubeClient, instance.NodeRef.Name)
+			if err != nil {
+				// The instance will be drained again at the next reconcile.
+				log.Error(err, "Drain failed, retry in 20s", "providerID", instance.ProviderID, "node", instance.NodeRef.Name)
+				continue
+			}
+			if !drained {
+				log.Info("Draining in progress, retry in 20s", "providerID", instance.ProviderID, "node", instance.NodeRef.Name)
+				continue
+			}
+		}
+		log.Info("Marking outdated instance for deletion", "providerID", instance.ProviderID)
+		instance.Delete = true
+	}
+	return ctrl.Result{RequeueAfter: rolloutRequeueAfter}, nil
+}
+
+// instancesToReplace returns the outdated instances to drain and mark for deletion next. Instances which are
+// not ready are always replaced, while ready instances are replaced only as long as the number of unavailable
+// instances does not exceed the maximum allowed by the rolling update.
+func instancesToReplace(mp *expv1.MachinePool, templateHash string) []*expv1.MachinePoolInstanceStatus {
+	desired := int32(1)
+	if mp.Spec.Replicas != nil {
+		desired = *mp.Spec.Replicas
+	}
+
+	var available int32
+	var notReady, ready []*expv1.MachinePoolInstanceStatus
+	for i := range mp.Status.Instances {
+		instance := &mp.Status.Instances[i]
+		if instance.Ready && !instance.Delete {
+			available++
+		}
+		if instance.TemplateHash == templateHash || instance.Delete {
+			continue
+		}
+		if instance.Ready {
+			ready = append(ready, instance)
+		} else {
+			notReady = append(notReady, instance)
+		}
+	}
+
+	budget := maxUnavailableInstances(mp, desired) - (desired - available)
+	for i := 0; i < len(ready) && int32(i) < budget; i++ {
+		notReady = append(notReady, ready[i])
+	}
+	return notReady
+}
+
+// maxUnavailableInstances returns the maximum number of unavailable instances during a managed rolling update,
+// which is at least one so the rolling update always progresses.
+func maxUnavailableInstances(mp *expv1.MachinePool, desired int32) int32 {
+	maxUnavailable := intstr.FromInt(1)
+	if mp.Spec.RolloutStrategy.ManagedRollingUpdate != nil && mp.Spec.RolloutStrategy.ManagedRollingUpdate.MaxUnavailable != nil {
+		maxUnavailable = *mp.Spec.RolloutStrategy.ManagedRollingUpdate.MaxUnavailable
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(desired), false)
+	if err != nil || value < 1 {
+		return 1
+	}
+	return int32(value)
+}
+
+// machinePoolTemplateHash returns the hash of the template the instances of the MachinePool are created from.
+func machinePoolTemplateHash(mp *expv1.MachinePool) string {
+	template := mp.Spec.Template.DeepCopy()
+	if template.Spec.Bootstrap.ConfigRef != nil {
+		// The data secret name is set by the controller from the bootstrap config, so it is not part of the user intent.
+		template.Spec.Bootstrap.DataSecretName = nil
+	}
+	return fmt.Sprintf("%d", mdutil.ComputeHash(template))
+}
+
+// preserveInstanceRolloutStatus copies the rollout status of the instances from the current to the updated instance statuses.
+func preserveInstanceRolloutStatus(current, updated []expv1.MachinePoolInstanceStatus) []expv1.MachinePoolInstanceStatus {
+	byProviderID := make(map[string]expv1.MachinePoolInstanceStatus, len(current))
+	for _, instance := range current {
+		byProviderID[instance.ProviderID] = instance
+	}
+	for i := range updated {
+		if instance, ok := byProviderID[updated[i].ProviderID]; ok {
+			updated[i].TemplateHash = instance.TemplateHash
+			updated[i].Delete = instance.Delete
+		}
+	}
+	return updated
+}
+
+// drainNode cordons the Node of an instance being replaced and evicts its pods, without waiting for them
+// to be deleted. It returns true once the Node has no pods left to evict.
+func drainNode(ctx context.Context, kubeClient kubernetes.Interface, nodeName string) (bool, error) {
+	log := ctrl.LoggerFrom(ctx, "node", nodeName)
+
+	node, err := kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// There is nothing to drain if the Node is already gone.
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "unable to get node %q", nodeName)
+	}
+
+	drainer := drainutil.NewHelper(log, kubeClient)
+	if err := kubedrain.RunCordonOrUncordon(ctx, drainer, node, true); err != nil {
+		return false, errors.Wrapf(err, "unable to cordon node %s", node.Name)
+	}
+
+	list, errs := drainer.GetPodsForDeletion(ctx, node.Name)
+	if errs != nil {
+		return false, errors.Wrapf(kerrors.NewAggregate(errs), "unable to list the pods to evict from node %s", node.Name)
+	}
+	pods := list.Pods()
+	if len(pods) == 0 {
+		return true, nil
+	}
+
+	policyGroupVersion, err := kubedrain.CheckEvictionSupport(kubeClient)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to drain node %s", node.Name)
+	}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			// The pod is already being deleted.
+			continue
+		}
+		if policyGroupVersion != "" {
+			err = drainer.EvictPod(ctx, pod, policyGroupVersion)
+		} else {
+			err = drainer.DeletePod(ctx, pod)
+		}
+		switch {
+		case err == nil, apierrors.IsNotFound(err):
+		case apierrors.IsTooManyRequests(err):
+			// The eviction is not allowed by a PodDisruptionBudget yet, it is retried at the next reconcile.
+			log.V(2).Info("Pod eviction is not allowed yet", "pod", fmt.Sprintf("%s/%s", pod.Namespace, pod.Name), "err", err.Error())
+		default:
+			return false, errors.Wrapf(err, "unable to evict pod %s/%s", pod.Namespace, pod.Name)
+		}
+	}
+	return false, nil
+}
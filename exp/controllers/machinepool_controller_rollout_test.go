@@ -0,0 +1,375 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// fakeInfraMachinePool simulates an infrastructure machine pool, which reports its instances and replaces
+// the instances marked for deletion with new ones; new instances become ready after being reported once.
+type fakeInfraMachinePool struct {
+	kubeClient kubernetes.Interface
+	instances  []*fakeInfraInstance
+	nextID     int
+}
+
+type fakeInfraInstance struct {
+	id    int
+	ready bool
+}
+
+func (f *fakeInfraMachinePool) createInstance(g *WithT, ready bool) {
+	f.nextID++
+	_, err := f.kubeClient.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", f.nextID)},
+		Spec:       corev1.NodeSpec{ProviderID: f.providerID(f.nextID)},
+	}, metav1.CreateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	f.instances = append(f.instances, &fakeInfraInstance{id: f.nextID, ready: ready})
+}
+
+func (f *fakeInfraMachinePool) providerID(id int) string {
+	return fmt.Sprintf("aws://us-east-1/id-%d", id)
+}
+
+// reportInstances returns the status of the instances, as observed by the MachinePool controller.
+func (f *fakeInfraMachinePool) reportInstances() []expv1.MachinePoolInstanceStatus {
+	instances := []expv1.MachinePoolInstanceStatus{}
+	for _, instance := range f.instances {
+		instances = append(instances, expv1.MachinePoolInstanceStatus{
+			ProviderID: f.providerID(instance.id),
+			NodeRef:    &corev1.ObjectReference{Kind: "Node", Name: fmt.Sprintf("node-%d", instance.id)},
+			Ready:      instance.ready,
+		})
+		instance.ready = true
+	}
+	return instances
+}
+
+// replaceDeletedInstances deletes the instances marked for deletion and creates their replacements.
+func (f *fakeInfraMachinePool) replaceDeletedInstances(g *WithT, statuses []expv1.MachinePoolInstanceStatus) {
+	for _, status := range statuses {
+		if !status.Delete {
+			continue
+		}
+		for i, instance := range f.instances {
+			if f.providerID(instance.id) == status.ProviderID {
+				f.instances = append(f.instances[:i], f.instances[i+1:]...)
+				f.createInstance(g, false)
+				break
+			}
+		}
+	}
+}
+
+func newRolloutMachinePool(replicas int32, maxUnavailable intstr.IntOrString) *expv1.MachinePool {
+	return &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machinepool-test",
+			Namespace: "default",
+		},
+		Spec: expv1.MachinePoolSpec{
+			Replicas: pointer.Int32Ptr(replicas),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Version: pointer.StringPtr("v1.19.1"),
+				},
+			},
+			RolloutStrategy: &expv1.MachinePoolRolloutStrategy{
+				Type: expv1.ManagedRollingUpdateMachinePoolRolloutStrategyType,
+				ManagedRollingUpdate: &expv1.MachinePoolManagedRollingUpdate{
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+		},
+	}
+}
+
+func TestMachinePoolManagedRollingUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		replicas       int32
+		maxUnavailable intstr.IntOrString
+	}{
+		{
+			name:           "replaces one instance at a time",
+			replicas:       3,
+			maxUnavailable: intstr.FromInt(1),
+		},
+		{
+			name:           "replaces instances in batches",
+			replicas:       5,
+			maxUnavailable: intstr.FromInt(2),
+		},
+		{
+			name:           "replaces at least one instance at a time with a percentage rounding down to zero",
+			replicas:       3,
+			maxUnavailable: intstr.FromString("10%"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kubeClient := fake.NewSimpleClientset()
+			kubeClientFn := func() (kubernetes.Interface, error) { return kubeClient, nil }
+			infra := &fakeInfraMachinePool{kubeClient: kubeClient}
+			for i := int32(0); i < tt.replicas; i++ {
+				infra.createInstance(g, true)
+			}
+
+			r := &MachinePoolReconciler{}
+			mp := newRolloutMachinePool(tt.replicas, tt.maxUnavailable)
+			maxUnavailable := maxUnavailableInstances(mp, tt.replicas)
+
+			// The instances created from the current template are up to date.
+			mp.Status.Instances = preserveInstanceRolloutStatus(mp.Status.Instances, infra.reportInstances())
+			_, err := r.rollout(ctx, mp, kubeClientFn)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(conditions.IsTrue(mp, expv1.InstancesUpToDateCondition)).To(BeTrue())
+			oldProviderIDs := map[string]bool{}
+			for _, instance := range mp.Status.Instances {
+				oldProviderIDs[instance.ProviderID] = true
+			}
+
+			// Changing the template triggers the rolling update.
+			mp.Spec.Template.Spec.Version = pointer.StringPtr("v1.20.0")
+			for i := 0; i < 20; i++ {
+				mp.Status.Instances = preserveInstanceRolloutStatus(mp.Status.Instances, infra.reportInstances())
+				_, err := r.rollout(ctx, mp, kubeClientFn)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				var unavailable int32
+				for _, instance := range mp.Status.Instances {
+					if !instance.Ready || instance.Delete {
+						unavailable++
+					}
+					if instance.Delete {
+						g.Expect(oldProviderIDs).To(HaveKey(instance.ProviderID))
+						node, err := kubeClient.CoreV1().Nodes().Get(ctx, instance.NodeRef.Name, metav1.GetOptions{})
+						g.Expect(err).ToNot(HaveOccurred())
+						g.Expect(node.Spec.Unschedulable).To(BeTrue())
+					}
+				}
+				g.Expect(unavailable).To(BeNumerically("<=", maxUnavailable))
+
+				if conditions.IsTrue(mp, expv1.InstancesUpToDateCondition) {
+					break
+				}
+				infra.replaceDeletedInstances(g, mp.Status.Instances)
+			}
+
+			g.Expect(conditions.IsTrue(mp, expv1.InstancesUpToDateCondition)).To(BeTrue())
+			g.Expect(mp.Status.Instances).To(HaveLen(int(tt.replicas)))
+			for _, instance := range mp.Status.Instances {
+				g.Expect(oldProviderIDs).ToNot(HaveKey(instance.ProviderID))
+				g.Expect(instance.TemplateHash).To(Equal(machinePoolTemplateHash(mp)))
+			}
+		})
+	}
+}
+
+func TestMachinePoolRolloutProgressCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeClient := fake.NewSimpleClientset()
+	kubeClientFn := func() (kubernetes.Interface, error) { return kubeClient, nil }
+	infra := &fakeInfraMachinePool{kubeClient: kubeClient}
+	for i := 0; i < 3; i++ {
+		infra.createInstance(g, true)
+	}
+
+	r := &MachinePoolReconciler{}
+	mp := newRolloutMachinePool(3, intstr.FromInt(1))
+	mp.Status.Instances = preserveInstanceRolloutStatus(mp.Status.Instances, infra.reportInstances())
+	_, err := r.rollout(ctx, mp, kubeClientFn)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	mp.Spec.Template.Spec.Version = pointer.StringPtr("v1.20.0")
+	result, err := r.rollout(ctx, mp, kubeClientFn)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(rolloutRequeueAfter))
+
+	condition := conditions.Get(mp, expv1.InstancesUpToDateCondition)
+	g.Expect(condition).ToNot(BeNil())
+	g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(expv1.RollingUpdateInProgressReason))
+	g.Expect(condition.Message).To(Equal("0 of 3 instances are up to date"))
+}
+
+func TestMachinePoolRolloutDrainsWithoutBlocking(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeClient := fake.NewSimpleClientset()
+	kubeClientFn := func() (kubernetes.Interface, error) { return kubeClient, nil }
+	infra := &fakeInfraMachinePool{kubeClient: kubeClient}
+	for i := 0; i < 2; i++ {
+		infra.createInstance(g, true)
+	}
+	controller := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "app",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs", Controller: &controller}},
+		},
+		Spec:   corev1.PodSpec{NodeName: "node-1"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	_, err := kubeClient.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r := &MachinePoolReconciler{}
+	mp := newRolloutMachinePool(2, intstr.FromInt(1))
+	mp.Status.Instances = preserveInstanceRolloutStatus(mp.Status.Instances, infra.reportInstances())
+	_, err = r.rollout(ctx, mp, kubeClientFn)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The pod is evicted, but the instance is only marked for deletion once its Node has no pods left.
+	mp.Spec.Template.Spec.Version = pointer.StringPtr("v1.20.0")
+	result, err := r.rollout(ctx, mp, kubeClientFn)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(rolloutRequeueAfter))
+	g.Expect(mp.Status.Instances[0].Delete).To(BeFalse())
+
+	pods, err := kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pods.Items).To(BeEmpty())
+	node, err := kubeClient.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(node.Spec.Unschedulable).To(BeTrue())
+
+	result, err = r.rollout(ctx, mp, kubeClientFn)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(rolloutRequeueAfter))
+	g.Expect(mp.Status.Instances[0].Delete).To(BeTrue())
+	g.Expect(mp.Status.Instances[1].Delete).To(BeFalse())
+}
+
+func TestMachinePoolInfrastructureManagedRollout(t *testing.T) {
+	g := NewWithT(t)
+
+	mp := newRolloutMachinePool(2, intstr.FromInt(1))
+	mp.Spec.RolloutStrategy = nil
+	mp.Status.Instances = []expv1.MachinePoolInstanceStatus{
+		{ProviderID: "aws://us-east-1/id-1", Ready: true},
+		{ProviderID: "aws://us-east-1/id-2", Ready: true, TemplateHash: "outdated"},
+	}
+	conditions.MarkTrue(mp, expv1.InstancesUpToDateCondition)
+
+	r := &MachinePoolReconciler{}
+	_, err := r.rollout(ctx, mp, func() (kubernetes.Interface, error) {
+		return nil, fmt.Errorf("the workload cluster must not be accessed")
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The template hashes are recorded, but the outdated instances are left to the infrastructure provider.
+	g.Expect(mp.Status.Instances[0].TemplateHash).To(Equal(machinePoolTemplateHash(mp)))
+	g.Expect(mp.Status.Instances[1].TemplateHash).To(Equal("outdated"))
+	g.Expect(mp.Status.Instances[1].Delete).To(BeFalse())
+	g.Expect(conditions.Has(mp, expv1.InstancesUpToDateCondition)).To(BeFalse())
+}
+
+func TestMachinePoolInstancesToReplace(t *testing.T) {
+	tests := []struct {
+		name      string
+		replicas  int32
+		instances []expv1.MachinePoolInstanceStatus
+		expected  []string
+	}{
+		{
+			name:     "replaces ready instances up to maxUnavailable",
+			replicas: 3,
+			instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: "old-1", Ready: true, TemplateHash: "old"},
+				{ProviderID: "old-2", Ready: true, TemplateHash: "old"},
+				{ProviderID: "old-3", Ready: true, TemplateHash: "old"},
+			},
+			expected: []string{"old-1"},
+		},
+		{
+			name:     "replaces outdated instances which are not ready without waiting",
+			replicas: 3,
+			instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: "old-1", Ready: false, TemplateHash: "old"},
+				{ProviderID: "old-2", Ready: true, TemplateHash: "old"},
+				{ProviderID: "old-3", Ready: true, TemplateHash: "old"},
+			},
+			expected: []string{"old-1"},
+		},
+		{
+			name:     "waits for the replacements to be ready",
+			replicas: 3,
+			instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: "old-1", Ready: true, TemplateHash: "old"},
+				{ProviderID: "old-2", Ready: true, TemplateHash: "old"},
+				{ProviderID: "new-1", Ready: false, TemplateHash: "new"},
+			},
+			expected: nil,
+		},
+		{
+			name:     "waits for the instances marked for deletion to be replaced",
+			replicas: 3,
+			instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: "old-1", Ready: true, TemplateHash: "old", Delete: true},
+				{ProviderID: "old-2", Ready: true, TemplateHash: "old"},
+				{ProviderID: "old-3", Ready: true, TemplateHash: "old"},
+			},
+			expected: nil,
+		},
+		{
+			name:     "does not replace up to date instances",
+			replicas: 2,
+			instances: []expv1.MachinePoolInstanceStatus{
+				{ProviderID: "new-1", Ready: true, TemplateHash: "new"},
+				{ProviderID: "new-2", Ready: true, TemplateHash: "new"},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mp := newRolloutMachinePool(tt.replicas, intstr.FromInt(1))
+			mp.Status.Instances = tt.instances
+
+			var providerIDs []string
+			for _, instance := range instancesToReplace(mp, "new") {
+				providerIDs = append(providerIDs, instance.ProviderID)
+			}
+			g.Expect(providerIDs).To(Equal(tt.expected))
+		})
+	}
+}
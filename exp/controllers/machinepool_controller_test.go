@@ -27,12 +27,14 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/test/helpers"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -298,6 +300,14 @@ func TestReconcileMachinePoolRequest(t *testing.T) {
 		},
 	}
 
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec:       corev1.NodeSpec{ProviderID: "test://id-1"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+
 	type expected struct {
 		result reconcile.Result
 		err    bool
@@ -335,6 +345,9 @@ func TestReconcileMachinePoolRequest(t *testing.T) {
 					NodeRefs: []corev1.ObjectReference{
 						{Name: "test"},
 					},
+					Instances: []expv1.MachinePoolInstanceStatus{
+						{ProviderID: "test://id-1", NodeRef: &corev1.ObjectReference{Name: "test"}, Ready: true},
+					},
 					ObservedGeneration: 1,
 				},
 			},
@@ -371,6 +384,9 @@ func TestReconcileMachinePoolRequest(t *testing.T) {
 					NodeRefs: []corev1.ObjectReference{
 						{Name: "test"},
 					},
+					Instances: []expv1.MachinePoolInstanceStatus{
+						{ProviderID: "test://id-1", NodeRef: &corev1.ObjectReference{Name: "test"}, Ready: true},
+					},
 					ObservedGeneration: 1,
 				},
 			},
@@ -424,10 +440,12 @@ func TestReconcileMachinePoolRequest(t *testing.T) {
 				&tc.machinePool,
 				&infraConfig,
 				bootstrapConfig,
+				node,
 			)
 
 			r := &MachinePoolReconciler{
-				Client: clientFake,
+				Client:  clientFake,
+				Tracker: remote.NewTestClusterCacheTracker(log.NullLogger{}, clientFake, scheme.Scheme, client.ObjectKeyFromObject(&testCluster)),
 			}
 
 			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: util.ObjectKey(&tc.machinePool)})
@@ -691,7 +709,7 @@ func TestMachinePoolConditions(t *testing.T) {
 				Spec: corev1.NodeSpec{
 					ProviderID: "aws://us-east-1/id-node-1",
 				},
-				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady}}},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
 			},
 			{
 				ObjectMeta: metav1.ObjectMeta{
@@ -700,7 +718,7 @@ func TestMachinePoolConditions(t *testing.T) {
 				Spec: corev1.NodeSpec{
 					ProviderID: "azure://westus2/id-node-4",
 				},
-				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady}}},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
 			},
 		},
 	}
@@ -723,6 +741,10 @@ func TestMachinePoolConditions(t *testing.T) {
 						{Name: "node-1"},
 						{Name: "azure-node-4"},
 					},
+					Instances: []expv1.MachinePoolInstanceStatus{
+						{ProviderID: "azure://westus2/id-node-4", NodeRef: &corev1.ObjectReference{Name: "azure-node-4"}, Ready: true},
+						{ProviderID: "aws://us-east-1/id-node-1", NodeRef: &corev1.ObjectReference{Name: "node-1"}, Ready: true},
+					},
 					Replicas:      2,
 					ReadyReplicas: 2,
 				}
@@ -841,7 +863,8 @@ func TestMachinePoolConditions(t *testing.T) {
 			)
 
 			r := &MachinePoolReconciler{
-				Client: clientFake,
+				Client:  clientFake,
+				Tracker: remote.NewTestClusterCacheTracker(log.NullLogger{}, clientFake, scheme.Scheme, client.ObjectKeyFromObject(testCluster)),
 			}
 
 			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: util.ObjectKey(machinePool)})
@@ -22,10 +22,12 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/test/helpers"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,8 +51,13 @@ var _ = BeforeSuite(func() {
 	By("bootstrapping test environment")
 	testEnv = helpers.NewTestEnvironment()
 
+	// Set up a ClusterCacheTracker to provide to controllers requiring a connection to a remote cluster
+	tracker, err := remote.NewClusterCacheTracker(log.Log, testEnv.Manager)
+	Expect(err).NotTo(HaveOccurred())
+
 	Expect((&MachinePoolReconciler{
 		Client:   testEnv,
+		Tracker:  tracker,
 		recorder: testEnv.GetEventRecorderFor("machinepool-controller"),
 	}).SetupWithManager(ctx, testEnv.Manager, controller.Options{MaxConcurrentReconciles: 1})).To(Succeed())
 
@@ -23,8 +23,10 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -40,6 +42,8 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	expcontrollers "sigs.k8s.io/cluster-api/exp/controllers"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/multinamespace"
+	"sigs.k8s.io/cluster-api/util/ratelimiter"
 	"sigs.k8s.io/cluster-api/version"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -59,6 +63,7 @@ var (
 	leaderElectionRenewDeadline   time.Duration
 	leaderElectionRetryPeriod     time.Duration
 	watchNamespace                string
+	watchNamespaces               []string
 	watchFilterValue              string
 	profilerAddress               string
 	clusterConcurrency            int
@@ -72,6 +77,10 @@ var (
 	webhookPort                   int
 	webhookCertDir                string
 	healthAddr                    string
+	nodeLabelPrefix               string
+	nodeHealthyConditions         []string
+	workloadClusterQPS            float32
+	workloadClusterBurst          int
 )
 
 func init() {
@@ -105,6 +114,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&watchNamespace, "namespace", "",
 		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.")
 
+	fs.StringSliceVar(&watchNamespaces, "watch-namespaces", nil,
+		"Comma-separated list of namespaces that the controller watches to reconcile cluster-api objects, in addition to the one set with --namespace. If both are unspecified, the controller watches for cluster-api objects across all namespaces.")
+
 	fs.StringVar(&watchFilterValue, "watch-filter", "",
 		fmt.Sprintf("Label value that the controller watches to reconcile cluster-api objects. Label key is always %s. If unspecified, the controller watches for all cluster-api objects.", clusterv1.WatchLabel))
 
@@ -132,6 +144,12 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&machineHealthCheckConcurrency, "machinehealthcheck-concurrency", 10,
 		"Number of machine health checks to process simultaneously")
 
+	fs.Float32Var(&workloadClusterQPS, "workload-cluster-qps", 20,
+		"Maximum queries per second from the clients created for the workload clusters")
+
+	fs.IntVar(&workloadClusterBurst, "workload-cluster-burst", 30,
+		"Maximum burst for throttling the clients created for the workload clusters")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -144,6 +162,12 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.StringVar(&nodeLabelPrefix, "node-label-prefix", clusterv1.NodeLabelPrefix,
+		"Machine labels with this prefix are synced to the Machine's Node. Set to an empty string to disable syncing.")
+
+	fs.StringSliceVar(&nodeHealthyConditions, "node-healthy-conditions", nodeConditionTypesToStrings(controllers.DefaultNodeHealthyConditions),
+		"Comma-separated list of the Node conditions summarized by the NodeHealthy condition of the Machines, among Ready, MemoryPressure, DiskPressure, PIDPressure and NetworkUnavailable. The Ready condition is healthy when True, any other condition when False. Custom Node conditions, e.g. the ones set by node-problem-detector, are not supported.")
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -156,6 +180,15 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	if err := validateConcurrencyFlags(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := validateNodeHealthyConditionsFlag(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+
 	if profilerAddress != "" {
 		klog.Infof("Profiler listening for requests at %s", profilerAddress)
 		go func() {
@@ -163,7 +196,7 @@ func main() {
 		}()
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	options := ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsBindAddr,
 		LeaderElection:     enableLeaderElection,
@@ -171,7 +204,6 @@ func main() {
 		LeaseDuration:      &leaderElectionLeaseDuration,
 		RenewDeadline:      &leaderElectionRenewDeadline,
 		RetryPeriod:        &leaderElectionRetryPeriod,
-		Namespace:          watchNamespace,
 		SyncPeriod:         &syncPeriod,
 		ClientDisableCacheFor: []client.Object{
 			&corev1.ConfigMap{},
@@ -180,7 +212,10 @@ func main() {
 		Port:                   webhookPort,
 		CertDir:                webhookCertDir,
 		HealthProbeBindAddress: healthAddr,
-	})
+	}
+	multinamespace.SetManagerOptions(&options, append([]string{watchNamespace}, watchNamespaces...)...)
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -216,9 +251,13 @@ func setupChecks(mgr ctrl.Manager) {
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	// Set up a ClusterCacheTracker and ClusterCacheReconciler to provide to controllers
 	// requiring a connection to a remote cluster
-	tracker, err := remote.NewClusterCacheTracker(
+	tracker, err := remote.NewClusterCacheTrackerWithOptions(
 		ctrl.Log.WithName("remote").WithName("ClusterCacheTracker"),
 		mgr,
+		remote.ClusterCacheTrackerOptions{
+			ClientQPS:   workloadClusterQPS,
+			ClientBurst: workloadClusterBurst,
+		},
 	)
 	if err != nil {
 		setupLog.Error(err, "unable to create cluster cache tracker")
@@ -236,15 +275,18 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if err := (&controllers.ClusterReconciler{
 		Client:           mgr.GetClient(),
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, clusterRateLimited(concurrency(clusterConcurrency), "cluster", ratelimiter.ClusterKeyFromRequest)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
 	if err := (&controllers.MachineReconciler{
-		Client:           mgr.GetClient(),
-		Tracker:          tracker,
-		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
+		Client:                mgr.GetClient(),
+		Tracker:               tracker,
+		WatchFilterValue:      watchFilterValue,
+		NodeLabelPrefix:       nodeLabelPrefix,
+		NodeHealthyConditions: stringsToNodeConditionTypes(nodeHealthyConditions),
+	}).SetupWithManager(ctx, mgr, clusterRateLimited(concurrency(machineConcurrency), "machine",
+		ratelimiter.ClusterKeyFromLabel(mgr.GetClient(), &clusterv1.Machine{}))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Machine")
 		os.Exit(1)
 	}
@@ -252,14 +294,16 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		Client:           mgr.GetClient(),
 		Tracker:          tracker,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineSetConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, clusterRateLimited(concurrency(machineSetConcurrency), "machineset",
+		ratelimiter.ClusterKeyFromLabel(mgr.GetClient(), &clusterv1.MachineSet{}))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineSet")
 		os.Exit(1)
 	}
 	if err := (&controllers.MachineDeploymentReconciler{
 		Client:           mgr.GetClient(),
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineDeploymentConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, clusterRateLimited(concurrency(machineDeploymentConcurrency), "machinedeployment",
+		ratelimiter.ClusterKeyFromLabel(mgr.GetClient(), &clusterv1.MachineDeployment{}))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineDeployment")
 		os.Exit(1)
 	}
@@ -267,8 +311,10 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if feature.Gates.Enabled(feature.MachinePool) {
 		if err := (&expcontrollers.MachinePoolReconciler{
 			Client:           mgr.GetClient(),
+			Tracker:          tracker,
 			WatchFilterValue: watchFilterValue,
-		}).SetupWithManager(ctx, mgr, concurrency(machinePoolConcurrency)); err != nil {
+		}).SetupWithManager(ctx, mgr, clusterRateLimited(concurrency(machinePoolConcurrency), "machinepool",
+			ratelimiter.ClusterKeyFromLabel(mgr.GetClient(), &expv1.MachinePool{}))); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "MachinePool")
 			os.Exit(1)
 		}
@@ -296,7 +342,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		Client:           mgr.GetClient(),
 		Tracker:          tracker,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineHealthCheckConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, clusterRateLimited(concurrency(machineHealthCheckConcurrency), "machinehealthcheck",
+		ratelimiter.ClusterKeyFromLabel(mgr.GetClient(), &clusterv1.MachineHealthCheck{}))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineHealthCheck")
 		os.Exit(1)
 	}
@@ -343,6 +390,79 @@ func setupWebhooks(mgr ctrl.Manager) {
 	}
 }
 
+// validateConcurrencyFlags returns an error if the number of objects one of the controllers processes
+// simultaneously is not positive.
+func validateConcurrencyFlags() error {
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{name: "cluster-concurrency", value: clusterConcurrency},
+		{name: "machine-concurrency", value: machineConcurrency},
+		{name: "machineset-concurrency", value: machineSetConcurrency},
+		{name: "machinedeployment-concurrency", value: machineDeploymentConcurrency},
+		{name: "machinepool-concurrency", value: machinePoolConcurrency},
+		{name: "clusterresourceset-concurrency", value: clusterResourceSetConcurrency},
+		{name: "machinehealthcheck-concurrency", value: machineHealthCheckConcurrency},
+	} {
+		if f.value < 1 {
+			return errors.Errorf("--%s must be a positive number, got %d", f.name, f.value)
+		}
+	}
+	return nil
+}
+
+// knownNodeConditionTypes are the Node conditions set by the kubelet or the node controller.
+var knownNodeConditionTypes = []corev1.NodeConditionType{
+	corev1.NodeReady,
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+}
+
+// validateNodeHealthyConditionsFlag returns an error if one of the Node conditions summarized by the NodeHealthy
+// condition of the Machines is not a known Node condition.
+func validateNodeHealthyConditionsFlag() error {
+	for _, s := range nodeHealthyConditions {
+		known := false
+		for _, t := range knownNodeConditionTypes {
+			if s == string(t) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errors.Errorf("--node-healthy-conditions must only contain known Node conditions (%s), got %q",
+				strings.Join(nodeConditionTypesToStrings(knownNodeConditionTypes), ", "), s)
+		}
+	}
+	return nil
+}
+
+func nodeConditionTypesToStrings(conditionTypes []corev1.NodeConditionType) []string {
+	s := make([]string, 0, len(conditionTypes))
+	for _, t := range conditionTypes {
+		s = append(s, string(t))
+	}
+	return s
+}
+
+func stringsToNodeConditionTypes(s []string) []corev1.NodeConditionType {
+	conditionTypes := make([]corev1.NodeConditionType, 0, len(s))
+	for _, t := range s {
+		conditionTypes = append(conditionTypes, corev1.NodeConditionType(t))
+	}
+	return conditionTypes
+}
+
 func concurrency(c int) controller.Options {
 	return controller.Options{MaxConcurrentReconciles: c}
 }
+
+// clusterRateLimited sets a rate limiter backing off the retries of the failing reconciles of the named controller
+// per cluster, so a single unhealthy cluster does not starve the reconciles of the other clusters.
+func clusterRateLimited(options controller.Options, controllerName string, clusterKey ratelimiter.ClusterKeyFunc) controller.Options {
+	options.RateLimiter = ratelimiter.New(controllerName, clusterKey)
+	return options
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+)
+
+func TestConcurrencyFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[*int]int
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			args: []string{},
+			want: map[*int]int{
+				&clusterConcurrency:           10,
+				&machineConcurrency:           10,
+				&machineSetConcurrency:        10,
+				&machineDeploymentConcurrency: 10,
+			},
+		},
+		{
+			name: "per controller concurrency",
+			args: []string{
+				"--cluster-concurrency=2",
+				"--machine-concurrency=50",
+				"--machineset-concurrency=20",
+				"--machinedeployment-concurrency=5",
+			},
+			want: map[*int]int{
+				&clusterConcurrency:           2,
+				&machineConcurrency:           50,
+				&machineSetConcurrency:        20,
+				&machineDeploymentConcurrency: 5,
+			},
+		},
+		{
+			name:    "zero concurrency",
+			args:    []string{"--machine-concurrency=0"},
+			wantErr: true,
+		},
+		{
+			name:    "negative concurrency",
+			args:    []string{"--machinehealthcheck-concurrency=-1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			InitFlags(fs)
+			g.Expect(fs.Parse(tt.args)).To(Succeed())
+
+			err := validateConcurrencyFlags()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// The value of each flag is propagated to the options the controller is built with.
+			for flagValue, want := range tt.want {
+				g.Expect(concurrency(*flagValue).MaxConcurrentReconciles).To(Equal(want))
+			}
+		})
+	}
+}
+
+func TestValidateConcurrencyFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "positive concurrency for all the controllers",
+			args: []string{
+				"--cluster-concurrency=1",
+				"--machine-concurrency=1",
+				"--machineset-concurrency=1",
+				"--machinedeployment-concurrency=1",
+				"--machinepool-concurrency=1",
+				"--clusterresourceset-concurrency=1",
+				"--machinehealthcheck-concurrency=1",
+			},
+		},
+		{
+			name:    "zero cluster concurrency",
+			args:    []string{"--cluster-concurrency=0"},
+			wantErr: "--cluster-concurrency must be a positive number, got 0",
+		},
+		{
+			name:    "zero machine concurrency",
+			args:    []string{"--machine-concurrency=0"},
+			wantErr: "--machine-concurrency must be a positive number, got 0",
+		},
+		{
+			name:    "negative machineset concurrency",
+			args:    []string{"--machineset-concurrency=-1"},
+			wantErr: "--machineset-concurrency must be a positive number, got -1",
+		},
+		{
+			name:    "negative machinedeployment concurrency",
+			args:    []string{"--machinedeployment-concurrency=-5"},
+			wantErr: "--machinedeployment-concurrency must be a positive number, got -5",
+		},
+		{
+			name:    "zero machinepool concurrency",
+			args:    []string{"--machinepool-concurrency=0"},
+			wantErr: "--machinepool-concurrency must be a positive number, got 0",
+		},
+		{
+			name:    "zero clusterresourceset concurrency",
+			args:    []string{"--clusterresourceset-concurrency=0"},
+			wantErr: "--clusterresourceset-concurrency must be a positive number, got 0",
+		},
+		{
+			name:    "negative machinehealthcheck concurrency",
+			args:    []string{"--machinehealthcheck-concurrency=-1"},
+			wantErr: "--machinehealthcheck-concurrency must be a positive number, got -1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			InitFlags(fs)
+			g.Expect(fs.Parse(tt.args)).To(Succeed())
+
+			err := validateConcurrencyFlags()
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestNodeHealthyConditionsFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			args: []string{},
+		},
+		{
+			name: "known conditions",
+			args: []string{"--node-healthy-conditions=Ready,NetworkUnavailable"},
+		},
+		{
+			name:    "unknown condition",
+			args:    []string{"--node-healthy-conditions=Ready,MemoryPresure"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			InitFlags(fs)
+			g.Expect(fs.Parse(tt.args)).To(Succeed())
+
+			err := validateNodeHealthyConditionsFlag()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
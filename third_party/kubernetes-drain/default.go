@@ -19,6 +19,7 @@ package drain
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -45,9 +46,28 @@ func RunNodeDrain(ctx context.Context, drainer *Helper, nodeName string) error {
 		// Maybe warn about non-deleted pods here
 		return err
 	}
+
+	if waiting := list.WaitingPods(); len(waiting) > 0 {
+		names := make([]string, 0, len(waiting))
+		for _, pod := range waiting {
+			names = append(names, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+		return &WaitingForPodsError{Pods: names}
+	}
 	return nil
 }
 
+// WaitingForPodsError is returned by RunNodeDrain when the other pods have been drained,
+// but the drain is still waiting for pods to complete; the drain is in progress, not failed.
+type WaitingForPodsError struct {
+	// Pods are the namespace/name of the pods the drain is waiting for.
+	Pods []string
+}
+
+func (e *WaitingForPodsError) Error() string {
+	return fmt.Sprintf("waiting for Pods to complete: %s", strings.Join(e.Pods, ", "))
+}
+
 // RunCordonOrUncordon demonstrates the canonical way to cordon or uncordon a Node
 func RunCordonOrUncordon(ctx context.Context, drainer *Helper, node *corev1.Node, desired bool) error {
 	// TODO(justinsb): Ensure we have adequate e2e coverage of this function in library consumers
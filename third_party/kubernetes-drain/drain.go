@@ -71,6 +71,9 @@ type Helper struct {
 
 	// OnPodDeletedOrEvicted is called when a pod is evicted/deleted; for printing progress output
 	OnPodDeletedOrEvicted func(pod *corev1.Pod, usingEviction bool)
+
+	// DrainBehavior, if set, returns how a pod is handled by the drain, overriding the default filters
+	DrainBehavior func(pod corev1.Pod) PodDrainBehavior
 }
 
 type waitForDeleteParams struct {
@@ -171,7 +174,7 @@ func (d *Helper) GetPodsForDeletion(ctx context.Context, nodeName string) (*podD
 
 	for _, pod := range podList.Items {
 		var status podDeleteStatus
-		for _, filter := range d.makeFilters() {
+		for _, filter := range d.makeFilters(pod) {
 			status = filter(pod)
 			if !status.delete {
 				// short-circuit as soon as pod is filtered out
@@ -180,7 +183,7 @@ func (d *Helper) GetPodsForDeletion(ctx context.Context, nodeName string) (*podD
 				break
 			}
 		}
-		if status.delete {
+		if status.delete || status.reason == podDeleteStatusTypeWait {
 			pods = append(pods, podDelete{
 				pod:    pod,
 				status: status,
@@ -56,6 +56,17 @@ func (l *podDeleteList) Pods() []corev1.Pod {
 	return pods
 }
 
+// WaitingPods returns the pods the drain must wait for to complete, without deleting them.
+func (l *podDeleteList) WaitingPods() []corev1.Pod {
+	pods := []corev1.Pod{}
+	for _, i := range l.items {
+		if i.status.reason == podDeleteStatusTypeWait {
+			pods = append(pods, i.pod)
+		}
+	}
+	return pods
+}
+
 func (l *podDeleteList) Warnings() string {
 	ps := make(map[string][]string)
 	for _, i := range l.items {
@@ -103,6 +114,25 @@ const (
 	podDeleteStatusTypeSkip    = "Skip"
 	podDeleteStatusTypeWarning = "Warning"
 	podDeleteStatusTypeError   = "Error"
+	podDeleteStatusTypeWait    = "Wait"
+)
+
+// PodDrainBehavior defines how a pod is handled by the drain, overriding the default filters.
+type PodDrainBehavior string
+
+const (
+	// PodDrainBehaviorDefault applies the default filters to the pod.
+	PodDrainBehaviorDefault PodDrainBehavior = ""
+
+	// PodDrainBehaviorDrain deletes the pod regardless of the default filters,
+	// except for mirror pods and pods skipped because of SkipWaitForDeleteTimeoutSeconds.
+	PodDrainBehaviorDrain PodDrainBehavior = "Drain"
+
+	// PodDrainBehaviorSkip never deletes the pod.
+	PodDrainBehaviorSkip PodDrainBehavior = "Skip"
+
+	// PodDrainBehaviorWaitCompleted does not delete the pod, but the drain waits for it to complete.
+	PodDrainBehaviorWaitCompleted PodDrainBehavior = "WaitCompleted"
 )
 
 func makePodDeleteStatusOkay() podDeleteStatus {
@@ -135,9 +165,39 @@ func makePodDeleteStatusWithError(message string) podDeleteStatus {
 	}
 }
 
+func makePodDeleteStatusWait() podDeleteStatus {
+	return podDeleteStatus{
+		delete: false,
+		reason: podDeleteStatusTypeWait,
+	}
+}
+
 // The filters are applied in a specific order, only the last filter's
 // message will be retained if there are any warnings.
-func (d *Helper) makeFilters() []podFilter {
+// The DrainBehavior of the pod, if any, replaces the default filters.
+func (d *Helper) makeFilters(pod corev1.Pod) []podFilter {
+	behavior := PodDrainBehaviorDefault
+	if d.DrainBehavior != nil {
+		behavior = d.DrainBehavior(pod)
+	}
+
+	switch behavior {
+	case PodDrainBehaviorDrain:
+		return []podFilter{
+			d.skipDeletedFilter,
+			d.mirrorPodFilter,
+		}
+	case PodDrainBehaviorSkip:
+		return []podFilter{
+			skipFilter,
+		}
+	case PodDrainBehaviorWaitCompleted:
+		return []podFilter{
+			d.skipDeletedFilter,
+			waitCompletedFilter,
+		}
+	}
+
 	return []podFilter{
 		d.skipDeletedFilter,
 		d.daemonSetFilter,
@@ -147,6 +207,17 @@ func (d *Helper) makeFilters() []podFilter {
 	}
 }
 
+func skipFilter(pod corev1.Pod) podDeleteStatus {
+	return makePodDeleteStatusSkip()
+}
+
+func waitCompletedFilter(pod corev1.Pod) podDeleteStatus {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return makePodDeleteStatusSkip()
+	}
+	return makePodDeleteStatusWait()
+}
+
 func hasLocalStorage(pod corev1.Pod) bool {
 	for _, volume := range pod.Spec.Volumes {
 		if volume.EmptyDir != nil {
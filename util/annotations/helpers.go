@@ -63,6 +63,9 @@ func AddAnnotations(o metav1.Object, desired map[string]string) bool {
 			hasChanged = true
 		}
 	}
+	if hasChanged {
+		o.SetAnnotations(annotations)
+	}
 	return hasChanged
 }
 
@@ -110,6 +110,21 @@ func TestAddAnnotations(t *testing.T) {
 			},
 			changed: true,
 		},
+		{
+			name: "should return true if annotations are added to an object without annotations",
+			obj: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec:       corev1.NodeSpec{},
+				Status:     corev1.NodeStatus{},
+			},
+			input: map[string]string{
+				"foo": "bar",
+			},
+			expected: map[string]string{
+				"foo": "bar",
+			},
+			changed: true,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 )
 
 func falseFilter(_ *clusterv1.Machine) bool {
@@ -163,6 +164,48 @@ func TestShouldRolloutAfter(t *testing.T) {
 	})
 }
 
+func TestShouldRolloutBefore(t *testing.T) {
+	reconciliationTime := metav1.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	rolloutBefore := &controlplanev1.RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(7)}
+	t.Run("if the machine is nil it returns false", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(collections.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(nil)).To(BeFalse())
+	})
+	t.Run("if the rolloutBefore is nil it returns false", func(t *testing.T) {
+		g := NewWithT(t)
+		m := &clusterv1.Machine{}
+		m.Status.CertificatesExpiryDate = &reconciliationTime
+		g.Expect(collections.ShouldRolloutBefore(&reconciliationTime, nil)(m)).To(BeFalse())
+		g.Expect(collections.ShouldRolloutBefore(&reconciliationTime, &controlplanev1.RolloutBefore{})(m)).To(BeFalse())
+	})
+	t.Run("if the machine certificates expiry date is not known it returns false", func(t *testing.T) {
+		g := NewWithT(t)
+		m := &clusterv1.Machine{}
+		g.Expect(collections.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(m)).To(BeFalse())
+	})
+	t.Run("if the certificates expire after the specified days, return false", func(t *testing.T) {
+		g := NewWithT(t)
+		m := &clusterv1.Machine{}
+		expiry := metav1.NewTime(reconciliationTime.Add(8 * 24 * time.Hour))
+		m.Status.CertificatesExpiryDate = &expiry
+		g.Expect(collections.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(m)).To(BeFalse())
+	})
+	t.Run("if the certificates expire within the specified days, return true", func(t *testing.T) {
+		g := NewWithT(t)
+		m := &clusterv1.Machine{}
+		expiry := metav1.NewTime(reconciliationTime.Add(6 * 24 * time.Hour))
+		m.Status.CertificatesExpiryDate = &expiry
+		g.Expect(collections.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(m)).To(BeTrue())
+	})
+	t.Run("if the certificates are already expired, return true", func(t *testing.T) {
+		g := NewWithT(t)
+		m := &clusterv1.Machine{}
+		expiry := metav1.NewTime(reconciliationTime.Add(-1 * time.Hour))
+		m.Status.CertificatesExpiryDate = &expiry
+		g.Expect(collections.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(m)).To(BeTrue())
+	})
+}
+
 func TestHashAnnotationKey(t *testing.T) {
 	t.Run("machine with specified annotation returns true", func(t *testing.T) {
 		g := NewWithT(t)
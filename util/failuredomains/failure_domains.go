@@ -27,8 +27,9 @@ import (
 )
 
 type failureDomainAggregation struct {
-	id    string
-	count int
+	id     string
+	count  int
+	weight int
 }
 type failureDomainAggregations []failureDomainAggregation
 
@@ -37,10 +38,20 @@ func (f failureDomainAggregations) Len() int {
 	return len(f)
 }
 
-// Less reports whether the element with
-// index i should sort before the element with index j.
+// Less reports whether the element with index i should sort before the element with index j, i.e. whether
+// a machine placed in the failure domain i is closer to its share of the machines, according to the weights
+// of the failure domains, than a machine placed in the failure domain j. Failure domains with fewer machines,
+// and then the id, are the tiebreakers, so failure domains with the same weight are spread evenly.
 func (f failureDomainAggregations) Less(i, j int) bool {
-	return f[i].count < f[j].count
+	// Compare (count+1)/weight without dividing.
+	left, right := (f[i].count+1)*f[j].weight, (f[j].count+1)*f[i].weight
+	if left != right {
+		return left < right
+	}
+	if f[i].count != f[j].count {
+		return f[i].count < f[j].count
+	}
+	return f[i].id < f[j].id
 }
 
 // Swap swaps the elements with indexes i and j.
@@ -48,7 +59,27 @@ func (f failureDomainAggregations) Swap(i, j int) {
 	f[i], f[j] = f[j], f[i]
 }
 
-// PickMost returns a failure domain that is in machines and has most of the group of machines on.
+// overweight sorts failure domains by decreasing number of machines above their share of the machines, according
+// to the weights of the failure domains, i.e. the failure domains to remove a machine from first.
+type overweight struct{ failureDomainAggregations }
+
+// Less reports whether the failure domain i has more machines above its share than the failure domain j.
+// Failure domains with more machines, and then the id, are the tiebreakers.
+func (f overweight) Less(i, j int) bool {
+	a := f.failureDomainAggregations
+	// Compare count/weight without dividing.
+	left, right := a[i].count*a[j].weight, a[j].count*a[i].weight
+	if left != right {
+		return left > right
+	}
+	if a[i].count != a[j].count {
+		return a[i].count > a[j].count
+	}
+	return a[i].id < a[j].id
+}
+
+// PickMost returns a failure domain that is in machines and has most of the group of machines on, relative to the
+// weights of the failure domains.
 func PickMost(failureDomains clusterv1.FailureDomains, groupMachines, machines collections.Machines) *string {
 	// orderDescending sorts failure domains according to all machines belonging to the group.
 	fds := orderDescending(failureDomains, groupMachines)
@@ -65,17 +96,17 @@ func PickMost(failureDomains clusterv1.FailureDomains, groupMachines, machines c
 	return nil
 }
 
-// orderDescending returns the sorted failure domains in decreasing order.
+// orderDescending returns the failure domains sorted by decreasing number of machines, relative to their weights.
 func orderDescending(failureDomains clusterv1.FailureDomains, machines collections.Machines) failureDomainAggregations {
 	aggregations := pick(failureDomains, machines)
 	if len(aggregations) == 0 {
 		return nil
 	}
-	sort.Sort(sort.Reverse(aggregations))
+	sort.Sort(overweight{aggregations})
 	return aggregations
 }
 
-// PickFewest returns the failure domain with the fewest number of machines.
+// PickFewest returns the failure domain with the fewest number of machines, relative to the weights of the failure domains.
 func PickFewest(failureDomains clusterv1.FailureDomains, machines collections.Machines) *string {
 	aggregations := pick(failureDomains, machines)
 	if len(aggregations) == 0 {
@@ -112,9 +143,13 @@ func pick(failureDomains clusterv1.FailureDomains, machines collections.Machines
 
 	aggregations := make(failureDomainAggregations, 0)
 
-	// Gather up tuples of failure domains ids and counts
+	// Gather up tuples of failure domains ids, counts and weights
 	for fd, count := range counters {
-		aggregations = append(aggregations, failureDomainAggregation{id: fd, count: count})
+		weight := 1
+		if w := failureDomains[fd].Weight; w != nil && *w > 0 {
+			weight = int(*w)
+		}
+		aggregations = append(aggregations, failureDomainAggregation{id: fd, count: count, weight: weight})
 	}
 
 	return aggregations
@@ -17,11 +17,13 @@ limitations under the License.
 package failuredomains
 
 import (
+	"fmt"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"testing"
 
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -83,6 +85,32 @@ func TestNewFailureDomainPicker(t *testing.T) {
 			fds:      fds,
 			expected: []*string{a, b},
 		},
+		{
+			name: "no machines should return the failure domain with the highest weight",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(1)},
+				*b: clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(2)},
+			},
+			expected: []*string{b},
+		},
+		{
+			name: "one machine in a failure domain with a higher weight",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(3)},
+				*b: clusterv1.FailureDomainSpec{},
+			},
+			machines: collections.FromMachines(machinea.DeepCopy()),
+			expected: []*string{a},
+		},
+		{
+			name: "one machine in a failure domain with equal weights",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(2)},
+				*b: clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(2)},
+			},
+			machines: collections.FromMachines(machinea.DeepCopy()),
+			expected: []*string{b},
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -163,6 +191,32 @@ func TestNewFailureDomainPickMost(t *testing.T) {
 			name:     "nil failure domains with no machines",
 			expected: nil,
 		},
+		{
+			name: "machines in failure domains with different weights",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{ControlPlane: true, Weight: pointer.Int32Ptr(3)},
+				*b: clusterv1.FailureDomainSpec{ControlPlane: true},
+			},
+			machines: collections.FromMachines(
+				&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "a1"}, Spec: clusterv1.MachineSpec{FailureDomain: a}},
+				&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "a2"}, Spec: clusterv1.MachineSpec{FailureDomain: a}},
+				&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "b1"}, Spec: clusterv1.MachineSpec{FailureDomain: b}},
+			),
+			expected: []*string{b},
+		},
+		{
+			name: "machines in failure domains with equal weights",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{ControlPlane: true, Weight: pointer.Int32Ptr(2)},
+				*b: clusterv1.FailureDomainSpec{ControlPlane: true, Weight: pointer.Int32Ptr(2)},
+			},
+			machines: collections.FromMachines(
+				&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "a1"}, Spec: clusterv1.MachineSpec{FailureDomain: a}},
+				&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "a2"}, Spec: clusterv1.MachineSpec{FailureDomain: a}},
+				&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "b1"}, Spec: clusterv1.MachineSpec{FailureDomain: b}},
+			),
+			expected: []*string{a},
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -177,3 +231,62 @@ func TestNewFailureDomainPickMost(t *testing.T) {
 		})
 	}
 }
+
+func TestPickFewestWeightedSpread(t *testing.T) {
+	tests := []struct {
+		name     string
+		fds      clusterv1.FailureDomains
+		machines int
+		expected map[string]int
+	}{
+		{
+			name: "machines are spread according to the weights",
+			fds: clusterv1.FailureDomains{
+				"us-west-1a": clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(3)},
+				"us-west-1b": clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(1)},
+			},
+			machines: 8,
+			expected: map[string]int{"us-west-1a": 6, "us-west-1b": 2},
+		},
+		{
+			name: "machines are spread evenly with equal weights",
+			fds: clusterv1.FailureDomains{
+				"us-west-1a": clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(2)},
+				"us-west-1b": clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(2)},
+				"us-west-1c": clusterv1.FailureDomainSpec{Weight: pointer.Int32Ptr(2)},
+			},
+			machines: 6,
+			expected: map[string]int{"us-west-1a": 2, "us-west-1b": 2, "us-west-1c": 2},
+		},
+		{
+			name: "machines are spread evenly without weights",
+			fds: clusterv1.FailureDomains{
+				"us-west-1a": clusterv1.FailureDomainSpec{},
+				"us-west-1b": clusterv1.FailureDomainSpec{},
+			},
+			machines: 5,
+			expected: map[string]int{"us-west-1a": 3, "us-west-1b": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machines := collections.New()
+			for i := 0; i < tt.machines; i++ {
+				fd := PickFewest(tt.fds, machines)
+				g.Expect(fd).ToNot(BeNil())
+				machines.Insert(&clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("machine-%d", i)},
+					Spec:       clusterv1.MachineSpec{FailureDomain: fd},
+				})
+			}
+
+			got := map[string]int{}
+			for _, m := range machines {
+				got[*m.Spec.FailureDomain]++
+			}
+			g.Expect(got).To(Equal(tt.expected))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multinamespace implements utilities to scope the manager of a Cluster API controller to a set of namespaces.
+package multinamespace
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// SetManagerOptions scopes the cache of the manager to the given namespaces. Empty and duplicated
+// namespaces are ignored; when no namespace is given, the manager watches all namespaces.
+func SetManagerOptions(options *ctrl.Options, namespaces ...string) {
+	watched := sets.NewString()
+	for _, ns := range namespaces {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			watched.Insert(ns)
+		}
+	}
+
+	switch watched.Len() {
+	case 0:
+		options.Namespace = ""
+	case 1:
+		options.Namespace = watched.List()[0]
+	default:
+		options.Namespace = ""
+		options.NewCache = NewCacheFunc(watched.List())
+	}
+}
+
+// NewCacheFunc returns a cache.NewCacheFunc creating a cache which watches namespaced objects only in the given
+// namespaces, while cluster-scoped objects, e.g. CustomResourceDefinitions, are watched cluster-wide.
+func NewCacheFunc(namespaces []string) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		if opts.Scheme == nil {
+			opts.Scheme = runtime.NewScheme()
+		}
+		if opts.Mapper == nil {
+			mapper, err := apiutil.NewDynamicRESTMapper(config)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create the REST mapper")
+			}
+			opts.Mapper = mapper
+		}
+
+		namespacedCache, err := cache.MultiNamespacedCacheBuilder(namespaces)(config, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Namespace = ""
+		clusterCache, err := cache.New(config, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &multiNamespaceCache{
+			namespacedCache: namespacedCache,
+			clusterCache:    clusterCache,
+			scheme:          opts.Scheme,
+			mapper:          opts.Mapper,
+		}, nil
+	}
+}
+
+// multiNamespaceCache routes namespaced objects to a cache scoped to the watched namespaces, and
+// cluster-scoped objects to a cluster-wide cache; informers of the cluster-wide cache are only created
+// for cluster-scoped objects.
+type multiNamespaceCache struct {
+	namespacedCache cache.Cache
+	clusterCache    cache.Cache
+	scheme          *runtime.Scheme
+	mapper          meta.RESTMapper
+}
+
+var _ cache.Cache = &multiNamespaceCache{}
+
+func (c *multiNamespaceCache) cacheForGVK(gvk schema.GroupVersionKind) cache.Cache {
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err == nil && mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return c.clusterCache
+	}
+	return c.namespacedCache
+}
+
+func (c *multiNamespaceCache) cacheForObject(obj runtime.Object) cache.Cache {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return c.namespacedCache
+	}
+	return c.cacheForGVK(gvk)
+}
+
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return c.cacheForObject(obj).Get(ctx, key, obj)
+}
+
+func (c *multiNamespaceCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return c.cacheForObject(list).List(ctx, list, opts...)
+}
+
+func (c *multiNamespaceCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	return c.cacheForObject(obj).GetInformer(ctx, obj)
+}
+
+func (c *multiNamespaceCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	return c.cacheForGVK(gvk).GetInformerForKind(ctx, gvk)
+}
+
+func (c *multiNamespaceCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	return c.cacheForObject(obj).IndexField(ctx, obj, field, extractValue)
+}
+
+func (c *multiNamespaceCache) Start(ctx context.Context) error {
+	errCh := make(chan error, 2)
+	go func() { errCh <- c.namespacedCache.Start(ctx) }()
+	go func() { errCh <- c.clusterCache.Start(ctx) }()
+
+	for i := 0; i < 2; i++ {
+		if err := <-errCh; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *multiNamespaceCache) WaitForCacheSync(ctx context.Context) bool {
+	namespacedSynced := c.namespacedCache.WaitForCacheSync(ctx)
+	clusterSynced := c.clusterCache.WaitForCacheSync(ctx)
+	return namespacedSynced && clusterSynced
+}
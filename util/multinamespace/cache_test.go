@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multinamespace
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSetManagerOptions(t *testing.T) {
+	tests := []struct {
+		name              string
+		namespaces        []string
+		expectedNamespace string
+		expectNewCache    bool
+	}{
+		{
+			name:              "watches all namespaces when no namespace is given",
+			namespaces:        nil,
+			expectedNamespace: "",
+		},
+		{
+			name:              "ignores empty namespaces",
+			namespaces:        []string{"", " "},
+			expectedNamespace: "",
+		},
+		{
+			name:              "watches a single namespace",
+			namespaces:        []string{"ns1", ""},
+			expectedNamespace: "ns1",
+		},
+		{
+			name:              "ignores duplicated namespaces",
+			namespaces:        []string{"ns1", "ns1"},
+			expectedNamespace: "ns1",
+		},
+		{
+			name:              "watches multiple namespaces",
+			namespaces:        []string{"ns1", "ns2"},
+			expectedNamespace: "",
+			expectNewCache:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			options := ctrl.Options{}
+			SetManagerOptions(&options, tt.namespaces...)
+			g.Expect(options.Namespace).To(Equal(tt.expectedNamespace))
+			g.Expect(options.NewCache != nil).To(Equal(tt.expectNewCache))
+		})
+	}
+}
+
+func TestNewCacheFunc(t *testing.T) {
+	g := NewWithT(t)
+
+	var namespaces []*corev1.Namespace
+	for i := 0; i < 3; i++ {
+		ns, err := testEnv.CreateNamespace(ctx, "test-multinamespace")
+		g.Expect(err).ToNot(HaveOccurred())
+		namespaces = append(namespaces, ns)
+
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: ns.Name,
+				Labels:    map[string]string{"test": "multinamespace"},
+			},
+		}
+		g.Expect(testEnv.Create(ctx, cm)).To(Succeed())
+	}
+	defer func() {
+		for _, ns := range namespaces {
+			g.Expect(testEnv.Cleanup(ctx, ns)).To(Succeed())
+		}
+	}()
+	watched, notWatched := namespaces[:2], namespaces[2]
+
+	c, err := NewCacheFunc([]string{watched[0].Name, watched[1].Name})(testEnv.Config, cache.Options{Scheme: scheme.Scheme})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cacheCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		g.Expect(c.Start(cacheCtx)).To(Succeed())
+	}()
+	g.Expect(c.WaitForCacheSync(cacheCtx)).To(BeTrue())
+
+	// Only the objects in the watched namespaces are listed.
+	configMaps := &corev1.ConfigMapList{}
+	g.Expect(c.List(ctx, configMaps, client.MatchingLabels{"test": "multinamespace"})).To(Succeed())
+	listedNamespaces := []string{}
+	for _, cm := range configMaps.Items {
+		listedNamespaces = append(listedNamespaces, cm.Namespace)
+	}
+	g.Expect(listedNamespaces).To(ConsistOf(watched[0].Name, watched[1].Name))
+
+	// Objects in the other namespaces are ignored.
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: watched[0].Name, Name: "test"}, &corev1.ConfigMap{})).To(Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: notWatched.Name, Name: "test"}, &corev1.ConfigMap{})).ToNot(Succeed())
+	g.Expect(c.List(ctx, &corev1.ConfigMapList{}, client.InNamespace(notWatched.Name))).ToNot(Succeed())
+
+	// Cluster-scoped objects are still available.
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: notWatched.Name}, &corev1.Namespace{})).To(Succeed())
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	g.Expect(c.List(ctx, crds)).To(Succeed())
+	g.Expect(crds.Items).ToNot(BeEmpty())
+}
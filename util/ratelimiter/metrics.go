@@ -0,0 +1,37 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// clusterBackoffSeconds is a prometheus metric which tracks the current delay of the retries of the failing
+	// reconciles of the objects of a cluster.
+	clusterBackoffSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "capi_reconcile_cluster_backoff_seconds",
+		Help: "Current delay in seconds of the retries of the failing reconciles of the objects of a cluster",
+	}, []string{"controller", "cluster"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		clusterBackoffSeconds,
+	)
+}
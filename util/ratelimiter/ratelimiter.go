@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimiter implements a work queue rate limiter backing off the reconciles of the objects of a
+// cluster as a whole, so a single unhealthy cluster does not starve the reconciles of the healthy ones.
+package ratelimiter
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultBaseDelay is the delay of the first retry of a failing request, and of the first retry of
+	// a request of a cluster with a failing request.
+	DefaultBaseDelay = 5 * time.Millisecond
+
+	// DefaultMaxDelay is the maximum delay between the retries of a failing request.
+	DefaultMaxDelay = 1000 * time.Second
+
+	// overallQPS and overallBurst limit the retries of all the requests together, as the controller-runtime
+	// default rate limiter does.
+	overallQPS   = 10
+	overallBurst = 100
+)
+
+// ClusterKeyFunc returns the namespace/name key of the Cluster the object of a reconcile request belongs to,
+// or false if the object does not belong to a Cluster.
+type ClusterKeyFunc func(req reconcile.Request) (string, bool)
+
+// ClusterKeyFromRequest returns the key of the Cluster reconcile requests are for.
+func ClusterKeyFromRequest(req reconcile.Request) (string, bool) {
+	return req.String(), true
+}
+
+// ClusterKeyFromLabel returns a ClusterKeyFunc reading the Cluster of the object of a reconcile request from
+// the cluster name label of the object. The object is read with the reader, which should be backed by a cache,
+// in a new instance of obj.
+func ClusterKeyFromLabel(c client.Reader, obj client.Object) ClusterKeyFunc {
+	return func(req reconcile.Request) (string, bool) {
+		o := obj.DeepCopyObject().(client.Object)
+		if err := c.Get(context.Background(), req.NamespacedName, o); err != nil {
+			return "", false
+		}
+		clusterName, ok := o.GetLabels()[clusterv1.ClusterLabelName]
+		if !ok || clusterName == "" {
+			return "", false
+		}
+		return client.ObjectKey{Namespace: o.GetNamespace(), Name: clusterName}.String(), true
+	}
+}
+
+// clusterRateLimiter delays the retries of a request by the longer of the exponential backoff of the request
+// itself and the exponential backoff of its cluster, which grows with the failures of all the requests of the cluster.
+type clusterRateLimiter struct {
+	controllerName string
+	clusterKey     ClusterKeyFunc
+	baseDelay      time.Duration
+	maxDelay       time.Duration
+
+	itemLimiter ratelimiter.RateLimiter
+
+	lock sync.Mutex
+	// clusterFailures are the failures of the requests of each cluster since they last succeeded.
+	clusterFailures map[string]int
+	// itemFailures are the failures of each request added to the failures of its cluster, kept to discount them
+	// when the request is forgotten, even if its object is already gone.
+	itemFailures map[interface{}]*itemFailures
+}
+
+// itemFailures are the failures of a request added to the failures of its cluster.
+type itemFailures struct {
+	cluster  string
+	failures int
+}
+
+// New returns a rate limiter backing off the retries of the failing requests of the named controller,
+// both per request and per cluster, using the default delays.
+func New(controllerName string, clusterKey ClusterKeyFunc) ratelimiter.RateLimiter {
+	return NewWithDelays(controllerName, clusterKey, DefaultBaseDelay, DefaultMaxDelay)
+}
+
+// NewWithDelays returns a rate limiter backing off the retries of the failing requests of the named controller,
+// both per request and per cluster, starting from baseDelay up to maxDelay. The retries of all the requests
+// together are limited to 10 qps with a burst of 100, as with the controller-runtime default rate limiter.
+func NewWithDelays(controllerName string, clusterKey ClusterKeyFunc, baseDelay, maxDelay time.Duration) ratelimiter.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		newClusterRateLimiter(controllerName, clusterKey, baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(overallQPS), overallBurst)},
+	)
+}
+
+func newClusterRateLimiter(controllerName string, clusterKey ClusterKeyFunc, baseDelay, maxDelay time.Duration) *clusterRateLimiter {
+	return &clusterRateLimiter{
+		controllerName:  controllerName,
+		clusterKey:      clusterKey,
+		baseDelay:       baseDelay,
+		maxDelay:        maxDelay,
+		itemLimiter:     workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		clusterFailures: map[string]int{},
+		itemFailures:    map[interface{}]*itemFailures{},
+	}
+}
+
+// When returns how long to wait before retrying the request.
+func (r *clusterRateLimiter) When(item interface{}) time.Duration {
+	delay := r.itemLimiter.When(item)
+
+	r.lock.Lock()
+	f, ok := r.itemFailures[item]
+	r.lock.Unlock()
+	if !ok {
+		// The cluster is looked up without holding the lock, given that it may read the object of the request.
+		req, isRequest := item.(reconcile.Request)
+		if !isRequest {
+			return delay
+		}
+		key, ok := r.clusterKey(req)
+		if !ok {
+			return delay
+		}
+		f = &itemFailures{cluster: key}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if current, ok := r.itemFailures[item]; ok {
+		// The request failed concurrently, and its cluster has been recorded already.
+		f = current
+	}
+	r.itemFailures[item] = f
+	f.failures++
+
+	key := f.cluster
+	r.clusterFailures[key]++
+	clusterDelay := r.backoff(r.clusterFailures[key])
+	clusterBackoffSeconds.WithLabelValues(r.controllerName, key).Set(clusterDelay.Seconds())
+	if clusterDelay > delay {
+		return clusterDelay
+	}
+	return delay
+}
+
+// Forget stops tracking the request, and discounts its failures from the backoff of its cluster.
+func (r *clusterRateLimiter) Forget(item interface{}) {
+	r.itemLimiter.Forget(item)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	f, ok := r.itemFailures[item]
+	if !ok {
+		return
+	}
+	delete(r.itemFailures, item)
+
+	key := f.cluster
+	r.clusterFailures[key] -= f.failures
+	if r.clusterFailures[key] <= 0 {
+		delete(r.clusterFailures, key)
+		clusterBackoffSeconds.DeleteLabelValues(r.controllerName, key)
+		return
+	}
+	clusterBackoffSeconds.WithLabelValues(r.controllerName, key).Set(r.backoff(r.clusterFailures[key]).Seconds())
+}
+
+// NumRequeues returns how many times the request failed.
+func (r *clusterRateLimiter) NumRequeues(item interface{}) int {
+	return r.itemLimiter.NumRequeues(item)
+}
+
+// backoff returns the delay after the given number of failures, doubling the base delay for each failure but the first.
+func (r *clusterRateLimiter) backoff(failures int) time.Duration {
+	delay := float64(r.baseDelay.Nanoseconds()) * math.Pow(2, float64(failures-1))
+	if delay > math.MaxInt64 || time.Duration(delay) > r.maxDelay {
+		return r.maxDelay
+	}
+	return time.Duration(delay)
+}
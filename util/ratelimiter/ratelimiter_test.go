@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestClusterRateLimiter(t *testing.T) {
+	g := NewWithT(t)
+
+	// Requests are keyed on the cluster named by the namespace of the request.
+	clusterKey := func(req reconcile.Request) (string, bool) {
+		if req.Namespace == "" {
+			return "", false
+		}
+		return req.Namespace + "/cluster", true
+	}
+	limiter := NewWithDelays("test", clusterKey, time.Millisecond, time.Second)
+
+	hot1 := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "hot", Name: "machine-1"}}
+	hot2 := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "hot", Name: "machine-2"}}
+	healthy := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "healthy", Name: "machine-1"}}
+
+	// The objects of the hot looping cluster keep failing.
+	var hotDelay time.Duration
+	for i := 0; i < 5; i++ {
+		g.Expect(limiter.When(hot1)).To(BeNumerically(">=", hotDelay))
+		hotDelay = limiter.When(hot2)
+	}
+	g.Expect(limiter.NumRequeues(hot1)).To(Equal(5))
+	g.Expect(limiter.NumRequeues(hot2)).To(Equal(5))
+
+	// The retries of the hot looping cluster back off with the failures of all its objects,
+	// longer than the backoff of each object alone.
+	g.Expect(hotDelay).To(Equal(512 * time.Millisecond))
+	g.Expect(testutil.ToFloat64(clusterBackoffSeconds.WithLabelValues("test", "hot/cluster"))).To(Equal(0.512))
+
+	// The first failure of an object of the healthy cluster is retried right away.
+	g.Expect(limiter.When(healthy)).To(Equal(time.Millisecond))
+	g.Expect(testutil.ToFloat64(clusterBackoffSeconds.WithLabelValues("test", "healthy/cluster"))).To(Equal(0.001))
+	limiter.Forget(healthy)
+	g.Expect(limiter.NumRequeues(healthy)).To(Equal(0))
+
+	// The backoff of the hot looping cluster is capped.
+	for i := 0; i < 10; i++ {
+		hotDelay = limiter.When(hot1)
+	}
+	g.Expect(hotDelay).To(Equal(time.Second))
+
+	// The backoff of the cluster decreases as its objects are reconciled successfully.
+	limiter.Forget(hot1)
+	g.Expect(testutil.ToFloat64(clusterBackoffSeconds.WithLabelValues("test", "hot/cluster"))).To(Equal(0.016))
+	limiter.Forget(hot2)
+	g.Expect(limiter.When(hot1)).To(Equal(time.Millisecond))
+
+	// Requests not belonging to a cluster are only backed off per request.
+	noCluster := reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo"}}
+	g.Expect(limiter.When(noCluster)).To(Equal(time.Millisecond))
+	g.Expect(limiter.When(noCluster)).To(Equal(2 * time.Millisecond))
+}
+
+func TestClusterRateLimiterDiscountsCountedFailures(t *testing.T) {
+	g := NewWithT(t)
+
+	// The cluster of the request can't be looked up until its object is labeled.
+	labeled := false
+	clusterKey := func(req reconcile.Request) (string, bool) {
+		return "default/cluster", labeled
+	}
+	limiter := NewWithDelays("test-counted", clusterKey, time.Millisecond, time.Second)
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "machine-1"}}
+	other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "machine-2"}}
+
+	// The failures before the cluster is known are only backed off per request.
+	for i := 0; i < 3; i++ {
+		limiter.When(req)
+	}
+	labeled = true
+	g.Expect(limiter.When(req)).To(Equal(8 * time.Millisecond))
+	g.Expect(limiter.When(other)).To(Equal(2 * time.Millisecond))
+	g.Expect(testutil.ToFloat64(clusterBackoffSeconds.WithLabelValues("test-counted", "default/cluster"))).To(Equal(0.002))
+
+	// Forgetting the request only discounts the failure added to its cluster.
+	limiter.Forget(req)
+	g.Expect(testutil.ToFloat64(clusterBackoffSeconds.WithLabelValues("test-counted", "default/cluster"))).To(Equal(0.001))
+	g.Expect(limiter.When(other)).To(Equal(2 * time.Millisecond))
+}
+
+func TestClusterKeyFromLabel(t *testing.T) {
+	scheme := runtime.NewScheme()
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "labeled",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: "cluster"},
+			},
+		},
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "unlabeled",
+			},
+		},
+	).Build()
+	clusterKey := ClusterKeyFromLabel(c, &clusterv1.Machine{})
+
+	tests := []struct {
+		name    string
+		req     reconcile.Request
+		wantKey string
+		wantOK  bool
+	}{
+		{
+			name:    "object with the cluster name label",
+			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "labeled"}},
+			wantKey: "default/cluster",
+			wantOK:  true,
+		},
+		{
+			name:   "object without the cluster name label",
+			req:    reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "unlabeled"}},
+			wantOK: false,
+		},
+		{
+			name:   "object not found",
+			req:    reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "missing"}},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			key, ok := clusterKey(tt.req)
+			g.Expect(ok).To(Equal(tt.wantOK))
+			g.Expect(key).To(Equal(tt.wantKey))
+		})
+	}
+}
@@ -18,12 +18,14 @@ package secret
 
 import (
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/hex"
+	"encoding/pem"
 	"math/big"
 	"path/filepath"
 	"strings"
@@ -241,6 +243,22 @@ func (c Certificates) EnsureAllExist() error {
 	return nil
 }
 
+// Validate ensures that every certificate that was looked up from a secret contains well formed
+// data; in case of user provided certificate authorities, it also checks that the certificate is a CA
+// and that the private key matches the certificate. Certificates without KeyPair data or with
+// generated data are skipped.
+func (c Certificates) Validate() error {
+	for _, certificate := range c {
+		if certificate.KeyPair == nil || certificate.Generated {
+			continue
+		}
+		if err := certificate.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid certificate: %s", certificate.Purpose)
+		}
+	}
+	return nil
+}
+
 // Generate will generate any certificates that do not have KeyPair data.
 func (c Certificates) Generate() error {
 	for _, certificate := range c {
@@ -297,6 +315,57 @@ type Certificate struct {
 	CertFile, KeyFile string
 }
 
+// Validate checks that the certificate KeyPair contains a well formed certificate and a private key matching it.
+// The private key can be omitted only for external certificates.
+func (c *Certificate) Validate() error {
+	if c.KeyPair == nil {
+		return ErrMissingCertificate
+	}
+	if len(c.KeyPair.Cert) == 0 {
+		return ErrMissingCrt
+	}
+
+	var publicKey crypto.PublicKey
+	if c.Purpose == ServiceAccount {
+		// The service account key pair does not have a certificate, only a public key.
+		block, _ := pem.Decode(c.KeyPair.Cert)
+		if block == nil {
+			return errors.New("unable to decode PEM data for the public key")
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse public key")
+		}
+		publicKey = key
+	} else {
+		cert, err := certs.DecodeCertPEM(c.KeyPair.Cert)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse certificate")
+		}
+		if c.Purpose.isCertificateAuthority() && !cert.IsCA {
+			return errors.New("certificate is not a certificate authority")
+		}
+		publicKey = cert.PublicKey
+	}
+
+	if len(c.KeyPair.Key) == 0 {
+		if c.External {
+			return nil
+		}
+		return ErrMissingKey
+	}
+
+	key, err := certs.DecodePrivateKeyPEM(c.KeyPair.Key)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse private key")
+	}
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(publicKey) {
+		return errors.New("private key does not match the certificate")
+	}
+	return nil
+}
+
 // Hashes hashes all the certificates stored in a CA certificate.
 func (c *Certificate) Hashes() ([]string, error) {
 	certificates, err := cert.ParseCertsPEM(c.KeyPair.Cert)
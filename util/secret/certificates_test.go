@@ -17,11 +17,13 @@ limitations under the License.
 package secret_test
 
 import (
+	"crypto/x509"
 	"testing"
 
 	. "github.com/onsi/gomega"
 
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
+	"sigs.k8s.io/cluster-api/util/certs"
 	"sigs.k8s.io/cluster-api/util/secret"
 )
 
@@ -52,3 +54,117 @@ func TestNewControlPlaneJoinCerts_External(t *testing.T) {
 	certs := secret.NewControlPlaneJoinCerts(config)
 	g.Expect(certs.GetByPurpose(secret.EtcdCA).KeyFile).To(BeEmpty())
 }
+
+func TestCertificateValidate(t *testing.T) {
+	g := NewWithT(t)
+
+	generated := secret.NewCertificatesForInitialControlPlane(nil)
+	g.Expect(generated.Generate()).To(Succeed())
+	ca := generated.GetByPurpose(secret.ClusterCA).KeyPair
+	otherCA := generated.GetByPurpose(secret.FrontProxyCA).KeyPair
+	sa := generated.GetByPurpose(secret.ServiceAccount).KeyPair
+
+	caCert, err := certs.DecodeCertPEM(ca.Cert)
+	g.Expect(err).NotTo(HaveOccurred())
+	caKey, err := certs.DecodePrivateKeyPEM(ca.Key)
+	g.Expect(err).NotTo(HaveOccurred())
+	clientKey, err := certs.NewPrivateKey()
+	g.Expect(err).NotTo(HaveOccurred())
+	clientCfg := &certs.Config{CommonName: "client", Usages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+	clientCert, err := clientCfg.NewSignedCert(clientKey, caCert, caKey)
+	g.Expect(err).NotTo(HaveOccurred())
+	client := &certs.KeyPair{Cert: certs.EncodeCertPEM(clientCert), Key: certs.EncodePrivateKeyPEM(clientKey)}
+
+	tests := []struct {
+		name        string
+		certificate *secret.Certificate
+		wantErr     bool
+	}{
+		{
+			name:        "valid CA",
+			certificate: &secret.Certificate{Purpose: secret.ClusterCA, KeyPair: ca},
+		},
+		{
+			name:        "valid service account key pair",
+			certificate: &secret.Certificate{Purpose: secret.ServiceAccount, KeyPair: sa},
+		},
+		{
+			name:        "valid external certificate without key",
+			certificate: &secret.Certificate{Purpose: secret.EtcdCA, External: true, KeyPair: &certs.KeyPair{Cert: ca.Cert}},
+		},
+		{
+			name:        "valid client certificate",
+			certificate: &secret.Certificate{Purpose: secret.APIServerEtcdClient, External: true, KeyPair: client},
+		},
+		{
+			name:        "missing key pair",
+			certificate: &secret.Certificate{Purpose: secret.ClusterCA},
+			wantErr:     true,
+		},
+		{
+			name:        "missing certificate data",
+			certificate: &secret.Certificate{Purpose: secret.ClusterCA, KeyPair: &certs.KeyPair{Key: ca.Key}},
+			wantErr:     true,
+		},
+		{
+			name:        "missing key",
+			certificate: &secret.Certificate{Purpose: secret.ClusterCA, KeyPair: &certs.KeyPair{Cert: ca.Cert}},
+			wantErr:     true,
+		},
+		{
+			name:        "malformed certificate",
+			certificate: &secret.Certificate{Purpose: secret.ClusterCA, KeyPair: &certs.KeyPair{Cert: []byte("foo"), Key: ca.Key}},
+			wantErr:     true,
+		},
+		{
+			name:        "malformed key",
+			certificate: &secret.Certificate{Purpose: secret.ClusterCA, KeyPair: &certs.KeyPair{Cert: ca.Cert, Key: []byte("foo")}},
+			wantErr:     true,
+		},
+		{
+			name:        "key not matching the certificate",
+			certificate: &secret.Certificate{Purpose: secret.ClusterCA, KeyPair: &certs.KeyPair{Cert: ca.Cert, Key: otherCA.Key}},
+			wantErr:     true,
+		},
+		{
+			name:        "key not matching the service account public key",
+			certificate: &secret.Certificate{Purpose: secret.ServiceAccount, KeyPair: &certs.KeyPair{Cert: sa.Cert, Key: ca.Key}},
+			wantErr:     true,
+		},
+		{
+			name:        "certificate is not a CA",
+			certificate: &secret.Certificate{Purpose: secret.EtcdCA, KeyPair: client},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.certificate.Validate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestCertificatesValidate(t *testing.T) {
+	g := NewWithT(t)
+
+	certificates := secret.NewCertificatesForInitialControlPlane(nil)
+	// Certificates without data are going to be generated, so they are not validated.
+	g.Expect(certificates.Validate()).To(Succeed())
+
+	g.Expect(certificates.Generate()).To(Succeed())
+	ca := certificates.GetByPurpose(secret.ClusterCA)
+	ca.KeyPair.Cert = []byte("foo")
+	// Generated certificates are not validated.
+	g.Expect(certificates.Validate()).To(Succeed())
+
+	ca.Generated = false
+	g.Expect(certificates.Validate()).NotTo(Succeed())
+}
@@ -52,3 +52,12 @@ var (
 	// allSecretPurposes defines a lists with all the secret suffix used by Cluster API
 	allSecretPurposes = []Purpose{Kubeconfig, ClusterCA, EtcdCA, ServiceAccount, FrontProxyCA, APIServerEtcdClient}
 )
+
+// isCertificateAuthority returns true if the secret with the given purpose is expected to hold a certificate authority.
+func (p Purpose) isCertificateAuthority() bool {
+	switch p {
+	case ClusterCA, EtcdCA, FrontProxyCA:
+		return true
+	}
+	return false
+}